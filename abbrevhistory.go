@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Year bounds of abbreviation_history; the tz database goes back to local
+// mean time in the 19th century
+const (
+	minAbbreviationYear     = 1800
+	maxAbbreviationYear     = 2100
+	defaultAbbreviationYear = 1850
+)
+
+// abbreviationHistory is the structured result of abbreviation_history
+type abbreviationHistory struct {
+	Timezone      string               `json:"timezone"`
+	From          string               `json:"from"`
+	Until         string               `json:"until"`
+	Periods       []abbreviationPeriod `json:"periods" jsonschema:"Consecutive spans with the same abbreviation and offset, oldest first"`
+	Abbreviations []abbreviationUse    `json:"abbreviations" jsonschema:"Each abbreviation once, in order of first use"`
+}
+
+type abbreviationPeriod struct {
+	Abbreviation string `json:"abbreviation"`
+	UTCOffset    string `json:"utc_offset"`
+	IsDST        bool   `json:"is_dst"`
+	Start        string `json:"start" jsonschema:"Start of the span in RFC 3339, or the start of the range"`
+	End          string `json:"end" jsonschema:"End of the span in RFC 3339, or the end of the range"`
+}
+
+type abbreviationUse struct {
+	Abbreviation string   `json:"abbreviation"`
+	UTCOffsets   []string `json:"utc_offsets" jsonschema:"Every offset the abbreviation stood for"`
+	FirstUsed    string   `json:"first_used"`
+	LastUsed     string   `json:"last_used"`
+	Periods      int      `json:"periods"`
+	// Numeric abbreviations like +03 are what the tz database uses where
+	// no abbreviation is in common use
+	Numeric bool `json:"numeric,omitempty" jsonschema:"Whether the abbreviation is a numeric placeholder such as +03 rather than one in real use"`
+}
+
+// buildAbbreviationHistory merges the transitions of loc in [from, to) into
+// spans of one abbreviation and offset
+func buildAbbreviationHistory(loc *time.Location, from, to time.Time) abbreviationHistory {
+	history := abbreviationHistory{
+		Timezone: loc.String(),
+		From:     from.In(loc).Format(time.RFC3339),
+		Until:    to.In(loc).Format(time.RFC3339),
+	}
+	period := func(t time.Time) abbreviationPeriod {
+		t = t.In(loc)
+		name, _ := t.Zone()
+		return abbreviationPeriod{Abbreviation: name, UTCOffset: t.Format("-07:00"), IsDST: t.IsDST(), Start: t.Format(time.RFC3339)}
+	}
+	current := period(from)
+	for _, tr := range zoneTransitions(loc, from, to) {
+		next := period(tr.At)
+		if next.Abbreviation == current.Abbreviation && next.UTCOffset == current.UTCOffset && next.IsDST == current.IsDST {
+			continue
+		}
+		current.End = next.Start
+		history.Periods = append(history.Periods, current)
+		current = next
+	}
+	current.End = history.Until
+	history.Periods = append(history.Periods, current)
+
+	uses := make(map[string]int)
+	for _, p := range history.Periods {
+		i, seen := uses[p.Abbreviation]
+		if !seen {
+			i = len(history.Abbreviations)
+			uses[p.Abbreviation] = i
+			history.Abbreviations = append(history.Abbreviations, abbreviationUse{
+				Abbreviation: p.Abbreviation,
+				FirstUsed:    p.Start,
+				Numeric:      p.Abbreviation != "" && (p.Abbreviation[0] == '+' || p.Abbreviation[0] == '-'),
+			})
+		}
+		use := &history.Abbreviations[i]
+		use.LastUsed = p.End
+		use.Periods++
+		if !slices.Contains(use.UTCOffsets, p.UTCOffset) {
+			use.UTCOffsets = append(use.UTCOffsets, p.UTCOffset)
+		}
+	}
+	return history
+}
+
+func addAbbreviationHistoryTool(mcpServer *server.MCPServer, config *Config) {
+	mcpServer.AddTool(
+		mcp.NewTool("abbreviation_history",
+			mcp.WithDescription("List which abbreviations a timezone has used over time and when, e.g. Europe/Moscow's MSK and MSD, with the UTC offset each stood for. Helps interpret abbreviations in old documents and logs, which may mean different offsets in different years."),
+			mcp.WithString("timezone",
+				mcp.Description("IANA timezone name. Defaults to the session or server default."),
+			),
+			mcp.WithNumber("from_year",
+				mcp.Description(fmt.Sprintf("First year to cover. Defaults to %d.", defaultAbbreviationYear)),
+			),
+			mcp.WithNumber("to_year",
+				mcp.Description("Last year to cover, inclusive. Defaults to the current year."),
+			),
+			mcp.WithOutputSchema[abbreviationHistory](),
+			mcp.WithTitleAnnotation("Abbreviation History"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		handleAbbreviationHistory(config),
+	)
+}
+
+// handleAbbreviationHistory returns a handler for the abbreviation_history tool
+func handleAbbreviationHistory(config *Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		timezoneStr := request.GetString("timezone", "")
+		loc, err := loadTimezone(ctx, timezoneStr, config)
+		if err != nil {
+			return invalidTimezoneError("Invalid timezone", timezoneStr), nil
+		}
+		fromYear := request.GetInt("from_year", defaultAbbreviationYear)
+		toYear := request.GetInt("to_year", config.now().In(loc).Year())
+		if fromYear < minAbbreviationYear || toYear > maxAbbreviationYear || fromYear > toYear {
+			return toolError(errorInvalidArgument, fmt.Sprintf("Invalid years %d-%d; they must be in order within %d-%d", fromYear, toYear, minAbbreviationYear, maxAbbreviationYear)), nil
+		}
+
+		from := time.Date(fromYear, time.January, 1, 0, 0, 0, 0, loc)
+		to := time.Date(toYear+1, time.January, 1, 0, 0, 0, 0, loc)
+		history := buildAbbreviationHistory(loc, from, to)
+
+		data, err := json.Marshal(history)
+		if err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to encode result: %v", err)), nil
+		}
+		return mcp.NewToolResultStructured(history, string(data)), nil
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestAbbreviationHistory(t *testing.T) {
+	handler := handleAbbreviationHistory(&Config{DefaultTimezone: "UTC"})
+	call := func(args map[string]any) *mcp.CallToolResult {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := handler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("Failed to call handler: %v", err)
+		}
+		return result
+	}
+
+	result := call(map[string]any{"timezone": "Europe/Moscow", "from_year": 2009, "to_year": 2015})
+	if result.IsError {
+		t.Fatalf("Failed to list abbreviations: %s", toolResultText(result))
+	}
+	got := result.StructuredContent.(abbreviationHistory)
+	if len(got.Periods) != 7 || len(got.Abbreviations) != 2 {
+		t.Fatalf("Expected 7 periods of MSK and MSD but got %+v", got)
+	}
+	// MSK stood for +04:00 from 2011 to 2014, after MSD was dropped
+	if p := got.Periods[5]; p.Abbreviation != "MSK" || p.UTCOffset != "+04:00" || p.Start != "2011-03-27T03:00:00+04:00" || p.End != "2014-10-26T01:00:00+03:00" {
+		t.Errorf("Expected MSK at +04:00 from 2011 to 2014 but got %+v", p)
+	}
+	if use := got.Abbreviations[0]; use.Abbreviation != "MSK" || len(use.UTCOffsets) != 2 || use.Periods != 5 {
+		t.Errorf("Expected MSK with two offsets but got %+v", use)
+	}
+	if use := got.Abbreviations[1]; use.Abbreviation != "MSD" || use.LastUsed != "2010-10-31T02:00:00+03:00" {
+		t.Errorf("Expected MSD last used in October 2010 but got %+v", use)
+	}
+
+	// The default range starts in local mean time
+	got = call(map[string]any{"timezone": "Asia/Dubai"}).StructuredContent.(abbreviationHistory)
+	if first := got.Abbreviations[0]; first.Abbreviation != "LMT" || got.Abbreviations[1].Abbreviation != "+04" || !got.Abbreviations[1].Numeric {
+		t.Errorf("Expected LMT then a numeric +04 but got %+v", got.Abbreviations)
+	}
+
+	for _, args := range []map[string]any{
+		{"timezone": "Mars/Olympus"},
+		{"from_year": 2020, "to_year": 2010},
+		{"from_year": 1700},
+		{"to_year": 2500},
+	} {
+		if result := call(args); !result.IsError {
+			t.Errorf("Expected an error for %v but got %s", args, toolResultText(result))
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Access log formats
+const (
+	accessLogOff      = "off"
+	accessLogCommon   = "common"
+	accessLogCombined = "combined"
+	accessLogJSON     = "json"
+)
+
+// commonLogTimeFormat is the timestamp layout of the Common Log Format
+const commonLogTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// accessLogFormats lists the accepted TIME_HTTP_ACCESS_LOG values
+var accessLogFormats = map[string]bool{
+	accessLogOff:      true,
+	accessLogCommon:   true,
+	accessLogCombined: true,
+	accessLogJSON:     true,
+}
+
+// statusRecorder captures the status code and body size of a response
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (sr *statusRecorder) WriteHeader(status int) {
+	if sr.status == 0 {
+		sr.status = status
+	}
+	sr.ResponseWriter.WriteHeader(status)
+}
+
+func (sr *statusRecorder) Write(b []byte) (int, error) {
+	if sr.status == 0 {
+		sr.status = http.StatusOK
+	}
+	n, err := sr.ResponseWriter.Write(b)
+	sr.bytes += int64(n)
+	return n, err
+}
+
+// Flush keeps event streams working through the recorder
+func (sr *statusRecorder) Flush() {
+	if f, ok := sr.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (sr *statusRecorder) Unwrap() http.ResponseWriter {
+	return sr.ResponseWriter
+}
+
+// accessLogEntry describes one completed request
+type accessLogEntry struct {
+	Time       time.Time
+	RemoteAddr string
+	Method     string
+	URI        string
+	Proto      string
+	Status     int
+	Bytes      int64
+	Duration   time.Duration
+	Referer    string
+	UserAgent  string
+	RequestID  string
+}
+
+// syncWriter serializes writes so concurrent requests never interleave lines
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (s *syncWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.w.Write(p)
+}
+
+// openAccessLog returns the access log destination: the named file, opened
+// for appending, or stderr alongside the application logs
+func openAccessLog(path string) (io.Writer, error) {
+	if path == "" {
+		return &syncWriter{w: os.Stderr}, nil
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open access log: %w", err)
+	}
+	return &syncWriter{w: f}, nil
+}
+
+// accessLogHandler writes one line per request in the given format once the
+// response completes. Long-lived event streams are logged when they close.
+func accessLogHandler(next http.Handler, format string, w io.Writer) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: rw}
+		next.ServeHTTP(rec, r)
+
+		status := rec.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		entry := accessLogEntry{
+			Time:       start,
+			RemoteAddr: r.RemoteAddr,
+			Method:     r.Method,
+			URI:        r.URL.RequestURI(),
+			Proto:      r.Proto,
+			Status:     status,
+			Bytes:      rec.bytes,
+			Duration:   time.Since(start),
+			Referer:    r.Referer(),
+			UserAgent:  r.UserAgent(),
+			RequestID:  getRequestID(r.Context()),
+		}
+		io.WriteString(w, formatAccessLog(entry, format)+"\n")
+	})
+}
+
+// formatAccessLog renders an entry; query strings and headers are redacted
+// like application logs since clients sometimes pass tokens in URLs
+func formatAccessLog(e accessLogEntry, format string) string {
+	host := e.RemoteAddr
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	uri := redactString(e.URI)
+
+	if format == accessLogJSON {
+		data, _ := json.Marshal(map[string]any{
+			"time":        e.Time.UTC().Format(time.RFC3339Nano),
+			"remote_addr": host,
+			"method":      e.Method,
+			"uri":         uri,
+			"proto":       e.Proto,
+			"status":      e.Status,
+			"bytes":       e.Bytes,
+			"duration_ms": float64(e.Duration.Microseconds()) / 1000,
+			"referer":     redactString(e.Referer),
+			"user_agent":  e.UserAgent,
+			"request_id":  e.RequestID,
+		})
+		return string(data)
+	}
+
+	size := "-"
+	if e.Bytes > 0 {
+		size = strconv.FormatInt(e.Bytes, 10)
+	}
+	line := fmt.Sprintf("%s - - [%s] %s %d %s", host, e.Time.Format(commonLogTimeFormat),
+		strconv.Quote(e.Method+" "+uri+" "+e.Proto), e.Status, size)
+	if format == accessLogCombined {
+		line += fmt.Sprintf(" %s %s", quoteLogField(redactString(e.Referer)), quoteLogField(e.UserAgent))
+	}
+	return line
+}
+
+// quoteLogField quotes a header value, using "-" for absent values
+func quoteLogField(value string) string {
+	if value == "" {
+		return `"-"`
+	}
+	return strconv.Quote(strings.TrimSpace(value))
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatAccessLog(t *testing.T) {
+	entry := accessLogEntry{
+		Time:       time.Date(2024, 3, 10, 13, 55, 36, 0, time.FixedZone("", -7*3600)),
+		RemoteAddr: "203.0.113.7:51234",
+		Method:     "POST",
+		URI:        "/mcp?token=supersecretvalue",
+		Proto:      "HTTP/1.1",
+		Status:     200,
+		Bytes:      2326,
+		Duration:   1500 * time.Microsecond,
+		UserAgent:  "curl/8.5.0",
+		RequestID:  "req-1",
+	}
+
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{accessLogCommon, `203.0.113.7 - - [10/Mar/2024:13:55:36 -0700] "POST /mcp?token=[REDACTED] HTTP/1.1" 200 2326`},
+		{accessLogCombined, `203.0.113.7 - - [10/Mar/2024:13:55:36 -0700] "POST /mcp?token=[REDACTED] HTTP/1.1" 200 2326 "-" "curl/8.5.0"`},
+	}
+	for _, tt := range tests {
+		if got := formatAccessLog(entry, tt.format); got != tt.expected {
+			t.Errorf("%s: Expected %q but got %q", tt.format, tt.expected, got)
+		}
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(formatAccessLog(entry, accessLogJSON)), &fields); err != nil {
+		t.Fatalf("Failed to parse JSON access log: %v", err)
+	}
+	if fields["status"] != float64(200) || fields["bytes"] != float64(2326) || fields["request_id"] != "req-1" {
+		t.Errorf("Expected status, bytes and request_id in JSON entry but got %v", fields)
+	}
+	if fields["duration_ms"] != 1.5 {
+		t.Errorf("Expected duration_ms 1.5 but got %v", fields["duration_ms"])
+	}
+}
+
+func TestAccessLogHandler(t *testing.T) {
+	var out bytes.Buffer
+	handler := requestIDHandler(accessLogHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := w.(http.Flusher); !ok {
+			t.Errorf("Expected the recorder to implement http.Flusher")
+		}
+		w.WriteHeader(http.StatusTeapot)
+		w.Write([]byte("short and stout"))
+	}), accessLogJSON, &out))
+
+	req := httptest.NewRequest("GET", "/health", nil)
+	req.Header.Set(requestIDHeader, "trace-me")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	var fields map[string]any
+	if err := json.Unmarshal(bytes.TrimSpace(out.Bytes()), &fields); err != nil {
+		t.Fatalf("Failed to parse access log line %q: %v", out.String(), err)
+	}
+	if fields["status"] != float64(http.StatusTeapot) {
+		t.Errorf("Expected status %d but got %v", http.StatusTeapot, fields["status"])
+	}
+	if fields["bytes"] != float64(len("short and stout")) {
+		t.Errorf("Expected bytes %d but got %v", len("short and stout"), fields["bytes"])
+	}
+	if fields["request_id"] != "trace-me" {
+		t.Errorf("Expected request_id trace-me but got %v", fields["request_id"])
+	}
+}
+
+func TestAccessLogHandler_DefaultStatus(t *testing.T) {
+	var out bytes.Buffer
+	handler := accessLogHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}), accessLogCommon, &out)
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/livez", nil))
+
+	if !strings.HasSuffix(strings.TrimSpace(out.String()), `"GET /livez HTTP/1.1" 200 -`) {
+		t.Errorf("Expected an empty 200 response but got %q", out.String())
+	}
+}
+
+func TestParseAccessLogSettings(t *testing.T) {
+	t.Setenv("TIME_HTTP_ACCESS_LOG", "Combined")
+	t.Setenv("TIME_HTTP_ACCESS_LOG_FILE", "/tmp/access.log")
+	format, file, err := parseAccessLogSettings()
+	if err != nil {
+		t.Fatalf("Failed to parse access log settings: %v", err)
+	}
+	if format != accessLogCombined || file != "/tmp/access.log" {
+		t.Errorf("Expected combined and /tmp/access.log but got %q and %q", format, file)
+	}
+
+	t.Setenv("TIME_HTTP_ACCESS_LOG", "apache")
+	if _, _, err := parseAccessLogSettings(); err == nil {
+		t.Errorf("Expected an error for an unknown access log format")
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// addAdminTools registers the administrative tools
+func addAdminTools(mcpServer *server.MCPServer, config *Config) {
+	mcpServer.AddTool(
+		mcp.NewTool("revoke_token",
+			mcp.WithDescription("Revoke a JWT before it expires. Requires the admin role."),
+			mcp.WithString("jti",
+				mcp.Description("Token ID (jti claim) to revoke."),
+			),
+			mcp.WithString("token",
+				mcp.Description("Full JWT to revoke; its jti claim is used."),
+			),
+			mcp.WithTitleAnnotation("Revoke Token"),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		handleRevokeToken(config),
+	)
+}
+
+// requireAdmin returns an error result unless the caller holds the admin role.
+// Unauthenticated local stdio calls are trusted.
+func requireAdmin(ctx context.Context, config *Config) *mcp.CallToolResult {
+	if !authEnforced(ctx) {
+		return nil
+	}
+	if !config.AuthEnabled || !isAuthenticated(ctx) {
+		return toolError(errorForbidden, "Forbidden: administrative tools require authentication")
+	}
+	if _, _, role := getUserInfo(ctx); role != config.AuthAdminRole {
+		return toolError(errorForbidden, fmt.Sprintf("Forbidden: role '%s' is not an admin role", role))
+	}
+	return nil
+}
+
+// handleRevokeToken returns a handler for the revoke_token tool
+func handleRevokeToken(config *Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if denied := requireAdmin(ctx, config); denied != nil {
+			return denied, nil
+		}
+
+		jti := request.GetString("jti", "")
+		if jti == "" {
+			jti = tokenID(request.GetString("token", ""))
+		}
+		if jti == "" {
+			return toolError(errorInvalidArgument, "Provide either 'jti' or a 'token' that carries a jti claim"), nil
+		}
+
+		if err := config.AuthDenylist.revoke(jti); err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to revoke token: %v", err)), nil
+		}
+		_, username, _ := getUserInfo(ctx)
+		slog.InfoContext(ctx, "Token revoked", "jti", jti, "username", username)
+		return mcp.NewToolResultText(fmt.Sprintf("Token %s revoked", jti)), nil
+	}
+}
+
+// RevokeTokenCommand adds a token to the denylist file from the command line
+func RevokeTokenCommand(denylistFile, token string) error {
+	if denylistFile == "" {
+		return fmt.Errorf("TIME_AUTH_DENYLIST_FILE must be set to revoke tokens")
+	}
+	jti := tokenID(token)
+	if jti == "" {
+		return fmt.Errorf("token has no jti claim and cannot be revoked")
+	}
+	if err := appendToDenylistFile(denylistFile, jti); err != nil {
+		return err
+	}
+	slog.Info("Token added to denylist", "jti", jti, "file", denylistFile)
+	return nil
+}
@@ -0,0 +1,394 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// maxAnnotateTextBytes caps the text of one annotate_times_in_text call
+	maxAnnotateTextBytes = 100_000
+	// maxTextAnnotations caps the time expressions annotated in one text
+	maxTextAnnotations = 200
+)
+
+// zoneAbbreviation is what a timezone abbreviation found in text stands for.
+// Offset is set for abbreviations of one fixed offset, such as PST, and nil
+// for generic ones, such as PT, that follow the zone's DST rules.
+type zoneAbbreviation struct {
+	Zone      string
+	Offset    *int
+	Ambiguous string
+}
+
+// offsetHours returns an offset of h hours in seconds
+func offsetHours(h float64) *int {
+	seconds := int(h * 3600)
+	return &seconds
+}
+
+// zoneAbbreviations are the abbreviations annotate_times_in_text recognizes.
+// Several are used in more than one region; those name the reading chosen.
+var zoneAbbreviations = map[string]zoneAbbreviation{
+	"UTC": {Zone: "UTC", Offset: offsetHours(0)},
+	"GMT": {Zone: "Europe/London", Offset: offsetHours(0)},
+	"Z":   {Zone: "UTC", Offset: offsetHours(0)},
+	"PT":  {Zone: "America/Los_Angeles"}, "PST": {Zone: "America/Los_Angeles", Offset: offsetHours(-8)}, "PDT": {Zone: "America/Los_Angeles", Offset: offsetHours(-7)},
+	"MT": {Zone: "America/Denver"}, "MST": {Zone: "America/Denver", Offset: offsetHours(-7)}, "MDT": {Zone: "America/Denver", Offset: offsetHours(-6)},
+	"CT": {Zone: "America/Chicago"}, "CDT": {Zone: "America/Chicago", Offset: offsetHours(-5)},
+	"CST": {Zone: "America/Chicago", Offset: offsetHours(-6), Ambiguous: "CST also means China Standard Time (UTC+08:00); read as US Central"},
+	"ET":  {Zone: "America/New_York"}, "EST": {Zone: "America/New_York", Offset: offsetHours(-5)}, "EDT": {Zone: "America/New_York", Offset: offsetHours(-4)},
+	"AKST": {Zone: "America/Anchorage", Offset: offsetHours(-9)}, "AKDT": {Zone: "America/Anchorage", Offset: offsetHours(-8)},
+	"HST": {Zone: "Pacific/Honolulu", Offset: offsetHours(-10)},
+	"BST": {Zone: "Europe/London", Offset: offsetHours(1), Ambiguous: "BST also means Bangladesh Standard Time (UTC+06:00); read as British Summer Time"},
+	"WET": {Zone: "Europe/Lisbon", Offset: offsetHours(0)}, "WEST": {Zone: "Europe/Lisbon", Offset: offsetHours(1)},
+	"CET": {Zone: "Europe/Paris", Offset: offsetHours(1)}, "CEST": {Zone: "Europe/Paris", Offset: offsetHours(2)},
+	"EET": {Zone: "Europe/Athens", Offset: offsetHours(2)}, "EEST": {Zone: "Europe/Athens", Offset: offsetHours(3)},
+	"MSK": {Zone: "Europe/Moscow", Offset: offsetHours(3)},
+	"IST": {Zone: "Asia/Kolkata", Offset: offsetHours(5.5), Ambiguous: "IST also means Irish and Israel Standard Time; read as India Standard Time"},
+	"SGT": {Zone: "Asia/Singapore", Offset: offsetHours(8)}, "HKT": {Zone: "Asia/Hong_Kong", Offset: offsetHours(8)},
+	"JST": {Zone: "Asia/Tokyo", Offset: offsetHours(9)}, "KST": {Zone: "Asia/Seoul", Offset: offsetHours(9)},
+	"AWST": {Zone: "Australia/Perth", Offset: offsetHours(8)},
+	"ACST": {Zone: "Australia/Adelaide", Offset: offsetHours(9.5)}, "ACDT": {Zone: "Australia/Adelaide", Offset: offsetHours(10.5)},
+	"AEST": {Zone: "Australia/Sydney", Offset: offsetHours(10)}, "AEDT": {Zone: "Australia/Sydney", Offset: offsetHours(11)},
+	"NZST": {Zone: "Pacific/Auckland", Offset: offsetHours(12)}, "NZDT": {Zone: "Pacific/Auckland", Offset: offsetHours(13)},
+}
+
+// textTimePattern matches a time of day with an optional date before it and
+// an optional zone after it: "2026-03-10 14:00 CET", "March 10 at 3pm ET",
+// "10 Mar, 9:30 a.m. Europe/Warsaw", "17:00 UTC+2"
+var textTimePattern = func() *regexp.Regexp {
+	abbreviations := make([]string, 0, len(zoneAbbreviations))
+	for abbr := range zoneAbbreviations {
+		abbreviations = append(abbreviations, abbr)
+	}
+	// Longest first, so CEST wins over CET
+	slices.SortFunc(abbreviations, func(a, b string) int { return len(b) - len(a) })
+	month := `(?:jan|feb|mar|apr|may|jun|jul|aug|sep|sept|oct|nov|dec)[a-z]*\.?`
+	date := `(?P<date>\d{4}-\d{2}-\d{2}|` + month + `\s+\d{1,2}(?:st|nd|rd|th)?(?:,?\s+\d{4})?|\d{1,2}(?:st|nd|rd|th)?\s+` + month + `(?:,?\s+\d{4})?)`
+	clock := `(?P<hour>\d{1,2})(?::(?P<minute>\d{2})(?::(?P<second>\d{2}))?)?(?:\s*(?P<meridiem>[ap])\.?m\b\.?)?`
+	zone := `(?P<zone>(?:UTC|GMT)[+-]\d{1,2}(?::?\d{2})?|[+-]\d{2}:?\d{2}|(?-i:` + strings.Join(abbreviations, "|") + `)\b|(?-i:[A-Z][A-Za-z]+/[A-Za-z_]+(?:/[A-Za-z_]+)?))`
+	return regexp.MustCompile(`(?i)(?:` + date + `(?:,?\s+(?:at\s+)?|T))?` + clock + `(?:\s*` + zone + `)?`)
+}()
+
+// textAnnotations is the structured result of annotate_times_in_text
+type textAnnotations struct {
+	Timezone       string           `json:"timezone" jsonschema:"The timezone times are converted to"`
+	SourceTimezone string           `json:"source_timezone" jsonschema:"The timezone assumed for times without one"`
+	Annotations    []textAnnotation `json:"annotations"`
+	AnnotatedText  string           `json:"annotated_text" jsonschema:"The text with each converted time in brackets after its expression"`
+	Truncated      bool             `json:"truncated,omitempty" jsonschema:"Whether annotation stopped at the limit of expressions"`
+}
+
+type textAnnotation struct {
+	Text        string   `json:"text" jsonschema:"The expression as found in the text"`
+	Start       int      `json:"start" jsonschema:"Character offset of the expression, from 0"`
+	End         int      `json:"end" jsonschema:"Character offset just past the expression"`
+	Original    string   `json:"original" jsonschema:"The parsed instant in RFC 3339 in its own zone"`
+	UTC         string   `json:"utc"`
+	Converted   string   `json:"converted" jsonschema:"The instant in RFC 3339 in the target timezone"`
+	Display     string   `json:"display" jsonschema:"The converted time for reading, e.g. Tue 10 Mar 15:00 CET"`
+	DayShift    int      `json:"day_shift,omitempty" jsonschema:"Days the converted date is after (positive) or before (negative) the original date"`
+	Assumptions []string `json:"assumptions,omitempty"`
+}
+
+// textTimeMatch is one time expression found in text, as byte offsets
+type textTimeMatch struct {
+	start, end                                 int
+	date, hour, minute, second, meridiem, zone string
+	zoneStart                                  int
+}
+
+// findTextTimes returns the time expressions in text. A bare number is only a
+// time with am or pm, and a match touching digits or a colon, as in a version
+// number or a longer timestamp, is skipped.
+func findTextTimes(text string) []textTimeMatch {
+	names := textTimePattern.SubexpNames()
+	var found []textTimeMatch
+	for _, loc := range textTimePattern.FindAllStringSubmatchIndex(text, -1) {
+		m := textTimeMatch{start: loc[0], end: loc[1], zoneStart: -1}
+		for i, name := range names {
+			if name == "" || loc[2*i] < 0 {
+				continue
+			}
+			value := text[loc[2*i]:loc[2*i+1]]
+			switch name {
+			case "date":
+				m.date = value
+			case "hour":
+				m.hour = value
+			case "minute":
+				m.minute = value
+			case "second":
+				m.second = value
+			case "meridiem":
+				m.meridiem = strings.ToLower(value)
+			case "zone":
+				m.zone, m.zoneStart = value, loc[2*i]
+			}
+		}
+		if m.minute == "" && m.meridiem == "" {
+			continue
+		}
+		if m.start > 0 && strings.ContainsAny(text[m.start-1:m.start], "0123456789:.") {
+			continue
+		}
+		if m.end < len(text) && strings.ContainsAny(text[m.end:m.end+1], "0123456789:") {
+			continue
+		}
+		found = append(found, m)
+	}
+	return found
+}
+
+// clock returns the time of day of a match
+func (m textTimeMatch) clock() (time.Duration, error) {
+	hour, _ := strconv.Atoi(m.hour)
+	minute, _ := strconv.Atoi(m.minute)
+	second, _ := strconv.Atoi(m.second)
+	switch {
+	case m.meridiem != "" && (hour < 1 || hour > 12):
+		return 0, fmt.Errorf("invalid hour %d", hour)
+	case m.meridiem == "a" && hour == 12:
+		hour = 0
+	case m.meridiem == "p" && hour < 12:
+		hour += 12
+	}
+	if hour > 23 || minute > 59 || second > 59 {
+		return 0, fmt.Errorf("invalid time")
+	}
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute + time.Duration(second)*time.Second, nil
+}
+
+// textDate parses the date of a match; a date without a year takes the
+// reference year
+func textDate(s string, reference time.Time) (time.Time, bool, error) {
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, true, nil
+	}
+	var month time.Month
+	day, year := 0, 0
+	for _, f := range strings.Fields(strings.NewReplacer(",", " ", ".", " ").Replace(strings.ToLower(s))) {
+		if m := scheduleDayNumberPattern.FindStringSubmatch(f); m != nil {
+			f = m[1]
+		}
+		if n, err := strconv.Atoi(f); err == nil {
+			if len(f) == 4 {
+				year = n
+			} else {
+				day = n
+			}
+		} else if mo, ok := deadlineMonths[f]; ok {
+			month = mo
+		} else if mo, ok := deadlineMonths[f[:min(3, len(f))]]; ok {
+			month = mo
+		}
+	}
+	hasYear := year != 0
+	if !hasYear {
+		year = reference.Year()
+	}
+	if month == 0 || day < 1 || day > daysInMonth(year, month) {
+		return time.Time{}, hasYear, fmt.Errorf("invalid date %q", s)
+	}
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC), hasYear, nil
+}
+
+// textZone resolves the zone of a match to a location, or nil if the text
+// names none that is known. Fixed-offset abbreviations get a fixed zone; a
+// note explains an ambiguous reading or one that disagrees with the region's
+// clocks on that date, such as EST in July.
+func textZone(zone string, date time.Time, clock time.Duration) (*time.Location, []string) {
+	if zone == "" {
+		return nil, nil
+	}
+	if abbr, ok := zoneAbbreviations[zone]; ok {
+		loc, err := loadLocationCached(abbr.Zone)
+		if err != nil {
+			return nil, nil
+		}
+		var notes []string
+		if abbr.Ambiguous != "" {
+			notes = append(notes, abbr.Ambiguous)
+		}
+		if abbr.Offset == nil {
+			return loc, notes
+		}
+		if name, offset := wallClockOn(date, clock, loc).Zone(); offset != *abbr.Offset && abbr.Zone != "UTC" {
+			notes = append(notes, fmt.Sprintf("%s is UTC%s but %s was on %s then; used %s as written", zone, formatOffsetClock(*abbr.Offset), abbr.Zone, name, zone))
+		}
+		return time.FixedZone(zone, *abbr.Offset), notes
+	}
+	upper := strings.ToUpper(zone)
+	if strings.HasPrefix(upper, "UTC") || strings.HasPrefix(upper, "GMT") || zone[0] == '+' || zone[0] == '-' {
+		offset := strings.TrimLeft(upper, "UTCGM")
+		sign := 1
+		if offset[0] == '-' {
+			sign = -1
+		}
+		digits := strings.ReplaceAll(offset[1:], ":", "")
+		h, m := digits, "0"
+		if len(digits) > 2 {
+			h, m = digits[:len(digits)-2], digits[len(digits)-2:]
+		}
+		hh, _ := strconv.Atoi(h)
+		mm, _ := strconv.Atoi(m)
+		if hh > 14 || mm > 59 {
+			return nil, nil
+		}
+		seconds := sign * (hh*3600 + mm*60)
+		return time.FixedZone("UTC"+formatOffsetClock(seconds), seconds), nil
+	}
+	loc, err := loadLocationCached(zone)
+	if err != nil {
+		return nil, nil
+	}
+	return loc, nil
+}
+
+// annotateText converts every time expression of text from its zone, or
+// source when it names none, to target. Times without a date fall on the
+// reference date in their zone.
+func annotateText(text string, source, target *time.Location, reference time.Time) textAnnotations {
+	result := textAnnotations{Timezone: target.String(), SourceTimezone: source.String(), Annotations: []textAnnotation{}}
+	var annotated strings.Builder
+	last := 0
+	for _, m := range findTextTimes(text) {
+		if len(result.Annotations) == maxTextAnnotations {
+			result.Truncated = true
+			break
+		}
+		clock, err := m.clock()
+		if err != nil {
+			continue
+		}
+		var assumptions []string
+		date := time.Date(reference.Year(), reference.Month(), reference.Day(), 0, 0, 0, 0, time.UTC)
+		if m.date != "" {
+			d, hasYear, err := textDate(m.date, reference)
+			if err != nil {
+				continue
+			}
+			if !hasYear {
+				assumptions = append(assumptions, fmt.Sprintf("no year given; assumed %d", d.Year()))
+			}
+			date = d
+		} else {
+			assumptions = append(assumptions, "no date given; assumed "+date.Format("2006-01-02"))
+		}
+
+		loc, notes := textZone(m.zone, date, clock)
+		if loc == nil {
+			// An unknown zone word is not part of the expression
+			if m.zoneStart >= 0 {
+				m.end = len(strings.TrimRight(text[:m.zoneStart], " \t"))
+			}
+			loc = source
+			assumptions = append(assumptions, "no timezone given; assumed "+source.String())
+		}
+		assumptions = append(assumptions, notes...)
+
+		at := wallClockOn(date, clock, loc)
+		if note := clockShiftNote(at, formatClock(clock)); note != "" {
+			assumptions = append(assumptions, note)
+		}
+		converted := at.In(target)
+		originalDay := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)
+		convertedDay := time.Date(converted.Year(), converted.Month(), converted.Day(), 0, 0, 0, 0, time.UTC)
+		display := converted.Format("Mon 2 Jan 15:04 MST")
+
+		expression := text[m.start:m.end]
+		result.Annotations = append(result.Annotations, textAnnotation{
+			Text:        expression,
+			Start:       utf8.RuneCountInString(text[:m.start]),
+			End:         utf8.RuneCountInString(text[:m.end]),
+			Original:    at.Format(time.RFC3339),
+			UTC:         at.UTC().Format(time.RFC3339),
+			Converted:   converted.Format(time.RFC3339),
+			Display:     display,
+			DayShift:    int(convertedDay.Sub(originalDay).Hours() / 24),
+			Assumptions: assumptions,
+		})
+		annotated.WriteString(text[last:m.end])
+		annotated.WriteString(" [" + display + "]")
+		last = m.end
+	}
+	annotated.WriteString(text[last:])
+	result.AnnotatedText = annotated.String()
+	return result
+}
+
+func addAnnotateTimesTool(mcpServer *server.MCPServer, config *Config) {
+	mcpServer.AddTool(
+		mcp.NewTool("annotate_times_in_text",
+			mcp.WithDescription("Find the time expressions in a block of text, such as an email from another timezone, and convert each to the requester's timezone. Understands times like 14:00, 3pm and 9:30 a.m., with an optional date before them (2026-03-10, March 10, 10 Mar 2026) and an optional zone after them (CET, PT, UTC+2, Europe/Warsaw). Returns each expression with its instant and conversion, and the text with conversions inserted."),
+			mcp.WithString("text",
+				mcp.Description(fmt.Sprintf("The text to scan, up to %d bytes.", maxAnnotateTextBytes)),
+				mcp.Required(),
+			),
+			mcp.WithString("timezone",
+				mcp.Description("Timezone to convert to. Defaults to the session or server default."),
+			),
+			mcp.WithString("source_timezone",
+				mcp.Description("Timezone of times the text gives without one, e.g. the sender's. Defaults to timezone."),
+			),
+			mcp.WithString("date",
+				mcp.Description("Date of times without one, and year of dates without one, as YYYY-MM-DD, e.g. the email's date. Defaults to today in source_timezone."),
+			),
+			mcp.WithOutputSchema[textAnnotations](),
+			mcp.WithTitleAnnotation("Annotate Times in Text"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		handleAnnotateTimes(config),
+	)
+}
+
+// handleAnnotateTimes returns a handler for the annotate_times_in_text tool
+func handleAnnotateTimes(config *Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		text, err := request.RequireString("text")
+		if err != nil {
+			return toolError(errorInvalidArgument, err.Error()), nil
+		}
+		if len(text) > maxAnnotateTextBytes {
+			return toolError(errorInvalidArgument, fmt.Sprintf("Text too long: %d bytes; the limit is %d", len(text), maxAnnotateTextBytes)), nil
+		}
+		timezoneStr := request.GetString("timezone", "")
+		target, err := loadTimezone(ctx, timezoneStr, config)
+		if err != nil {
+			return invalidTimezoneError("Invalid timezone", timezoneStr), nil
+		}
+		source := target
+		if sourceStr := request.GetString("source_timezone", ""); sourceStr != "" {
+			if source, err = loadLocationCached(sourceStr); err != nil {
+				return invalidTimezoneError("Invalid source_timezone", sourceStr), nil
+			}
+		}
+		reference := config.now().In(source)
+		if dateStr := request.GetString("date", ""); dateStr != "" {
+			if reference, err = time.Parse("2006-01-02", dateStr); err != nil {
+				return toolError(errorParse, fmt.Sprintf("Invalid date: %s. Please provide YYYY-MM-DD.", dateStr)), nil
+			}
+		}
+
+		result := annotateText(text, source, target, reference)
+		data, err := json.Marshal(result)
+		if err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to encode result: %v", err)), nil
+		}
+		return mcp.NewToolResultStructured(result, string(data)), nil
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestAnnotateText(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("Failed to load timezone: %v", err)
+	}
+	warsaw, err := time.LoadLocation("Europe/Warsaw")
+	if err != nil {
+		t.Fatalf("Failed to load timezone: %v", err)
+	}
+	reference := time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)
+	text := "Can we meet March 10 at 3pm ET? Otherwise 2026-03-11T09:30:00Z, 17:00 UTC+2, or 5pm CST. Not v1.2:30 or 4 TCP/IP."
+	got := annotateText(text, newYork, warsaw, reference)
+
+	want := []struct{ text, converted string }{
+		{"March 10 at 3pm ET", "2026-03-10T20:00:00+01:00"},
+		{"2026-03-11T09:30:00Z", "2026-03-11T10:30:00+01:00"},
+		{"17:00 UTC+2", "2026-03-05T16:00:00+01:00"},
+		{"5pm CST", "2026-03-06T00:00:00+01:00"},
+	}
+	if len(got.Annotations) != len(want) {
+		t.Fatalf("Expected %d annotations but got %+v", len(want), got.Annotations)
+	}
+	for i, w := range want {
+		a := got.Annotations[i]
+		if a.Text != w.text || a.Converted != w.converted {
+			t.Errorf("Expected %q converted to %s but got %+v", w.text, w.converted, a)
+		}
+		if []rune(text)[a.Start] != []rune(w.text)[0] || a.End-a.Start != len([]rune(w.text)) {
+			t.Errorf("Expected offsets of %q but got %d-%d", w.text, a.Start, a.End)
+		}
+	}
+	// The US was already on EDT on March 10, so ET is -04:00
+	if a := got.Annotations[0]; a.Original != "2026-03-10T15:00:00-04:00" || len(a.Assumptions) != 1 {
+		t.Errorf("Expected 3pm EDT with a year assumption but got %+v", a)
+	}
+	if a := got.Annotations[3]; a.DayShift != 1 || len(a.Assumptions) != 2 {
+		t.Errorf("Expected the next day and an ambiguity note for CST but got %+v", a)
+	}
+	if want := "3pm ET [Tue 10 Mar 20:00 CET]?"; !strings.Contains(got.AnnotatedText, want) {
+		t.Errorf("Expected the annotated text to contain %q but got %q", want, got.AnnotatedText)
+	}
+
+	// A fixed-offset abbreviation out of season is used as written, with a note
+	got = annotateText("10:00 EST on 1 July", newYork, time.UTC, reference)
+	if len(got.Annotations) != 1 || got.Annotations[0].UTC != "2026-03-05T15:00:00Z" {
+		t.Fatalf("Expected 10:00 EST but got %+v", got.Annotations)
+	}
+	got = annotateText("1 July 2026 10:00 EST", newYork, time.UTC, reference)
+	if a := got.Annotations[0]; a.UTC != "2026-07-01T15:00:00Z" || len(a.Assumptions) != 1 {
+		t.Errorf("Expected EST in July with a note but got %+v", a)
+	}
+}
+
+func TestAnnotateTimesTool(t *testing.T) {
+	handler := handleAnnotateTimes(&Config{DefaultTimezone: "UTC"})
+	call := func(args map[string]any) *mcp.CallToolResult {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := handler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("Failed to call handler: %v", err)
+		}
+		return result
+	}
+
+	result := call(map[string]any{"text": "Standup is at 9:30am.", "timezone": "Asia/Tokyo", "source_timezone": "Europe/London", "date": "2026-07-01"})
+	if result.IsError {
+		t.Fatalf("Failed to annotate text: %s", toolResultText(result))
+	}
+	got := result.StructuredContent.(textAnnotations)
+	if len(got.Annotations) != 1 || got.Annotations[0].Converted != "2026-07-01T17:30:00+09:00" || got.SourceTimezone != "Europe/London" {
+		t.Errorf("Expected 9:30 BST as 17:30 in Tokyo but got %+v", got)
+	}
+
+	for _, args := range []map[string]any{
+		{},
+		{"text": "9am", "timezone": "Mars/Olympus"},
+		{"text": "9am", "source_timezone": "Mars/Olympus"},
+		{"text": "9am", "date": "July 1"},
+	} {
+		if result := call(args); !result.IsError {
+			t.Errorf("Expected an error for %v but got %s", args, toolResultText(result))
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Authentication modes
+const (
+	authModeJWT    = "jwt"
+	authModeAPIKey = "apikey"
+)
+
+// apiKey is a configured API key identified by the SHA-256 hash of its secret
+type apiKey struct {
+	Name string
+	Role string
+}
+
+// parseAPIKeys parses entries of the form name:role:sha256hex separated by
+// commas or newlines. Blank lines and lines starting with # are ignored.
+func parseAPIKeys(data string) (map[string]apiKey, error) {
+	keys := make(map[string]apiKey)
+	scanner := bufio.NewScanner(strings.NewReader(strings.ReplaceAll(data, ",", "\n")))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := strings.Split(line, ":")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("invalid API key entry %q (expected name:role:sha256hex)", line)
+		}
+		name, role, hash := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]), strings.ToLower(strings.TrimSpace(parts[2]))
+		if name == "" || role == "" {
+			return nil, fmt.Errorf("API key entry %q has an empty name or role", line)
+		}
+		if b, err := hex.DecodeString(hash); err != nil || len(b) != sha256.Size {
+			return nil, fmt.Errorf("API key %q must be a hex-encoded SHA-256 hash", name)
+		}
+		if _, dup := keys[hash]; dup {
+			return nil, fmt.Errorf("duplicate API key hash for %q", name)
+		}
+		keys[hash] = apiKey{Name: name, Role: role}
+	}
+	return keys, scanner.Err()
+}
+
+// loadAPIKeys reads API keys from the inline list and the optional file
+func loadAPIKeys(inline, file string) (map[string]apiKey, error) {
+	data := inline
+	if file != "" {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read API keys file: %w", err)
+		}
+		data += "\n" + string(content)
+	}
+	return parseAPIKeys(data)
+}
+
+// hashAPIKey returns the hex-encoded SHA-256 hash stored in the configuration
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// presentedAPIKey extracts an API key from X-API-Key or a Bearer Authorization header
+func presentedAPIKey(r *http.Request) string {
+	if key := strings.TrimSpace(r.Header.Get("X-API-Key")); key != "" {
+		return key
+	}
+	parts := strings.Fields(r.Header.Get("Authorization"))
+	if len(parts) == 2 && strings.EqualFold(parts[0], "Bearer") {
+		return parts[1]
+	}
+	return ""
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestAuthMiddleware_APIKey(t *testing.T) {
+	keys, err := parseAPIKeys("ci:reader:" + hashAPIKey("ci-secret") + "\n# comment\nops:admin:" + hashAPIKey("ops-secret"))
+	if err != nil {
+		t.Fatalf("Failed to parse API keys: %v", err)
+	}
+	auth, err := NewAuthMiddlewareFromConfig(&Config{AuthEnabled: true, AuthMode: authModeAPIKey, AuthAPIKeys: keys})
+	if err != nil {
+		t.Fatalf("Failed to create auth middleware: %v", err)
+	}
+
+	testCases := []struct {
+		name          string
+		header        string
+		value         string
+		expectedAuth  bool
+		expectedError string
+		expectedUser  string
+		expectedRole  string
+	}{
+		{"X-API-Key Header", "X-API-Key", "ci-secret", true, "", "ci", "reader"},
+		{"Bearer Header", "Authorization", "Bearer ops-secret", true, "", "ops", "admin"},
+		{"Unknown Key", "X-API-Key", "nope", false, authErrorInvalidToken, "", ""},
+		{"Missing Key", "", "", false, authErrorMissingToken, "", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, _ := http.NewRequest("POST", "/mcp", nil)
+			if tc.header != "" {
+				req.Header.Set(tc.header, tc.value)
+			}
+
+			next := func(ctx context.Context, r *http.Request) context.Context { return ctx }
+			ctx := auth.HTTPContextFunc(next)(context.Background(), req)
+
+			if isAuthenticated(ctx) != tc.expectedAuth {
+				t.Fatalf("Expected authenticated to be %v", tc.expectedAuth)
+			}
+			if getAuthError(ctx) != tc.expectedError {
+				t.Errorf("Expected auth error %q, but got %q", tc.expectedError, getAuthError(ctx))
+			}
+			if tc.expectedAuth {
+				userID, _, role := getUserInfo(ctx)
+				if userID != tc.expectedUser || role != tc.expectedRole {
+					t.Errorf("Expected %s/%s, but got %s/%s", tc.expectedUser, tc.expectedRole, userID, role)
+				}
+			}
+		})
+	}
+}
+
+func TestParseAPIKeys_Invalid(t *testing.T) {
+	testCases := []string{
+		"ci:reader",
+		"ci:reader:not-hex",
+		":reader:" + hashAPIKey("x"),
+		"a:r:" + hashAPIKey("x") + ",b:r:" + hashAPIKey("x"),
+	}
+	for _, tc := range testCases {
+		if _, err := parseAPIKeys(tc); err == nil {
+			t.Errorf("Expected an error for %q, but got nil", tc)
+		}
+	}
+}
@@ -2,9 +2,12 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/hex"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strings"
@@ -25,8 +28,13 @@ const (
 	httpMethodKey     contextKey = "http_method"
 	httpPathKey       contextKey = "http_path"
 	httpRemoteAddrKey contextKey = "http_remote_addr"
+	clientTimezoneKey contextKey = "client_timezone"
+	clientCertKey     contextKey = "client_cert_subject"
 )
 
+// defaultClientCertRole is assigned to certificate identities without an organizational unit
+const defaultClientCertRole = "user"
+
 const (
 	authErrorMissingToken = "missing_token"
 	authErrorInvalidToken = "invalid_token"
@@ -37,10 +45,18 @@ type httpMiddleware func(ctx context.Context, r *http.Request) context.Context
 
 // AuthMiddleware handles JWT-based authentication for HTTP transport
 type AuthMiddleware struct {
-	secretKey []byte
-	enabled   bool
-	issuer    string
-	audience  string
+	secretKeys secretKeyring
+	verifyKey  any
+	jwks       *jwksCache
+	oidc       bool
+	apiKeys    map[string]apiKey
+	denylist   *tokenDenylist
+	validation jwtValidation
+	methods    []string
+	enabled    bool
+	issuer     string
+	audience   string
+	tenants    tenantPolicy
 }
 
 // Claims represents JWT token claims
@@ -49,18 +65,116 @@ type Claims struct {
 	UserID   string `json:"user_id"`
 	Username string `json:"username"`
 	Role     string `json:"role"`
+	// Tenant selects a TIME_TENANTS_FILE profile; see tenantPolicy.resolve
+	Tenant string `json:"tenant,omitempty"`
+
+	// OIDC standard claims, mapped onto the fields above in OIDC mode
+	PreferredUsername string           `json:"preferred_username,omitempty"`
+	Roles             jwt.ClaimStrings `json:"roles,omitempty"`
+	Groups            jwt.ClaimStrings `json:"groups,omitempty"`
 }
 
 // NewAuthMiddleware creates a new authentication middleware
 func NewAuthMiddleware(secretKey string, enabled bool, issuer string, audience string) (*AuthMiddleware, error) {
-	if enabled && secretKey == "" {
+	return newSecretAuthMiddleware(secretKey, "", enabled, issuer, audience)
+}
+
+// newSecretAuthMiddleware verifies HS256 tokens with secretKey or, when
+// given, the keys of keyring
+func newSecretAuthMiddleware(secretKey, keyring string, enabled bool, issuer string, audience string) (*AuthMiddleware, error) {
+	keys, err := parseSecretKeys(secretKey, keyring)
+	if err != nil {
+		return nil, fmt.Errorf("invalid secret key: %w", err)
+	}
+	if enabled && len(keys) == 0 {
 		return nil, fmt.Errorf("auth enabled but secret key is empty")
 	}
 	return &AuthMiddleware{
-		secretKey: []byte(secretKey),
-		enabled:   enabled,
-		issuer:    issuer,
-		audience:  audience,
+		secretKeys: keys,
+		validation: jwtValidation{
+			leeway:         defaultAuthLeeway,
+			requiredClaims: parseHeaderList(defaultAuthRequiredClaims, false),
+		},
+		methods:  []string{algHS256},
+		enabled:  enabled,
+		issuer:   issuer,
+		audience: audience,
+	}, nil
+}
+
+// NewAuthMiddlewareFromConfig creates the authentication middleware for the
+// configured mode and signing algorithm. Asymmetric algorithms verify tokens
+// with a public key and cannot generate tokens.
+func NewAuthMiddlewareFromConfig(config *Config) (*AuthMiddleware, error) {
+	a, err := newAuthMiddlewareForMode(config)
+	if err != nil {
+		return nil, err
+	}
+	a.denylist = config.AuthDenylist
+	a.tenants = config.Tenants
+	if config.AuthMode != authModeAPIKey {
+		a.validation = config.AuthValidation
+		if a.validation.requiredClaims == nil {
+			a.validation.requiredClaims = parseHeaderList(defaultAuthRequiredClaims, false)
+		}
+		if a.methods, err = a.validation.allowedMethods(a.methods); err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+func newAuthMiddlewareForMode(config *Config) (*AuthMiddleware, error) {
+	if config.AuthMode == authModeAPIKey {
+		return &AuthMiddleware{
+			apiKeys: config.AuthAPIKeys,
+			enabled: config.AuthEnabled,
+		}, nil
+	}
+
+	if config.AuthOIDCIssuer != "" {
+		doc, err := discoverOIDC(config.AuthOIDCIssuer)
+		if err != nil {
+			return nil, err
+		}
+		slog.Info("OIDC provider discovered", "issuer", doc.Issuer, "jwks_uri", doc.JWKSURI)
+		return &AuthMiddleware{
+			jwks:     newJWKSCache(doc.JWKSURI, config.AuthJWKSRefresh),
+			oidc:     true,
+			methods:  []string{algRS256, algES256, algEdDSA},
+			enabled:  config.AuthEnabled,
+			issuer:   doc.Issuer,
+			audience: config.AuthAudience,
+		}, nil
+	}
+
+	if config.AuthJWKSURL != "" {
+		return &AuthMiddleware{
+			jwks:     newJWKSCache(config.AuthJWKSURL, config.AuthJWKSRefresh),
+			methods:  []string{algRS256, algES256, algEdDSA},
+			enabled:  config.AuthEnabled,
+			issuer:   config.AuthIssuer,
+			audience: config.AuthAudience,
+		}, nil
+	}
+
+	if config.AuthAlgorithm == "" || config.AuthAlgorithm == algHS256 {
+		return newSecretAuthMiddleware(config.AuthSecretKey, config.AuthSecretKeys, config.AuthEnabled, config.AuthIssuer, config.AuthAudience)
+	}
+
+	if config.AuthPublicKey == "" {
+		return nil, fmt.Errorf("algorithm %s requires a public key", config.AuthAlgorithm)
+	}
+	key, err := loadPublicKey(config.AuthPublicKey, config.AuthAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+	return &AuthMiddleware{
+		verifyKey: key,
+		methods:   []string{config.AuthAlgorithm},
+		enabled:   config.AuthEnabled,
+		issuer:    config.AuthIssuer,
+		audience:  config.AuthAudience,
 	}, nil
 }
 
@@ -72,27 +186,15 @@ func (a *AuthMiddleware) HTTPContextFunc(next httpMiddleware) httpMiddleware {
 			return next(ctx, r)
 		}
 
-		// Extract token from Authorization header
-		authHeader := r.Header.Get("Authorization")
-		parts := strings.Fields(authHeader)
-		if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
-			log.Printf("Missing or invalid authorization header from %s\n", r.RemoteAddr)
-			// Set authentication error in context instead of failing the request
-			ctx = context.WithValue(ctx, authErrorKey, authErrorMissingToken)
-			ctx = context.WithValue(ctx, authenticatedKey, false)
-			return next(ctx, r)
-		}
-
-		token := parts[1]
-
-		// Validate JWT token
-		claims, err := a.validateJWT(token)
+		claims, err := a.authenticate(r)
 		if err != nil {
-			log.Printf("Invalid token from %s: %v\n", r.RemoteAddr, err)
-			errorKey := authErrorInvalidToken
-			if errors.Is(err, jwt.ErrTokenExpired) {
-				errorKey = authErrorExpiredToken
+			errorKey := authErrorCode(err)
+			if errorKey == authErrorMissingToken {
+				slog.DebugContext(ctx, "Missing or invalid authorization header", "remote_addr", r.RemoteAddr)
+			} else {
+				slog.WarnContext(ctx, "Invalid token", "remote_addr", r.RemoteAddr, "error", err)
 			}
+			// Set authentication error in context instead of failing the request
 			ctx = context.WithValue(ctx, authErrorKey, errorKey)
 			ctx = context.WithValue(ctx, authenticatedKey, false)
 			return next(ctx, r)
@@ -103,32 +205,114 @@ func (a *AuthMiddleware) HTTPContextFunc(next httpMiddleware) httpMiddleware {
 		ctx = context.WithValue(ctx, userIDKey, claims.UserID)
 		ctx = context.WithValue(ctx, usernameKey, claims.Username)
 		ctx = context.WithValue(ctx, userRoleKey, claims.Role)
+		ctx = withTenant(ctx, a.tenants.resolve(claims))
 
 		return next(ctx, r)
 	}
 }
 
+// authErrorCode maps an authentication failure to the error recorded in the
+// request context
+func authErrorCode(err error) string {
+	switch {
+	case errors.Is(err, errMissingCredentials):
+		return authErrorMissingToken
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return authErrorExpiredToken
+	default:
+		return authErrorInvalidToken
+	}
+}
+
+var (
+	// errMissingCredentials is returned when a request carries no usable credentials
+	errMissingCredentials = errors.New("missing credentials")
+	// errTokenRevoked is returned for tokens whose jti is on the denylist
+	errTokenRevoked = errors.New("token has been revoked")
+)
+
+// authenticate verifies the credentials presented with a request and returns
+// the caller's identity. API key mode accepts X-API-Key or a Bearer key;
+// otherwise a Bearer JWT is required.
+func (a *AuthMiddleware) authenticate(r *http.Request) (*Claims, error) {
+	if outcome, ok := r.Context().Value(authOutcomeKey).(*authOutcome); ok && outcome.auth == a {
+		return outcome.claims, outcome.err
+	}
+	if a.apiKeys != nil {
+		return a.authenticateCredential(presentedAPIKey(r))
+	}
+
+	parts := strings.Fields(r.Header.Get("Authorization"))
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
+		return nil, errMissingCredentials
+	}
+	return a.authenticateCredential(parts[1])
+}
+
+// authOutcome is the result of authenticating a request, kept in its context
+// so layers running before the MCP handler do not verify a token twice
+type authOutcome struct {
+	auth   *AuthMiddleware
+	claims *Claims
+	err    error
+}
+
+// authOutcomeKey is the context key of a request's *authOutcome
+const authOutcomeKey contextKey = "auth_outcome"
+
+// authenticateRequest authenticates r and returns it carrying the outcome,
+// which later calls of authenticate with the same middleware reuse
+func (a *AuthMiddleware) authenticateRequest(r *http.Request) (*Claims, *http.Request, error) {
+	claims, err := a.authenticate(r)
+	outcome := &authOutcome{auth: a, claims: claims, err: err}
+	return claims, r.WithContext(context.WithValue(r.Context(), authOutcomeKey, outcome)), err
+}
+
+// authenticateCredential verifies an API key or JWT, depending on the auth mode
+func (a *AuthMiddleware) authenticateCredential(credential string) (*Claims, error) {
+	if credential == "" {
+		return nil, errMissingCredentials
+	}
+	if a.apiKeys != nil {
+		entry, ok := a.apiKeys[hashAPIKey(credential)]
+		if !ok {
+			return nil, fmt.Errorf("unknown API key")
+		}
+		return &Claims{UserID: entry.Name, Username: entry.Name, Role: entry.Role}, nil
+	}
+	return a.validateJWT(credential)
+}
+
 // validateJWT validates a JWT token and returns the claims
 func (a *AuthMiddleware) validateJWT(tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		// WithValidMethods rejects any algorithm other than the configured ones
+		kid, _ := token.Header["kid"].(string)
+		if a.jwks != nil {
+			return a.jwks.key(kid)
 		}
-		return a.secretKey, nil
-	},
-		jwt.WithIssuer(a.issuer),
-		jwt.WithAudience(a.audience),
-		jwt.WithLeeway(60*time.Second),
-		jwt.WithValidMethods([]string{"HS256"}),
-	)
+		if a.secretKeys != nil {
+			return a.secretKeys.verificationKey(kid)
+		}
+		return a.verifyKey, nil
+	}, a.validation.parserOptions(a.issuer, append([]string{a.audience}, a.tenants.audiences()...), a.methods)...)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
-		if claims.UserID == "" || claims.Username == "" || claims.Role == "" {
-			return nil, fmt.Errorf("token missing required claims")
+		if a.oidc {
+			claims.applyStandardClaims()
+		}
+		if err := a.validation.checkRequiredClaims(claims); err != nil {
+			return nil, err
+		}
+		if a.denylist != nil && a.denylist.isRevoked(claims.ID) {
+			return nil, errTokenRevoked
+		}
+		if err := a.tenants.check(claims); err != nil {
+			return nil, err
 		}
 		return claims, nil
 	}
@@ -138,22 +322,36 @@ func (a *AuthMiddleware) validateJWT(tokenString string) (*Claims, error) {
 
 // GenerateToken generates a JWT token for a user (utility function for testing/setup)
 func (a *AuthMiddleware) GenerateToken(userID, username, role string, expirationHours int) (string, error) {
-	now := time.Now()
-	claims := Claims{
-		RegisteredClaims: jwt.RegisteredClaims{
-			Issuer:    a.issuer,
-			Audience:  jwt.ClaimStrings{a.audience},
-			ExpiresAt: jwt.NewNumericDate(now.Add(time.Duration(expirationHours) * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(now),
-			NotBefore: jwt.NewNumericDate(now),
-		},
-		UserID:   userID,
-		Username: username,
-		Role:     role,
+	token, _, err := a.generateToken(tokenOptions{
+		UserID:     userID,
+		Username:   username,
+		Role:       role,
+		Expiration: time.Duration(expirationHours) * time.Hour,
+	})
+	return token, err
+}
+
+// newTokenID returns a random identifier for the jti claim
+func newTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token ID: %w", err)
 	}
+	return hex.EncodeToString(b), nil
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(a.secretKey)
+// tokenID returns the jti of a JWT without verifying it. Values that are not
+// JWTs are assumed to be token IDs already.
+func tokenID(value string) string {
+	value = strings.TrimSpace(value)
+	if strings.Count(value, ".") != 2 {
+		return value
+	}
+	claims := &Claims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(value, claims); err != nil {
+		return ""
+	}
+	return claims.ID
 }
 
 // isAuthenticated checks if the request context contains valid authentication
@@ -186,46 +384,58 @@ func getUserInfo(ctx context.Context) (userID, username, role string) {
 	return
 }
 
-// CreateTokenCommand creates a command-line utility to generate tokens
-func CreateTokenCommand(secretKey, userID, username, role string, expirationHours int) {
-	if secretKey == "" {
-		log.Println("Error: TIME_AUTH_SECRET_KEY environment variable is required")
-		return
+// getClientCertSubject returns the verified client certificate subject, if any
+func getClientCertSubject(ctx context.Context) string {
+	if val, ok := ctx.Value(clientCertKey).(string); ok {
+		return val
 	}
+	return ""
+}
 
-	auth, err := NewAuthMiddleware(secretKey, true, "TimeMCP", "TimeMCP-user")
-	if err != nil {
-		log.Printf("Error creating auth middleware: %v\n", err)
-		return
+// withClientCertIdentity records a verified client certificate in the context and,
+// when the request presented no other credentials, uses it as the
+// authenticated identity. A bearer credential that failed validation keeps
+// its error, so a revoked or expired token is not masked by the certificate.
+// The certificate's first organizational unit is used as the role.
+func withClientCertIdentity(ctx context.Context, cert *x509.Certificate) context.Context {
+	ctx = context.WithValue(ctx, clientCertKey, cert.Subject.String())
+	if isAuthenticated(ctx) {
+		return ctx
+	}
+	if authError := getAuthError(ctx); authError != "" && authError != authErrorMissingToken {
+		return ctx
 	}
 
-	token, err := auth.GenerateToken(userID, username, role, expirationHours)
-	if err != nil {
-		log.Printf("Error generating token: %v\n", err)
-		return
-	}
-
-	log.Printf("Generated JWT token:\n%s\n\n", token)
-	log.Printf("Token details:\n")
-	log.Printf("  User ID: %s\n", userID)
-	log.Printf("  Username: %s\n", username)
-	log.Printf("  Role: %s\n", role)
-	log.Printf("  Expires: %s\n", time.Now().Add(time.Duration(expirationHours)*time.Hour).Format(time.RFC3339))
-	log.Printf("\nTo use this token, include it in HTTP requests:\n")
-	log.Printf("  Authorization: Bearer %s\n", token)
+	claims := clientCertClaims(cert)
+	ctx = context.WithValue(ctx, authErrorKey, "")
+	ctx = context.WithValue(ctx, authenticatedKey, true)
+	ctx = context.WithValue(ctx, userIDKey, claims.UserID)
+	ctx = context.WithValue(ctx, usernameKey, claims.Username)
+	ctx = context.WithValue(ctx, userRoleKey, claims.Role)
+	return ctx
+}
+
+// clientCertClaims returns the identity of a verified client certificate
+func clientCertClaims(cert *x509.Certificate) *Claims {
+	role := defaultClientCertRole
+	if len(cert.Subject.OrganizationalUnit) > 0 {
+		role = cert.Subject.OrganizationalUnit[0]
+	}
+	return &Claims{UserID: cert.Subject.String(), Username: cert.Subject.CommonName, Role: role}
 }
 
-func createHTTPMiddleware(config *Config) (server.HTTPContextFunc, error) {
+func createHTTPMiddleware(config *Config) (server.HTTPContextFunc, *AuthMiddleware, error) {
 	// Create authentication middleware
 	var authMiddleware *AuthMiddleware
 	if config.AuthEnabled {
 		var err error
-		authMiddleware, err = NewAuthMiddleware(config.AuthSecretKey, config.AuthEnabled, config.AuthIssuer, config.AuthAudience)
+		authMiddleware, err = NewAuthMiddlewareFromConfig(config)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create auth middleware: %v", err)
+			return nil, nil, fmt.Errorf("failed to create auth middleware: %v", err)
 		}
-		log.Println("HTTP authentication enabled")
+		slog.Info("HTTP authentication enabled")
 	}
+	clientTZ := newClientTimezoneDetector(config)
 
 	return func(ctx context.Context, r *http.Request) context.Context {
 
@@ -239,14 +449,37 @@ func createHTTPMiddleware(config *Config) (server.HTTPContextFunc, error) {
 			ctx = authMiddleware.HTTPContextFunc(nextFunc)(ctx, r)
 		}
 
+		// A verified client certificate is an alternative identity to JWT
+		if cert, ok := clientCertIdentity(r.TLS); ok {
+			ctx = withClientCertIdentity(ctx, cert)
+		}
+
 		// Add request info to context
 		ctx = context.WithValue(ctx, httpMethodKey, r.Method)
 		ctx = context.WithValue(ctx, httpPathKey, r.URL.Path)
 		ctx = context.WithValue(ctx, httpRemoteAddrKey, r.RemoteAddr)
+		if clientTZ != nil {
+			if detected, ok := clientTZ.detect(r); ok {
+				ctx = withClientTimezone(ctx, detected)
+			}
+		}
 
 		return ctx
-	}, nil
+	}, authMiddleware, nil
 }
+
+// ready reports whether verification keys are available. A JWKS that could
+// not be fetched at startup is retried so the server becomes ready once the
+// key set is reachable.
+func (a *AuthMiddleware) ready() error {
+	if a.jwks != nil && !a.jwks.ready() {
+		if err := a.jwks.refresh(); err != nil {
+			return fmt.Errorf("JWKS unavailable: %w", err)
+		}
+	}
+	return nil
+}
+
 func checkOrigin(originURL *url.URL, allowed string) bool {
 	host := originURL.Host
 	hostname := originURL.Hostname()
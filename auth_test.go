@@ -2,9 +2,20 @@ package main
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"errors"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
@@ -266,3 +277,174 @@ func TestValidateJWT_MissingClaims(t *testing.T) {
 		t.Errorf("Expected error to be 'token missing required claims', but got '%v'", err)
 	}
 }
+
+func TestWithClientCertIdentity(t *testing.T) {
+	cert := &x509.Certificate{
+		Subject: pkix.Name{CommonName: "agent-1", OrganizationalUnit: []string{"reader"}},
+	}
+
+	ctx := withClientCertIdentity(context.Background(), cert)
+
+	if !isAuthenticated(ctx) {
+		t.Fatal("Expected client certificate to authenticate the request")
+	}
+	userID, username, role := getUserInfo(ctx)
+	if userID != cert.Subject.String() {
+		t.Errorf("Expected userID to be '%s', but got '%s'", cert.Subject.String(), userID)
+	}
+	if username != "agent-1" {
+		t.Errorf("Expected username to be 'agent-1', but got '%s'", username)
+	}
+	if role != "reader" {
+		t.Errorf("Expected role to be 'reader', but got '%s'", role)
+	}
+	if getClientCertSubject(ctx) != cert.Subject.String() {
+		t.Errorf("Expected client cert subject to be recorded, but got '%s'", getClientCertSubject(ctx))
+	}
+}
+
+func TestClientCertIdentity_DoesNotMaskRejectedToken(t *testing.T) {
+	denylist, err := newTokenDenylist("", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &Config{
+		AuthEnabled:   true,
+		AuthSecretKey: "test-secret-key-that-is-long-enough-for-hs256",
+		AuthIssuer:    "test-issuer",
+		AuthAudience:  "test-audience",
+		AuthDenylist:  denylist,
+	}
+	fn, auth, err := createHTTPMiddleware(config)
+	if err != nil {
+		t.Fatalf("Failed to create HTTP middleware: %v", err)
+	}
+	contextFunc := &reloadableContextFunc{}
+	contextFunc.store(fn, auth)
+	rt := &httpRuntime{contextFunc: contextFunc}
+	token, claims, err := auth.generateToken(tokenOptions{UserID: "1", Username: "ada", Role: "admin", Expiration: time.Hour})
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+	denylist.revoke(claims["jti"].(string))
+
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "agent-1", OrganizationalUnit: []string{"reader"}}}
+	request := func(bearer string) *http.Request {
+		req := httptest.NewRequest("POST", "/mcp", nil)
+		req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+		if bearer != "" {
+			req.Header.Set("Authorization", "Bearer "+bearer)
+		}
+		return req
+	}
+
+	// A revoked token sent with a valid certificate is still rejected
+	req := request(token)
+	ctx := fn(req.Context(), req)
+	if isAuthenticated(ctx) || getAuthError(ctx) != authErrorInvalidToken {
+		t.Errorf("Expected the revoked token to stay rejected, but got authenticated=%v error=%q", isAuthenticated(ctx), getAuthError(ctx))
+	}
+	if result := checkAuth(ctx, "get_current_time", config); result == nil || toolErrorCodeOf(result) != errorUnauthorized {
+		t.Errorf("Expected tool calls to be unauthorized, but got %+v", result)
+	}
+	rec := httptest.NewRecorder()
+	if _, ok := authenticateEndpoint(rec, request(token), config, rt); ok || rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected endpoints to reject the revoked token with 401, but got %d", rec.Code)
+	}
+
+	// Without a bearer credential the certificate is the identity
+	req = request("")
+	ctx = fn(req.Context(), req)
+	if _, _, role := getUserInfo(ctx); !isAuthenticated(ctx) || role != "reader" {
+		t.Errorf("Expected the certificate identity without a token, but got authenticated=%v role=%q", isAuthenticated(ctx), role)
+	}
+	rec = httptest.NewRecorder()
+	if claims, ok := authenticateEndpoint(rec, request(""), config, rt); !ok || claims.Role != "reader" {
+		t.Errorf("Expected endpoints to accept the certificate identity, but got %d", rec.Code)
+	}
+}
+
+func TestAuthMiddlewareFromConfig_Asymmetric(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate RSA key: %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate EC key: %v", err)
+	}
+	edPub, edPriv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate Ed25519 key: %v", err)
+	}
+
+	testCases := []struct {
+		alg     string
+		method  jwt.SigningMethod
+		private any
+		public  any
+	}{
+		{algRS256, jwt.SigningMethodRS256, rsaKey, &rsaKey.PublicKey},
+		{algES256, jwt.SigningMethodES256, ecKey, &ecKey.PublicKey},
+		{algEdDSA, jwt.SigningMethodEdDSA, edPriv, edPub},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.alg, func(t *testing.T) {
+			der, err := x509.MarshalPKIXPublicKey(tc.public)
+			if err != nil {
+				t.Fatalf("Failed to marshal public key: %v", err)
+			}
+			config := &Config{
+				AuthEnabled:   true,
+				AuthIssuer:    "test-issuer",
+				AuthAudience:  "test-audience",
+				AuthAlgorithm: tc.alg,
+				AuthPublicKey: string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+			}
+			auth, err := NewAuthMiddlewareFromConfig(config)
+			if err != nil {
+				t.Fatalf("Failed to create auth middleware: %v", err)
+			}
+
+			claims := Claims{
+				RegisteredClaims: jwt.RegisteredClaims{
+					Issuer:    "test-issuer",
+					Audience:  jwt.ClaimStrings{"test-audience"},
+					ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+				},
+				UserID:   "1",
+				Username: "testuser",
+				Role:     "user",
+			}
+			signed, err := jwt.NewWithClaims(tc.method, claims).SignedString(tc.private)
+			if err != nil {
+				t.Fatalf("Failed to sign token: %v", err)
+			}
+
+			if _, err := auth.validateJWT(signed); err != nil {
+				t.Errorf("Expected token to validate, but got %v", err)
+			}
+
+			// An HS256 token must not be accepted by an asymmetric verifier
+			hmacToken, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(der)
+			if _, err := auth.validateJWT(hmacToken); err == nil {
+				t.Error("Expected HS256 token to be rejected")
+			}
+
+			if _, err := auth.GenerateToken("1", "testuser", "user", 1); err == nil {
+				t.Error("Expected token generation to fail without a secret key")
+			}
+		})
+	}
+}
+
+func TestLoadPublicKey_AlgorithmMismatch(t *testing.T) {
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	der, _ := x509.MarshalPKIXPublicKey(&ecKey.PublicKey)
+	pemKey := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+
+	if _, err := loadPublicKey(pemKey, algRS256); err == nil {
+		t.Fatal("Expected an error for an EC key used with RS256, but got nil")
+	}
+}
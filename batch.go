@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// maxBatchCalls caps the calls of one batch_call
+	maxBatchCalls = 20
+	// batchParallelism is how many calls of a batch run at once
+	batchParallelism = 4
+)
+
+// batchCallInput is one call as given
+type batchCallInput struct {
+	Tool      string         `json:"tool"`
+	Arguments map[string]any `json:"arguments"`
+}
+
+// batchCallResults is the structured result of batch_call
+type batchCallResults struct {
+	Results   []batchCallResult `json:"results" jsonschema:"One entry per call, in the order given"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+}
+
+type batchCallResult struct {
+	Tool   string        `json:"tool"`
+	Result any           `json:"result,omitempty" jsonschema:"The tool's structured result or, for tools without one, its text"`
+	Error  string        `json:"error,omitempty"`
+	Code   toolErrorCode `json:"code,omitempty" jsonschema:"Error code, as returned by the tool when called directly"`
+}
+
+// addBatchTool registers batch_call. Each call runs through chain, so it is
+// authorized, counted against quotas, cached and timed out as if the client
+// had made it directly.
+func addBatchTool(mcpServer *server.MCPServer, chain *toolMiddlewareChain) {
+	mcpServer.AddTool(
+		mcp.NewTool("batch_call",
+			mcp.WithDescription(fmt.Sprintf("Call several tools at once, e.g. the current time in three zones and whether tomorrow is a business day. Calls are independent and run concurrently, %d at a time; each returns its own result or error, in the order given, so one failure does not fail the batch.", batchParallelism)),
+			mcp.WithArray("calls",
+				mcp.Description("Tool calls: the tool name and its arguments as for a direct call. batch_call cannot be nested."),
+				mcp.Items(map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"tool":      map[string]any{"type": "string"},
+						"arguments": map[string]any{"type": "object"},
+					},
+					"required": []string{"tool"},
+				}),
+				mcp.MinItems(1),
+				mcp.MaxItems(maxBatchCalls),
+				mcp.Required(),
+			),
+			mcp.WithOutputSchema[batchCallResults](),
+			mcp.WithTitleAnnotation("Batch Tool Calls"),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(false),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		handleBatchCall(mcpServer, chain),
+	)
+}
+
+// handleBatchCall returns a handler for the batch_call tool
+func handleBatchCall(mcpServer *server.MCPServer, chain *toolMiddlewareChain) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Calls []batchCallInput `json:"calls"`
+		}
+		if err := request.BindArguments(&args); err != nil {
+			return toolError(errorInvalidArgument, fmt.Sprintf("Invalid calls: %v", err)), nil
+		}
+		if len(args.Calls) == 0 || len(args.Calls) > maxBatchCalls {
+			return toolError(errorInvalidArgument, fmt.Sprintf("Provide between 1 and %d calls", maxBatchCalls)), nil
+		}
+
+		results := batchCallResults{Results: make([]batchCallResult, len(args.Calls))}
+		sem := make(chan struct{}, batchParallelism)
+		var wg sync.WaitGroup
+		for i, call := range args.Calls {
+			wg.Go(func() {
+				sem <- struct{}{}
+				defer func() { <-sem }()
+				results.Results[i] = runBatchCall(ctx, mcpServer, chain, call)
+			})
+		}
+		wg.Wait()
+		for _, r := range results.Results {
+			if r.Error != "" {
+				results.Failed++
+			} else {
+				results.Succeeded++
+			}
+		}
+
+		data, err := json.Marshal(results)
+		if err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to encode result: %v", err)), nil
+		}
+		return mcp.NewToolResultStructured(results, string(data)), nil
+	}
+}
+
+// runBatchCall makes one call of a batch through the middleware chain
+func runBatchCall(ctx context.Context, mcpServer *server.MCPServer, chain *toolMiddlewareChain, call batchCallInput) batchCallResult {
+	entry := batchCallResult{Tool: call.Tool}
+	if call.Tool == "batch_call" {
+		entry.Error, entry.Code = "batch_call cannot be nested", errorInvalidArgument
+		return entry
+	}
+	tool := mcpServer.GetTool(call.Tool)
+	if tool == nil {
+		entry.Error, entry.Code = fmt.Sprintf("Unknown tool: %s", call.Tool), errorNotFound
+		return entry
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = call.Tool
+	req.Params.Arguments = call.Arguments
+	result, err := chain.wrap(tool.Handler)(ctx, req)
+	switch {
+	case err != nil:
+		entry.Error, entry.Code = err.Error(), errorInternal
+	case result == nil:
+		entry.Error, entry.Code = "Tool returned no result", errorInternal
+	case result.IsError:
+		entry.Error, entry.Code = toolResultText(result), toolErrorCodeOf(result)
+	case result.StructuredContent != nil:
+		entry.Result = result.StructuredContent
+	default:
+		entry.Result = toolResultText(result)
+	}
+	return entry
+}
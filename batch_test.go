@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestBatchCall(t *testing.T) {
+	config := &Config{ToolTimeout: time.Second}
+	mcpServer := server.NewMCPServer("TimeMCP", "test", server.WithToolCapabilities(true))
+	chain := newToolMiddlewareChain(func() *Config { return config }, nil, nil)
+	addTools(mcpServer, config)
+	addBatchTool(mcpServer, chain)
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"calls": []any{
+		map[string]any{"tool": "get_current_time", "arguments": map[string]any{"timezone": "UTC"}},
+		map[string]any{"tool": "date_facts", "arguments": map[string]any{"date": "2026-03-10", "timezone": "Mars/Olympus"}},
+		map[string]any{"tool": "no_such_tool"},
+		map[string]any{"tool": "batch_call", "arguments": map[string]any{"calls": []any{}}},
+		map[string]any{"tool": "date_facts", "arguments": map[string]any{"date": "2026-03-10", "timezone": "Europe/Warsaw"}},
+	}}
+	result, err := handleBatchCall(mcpServer, chain)(context.Background(), req)
+	if err != nil || result.IsError {
+		t.Fatalf("Expected the batch to succeed but got %v %v", err, result.Content)
+	}
+	batch := result.StructuredContent.(batchCallResults)
+	if batch.Succeeded != 2 || batch.Failed != 3 {
+		t.Errorf("Expected 2 successes and 3 failures but got %+v", batch)
+	}
+	expected := []struct {
+		tool string
+		code toolErrorCode
+	}{
+		{"get_current_time", ""},
+		{"date_facts", errorInvalidTimezone},
+		{"no_such_tool", errorNotFound},
+		{"batch_call", errorInvalidArgument},
+		{"date_facts", ""},
+	}
+	for i, want := range expected {
+		got := batch.Results[i]
+		if got.Tool != want.tool || got.Code != want.code || (got.Error == "") != (want.code == "") {
+			t.Errorf("Result %d: expected %s with code %q but got %+v", i, want.tool, want.code, got)
+		}
+	}
+	if _, ok := batch.Results[4].Result.(dateFacts); !ok {
+		t.Errorf("Expected the structured result of date_facts but got %T", batch.Results[4].Result)
+	}
+
+	req.Params.Arguments = map[string]any{"calls": []any{}}
+	if result, _ := handleBatchCall(mcpServer, chain)(context.Background(), req); !result.IsError {
+		t.Error("Expected an empty batch to be rejected")
+	}
+}
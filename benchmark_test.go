@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Hot-path benchmarks; compare runs with benchstat before releasing:
+//
+//	go test -run '^$' -bench . -benchmem -count 10 > new.txt
+
+func benchmarkToolRequest(name string, args map[string]any) mcp.CallToolRequest {
+	req := mcp.CallToolRequest{}
+	req.Params.Name = name
+	req.Params.Arguments = args
+	return req
+}
+
+func BenchmarkHandleGetCurrentTime(b *testing.B) {
+	handler := handleGetCurrentTime(&Config{})
+	req := benchmarkToolRequest("get_current_time", map[string]any{"timezone": "Europe/Warsaw"})
+	ctx := context.Background()
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := handler(ctx, req); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkHandleConvertTime(b *testing.B) {
+	handler := handleConvertTime(&Config{})
+	req := benchmarkToolRequest("convert_time", map[string]any{
+		"source_timezone": "America/New_York",
+		"target_timezone": "Asia/Tokyo",
+		"time":            "14:30",
+	})
+	ctx := context.Background()
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := handler(ctx, req); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkToolMiddlewareChain measures the per-call overhead of the
+// standard middleware chain around a trivial handler
+func BenchmarkToolMiddlewareChain(b *testing.B) {
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.DiscardHandler))
+	b.Cleanup(func() { slog.SetDefault(previous) })
+
+	config := &Config{ToolTimeout: 10 * time.Second}
+	chain := newToolMiddlewareChain(func() *Config { return config }, newServerStats(""), nil)
+	handler := chain.wrap(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	req := benchmarkToolRequest("get_current_time", map[string]any{"timezone": "UTC"})
+	ctx := context.Background()
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := handler(ctx, req); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkRedactString(b *testing.B) {
+	line := "Tool call completed tool=convert_time user_id=user1 remote_addr=203.0.113.7 duration_ms=0.42"
+	b.ReportAllocs()
+	for b.Loop() {
+		redactString(line)
+	}
+}
+
+func BenchmarkFormatAccessLog(b *testing.B) {
+	entry := accessLogEntry{
+		Time: time.Now(), RemoteAddr: "203.0.113.7:51234", Method: "POST", URI: "/mcp",
+		Proto: "HTTP/1.1", Status: 200, Bytes: 512, Duration: time.Millisecond, UserAgent: "client/1.0",
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		formatAccessLog(entry, accessLogJSON)
+	}
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/araddon/dateparse"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// maxBucketTimes caps the timestamps of one bucket_time call
+	maxBucketTimes = 1000
+	// maxBucketBands caps the custom bands of one bucket_time call
+	maxBucketBands = 48
+	// otherBucket names times outside every custom band
+	otherBucket = "other"
+)
+
+// timeBand is a named range of wall clock time; End before Start wraps
+// past midnight
+type timeBand struct {
+	Name       string
+	Start, End time.Duration
+}
+
+// contains reports whether a wall clock offset from midnight is in the band
+func (b timeBand) contains(clock time.Duration) bool {
+	if b.End > b.Start {
+		return clock >= b.Start && clock < b.End
+	}
+	return clock >= b.Start || clock < b.End
+}
+
+// dayPartBands are the day parts by language, with night wrapping past midnight
+var dayPartBands = map[string][]timeBand{
+	"en": {
+		{"morning", 5 * time.Hour, 12 * time.Hour},
+		{"afternoon", 12 * time.Hour, 17 * time.Hour},
+		{"evening", 17 * time.Hour, 21 * time.Hour},
+		{"night", 21 * time.Hour, 5 * time.Hour},
+	},
+	"pl": {
+		{"rano", 5 * time.Hour, 12 * time.Hour},
+		{"popołudnie", 12 * time.Hour, 17 * time.Hour},
+		{"wieczór", 17 * time.Hour, 21 * time.Hour},
+		{"noc", 21 * time.Hour, 5 * time.Hour},
+	},
+}
+
+// hourBands splits the day into bands of the given width in hours
+func hourBands(width int) []timeBand {
+	var bands []timeBand
+	for h := 0; h < 24; h += width {
+		start, end := time.Duration(h)*time.Hour, time.Duration(h+width)*time.Hour
+		bands = append(bands, timeBand{formatClock(start) + "-" + formatClock(end), start, end})
+	}
+	return bands
+}
+
+// parseTimeBands parses custom bands such as "early 05:00-09:00, core
+// 09:00-17:00"; bands may wrap past midnight and the first match wins
+func parseTimeBands(spec string) ([]timeBand, error) {
+	var bands []timeBand
+	for part := range strings.SplitSeq(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		i := strings.LastIndex(part, " ")
+		if i < 0 {
+			return nil, fmt.Errorf("band %q has no name (expected NAME HH:MM-HH:MM)", part)
+		}
+		name, clocks := strings.TrimSpace(part[:i]), part[i+1:]
+		from, to, ok := strings.Cut(clocks, "-")
+		if !ok {
+			return nil, fmt.Errorf("band %q has no range (expected NAME HH:MM-HH:MM)", part)
+		}
+		start, err := parseClock(from)
+		if err != nil {
+			return nil, fmt.Errorf("band %q: %w", name, err)
+		}
+		end, err := parseClock(to)
+		if err != nil {
+			return nil, fmt.Errorf("band %q: %w", name, err)
+		}
+		if start == end || start >= 24*time.Hour {
+			return nil, fmt.Errorf("band %q is empty", name)
+		}
+		bands = append(bands, timeBand{name, start, end % (24 * time.Hour)})
+	}
+	if len(bands) == 0 || len(bands) > maxBucketBands {
+		return nil, fmt.Errorf("provide between 1 and %d bands", maxBucketBands)
+	}
+	return bands, nil
+}
+
+// bucketAssignments is the structured result of bucket_time
+type bucketAssignments struct {
+	Timezone    string             `json:"timezone"`
+	Scheme      string             `json:"scheme"`
+	Assignments []bucketAssignment `json:"assignments" jsonschema:"The times in input order with their buckets"`
+	Counts      []bucketCount      `json:"counts" jsonschema:"Times per bucket, in band order; empty buckets included"`
+}
+
+type bucketAssignment struct {
+	Time   string `json:"time" jsonschema:"The time in the bucket timezone, RFC 3339"`
+	Bucket string `json:"bucket"`
+}
+
+type bucketCount struct {
+	Bucket string `json:"bucket"`
+	Count  int    `json:"count"`
+}
+
+func addBucketTimeTool(mcpServer *server.MCPServer, config *Config) {
+	mcpServer.AddTool(
+		mcp.NewTool("bucket_time",
+			mcp.WithDescription("Assign timestamps to named buckets by their local time of day, e.g. to categorize events for analytics: day parts (morning, afternoon, evening, night), hour bands, or custom bands such as 'early 05:00-09:00, core 09:00-17:00, late 17:00-22:00'. Returns each time's bucket and the count per bucket."),
+			mcp.WithArray("times",
+				mcp.Description("Timestamps in any common format (RFC 3339, '2025-03-10 14:05', Unix seconds). Times without an offset are in timezone."),
+				mcp.WithStringItems(),
+				mcp.MinItems(1),
+				mcp.MaxItems(maxBucketTimes),
+				mcp.Required(),
+			),
+			mcp.WithString("timezone",
+				mcp.Description("Timezone whose local time decides the bucket. Defaults to the session or server default."),
+			),
+			mcp.WithString("scheme",
+				mcp.Description("day_part (05-12 morning, 12-17 afternoon, 17-21 evening, 21-05 night), hour (bands of band_hours), or custom (bands). Defaults to day_part."),
+				mcp.Enum("day_part", "hour", "custom"),
+			),
+			mcp.WithNumber("band_hours",
+				mcp.Description("Width of hour bands, a divisor of 24. Defaults to 1."),
+			),
+			mcp.WithString("bands",
+				mcp.Description("Custom bands as comma-separated 'NAME HH:MM-HH:MM'; bands may wrap past midnight and the first match wins. Times outside every band go to 'other'."),
+			),
+			mcp.WithString("language",
+				mcp.Description("Language of day part names: en or pl. Defaults to the session locale when supported, otherwise en."),
+			),
+			mcp.WithOutputSchema[bucketAssignments](),
+			mcp.WithTitleAnnotation("Bucket Time"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		handleBucketTime(config),
+	)
+}
+
+// handleBucketTime returns a handler for the bucket_time tool
+func handleBucketTime(config *Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		times := request.GetStringSlice("times", nil)
+		if len(times) == 0 || len(times) > maxBucketTimes {
+			return toolError(errorInvalidArgument, fmt.Sprintf("Provide between 1 and %d times", maxBucketTimes)), nil
+		}
+		timezoneStr := request.GetString("timezone", "")
+		loc, err := loadTimezone(ctx, timezoneStr, config)
+		if err != nil {
+			return invalidTimezoneError("Invalid timezone", timezoneStr), nil
+		}
+
+		scheme := request.GetString("scheme", "day_part")
+		var bands []timeBand
+		switch scheme {
+		case "day_part":
+			// An omitted language follows the session locale when it is supported
+			language := strings.ToLower(request.GetString("language", ""))
+			if language == "" {
+				language = "en"
+				if lang := sessionLanguage(ctx); dayPartBands[lang] != nil {
+					language = lang
+				}
+			}
+			if bands = dayPartBands[language]; bands == nil {
+				return toolError(errorInvalidArgument, fmt.Sprintf("Unsupported language: %s. Supported languages: en, pl", language)), nil
+			}
+		case "hour":
+			width := request.GetInt("band_hours", 1)
+			if width < 1 || width > 24 || 24%width != 0 {
+				return toolError(errorInvalidArgument, fmt.Sprintf("Invalid band_hours: %d. Please provide a divisor of 24.", width)), nil
+			}
+			bands = hourBands(width)
+		case "custom":
+			if bands, err = parseTimeBands(request.GetString("bands", "")); err != nil {
+				return toolError(errorParse, fmt.Sprintf("Invalid bands: %v", err)), nil
+			}
+		default:
+			return toolError(errorInvalidArgument, fmt.Sprintf("Invalid scheme: %s. Please use day_part, hour or custom.", scheme)), nil
+		}
+
+		result := bucketAssignments{Timezone: loc.String(), Scheme: scheme}
+		counts := make(map[string]int)
+		for i, s := range times {
+			t, err := dateparse.ParseIn(s, loc)
+			if err != nil {
+				return toolError(errorParse, fmt.Sprintf("Invalid time %d: %q. Please provide RFC 3339 or another common date-time format.", i, s)), nil
+			}
+			t = t.In(loc)
+			clock := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+			bucket := otherBucket
+			for _, b := range bands {
+				if b.contains(clock) {
+					bucket = b.Name
+					break
+				}
+			}
+			counts[bucket]++
+			result.Assignments = append(result.Assignments, bucketAssignment{Time: t.Format(time.RFC3339), Bucket: bucket})
+		}
+
+		// Bands may share a name, e.g. two halves of a night
+		listed := make(map[string]bool)
+		for _, b := range bands {
+			if !listed[b.Name] {
+				listed[b.Name] = true
+				result.Counts = append(result.Counts, bucketCount{b.Name, counts[b.Name]})
+			}
+		}
+		if n := counts[otherBucket]; n > 0 && !listed[otherBucket] {
+			result.Counts = append(result.Counts, bucketCount{otherBucket, n})
+		}
+
+		data, err := json.Marshal(result)
+		if err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to encode result: %v", err)), nil
+		}
+		return mcp.NewToolResultStructured(result, string(data)), nil
+	}
+}
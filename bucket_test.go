@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestParseTimeBands(t *testing.T) {
+	bands, err := parseTimeBands("early 05:00-09:00, core hours 09:00-17:00, late 22:00-02:00")
+	if err != nil {
+		t.Fatalf("Failed to parse bands: %v", err)
+	}
+	if len(bands) != 3 || bands[1].Name != "core hours" || bands[2].contains(21*time.Hour) || !bands[2].contains(time.Hour) {
+		t.Errorf("Expected three bands with late wrapping past midnight but got %+v", bands)
+	}
+	for _, spec := range []string{"", "05:00-09:00", "early 05:00", "early 05:00-05:00", "early 5am-9am"} {
+		if _, err := parseTimeBands(spec); err == nil {
+			t.Errorf("Expected an error for %q", spec)
+		}
+	}
+}
+
+func TestBucketTime(t *testing.T) {
+	handler := handleBucketTime(&Config{DefaultTimezone: "UTC"})
+	call := func(args map[string]any) *mcp.CallToolResult {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := handler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("Failed to call handler: %v", err)
+		}
+		return result
+	}
+	buckets := func(got bucketAssignments) string {
+		var names []string
+		for _, a := range got.Assignments {
+			names = append(names, a.Bucket)
+		}
+		return strings.Join(names, ",")
+	}
+
+	// 2025-06-02T03:30Z is 05:30 in Warsaw
+	times := []any{"2025-06-02T03:30:00Z", "2025-06-02 12:00", "2025-06-02 20:59", "2025-06-02 23:15", "1748822400"}
+	result := call(map[string]any{"times": times, "timezone": "Europe/Warsaw"})
+	if result.IsError {
+		t.Fatalf("Failed to bucket times: %s", toolResultText(result))
+	}
+	got := result.StructuredContent.(bucketAssignments)
+	if b := buckets(got); b != "morning,afternoon,evening,night,night" {
+		t.Errorf("Expected day parts but got %s", b)
+	}
+	if got.Assignments[0].Time != "2025-06-02T05:30:00+02:00" {
+		t.Errorf("Expected times in Warsaw but got %s", got.Assignments[0].Time)
+	}
+	if len(got.Counts) != 4 || got.Counts[3] != (bucketCount{"night", 2}) {
+		t.Errorf("Expected four day parts with two at night but got %+v", got.Counts)
+	}
+
+	got = call(map[string]any{"times": times[:2], "timezone": "Europe/Warsaw", "language": "pl"}).StructuredContent.(bucketAssignments)
+	if b := buckets(got); b != "rano,popołudnie" {
+		t.Errorf("Expected Polish day parts but got %s", b)
+	}
+
+	got = call(map[string]any{"times": times, "timezone": "Europe/Warsaw", "scheme": "hour", "band_hours": 6}).StructuredContent.(bucketAssignments)
+	if b := buckets(got); b != "00:00-06:00,12:00-18:00,18:00-24:00,18:00-24:00,00:00-06:00" {
+		t.Errorf("Expected 6-hour bands but got %s", b)
+	}
+
+	got = call(map[string]any{"times": times, "timezone": "Europe/Warsaw", "scheme": "custom", "bands": "core 09:00-17:00, late 20:00-24:00"}).StructuredContent.(bucketAssignments)
+	if b := buckets(got); b != "other,core,late,late,other" {
+		t.Errorf("Expected custom bands but got %s", b)
+	}
+	if len(got.Counts) != 3 || got.Counts[2] != (bucketCount{"other", 2}) {
+		t.Errorf("Expected other listed last but got %+v", got.Counts)
+	}
+
+	for _, args := range []map[string]any{
+		{"times": []any{}},
+		{"times": []any{"not a time"}},
+		{"times": times, "scheme": "hour", "band_hours": 5},
+		{"times": times, "scheme": "custom"},
+		{"times": times, "language": "xx"},
+	} {
+		if result := call(args); !result.IsError {
+			t.Errorf("Expected an error for %v but got %s", args, toolResultText(result))
+		}
+	}
+}
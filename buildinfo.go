@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Build metadata, injected at build time with
+// -ldflags "-X main.gitCommit=$(git rev-parse HEAD) -X main.buildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)".
+// When unset, the VCS information recorded by the Go toolchain is used.
+var (
+	gitCommit = ""
+	buildDate = ""
+)
+
+// serverInfoToolName is the tool reporting build metadata
+const serverInfoToolName = "get_server_info"
+
+// tzdataVersionFiles are checked in order for the tz database release
+var tzdataVersionFiles = []string{
+	"/usr/share/zoneinfo/tzdata.zi",
+	"/usr/share/zoneinfo/+VERSION",
+	"/usr/share/zoneinfo/version",
+}
+
+// buildInfo describes exactly what is deployed
+type buildInfo struct {
+	Name          string `json:"name"`
+	Version       string `json:"version"`
+	GitCommit     string `json:"git_commit"`
+	BuildDate     string `json:"build_date"`
+	GoVersion     string `json:"go_version"`
+	TZDataVersion string `json:"tzdata_version"`
+	// Datasets are the loaded calendar, term and GeoIP files, which change
+	// on reload_datasets without a new build
+	Datasets map[string]datasetVersion `json:"datasets,omitempty"`
+}
+
+// vcsInfo returns the commit and commit time recorded by the Go toolchain
+var vcsInfo = sync.OnceValues(func() (string, string) {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "", ""
+	}
+	var revision, modified, commitTime string
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+		case "vcs.modified":
+			modified = setting.Value
+		case "vcs.time":
+			commitTime = setting.Value
+		}
+	}
+	if revision != "" && modified == "true" {
+		revision += "-dirty"
+	}
+	return revision, commitTime
+})
+
+// tzdataVersion reports the release of the system tz database, e.g. "2025b",
+// or "unknown" when it cannot be determined (such as with the database
+// embedded by -tags timetzdata)
+var tzdataVersion = sync.OnceValue(func() string {
+	files := tzdataVersionFiles
+	if dir := os.Getenv("ZONEINFO"); dir != "" {
+		files = append([]string{filepath.Join(dir, "tzdata.zi"), filepath.Join(dir, "+VERSION")}, files...)
+	}
+	for _, file := range files {
+		if version := readTZDataVersion(file); version != "" {
+			return version
+		}
+	}
+	return "unknown"
+})
+
+// readTZDataVersion reads "# version 2025b" from tzdata.zi or a bare
+// release from a version file
+func readTZDataVersion(file string) string {
+	f, err := os.Open(file)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() {
+		return ""
+	}
+	line := strings.TrimSpace(scanner.Text())
+	if strings.HasSuffix(file, ".zi") {
+		version, ok := strings.CutPrefix(line, "# version ")
+		if !ok {
+			return ""
+		}
+		return strings.TrimSpace(version)
+	}
+	return line
+}
+
+func currentBuildInfo(config *Config) buildInfo {
+	commit, date := gitCommit, buildDate
+	vcsCommit, vcsTime := vcsInfo()
+	if commit == "" {
+		commit = vcsCommit
+	}
+	if date == "" {
+		date = vcsTime
+	}
+	return buildInfo{
+		Name:          config.ServerName,
+		Version:       config.ServerVersion,
+		GitCommit:     valueOrUnknown(commit),
+		BuildDate:     valueOrUnknown(date),
+		GoVersion:     runtime.Version(),
+		TZDataVersion: tzdataVersion(),
+		Datasets:      config.datasets().Versions,
+	}
+}
+
+func valueOrUnknown(value string) string {
+	if value == "" {
+		return "unknown"
+	}
+	return value
+}
+
+// addVersionEndpoint registers GET /version
+func addVersionEndpoint(mux *http.ServeMux, config *Config) {
+	mux.HandleFunc("/version", func(w http.ResponseWriter, r *http.Request) {
+		writeJSONResponse(w, r, http.StatusOK, currentBuildInfo(config))
+	})
+}
+
+// addServerInfoTool registers the get_server_info tool
+func addServerInfoTool(mcpServer *server.MCPServer, config *Config) {
+	mcpServer.AddTool(
+		mcp.NewTool(serverInfoToolName,
+			mcp.WithDescription("Show the server's version, git commit, build date, Go version and tz database release, plus the versions of the loaded datasets."),
+			mcp.WithOutputSchema[buildInfo](),
+			mcp.WithTitleAnnotation("Get Server Info"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			info := currentBuildInfo(config)
+			data, err := json.MarshalIndent(info, "", "  ")
+			if err != nil {
+				return toolError(errorInternal, fmt.Sprintf("Failed to encode server info: %v", err)), nil
+			}
+			return mcp.NewToolResultStructured(info, string(data)), nil
+		},
+	)
+}
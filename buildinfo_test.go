@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestReadTZDataVersion(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		file     string
+		content  string
+		expected string
+	}{
+		{"tzdata.zi", "# version 2025b\n# This zic input file is in the public domain.\n", "2025b"},
+		{"+VERSION", "2024a\n", "2024a"},
+		{"other.zi", "R d 1916 o - Jun 14 23s 1 S\n", ""},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(dir, tt.file)
+		os.WriteFile(path, []byte(tt.content), 0o644)
+		if got := readTZDataVersion(path); got != tt.expected {
+			t.Errorf("%s: Expected %q but got %q", tt.file, tt.expected, got)
+		}
+	}
+	if got := readTZDataVersion(filepath.Join(dir, "missing")); got != "" {
+		t.Errorf("Expected no version for a missing file but got %q", got)
+	}
+}
+
+func TestVersionEndpoint(t *testing.T) {
+	savedCommit, savedDate := gitCommit, buildDate
+	gitCommit, buildDate = "abc1234", "2025-06-01T12:00:00Z"
+	t.Cleanup(func() { gitCommit, buildDate = savedCommit, savedDate })
+
+	mux := http.NewServeMux()
+	addVersionEndpoint(mux, &Config{ServerName: "TimeMCP", ServerVersion: "1.4.0"})
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest("GET", "/version", nil))
+
+	var info buildInfo
+	if err := json.NewDecoder(rec.Body).Decode(&info); err != nil {
+		t.Fatalf("Failed to decode version: %v", err)
+	}
+	expected := buildInfo{
+		Name: "TimeMCP", Version: "1.4.0", GitCommit: "abc1234", BuildDate: "2025-06-01T12:00:00Z",
+		GoVersion: runtime.Version(), TZDataVersion: tzdataVersion(),
+	}
+	if !reflect.DeepEqual(info, expected) {
+		t.Errorf("Expected %+v but got %+v", expected, info)
+	}
+}
+
+func TestServerInfoTool(t *testing.T) {
+	mcpServer := server.NewMCPServer("TimeMCP", "test", server.WithToolCapabilities(true))
+	addServerInfoTool(mcpServer, &Config{ServerName: "TimeMCP", ServerVersion: "1.4.0"})
+	tool := mcpServer.GetTool(serverInfoToolName)
+	if tool == nil {
+		t.Fatalf("Expected %s to be registered", serverInfoToolName)
+	}
+	result, err := tool.Handler(context.Background(), mcp.CallToolRequest{})
+	if err != nil || result.IsError {
+		t.Fatalf("Failed to call %s: %v", serverInfoToolName, err)
+	}
+	if info, ok := result.StructuredContent.(buildInfo); !ok || info.Version != "1.4.0" {
+		t.Errorf("Expected structured build info but got %v", result.StructuredContent)
+	}
+}
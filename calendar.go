@@ -0,0 +1,411 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"gopkg.in/yaml.v3"
+)
+
+// calendarFile is the schema of TIME_CALENDARS_FILE (YAML or JSON):
+//
+//	calendars:
+//	  acme-pl:
+//	    timezone: Europe/Warsaw
+//	    weekend: [saturday, sunday]
+//	    working_hours: "09:00-17:00"
+//	    holidays:
+//	      - {date: 2025-12-24, name: Office closed}
+//	      - {rule: "12-25", name: Christmas}
+//	      - {rule: "last monday of may", name: Spring break}
+//	      - {rule: "easter+1", name: Easter Monday}
+type calendarFile struct {
+	Calendars map[string]calendarDefinition `yaml:"calendars"`
+}
+
+type calendarDefinition struct {
+	Timezone     string              `yaml:"timezone"`
+	Weekend      []string            `yaml:"weekend"`
+	WorkingHours string              `yaml:"working_hours"`
+	Holidays     []holidayDefinition `yaml:"holidays"`
+}
+
+type holidayDefinition struct {
+	Date string `yaml:"date"`
+	Rule string `yaml:"rule"`
+	Name string `yaml:"name"`
+}
+
+// businessCalendar is a parsed calendar definition
+type businessCalendar struct {
+	Name      string
+	Location  *time.Location
+	Weekend   map[time.Weekday]bool
+	WorkStart time.Duration
+	WorkEnd   time.Duration
+	Holidays  []holiday
+}
+
+// holiday matches dates either once (a fixed date) or every year (a rule)
+type holiday struct {
+	Name    string
+	matches func(year int, month time.Month, day int) bool
+	// Rule is the original date or rule text
+	Rule string
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday,
+	"thursday": time.Thursday, "friday": time.Friday, "saturday": time.Saturday,
+}
+
+var monthNames = map[string]time.Month{
+	"january": time.January, "february": time.February, "march": time.March, "april": time.April,
+	"may": time.May, "june": time.June, "july": time.July, "august": time.August,
+	"september": time.September, "october": time.October, "november": time.November, "december": time.December,
+}
+
+var ordinals = map[string]int{"first": 1, "second": 2, "third": 3, "fourth": 4, "fifth": 5, "last": -1}
+
+// loadCalendars reads and validates a calendar file
+func loadCalendars(path string) (map[string]*businessCalendar, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read calendars file: %w", err)
+	}
+	return parseCalendars(data)
+}
+
+// parseCalendars parses calendar definitions from YAML or JSON
+func parseCalendars(data []byte) (map[string]*businessCalendar, error) {
+	var file calendarFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+
+	calendars := make(map[string]*businessCalendar, len(file.Calendars))
+	for name, def := range file.Calendars {
+		cal, err := def.build(name)
+		if err != nil {
+			return nil, fmt.Errorf("calendar %q: %w", name, err)
+		}
+		calendars[name] = cal
+	}
+	return calendars, nil
+}
+
+func (d calendarDefinition) build(name string) (*businessCalendar, error) {
+	cal := &businessCalendar{
+		Name:      name,
+		Location:  time.UTC,
+		Weekend:   map[time.Weekday]bool{time.Saturday: true, time.Sunday: true},
+		WorkStart: 9 * time.Hour,
+		WorkEnd:   17 * time.Hour,
+	}
+
+	if d.Timezone != "" {
+		loc, err := loadLocationCached(d.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone %q", d.Timezone)
+		}
+		cal.Location = loc
+	}
+
+	if d.Weekend != nil {
+		cal.Weekend = make(map[time.Weekday]bool)
+		for _, day := range d.Weekend {
+			wd, ok := weekdayNames[strings.ToLower(strings.TrimSpace(day))]
+			if !ok {
+				return nil, fmt.Errorf("invalid weekend day %q", day)
+			}
+			cal.Weekend[wd] = true
+		}
+	}
+
+	if d.WorkingHours != "" {
+		start, end, err := parseWorkingHours(d.WorkingHours)
+		if err != nil {
+			return nil, err
+		}
+		cal.WorkStart, cal.WorkEnd = start, end
+	}
+
+	for _, h := range d.Holidays {
+		parsed, err := h.build()
+		if err != nil {
+			return nil, err
+		}
+		cal.Holidays = append(cal.Holidays, parsed)
+	}
+	return cal, nil
+}
+
+// parseWorkingHours parses "HH:MM-HH:MM"
+func parseWorkingHours(str string) (time.Duration, time.Duration, error) {
+	from, to, ok := strings.Cut(str, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid working hours %q (expected HH:MM-HH:MM)", str)
+	}
+	start, err := parseClock(from)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid working hours %q: %w", str, err)
+	}
+	end, err := parseClock(to)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid working hours %q: %w", str, err)
+	}
+	if end <= start {
+		return 0, 0, fmt.Errorf("invalid working hours %q: end must be after start", str)
+	}
+	return start, end, nil
+}
+
+// parseClock parses "HH:MM" into an offset from midnight; "24:00" is allowed
+func parseClock(str string) (time.Duration, error) {
+	str = strings.TrimSpace(str)
+	if str == "24:00" {
+		return 24 * time.Hour, nil
+	}
+	t, err := time.Parse("15:04", str)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q", str)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+func (h holidayDefinition) build() (holiday, error) {
+	switch {
+	case h.Date != "" && h.Rule != "":
+		return holiday{}, fmt.Errorf("holiday %q sets both date and rule", h.Name)
+	case h.Date != "":
+		date, err := time.Parse("2006-01-02", h.Date)
+		if err != nil {
+			return holiday{}, fmt.Errorf("invalid holiday date %q", h.Date)
+		}
+		return holiday{Name: h.Name, Rule: h.Date, matches: func(y int, m time.Month, d int) bool {
+			return y == date.Year() && m == date.Month() && d == date.Day()
+		}}, nil
+	case h.Rule != "":
+		matches, err := parseHolidayRule(h.Rule)
+		if err != nil {
+			return holiday{}, err
+		}
+		return holiday{Name: h.Name, Rule: h.Rule, matches: matches}, nil
+	}
+	return holiday{}, fmt.Errorf("holiday %q needs a date or a rule", h.Name)
+}
+
+// parseHolidayRule parses a recurring rule: "MM-DD", "<ordinal> <weekday> of
+// <month>" (e.g. "last monday of may") or "easter", "easter+N", "easter-N"
+func parseHolidayRule(rule string) (func(int, time.Month, int) bool, error) {
+	r := strings.ToLower(strings.TrimSpace(rule))
+
+	if strings.HasPrefix(r, "easter") {
+		offset := 0
+		if rest := strings.TrimPrefix(r, "easter"); rest != "" {
+			n, err := strconv.Atoi(strings.TrimPrefix(rest, "+"))
+			if err != nil {
+				return nil, fmt.Errorf("invalid holiday rule %q", rule)
+			}
+			offset = n
+		}
+		return func(y int, m time.Month, d int) bool {
+			date := easterSunday(y).AddDate(0, 0, offset)
+			return date.Year() == y && date.Month() == m && date.Day() == d
+		}, nil
+	}
+
+	if fields := strings.Fields(r); len(fields) == 4 && fields[2] == "of" {
+		n, okOrdinal := ordinals[fields[0]]
+		wd, okWeekday := weekdayNames[fields[1]]
+		month, okMonth := monthNames[fields[3]]
+		if !okOrdinal || !okWeekday || !okMonth {
+			return nil, fmt.Errorf("invalid holiday rule %q", rule)
+		}
+		return func(y int, m time.Month, d int) bool {
+			if m != month {
+				return false
+			}
+			day, ok := nthWeekday(y, month, wd, n)
+			return ok && day == d
+		}, nil
+	}
+
+	if t, err := time.Parse("01-02", r); err == nil {
+		return func(_ int, m time.Month, d int) bool {
+			return m == t.Month() && d == t.Day()
+		}, nil
+	}
+	return nil, fmt.Errorf("invalid holiday rule %q", rule)
+}
+
+// nthWeekday returns the day of month of the nth weekday; a negative n counts
+// from the end of the month, so -1 means the last one
+func nthWeekday(year int, month time.Month, wd time.Weekday, n int) (int, bool) {
+	if n < 0 {
+		last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC)
+		day := last.Day() - (int(last.Weekday())-int(wd)+7)%7 + (n+1)*7
+		return day, day >= 1
+	}
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	day := 1 + (int(wd)-int(first.Weekday())+7)%7 + (n-1)*7
+	if day > time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day() {
+		return 0, false
+	}
+	return day, true
+}
+
+// easterSunday computes Western Easter with the anonymous Gregorian algorithm
+func easterSunday(year int) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+// holidayOn returns the holiday falling on the date, if any
+func (c *businessCalendar) holidayOn(date time.Time) (holiday, bool) {
+	y, m, d := date.Date()
+	for _, h := range c.Holidays {
+		if h.matches(y, m, d) {
+			return h, true
+		}
+	}
+	return holiday{}, false
+}
+
+// isBusinessDay reports whether the date is a working day and, if not, why
+func (c *businessCalendar) isBusinessDay(date time.Time) (bool, string) {
+	if c.Weekend[date.Weekday()] {
+		return false, "weekend"
+	}
+	if h, ok := c.holidayOn(date); ok {
+		if h.Name != "" {
+			return false, "holiday: " + h.Name
+		}
+		return false, "holiday"
+	}
+	return true, ""
+}
+
+// isWorkingTime reports whether t falls within working hours on a business day
+func (c *businessCalendar) isWorkingTime(t time.Time) bool {
+	t = t.In(c.Location)
+	if ok, _ := c.isBusinessDay(t); !ok {
+		return false
+	}
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, c.Location)
+	offset := t.Sub(midnight)
+	return offset >= c.WorkStart && offset < c.WorkEnd
+}
+
+// workingTimeBetween returns how much of the time from from to to falls
+// within working hours on business days
+func (c *businessCalendar) workingTimeBetween(from, to time.Time) time.Duration {
+	from, to = from.In(c.Location), to.In(c.Location)
+	var total time.Duration
+	for date := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, c.Location); date.Before(to); date = date.AddDate(0, 0, 1) {
+		if ok, _ := c.isBusinessDay(date); !ok {
+			continue
+		}
+		start, end := clockOn(date, c.WorkStart), clockOn(date, c.WorkEnd)
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if end.After(start) {
+			total += end.Sub(start)
+		}
+	}
+	return total
+}
+
+// calendarByName looks up a configured calendar for tools that reference one by name
+func calendarByName(config *Config, name string) (*businessCalendar, error) {
+	calendars := config.datasets().Calendars
+	if cal, ok := calendars[name]; ok {
+		return cal, nil
+	}
+	names := make([]string, 0, len(calendars))
+	for n := range calendars {
+		names = append(names, n)
+	}
+	slices.Sort(names)
+	return nil, fmt.Errorf("unknown calendar %q (available: %s)", name, strings.Join(names, ", "))
+}
+
+// addCalendarTools registers tools backed by the configured business calendars
+func addCalendarTools(mcpServer *server.MCPServer, config *Config) {
+	mcpServer.AddTool(
+		mcp.NewTool("check_business_day",
+			mcp.WithDescription("Check whether a date is a business day in a configured business calendar, and show its working hours."),
+			mcp.WithString("calendar",
+				mcp.Description("Name of the business calendar. Defaults to the caller's tenant calendar, if any."),
+			),
+			mcp.WithString("date",
+				mcp.Description("Date in YYYY-MM-DD format. Defaults to today in the calendar's timezone."),
+				mcp.DefaultString(""),
+			),
+			mcp.WithTitleAnnotation("Check Business Day"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		handleCheckBusinessDay(config),
+	)
+}
+
+// handleCheckBusinessDay returns a handler for the check_business_day tool
+func handleCheckBusinessDay(config *Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		name := request.GetString("calendar", "")
+		if t := tenantFromContext(ctx); name == "" && t != nil {
+			name = t.Calendar
+		}
+		if name == "" {
+			return toolError(errorInvalidArgument, "required argument \"calendar\" not found"), nil
+		}
+		cal, err := calendarByName(config, name)
+		if err != nil {
+			return toolError(errorNotFound, err.Error()), nil
+		}
+
+		date := config.now().In(cal.Location)
+		if dateStr := request.GetString("date", ""); dateStr != "" {
+			date, err = time.ParseInLocation("2006-01-02", dateStr, cal.Location)
+			if err != nil {
+				return toolError(errorParse, fmt.Sprintf("Invalid date format: %s. Please provide date in YYYY-MM-DD format.", dateStr)), nil
+			}
+		}
+
+		day := date.Format("Monday, 2006-01-02")
+		if ok, reason := cal.isBusinessDay(date); !ok {
+			return mcp.NewToolResultText(fmt.Sprintf("%s is not a business day in calendar %s (%s)", day, cal.Name, reason)), nil
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("%s is a business day in calendar %s; working hours %s-%s %s",
+			day, cal.Name, formatClock(cal.WorkStart), formatClock(cal.WorkEnd), cal.Location)), nil
+	}
+}
+
+// formatClock formats an offset from midnight as HH:MM
+func formatClock(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const testCalendars = `
+calendars:
+  acme:
+    timezone: Europe/Warsaw
+    working_hours: "08:30-16:30"
+    holidays:
+      - {date: 2025-12-24, name: Office closed}
+      - {rule: "12-25", name: Christmas}
+      - {rule: "last monday of may", name: Spring break}
+      - {rule: "first friday of august"}
+      - {rule: "easter+1", name: Easter Monday}
+  gulf:
+    weekend: [friday, saturday]
+`
+
+func TestBusinessCalendar(t *testing.T) {
+	calendars, err := parseCalendars([]byte(testCalendars))
+	if err != nil {
+		t.Fatalf("Failed to parse calendars: %v", err)
+	}
+	acme, gulf := calendars["acme"], calendars["gulf"]
+
+	tests := []struct {
+		cal    *businessCalendar
+		date   string
+		want   bool
+		reason string
+	}{
+		{acme, "2025-12-23", true, ""},
+		{acme, "2025-12-24", false, "holiday: Office closed"},
+		{acme, "2026-12-24", true, ""},
+		{acme, "2026-12-25", false, "holiday: Christmas"},
+		{acme, "2025-05-26", false, "holiday: Spring break"},
+		{acme, "2025-05-19", true, ""},
+		{acme, "2025-08-01", false, "holiday"},
+		{acme, "2025-04-21", false, "holiday: Easter Monday"},
+		{acme, "2024-04-01", false, "holiday: Easter Monday"},
+		{acme, "2025-12-27", false, "weekend"},
+		{gulf, "2025-12-26", false, "weekend"},
+		{gulf, "2025-12-28", true, ""},
+	}
+	for _, tt := range tests {
+		date, _ := time.Parse("2006-01-02", tt.date)
+		got, reason := tt.cal.isBusinessDay(date)
+		if got != tt.want || reason != tt.reason {
+			t.Errorf("Expected %s in %s to be (%v, %q) but got (%v, %q)", tt.date, tt.cal.Name, tt.want, tt.reason, got, reason)
+		}
+	}
+
+	// Working hours are evaluated in the calendar's timezone
+	start := time.Date(2025, 12, 23, 7, 30, 0, 0, time.UTC) // 08:30 in Warsaw
+	if !acme.isWorkingTime(start) {
+		t.Errorf("Expected %v to be within working hours", start)
+	}
+	if acme.isWorkingTime(start.Add(-time.Minute)) {
+		t.Errorf("Expected %v to be outside working hours", start.Add(-time.Minute))
+	}
+	if acme.isWorkingTime(start.Add(8 * time.Hour)) {
+		t.Errorf("Expected the end of working hours to be exclusive")
+	}
+}
+
+func TestParseCalendarsJSON(t *testing.T) {
+	calendars, err := parseCalendars([]byte(`{"calendars": {"ops": {"weekend": ["sunday"], "holidays": [{"rule": "01-01"}]}}}`))
+	if err != nil {
+		t.Fatalf("Failed to parse calendars: %v", err)
+	}
+	ops := calendars["ops"]
+	if ok, _ := ops.isBusinessDay(time.Date(2025, 12, 27, 0, 0, 0, 0, time.UTC)); !ok {
+		t.Errorf("Expected Saturday to be a business day when only Sunday is a weekend day")
+	}
+	if ok, _ := ops.isBusinessDay(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)); ok {
+		t.Errorf("Expected New Year's Day to be a holiday")
+	}
+}
+
+func TestParseCalendarsInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"timezone", "calendars: {a: {timezone: Mars/Base}}", "invalid timezone"},
+		{"weekend", "calendars: {a: {weekend: [caturday]}}", "invalid weekend day"},
+		{"hours", `calendars: {a: {working_hours: "17:00-09:00"}}`, "end must be after start"},
+		{"rule", `calendars: {a: {holidays: [{rule: "sixth monday of may"}]}}`, "invalid holiday rule"},
+		{"date", `calendars: {a: {holidays: [{date: "2025-13-01"}]}}`, "invalid holiday date"},
+		{"both", `calendars: {a: {holidays: [{date: "2025-01-01", rule: "01-01"}]}}`, "both date and rule"},
+		{"empty", `calendars: {a: {holidays: [{name: x}]}}`, "needs a date or a rule"},
+	}
+	for _, tt := range tests {
+		_, err := parseCalendars([]byte(tt.data))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: Expected error containing %q but got %v", tt.name, tt.want, err)
+		}
+	}
+}
+
+func TestNthWeekday(t *testing.T) {
+	tests := []struct {
+		month time.Month
+		wd    time.Weekday
+		n     int
+		want  int
+		ok    bool
+	}{
+		{time.November, time.Thursday, 4, 27, true}, // US Thanksgiving 2025
+		{time.May, time.Monday, -1, 26, true},
+		{time.September, time.Monday, 1, 1, true},
+		{time.February, time.Monday, 5, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := nthWeekday(2025, tt.month, tt.wd, tt.n)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Expected nthWeekday(%v, %v, %d) = (%d, %v) but got (%d, %v)", tt.month, tt.wd, tt.n, tt.want, tt.ok, got, ok)
+		}
+	}
+}
+
+func TestWorkingTimeBetween(t *testing.T) {
+	calendars, err := parseCalendars([]byte(testCalendars))
+	if err != nil {
+		t.Fatalf("Failed to parse calendars: %v", err)
+	}
+	acme := calendars["acme"]
+	warsaw := acme.Location
+
+	tests := []struct {
+		from, to time.Time
+		want     time.Duration
+	}{
+		// Within one working day
+		{time.Date(2025, 12, 22, 10, 0, 0, 0, warsaw), time.Date(2025, 12, 22, 12, 0, 0, 0, warsaw), 2 * time.Hour},
+		// Evening to the next morning counts only working hours
+		{time.Date(2025, 12, 22, 15, 30, 0, 0, warsaw), time.Date(2025, 12, 23, 9, 30, 0, 0, warsaw), 2 * time.Hour},
+		// Tuesday evening to Monday morning skips the holidays and the weekend
+		{time.Date(2025, 12, 23, 16, 0, 0, 0, warsaw), time.Date(2025, 12, 29, 9, 0, 0, 0, warsaw), 30*time.Minute + 8*time.Hour + 30*time.Minute},
+		{time.Date(2025, 12, 27, 10, 0, 0, 0, warsaw), time.Date(2025, 12, 28, 10, 0, 0, 0, warsaw), 0},
+		{time.Date(2025, 12, 22, 12, 0, 0, 0, warsaw), time.Date(2025, 12, 22, 10, 0, 0, 0, warsaw), 0},
+	}
+	for _, tt := range tests {
+		if got := acme.workingTimeBetween(tt.from, tt.to); got != tt.want {
+			t.Errorf("Expected %v working time from %v to %v but got %v", tt.want, tt.from, tt.to, got)
+		}
+	}
+}
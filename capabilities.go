@@ -0,0 +1,117 @@
+package main
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// capabilitiesResponse is the body of GET /capabilities
+type capabilitiesResponse struct {
+	Server     map[string]string `json:"server"`
+	Tools      []mcp.Tool        `json:"tools"`
+	Deprecated map[string]string `json:"deprecated,omitempty"`
+}
+
+// authenticateEndpoint enforces credentials on an HTTP endpoint when auth is
+// enabled, resolving the caller as the MCP context function does: valid
+// credentials first, then a verified client certificate when no credentials
+// were presented. It returns nil claims when auth is disabled and false after
+// writing an error response.
+func authenticateEndpoint(w http.ResponseWriter, r *http.Request, config *Config, rt *httpRuntime) (*Claims, bool) {
+	if !config.AuthEnabled {
+		return nil, true
+	}
+	auth := rt.contextFunc.auth()
+	if auth == nil {
+		http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+		return nil, false
+	}
+	claims, err := auth.authenticate(r)
+	if cert, ok := clientCertIdentity(r.TLS); ok && errors.Is(err, errMissingCredentials) {
+		return clientCertClaims(cert), true
+	}
+	if err != nil {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return nil, false
+	}
+	return claims, true
+}
+
+// authenticateAdminEndpoint is authenticateEndpoint for endpoints restricted
+// to TIME_AUTH_ADMIN_ROLE
+func authenticateAdminEndpoint(w http.ResponseWriter, r *http.Request, config *Config, rt *httpRuntime) (*Claims, bool) {
+	claims, ok := authenticateEndpoint(w, r, config, rt)
+	if ok && claims != nil && claims.Role != config.AuthAdminRole {
+		slog.WarnContext(r.Context(), "Admin endpoint denied", "path", r.URL.Path, "user_id", claims.UserID, "role", claims.Role)
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return nil, false
+	}
+	return claims, ok
+}
+
+// capabilities lists the tools registered on the server, as sent in
+// tools/list, so the endpoint cannot drift from what clients see. With auth
+// enabled only tools the caller's role and tenant may call are included, and
+// nil claims stand for an anonymous caller limited to the anonymous tools.
+func capabilities(rt *httpRuntime, config *Config, claims *Claims) capabilitiesResponse {
+	resp := capabilitiesResponse{
+		Server: map[string]string{"name": config.ServerName, "version": config.ServerVersion},
+		Tools:  []mcp.Tool{},
+	}
+	t := config.Tenants.resolve(claims)
+	if t != nil && t.ServerName != "" {
+		resp.Server["name"] = t.ServerName
+	}
+	authError, role := authErrorMissingToken, ""
+	if claims != nil {
+		authError, role = "", claims.Role
+	}
+	for name, serverTool := range rt.mcpServer.ListTools() {
+		if !checkToolAccess(config, name, authError, role, t).permitted() {
+			continue
+		}
+		if d, deprecated := deprecatedTools[name]; deprecated {
+			if config.HideDeprecatedTools {
+				continue
+			}
+			if resp.Deprecated == nil {
+				resp.Deprecated = make(map[string]string)
+			}
+			resp.Deprecated[name] = d.Replacement
+		}
+		resp.Tools = append(resp.Tools, serverTool.Tool)
+	}
+	slices.SortFunc(resp.Tools, func(a, b mcp.Tool) int { return strings.Compare(a.Name, b.Name) })
+	return resp
+}
+
+// addCapabilitiesEndpoint registers GET /capabilities
+func addCapabilitiesEndpoint(mux *http.ServeMux, config *Config, rt *httpRuntime) {
+	if rt == nil || rt.mcpServer == nil {
+		return
+	}
+	mux.HandleFunc("/capabilities", func(w http.ResponseWriter, r *http.Request) {
+		// Callers without credentials see the anonymous tier when there is one
+		var claims *Claims
+		_, hasCert := clientCertIdentity(r.TLS)
+		if !config.AuthEnabled || len(config.AuthAnonymousTools) == 0 || presentedAPIKey(r) != "" || hasCert {
+			var ok bool
+			if claims, ok = authenticateEndpoint(w, r, config, rt); !ok {
+				return
+			}
+		}
+		if config.HTTPCORSEnabled {
+			if origin := r.Header.Get("Origin"); origin != "" && isOriginAllowed(origin, config.HTTPCORSOrigins) {
+				setCORSHeaders(w, r, origin, config.HTTPCORSPolicy, false)
+			}
+		}
+		slog.DebugContext(r.Context(), "Capabilities requested")
+		writeJSONResponse(w, r, http.StatusOK, capabilities(rt, config, claims))
+	})
+}
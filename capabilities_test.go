@@ -0,0 +1,180 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// decodedCapabilities mirrors the JSON shape clients see
+type decodedCapabilities struct {
+	Tools []struct {
+		Name         string          `json:"name"`
+		InputSchema  json.RawMessage `json:"inputSchema"`
+		OutputSchema json.RawMessage `json:"outputSchema"`
+	} `json:"tools"`
+	Deprecated map[string]string `json:"deprecated"`
+}
+
+func getCapabilities(t *testing.T, mux *http.ServeMux, token string) (int, decodedCapabilities) {
+	t.Helper()
+	req := httptest.NewRequest("GET", "/capabilities", nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	var body decodedCapabilities
+	if rec.Code == http.StatusOK {
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode capabilities: %v", err)
+		}
+	}
+	return rec.Code, body
+}
+
+func TestCapabilitiesEndpoint_MatchesRegisteredTools(t *testing.T) {
+	config := &Config{ServerName: "TimeMCP", ServerVersion: "test"}
+	mcpServer := server.NewMCPServer("TimeMCP", "test", server.WithToolCapabilities(true))
+	addTools(mcpServer, config)
+	mux := http.NewServeMux()
+	addCapabilitiesEndpoint(mux, config, &httpRuntime{mcpServer: mcpServer})
+
+	status, body := getCapabilities(t, mux, "")
+	if status != http.StatusOK {
+		t.Fatalf("Expected status 200 but got %d", status)
+	}
+	if len(body.Tools) != len(mcpServer.ListTools()) {
+		t.Errorf("Expected %d tools but got %d", len(mcpServer.ListTools()), len(body.Tools))
+	}
+	for i, tool := range body.Tools {
+		if i > 0 && body.Tools[i-1].Name > tool.Name {
+			t.Errorf("Expected tools sorted by name but %s follows %s", tool.Name, body.Tools[i-1].Name)
+		}
+		if len(tool.InputSchema) == 0 {
+			t.Errorf("Expected an input schema for %s", tool.Name)
+		}
+		if tool.Name == "convert_time_v2" && len(tool.OutputSchema) == 0 {
+			t.Errorf("Expected an output schema for convert_time_v2")
+		}
+	}
+	if body.Deprecated["convert_time"] != "convert_time_v2" {
+		t.Errorf("Expected convert_time listed as deprecated but got %v", body.Deprecated)
+	}
+
+	config.HideDeprecatedTools = true
+	if _, body := getCapabilities(t, mux, ""); len(body.Deprecated) != 0 || len(body.Tools) != len(mcpServer.ListTools())-1 {
+		t.Errorf("Expected deprecated tools to be hidden but got %d tools, deprecated %v", len(body.Tools), body.Deprecated)
+	}
+}
+
+func TestCapabilitiesEndpoint_FiltersByRole(t *testing.T) {
+	config := &Config{
+		AuthEnabled:   true,
+		AuthSecretKey: "test-secret-key-that-is-long-enough-for-hs256",
+		AuthIssuer:    "test-issuer",
+		AuthAudience:  "test-audience",
+		AuthRoles:     rolePolicy{"viewer": {"get_current_time": {}}},
+	}
+	contextFunc := &reloadableContextFunc{}
+	fn, auth, err := createHTTPMiddleware(config)
+	if err != nil {
+		t.Fatalf("Failed to create HTTP middleware: %v", err)
+	}
+	contextFunc.store(fn, auth)
+	mcpServer := server.NewMCPServer("TimeMCP", "test", server.WithToolCapabilities(true))
+	addTools(mcpServer, config)
+	mux := http.NewServeMux()
+	addCapabilitiesEndpoint(mux, config, &httpRuntime{mcpServer: mcpServer, contextFunc: contextFunc})
+
+	if status, _ := getCapabilities(t, mux, ""); status != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 without credentials but got %d", status)
+	}
+	token, _ := auth.GenerateToken("1", "vera", "viewer", 1)
+	status, body := getCapabilities(t, mux, token)
+	if status != http.StatusOK || len(body.Tools) != 1 || body.Tools[0].Name != "get_current_time" {
+		t.Errorf("Expected only get_current_time for the viewer role but got %d %v", status, body.Tools)
+	}
+}
+
+func TestCapabilitiesEndpoint_AnonymousTier(t *testing.T) {
+	config := &Config{
+		AuthEnabled:        true,
+		AuthSecretKey:      "test-secret-key-that-is-long-enough-for-hs256",
+		AuthIssuer:         "test-issuer",
+		AuthAudience:       "test-audience",
+		AuthAnonymousTools: []string{"get_current_time"},
+	}
+	contextFunc := &reloadableContextFunc{}
+	fn, auth, err := createHTTPMiddleware(config)
+	if err != nil {
+		t.Fatalf("Failed to create HTTP middleware: %v", err)
+	}
+	contextFunc.store(fn, auth)
+	mcpServer := server.NewMCPServer("TimeMCP", "test", server.WithToolCapabilities(true))
+	addTools(mcpServer, config)
+	mux := http.NewServeMux()
+	addCapabilitiesEndpoint(mux, config, &httpRuntime{mcpServer: mcpServer, contextFunc: contextFunc})
+
+	status, body := getCapabilities(t, mux, "")
+	if status != http.StatusOK || len(body.Tools) != 1 || body.Tools[0].Name != "get_current_time" {
+		t.Errorf("Expected only the anonymous tools without credentials but got %d %v", status, body.Tools)
+	}
+	if status, _ := getCapabilities(t, mux, "not-a-token"); status != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 for an invalid token but got %d", status)
+	}
+	token, _ := auth.GenerateToken("1", "vera", "viewer", 1)
+	if status, body := getCapabilities(t, mux, token); status != http.StatusOK || len(body.Tools) <= 1 {
+		t.Errorf("Expected every tool for an authenticated caller but got %d with %d tools", status, len(body.Tools))
+	}
+}
+
+func TestCapabilitiesEndpoint_ClientCertificate(t *testing.T) {
+	config := &Config{
+		AuthEnabled:        true,
+		AuthSecretKey:      "test-secret-key-that-is-long-enough-for-hs256",
+		AuthIssuer:         "test-issuer",
+		AuthAudience:       "test-audience",
+		AuthAnonymousTools: []string{"convert_time"},
+		AuthRoles:          rolePolicy{"viewer": {"get_current_time": {}}},
+	}
+	contextFunc := &reloadableContextFunc{}
+	fn, auth, err := createHTTPMiddleware(config)
+	if err != nil {
+		t.Fatalf("Failed to create HTTP middleware: %v", err)
+	}
+	contextFunc.store(fn, auth)
+	mcpServer := server.NewMCPServer("TimeMCP", "test", server.WithToolCapabilities(true))
+	addTools(mcpServer, config)
+	mux := http.NewServeMux()
+	addCapabilitiesEndpoint(mux, config, &httpRuntime{mcpServer: mcpServer, contextFunc: contextFunc})
+
+	// A verified client certificate is the caller's identity, as for tool calls
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "agent-1", OrganizationalUnit: []string{"viewer"}}}
+	req := httptest.NewRequest("GET", "/capabilities", nil)
+	req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	var body decodedCapabilities
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("Failed to decode capabilities: %v", err)
+	}
+	if rec.Code != http.StatusOK || len(body.Tools) != 1 || body.Tools[0].Name != "get_current_time" {
+		t.Errorf("Expected the viewer role's tools for the certificate but got %d %v", rec.Code, body.Tools)
+	}
+
+	// The listing agrees with what the certificate's calls may do
+	ctx := contextFunc.contextFunc(req.Context(), req)
+	for name := range mcpServer.ListTools() {
+		listed := name == "get_current_time"
+		if allowed := checkAuth(ctx, name, config) == nil; allowed != listed {
+			t.Errorf("Expected %s listed (%v) to match checkAuth (%v)", name, listed, allowed)
+		}
+	}
+}
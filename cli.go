@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const cliUsage = `Usage:
+  timemcp now [--tz ZONE] [--json]
+  timemcp convert [TIME] --to ZONE [--from ZONE] [--json]
+  timemcp tools list [--json]
+  timemcp tools call NAME [key=value ...] [--args JSON] [--json]`
+
+// ToolCommand runs a tool from the command line with the same handlers the
+// MCP server registers, printing its text result, or its structured result
+// as JSON with --json. Tool errors are returned so the process exits non-zero.
+func ToolCommand(config *Config, args []string, stdout io.Writer) error {
+	mcpServer := server.NewMCPServer(config.ServerName, config.ServerVersion, server.WithToolCapabilities(true))
+	if config.ToolPluginsDir != "" {
+		if err := loadToolPlugins(config.ToolPluginsDir); err != nil {
+			return fmt.Errorf("configuration error: %w", err)
+		}
+	}
+	addTools(mcpServer, config)
+	if err := addRegisteredTools(mcpServer, config); err != nil {
+		return fmt.Errorf("configuration error: %w", err)
+	}
+
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	jsonOutput := fs.Bool("json", false, "Print the result as JSON")
+	arguments := map[string]any{}
+
+	switch args[0] {
+	case "now":
+		tz := fs.String("tz", "", "Timezone (default: TIME_DEFAULT_TIMEZONE or the system timezone)")
+		if _, err := parseCLIArgs(fs, args[1:], 0); err != nil {
+			return err
+		}
+		arguments["timezone"] = *tz
+		return callCLITool(mcpServer, "get_current_time", arguments, *jsonOutput, stdout)
+
+	case "convert":
+		from := fs.String("from", "", "Source timezone (default: TIME_DEFAULT_TIMEZONE or the system timezone)")
+		to := fs.String("to", "", "Target timezone")
+		positional, err := parseCLIArgs(fs, args[1:], 1)
+		if err != nil {
+			return err
+		}
+		if *to == "" {
+			return fmt.Errorf("convert requires --to\n%s", cliUsage)
+		}
+		arguments["source_timezone"], arguments["target_timezone"] = *from, *to
+		if len(positional) == 1 {
+			arguments["time"] = positional[0]
+		}
+		// convert_time reads better as text; convert_time_v2 has the structured result
+		name := "convert_time"
+		if *jsonOutput {
+			name = "convert_time_v2"
+		}
+		return callCLITool(mcpServer, name, arguments, *jsonOutput, stdout)
+
+	case "tools":
+		rawArgs := fs.String("args", "", "Tool arguments as a JSON object")
+		positional, err := parseCLIArgs(fs, args[1:], -1)
+		if err != nil {
+			return err
+		}
+		switch {
+		case len(positional) == 1 && positional[0] == "list":
+			return listCLITools(mcpServer, *jsonOutput, stdout)
+		case len(positional) >= 2 && positional[0] == "call":
+			tool := mcpServer.GetTool(positional[1])
+			if tool == nil {
+				return fmt.Errorf("unknown tool %q; see 'timemcp tools list'", positional[1])
+			}
+			if *rawArgs != "" {
+				if err := json.Unmarshal([]byte(*rawArgs), &arguments); err != nil {
+					return fmt.Errorf("invalid --args: %w", err)
+				}
+			}
+			for _, pair := range positional[2:] {
+				key, value, err := parseCLIArgument(tool.Tool, pair)
+				if err != nil {
+					return err
+				}
+				arguments[key] = value
+			}
+			return callCLITool(mcpServer, positional[1], arguments, *jsonOutput, stdout)
+		}
+	}
+	return fmt.Errorf("unknown command %q\n%s", strings.Join(args, " "), cliUsage)
+}
+
+// parseCLIArgs parses flags wherever they appear among the arguments, so
+// "convert 14:00 --to Asia/Tokyo" works, and returns the positional ones;
+// maxPositional of -1 allows any number
+func parseCLIArgs(fs *flag.FlagSet, args []string, maxPositional int) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, fmt.Errorf("%s: %w\n%s", fs.Name(), err, cliUsage)
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if maxPositional >= 0 && len(positional) > maxPositional {
+		return nil, fmt.Errorf("%s: unexpected arguments %v\n%s", fs.Name(), positional[maxPositional:], cliUsage)
+	}
+	return positional, nil
+}
+
+// parseCLIArgument parses a key=value tool argument. Values of string
+// properties are taken as they are; others are parsed as JSON when they can be.
+func parseCLIArgument(tool mcp.Tool, pair string) (string, any, error) {
+	key, raw, ok := strings.Cut(pair, "=")
+	if !ok || key == "" {
+		return "", nil, fmt.Errorf("expected key=value, got %q", pair)
+	}
+	if property, ok := tool.InputSchema.Properties[key].(map[string]any); ok && property["type"] == "string" {
+		return key, raw, nil
+	}
+	var value any
+	if err := json.Unmarshal([]byte(raw), &value); err != nil {
+		value = raw
+	}
+	return key, value, nil
+}
+
+// callCLITool runs a tool handler and prints its result
+func callCLITool(mcpServer *server.MCPServer, name string, arguments map[string]any, jsonOutput bool, stdout io.Writer) error {
+	tool := mcpServer.GetTool(name)
+	if tool == nil {
+		return fmt.Errorf("tool %q is not available with this configuration", name)
+	}
+	request := mcp.CallToolRequest{}
+	request.Params.Name = name
+	request.Params.Arguments = arguments
+	result, err := tool.Handler(context.Background(), request)
+	if err != nil {
+		return err
+	}
+	if result.IsError {
+		return fmt.Errorf("%s", toolResultText(result))
+	}
+
+	if !jsonOutput {
+		_, err := fmt.Fprintln(stdout, toolResultText(result))
+		return err
+	}
+	var output any = map[string]string{"result": toolResultText(result)}
+	if result.StructuredContent != nil {
+		output = result.StructuredContent
+	}
+	enc := json.NewEncoder(stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(output)
+}
+
+// listCLITools prints the available tools, by name
+func listCLITools(mcpServer *server.MCPServer, jsonOutput bool, stdout io.Writer) error {
+	tools := mcpServer.ListTools()
+	names := make([]string, 0, len(tools))
+	for name := range tools {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	if jsonOutput {
+		list := make([]mcp.Tool, 0, len(names))
+		for _, name := range names {
+			list = append(list, tools[name].Tool)
+		}
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(list)
+	}
+	for _, name := range names {
+		if _, err := fmt.Fprintf(stdout, "%-24s %s\n", name, tools[name].Tool.Description); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestToolCommand(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"now", "--tz", "Asia/Tokyo"}, "Current time in Asia/Tokyo (JST, UTC+09:00)"},
+		{[]string{"convert", "14:00", "--from", "UTC", "--to", "Asia/Tokyo"}, "in UTC → "},
+		{[]string{"tools", "list"}, "describe_cron"},
+		{[]string{"tools", "call", "describe_cron", "expression=0 9 * * MON-FRI"}, "At 09:00 on weekdays"},
+		{[]string{"tools", "call", "export_vtimezone", "--args", `{"timezone": "UTC"}`}, "TZID:UTC"},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		if err := ToolCommand(&Config{}, tt.args, &out); err != nil {
+			t.Fatalf("Failed to run %v: %v", tt.args, err)
+		}
+		if !strings.Contains(out.String(), tt.expected) {
+			t.Errorf("%v: Expected output containing %q but got %q", tt.args, tt.expected, out.String())
+		}
+	}
+}
+
+func TestToolCommand_JSON(t *testing.T) {
+	var out bytes.Buffer
+	if err := ToolCommand(&Config{}, []string{"convert", "--json", "--to", "Asia/Tokyo", "--from", "UTC", "14:00"}, &out); err != nil {
+		t.Fatalf("Failed to run convert: %v", err)
+	}
+	var conversion timeConversion
+	if err := json.Unmarshal(out.Bytes(), &conversion); err != nil {
+		t.Fatalf("Failed to decode output %q: %v", out.String(), err)
+	}
+	if !strings.HasSuffix(conversion.Target.Datetime, "T23:00:00+09:00") || conversion.TimeDifference != "+9h" {
+		t.Errorf("Expected 23:00+09:00 and +9h but got %+v", conversion)
+	}
+}
+
+func TestToolCommand_Errors(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"now", "--tz", "Invalid/Zone"}, "Invalid timezone"},
+		{[]string{"convert", "14:00"}, "requires --to"},
+		{[]string{"now", "extra"}, "unexpected arguments"},
+		{[]string{"tools", "call", "missing_tool"}, "unknown tool"},
+		{[]string{"tools", "call", "describe_cron", "expression"}, "expected key=value"},
+		{[]string{"tools"}, "unknown command"},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		if err := ToolCommand(&Config{}, tt.args, &out); err == nil || !strings.Contains(err.Error(), tt.expected) {
+			t.Errorf("%v: Expected an error containing %q but got %v", tt.args, tt.expected, err)
+		}
+	}
+}
@@ -0,0 +1,315 @@
+// Package client calls a TimeMCP server from Go over stdio or Streamable
+// HTTP, with typed methods for the built-in tools:
+//
+//	c, err := client.NewHTTP(ctx, "http://localhost:8080/mcp", client.WithToken(token))
+//	if err != nil {
+//		return err
+//	}
+//	defer c.Close()
+//	conversion, err := c.ConvertTime(ctx, client.ConvertRequest{Time: "14:00", SourceTimezone: "UTC", TargetTimezone: "Asia/Tokyo"})
+//
+// Tools without a typed method can be called with CallTool. A tool that
+// reports an error, such as an invalid timezone or a denied call, returns a
+// *ToolError.
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	mcpclient "github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Client is a connected, initialized TimeMCP session. It is safe for
+// concurrent use.
+type Client struct {
+	mcp    *mcpclient.Client
+	server mcp.Implementation
+}
+
+// options collects Option values
+type options struct {
+	token       string
+	apiKey      string
+	headers     map[string]string
+	env         []string
+	name        string
+	version     string
+	timezone    string
+	locale      string
+	httpOptions []transport.StreamableHTTPCOption
+}
+
+// Option configures a Client
+type Option func(*options)
+
+// WithToken authenticates with a JWT or an API key as a Bearer token. Over
+// stdio it is passed to the server as TIME_STDIO_AUTH_TOKEN.
+func WithToken(token string) Option {
+	return func(o *options) { o.token = token }
+}
+
+// WithAPIKey authenticates over HTTP with an X-API-Key header
+func WithAPIKey(key string) Option {
+	return func(o *options) { o.apiKey = key }
+}
+
+// WithHeaders adds headers to every HTTP request, e.g. X-Timezone
+func WithHeaders(headers map[string]string) Option {
+	return func(o *options) {
+		if o.headers == nil {
+			o.headers = make(map[string]string)
+		}
+		for k, v := range headers {
+			o.headers[k] = v
+		}
+	}
+}
+
+// WithEnv adds KEY=value entries to the environment of a stdio server,
+// which otherwise inherits this process's environment
+func WithEnv(env ...string) Option {
+	return func(o *options) { o.env = append(o.env, env...) }
+}
+
+// WithClientInfo sets the name and version sent in initialize
+func WithClientInfo(name, version string) Option {
+	return func(o *options) { o.name, o.version = name, version }
+}
+
+// WithTimezone declares a session default timezone, used by every tool call
+// that omits its timezone argument
+func WithTimezone(timezone string) Option {
+	return func(o *options) { o.timezone = timezone }
+}
+
+// WithLocale declares a session locale such as "pl-PL", used for the
+// language of describe_cron when it is omitted
+func WithLocale(locale string) Option {
+	return func(o *options) { o.locale = locale }
+}
+
+// WithHTTPOptions passes options to the underlying mcp-go HTTP transport,
+// such as a custom http.Client or timeout
+func WithHTTPOptions(opts ...transport.StreamableHTTPCOption) Option {
+	return func(o *options) { o.httpOptions = append(o.httpOptions, opts...) }
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{name: "timemcp-go-client", version: "1.0.0"}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// NewHTTP connects to a server's Streamable HTTP endpoint, e.g.
+// http://localhost:8080/mcp
+func NewHTTP(ctx context.Context, url string, opts ...Option) (*Client, error) {
+	o := newOptions(opts)
+	headers := make(map[string]string, len(o.headers)+2)
+	for k, v := range o.headers {
+		headers[k] = v
+	}
+	if o.token != "" {
+		headers["Authorization"] = "Bearer " + o.token
+	}
+	if o.apiKey != "" {
+		headers["X-API-Key"] = o.apiKey
+	}
+	t, err := transport.NewStreamableHTTP(url, append([]transport.StreamableHTTPCOption{transport.WithHTTPHeaders(headers)}, o.httpOptions...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP transport: %w", err)
+	}
+	return connect(ctx, t, o)
+}
+
+// NewStdio starts a server process, e.g. NewStdio(ctx, "TimeMCP", nil), and
+// talks to it over stdin and stdout. Close stops the process.
+func NewStdio(ctx context.Context, command string, args []string, opts ...Option) (*Client, error) {
+	o := newOptions(opts)
+	env := append(os.Environ(), o.env...)
+	if o.token != "" {
+		env = append(env, "TIME_STDIO_AUTH_TOKEN="+o.token)
+	}
+	return connect(ctx, transport.NewStdio(command, env, args...), o)
+}
+
+// New initializes a session over an existing transport, such as
+// transport.NewIO for a server connected through pipes
+func New(ctx context.Context, t transport.Interface, opts ...Option) (*Client, error) {
+	return connect(ctx, t, newOptions(opts))
+}
+
+func connect(ctx context.Context, t transport.Interface, o *options) (*Client, error) {
+	c := mcpclient.NewClient(t)
+	if err := c.Start(ctx); err != nil {
+		return nil, fmt.Errorf("failed to start transport: %w", err)
+	}
+	init := mcp.InitializeRequest{}
+	init.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	init.Params.ClientInfo = mcp.Implementation{Name: o.name, Version: o.version}
+	if o.timezone != "" || o.locale != "" {
+		hints := map[string]any{}
+		if o.timezone != "" {
+			hints["timezone"] = o.timezone
+		}
+		if o.locale != "" {
+			hints["locale"] = o.locale
+		}
+		init.Params.Capabilities.Experimental = map[string]any{"timemcp": hints}
+	}
+	result, err := c.Initialize(ctx, init)
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to initialize: %w", err)
+	}
+	return &Client{mcp: c, server: result.ServerInfo}, nil
+}
+
+// Close ends the session and, for stdio, stops the server process
+func (c *Client) Close() error {
+	return c.mcp.Close()
+}
+
+// Server returns the name and version the server reported in initialize
+func (c *Client) Server() mcp.Implementation {
+	return c.server
+}
+
+// ToolError is a tool result flagged as an error. Code classifies it, e.g.
+// "INVALID_TIMEZONE" or "FORBIDDEN"; it is empty for tools that do not set
+// one, such as plugins.
+type ToolError struct {
+	Tool    string
+	Code    string
+	Message string
+}
+
+func (e *ToolError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("%s: %s", e.Tool, e.Message)
+	}
+	return fmt.Sprintf("%s: %s (%s)", e.Tool, e.Message, e.Code)
+}
+
+// Error codes the server sets on ToolError
+const (
+	CodeInvalidArgument = "INVALID_ARGUMENT"
+	CodeInvalidTimezone = "INVALID_TIMEZONE"
+	CodeAmbiguousTime   = "AMBIGUOUS_TIME"
+	CodeParseError      = "PARSE_ERROR"
+	CodeNotFound        = "NOT_FOUND"
+	CodeSessionRequired = "SESSION_REQUIRED"
+	CodeLimitExceeded   = "LIMIT_EXCEEDED"
+	CodeUnauthorized    = "UNAUTHORIZED"
+	CodeForbidden       = "FORBIDDEN"
+	CodeRateLimited     = "RATE_LIMITED"
+	CodeTimeout         = "TIMEOUT"
+	CodeToolFailed      = "TOOL_FAILED"
+	CodeInternal        = "INTERNAL"
+)
+
+// ErrorCode returns the code of a *ToolError in err's chain, or ""
+func ErrorCode(err error) string {
+	var toolErr *ToolError
+	if errors.As(err, &toolErr) {
+		return toolErr.Code
+	}
+	return ""
+}
+
+// IsForbidden reports whether err is a tool call denied by the server's
+// authentication, role or tenant policy
+func IsForbidden(err error) bool {
+	code := ErrorCode(err)
+	return code == CodeUnauthorized || code == CodeForbidden
+}
+
+// ListTools returns the tools the server offers
+func (c *Client) ListTools(ctx context.Context) ([]mcp.Tool, error) {
+	result, err := c.mcp.ListTools(ctx, mcp.ListToolsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return result.Tools, nil
+}
+
+// CallTool calls any tool; arguments with empty values may be omitted
+func (c *Client) CallTool(ctx context.Context, name string, args map[string]any) (*mcp.CallToolResult, error) {
+	req := mcp.CallToolRequest{}
+	req.Params.Name = name
+	req.Params.Arguments = args
+	result, err := c.mcp.CallTool(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if result.IsError {
+		toolErr := &ToolError{Tool: name, Message: text(result)}
+		// Error results carry {"error": {"code": ..., "message": ...}}
+		var detail struct {
+			Error struct {
+				Code string `json:"code"`
+			} `json:"error"`
+		}
+		if data, err := json.Marshal(result.StructuredContent); err == nil && json.Unmarshal(data, &detail) == nil {
+			toolErr.Code = detail.Error.Code
+		}
+		return nil, toolErr
+	}
+	return result, nil
+}
+
+// callText calls a tool with a text result
+func (c *Client) callText(ctx context.Context, name string, args map[string]any) (string, error) {
+	result, err := c.CallTool(ctx, name, args)
+	if err != nil {
+		return "", err
+	}
+	return text(result), nil
+}
+
+// callStructured calls a tool with structured output and decodes it into out
+func (c *Client) callStructured(ctx context.Context, name string, args map[string]any, out any) error {
+	result, err := c.CallTool(ctx, name, args)
+	if err != nil {
+		return err
+	}
+	data := []byte(text(result))
+	if result.StructuredContent != nil {
+		if data, err = json.Marshal(result.StructuredContent); err != nil {
+			return err
+		}
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("%s: invalid result: %w", name, err)
+	}
+	return nil
+}
+
+// text joins the text content of a result
+func text(result *mcp.CallToolResult) string {
+	var parts []string
+	for _, content := range result.Content {
+		if t, ok := mcp.AsTextContent(content); ok {
+			parts = append(parts, t.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// omitEmpty drops empty string arguments so the server applies its defaults
+func omitEmpty(args map[string]any) map[string]any {
+	for k, v := range args {
+		if s, ok := v.(string); ok && s == "" {
+			delete(args, k)
+		}
+	}
+	return args
+}
@@ -0,0 +1,78 @@
+package client
+
+import "context"
+
+// ConvertedTime is one side of a conversion
+type ConvertedTime struct {
+	Timezone     string `json:"timezone"`
+	Datetime     string `json:"datetime"`
+	Abbreviation string `json:"abbreviation"`
+	UTCOffset    string `json:"utc_offset"`
+	IsDST        bool   `json:"is_dst"`
+}
+
+// Conversion is the result of ConvertTime
+type Conversion struct {
+	Source         ConvertedTime `json:"source"`
+	Target         ConvertedTime `json:"target"`
+	TimeDifference string        `json:"time_difference"`
+}
+
+// ConvertRequest are the arguments of ConvertTime. Empty fields use the
+// server's defaults: the current time and the default timezone.
+type ConvertRequest struct {
+	Time           string // HH:MM
+	SourceTimezone string
+	TargetTimezone string
+}
+
+// ServerInfo is the result of GetServerInfo
+type ServerInfo struct {
+	Name          string `json:"name"`
+	Version       string `json:"version"`
+	GitCommit     string `json:"git_commit"`
+	BuildDate     string `json:"build_date"`
+	GoVersion     string `json:"go_version"`
+	TZDataVersion string `json:"tzdata_version"`
+}
+
+// GetCurrentTime returns the current time in timezone, e.g. "Current time in
+// Asia/Tokyo (JST, UTC+09:00): 2025-06-02 18:04:05 Monday"; an empty
+// timezone uses the server's default
+func (c *Client) GetCurrentTime(ctx context.Context, timezone string) (string, error) {
+	return c.callText(ctx, "get_current_time", omitEmpty(map[string]any{"timezone": timezone}))
+}
+
+// ConvertTime converts a time between timezones with convert_time_v2
+func (c *Client) ConvertTime(ctx context.Context, req ConvertRequest) (*Conversion, error) {
+	var conversion Conversion
+	err := c.callStructured(ctx, "convert_time_v2", omitEmpty(map[string]any{
+		"time":            req.Time,
+		"source_timezone": req.SourceTimezone,
+		"target_timezone": req.TargetTimezone,
+	}), &conversion)
+	if err != nil {
+		return nil, err
+	}
+	return &conversion, nil
+}
+
+// DescribeCron describes a cron expression in language ("en" when empty)
+func (c *Client) DescribeCron(ctx context.Context, expression, language string) (string, error) {
+	return c.callText(ctx, "describe_cron", omitEmpty(map[string]any{"expression": expression, "language": language}))
+}
+
+// CheckBusinessDay reports whether date (YYYY-MM-DD, today when empty) is a
+// business day in a configured calendar
+func (c *Client) CheckBusinessDay(ctx context.Context, calendar, date string) (string, error) {
+	return c.callText(ctx, "check_business_day", omitEmpty(map[string]any{"calendar": calendar, "date": date}))
+}
+
+// GetServerInfo returns the server's build metadata
+func (c *Client) GetServerInfo(ctx context.Context) (*ServerInfo, error) {
+	var info ServerInfo
+	if err := c.callStructured(ctx, "get_server_info", nil, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
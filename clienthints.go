@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// clientHintsCapability is the experimental capability under which clients
+// declare session defaults in initialize and the server advertises them
+const clientHintsCapability = "timemcp"
+
+// clientHints are session defaults a client declares in initialize, e.g.
+//
+//	"capabilities": {"experimental": {"timemcp": {"timezone": "Europe/Warsaw", "locale": "pl-PL"}}}
+//
+// They apply for the life of the session wherever a tool's timezone or
+// language argument is omitted.
+type clientHints struct {
+	Timezone string
+	Locale   string
+}
+
+// parseClientHints reads hints from the experimental client capabilities
+func parseClientHints(experimental map[string]any) clientHints {
+	values, _ := experimental[clientHintsCapability].(map[string]any)
+	timezone, _ := values["timezone"].(string)
+	locale, _ := values["locale"].(string)
+	return clientHints{Timezone: strings.TrimSpace(timezone), Locale: strings.TrimSpace(locale)}
+}
+
+// sessionHints returns the hints of the session in ctx. mcp-go keeps the
+// initialize capabilities on stdio and stateful HTTP sessions; stateless
+// HTTP requests have none.
+func sessionHints(ctx context.Context) clientHints {
+	session, ok := server.ClientSessionFromContext(ctx).(server.SessionWithClientInfo)
+	if !ok {
+		return clientHints{}
+	}
+	return parseClientHints(session.GetClientCapabilities().Experimental)
+}
+
+// sessionTimezone returns the session's timezone hint when it names a real zone
+func sessionTimezone(ctx context.Context) (string, bool) {
+	zone := sessionHints(ctx).Timezone
+	if zone == "" || zone == "Local" {
+		return "", false
+	}
+	if _, err := loadLocationCached(zone); err != nil {
+		return "", false
+	}
+	return zone, true
+}
+
+// sessionLanguage returns the primary language subtag of the session's
+// locale hint, e.g. "pl" for "pl-PL"
+func sessionLanguage(ctx context.Context) string {
+	lang, _, _ := strings.Cut(strings.ReplaceAll(sessionHints(ctx).Locale, "_", "-"), "-")
+	return strings.ToLower(lang)
+}
+
+// acknowledgeClientHints advertises hint support in the initialize result and
+// logs the hints a client sent, warning about a timezone that will be ignored
+func acknowledgeClientHints(ctx context.Context, _ any, request *mcp.InitializeRequest, result *mcp.InitializeResult) {
+	if result.Capabilities.Experimental == nil {
+		result.Capabilities.Experimental = make(map[string]any)
+	}
+	result.Capabilities.Experimental[clientHintsCapability] = map[string]any{"hints": []string{"timezone", "locale"}}
+
+	if request == nil {
+		return
+	}
+	hints := parseClientHints(request.Params.Capabilities.Experimental)
+	if hints.Timezone != "" {
+		if _, err := loadLocationCached(hints.Timezone); err != nil || hints.Timezone == "Local" {
+			slog.WarnContext(ctx, "Ignoring invalid client timezone hint", "timezone", hints.Timezone, "client", request.Params.ClientInfo.Name)
+			hints.Timezone = ""
+		}
+	}
+	if hints != (clientHints{}) {
+		slog.InfoContext(ctx, "Client declared session defaults", "timezone", hints.Timezone, "locale", hints.Locale, "client", request.Params.ClientInfo.Name)
+	}
+}
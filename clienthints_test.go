@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestParseClientHints(t *testing.T) {
+	tests := []struct {
+		name         string
+		experimental map[string]any
+		want         clientHints
+	}{
+		{"none", nil, clientHints{}},
+		{"both", map[string]any{"timemcp": map[string]any{"timezone": " Europe/Warsaw ", "locale": "pl-PL"}}, clientHints{"Europe/Warsaw", "pl-PL"}},
+		{"wrong types", map[string]any{"timemcp": map[string]any{"timezone": 1}}, clientHints{}},
+		{"other capability", map[string]any{"other": map[string]any{"timezone": "UTC"}}, clientHints{}},
+	}
+	for _, tt := range tests {
+		if got := parseClientHints(tt.experimental); got != tt.want {
+			t.Errorf("%s: Expected %+v but got %+v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestAcknowledgeClientHints(t *testing.T) {
+	request := &mcp.InitializeRequest{}
+	request.Params.Capabilities.Experimental = map[string]any{"timemcp": map[string]any{"timezone": "Mars/Olympus"}}
+	result := &mcp.InitializeResult{}
+	acknowledgeClientHints(context.Background(), 1, request, result)
+	if _, ok := result.Capabilities.Experimental[clientHintsCapability]; !ok {
+		t.Errorf("Expected the server to advertise client hints but got %v", result.Capabilities.Experimental)
+	}
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Client timezone detection sources, tried in the configured order
+const (
+	clientTZHeader         = "header"
+	clientTZAcceptLanguage = "accept-language"
+	clientTZGeoIP          = "geoip"
+
+	defaultClientTZHeader = "X-Timezone"
+)
+
+var clientTZSources = []string{clientTZHeader, clientTZAcceptLanguage, clientTZGeoIP}
+
+// regionTimezones maps Accept-Language regions to their zone. Only regions
+// with a single zone are listed; guessing one zone for the US or Russia would
+// be wrong more often than the server default.
+var regionTimezones = map[string]string{
+	"AE": "Asia/Dubai", "AT": "Europe/Vienna", "BE": "Europe/Brussels", "BG": "Europe/Sofia",
+	"CH": "Europe/Zurich", "CN": "Asia/Shanghai", "CZ": "Europe/Prague", "DE": "Europe/Berlin",
+	"DK": "Europe/Copenhagen", "EE": "Europe/Tallinn", "EG": "Africa/Cairo", "FI": "Europe/Helsinki",
+	"FR": "Europe/Paris", "GB": "Europe/London", "GR": "Europe/Athens", "HK": "Asia/Hong_Kong",
+	"HR": "Europe/Zagreb", "HU": "Europe/Budapest", "IE": "Europe/Dublin", "IL": "Asia/Jerusalem",
+	"IN": "Asia/Kolkata", "IS": "Atlantic/Reykjavik", "IT": "Europe/Rome", "JP": "Asia/Tokyo",
+	"KR": "Asia/Seoul", "LT": "Europe/Vilnius", "LU": "Europe/Luxembourg", "LV": "Europe/Riga",
+	"NG": "Africa/Lagos", "NL": "Europe/Amsterdam", "NO": "Europe/Oslo", "NZ": "Pacific/Auckland",
+	"PH": "Asia/Manila", "PK": "Asia/Karachi", "PL": "Europe/Warsaw", "RO": "Europe/Bucharest",
+	"RS": "Europe/Belgrade", "SA": "Asia/Riyadh", "SE": "Europe/Stockholm", "SG": "Asia/Singapore",
+	"SI": "Europe/Ljubljana", "SK": "Europe/Bratislava", "TH": "Asia/Bangkok", "TR": "Europe/Istanbul",
+	"TW": "Asia/Taipei", "UA": "Europe/Kyiv", "VN": "Asia/Ho_Chi_Minh", "ZA": "Africa/Johannesburg",
+}
+
+// detectedTimezone is a client zone and the source it came from
+type detectedTimezone struct {
+	Zone   string
+	Source string
+}
+
+// clientTimezoneDetector infers the caller's timezone from HTTP request
+// metadata; see TIME_CLIENT_TZ_DETECT
+type clientTimezoneDetector struct {
+	sources []string
+	header  string
+	// geoIP returns the current database, which reload_datasets may replace
+	geoIP func() *geoIPDatabase
+}
+
+func newClientTimezoneDetector(config *Config) *clientTimezoneDetector {
+	if len(config.ClientTZDetect) == 0 {
+		return nil
+	}
+	geoIP := func() *geoIPDatabase { return config.datasets().GeoIP }
+	return &clientTimezoneDetector{sources: config.ClientTZDetect, header: config.ClientTZHeader, geoIP: geoIP}
+}
+
+// detect returns the first valid zone the sources yield
+func (d *clientTimezoneDetector) detect(r *http.Request) (detectedTimezone, bool) {
+	for _, source := range d.sources {
+		var zone string
+		switch source {
+		case clientTZHeader:
+			zone = strings.TrimSpace(r.Header.Get(d.header))
+		case clientTZAcceptLanguage:
+			zone = acceptLanguageTimezone(r.Header.Get("Accept-Language"))
+		case clientTZGeoIP:
+			// RemoteAddr is the real client IP behind trusted proxies
+			if ip, db := remoteIP(r), d.geoIP(); db != nil && ip != nil {
+				zone = db.timezone(ip)
+			}
+		}
+		if zone == "" {
+			continue
+		}
+		// Client input must name a real zone; "Local" would mean the server's
+		if _, err := loadLocationCached(zone); err == nil && zone != "Local" {
+			return detectedTimezone{Zone: zone, Source: source}, true
+		}
+	}
+	return detectedTimezone{}, false
+}
+
+// acceptLanguageTimezone returns the zone of the preferred language tag with
+// a single-zone region, e.g. "pl-PL,en;q=0.5" gives Europe/Warsaw
+func acceptLanguageTimezone(header string) string {
+	type tag struct {
+		region string
+		q      float64
+	}
+	var tags []tag
+	for _, part := range strings.Split(header, ",") {
+		lang, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+		subtags := strings.Split(lang, "-")
+		// The region is the first two-letter subtag after the language
+		for _, sub := range subtags[1:] {
+			if len(sub) == 2 {
+				tags = append(tags, tag{strings.ToUpper(sub), q})
+				break
+			}
+		}
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+	for _, t := range tags {
+		if zone, ok := regionTimezones[t.region]; ok && t.q > 0 {
+			return zone
+		}
+	}
+	return ""
+}
+
+// withClientTimezone records the zone detected for the request
+func withClientTimezone(ctx context.Context, detected detectedTimezone) context.Context {
+	return context.WithValue(ctx, clientTimezoneKey, detected)
+}
+
+// clientTimezone returns the zone detected for the HTTP request, if any
+func clientTimezone(ctx context.Context) (detectedTimezone, bool) {
+	detected, ok := ctx.Value(clientTimezoneKey).(detectedTimezone)
+	return detected, ok
+}
+
+func parseClientTZSettings(fetcher *datasetFetcher) ([]string, string, string, *geoIPDatabase, error) {
+	sources := parseHeaderList(strings.ToLower(os.Getenv("TIME_CLIENT_TZ_DETECT")), false)
+	for _, source := range sources {
+		if !slices.Contains(clientTZSources, source) {
+			return nil, "", "", nil, fmt.Errorf("invalid TIME_CLIENT_TZ_DETECT source: %q (expected header, accept-language or geoip)", source)
+		}
+	}
+	header := getEnvWithDefault("TIME_CLIENT_TZ_HEADER", defaultClientTZHeader)
+	geoIPFile := fetcher.source(datasetGeoIP, os.Getenv("TIME_GEOIP_DB"))
+	if !slices.Contains(sources, clientTZGeoIP) {
+		return sources, header, geoIPFile, nil, nil
+	}
+	if geoIPFile == "" {
+		return nil, "", "", nil, fmt.Errorf("TIME_CLIENT_TZ_DETECT=geoip requires TIME_GEOIP_DB or TIME_GEOIP_DB_URL")
+	}
+	if fetcher.pending(datasetGeoIP) {
+		return sources, header, geoIPFile, nil, nil
+	}
+	db, err := openGeoIPDatabase(geoIPFile)
+	if err != nil {
+		return nil, "", "", nil, fmt.Errorf("invalid TIME_GEOIP_DB: %w", err)
+	}
+	return sources, header, geoIPFile, db, nil
+}
+
+// clientTimezoneInfo is the structured result of detect_client_timezone
+type clientTimezoneInfo struct {
+	Timezone string `json:"timezone" jsonschema:"Zone used when a tool's timezone is omitted"`
+	Source   string `json:"source" jsonschema:"header, accept-language, geoip, default (TIME_DEFAULT_TIMEZONE) or system"`
+	Detected bool   `json:"detected" jsonschema:"Whether the zone was inferred from the request"`
+}
+
+func addClientTimezoneTool(mcpServer *server.MCPServer, config *Config) {
+	mcpServer.AddTool(
+		mcp.NewTool("detect_client_timezone",
+			mcp.WithDescription(fmt.Sprintf("Report the timezone inferred for the caller from HTTP request metadata (%s), which tools use when no timezone is given, and where it came from.", strings.Join(config.ClientTZDetect, ", "))),
+			mcp.WithOutputSchema[clientTimezoneInfo](),
+			mcp.WithTitleAnnotation("Detect Client Timezone"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		handleDetectClientTimezone(config),
+	)
+}
+
+// handleDetectClientTimezone returns a handler for the detect_client_timezone tool
+func handleDetectClientTimezone(config *Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		info := clientTimezoneInfo{Timezone: time.Local.String(), Source: "system"}
+		if detected, ok := clientTimezone(ctx); ok {
+			info = clientTimezoneInfo{Timezone: detected.Zone, Source: detected.Source, Detected: true}
+		} else if config.DefaultTimezone != "" {
+			info = clientTimezoneInfo{Timezone: config.DefaultTimezone, Source: "default"}
+		}
+		data, err := json.Marshal(info)
+		if err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to encode result: %v", err)), nil
+		}
+		return mcp.NewToolResultStructured(info, string(data)), nil
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"net"
+	"net/http/httptest"
+	"testing"
+)
+
+// testGeoIPDatabase builds an IPv4 MaxMind DB mapping 203.0.113.0/24 to
+// Asia/Tokyo
+func testGeoIPDatabase() []byte {
+	const nodeCount = 24
+	prefix := net.ParseIP("203.0.113.0").To4()
+	var data []byte
+	for i := range nodeCount {
+		next, empty := uint(i+1), uint(nodeCount)
+		if i == nodeCount-1 {
+			next = nodeCount + 16 // the record at data offset 0
+		}
+		records := [2]uint{empty, empty}
+		records[prefix[i/8]>>(7-i%8)&1] = next
+		for _, r := range records {
+			data = append(data, byte(r>>16), byte(r>>8), byte(r))
+		}
+	}
+	data = append(data, make([]byte, 16)...)
+	data = append(data, 0xE1, 0x48)
+	data = append(data, "location"...)
+	data = append(data, 0xE1, 0x49)
+	data = append(data, "time_zone"...)
+	data = append(data, 0x4A)
+	data = append(data, "Asia/Tokyo"...)
+	data = append(data, mmdbMetadataMarker...)
+	data = append(data, 0xE3, 0x4A)
+	data = append(data, "node_count"...)
+	data = append(data, 0xC1, nodeCount, 0x4B)
+	data = append(data, "record_size"...)
+	data = append(data, 0xA1, 24, 0x4A)
+	data = append(data, "ip_version"...)
+	data = append(data, 0xA1, 4)
+	return data
+}
+
+func TestGeoIPDatabase_Timezone(t *testing.T) {
+	db, err := newGeoIPDatabase(testGeoIPDatabase())
+	if err != nil {
+		t.Fatalf("Failed to open GeoIP database: %v", err)
+	}
+	if zone := db.timezone(net.ParseIP("203.0.113.7")); zone != "Asia/Tokyo" {
+		t.Errorf("Expected Asia/Tokyo but got %q", zone)
+	}
+	for _, ip := range []string{"198.51.100.7", "2001:db8::1"} {
+		if zone := db.timezone(net.ParseIP(ip)); zone != "" {
+			t.Errorf("Expected no zone for %s but got %q", ip, zone)
+		}
+	}
+	if _, err := newGeoIPDatabase([]byte("not a database")); err == nil {
+		t.Errorf("Expected an error for a file without metadata")
+	}
+}
+
+func TestAcceptLanguageTimezone(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected string
+	}{
+		{"pl-PL,pl;q=0.9,en;q=0.8", "Europe/Warsaw"},
+		{"en-US,en;q=0.9,de-DE;q=0.5", "Europe/Berlin"},
+		{"en;q=0.5, ja-JP", "Asia/Tokyo"},
+		{"zh-Hant-TW", "Asia/Taipei"},
+		{"fr-FR;q=0", ""},
+		{"en-US", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if zone := acceptLanguageTimezone(tt.header); zone != tt.expected {
+			t.Errorf("%q: Expected %q but got %q", tt.header, tt.expected, zone)
+		}
+	}
+}
+
+func TestClientTimezoneDetector(t *testing.T) {
+	db, err := newGeoIPDatabase(testGeoIPDatabase())
+	if err != nil {
+		t.Fatalf("Failed to open GeoIP database: %v", err)
+	}
+	detector := newClientTimezoneDetector(&Config{
+		ClientTZDetect: []string{clientTZHeader, clientTZAcceptLanguage, clientTZGeoIP},
+		ClientTZHeader: defaultClientTZHeader,
+		GeoIP:          db,
+	})
+
+	tests := []struct {
+		name, header, language, remote string
+		expected                       detectedTimezone
+	}{
+		{"header", "America/Chicago", "pl-PL", "203.0.113.7:1234", detectedTimezone{"America/Chicago", clientTZHeader}},
+		{"invalid header", "Mars/Olympus", "pl-PL", "203.0.113.7:1234", detectedTimezone{"Europe/Warsaw", clientTZAcceptLanguage}},
+		{"local header", "Local", "", "203.0.113.7:1234", detectedTimezone{"Asia/Tokyo", clientTZGeoIP}},
+		{"geoip", "", "en-US", "203.0.113.7:1234", detectedTimezone{"Asia/Tokyo", clientTZGeoIP}},
+		{"nothing", "", "en", "192.0.2.1:1234", detectedTimezone{}},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("POST", "/mcp", nil)
+		req.RemoteAddr = tt.remote
+		req.Header.Set("X-Timezone", tt.header)
+		req.Header.Set("Accept-Language", tt.language)
+		if detected, _ := detector.detect(req); detected != tt.expected {
+			t.Errorf("%s: Expected %+v but got %+v", tt.name, tt.expected, detected)
+		}
+	}
+}
+
+func TestLoadTimezone_ClientTimezone(t *testing.T) {
+	config := &Config{DefaultTimezone: "UTC", ClientTZDetect: []string{clientTZHeader}, ClientTZHeader: "X-Zone"}
+	contextFunc, _, err := createHTTPMiddleware(config)
+	if err != nil {
+		t.Fatalf("Failed to create HTTP middleware: %v", err)
+	}
+	req := httptest.NewRequest("POST", "/mcp", nil)
+	req.Header.Set("X-Zone", "Asia/Kolkata")
+	ctx := contextFunc(context.Background(), req)
+
+	loc, err := loadTimezone(ctx, "", config)
+	if err != nil {
+		t.Fatalf("Failed to load timezone: %v", err)
+	}
+	if loc.String() != "Asia/Kolkata" {
+		t.Errorf("Expected the detected zone but got %s", loc)
+	}
+	if loc, _ := loadTimezone(ctx, "Europe/Paris", config); loc.String() != "Europe/Paris" {
+		t.Errorf("Expected an explicit zone to win but got %s", loc)
+	}
+	if loc, _ := loadTimezone(context.Background(), "", config); loc.String() != "UTC" {
+		t.Errorf("Expected the default zone without a client zone but got %s", loc)
+	}
+}
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// defaultClockInterval is the tick cadence when none is given
+	defaultClockInterval = time.Minute
+	// minClockInterval and maxClockInterval bound the tick cadence
+	minClockInterval = time.Second
+	maxClockInterval = 24 * time.Hour
+	// maxClockSubscriptions caps the subscriptions of one session
+	maxClockSubscriptions = 5
+)
+
+// clockTick is the data of one tick notification
+type clockTick struct {
+	SubscriptionID string `json:"subscription_id"`
+	Timezone       string `json:"timezone"`
+	Time           string `json:"time"`
+	Unix           int64  `json:"unix"`
+	DayOfWeek      string `json:"day_of_week"`
+	IsDST          bool   `json:"is_dst"`
+}
+
+// clockSubscription is one session's tick stream
+type clockSubscription struct {
+	ID        string `json:"id"`
+	SessionID string `json:"-"`
+	Timezone  string `json:"timezone"`
+	Interval  string `json:"interval"`
+	NextTick  string `json:"next_tick"`
+	interval  time.Duration
+	loc       *time.Location
+	stop      chan struct{}
+	rearm     chan struct{}
+}
+
+// clockTicker pushes time ticks to subscribed sessions. Subscriptions live
+// with the session on this replica, so they are kept in memory rather than
+// in the state store, and end when a tick finds the session gone.
+type clockTicker struct {
+	mcpServer *server.MCPServer
+	now       func() time.Time
+
+	mu   sync.Mutex
+	subs map[string]*clockSubscription
+	wg   sync.WaitGroup
+}
+
+func newClockTicker(mcpServer *server.MCPServer) *clockTicker {
+	return &clockTicker{
+		mcpServer: mcpServer,
+		now:       time.Now,
+		subs:      make(map[string]*clockSubscription),
+	}
+}
+
+// nextClockTick returns the first multiple of interval after t, so that
+// minute ticks land on the minute
+func nextClockTick(t time.Time, interval time.Duration) time.Time {
+	return t.Truncate(interval).Add(interval)
+}
+
+// subscribe starts a tick stream for a session
+func (c *clockTicker) subscribe(sessionID string, loc *time.Location, interval time.Duration) (*clockSubscription, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	count := 0
+	for _, sub := range c.subs {
+		if sub.SessionID == sessionID {
+			count++
+		}
+	}
+	if count >= maxClockSubscriptions {
+		return nil, fmt.Errorf("this session already has %d clock subscriptions; unsubscribe one first", maxClockSubscriptions)
+	}
+
+	sub := &clockSubscription{
+		ID:        newJobID(),
+		SessionID: sessionID,
+		Timezone:  loc.String(),
+		Interval:  interval.String(),
+		NextTick:  nextClockTick(c.now(), interval).In(loc).Format(time.RFC3339),
+		interval:  interval,
+		loc:       loc,
+		stop:      make(chan struct{}),
+		rearm:     make(chan struct{}, 1),
+	}
+	c.subs[sub.ID] = sub
+	c.wg.Add(1)
+	go c.run(sub)
+	return sub, nil
+}
+
+// run sends ticks until the subscription is stopped or its session is gone
+func (c *clockTicker) run(sub *clockSubscription) {
+	defer c.wg.Done()
+	timer := time.NewTimer(time.Until(nextClockTick(c.now(), sub.interval)))
+	defer timer.Stop()
+	for {
+		select {
+		case <-sub.stop:
+			return
+		case <-sub.rearm:
+			timer.Reset(time.Until(nextClockTick(c.now(), sub.interval)))
+			continue
+		case <-timer.C:
+		}
+		if !c.tick(sub, c.now()) {
+			c.unsubscribe(sub.SessionID, sub.ID)
+			return
+		}
+		timer.Reset(time.Until(nextClockTick(c.now(), sub.interval)))
+	}
+}
+
+// tick sends one notification and reports whether the session is still there
+func (c *clockTicker) tick(sub *clockSubscription, now time.Time) bool {
+	// Ticks fire a moment after the boundary; report the boundary itself
+	local := now.Round(time.Second).In(sub.loc)
+	data, err := json.Marshal(clockTick{
+		SubscriptionID: sub.ID,
+		Timezone:       sub.Timezone,
+		Time:           local.Format(time.RFC3339),
+		Unix:           local.Unix(),
+		DayOfWeek:      local.Weekday().String(),
+		IsDST:          local.IsDST(),
+	})
+	if err != nil {
+		return true
+	}
+	var params map[string]any
+	json.Unmarshal(data, &params)
+
+	err = c.mcpServer.SendNotificationToSpecificClient(sub.SessionID, "notifications/message", map[string]any{
+		"level":  mcp.LoggingLevelInfo,
+		"logger": "clock",
+		"data":   params,
+	})
+	if errors.Is(err, server.ErrSessionNotFound) {
+		slog.Debug("Clock subscription ended with its session", "id", sub.ID)
+		return false
+	}
+	if err != nil {
+		slog.Warn("Failed to send clock tick", "id", sub.ID, "error", err)
+	}
+	return true
+}
+
+// unsubscribe stops the session's subscription id, or all of its
+// subscriptions when id is empty, and returns how many were stopped
+func (c *clockTicker) unsubscribe(sessionID, id string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stopped := 0
+	for key, sub := range c.subs {
+		if sub.SessionID == sessionID && (id == "" || key == id) {
+			close(sub.stop)
+			delete(c.subs, key)
+			stopped++
+		}
+	}
+	return stopped
+}
+
+// rearm realigns every subscription's next tick with the wall clock, after
+// the system clock stepped
+func (c *clockTicker) rearm() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, sub := range c.subs {
+		select {
+		case sub.rearm <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// close stops every subscription
+func (c *clockTicker) close() {
+	c.mu.Lock()
+	for key, sub := range c.subs {
+		close(sub.stop)
+		delete(c.subs, key)
+	}
+	c.mu.Unlock()
+	c.wg.Wait()
+}
+
+// addClockTools registers subscribe_clock and unsubscribe_clock. They need a
+// session that outlives the call, so they are not offered in stateless mode.
+func addClockTools(mcpServer *server.MCPServer, clock *clockTicker, config *Config) {
+	mcpServer.AddTool(
+		mcp.NewTool("subscribe_clock",
+			mcp.WithDescription("Receive a time tick as an MCP logging notification (logger 'clock') at a fixed cadence, aligned to the interval (e.g. on each minute), with the time in the given timezone. Ticks stop when the session ends or with unsubscribe_clock."),
+			mcp.WithString("timezone",
+				mcp.Description("Timezone of the ticks. Defaults to system timezone if not provided."),
+			),
+			mcp.WithString("interval",
+				mcp.Description(fmt.Sprintf("Tick cadence as a Go duration, between %s and %s. Defaults to %s.", minClockInterval, maxClockInterval, defaultClockInterval)),
+			),
+			mcp.WithTitleAnnotation("Subscribe to Clock Ticks"),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(false),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		handleSubscribeClock(clock, config),
+	)
+	mcpServer.AddTool(
+		mcp.NewTool("unsubscribe_clock",
+			mcp.WithDescription("Stop clock ticks for this session: one subscription by ID, or all of them."),
+			mcp.WithString("id",
+				mcp.Description("Subscription ID returned by subscribe_clock. Omit to stop all of this session's subscriptions."),
+			),
+			mcp.WithTitleAnnotation("Unsubscribe from Clock Ticks"),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		handleUnsubscribeClock(clock),
+	)
+}
+
+// clockSessionID returns the calling session, which ticks are sent to
+func clockSessionID(ctx context.Context) (string, *mcp.CallToolResult) {
+	session := server.ClientSessionFromContext(ctx)
+	if session == nil || session.SessionID() == "" {
+		return "", toolError(errorSessionRequired, "Clock subscriptions need an MCP session")
+	}
+	return session.SessionID(), nil
+}
+
+// parseClockInterval parses a tick interval such as "30s", defaulting when
+// empty and rejecting durations outside the allowed range
+func parseClockInterval(s string) (time.Duration, bool) {
+	if s == "" {
+		return defaultClockInterval, true
+	}
+	interval, err := time.ParseDuration(s)
+	return interval, err == nil && interval >= minClockInterval && interval <= maxClockInterval
+}
+
+// handleSubscribeClock returns a handler for the subscribe_clock tool
+func handleSubscribeClock(clock *clockTicker, config *Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		sessionID, denied := clockSessionID(ctx)
+		if denied != nil {
+			return denied, nil
+		}
+		timezoneStr := request.GetString("timezone", "")
+		loc, err := loadTimezone(ctx, timezoneStr, config)
+		if err != nil {
+			return invalidTimezoneError("Invalid timezone", timezoneStr), nil
+		}
+		s := request.GetString("interval", "")
+		interval, ok := parseClockInterval(s)
+		if !ok {
+			return toolError(errorInvalidArgument, fmt.Sprintf("Invalid interval: %s. Please provide a duration between %s and %s.", s, minClockInterval, maxClockInterval)), nil
+		}
+
+		sub, err := clock.subscribe(sessionID, loc, interval)
+		if err != nil {
+			return toolError(errorLimitExceeded, err.Error()), nil
+		}
+		slog.InfoContext(ctx, "Clock subscription started", "id", sub.ID, "timezone", sub.Timezone, "interval", sub.Interval)
+		data, err := json.Marshal(sub)
+		if err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to encode subscription: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(data)), nil
+	}
+}
+
+// handleUnsubscribeClock returns a handler for the unsubscribe_clock tool
+func handleUnsubscribeClock(clock *clockTicker) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		sessionID, denied := clockSessionID(ctx)
+		if denied != nil {
+			return denied, nil
+		}
+		id := request.GetString("id", "")
+		stopped := clock.unsubscribe(sessionID, id)
+		if id != "" && stopped == 0 {
+			return toolError(errorNotFound, fmt.Sprintf("No clock subscription with ID %s", id)), nil
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("Stopped %d clock subscription(s)", stopped)), nil
+	}
+}
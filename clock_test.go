@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// testSession is a client session whose notifications the test reads
+type testSession struct {
+	id            string
+	notifications chan mcp.JSONRPCNotification
+}
+
+func (s *testSession) Initialize()       {}
+func (s *testSession) Initialized() bool { return true }
+func (s *testSession) SessionID() string { return s.id }
+func (s *testSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifications
+}
+
+func TestClockTicker(t *testing.T) {
+	mcpServer := server.NewMCPServer("TimeMCP", "test", server.WithToolCapabilities(true))
+	session := &testSession{id: "session-1", notifications: make(chan mcp.JSONRPCNotification, 10)}
+	if err := mcpServer.RegisterSession(context.Background(), session); err != nil {
+		t.Fatalf("Failed to register session: %v", err)
+	}
+	ctx := mcpServer.WithContext(context.Background(), session)
+	clock := newClockTicker(mcpServer)
+	defer clock.close()
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"timezone": "Asia/Tokyo", "interval": "1s"}
+	result, err := handleSubscribeClock(clock, &Config{})(ctx, req)
+	if err != nil || result.IsError {
+		t.Fatalf("Failed to subscribe: %v %s", err, toolResultText(result))
+	}
+	var sub clockSubscription
+	if err := json.Unmarshal([]byte(toolResultText(result)), &sub); err != nil {
+		t.Fatalf("Failed to decode subscription: %v", err)
+	}
+
+	select {
+	case n := <-session.notifications:
+		data, _ := json.Marshal(n.Params.AdditionalFields["data"])
+		var tick clockTick
+		json.Unmarshal(data, &tick)
+		if n.Method != "notifications/message" || tick.SubscriptionID != sub.ID || !strings.HasSuffix(tick.Time, "+09:00") {
+			t.Errorf("Expected a Tokyo tick for %s but got %s %s", sub.ID, n.Method, data)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("Failed to receive a tick")
+	}
+
+	unsub := mcp.CallToolRequest{}
+	unsub.Params.Arguments = map[string]any{"id": sub.ID}
+	if result, _ := handleUnsubscribeClock(clock)(ctx, unsub); result.IsError {
+		t.Errorf("Expected to unsubscribe but got %s", toolResultText(result))
+	}
+	if result, _ := handleUnsubscribeClock(clock)(ctx, unsub); !result.IsError {
+		t.Errorf("Expected an error for an unknown subscription")
+	}
+
+	mcpServer.UnregisterSession(context.Background(), session.id)
+	if clock.tick(&clockSubscription{ID: "gone", SessionID: session.id, loc: time.UTC}, time.Now()) {
+		t.Errorf("Expected a tick to a closed session to end the subscription")
+	}
+}
+
+func TestHandleSubscribeClock_Errors(t *testing.T) {
+	mcpServer := server.NewMCPServer("TimeMCP", "test", server.WithToolCapabilities(true))
+	session := &testSession{id: "session-1", notifications: make(chan mcp.JSONRPCNotification, 10)}
+	ctx := mcpServer.WithContext(context.Background(), session)
+	clock := newClockTicker(mcpServer)
+	defer clock.close()
+
+	tests := []struct {
+		ctx      context.Context
+		args     map[string]any
+		expected string
+	}{
+		{context.Background(), map[string]any{}, "need an MCP session"},
+		{ctx, map[string]any{"interval": "100ms"}, "Invalid interval"},
+		{ctx, map[string]any{"interval": "soon"}, "Invalid interval"},
+		{ctx, map[string]any{"timezone": "Invalid/Zone"}, "Invalid timezone"},
+	}
+	for _, tt := range tests {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = tt.args
+		result, err := handleSubscribeClock(clock, &Config{})(tt.ctx, req)
+		if err != nil || !result.IsError || !strings.Contains(toolResultText(result), tt.expected) {
+			t.Errorf("Expected an error containing %q but got %s (%v)", tt.expected, toolResultText(result), err)
+		}
+	}
+
+	for range maxClockSubscriptions {
+		if _, err := clock.subscribe(session.id, time.UTC, time.Hour); err != nil {
+			t.Fatalf("Failed to subscribe: %v", err)
+		}
+	}
+	if _, err := clock.subscribe(session.id, time.UTC, time.Hour); err == nil {
+		t.Errorf("Expected the per-session subscription limit to be enforced")
+	}
+}
+
+func TestNextClockTick(t *testing.T) {
+	now := time.Date(2025, 6, 2, 10, 7, 30, 0, time.UTC)
+	if got := nextClockTick(now, time.Minute); !got.Equal(time.Date(2025, 6, 2, 10, 8, 0, 0, time.UTC)) {
+		t.Errorf("Expected the next minute but got %v", got)
+	}
+	if got := nextClockTick(now, 15*time.Minute); !got.Equal(time.Date(2025, 6, 2, 10, 15, 0, 0, time.UTC)) {
+		t.Errorf("Expected the next quarter hour but got %v", got)
+	}
+}
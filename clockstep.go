@@ -0,0 +1,90 @@
+package main
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// clockStepCheckInterval is how often the wall clock is compared with the
+// monotonic clock
+const clockStepCheckInterval = 5 * time.Second
+
+// clockStepMonitor detects the system clock stepping, as on a VM resume or
+// an NTP correction, by comparing how far the wall clock moved between
+// checks with how far the monotonic clock did. Timers run on the monotonic
+// clock, so after a step anything armed for a wall time, such as clock
+// subscription ticks, fires off the mark until it is re-armed.
+type clockStepMonitor struct {
+	config    func() *Config
+	mcpServer *server.MCPServer
+	stats     *serverStats
+	// onStep runs after each detected step
+	onStep []func()
+	// read returns the wall time and the monotonic time since an arbitrary
+	// origin
+	read func() (time.Time, time.Duration)
+
+	lastWall time.Time
+	lastMono time.Duration
+}
+
+func newClockStepMonitor(config func() *Config, mcpServer *server.MCPServer, stats *serverStats) *clockStepMonitor {
+	origin := time.Now()
+	m := &clockStepMonitor{
+		config:    config,
+		mcpServer: mcpServer,
+		stats:     stats,
+		read: func() (time.Time, time.Duration) {
+			now := time.Now()
+			return now.Round(0), now.Sub(origin)
+		},
+	}
+	m.lastWall, m.lastMono = m.read()
+	return m
+}
+
+// start checks the clock every clockStepCheckInterval in the background.
+// The threshold is read on each check, so detection follows config reloads.
+func (m *clockStepMonitor) start() {
+	go func() {
+		for range time.Tick(clockStepCheckInterval) {
+			m.check()
+		}
+	}()
+}
+
+// check compares the clocks once and reports a step beyond the configured
+// threshold, returning the step or zero
+func (m *clockStepMonitor) check() time.Duration {
+	wall, mono := m.read()
+	step := wall.Sub(m.lastWall) - (mono - m.lastMono)
+	m.lastWall, m.lastMono = wall, mono
+
+	config := m.config()
+	if config.ClockStepThreshold <= 0 || step.Abs() < config.ClockStepThreshold {
+		return 0
+	}
+	slog.Warn("System clock stepped; times computed before it may be off", "step", step.Round(time.Millisecond).String(), "now", wall.UTC().Format(time.RFC3339))
+	if m.stats != nil {
+		m.stats.recordClockStep(wall, step)
+	}
+	if config.ClockStepNotify && m.mcpServer != nil {
+		m.mcpServer.SendNotificationToAllClients("notifications/message", map[string]any{
+			"level":  mcp.LoggingLevelWarning,
+			"logger": "clock",
+			"data": map[string]any{
+				"event":        "clock_step",
+				"step_seconds": step.Seconds(),
+				"time":         wall.UTC().Format(time.RFC3339),
+				"message":      "The server clock stepped; recompute times relative to now",
+			},
+		})
+	}
+	for _, fn := range m.onStep {
+		fn()
+	}
+	return step
+}
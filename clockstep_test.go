@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestClockStepMonitor(t *testing.T) {
+	mcpServer := server.NewMCPServer("TimeMCP", "test", server.WithToolCapabilities(true))
+	session := &testSession{id: "session-1", notifications: make(chan mcp.JSONRPCNotification, 10)}
+	if err := mcpServer.RegisterSession(context.Background(), session); err != nil {
+		t.Fatalf("Failed to register session: %v", err)
+	}
+	config := &Config{ClockStepThreshold: time.Second}
+	stats := newServerStats("")
+	monitor := newClockStepMonitor(func() *Config { return config }, mcpServer, stats)
+	wall := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	var mono time.Duration
+	monitor.read = func() (time.Time, time.Duration) { return wall, mono }
+	monitor.lastWall, monitor.lastMono = monitor.read()
+	rearmed := 0
+	monitor.onStep = append(monitor.onStep, func() { rearmed++ })
+
+	// Both clocks advancing together, with a little drift, is not a step
+	wall, mono = wall.Add(5*time.Second+100*time.Millisecond), mono+5*time.Second
+	if step := monitor.check(); step != 0 {
+		t.Errorf("Expected no step but got %s", step)
+	}
+
+	// A resumed VM: the wall clock moved an hour while the monotonic one paused
+	wall, mono = wall.Add(time.Hour), mono+5*time.Second
+	if step := monitor.check(); step != time.Hour-5*time.Second {
+		t.Errorf("Expected a forward step of 59m55s but got %s", step)
+	}
+	// An NTP correction setting the clock back
+	wall, mono = wall.Add(-2*time.Second), mono+5*time.Second
+	if step := monitor.check(); step != -7*time.Second {
+		t.Errorf("Expected a backward step of 7s but got %s", step)
+	}
+	if snap := stats.snapshot(false); snap.ClockSteps != 2 || snap.LastClockStepSeconds != -7 {
+		t.Errorf("Expected 2 clock steps, the last of -7s, but got %d %v", snap.ClockSteps, snap.LastClockStepSeconds)
+	}
+	if rearmed != 2 {
+		t.Errorf("Expected timers to be re-armed after each step but got %d", rearmed)
+	}
+	if len(session.notifications) != 0 {
+		t.Errorf("Expected no notifications unless enabled but got %d", len(session.notifications))
+	}
+
+	config.ClockStepNotify = true
+	wall, mono = wall.Add(time.Minute), mono+5*time.Second
+	monitor.check()
+	select {
+	case n := <-session.notifications:
+		if n.Method != "notifications/message" || n.Params.AdditionalFields["logger"] != "clock" {
+			t.Errorf("Expected a clock logging notification but got %s %v", n.Method, n.Params.AdditionalFields)
+		}
+	default:
+		t.Error("Expected clients to be notified of the step")
+	}
+
+	config.ClockStepThreshold = 0
+	wall, mono = wall.Add(time.Hour), mono+5*time.Second
+	if step := monitor.check(); step != 0 {
+		t.Errorf("Expected detection to be off without a threshold but got %s", step)
+	}
+}
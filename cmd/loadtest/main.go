@@ -0,0 +1,278 @@
+// Command loadtest drives a running TimeMCP HTTP server with concurrent MCP
+// clients calling a weighted mix of tools, then reports throughput and
+// latency percentiles per tool.
+//
+//	go run ./cmd/loadtest -url http://localhost:8080/mcp -c 32 -d 30s \
+//	    -mix get_current_time=3,convert_time=1 -token "$TOKEN"
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// timezones are sampled for tool arguments; invalid zones are deliberately
+// absent so errors in the report point at the server
+var timezones = []string{
+	"UTC", "America/New_York", "America/Los_Angeles", "Europe/London", "Europe/Warsaw",
+	"Asia/Tokyo", "Asia/Kolkata", "Australia/Sydney", "America/Sao_Paulo", "Africa/Nairobi",
+}
+
+// toolArguments builds arguments for the tools the harness knows about;
+// other tools are called without arguments
+var toolArguments = map[string]func(r *rand.Rand) map[string]any{
+	"get_current_time": func(r *rand.Rand) map[string]any {
+		return map[string]any{"timezone": timezones[r.IntN(len(timezones))]}
+	},
+	"convert_time": func(r *rand.Rand) map[string]any {
+		return map[string]any{
+			"source_timezone": timezones[r.IntN(len(timezones))],
+			"target_timezone": timezones[r.IntN(len(timezones))],
+			"time":            fmt.Sprintf("%02d:%02d", r.IntN(24), r.IntN(60)),
+		}
+	},
+}
+
+func init() {
+	toolArguments["convert_time_v2"] = toolArguments["convert_time"]
+}
+
+// weightedTool is one entry of the tool mix
+type weightedTool struct {
+	name   string
+	weight int
+}
+
+// parseMix parses "tool=weight,tool=weight"; a missing weight means 1
+func parseMix(str string) ([]weightedTool, error) {
+	var mix []weightedTool
+	for _, item := range strings.Split(str, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, weightStr, hasWeight := strings.Cut(item, "=")
+		weight := 1
+		if hasWeight {
+			w, err := strconv.Atoi(weightStr)
+			if err != nil || w < 1 {
+				return nil, fmt.Errorf("invalid weight for %s: %q", name, weightStr)
+			}
+			weight = w
+		}
+		mix = append(mix, weightedTool{strings.TrimSpace(name), weight})
+	}
+	if len(mix) == 0 {
+		return nil, errors.New("empty tool mix")
+	}
+	return mix, nil
+}
+
+// pick returns a tool name with probability proportional to its weight
+func pick(mix []weightedTool, r *rand.Rand) string {
+	total := 0
+	for _, t := range mix {
+		total += t.weight
+	}
+	n := r.IntN(total)
+	for _, t := range mix {
+		if n < t.weight {
+			return t.name
+		}
+		n -= t.weight
+	}
+	return mix[len(mix)-1].name
+}
+
+// results collects latencies per tool across workers
+type results struct {
+	mu        sync.Mutex
+	latencies map[string][]time.Duration
+	errors    map[string]int
+	firstErr  map[string]string
+}
+
+func (res *results) add(tool string, latency time.Duration, err error) {
+	res.mu.Lock()
+	defer res.mu.Unlock()
+	res.latencies[tool] = append(res.latencies[tool], latency)
+	if err != nil {
+		res.errors[tool]++
+		if _, ok := res.firstErr[tool]; !ok {
+			res.firstErr[tool] = err.Error()
+		}
+	}
+}
+
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	return sorted[int(float64(len(sorted)-1)*p)]
+}
+
+func main() {
+	url := flag.String("url", "http://localhost:8080/mcp", "MCP endpoint of the server under test")
+	concurrency := flag.Int("c", 8, "Number of concurrent clients, each with its own session")
+	duration := flag.Duration("d", 10*time.Second, "How long to generate load")
+	requests := flag.Int("n", 0, "Stop after this many calls in total (0: run for -d)")
+	mixFlag := flag.String("mix", "get_current_time=3,convert_time=1", "Weighted tool mix as tool=weight pairs")
+	token := flag.String("token", os.Getenv("TIME_LOADTEST_TOKEN"), "Bearer token sent with every request")
+	seed := flag.Uint64("seed", uint64(time.Now().UnixNano()), "Random seed for tool and argument choice")
+	flag.Parse()
+
+	if err := run(*url, *concurrency, *duration, *requests, *mixFlag, *token, *seed); err != nil {
+		fmt.Fprintf(os.Stderr, "loadtest: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(url string, concurrency int, duration time.Duration, requests int, mixFlag, token string, seed uint64) error {
+	mix, err := parseMix(mixFlag)
+	if err != nil {
+		return err
+	}
+	if concurrency < 1 {
+		return errors.New("concurrency must be at least 1")
+	}
+
+	var opts []transport.StreamableHTTPCOption
+	if token != "" {
+		opts = append(opts, transport.WithHTTPHeaders(map[string]string{"Authorization": "Bearer " + token}))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), duration)
+	defer cancel()
+
+	// Sessions are set up before the clock starts so the report measures tool calls only
+	clients := make([]*client.Client, concurrency)
+	for i := range clients {
+		c, err := client.NewStreamableHttpClient(url, opts...)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+		if err := c.Start(context.Background()); err != nil {
+			return fmt.Errorf("failed to start client: %w", err)
+		}
+		init := mcp.InitializeRequest{}
+		init.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+		init.Params.ClientInfo = mcp.Implementation{Name: "timemcp-loadtest", Version: "1.0.0"}
+		if _, err := c.Initialize(context.Background(), init); err != nil {
+			return fmt.Errorf("failed to initialize session: %w", err)
+		}
+		clients[i] = c
+	}
+
+	res := &results{
+		latencies: make(map[string][]time.Duration),
+		errors:    make(map[string]int),
+		firstErr:  make(map[string]string),
+	}
+	var remaining chan struct{}
+	if requests > 0 {
+		remaining = make(chan struct{}, requests)
+		for range requests {
+			remaining <- struct{}{}
+		}
+		close(remaining)
+	}
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i, c := range clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r := rand.New(rand.NewPCG(seed, uint64(i)))
+			for ctx.Err() == nil {
+				if remaining != nil {
+					if _, ok := <-remaining; !ok {
+						return
+					}
+				}
+				tool := pick(mix, r)
+				req := mcp.CallToolRequest{}
+				req.Params.Name = tool
+				if args, ok := toolArguments[tool]; ok {
+					req.Params.Arguments = args(r)
+				}
+
+				callStart := time.Now()
+				result, err := c.CallTool(ctx, req)
+				if ctx.Err() != nil {
+					return // Calls cut off by the deadline are not counted
+				}
+				if err == nil && result.IsError {
+					err = toolError(result)
+				}
+				res.add(tool, time.Since(callStart), err)
+			}
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	report(res, elapsed, concurrency)
+	return nil
+}
+
+func toolError(result *mcp.CallToolResult) error {
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			return errors.New(text.Text)
+		}
+	}
+	return errors.New("tool returned an error")
+}
+
+func report(res *results, elapsed time.Duration, concurrency int) {
+	tools := make([]string, 0, len(res.latencies))
+	total, totalErrors := 0, 0
+	for tool, latencies := range res.latencies {
+		tools = append(tools, tool)
+		total += len(latencies)
+		totalErrors += res.errors[tool]
+	}
+	sort.Strings(tools)
+
+	fmt.Printf("%d calls in %s with %d clients: %.1f calls/s, %d errors\n\n",
+		total, elapsed.Round(time.Millisecond), concurrency, float64(total)/elapsed.Seconds(), totalErrors)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "tool\tcalls\terrors\tp50\tp90\tp99\tmax\t")
+	for _, tool := range tools {
+		latencies := slices.Clone(res.latencies[tool])
+		slices.Sort(latencies)
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\t%s\t%s\t\n", tool, len(latencies), res.errors[tool],
+			percentile(latencies, 0.50).Round(time.Microsecond),
+			percentile(latencies, 0.90).Round(time.Microsecond),
+			percentile(latencies, 0.99).Round(time.Microsecond),
+			latencies[len(latencies)-1].Round(time.Microsecond))
+	}
+	w.Flush()
+
+	for _, tool := range tools {
+		if msg, ok := res.firstErr[tool]; ok {
+			fmt.Printf("\nfirst %s error: %s", tool, msg)
+		}
+	}
+	if len(res.firstErr) > 0 {
+		fmt.Println()
+	}
+}
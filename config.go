@@ -2,28 +2,77 @@ package main
 
 import (
 	"fmt"
+	"log/slog"
+	"net"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
 )
 
+// Server identity defaults, overridable at build time with
+// -ldflags "-X main.serverName=... -X main.serverVersion=..."
+var (
+	serverName         = "TimeMCP"
+	serverVersion      = "1.0.0"
+	serverInstructions = "Time conversion and timezone utilities."
+)
+
 // Default configuration values
 const (
 	// HTTP transport defaults
-	defaultHTTPAddress        = ":8080"
-	defaultHTTPPath           = "/mcp"
-	defaultHTTPStateless      = false
-	defaultHTTPHeartbeat      = 30 * time.Second
-	defaultHTTPTimeout        = 30 * time.Second
-	defaultHTTPCORSEnabled    = false
-	defaultHTTPSessionIdleTTL = 5 * time.Minute
+	defaultHTTPAddress         = ":8080"
+	defaultHTTPPath            = "/mcp"
+	defaultHTTPStateless       = false
+	defaultHTTPHeartbeat       = 30 * time.Second
+	defaultHTTPTimeout         = 30 * time.Second
+	defaultHTTPShutdownTimeout = 30 * time.Second
+	defaultHTTPCORSEnabled     = false
+	defaultHTTPCORSMethods     = "GET, POST, DELETE, OPTIONS"
+	defaultHTTPCORSHeaders     = "Content-Type, Authorization, Mcp-Session-Id, Mcp-Protocol-Version, Last-Event-ID, X-Request-ID"
+	defaultHTTPCORSExpose      = "Mcp-Session-Id, X-Request-ID"
+	defaultHTTPCORSMaxAge      = 24 * time.Hour
+	defaultHTTPCORSRoutes      = "/health=GET, OPTIONS"
+	defaultHTTPSessionIdleTTL  = 5 * time.Minute
+	defaultHTTPTLSSelfSigned   = false
+	defaultHTTPTLSClientAuth   = "require"
+	defaultHTTPSocketMode      = 0o660
+	defaultHTTPACMECacheDir    = "acme-cache"
+	defaultHTTPCompression     = false
+	defaultHTTPREST            = false
+
+	// Security header defaults
+	defaultHTTPSecurityHeaders       = true
+	defaultHTTPHSTSMaxAge            = 31536000 // one year, in seconds
+	defaultHTTPFrameOptions          = "DENY"
+	defaultHTTPContentSecurityPolicy = "default-src 'none'; frame-ancestors 'none'"
 
 	// Authentication defaults
-	defaultAuthEnabled  = false
-	defaultAuthIssuer   = "TimeMCP"
-	defaultAuthAudience = "TimeMCP-user"
+	defaultAuthEnabled     = false
+	defaultAuthIssuer      = "TimeMCP"
+	defaultAuthAudience    = "TimeMCP-user"
+	defaultAuthAlgorithm   = algHS256
+	defaultAuthMode        = authModeJWT
+	defaultAuthJWKSRefresh = time.Hour
+	defaultAuthDenylistTTL = time.Minute
+	defaultAuthAdminRole   = "admin"
+
+	// Telemetry defaults
+	defaultOTelSampleRatio    = 1.0
+	defaultStatsFlushInterval = time.Minute
+	defaultDebugAddress       = "127.0.0.1:6060"
+	defaultHealthNTPMaxOffset = time.Second
+	defaultClockStepThreshold = time.Second
+
+	// Remote dataset defaults
+	defaultDatasetRefresh = 24 * time.Hour
+
+	// Tool defaults
+	defaultToolTimeout   = 10 * time.Second
+	defaultToolCacheSize = 1000
+	defaultToolCacheTTL  = 10 * time.Minute
 
 	// Timezone defaults
 	defaultTimezone = "" // Empty means use system timezone
@@ -31,57 +80,450 @@ const (
 
 // Config holds the server configuration
 type Config struct {
+	// Server identity reported to MCP clients and by /health
+	ServerName         string
+	ServerVersion      string
+	ServerInstructions string
+
 	// HTTP transport settings
-	HTTPAddress        string
-	HTTPPath           string
-	HTTPStateless      bool
-	HTTPHeartbeat      time.Duration
-	HTTPTimeout        time.Duration
-	HTTPCORSEnabled    bool
-	HTTPCORSOrigins    []string
-	HTTPSessionIdleTTL time.Duration
+	HTTPAddress         string
+	HTTPPath            string
+	HTTPStateless       bool
+	HTTPHeartbeat       time.Duration
+	HTTPTimeout         time.Duration
+	HTTPShutdownTimeout time.Duration
+	HTTPCORSEnabled     bool
+	HTTPCORSOrigins     []string
+	HTTPCORSPolicy      CORSPolicy
+	HTTPSessionIdleTTL  time.Duration
+	// Sessions older than this must initialize again; 0 means no limit
+	HTTPSessionLifetime time.Duration
+	HTTPSocketMode      os.FileMode
+	HTTPAllowCIDRs      []*net.IPNet
+	HTTPDenyCIDRs       []*net.IPNet
+	HTTPTrustedProxies  []*net.IPNet
+	HTTPCompression     bool
+	HTTPREST            bool
+	HTTPAccessLog       string
+	HTTPAccessLogFile   string
+
+	// Security header settings
+	HTTPSecurityHeaders       bool
+	HTTPHSTSMaxAge            int
+	HTTPFrameOptions          string
+	HTTPContentSecurityPolicy string
+
+	// Rate limiting settings
+	HTTPRateLimitGlobal RateLimit
+	HTTPRateLimitIP     RateLimit
+	HTTPRateLimitUser   RateLimit
+
+	// State store backing quotas; see stateStore
+	Store         string
+	StoreFile     string
+	StoreRedisURL string
+	StorePrefix   string
+
+	// Per-user tool call quotas; zero disables a window
+	QuotaPerMinute int
+	QuotaPerDay    int
+
+	// Webhook and notification scheduler; see scheduler
+	SchedulerEnabled    bool
+	SchedulerMaxJobs    int
+	WebhookSecret       string
+	WebhookAllowPrivate bool
+
+	// TLS settings
+	HTTPTLSCert       string
+	HTTPTLSKey        string
+	HTTPTLSSelfSigned bool
+	HTTPTLSClientCA   string
+	HTTPTLSClientAuth string
+
+	// ACME (automatic certificate) settings
+	HTTPACMEDomains     []string
+	HTTPACMECacheDir    string
+	HTTPACMEEmail       string
+	HTTPACMEHTTPAddress string
 
 	// Authentication settings
-	AuthEnabled   bool
-	AuthSecretKey string
-	AuthIssuer    string
-	AuthAudience  string
+	AuthEnabled     bool
+	AuthSecretKey   string
+	AuthSecretKeys  string
+	AuthIssuer      string
+	AuthAudience    string
+	AuthAlgorithm   string
+	AuthPublicKey   string
+	AuthJWKSURL     string
+	AuthJWKSRefresh time.Duration
+	AuthValidation  jwtValidation
+	AuthOIDCIssuer  string
+	AuthMode        string
+	AuthAPIKeys     map[string]apiKey
+	AuthRoles       rolePolicy
+	AuthDenylist    *tokenDenylist
+	AuthAdminRole   string
+	// Tools unauthenticated callers may use when auth is enabled
+	AuthAnonymousTools []string
+
+	// Stdio authentication
+	StdioAuthToken    string
+	StdioAuthRequired bool
+
+	// Clock is the source of "now" for tool handlers; nil means the system
+	// clock. Tests install a fakeClock.
+	Clock Clock
 
 	// Timezone settings
 	DefaultTimezone string
+	TZValidate      bool
+	TZPreload       []string
+	// Sources of the HTTP client's zone, used when a tool's timezone is
+	// omitted; see clientTimezoneDetector
+	ClientTZDetect []string
+	ClientTZHeader string
+	GeoIPFile      string
+	GeoIP          *geoIPDatabase
+
+	// Deadline for each tool call; 0 disables it
+	ToolTimeout time.Duration
+	// Directory of subprocess tool plugins; see loadToolPlugins
+	ToolPluginsDir string
+	// Omit deprecated tool versions from tools/list; they stay callable
+	HideDeprecatedTools bool
+	// Result cache for deterministic tool calls; a size of 0 disables it
+	ToolCacheSize int
+	ToolCacheTTL  time.Duration
+
+	// Usage statistics persistence; an empty file keeps statistics in memory only
+	StatsFile          string
+	StatsFlushInterval time.Duration
+
+	// pprof and expvar endpoints on a separate loopback listener
+	DebugEndpoints bool
+	DebugAddress   string
+
+	// NTP servers /health compares the system clock with, and the offset
+	// beyond which the clock counts as degraded
+	HealthNTPServers   []string
+	HealthNTPMaxOffset time.Duration
+
+	// Wall clock jumps beyond which a step is reported, and whether clients
+	// are notified of it
+	ClockStepThreshold time.Duration
+	ClockStepNotify    bool
+
+	// Logging settings; LogPayloads is the percentage of JSON-RPC messages
+	// logged in full
+	LogLevel    slog.Level
+	LogFormat   string
+	LogPayloads float64
+
+	// OpenTelemetry tracing settings
+	OTelEnabled     bool
+	OTelEndpoint    string
+	OTelSampleRatio float64
+
+	// Business calendars by name
+	CalendarsFile string
+	Calendars     map[string]*businessCalendar
+
+	// Academic term schedules by name
+	TermsFile string
+	Terms     map[string]*termSchedule
+
+	// Datasets downloaded from URLs into DatasetCacheDir instead of read
+	// from local files, keyed by dataset name
+	DatasetURLs            map[string]string
+	DatasetCacheDir        string
+	DatasetRefresh         time.Duration
+	DatasetRequireChecksum bool
+
+	// Tenant profiles selected by the token's tenant claim or audience
+	TenantsFile string
+	Tenants     tenantPolicy
+
+	// Datasets follows reloads of the calendars, terms and GeoIP files;
+	// tools read them through datasets()
+	Datasets *datasetRegistry
 }
 
 // NewConfig creates a new configuration from environment variables
 func NewConfig() (*Config, error) {
+	name, version, instructions := parseServerSettings()
 	httpAddress, httpPath, httpStateless, httpHeartbeat, httpTimeout, httpSessionIdleTTL := parseHTTPSettings()
-	authEnabled, authSecretKey, authIssuer, authAudience, err := parseAuthSettings()
+	authEnabled, authSecretKey, authSecretKeys, authIssuer, authAudience, err := parseAuthSettings()
+	if err != nil {
+		return nil, err
+	}
+	authAlgorithm, authPublicKey, err := parseAuthKeySettings(authEnabled)
 	if err != nil {
 		return nil, err
 	}
+	authJWKSURL, authJWKSRefresh := parseJWKSSettings()
+	authValidation, err := parseJWTValidationSettings()
+	if err != nil {
+		return nil, err
+	}
+	authOIDCIssuer := os.Getenv("TIME_AUTH_OIDC_ISSUER")
+	authMode, authAPIKeys, err := parseAuthModeSettings(authEnabled)
+	if err != nil {
+		return nil, err
+	}
+	authRoles, err := loadRolePolicy(os.Getenv("TIME_AUTH_ROLES"), os.Getenv("TIME_AUTH_ROLES_FILE"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid role policy: %w", err)
+	}
+	authDenylist, err := parseDenylistSettings()
+	if err != nil {
+		return nil, err
+	}
+	authAdminRole := getEnvWithDefault("TIME_AUTH_ADMIN_ROLE", defaultAuthAdminRole)
+	stdioAuthToken := os.Getenv("TIME_STDIO_AUTH_TOKEN")
+	stdioAuthRequired := parseEnvBool("TIME_STDIO_AUTH_REQUIRED", false)
+	authAnonymousTools := parseHeaderList(os.Getenv("TIME_AUTH_ANONYMOUS_TOOLS"), false)
+	httpSocketMode, err := parseSocketMode()
+	if err != nil {
+		return nil, err
+	}
+	httpTrustedProxies, err := parseTrustedProxies()
+	if err != nil {
+		return nil, err
+	}
+	httpAllowCIDRs, httpDenyCIDRs, err := parseIPFilterSettings()
+	if err != nil {
+		return nil, err
+	}
+	httpShutdownTimeout := parseEnvDuration("TIME_HTTP_SHUTDOWN_TIMEOUT", defaultHTTPShutdownTimeout)
+	httpSessionLifetime := parseEnvDuration("TIME_HTTP_SESSION_MAX_LIFETIME", 0)
+	if httpHeartbeat < 0 || httpSessionIdleTTL < 0 || httpSessionLifetime < 0 {
+		return nil, fmt.Errorf("invalid session keepalive settings: TIME_HTTP_HEARTBEAT, TIME_HTTP_SESSION_IDLE_TTL and TIME_HTTP_SESSION_MAX_LIFETIME must not be negative")
+	}
+	httpCompression := parseCompressionSettings()
+	httpREST := parseEnvBool("TIME_HTTP_REST", defaultHTTPREST)
+	httpAccessLog, httpAccessLogFile, err := parseAccessLogSettings()
+	if err != nil {
+		return nil, err
+	}
+	httpRateLimitGlobal, httpRateLimitIP, httpRateLimitUser := parseRateLimitSettings()
+	store, storeFile, storeRedisURL, storePrefix, err := parseStoreSettings()
+	if err != nil {
+		return nil, err
+	}
+	quotaPerMinute := parseEnvInt("TIME_QUOTA_PER_MINUTE", 0)
+	quotaPerDay := parseEnvInt("TIME_QUOTA_PER_DAY", 0)
+	schedulerEnabled := parseEnvBool("TIME_SCHEDULER", false)
+	schedulerMaxJobs := parseEnvInt("TIME_SCHEDULER_MAX_JOBS", defaultSchedulerMaxJobs)
+	if schedulerMaxJobs < 1 {
+		return nil, fmt.Errorf("invalid TIME_SCHEDULER_MAX_JOBS: %d (must be positive)", schedulerMaxJobs)
+	}
+	webhookSecret := os.Getenv("TIME_WEBHOOK_SECRET")
+	webhookAllowPrivate := parseEnvBool("TIME_WEBHOOK_ALLOW_PRIVATE", false)
+	httpSecurityHeaders, httpHSTSMaxAge, httpFrameOptions, httpContentSecurityPolicy := parseSecurityHeaderSettings()
 	httpCORSEnabled, httpCORSOrigins, err := parseCORSSettings(authEnabled)
 	if err != nil {
 		return nil, err
 	}
+	httpCORSPolicy, err := parseCORSPolicy()
+	if err != nil {
+		return nil, err
+	}
+	httpTLSCert, httpTLSKey, httpTLSSelfSigned, err := parseTLSSettings()
+	if err != nil {
+		return nil, err
+	}
+	httpACMEDomains, httpACMECacheDir, httpACMEEmail, httpACMEHTTPAddress, err := parseACMESettings(httpTLSCert != "" || httpTLSSelfSigned)
+	if err != nil {
+		return nil, err
+	}
+	httpTLSClientCA, httpTLSClientAuth, err := parseMTLSSettings(httpTLSCert != "" || httpTLSSelfSigned || len(httpACMEDomains) > 0)
+	if err != nil {
+		return nil, err
+	}
 	defaultTimezone, err := parseTimezoneSettings()
 	if err != nil {
 		return nil, err
 	}
+	datasetURLs, datasetCacheDir, datasetRefresh, datasetRequireChecksum, err := parseDatasetFetchSettings()
+	if err != nil {
+		return nil, err
+	}
+	// Datasets are only read from the cache here; fetchDatasets downloads
+	// them at startup, so parsing the configuration has no side effects
+	fetcher := newDatasetFetcher(datasetURLs, datasetCacheDir, datasetRequireChecksum)
+	calendarsFile, calendars, err := parseCalendarSettings(fetcher)
+	if err != nil {
+		return nil, err
+	}
+	termsFile, terms, err := parseTermSettings(fetcher)
+	if err != nil {
+		return nil, err
+	}
+	// Tenant calendars not downloaded yet are checked when they are loaded
+	knownCalendars := calendars
+	if knownCalendars == nil && !fetcher.pending(datasetCalendars) {
+		knownCalendars = map[string]*businessCalendar{}
+	}
+	tenantsFile, tenants, err := parseTenantSettings(knownCalendars)
+	if err != nil {
+		return nil, err
+	}
+	clientTZDetect, clientTZHeader, geoIPFile, geoIP, err := parseClientTZSettings(fetcher)
+	if err != nil {
+		return nil, err
+	}
+	tzValidate := parseEnvBool("TIME_TZ_VALIDATE", true)
+	tzPreload := parseHeaderList(os.Getenv("TIME_TZ_PRELOAD"), false)
+	toolTimeout := parseEnvDuration("TIME_TOOL_TIMEOUT", defaultToolTimeout)
+	if toolTimeout < 0 {
+		return nil, fmt.Errorf("invalid TIME_TOOL_TIMEOUT: %s (must not be negative)", toolTimeout)
+	}
+	toolPluginsDir := os.Getenv("TIME_TOOL_PLUGINS_DIR")
+	hideDeprecatedTools := parseEnvBool("TIME_HIDE_DEPRECATED_TOOLS", false)
+	toolCacheSize, toolCacheTTL, err := parseToolCacheSettings()
+	if err != nil {
+		return nil, err
+	}
+	statsFile := os.Getenv("TIME_STATS_FILE")
+	statsFlushInterval := parseEnvDuration("TIME_STATS_FLUSH_INTERVAL", defaultStatsFlushInterval)
+	debugEndpoints, debugAddress, err := parseDebugSettings()
+	if err != nil {
+		return nil, err
+	}
+	healthNTPServers, healthNTPMaxOffset, err := parseHealthSettings()
+	if err != nil {
+		return nil, err
+	}
+	clockStepThreshold := parseEnvDuration("TIME_CLOCK_STEP_THRESHOLD", defaultClockStepThreshold)
+	if clockStepThreshold < 0 {
+		return nil, fmt.Errorf("invalid TIME_CLOCK_STEP_THRESHOLD: %s (must not be negative)", clockStepThreshold)
+	}
+	clockStepNotify := parseEnvBool("TIME_CLOCK_STEP_NOTIFY", false)
+	logLevel, logFormat, logPayloads, err := parseLogSettings()
+	if err != nil {
+		return nil, err
+	}
+	otelEnabled, otelEndpoint, otelSampleRatio, err := parseTelemetrySettings()
+	if err != nil {
+		return nil, err
+	}
 
-	return &Config{
-		HTTPAddress:        httpAddress,
-		HTTPPath:           httpPath,
-		HTTPStateless:      httpStateless,
-		HTTPHeartbeat:      httpHeartbeat,
-		HTTPTimeout:        httpTimeout,
-		HTTPCORSEnabled:    httpCORSEnabled,
-		HTTPCORSOrigins:    httpCORSOrigins,
-		HTTPSessionIdleTTL: httpSessionIdleTTL,
-		AuthEnabled:        authEnabled,
-		AuthSecretKey:      authSecretKey,
-		AuthIssuer:         authIssuer,
-		AuthAudience:       authAudience,
-		DefaultTimezone:    defaultTimezone,
-	}, nil
+	config := &Config{
+		ServerName:                name,
+		ServerVersion:             version,
+		ServerInstructions:        instructions,
+		HTTPAddress:               httpAddress,
+		HTTPPath:                  httpPath,
+		HTTPStateless:             httpStateless,
+		HTTPHeartbeat:             httpHeartbeat,
+		HTTPTimeout:               httpTimeout,
+		HTTPShutdownTimeout:       httpShutdownTimeout,
+		HTTPCORSEnabled:           httpCORSEnabled,
+		HTTPCORSOrigins:           httpCORSOrigins,
+		HTTPCORSPolicy:            httpCORSPolicy,
+		HTTPSessionIdleTTL:        httpSessionIdleTTL,
+		HTTPSessionLifetime:       httpSessionLifetime,
+		HTTPSocketMode:            httpSocketMode,
+		HTTPTrustedProxies:        httpTrustedProxies,
+		HTTPAllowCIDRs:            httpAllowCIDRs,
+		HTTPDenyCIDRs:             httpDenyCIDRs,
+		HTTPCompression:           httpCompression,
+		HTTPREST:                  httpREST,
+		HTTPAccessLog:             httpAccessLog,
+		HTTPAccessLogFile:         httpAccessLogFile,
+		HTTPSecurityHeaders:       httpSecurityHeaders,
+		HTTPHSTSMaxAge:            httpHSTSMaxAge,
+		HTTPFrameOptions:          httpFrameOptions,
+		HTTPContentSecurityPolicy: httpContentSecurityPolicy,
+		HTTPRateLimitGlobal:       httpRateLimitGlobal,
+		HTTPRateLimitIP:           httpRateLimitIP,
+		HTTPRateLimitUser:         httpRateLimitUser,
+		Store:                     store,
+		StoreFile:                 storeFile,
+		StoreRedisURL:             storeRedisURL,
+		StorePrefix:               storePrefix,
+		QuotaPerMinute:            quotaPerMinute,
+		QuotaPerDay:               quotaPerDay,
+		SchedulerEnabled:          schedulerEnabled,
+		SchedulerMaxJobs:          schedulerMaxJobs,
+		WebhookSecret:             webhookSecret,
+		WebhookAllowPrivate:       webhookAllowPrivate,
+		HTTPTLSCert:               httpTLSCert,
+		HTTPTLSKey:                httpTLSKey,
+		HTTPTLSSelfSigned:         httpTLSSelfSigned,
+		HTTPTLSClientCA:           httpTLSClientCA,
+		HTTPTLSClientAuth:         httpTLSClientAuth,
+		HTTPACMEDomains:           httpACMEDomains,
+		HTTPACMECacheDir:          httpACMECacheDir,
+		HTTPACMEEmail:             httpACMEEmail,
+		HTTPACMEHTTPAddress:       httpACMEHTTPAddress,
+		AuthEnabled:               authEnabled,
+		AuthSecretKey:             authSecretKey,
+		AuthSecretKeys:            authSecretKeys,
+		AuthIssuer:                authIssuer,
+		AuthAudience:              authAudience,
+		AuthAlgorithm:             authAlgorithm,
+		AuthPublicKey:             authPublicKey,
+		AuthJWKSURL:               authJWKSURL,
+		AuthJWKSRefresh:           authJWKSRefresh,
+		AuthValidation:            authValidation,
+		AuthOIDCIssuer:            authOIDCIssuer,
+		AuthMode:                  authMode,
+		AuthAPIKeys:               authAPIKeys,
+		AuthRoles:                 authRoles,
+		AuthDenylist:              authDenylist,
+		AuthAdminRole:             authAdminRole,
+		AuthAnonymousTools:        authAnonymousTools,
+		StdioAuthToken:            stdioAuthToken,
+		StdioAuthRequired:         stdioAuthRequired,
+		Clock:                     systemClock{},
+		DefaultTimezone:           defaultTimezone,
+		TZValidate:                tzValidate,
+		TZPreload:                 tzPreload,
+		ClientTZDetect:            clientTZDetect,
+		ClientTZHeader:            clientTZHeader,
+		GeoIPFile:                 geoIPFile,
+		GeoIP:                     geoIP,
+		ToolTimeout:               toolTimeout,
+		ToolPluginsDir:            toolPluginsDir,
+		HideDeprecatedTools:       hideDeprecatedTools,
+		ToolCacheSize:             toolCacheSize,
+		ToolCacheTTL:              toolCacheTTL,
+		StatsFile:                 statsFile,
+		StatsFlushInterval:        statsFlushInterval,
+		DebugEndpoints:            debugEndpoints,
+		DebugAddress:              debugAddress,
+		HealthNTPServers:          healthNTPServers,
+		HealthNTPMaxOffset:        healthNTPMaxOffset,
+		ClockStepThreshold:        clockStepThreshold,
+		ClockStepNotify:           clockStepNotify,
+		LogLevel:                  logLevel,
+		LogFormat:                 logFormat,
+		LogPayloads:               logPayloads,
+		OTelEnabled:               otelEnabled,
+		OTelEndpoint:              otelEndpoint,
+		OTelSampleRatio:           otelSampleRatio,
+		CalendarsFile:             calendarsFile,
+		Calendars:                 calendars,
+		TermsFile:                 termsFile,
+		Terms:                     terms,
+		DatasetURLs:               datasetURLs,
+		DatasetCacheDir:           datasetCacheDir,
+		DatasetRefresh:            datasetRefresh,
+		DatasetRequireChecksum:    datasetRequireChecksum,
+		TenantsFile:               tenantsFile,
+		Tenants:                   tenants,
+	}
+	config.Datasets = newDatasetRegistry(config)
+	return config, nil
+}
+
+func parseServerSettings() (string, string, string) {
+	name := getEnvWithDefault("TIME_SERVER_NAME", serverName)
+	version := getEnvWithDefault("TIME_SERVER_VERSION", serverVersion)
+	instructions := getEnvWithDefault("TIME_SERVER_INSTRUCTIONS", serverInstructions)
+	return name, version, instructions
 }
 
 func parseHTTPSettings() (string, string, bool, time.Duration, time.Duration, time.Duration) {
@@ -94,19 +536,210 @@ func parseHTTPSettings() (string, string, bool, time.Duration, time.Duration, ti
 	return httpAddress, httpPath, httpStateless, httpHeartbeat, httpTimeout, httpSessionIdleTTL
 }
 
-func parseAuthSettings() (bool, string, string, string, error) {
+func parseCompressionSettings() bool {
+	return parseEnvBool("TIME_HTTP_COMPRESSION", defaultHTTPCompression)
+}
+
+func parseAccessLogSettings() (string, string, error) {
+	format := strings.ToLower(getEnvWithDefault("TIME_HTTP_ACCESS_LOG", accessLogOff))
+	if !accessLogFormats[format] {
+		return "", "", fmt.Errorf("invalid TIME_HTTP_ACCESS_LOG: %q (expected off, common, combined or json)", format)
+	}
+	return format, os.Getenv("TIME_HTTP_ACCESS_LOG_FILE"), nil
+}
+
+func parseStoreSettings() (string, string, string, string, error) {
+	store := strings.ToLower(getEnvWithDefault("TIME_STORE", storeMemory))
+	file := os.Getenv("TIME_STORE_FILE")
+	redisURL := os.Getenv("TIME_STORE_REDIS_URL")
+	prefix := getEnvWithDefault("TIME_STORE_PREFIX", defaultStorePrefix)
+	switch store {
+	case storeMemory:
+	case storeFile:
+		if file == "" {
+			return "", "", "", "", fmt.Errorf("TIME_STORE=file requires TIME_STORE_FILE")
+		}
+	case storeRedis:
+		if redisURL == "" {
+			return "", "", "", "", fmt.Errorf("TIME_STORE=redis requires TIME_STORE_REDIS_URL")
+		}
+	default:
+		return "", "", "", "", fmt.Errorf("invalid TIME_STORE: %q (expected memory, file or redis)", store)
+	}
+	return store, file, redisURL, prefix, nil
+}
+
+func parseSocketMode() (os.FileMode, error) {
+	str := os.Getenv("TIME_HTTP_SOCKET_MODE")
+	if str == "" {
+		return defaultHTTPSocketMode, nil
+	}
+	mode, err := strconv.ParseUint(str, 8, 32)
+	if err != nil || mode > 0o777 {
+		return 0, fmt.Errorf("invalid TIME_HTTP_SOCKET_MODE: %q (expected octal permissions like 0660)", str)
+	}
+	return os.FileMode(mode), nil
+}
+
+func parseTrustedProxies() ([]*net.IPNet, error) {
+	proxies, err := parseCIDRList(os.Getenv("TIME_HTTP_TRUSTED_PROXIES"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid TIME_HTTP_TRUSTED_PROXIES: %w", err)
+	}
+	return proxies, nil
+}
+
+func parseIPFilterSettings() ([]*net.IPNet, []*net.IPNet, error) {
+	allow, err := parseCIDRList(os.Getenv("TIME_HTTP_ALLOW_CIDRS"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid TIME_HTTP_ALLOW_CIDRS: %w", err)
+	}
+	deny, err := parseCIDRList(os.Getenv("TIME_HTTP_DENY_CIDRS"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid TIME_HTTP_DENY_CIDRS: %w", err)
+	}
+	return allow, deny, nil
+}
+
+func parseSecurityHeaderSettings() (bool, int, string, string) {
+	enabled := parseEnvBool("TIME_HTTP_SECURITY_HEADERS", defaultHTTPSecurityHeaders)
+	hstsMaxAge := parseEnvInt("TIME_HTTP_HSTS_MAX_AGE", defaultHTTPHSTSMaxAge)
+	frameOptions := defaultHTTPFrameOptions
+	if v, ok := os.LookupEnv("TIME_HTTP_FRAME_OPTIONS"); ok {
+		frameOptions = v
+	}
+	csp := defaultHTTPContentSecurityPolicy
+	if v, ok := os.LookupEnv("TIME_HTTP_CSP"); ok {
+		csp = v
+	}
+	return enabled, hstsMaxAge, frameOptions, csp
+}
+
+func parseRateLimitSettings() (RateLimit, RateLimit, RateLimit) {
+	parse := func(scope string) RateLimit {
+		return RateLimit{
+			Rate:  parseEnvFloat("TIME_HTTP_RATE_LIMIT_"+scope+"_RPS", 0),
+			Burst: parseEnvInt("TIME_HTTP_RATE_LIMIT_"+scope+"_BURST", 0),
+		}
+	}
+	return parse("GLOBAL"), parse("IP"), parse("USER")
+}
+
+func parseAuthSettings() (bool, string, string, string, string, error) {
 	authEnabled := parseEnvBool("TIME_AUTH_ENABLED", defaultAuthEnabled)
 	authSecretKey := os.Getenv("TIME_AUTH_SECRET_KEY")
+	authSecretKeys := os.Getenv("TIME_AUTH_SECRET_KEYS")
 	authIssuer := getEnvWithDefault("TIME_AUTH_ISSUER", defaultAuthIssuer)
 	authAudience := getEnvWithDefault("TIME_AUTH_AUDIENCE", defaultAuthAudience)
 
-	if authEnabled && authSecretKey == "" {
-		return false, "", "", "", fmt.Errorf("TIME_AUTH_SECRET_KEY is required when TIME_AUTH_ENABLED=true")
+	usesSecret := authUsesSharedSecret()
+	if authSecretKey != "" && authSecretKeys != "" {
+		return false, "", "", "", "", fmt.Errorf("set TIME_AUTH_SECRET_KEY or TIME_AUTH_SECRET_KEYS, not both; list the current secret in the keyring when rotating")
+	}
+	if authEnabled && usesSecret && authSecretKey == "" && authSecretKeys == "" {
+		return false, "", "", "", "", fmt.Errorf("TIME_AUTH_SECRET_KEY or TIME_AUTH_SECRET_KEYS is required when TIME_AUTH_ENABLED=true")
+	}
+	keys, err := parseSecretKeys(authSecretKey, authSecretKeys)
+	if err != nil {
+		return false, "", "", "", "", fmt.Errorf("invalid TIME_AUTH_SECRET_KEYS: %w", err)
 	}
-	if authEnabled && len(authSecretKey) < 32 {
-		fmt.Fprintf(os.Stderr, "[WARN] TIME_AUTH_SECRET_KEY should be at least 32 characters for security\n")
+	for _, key := range keys {
+		if authEnabled && usesSecret && len(key.Secret) < 32 {
+			slog.Warn("Auth secret keys should be at least 32 characters for security")
+			break
+		}
+	}
+	return authEnabled, authSecretKey, authSecretKeys, authIssuer, authAudience, nil
+}
+
+// authUsesKeySet reports whether tokens are verified against a remote key set
+func authUsesKeySet() bool {
+	return os.Getenv("TIME_AUTH_JWKS_URL") != "" || os.Getenv("TIME_AUTH_OIDC_ISSUER") != ""
+}
+
+// authUsesSharedSecret reports whether tokens are verified with TIME_AUTH_SECRET_KEY(S)
+func authUsesSharedSecret() bool {
+	return getEnvWithDefault("TIME_AUTH_MODE", defaultAuthMode) == authModeJWT &&
+		getEnvWithDefault("TIME_AUTH_ALGORITHM", defaultAuthAlgorithm) == algHS256 && !authUsesKeySet()
+}
+
+func parseAuthModeSettings(authEnabled bool) (string, map[string]apiKey, error) {
+	mode := strings.ToLower(getEnvWithDefault("TIME_AUTH_MODE", defaultAuthMode))
+	switch mode {
+	case authModeJWT:
+		return mode, nil, nil
+	case authModeAPIKey:
+	default:
+		return "", nil, fmt.Errorf("invalid TIME_AUTH_MODE: %q (expected 'jwt' or 'apikey')", mode)
+	}
+
+	keys, err := loadAPIKeys(os.Getenv("TIME_AUTH_API_KEYS"), os.Getenv("TIME_AUTH_API_KEYS_FILE"))
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid API keys: %w", err)
+	}
+	if authEnabled && len(keys) == 0 {
+		return "", nil, fmt.Errorf("TIME_AUTH_API_KEYS or TIME_AUTH_API_KEYS_FILE is required when TIME_AUTH_MODE=apikey")
+	}
+	return mode, keys, nil
+}
+
+func parseAuthKeySettings(authEnabled bool) (string, string, error) {
+	algorithm := getEnvWithDefault("TIME_AUTH_ALGORITHM", defaultAuthAlgorithm)
+	publicKey := os.Getenv("TIME_AUTH_PUBLIC_KEY")
+
+	if !isSupportedAlgorithm(algorithm) {
+		return "", "", fmt.Errorf("invalid TIME_AUTH_ALGORITHM: %q (expected HS256, RS256, ES256 or EdDSA)", algorithm)
+	}
+	if algorithm != algHS256 && !authUsesKeySet() {
+		if authEnabled && publicKey == "" {
+			return "", "", fmt.Errorf("TIME_AUTH_PUBLIC_KEY is required when TIME_AUTH_ALGORITHM=%s", algorithm)
+		}
+		if publicKey != "" {
+			if _, err := loadPublicKey(publicKey, algorithm); err != nil {
+				return "", "", fmt.Errorf("invalid TIME_AUTH_PUBLIC_KEY: %w", err)
+			}
+		}
 	}
-	return authEnabled, authSecretKey, authIssuer, authAudience, nil
+	return algorithm, publicKey, nil
+}
+
+func parseJWKSSettings() (string, time.Duration) {
+	jwksURL := os.Getenv("TIME_AUTH_JWKS_URL")
+	refresh := parseEnvDuration("TIME_AUTH_JWKS_REFRESH", defaultAuthJWKSRefresh)
+	return jwksURL, refresh
+}
+
+func parseJWTValidationSettings() (jwtValidation, error) {
+	algorithms, err := parseAlgorithmList(os.Getenv("TIME_AUTH_ALGORITHMS"))
+	if err != nil {
+		return jwtValidation{}, fmt.Errorf("invalid TIME_AUTH_ALGORITHMS: %w", err)
+	}
+	requiredClaims, err := parseRequiredClaims(getEnvWithDefault("TIME_AUTH_REQUIRED_CLAIMS", defaultAuthRequiredClaims))
+	if err != nil {
+		return jwtValidation{}, fmt.Errorf("invalid TIME_AUTH_REQUIRED_CLAIMS: %w", err)
+	}
+	return jwtValidation{
+		leeway:         parseEnvDuration("TIME_AUTH_LEEWAY", defaultAuthLeeway),
+		algorithms:     algorithms,
+		requiredClaims: requiredClaims,
+		skipIssuer:     !parseEnvBool("TIME_AUTH_VERIFY_ISSUER", true),
+		skipAudience:   !parseEnvBool("TIME_AUTH_VERIFY_AUDIENCE", true),
+	}, nil
+}
+
+// parseDenylistSettings returns nil when no revocation source is configured
+func parseDenylistSettings() (*tokenDenylist, error) {
+	file := os.Getenv("TIME_AUTH_DENYLIST_FILE")
+	url := os.Getenv("TIME_AUTH_DENYLIST_URL")
+	if file == "" && url == "" {
+		return nil, nil
+	}
+	refresh := parseEnvDuration("TIME_AUTH_DENYLIST_REFRESH", defaultAuthDenylistTTL)
+	denylist, err := newTokenDenylist(file, url, refresh)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TIME_AUTH_DENYLIST_FILE: %w", err)
+	}
+	return denylist, nil
 }
 
 func parseCORSOrigins(originsStr string) []string {
@@ -141,7 +774,7 @@ func parseCORSOrigins(originsStr string) []string {
 			if err == nil && u.Host != "" {
 				addOrigin(u.Host)
 			} else {
-				fmt.Fprintf(os.Stderr, "[WARN] Invalid CORS origin URL: %q (skipping)\n", trimmed)
+				slog.Warn("Invalid CORS origin URL, skipping", "origin", trimmed)
 			}
 			continue
 		}
@@ -166,18 +799,215 @@ func parseCORSSettings(authEnabled bool) (bool, []string, error) {
 	return httpCORSEnabled, httpCORSOrigins, nil
 }
 
+func parseCORSPolicy() (CORSPolicy, error) {
+	routes, err := parseCORSRouteMethods(getEnvWithDefault("TIME_HTTP_CORS_ROUTES", defaultHTTPCORSRoutes))
+	if err != nil {
+		return CORSPolicy{}, fmt.Errorf("invalid TIME_HTTP_CORS_ROUTES: %w", err)
+	}
+
+	exposeHeaders := defaultHTTPCORSExpose
+	if v, ok := os.LookupEnv("TIME_HTTP_CORS_EXPOSE_HEADERS"); ok {
+		exposeHeaders = v
+	}
+
+	return CORSPolicy{
+		Methods:          parseHeaderList(getEnvWithDefault("TIME_HTTP_CORS_METHODS", defaultHTTPCORSMethods), true),
+		Headers:          parseHeaderList(getEnvWithDefault("TIME_HTTP_CORS_HEADERS", defaultHTTPCORSHeaders), false),
+		ExposeHeaders:    parseHeaderList(exposeHeaders, false),
+		AllowCredentials: parseEnvBool("TIME_HTTP_CORS_ALLOW_CREDENTIALS", false),
+		MaxAge:           parseEnvDuration("TIME_HTTP_CORS_MAX_AGE", defaultHTTPCORSMaxAge),
+		RouteMethods:     routes,
+	}, nil
+}
+
+func parseTLSSettings() (string, string, bool, error) {
+	certFile := os.Getenv("TIME_HTTP_TLS_CERT")
+	keyFile := os.Getenv("TIME_HTTP_TLS_KEY")
+	selfSigned := parseEnvBool("TIME_HTTP_TLS_SELF_SIGNED", defaultHTTPTLSSelfSigned)
+
+	if (certFile == "") != (keyFile == "") {
+		return "", "", false, fmt.Errorf("TIME_HTTP_TLS_CERT and TIME_HTTP_TLS_KEY must be set together")
+	}
+	if selfSigned && certFile != "" {
+		slog.Warn("TIME_HTTP_TLS_SELF_SIGNED ignored because TIME_HTTP_TLS_CERT is set")
+		selfSigned = false
+	}
+	if selfSigned {
+		slog.Warn("Using a generated self-signed TLS certificate; do not use in production")
+	}
+	return certFile, keyFile, selfSigned, nil
+}
+
+func parseACMESettings(staticTLS bool) ([]string, string, string, string, error) {
+	var domains []string
+	for _, d := range strings.Split(os.Getenv("TIME_HTTP_ACME_DOMAINS"), ",") {
+		if d = strings.TrimSpace(d); d != "" {
+			domains = append(domains, d)
+		}
+	}
+	cacheDir := getEnvWithDefault("TIME_HTTP_ACME_CACHE_DIR", defaultHTTPACMECacheDir)
+	email := os.Getenv("TIME_HTTP_ACME_EMAIL")
+	httpAddress := os.Getenv("TIME_HTTP_ACME_HTTP_ADDRESS")
+
+	if len(domains) == 0 {
+		return nil, "", "", "", nil
+	}
+	if staticTLS {
+		return nil, "", "", "", fmt.Errorf("TIME_HTTP_ACME_DOMAINS cannot be combined with TIME_HTTP_TLS_CERT or TIME_HTTP_TLS_SELF_SIGNED")
+	}
+	return domains, cacheDir, email, httpAddress, nil
+}
+
+func parseMTLSSettings(tlsEnabled bool) (string, string, error) {
+	clientCA := os.Getenv("TIME_HTTP_TLS_CLIENT_CA")
+	clientAuth := strings.ToLower(getEnvWithDefault("TIME_HTTP_TLS_CLIENT_AUTH", defaultHTTPTLSClientAuth))
+
+	if clientCA == "" {
+		return "", "", nil
+	}
+	if !tlsEnabled {
+		return "", "", fmt.Errorf("TIME_HTTP_TLS_CLIENT_CA requires TLS (TIME_HTTP_TLS_CERT/TIME_HTTP_TLS_KEY or TIME_HTTP_TLS_SELF_SIGNED)")
+	}
+	if _, ok := clientAuthModes[clientAuth]; !ok {
+		return "", "", fmt.Errorf("invalid TIME_HTTP_TLS_CLIENT_AUTH: %q (expected 'require' or 'verify_if_given')", clientAuth)
+	}
+	return clientCA, clientAuth, nil
+}
+
 func parseTimezoneSettings() (string, error) {
 	defaultTimezone := getEnvWithDefault("TIME_DEFAULT_TIMEZONE", defaultTimezone)
 
 	if defaultTimezone != "" {
-		if _, err := time.LoadLocation(defaultTimezone); err != nil {
-			return "", fmt.Errorf("invalid TIME_DEFAULT_TIMEZONE: %s (%v)", defaultTimezone, err)
+		if _, err := loadLocationCached(defaultTimezone); err != nil {
+			return "", fmt.Errorf("invalid TIME_DEFAULT_TIMEZONE: %s (%v)%s", defaultTimezone, err, didYouMean(suggestTimezones(defaultTimezone)))
 		}
-		fmt.Fprintf(os.Stderr, "[INFO] Using default timezone: %s\n", defaultTimezone)
+		slog.Info("Using default timezone", "timezone", defaultTimezone)
 	}
 	return defaultTimezone, nil
 }
 
+func parseDebugSettings() (bool, string, error) {
+	enabled := parseEnvBool("TIME_DEBUG_ENDPOINTS", false)
+	address := getEnvWithDefault("TIME_DEBUG_ADDRESS", defaultDebugAddress)
+	if enabled && !isLoopbackAddress(address) {
+		return false, "", fmt.Errorf("invalid TIME_DEBUG_ADDRESS: %q (debug endpoints must bind to a loopback address such as 127.0.0.1:6060)", address)
+	}
+	return enabled, address, nil
+}
+
+func parseHealthSettings() ([]string, time.Duration, error) {
+	servers := parseHeaderList(os.Getenv("TIME_HEALTH_NTP_SERVERS"), false)
+	maxOffset := parseEnvDuration("TIME_HEALTH_NTP_MAX_OFFSET", defaultHealthNTPMaxOffset)
+	if maxOffset <= 0 {
+		return nil, 0, fmt.Errorf("invalid TIME_HEALTH_NTP_MAX_OFFSET: %s (must be positive)", maxOffset)
+	}
+	return servers, maxOffset, nil
+}
+
+func parseToolCacheSettings() (int, time.Duration, error) {
+	size := parseEnvInt("TIME_TOOL_CACHE_SIZE", defaultToolCacheSize)
+	if size < 0 {
+		return 0, 0, fmt.Errorf("invalid TIME_TOOL_CACHE_SIZE: %d (must not be negative)", size)
+	}
+	ttl := parseEnvDuration("TIME_TOOL_CACHE_TTL", defaultToolCacheTTL)
+	if ttl < 0 {
+		return 0, 0, fmt.Errorf("invalid TIME_TOOL_CACHE_TTL: %s (must not be negative)", ttl)
+	}
+	return size, ttl, nil
+}
+
+func parseLogSettings() (slog.Level, string, float64, error) {
+	level, err := parseLogLevel(os.Getenv("TIME_LOG_LEVEL"))
+	if err != nil {
+		return 0, "", 0, fmt.Errorf("invalid TIME_LOG_LEVEL: %w", err)
+	}
+	format := strings.ToLower(getEnvWithDefault("TIME_LOG_FORMAT", logFormatText))
+	if format != logFormatText && format != logFormatJSON {
+		return 0, "", 0, fmt.Errorf("invalid TIME_LOG_FORMAT: %q (expected text or json)", format)
+	}
+	payloads := parseEnvFloat("TIME_LOG_PAYLOADS", 0)
+	if payloads < 0 || payloads > 100 {
+		return 0, "", 0, fmt.Errorf("invalid TIME_LOG_PAYLOADS: %g (must be a percentage between 0 and 100)", payloads)
+	}
+	return level, format, payloads, nil
+}
+
+func parseTelemetrySettings() (bool, string, float64, error) {
+	enabled := parseEnvBool("TIME_OTEL_ENABLED", false)
+	endpoint := os.Getenv("TIME_OTEL_ENDPOINT")
+	if endpoint != "" {
+		if u, err := url.Parse(endpoint); err != nil || u.Scheme == "" || u.Host == "" {
+			return false, "", 0, fmt.Errorf("invalid TIME_OTEL_ENDPOINT: %q (expected a URL such as http://collector:4318)", endpoint)
+		}
+	}
+	ratio := parseEnvFloat("TIME_OTEL_SAMPLE_RATIO", defaultOTelSampleRatio)
+	if ratio < 0 || ratio > 1 {
+		return false, "", 0, fmt.Errorf("invalid TIME_OTEL_SAMPLE_RATIO: %g (must be between 0 and 1)", ratio)
+	}
+	return enabled, endpoint, ratio, nil
+}
+
+func parseCalendarSettings(fetcher *datasetFetcher) (string, map[string]*businessCalendar, error) {
+	calendarsFile := fetcher.source(datasetCalendars, os.Getenv("TIME_CALENDARS_FILE"))
+	if calendarsFile == "" || fetcher.pending(datasetCalendars) {
+		return calendarsFile, nil, nil
+	}
+	calendars, err := loadCalendars(calendarsFile)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid TIME_CALENDARS_FILE: %w", err)
+	}
+	return calendarsFile, calendars, nil
+}
+
+func parseTermSettings(fetcher *datasetFetcher) (string, map[string]*termSchedule, error) {
+	termsFile := fetcher.source(datasetTerms, os.Getenv("TIME_TERMS_FILE"))
+	if termsFile == "" || fetcher.pending(datasetTerms) {
+		return termsFile, nil, nil
+	}
+	terms, err := loadTermSchedules(termsFile)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid TIME_TERMS_FILE: %w", err)
+	}
+	return termsFile, terms, nil
+}
+
+// parseDatasetFetchSettings reads the dataset URLs. A dataset comes from
+// either a file or a URL, not both.
+func parseDatasetFetchSettings() (map[string]string, string, time.Duration, bool, error) {
+	files := map[string]string{datasetCalendars: "TIME_CALENDARS_FILE", datasetTerms: "TIME_TERMS_FILE", datasetGeoIP: "TIME_GEOIP_DB"}
+	var urls map[string]string
+	for name, variable := range datasetURLVariables {
+		raw := os.Getenv(variable)
+		if raw == "" {
+			continue
+		}
+		u, err := url.Parse(raw)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, "", 0, false, fmt.Errorf("invalid %s: %q (expected an http or https URL)", variable, raw)
+		}
+		if os.Getenv(files[name]) != "" {
+			return nil, "", 0, false, fmt.Errorf("%s and %s are mutually exclusive", files[name], variable)
+		}
+		if urls == nil {
+			urls = make(map[string]string)
+		}
+		urls[name] = raw
+	}
+	cacheDir := os.Getenv("TIME_DATASET_CACHE_DIR")
+	if cacheDir == "" {
+		base, err := os.UserCacheDir()
+		if err != nil {
+			base = os.TempDir()
+		}
+		cacheDir = filepath.Join(base, "timemcp", "datasets")
+	}
+	refresh := parseEnvDuration("TIME_DATASET_REFRESH", defaultDatasetRefresh)
+	if refresh < 0 {
+		return nil, "", 0, false, fmt.Errorf("invalid TIME_DATASET_REFRESH: %s (must not be negative)", refresh)
+	}
+	return urls, cacheDir, refresh, parseEnvBool("TIME_DATASET_REQUIRE_CHECKSUM", false), nil
+}
+
 // Helper functions for parsing environment variables
 
 func getEnvWithDefault(key, defaultValue string) string {
@@ -192,7 +1022,27 @@ func parseEnvBool(key string, defaultValue bool) bool {
 		if val, err := strconv.ParseBool(str); err == nil {
 			return val
 		}
-		fmt.Fprintf(os.Stderr, "[WARN] Invalid boolean value for %s: %q. Using default: %t\n", key, str, defaultValue)
+		slog.Warn("Invalid boolean value, using default", "variable", key, "value", str, "default", defaultValue)
+	}
+	return defaultValue
+}
+
+func parseEnvInt(key string, defaultValue int) int {
+	if str := os.Getenv(key); str != "" {
+		if val, err := strconv.Atoi(str); err == nil {
+			return val
+		}
+		slog.Warn("Invalid integer value, using default", "variable", key, "value", str, "default", defaultValue)
+	}
+	return defaultValue
+}
+
+func parseEnvFloat(key string, defaultValue float64) float64 {
+	if str := os.Getenv(key); str != "" {
+		if val, err := strconv.ParseFloat(str, 64); err == nil {
+			return val
+		}
+		slog.Warn("Invalid number value, using default", "variable", key, "value", str, "default", defaultValue)
 	}
 	return defaultValue
 }
@@ -202,7 +1052,7 @@ func parseEnvDuration(key string, defaultValue time.Duration) time.Duration {
 		if val, err := time.ParseDuration(str); err == nil {
 			return val
 		}
-		fmt.Fprintf(os.Stderr, "[WARN] Invalid duration value for %s: %q. Using default: %s\n", key, str, defaultValue.String())
+		slog.Warn("Invalid duration value, using default", "variable", key, "value", str, "default", defaultValue.String())
 	}
 	return defaultValue
 }
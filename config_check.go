@@ -0,0 +1,224 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"slices"
+	"strings"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// maskedValue replaces secrets in printed configuration
+const maskedValue = "********"
+
+// validateConfig runs the checks NewConfig leaves to server startup: TLS
+// material must load and the auth middleware must build. OIDC discovery is
+// skipped because it needs the identity provider to be reachable.
+func validateConfig(config *Config) error {
+	if tlsEnabled(config) {
+		var manager *autocert.Manager
+		if acmeEnabled(config) {
+			manager = newACMEManager(config)
+		}
+		if _, err := newTLSConfig(config, manager); err != nil {
+			return fmt.Errorf("invalid TLS configuration: %w", err)
+		}
+	}
+	if config.AuthEnabled && config.AuthOIDCIssuer == "" {
+		if _, err := NewAuthMiddlewareFromConfig(config); err != nil {
+			return fmt.Errorf("invalid auth configuration: %w", err)
+		}
+	}
+	if err := prepareTimezones(config); err != nil {
+		return err
+	}
+	if _, isSocket := unixSocketPath(config.HTTPAddress); !isSocket {
+		if _, _, err := net.SplitHostPort(config.HTTPAddress); err != nil {
+			return fmt.Errorf("invalid TIME_HTTP_ADDRESS: %w", err)
+		}
+	}
+	return nil
+}
+
+// effectiveConfig describes the resolved configuration keyed by TIME_* variable
+// name, with secrets masked
+func effectiveConfig(config *Config) map[string]any {
+	mask := func(secret string) string {
+		if secret == "" {
+			return ""
+		}
+		return maskedValue
+	}
+	cidrs := func(nets []*net.IPNet) []string {
+		list := make([]string, 0, len(nets))
+		for _, n := range nets {
+			list = append(list, n.String())
+		}
+		return list
+	}
+
+	apiKeys := make([]string, 0, len(config.AuthAPIKeys))
+	for _, key := range config.AuthAPIKeys {
+		apiKeys = append(apiKeys, key.Name+":"+key.Role)
+	}
+	slices.Sort(apiKeys)
+
+	var roles []string
+	for role, tools := range config.AuthRoles {
+		names := make([]string, 0, len(tools))
+		for tool := range tools {
+			names = append(names, tool)
+		}
+		slices.Sort(names)
+		roles = append(roles, role+":"+strings.Join(names, ","))
+	}
+	slices.Sort(roles)
+
+	var routes []string
+	for prefix, methods := range config.HTTPCORSPolicy.RouteMethods {
+		routes = append(routes, prefix+"="+strings.Join(methods, ", "))
+	}
+	slices.Sort(routes)
+
+	denylistFile, denylistURL := "", ""
+	if config.AuthDenylist != nil {
+		denylistFile, denylistURL = config.AuthDenylist.file, config.AuthDenylist.url
+	}
+
+	return map[string]any{
+		"TIME_SERVER_NAME":                 config.ServerName,
+		"TIME_SERVER_VERSION":              config.ServerVersion,
+		"TIME_SERVER_INSTRUCTIONS":         config.ServerInstructions,
+		"TIME_DEFAULT_TIMEZONE":            config.DefaultTimezone,
+		"TIME_TZ_VALIDATE":                 config.TZValidate,
+		"TIME_TZ_PRELOAD":                  config.TZPreload,
+		"TIME_CLIENT_TZ_DETECT":            config.ClientTZDetect,
+		"TIME_CLIENT_TZ_HEADER":            config.ClientTZHeader,
+		"TIME_GEOIP_DB":                    config.GeoIPFile,
+		"TIME_HTTP_ADDRESS":                config.HTTPAddress,
+		"TIME_HTTP_PATH":                   config.HTTPPath,
+		"TIME_HTTP_STATELESS":              config.HTTPStateless,
+		"TIME_HTTP_HEARTBEAT":              config.HTTPHeartbeat.String(),
+		"TIME_HTTP_TIMEOUT":                config.HTTPTimeout.String(),
+		"TIME_HTTP_SHUTDOWN_TIMEOUT":       config.HTTPShutdownTimeout.String(),
+		"TIME_HTTP_SESSION_IDLE_TTL":       config.HTTPSessionIdleTTL.String(),
+		"TIME_HTTP_SESSION_MAX_LIFETIME":   config.HTTPSessionLifetime.String(),
+		"TIME_HTTP_SOCKET_MODE":            fmt.Sprintf("%#o", config.HTTPSocketMode),
+		"TIME_HTTP_COMPRESSION":            config.HTTPCompression,
+		"TIME_HTTP_REST":                   config.HTTPREST,
+		"TIME_HTTP_ACCESS_LOG":             config.HTTPAccessLog,
+		"TIME_HTTP_ACCESS_LOG_FILE":        config.HTTPAccessLogFile,
+		"TIME_HTTP_TRUSTED_PROXIES":        cidrs(config.HTTPTrustedProxies),
+		"TIME_HTTP_ALLOW_CIDRS":            cidrs(config.HTTPAllowCIDRs),
+		"TIME_HTTP_DENY_CIDRS":             cidrs(config.HTTPDenyCIDRs),
+		"TIME_HTTP_SECURITY_HEADERS":       config.HTTPSecurityHeaders,
+		"TIME_HTTP_HSTS_MAX_AGE":           config.HTTPHSTSMaxAge,
+		"TIME_HTTP_FRAME_OPTIONS":          config.HTTPFrameOptions,
+		"TIME_HTTP_CSP":                    config.HTTPContentSecurityPolicy,
+		"TIME_HTTP_CORS_ENABLED":           config.HTTPCORSEnabled,
+		"TIME_HTTP_CORS_ORIGINS":           config.HTTPCORSOrigins,
+		"TIME_HTTP_CORS_METHODS":           config.HTTPCORSPolicy.Methods,
+		"TIME_HTTP_CORS_HEADERS":           config.HTTPCORSPolicy.Headers,
+		"TIME_HTTP_CORS_EXPOSE_HEADERS":    config.HTTPCORSPolicy.ExposeHeaders,
+		"TIME_HTTP_CORS_ALLOW_CREDENTIALS": config.HTTPCORSPolicy.AllowCredentials,
+		"TIME_HTTP_CORS_MAX_AGE":           config.HTTPCORSPolicy.MaxAge.String(),
+		"TIME_HTTP_CORS_ROUTES":            routes,
+		"TIME_HTTP_RATE_LIMIT_GLOBAL":      config.HTTPRateLimitGlobal,
+		"TIME_HTTP_RATE_LIMIT_IP":          config.HTTPRateLimitIP,
+		"TIME_HTTP_RATE_LIMIT_USER":        config.HTTPRateLimitUser,
+		"TIME_HTTP_TLS_CERT":               config.HTTPTLSCert,
+		"TIME_HTTP_TLS_KEY":                config.HTTPTLSKey,
+		"TIME_HTTP_TLS_SELF_SIGNED":        config.HTTPTLSSelfSigned,
+		"TIME_HTTP_TLS_CLIENT_CA":          config.HTTPTLSClientCA,
+		"TIME_HTTP_TLS_CLIENT_AUTH":        config.HTTPTLSClientAuth,
+		"TIME_HTTP_ACME_DOMAINS":           config.HTTPACMEDomains,
+		"TIME_HTTP_ACME_CACHE_DIR":         config.HTTPACMECacheDir,
+		"TIME_HTTP_ACME_EMAIL":             config.HTTPACMEEmail,
+		"TIME_HTTP_ACME_HTTP_ADDRESS":      config.HTTPACMEHTTPAddress,
+		"TIME_STORE":                       config.Store,
+		"TIME_STORE_FILE":                  config.StoreFile,
+		"TIME_STORE_REDIS_URL":             redactURLPassword(config.StoreRedisURL),
+		"TIME_STORE_PREFIX":                config.StorePrefix,
+		"TIME_QUOTA_PER_MINUTE":            config.QuotaPerMinute,
+		"TIME_QUOTA_PER_DAY":               config.QuotaPerDay,
+		"TIME_SCHEDULER":                   config.SchedulerEnabled,
+		"TIME_SCHEDULER_MAX_JOBS":          config.SchedulerMaxJobs,
+		"TIME_WEBHOOK_SECRET":              mask(config.WebhookSecret),
+		"TIME_WEBHOOK_ALLOW_PRIVATE":       config.WebhookAllowPrivate,
+		"TIME_TOOL_TIMEOUT":                config.ToolTimeout.String(),
+		"TIME_TOOL_PLUGINS_DIR":            config.ToolPluginsDir,
+		"TIME_HIDE_DEPRECATED_TOOLS":       config.HideDeprecatedTools,
+		"TIME_TOOL_CACHE_SIZE":             config.ToolCacheSize,
+		"TIME_TOOL_CACHE_TTL":              config.ToolCacheTTL.String(),
+		"TIME_AUTH_ENABLED":                config.AuthEnabled,
+		"TIME_AUTH_MODE":                   config.AuthMode,
+		"TIME_AUTH_SECRET_KEY":             mask(config.AuthSecretKey),
+		"TIME_AUTH_SECRET_KEYS":            mask(config.AuthSecretKeys),
+		"TIME_AUTH_ISSUER":                 config.AuthIssuer,
+		"TIME_AUTH_AUDIENCE":               config.AuthAudience,
+		"TIME_AUTH_ALGORITHM":              config.AuthAlgorithm,
+		"TIME_AUTH_ALGORITHMS":             config.AuthValidation.algorithms,
+		"TIME_AUTH_LEEWAY":                 config.AuthValidation.leeway.String(),
+		"TIME_AUTH_REQUIRED_CLAIMS":        config.AuthValidation.requiredClaims,
+		"TIME_AUTH_VERIFY_ISSUER":          !config.AuthValidation.skipIssuer,
+		"TIME_AUTH_VERIFY_AUDIENCE":        !config.AuthValidation.skipAudience,
+		"TIME_AUTH_PUBLIC_KEY":             config.AuthPublicKey,
+		"TIME_AUTH_JWKS_URL":               config.AuthJWKSURL,
+		"TIME_AUTH_JWKS_REFRESH":           config.AuthJWKSRefresh.String(),
+		"TIME_AUTH_OIDC_ISSUER":            config.AuthOIDCIssuer,
+		"TIME_AUTH_API_KEYS":               apiKeys,
+		"TIME_AUTH_ROLES":                  roles,
+		"TIME_AUTH_ANONYMOUS_TOOLS":        config.AuthAnonymousTools,
+		"TIME_AUTH_ADMIN_ROLE":             config.AuthAdminRole,
+		"TIME_AUTH_DENYLIST_FILE":          denylistFile,
+		"TIME_AUTH_DENYLIST_URL":           denylistURL,
+		"TIME_STDIO_AUTH_TOKEN":            mask(config.StdioAuthToken),
+		"TIME_STDIO_AUTH_REQUIRED":         config.StdioAuthRequired,
+		"TIME_STATS_FILE":                  config.StatsFile,
+		"TIME_STATS_FLUSH_INTERVAL":        config.StatsFlushInterval.String(),
+		"TIME_DEBUG_ENDPOINTS":             config.DebugEndpoints,
+		"TIME_DEBUG_ADDRESS":               config.DebugAddress,
+		"TIME_HEALTH_NTP_SERVERS":          config.HealthNTPServers,
+		"TIME_HEALTH_NTP_MAX_OFFSET":       config.HealthNTPMaxOffset.String(),
+		"TIME_CLOCK_STEP_THRESHOLD":        config.ClockStepThreshold.String(),
+		"TIME_CLOCK_STEP_NOTIFY":           config.ClockStepNotify,
+		"TIME_LOG_LEVEL":                   strings.ToLower(config.LogLevel.String()),
+		"TIME_LOG_FORMAT":                  config.LogFormat,
+		"TIME_LOG_PAYLOADS":                config.LogPayloads,
+		"TIME_OTEL_ENABLED":                config.OTelEnabled,
+		"TIME_OTEL_ENDPOINT":               config.OTelEndpoint,
+		"TIME_OTEL_SAMPLE_RATIO":           config.OTelSampleRatio,
+		"TIME_CALENDARS_FILE":              config.CalendarsFile,
+		"TIME_TERMS_FILE":                  config.TermsFile,
+		"TIME_CALENDARS_URL":               config.DatasetURLs[datasetCalendars],
+		"TIME_TERMS_URL":                   config.DatasetURLs[datasetTerms],
+		"TIME_GEOIP_DB_URL":                config.DatasetURLs[datasetGeoIP],
+		"TIME_DATASET_CACHE_DIR":           config.DatasetCacheDir,
+		"TIME_DATASET_REFRESH":             config.DatasetRefresh.String(),
+		"TIME_DATASET_REQUIRE_CHECKSUM":    config.DatasetRequireChecksum,
+		"TIME_TENANTS_FILE":                config.TenantsFile,
+	}
+}
+
+// ValidateConfigCommand validates the configuration and exits
+func ValidateConfigCommand(config *Config) error {
+	if err := validateConfig(config); err != nil {
+		return err
+	}
+	slog.Info("Configuration is valid")
+	return nil
+}
+
+// PrintConfigCommand validates the configuration and prints it as JSON with secrets masked
+func PrintConfigCommand(config *Config) error {
+	if err := validateConfig(config); err != nil {
+		return err
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(effectiveConfig(config))
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestEffectiveConfig_MasksSecrets(t *testing.T) {
+	config := &Config{
+		HTTPAddress:    ":8080",
+		AuthSecretKey:  "super-secret-key-value-that-must-not-leak",
+		StdioAuthToken: "stdio-token-value",
+		AuthAPIKeys:    map[string]apiKey{"abc123": {Name: "ci", Role: "reader"}},
+	}
+	out, err := json.Marshal(effectiveConfig(config))
+	if err != nil {
+		t.Fatalf("Failed to encode config: %v", err)
+	}
+	for _, secret := range []string{config.AuthSecretKey, config.StdioAuthToken, "abc123"} {
+		if strings.Contains(string(out), secret) {
+			t.Errorf("Expected %q to be masked in %s", secret, out)
+		}
+	}
+	if !strings.Contains(string(out), `"ci:reader"`) {
+		t.Errorf("Expected API key names and roles to be listed, but got %s", out)
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	testCases := []struct {
+		name    string
+		config  Config
+		wantErr bool
+	}{
+		{"Valid Defaults", Config{HTTPAddress: ":8080"}, false},
+		{"Unix Socket", Config{HTTPAddress: "unix:/tmp/timemcp.sock"}, false},
+		{"Bad Address", Config{HTTPAddress: "8080"}, true},
+		{"Missing TLS Files", Config{HTTPAddress: ":8443", HTTPTLSCert: "/nonexistent/cert.pem", HTTPTLSKey: "/nonexistent/key.pem"}, true},
+		{"Auth Without Secret", Config{HTTPAddress: ":8080", AuthEnabled: true}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateConfig(&tc.config)
+			if tc.wantErr && err == nil {
+				t.Error("Expected an error but got none")
+			}
+			if !tc.wantErr && err != nil {
+				t.Errorf("Expected no error but got %v", err)
+			}
+		})
+	}
+}
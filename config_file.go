@@ -0,0 +1,365 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// configValue is a setting read from a config file. Scalars keep their text
+// form; lists are joined with the field's sep tag (default ",") to match the
+// TIME_* env var syntax.
+type configValue struct {
+	value string
+	items []string
+	set   bool
+}
+
+// text returns the env var form of the value
+func (v configValue) text(sep string) string {
+	if v.items == nil {
+		return v.value
+	}
+	if sep == "" {
+		sep = ","
+	}
+	return strings.Join(v.items, sep)
+}
+
+func (v *configValue) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		v.value = node.Value
+	case yaml.SequenceNode:
+		v.items = make([]string, 0, len(node.Content))
+		for _, item := range node.Content {
+			if item.Kind != yaml.ScalarNode {
+				return fmt.Errorf("line %d: list items must be scalars", item.Line)
+			}
+			v.items = append(v.items, item.Value)
+		}
+	default:
+		return fmt.Errorf("line %d: expected a scalar or a list", node.Line)
+	}
+	v.set = true
+	return nil
+}
+
+func (v *configValue) UnmarshalTOML(data any) error {
+	switch d := data.(type) {
+	case []any:
+		v.items = make([]string, 0, len(d))
+		for _, item := range d {
+			v.items = append(v.items, fmt.Sprint(item))
+		}
+	case map[string]any:
+		return fmt.Errorf("expected a scalar or a list")
+	default:
+		v.value = fmt.Sprint(d)
+	}
+	v.set = true
+	return nil
+}
+
+// rateLimitFile configures one rate limit scope
+type rateLimitFile struct {
+	RPS   configValue `yaml:"rps" toml:"rps"`
+	Burst configValue `yaml:"burst" toml:"burst"`
+}
+
+// ConfigFile is the schema of the file passed with -config. Every setting
+// maps to the TIME_* environment variable named in its env tag; see CLAUDE.md
+// for the meaning and defaults of each. Environment variables take precedence
+// over the file.
+//
+//	http:
+//	  address: ":8080"
+//	  cors:
+//	    enabled: true
+//	    origins: [https://app.example.com]
+//	auth:
+//	  enabled: true
+//	  secret_key: "..."
+type ConfigFile struct {
+	HTTP struct {
+		Address         configValue `yaml:"address" toml:"address" env:"TIME_HTTP_ADDRESS"`
+		Path            configValue `yaml:"path" toml:"path" env:"TIME_HTTP_PATH"`
+		Stateless       configValue `yaml:"stateless" toml:"stateless" env:"TIME_HTTP_STATELESS"`
+		Heartbeat       configValue `yaml:"heartbeat" toml:"heartbeat" env:"TIME_HTTP_HEARTBEAT"`
+		Timeout         configValue `yaml:"timeout" toml:"timeout" env:"TIME_HTTP_TIMEOUT"`
+		ShutdownTimeout configValue `yaml:"shutdown_timeout" toml:"shutdown_timeout" env:"TIME_HTTP_SHUTDOWN_TIMEOUT"`
+		SessionIdleTTL  configValue `yaml:"session_idle_ttl" toml:"session_idle_ttl" env:"TIME_HTTP_SESSION_IDLE_TTL"`
+		SessionMaxLife  configValue `yaml:"session_max_lifetime" toml:"session_max_lifetime" env:"TIME_HTTP_SESSION_MAX_LIFETIME"`
+		SocketMode      configValue `yaml:"socket_mode" toml:"socket_mode" env:"TIME_HTTP_SOCKET_MODE"`
+		Compression     configValue `yaml:"compression" toml:"compression" env:"TIME_HTTP_COMPRESSION"`
+		REST            configValue `yaml:"rest" toml:"rest" env:"TIME_HTTP_REST"`
+		AccessLog       configValue `yaml:"access_log" toml:"access_log" env:"TIME_HTTP_ACCESS_LOG"`
+		AccessLogFile   configValue `yaml:"access_log_file" toml:"access_log_file" env:"TIME_HTTP_ACCESS_LOG_FILE"`
+		TrustedProxies  configValue `yaml:"trusted_proxies" toml:"trusted_proxies" env:"TIME_HTTP_TRUSTED_PROXIES"`
+		AllowCIDRs      configValue `yaml:"allow_cidrs" toml:"allow_cidrs" env:"TIME_HTTP_ALLOW_CIDRS"`
+		DenyCIDRs       configValue `yaml:"deny_cidrs" toml:"deny_cidrs" env:"TIME_HTTP_DENY_CIDRS"`
+
+		SecurityHeaders configValue `yaml:"security_headers" toml:"security_headers" env:"TIME_HTTP_SECURITY_HEADERS"`
+		HSTSMaxAge      configValue `yaml:"hsts_max_age" toml:"hsts_max_age" env:"TIME_HTTP_HSTS_MAX_AGE"`
+		FrameOptions    configValue `yaml:"frame_options" toml:"frame_options" env:"TIME_HTTP_FRAME_OPTIONS"`
+		CSP             configValue `yaml:"csp" toml:"csp" env:"TIME_HTTP_CSP"`
+
+		CORS struct {
+			Enabled          configValue `yaml:"enabled" toml:"enabled" env:"TIME_HTTP_CORS_ENABLED"`
+			Origins          configValue `yaml:"origins" toml:"origins" env:"TIME_HTTP_CORS_ORIGINS"`
+			Methods          configValue `yaml:"methods" toml:"methods" env:"TIME_HTTP_CORS_METHODS"`
+			Headers          configValue `yaml:"headers" toml:"headers" env:"TIME_HTTP_CORS_HEADERS"`
+			ExposeHeaders    configValue `yaml:"expose_headers" toml:"expose_headers" env:"TIME_HTTP_CORS_EXPOSE_HEADERS"`
+			AllowCredentials configValue `yaml:"allow_credentials" toml:"allow_credentials" env:"TIME_HTTP_CORS_ALLOW_CREDENTIALS"`
+			MaxAge           configValue `yaml:"max_age" toml:"max_age" env:"TIME_HTTP_CORS_MAX_AGE"`
+			Routes           configValue `yaml:"routes" toml:"routes" env:"TIME_HTTP_CORS_ROUTES"`
+		} `yaml:"cors" toml:"cors"`
+
+		RateLimit struct {
+			Global rateLimitFile `yaml:"global" toml:"global" env:"TIME_HTTP_RATE_LIMIT_GLOBAL"`
+			IP     rateLimitFile `yaml:"ip" toml:"ip" env:"TIME_HTTP_RATE_LIMIT_IP"`
+			User   rateLimitFile `yaml:"user" toml:"user" env:"TIME_HTTP_RATE_LIMIT_USER"`
+		} `yaml:"rate_limit" toml:"rate_limit"`
+
+		TLS struct {
+			Cert       configValue `yaml:"cert" toml:"cert" env:"TIME_HTTP_TLS_CERT"`
+			Key        configValue `yaml:"key" toml:"key" env:"TIME_HTTP_TLS_KEY"`
+			SelfSigned configValue `yaml:"self_signed" toml:"self_signed" env:"TIME_HTTP_TLS_SELF_SIGNED"`
+			ClientCA   configValue `yaml:"client_ca" toml:"client_ca" env:"TIME_HTTP_TLS_CLIENT_CA"`
+			ClientAuth configValue `yaml:"client_auth" toml:"client_auth" env:"TIME_HTTP_TLS_CLIENT_AUTH"`
+		} `yaml:"tls" toml:"tls"`
+
+		ACME struct {
+			Domains     configValue `yaml:"domains" toml:"domains" env:"TIME_HTTP_ACME_DOMAINS"`
+			CacheDir    configValue `yaml:"cache_dir" toml:"cache_dir" env:"TIME_HTTP_ACME_CACHE_DIR"`
+			Email       configValue `yaml:"email" toml:"email" env:"TIME_HTTP_ACME_EMAIL"`
+			HTTPAddress configValue `yaml:"http_address" toml:"http_address" env:"TIME_HTTP_ACME_HTTP_ADDRESS"`
+		} `yaml:"acme" toml:"acme"`
+	} `yaml:"http" toml:"http"`
+
+	Auth struct {
+		Enabled        configValue `yaml:"enabled" toml:"enabled" env:"TIME_AUTH_ENABLED"`
+		Mode           configValue `yaml:"mode" toml:"mode" env:"TIME_AUTH_MODE"`
+		SecretKey      configValue `yaml:"secret_key" toml:"secret_key" env:"TIME_AUTH_SECRET_KEY"`
+		SecretKeys     configValue `yaml:"secret_keys" toml:"secret_keys" env:"TIME_AUTH_SECRET_KEYS"`
+		Issuer         configValue `yaml:"issuer" toml:"issuer" env:"TIME_AUTH_ISSUER"`
+		Audience       configValue `yaml:"audience" toml:"audience" env:"TIME_AUTH_AUDIENCE"`
+		Algorithm      configValue `yaml:"algorithm" toml:"algorithm" env:"TIME_AUTH_ALGORITHM"`
+		Algorithms     configValue `yaml:"algorithms" toml:"algorithms" env:"TIME_AUTH_ALGORITHMS"`
+		PublicKey      configValue `yaml:"public_key" toml:"public_key" env:"TIME_AUTH_PUBLIC_KEY"`
+		JWKSURL        configValue `yaml:"jwks_url" toml:"jwks_url" env:"TIME_AUTH_JWKS_URL"`
+		JWKSRefresh    configValue `yaml:"jwks_refresh" toml:"jwks_refresh" env:"TIME_AUTH_JWKS_REFRESH"`
+		OIDCIssuer     configValue `yaml:"oidc_issuer" toml:"oidc_issuer" env:"TIME_AUTH_OIDC_ISSUER"`
+		Leeway         configValue `yaml:"leeway" toml:"leeway" env:"TIME_AUTH_LEEWAY"`
+		RequiredClaims configValue `yaml:"required_claims" toml:"required_claims" env:"TIME_AUTH_REQUIRED_CLAIMS"`
+		VerifyIssuer   configValue `yaml:"verify_issuer" toml:"verify_issuer" env:"TIME_AUTH_VERIFY_ISSUER"`
+		VerifyAudience configValue `yaml:"verify_audience" toml:"verify_audience" env:"TIME_AUTH_VERIFY_AUDIENCE"`
+		APIKeys        configValue `yaml:"api_keys" toml:"api_keys" env:"TIME_AUTH_API_KEYS"`
+		APIKeysFile    configValue `yaml:"api_keys_file" toml:"api_keys_file" env:"TIME_AUTH_API_KEYS_FILE"`
+		Roles          configValue `yaml:"roles" toml:"roles" env:"TIME_AUTH_ROLES" sep:";"`
+		RolesFile      configValue `yaml:"roles_file" toml:"roles_file" env:"TIME_AUTH_ROLES_FILE"`
+		AnonymousTools configValue `yaml:"anonymous_tools" toml:"anonymous_tools" env:"TIME_AUTH_ANONYMOUS_TOOLS"`
+		AdminRole      configValue `yaml:"admin_role" toml:"admin_role" env:"TIME_AUTH_ADMIN_ROLE"`
+		DenylistFile   configValue `yaml:"denylist_file" toml:"denylist_file" env:"TIME_AUTH_DENYLIST_FILE"`
+		DenylistURL    configValue `yaml:"denylist_url" toml:"denylist_url" env:"TIME_AUTH_DENYLIST_URL"`
+		DenylistTTL    configValue `yaml:"denylist_refresh" toml:"denylist_refresh" env:"TIME_AUTH_DENYLIST_REFRESH"`
+	} `yaml:"auth" toml:"auth"`
+
+	Stdio struct {
+		AuthToken    configValue `yaml:"auth_token" toml:"auth_token" env:"TIME_STDIO_AUTH_TOKEN"`
+		AuthRequired configValue `yaml:"auth_required" toml:"auth_required" env:"TIME_STDIO_AUTH_REQUIRED"`
+	} `yaml:"stdio" toml:"stdio"`
+
+	Store struct {
+		Backend  configValue `yaml:"backend" toml:"backend" env:"TIME_STORE"`
+		File     configValue `yaml:"file" toml:"file" env:"TIME_STORE_FILE"`
+		RedisURL configValue `yaml:"redis_url" toml:"redis_url" env:"TIME_STORE_REDIS_URL"`
+		Prefix   configValue `yaml:"prefix" toml:"prefix" env:"TIME_STORE_PREFIX"`
+	} `yaml:"store" toml:"store"`
+
+	Quota struct {
+		PerMinute configValue `yaml:"per_minute" toml:"per_minute" env:"TIME_QUOTA_PER_MINUTE"`
+		PerDay    configValue `yaml:"per_day" toml:"per_day" env:"TIME_QUOTA_PER_DAY"`
+	} `yaml:"quota" toml:"quota"`
+
+	Scheduler struct {
+		Enabled             configValue `yaml:"enabled" toml:"enabled" env:"TIME_SCHEDULER"`
+		MaxJobs             configValue `yaml:"max_jobs" toml:"max_jobs" env:"TIME_SCHEDULER_MAX_JOBS"`
+		WebhookSecret       configValue `yaml:"webhook_secret" toml:"webhook_secret" env:"TIME_WEBHOOK_SECRET"`
+		WebhookAllowPrivate configValue `yaml:"webhook_allow_private" toml:"webhook_allow_private" env:"TIME_WEBHOOK_ALLOW_PRIVATE"`
+	} `yaml:"scheduler" toml:"scheduler"`
+
+	Tool struct {
+		Timeout        configValue `yaml:"timeout" toml:"timeout" env:"TIME_TOOL_TIMEOUT"`
+		PluginsDir     configValue `yaml:"plugins_dir" toml:"plugins_dir" env:"TIME_TOOL_PLUGINS_DIR"`
+		HideDeprecated configValue `yaml:"hide_deprecated" toml:"hide_deprecated" env:"TIME_HIDE_DEPRECATED_TOOLS"`
+		CacheSize      configValue `yaml:"cache_size" toml:"cache_size" env:"TIME_TOOL_CACHE_SIZE"`
+		CacheTTL       configValue `yaml:"cache_ttl" toml:"cache_ttl" env:"TIME_TOOL_CACHE_TTL"`
+	} `yaml:"tool" toml:"tool"`
+
+	Stats struct {
+		File          configValue `yaml:"file" toml:"file" env:"TIME_STATS_FILE"`
+		FlushInterval configValue `yaml:"flush_interval" toml:"flush_interval" env:"TIME_STATS_FLUSH_INTERVAL"`
+	} `yaml:"stats" toml:"stats"`
+
+	Debug struct {
+		Endpoints configValue `yaml:"endpoints" toml:"endpoints" env:"TIME_DEBUG_ENDPOINTS"`
+		Address   configValue `yaml:"address" toml:"address" env:"TIME_DEBUG_ADDRESS"`
+	} `yaml:"debug" toml:"debug"`
+
+	Health struct {
+		NTPServers         configValue `yaml:"ntp_servers" toml:"ntp_servers" env:"TIME_HEALTH_NTP_SERVERS"`
+		NTPMaxOffset       configValue `yaml:"ntp_max_offset" toml:"ntp_max_offset" env:"TIME_HEALTH_NTP_MAX_OFFSET"`
+		ClockStepThreshold configValue `yaml:"clock_step_threshold" toml:"clock_step_threshold" env:"TIME_CLOCK_STEP_THRESHOLD"`
+		ClockStepNotify    configValue `yaml:"clock_step_notify" toml:"clock_step_notify" env:"TIME_CLOCK_STEP_NOTIFY"`
+	} `yaml:"health" toml:"health"`
+
+	Datasets struct {
+		CalendarsURL    configValue `yaml:"calendars_url" toml:"calendars_url" env:"TIME_CALENDARS_URL"`
+		TermsURL        configValue `yaml:"terms_url" toml:"terms_url" env:"TIME_TERMS_URL"`
+		GeoIPDBURL      configValue `yaml:"geoip_db_url" toml:"geoip_db_url" env:"TIME_GEOIP_DB_URL"`
+		CacheDir        configValue `yaml:"cache_dir" toml:"cache_dir" env:"TIME_DATASET_CACHE_DIR"`
+		Refresh         configValue `yaml:"refresh" toml:"refresh" env:"TIME_DATASET_REFRESH"`
+		RequireChecksum configValue `yaml:"require_checksum" toml:"require_checksum" env:"TIME_DATASET_REQUIRE_CHECKSUM"`
+	} `yaml:"datasets" toml:"datasets"`
+
+	Log struct {
+		Level    configValue `yaml:"level" toml:"level" env:"TIME_LOG_LEVEL"`
+		Format   configValue `yaml:"format" toml:"format" env:"TIME_LOG_FORMAT"`
+		Payloads configValue `yaml:"payloads" toml:"payloads" env:"TIME_LOG_PAYLOADS"`
+	} `yaml:"log" toml:"log"`
+
+	OTel struct {
+		Enabled     configValue `yaml:"enabled" toml:"enabled" env:"TIME_OTEL_ENABLED"`
+		Endpoint    configValue `yaml:"endpoint" toml:"endpoint" env:"TIME_OTEL_ENDPOINT"`
+		SampleRatio configValue `yaml:"sample_ratio" toml:"sample_ratio" env:"TIME_OTEL_SAMPLE_RATIO"`
+	} `yaml:"otel" toml:"otel"`
+
+	Server struct {
+		Name         configValue `yaml:"name" toml:"name" env:"TIME_SERVER_NAME"`
+		Version      configValue `yaml:"version" toml:"version" env:"TIME_SERVER_VERSION"`
+		Instructions configValue `yaml:"instructions" toml:"instructions" env:"TIME_SERVER_INSTRUCTIONS"`
+	} `yaml:"server" toml:"server"`
+
+	DefaultTimezone configValue `yaml:"default_timezone" toml:"default_timezone" env:"TIME_DEFAULT_TIMEZONE"`
+	TZValidate      configValue `yaml:"tz_validate" toml:"tz_validate" env:"TIME_TZ_VALIDATE"`
+	TZPreload       configValue `yaml:"tz_preload" toml:"tz_preload" env:"TIME_TZ_PRELOAD"`
+	CalendarsFile   configValue `yaml:"calendars_file" toml:"calendars_file" env:"TIME_CALENDARS_FILE"`
+	TermsFile       configValue `yaml:"terms_file" toml:"terms_file" env:"TIME_TERMS_FILE"`
+	TenantsFile     configValue `yaml:"tenants_file" toml:"tenants_file" env:"TIME_TENANTS_FILE"`
+
+	ClientTZ struct {
+		Detect  configValue `yaml:"detect" toml:"detect" env:"TIME_CLIENT_TZ_DETECT"`
+		Header  configValue `yaml:"header" toml:"header" env:"TIME_CLIENT_TZ_HEADER"`
+		GeoIPDB configValue `yaml:"geoip_db" toml:"geoip_db" env:"TIME_GEOIP_DB"`
+	} `yaml:"client_tz" toml:"client_tz"`
+}
+
+// parseConfigFile decodes a YAML (.yaml, .yml) or TOML (.toml) config file.
+// Unknown keys are rejected so typos do not silently fall back to defaults.
+func parseConfigFile(path string) (*ConfigFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	file := &ConfigFile{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(file); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+		}
+	case ".toml":
+		meta, err := toml.Decode(string(data), file)
+		if err != nil {
+			return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+		}
+		if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+			return nil, fmt.Errorf("invalid config file %s: unknown key %q", path, undecoded[0].String())
+		}
+	default:
+		return nil, fmt.Errorf("unsupported config file type %q (expected .yaml, .yml or .toml)", filepath.Ext(path))
+	}
+	return file, nil
+}
+
+// settings flattens the file into TIME_* variable names and values
+func (f *ConfigFile) settings() map[string]string {
+	settings := make(map[string]string)
+	collectSettings(reflect.ValueOf(f).Elem(), settings)
+	return settings
+}
+
+func collectSettings(v reflect.Value, settings map[string]string) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		tag := v.Type().Field(i).Tag
+		env := tag.Get("env")
+		switch value := field.Addr().Interface().(type) {
+		case *configValue:
+			if value.set {
+				settings[env] = value.text(tag.Get("sep"))
+			}
+		case *rateLimitFile:
+			if value.RPS.set {
+				settings[env+"_RPS"] = value.RPS.text("")
+			}
+			if value.Burst.set {
+				settings[env+"_BURST"] = value.Burst.text("")
+			}
+		default:
+			if field.Kind() == reflect.Struct {
+				collectSettings(field, settings)
+			}
+		}
+	}
+}
+
+var (
+	fileEnvMu sync.Mutex
+	// fileEnv records the variables last set from the config file, so a
+	// reload can replace them without overriding the real environment
+	fileEnv = map[string]string{}
+)
+
+// applyConfigFile exports the file's settings as TIME_* environment variables
+// that are not already set by the environment, so NewConfig sees file values
+// with env taking precedence. Calling it again replaces earlier file values.
+func applyConfigFile(path string) error {
+	file, err := parseConfigFile(path)
+	if err != nil {
+		return err
+	}
+
+	fileEnvMu.Lock()
+	defer fileEnvMu.Unlock()
+
+	for key, value := range fileEnv {
+		if current, ok := os.LookupEnv(key); ok && current == value {
+			os.Unsetenv(key)
+		}
+	}
+	applied := make(map[string]string)
+	for key, value := range file.settings() {
+		if _, ok := os.LookupEnv(key); ok {
+			continue
+		}
+		if err := os.Setenv(key, value); err != nil {
+			return err
+		}
+		applied[key] = value
+	}
+	fileEnv = applied
+	return nil
+}
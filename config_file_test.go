@@ -0,0 +1,98 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestApplyConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	yamlPath := filepath.Join(dir, "timemcp.yaml")
+	yamlContent := `
+http:
+  address: ":9090"
+  cors:
+    enabled: true
+    origins: [https://app.example.com, localhost:3000]
+  rate_limit:
+    ip:
+      rps: 2.5
+auth:
+  roles: ["admin:*", "reader:get_current_time,convert_time"]
+default_timezone: Europe/Warsaw
+`
+	if err := os.WriteFile(yamlPath, []byte(yamlContent), 0o600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	// Environment variables take precedence over the file
+	t.Setenv("TIME_DEFAULT_TIMEZONE", "Asia/Tokyo")
+	for _, key := range []string{"TIME_HTTP_ADDRESS", "TIME_HTTP_CORS_ENABLED", "TIME_HTTP_CORS_ORIGINS", "TIME_HTTP_RATE_LIMIT_IP_RPS", "TIME_AUTH_ROLES"} {
+		t.Setenv(key, "")
+		os.Unsetenv(key)
+	}
+
+	if err := applyConfigFile(yamlPath); err != nil {
+		t.Fatalf("Failed to apply config file: %v", err)
+	}
+	t.Cleanup(func() {
+		for key := range fileEnv {
+			os.Unsetenv(key)
+		}
+		fileEnv = map[string]string{}
+	})
+
+	config, err := NewConfig()
+	if err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+	if config.HTTPAddress != ":9090" {
+		t.Errorf("Expected address :9090, but got %s", config.HTTPAddress)
+	}
+	if !config.HTTPCORSEnabled || len(config.HTTPCORSOrigins) != 2 {
+		t.Errorf("Expected CORS with two origins, but got %v %v", config.HTTPCORSEnabled, config.HTTPCORSOrigins)
+	}
+	if config.HTTPRateLimitIP.Rate != 2.5 {
+		t.Errorf("Expected IP rate 2.5, but got %v", config.HTTPRateLimitIP.Rate)
+	}
+	if !config.AuthRoles.allows("reader", "convert_time") || config.AuthRoles.allows("reader", "revoke_token") {
+		t.Errorf("Unexpected role policy: %v", config.AuthRoles)
+	}
+	if config.DefaultTimezone != "Asia/Tokyo" {
+		t.Errorf("Expected env timezone to win, but got %s", config.DefaultTimezone)
+	}
+
+	// Reloading a changed file replaces earlier file values
+	tomlPath := filepath.Join(dir, "timemcp.toml")
+	if err := os.WriteFile(tomlPath, []byte("[http]\npath = \"/rpc\"\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	if err := applyConfigFile(tomlPath); err != nil {
+		t.Fatalf("Failed to apply config file: %v", err)
+	}
+	if _, ok := os.LookupEnv("TIME_HTTP_ADDRESS"); ok {
+		t.Error("Expected values from the previous file to be cleared")
+	}
+	if os.Getenv("TIME_HTTP_PATH") != "/rpc" {
+		t.Errorf("Expected path from TOML file, but got %q", os.Getenv("TIME_HTTP_PATH"))
+	}
+}
+
+func TestParseConfigFile_Errors(t *testing.T) {
+	dir := t.TempDir()
+	testCases := map[string]string{
+		"unknown.yaml": "http:\n  adress: \":9090\"\n",
+		"unknown.toml": "[http]\nadress = \":9090\"\n",
+		"config.json":  "{}",
+	}
+	for name, content := range testCases {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+		if _, err := parseConfigFile(path); err == nil {
+			t.Errorf("Expected an error for %s", name)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORSPolicy describes the Access-Control-* headers returned to allowed origins
+type CORSPolicy struct {
+	Methods          []string
+	Headers          []string
+	ExposeHeaders    []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+
+	// RouteMethods overrides Methods for requests whose path starts with the key.
+	// The longest matching prefix wins.
+	RouteMethods map[string][]string
+}
+
+// methodsFor returns the allowed methods for a request path
+func (p CORSPolicy) methodsFor(path string) []string {
+	prefixes := make([]string, 0, len(p.RouteMethods))
+	for prefix := range p.RouteMethods {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Slice(prefixes, func(i, j int) bool { return len(prefixes[i]) > len(prefixes[j]) })
+
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return p.RouteMethods[prefix]
+		}
+	}
+	return p.Methods
+}
+
+// setCORSHeaders writes CORS headers for an allowed origin. Preflight-only
+// headers are added when preflight is true.
+func setCORSHeaders(w http.ResponseWriter, r *http.Request, origin string, policy CORSPolicy, preflight bool) {
+	h := w.Header()
+	h.Set("Access-Control-Allow-Origin", origin)
+	h.Add("Vary", "Origin")
+	if policy.AllowCredentials {
+		h.Set("Access-Control-Allow-Credentials", "true")
+	}
+	if len(policy.ExposeHeaders) > 0 {
+		h.Set("Access-Control-Expose-Headers", strings.Join(policy.ExposeHeaders, ", "))
+	}
+	h.Set("Access-Control-Allow-Methods", strings.Join(policy.methodsFor(r.URL.Path), ", "))
+	h.Set("Access-Control-Allow-Headers", strings.Join(policy.Headers, ", "))
+
+	if preflight && policy.MaxAge > 0 {
+		h.Set("Access-Control-Max-Age", strconv.Itoa(int(policy.MaxAge.Seconds())))
+	}
+}
+
+// parseCORSRouteMethods parses "/health=GET,OPTIONS;/mcp=GET,POST,DELETE,OPTIONS"
+func parseCORSRouteMethods(str string) (map[string][]string, error) {
+	routes := make(map[string][]string)
+	for _, entry := range strings.Split(str, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		path, methods, ok := strings.Cut(entry, "=")
+		path = strings.TrimSpace(path)
+		if !ok || !strings.HasPrefix(path, "/") {
+			return nil, fmt.Errorf("invalid route override %q (expected /path=METHOD,...)", entry)
+		}
+		routes[path] = parseHeaderList(methods, true)
+	}
+	return routes, nil
+}
+
+// parseHeaderList splits a comma-separated list, optionally upper-casing entries
+func parseHeaderList(str string, upper bool) []string {
+	var list []string
+	for _, item := range strings.Split(str, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if upper {
+			item = strings.ToUpper(item)
+		}
+		list = append(list, item)
+	}
+	return list
+}
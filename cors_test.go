@@ -0,0 +1,82 @@
+package main
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSetCORSHeaders_RouteOverride(t *testing.T) {
+	routes, err := parseCORSRouteMethods("/health=get, options; /mcp/admin=POST")
+	if err != nil {
+		t.Fatalf("Failed to parse route overrides: %v", err)
+	}
+	policy := CORSPolicy{
+		Methods:          []string{"GET", "POST", "DELETE", "OPTIONS"},
+		Headers:          []string{"Content-Type", "Mcp-Session-Id"},
+		ExposeHeaders:    []string{"Mcp-Session-Id"},
+		AllowCredentials: true,
+		MaxAge:           time.Hour,
+		RouteMethods:     routes,
+	}
+
+	testCases := []struct {
+		path    string
+		methods string
+	}{
+		{"/health", "GET, OPTIONS"},
+		{"/mcp", "GET, POST, DELETE, OPTIONS"},
+		{"/mcp/admin/sessions", "POST"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			req := httptest.NewRequest("OPTIONS", tc.path, nil)
+			rec := httptest.NewRecorder()
+			setCORSHeaders(rec, req, "https://app.example.com", policy, true)
+
+			h := rec.Header()
+			if got := h.Get("Access-Control-Allow-Methods"); got != tc.methods {
+				t.Errorf("Expected methods %q, but got %q", tc.methods, got)
+			}
+			if got := h.Get("Access-Control-Expose-Headers"); got != "Mcp-Session-Id" {
+				t.Errorf("Expected exposed headers 'Mcp-Session-Id', but got %q", got)
+			}
+			if got := h.Get("Access-Control-Allow-Credentials"); got != "true" {
+				t.Errorf("Expected credentials to be allowed, but got %q", got)
+			}
+			if got := h.Get("Access-Control-Max-Age"); got != "3600" {
+				t.Errorf("Expected max age 3600, but got %q", got)
+			}
+		})
+	}
+}
+
+func TestParseCORSRouteMethods_Invalid(t *testing.T) {
+	if _, err := parseCORSRouteMethods("health=GET"); err == nil {
+		t.Fatal("Expected an error for a route without leading slash, but got nil")
+	}
+}
+
+func TestIsOriginAllowed(t *testing.T) {
+	allowed := parseCORSOrigins("https://app.example.com, localhost:3000, *.trusted.com")
+
+	testCases := []struct {
+		origin   string
+		expected bool
+	}{
+		{"https://app.example.com", true},
+		{"http://localhost:3000", true},
+		{"http://localhost:4000", false},
+		{"https://api.trusted.com", true},
+		{"https://trusted.com", true},
+		{"https://eviltrusted.com", false},
+		{"null", false},
+	}
+
+	for _, tc := range testCases {
+		if got := isOriginAllowed(tc.origin, allowed); got != tc.expected {
+			t.Errorf("isOriginAllowed(%q): expected %v, but got %v", tc.origin, tc.expected, got)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/araddon/dateparse"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// maxCountdownEvents caps the events of one compare_countdowns call
+const maxCountdownEvents = 100
+
+// countdownInput is one named target as given
+type countdownInput struct {
+	Name     string `json:"name"`
+	Time     string `json:"time"`
+	Timezone string `json:"timezone"`
+}
+
+// countdownComparison is the structured result of compare_countdowns
+type countdownComparison struct {
+	Now    string           `json:"now" jsonschema:"When the countdowns were computed, in RFC 3339"`
+	Next   string           `json:"next,omitempty" jsonschema:"Name of the first event still to come"`
+	Events []countdownEntry `json:"events" jsonschema:"Events in chronological order"`
+}
+
+type countdownEntry struct {
+	Name                 string  `json:"name"`
+	Time                 string  `json:"time" jsonschema:"Event time in RFC 3339 in the event's timezone"`
+	Remaining            string  `json:"remaining" jsonschema:"Time until the event, e.g. 2d 3h 15m, or since it for passed events"`
+	RemainingSeconds     float64 `json:"remaining_seconds" jsonschema:"Seconds until the event; negative once it has passed"`
+	Passed               bool    `json:"passed"`
+	AfterPrevious        string  `json:"after_previous,omitempty" jsonschema:"How long after the previous event this one is"`
+	AfterPreviousSeconds float64 `json:"after_previous_seconds,omitempty"`
+}
+
+func addCompareCountdownsTool(mcpServer *server.MCPServer, config *Config) {
+	mcpServer.AddTool(
+		mcp.NewTool("compare_countdowns",
+			mcp.WithDescription("Compare countdowns to several named events, e.g. 'which comes first, the launch or the conference, and by how much'. Returns the events in chronological order with the time remaining until each and the gap after the previous one."),
+			mcp.WithArray("events",
+				mcp.Description("Named targets: name, time in RFC 3339 or another common date-time format, and an optional timezone for times without an offset."),
+				mcp.Items(map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"name":     map[string]any{"type": "string"},
+						"time":     map[string]any{"type": "string"},
+						"timezone": map[string]any{"type": "string"},
+					},
+					"required": []string{"name", "time"},
+				}),
+				mcp.MinItems(1),
+				mcp.MaxItems(maxCountdownEvents),
+				mcp.Required(),
+			),
+			mcp.WithString("timezone",
+				mcp.Description("Timezone of events without one. Defaults to the session or server default."),
+			),
+			mcp.WithOutputSchema[countdownComparison](),
+			mcp.WithTitleAnnotation("Compare Countdowns"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(false),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		handleCompareCountdowns(config),
+	)
+}
+
+// handleCompareCountdowns returns a handler for the compare_countdowns tool
+func handleCompareCountdowns(config *Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Events []countdownInput `json:"events"`
+		}
+		if err := request.BindArguments(&args); err != nil {
+			return toolError(errorInvalidArgument, fmt.Sprintf("Invalid events: %v", err)), nil
+		}
+		if len(args.Events) == 0 || len(args.Events) > maxCountdownEvents {
+			return toolError(errorInvalidArgument, fmt.Sprintf("Provide between 1 and %d events", maxCountdownEvents)), nil
+		}
+		timezoneStr := request.GetString("timezone", "")
+		defaultLoc, err := loadTimezone(ctx, timezoneStr, config)
+		if err != nil {
+			return invalidTimezoneError("Invalid timezone", timezoneStr), nil
+		}
+
+		type target struct {
+			name string
+			t    time.Time
+		}
+		targets := make([]target, len(args.Events))
+		for i, in := range args.Events {
+			loc := defaultLoc
+			if in.Timezone != "" {
+				if loc, err = loadLocationCached(in.Timezone); err != nil {
+					return invalidTimezoneError(fmt.Sprintf("Invalid timezone in event %d", i), in.Timezone), nil
+				}
+			}
+			t, err := dateparse.ParseIn(in.Time, loc)
+			if err != nil {
+				return toolError(errorParse, fmt.Sprintf("Invalid time in event %d: %q. Please provide RFC 3339 or another common date-time format.", i, in.Time)), nil
+			}
+			targets[i] = target{name: in.Name, t: t.In(loc)}
+		}
+		slices.SortStableFunc(targets, func(a, b target) int { return a.t.Compare(b.t) })
+
+		now := config.now()
+		comparison := countdownComparison{Now: now.In(defaultLoc).Format(time.RFC3339), Events: make([]countdownEntry, len(targets))}
+		for i, target := range targets {
+			remaining := target.t.Sub(now)
+			entry := countdownEntry{
+				Name:             target.name,
+				Time:             target.t.Format(time.RFC3339),
+				Remaining:        formatCountdown(remaining),
+				RemainingSeconds: remaining.Truncate(time.Second).Seconds(),
+				Passed:           remaining <= 0,
+			}
+			if i > 0 {
+				gap := target.t.Sub(targets[i-1].t)
+				entry.AfterPrevious, entry.AfterPreviousSeconds = formatCountdown(gap), gap.Seconds()
+			}
+			if !entry.Passed && comparison.Next == "" {
+				comparison.Next = target.name
+			}
+			comparison.Events[i] = entry
+		}
+
+		data, err := json.Marshal(comparison)
+		if err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to encode result: %v", err)), nil
+		}
+		return mcp.NewToolResultStructured(comparison, string(data)), nil
+	}
+}
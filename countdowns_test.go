@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestCompareCountdowns(t *testing.T) {
+	clock := newFakeClock(time.Date(2025, 9, 1, 10, 0, 0, 0, time.UTC))
+	handler := handleCompareCountdowns(&Config{DefaultTimezone: "UTC", Clock: clock})
+	call := func(args map[string]any) *mcp.CallToolResult {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := handler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("Failed to call handler: %v", err)
+		}
+		return result
+	}
+
+	result := call(map[string]any{"events": []any{
+		map[string]any{"name": "conference", "time": "2025-09-15 09:00", "timezone": "America/New_York"},
+		map[string]any{"name": "launch", "time": "2025-09-12T17:30:00+02:00"},
+		map[string]any{"name": "kickoff", "time": "2025-08-30 12:00"},
+	}})
+	if result.IsError {
+		t.Fatalf("Failed to compare countdowns: %s", toolResultText(result))
+	}
+	got := result.StructuredContent.(countdownComparison)
+	want := []struct {
+		name, remaining, afterPrevious string
+		passed                         bool
+	}{
+		{"kickoff", "1d 22h", "", true},
+		{"launch", "11d 5h 30m", "13d 3h 30m", false},
+		{"conference", "14d 3h", "2d 21h 30m", false},
+	}
+	if got.Next != "launch" || len(got.Events) != len(want) {
+		t.Fatalf("Expected the launch to come next of 3 events but got %+v", got)
+	}
+	for i, w := range want {
+		e := got.Events[i]
+		if e.Name != w.name || e.Remaining != w.remaining || e.AfterPrevious != w.afterPrevious || e.Passed != w.passed {
+			t.Errorf("Expected event %d to be %+v but got %+v", i, w, e)
+		}
+	}
+	if got.Events[2].Time != "2025-09-15T09:00:00-04:00" {
+		t.Errorf("Expected the conference in its own timezone but got %s", got.Events[2].Time)
+	}
+
+	for _, args := range []map[string]any{
+		{"events": []any{}},
+		{"events": []any{map[string]any{"name": "x", "time": "someday"}}},
+	} {
+		if result := call(args); !result.IsError {
+			t.Errorf("Expected %v to be rejected", args)
+		}
+	}
+}
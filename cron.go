@@ -0,0 +1,762 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// cronMacros are the shorthand schedules supported in place of five fields
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronUnit identifies a field for step and name formatting
+type cronUnit int
+
+const (
+	cronMinute cronUnit = iota
+	cronHour
+	cronDayOfMonth
+	cronMonth
+	cronDayOfWeek
+)
+
+// cronFieldSpec describes the values accepted by one field
+type cronFieldSpec struct {
+	name     string
+	min, max int
+	// names are accepted case-insensitively in place of min, min+1, ...
+	names []string
+}
+
+var cronFieldSpecs = [5]cronFieldSpec{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day-of-month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}},
+	// 7 is accepted as Sunday as well as 0
+	{name: "day-of-week", min: 0, max: 7, names: []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}},
+}
+
+// cronItem is one comma-separated element of a field: a value, a range, or
+// either of those or "*" with a step
+type cronItem struct {
+	Start, End, Step int
+	Star             bool
+	Single           bool
+}
+
+// cronField is a parsed field with the set of values it matches
+type cronField struct {
+	Items []cronItem
+	bits  uint64
+}
+
+// matches reports whether v is in the field's set
+func (f cronField) matches(v int) bool {
+	return f.bits&(1<<uint(v)) != 0
+}
+
+// isStar reports whether the field is a plain "*"
+func (f cronField) isStar() bool {
+	return len(f.Items) == 1 && f.Items[0].Star && f.Items[0].Step == 1
+}
+
+// starStep returns n for a field that is exactly "*/n"
+func (f cronField) starStep() (int, bool) {
+	if len(f.Items) == 1 && f.Items[0].Star && f.Items[0].Step > 1 {
+		return f.Items[0].Step, true
+	}
+	return 0, false
+}
+
+// singles returns the values of a field made only of single values
+func (f cronField) singles() ([]int, bool) {
+	var values []int
+	for _, item := range f.Items {
+		if !item.Single {
+			return nil, false
+		}
+		values = append(values, item.Start)
+	}
+	slices.Sort(values)
+	return slices.Compact(values), true
+}
+
+// cronSchedule is a parsed five-field cron expression
+type cronSchedule struct {
+	Minute, Hour, DayOfMonth, Month, DayOfWeek cronField
+}
+
+// cronSyntaxError reports where a cron expression is invalid
+type cronSyntaxError struct {
+	// Pos is the 0-based byte offset in the expression
+	Pos   int
+	Field string
+	Msg   string
+}
+
+func (e *cronSyntaxError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("position %d: %s", e.Pos+1, e.Msg)
+	}
+	return fmt.Sprintf("%s field at position %d: %s", e.Field, e.Pos+1, e.Msg)
+}
+
+// parseCron parses a standard five-field cron expression (minute hour
+// day-of-month month day-of-week) or one of the @ macros. Fields accept *,
+// values, names (JAN-DEC, SUN-SAT), ranges, steps and comma lists.
+func parseCron(expr string) (*cronSchedule, error) {
+	trimmed := strings.TrimSpace(expr)
+	if strings.HasPrefix(trimmed, "@") {
+		expanded, ok := cronMacros[strings.ToLower(trimmed)]
+		if !ok {
+			return nil, &cronSyntaxError{Pos: strings.Index(expr, "@"), Msg: fmt.Sprintf("unknown macro %s", trimmed)}
+		}
+		expr = expanded
+	}
+
+	// Split into fields, remembering where each starts
+	var fields []string
+	var offsets []int
+	start := -1
+	for i, r := range expr + " " {
+		if unicode.IsSpace(r) {
+			if start >= 0 {
+				fields = append(fields, expr[start:i])
+				offsets = append(offsets, start)
+				start = -1
+			}
+		} else if start < 0 {
+			start = i
+		}
+	}
+	if len(fields) != 5 {
+		pos := len(strings.TrimRightFunc(expr, unicode.IsSpace))
+		if len(fields) > 5 {
+			pos = offsets[5]
+		}
+		return nil, &cronSyntaxError{Pos: pos, Msg: fmt.Sprintf("expected 5 fields (minute hour day-of-month month day-of-week) but got %d", len(fields))}
+	}
+
+	var parsed [5]cronField
+	for i, field := range fields {
+		f, err := parseCronField(field, offsets[i], cronFieldSpecs[i])
+		if err != nil {
+			return nil, err
+		}
+		parsed[i] = f
+	}
+	// Sunday is both 0 and 7
+	if parsed[4].matches(7) {
+		parsed[4].bits = parsed[4].bits&^(1<<7) | 1
+	}
+	return &cronSchedule{parsed[0], parsed[1], parsed[2], parsed[3], parsed[4]}, nil
+}
+
+// parseCronField parses one field; offset is its position in the expression
+func parseCronField(field string, offset int, spec cronFieldSpec) (cronField, error) {
+	var f cronField
+	pos := offset
+	for _, part := range strings.Split(field, ",") {
+		fail := func(at int, format string, args ...any) (cronField, error) {
+			return cronField{}, &cronSyntaxError{Pos: at, Field: spec.name, Msg: fmt.Sprintf(format, args...)}
+		}
+		if part == "" {
+			return fail(pos, "empty list element")
+		}
+
+		item := cronItem{Step: 1}
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		if hasStep {
+			stepPos := pos + len(rangePart) + 1
+			step, err := strconv.Atoi(stepPart)
+			if err != nil || step < 1 {
+				return fail(stepPos, "invalid step %q", stepPart)
+			}
+			if step > spec.max-spec.min+1 {
+				return fail(stepPos, "step %d is larger than the field's range %d-%d", step, spec.min, spec.max)
+			}
+			item.Step = step
+		}
+
+		switch {
+		case rangePart == "*":
+			item.Star, item.Start, item.End = true, spec.min, spec.max
+			if spec.name == "day-of-week" {
+				item.End = 6
+			}
+		default:
+			startStr, endStr, isRange := strings.Cut(rangePart, "-")
+			start, err := parseCronValue(startStr, spec)
+			if err != nil {
+				return fail(pos, "%v", err)
+			}
+			item.Start, item.End = start, start
+			switch {
+			case isRange:
+				endPos := pos + len(startStr) + 1
+				end, err := parseCronValue(endStr, spec)
+				if err != nil {
+					return fail(endPos, "%v", err)
+				}
+				if end < start {
+					return fail(pos, "range %s-%s ends before it starts", startStr, endStr)
+				}
+				item.End = end
+			case hasStep:
+				// "5/15" means from 5 through the end of the range
+				item.End = spec.max
+			default:
+				item.Single = true
+			}
+		}
+
+		for v := item.Start; v <= item.End; v += item.Step {
+			f.bits |= 1 << uint(v)
+		}
+		f.Items = append(f.Items, item)
+		pos += len(part) + 1
+	}
+	return f, nil
+}
+
+// parseCronValue parses a number or, where the field allows it, a name
+func parseCronValue(s string, spec cronFieldSpec) (int, error) {
+	if s == "" {
+		return 0, fmt.Errorf("missing value")
+	}
+	if i := slices.Index(spec.names, strings.ToUpper(s)); i >= 0 {
+		return spec.min + i, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		if len(spec.names) > 0 {
+			return 0, fmt.Errorf("invalid value %q; expected a number or one of %s", s, strings.Join(spec.names, ", "))
+		}
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	if v < spec.min || v > spec.max {
+		return 0, fmt.Errorf("value %d is out of range %d-%d", v, spec.min, spec.max)
+	}
+	return v, nil
+}
+
+// maxCronSearch bounds the search for the next run of a schedule that
+// rarely or never matches, such as "0 0 30 2 *"
+const maxCronSearch = 5 * 366 * 24 * time.Hour
+
+// matchesDay applies the cron day rule: when both day fields are restricted
+// a day matching either runs, otherwise both must match
+func (s *cronSchedule) matchesDay(t time.Time) bool {
+	dom := s.DayOfMonth.matches(t.Day())
+	dow := s.DayOfWeek.matches(int(t.Weekday()))
+	if !s.DayOfMonth.Items[0].Star && !s.DayOfWeek.Items[0].Star {
+		return dom || dow
+	}
+	return dom && dow
+}
+
+// next returns the first run strictly after the given time, in its location,
+// or the zero time if there is none within five years. Runs follow the wall
+// clock: a time skipped by a DST change does not run and a repeated one runs
+// once.
+func (s *cronSchedule) next(after time.Time) time.Time {
+	loc := after.Location()
+	wall := func(t time.Time) time.Time {
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, time.UTC)
+	}
+	afterWall := wall(after)
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := after.Add(maxCronSearch)
+	for t.Before(limit) {
+		switch {
+		case !s.Month.matches(int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case !s.Hour.matches(t.Hour()):
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case !s.Minute.matches(t.Minute()) || !wall(t).After(afterWall):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// prev returns the last run at or before the given time, in its location,
+// or the zero time if there is none within five years. Like next it follows
+// the wall clock: skipped times did not run and a repeated time ran at its
+// first occurrence.
+func (s *cronSchedule) prev(before time.Time) time.Time {
+	loc := before.Location()
+	day := time.Date(before.Year(), before.Month(), before.Day(), 0, 0, 0, 0, time.UTC)
+	for i := 0; i <= int(maxCronSearch/(24*time.Hour)); i++ {
+		d := day.AddDate(0, 0, -i)
+		if !s.Month.matches(int(d.Month())) || !s.matchesDay(d) {
+			continue
+		}
+		for h := 23; h >= 0; h-- {
+			if !s.Hour.matches(h) {
+				continue
+			}
+			for m := 59; m >= 0; m-- {
+				if !s.Minute.matches(m) {
+					continue
+				}
+				t := time.Date(d.Year(), d.Month(), d.Day(), h, m, 0, 0, loc)
+				if t.Day() != d.Day() || t.Hour() != h || t.Minute() != m {
+					continue
+				}
+				if earlier := t.Add(-time.Hour); earlier.Hour() == h && earlier.Minute() == m {
+					t = earlier
+				}
+				if !t.After(before) {
+					return t
+				}
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// cronLocale holds the phrases of one describe_cron language. Phrases are
+// lowercase; the description is capitalized once assembled.
+type cronLocale struct {
+	and, or string
+	// through joins the ends of a range
+	through func(a, b string) string
+	// every is a step: "every 15 minutes"
+	every func(unit cronUnit, n int) string
+	// fromThrough bounds a step: "from 0 through 30"
+	fromThrough func(a, b string) string
+
+	at, everyMinute, atMinute, atMinutes, pastEveryHour, between     string
+	onDay, onDays, onWeekdays, onWeekends, onDayOfWeek, onDaysOfWeek string
+	inMonth, inMonths                                                string
+
+	// Names in lists and ranges, and in phrases about a single value
+	monthNames, monthNamesSingle     []string
+	weekdayNames, weekdayNamesSingle []string
+}
+
+// cronLocales are the languages describe_cron supports
+var cronLocales = map[string]*cronLocale{
+	"en": {
+		and:     "and",
+		or:      "or",
+		through: func(a, b string) string { return a + " through " + b },
+		every: func(unit cronUnit, n int) string {
+			units := [...]string{"minutes", "hours", "days", "months", "days of the week"}
+			return fmt.Sprintf("every %d %s", n, units[unit])
+		},
+		fromThrough:   func(a, b string) string { return "from " + a + " through " + b },
+		at:            "at %s",
+		everyMinute:   "every minute",
+		atMinute:      "at minute %s",
+		atMinutes:     "at minutes %s",
+		pastEveryHour: "past every hour",
+		between:       "between %s and %s",
+		onDay:         "on day %s of the month",
+		onDays:        "on days %s of the month",
+		onWeekdays:    "on weekdays",
+		onWeekends:    "on weekends",
+		onDayOfWeek:   "on %s",
+		onDaysOfWeek:  "on %s",
+		inMonth:       "in %s",
+		inMonths:      "in %s",
+		monthNames: []string{"January", "February", "March", "April", "May", "June",
+			"July", "August", "September", "October", "November", "December"},
+		weekdayNames: []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+	},
+	"pl": {
+		and:     "i",
+		or:      "lub",
+		through: func(a, b string) string { return a + "–" + b },
+		every: func(unit cronUnit, n int) string {
+			forms := [...][3]string{
+				{"minutę", "minuty", "minut"},
+				{"godzinę", "godziny", "godzin"},
+				{"dzień", "dni", "dni"},
+				{"miesiąc", "miesiące", "miesięcy"},
+				{"dzień tygodnia", "dni tygodnia", "dni tygodnia"},
+			}
+			return fmt.Sprintf("co %d %s", n, polishPlural(n, forms[unit]))
+		},
+		fromThrough:   func(a, b string) string { return "od " + a + " do " + b },
+		at:            "o %s",
+		everyMinute:   "co minutę",
+		atMinute:      "w minucie %s",
+		atMinutes:     "w minutach %s",
+		pastEveryHour: "każdej godziny",
+		between:       "między %s a %s",
+		onDay:         "w dniu %s miesiąca",
+		onDays:        "w dniach %s miesiąca",
+		onWeekdays:    "w dni robocze",
+		onWeekends:    "w weekendy",
+		onDayOfWeek:   "w %s",
+		onDaysOfWeek:  "w dni: %s",
+		inMonth:       "w %s",
+		inMonths:      "w miesiącach: %s",
+		monthNames: []string{"styczeń", "luty", "marzec", "kwiecień", "maj", "czerwiec",
+			"lipiec", "sierpień", "wrzesień", "październik", "listopad", "grudzień"},
+		monthNamesSingle: []string{"styczniu", "lutym", "marcu", "kwietniu", "maju", "czerwcu",
+			"lipcu", "sierpniu", "wrześniu", "październiku", "listopadzie", "grudniu"},
+		weekdayNames:       []string{"niedziela", "poniedziałek", "wtorek", "środa", "czwartek", "piątek", "sobota"},
+		weekdayNamesSingle: []string{"niedzielę", "poniedziałek", "wtorek", "środę", "czwartek", "piątek", "sobotę"},
+	},
+}
+
+// polishPlural picks the singular, few (2-4) or many form for n
+func polishPlural(n int, forms [3]string) string {
+	switch {
+	case n == 1:
+		return forms[0]
+	case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+		return forms[1]
+	}
+	return forms[2]
+}
+
+// list joins items as "a, b and c"
+func (l *cronLocale) list(items []string) string {
+	if len(items) <= 1 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " " + l.and + " " + items[len(items)-1]
+}
+
+// items describes each element of a field with value formatting the values
+func (l *cronLocale) items(f cronField, unit cronUnit, value func(int) string) string {
+	var parts []string
+	for _, item := range f.Items {
+		switch {
+		case item.Single:
+			parts = append(parts, value(item.Start))
+		case item.Star:
+			parts = append(parts, l.every(unit, item.Step))
+		case item.Step == 1:
+			parts = append(parts, l.through(value(item.Start), value(item.End)))
+		default:
+			parts = append(parts, l.every(unit, item.Step)+" "+l.fromThrough(value(item.Start), value(item.End)))
+		}
+	}
+	return l.list(parts)
+}
+
+// describe renders the schedule in the locale's language
+func (s *cronSchedule) describe(l *cronLocale) string {
+	parts := []string{s.describeTime(l)}
+	if days := s.describeDays(l); days != "" {
+		parts = append(parts, days)
+	}
+	if months := s.describeMonths(l); months != "" {
+		parts = append(parts, months)
+	}
+	desc := strings.Join(parts, " ")
+	r, size := utf8.DecodeRuneInString(desc)
+	return string(unicode.ToUpper(r)) + desc[size:]
+}
+
+func (s *cronSchedule) describeTime(l *cronLocale) string {
+	minutes, minuteSingles := s.Minute.singles()
+	hours, hourSingles := s.Hour.singles()
+	// A few fixed times read best as a list: "at 09:00 and 17:30"
+	if minuteSingles && hourSingles && len(minutes)*len(hours) <= 6 {
+		var times []string
+		for _, h := range hours {
+			for _, m := range minutes {
+				times = append(times, fmt.Sprintf("%02d:%02d", h, m))
+			}
+		}
+		return fmt.Sprintf(l.at, l.list(times))
+	}
+
+	var minute string
+	everyMinute := false
+	if s.Minute.isStar() {
+		minute, everyMinute = l.everyMinute, true
+	} else if n, ok := s.Minute.starStep(); ok {
+		minute, everyMinute = l.every(cronMinute, n), true
+	} else if minuteSingles && len(minutes) == 1 {
+		minute = fmt.Sprintf(l.atMinute, strconv.Itoa(minutes[0]))
+	} else {
+		minute = fmt.Sprintf(l.atMinutes, l.items(s.Minute, cronMinute, strconv.Itoa))
+	}
+
+	if s.Hour.isStar() {
+		if everyMinute {
+			return minute
+		}
+		return minute + " " + l.pastEveryHour
+	}
+	var hourParts []string
+	for _, item := range s.Hour.Items {
+		between := fmt.Sprintf(l.between, fmt.Sprintf("%02d:00", item.Start), fmt.Sprintf("%02d:59", item.End))
+		switch {
+		case item.Star:
+			hourParts = append(hourParts, l.every(cronHour, item.Step))
+		case item.Step > 1:
+			hourParts = append(hourParts, l.every(cronHour, item.Step)+" "+between)
+		default:
+			hourParts = append(hourParts, between)
+		}
+	}
+	return minute + ", " + l.list(hourParts)
+}
+
+func (s *cronSchedule) describeDays(l *cronLocale) string {
+	var dom, dow string
+	if !s.DayOfMonth.isStar() {
+		days, singles := s.DayOfMonth.singles()
+		if n, ok := s.DayOfMonth.starStep(); ok {
+			dom = l.every(cronDayOfMonth, n)
+		} else if singles && len(days) == 1 {
+			dom = fmt.Sprintf(l.onDay, strconv.Itoa(days[0]))
+		} else {
+			dom = fmt.Sprintf(l.onDays, l.items(s.DayOfMonth, cronDayOfMonth, strconv.Itoa))
+		}
+	}
+	if !s.DayOfWeek.isStar() {
+		days, singles := s.DayOfWeek.singles()
+		switch {
+		case s.DayOfWeek.bits == 0b0111110:
+			dow = l.onWeekdays
+		case s.DayOfWeek.bits == 0b1000001:
+			dow = l.onWeekends
+		case singles && len(days) == 1 || singles && len(days) == 2 && days[0] == 0 && days[1] == 7:
+			dow = fmt.Sprintf(l.onDayOfWeek, nameOr(l.weekdayNamesSingle, l.weekdayNames)[days[0]%7])
+		default:
+			dow = fmt.Sprintf(l.onDaysOfWeek, l.items(s.DayOfWeek, cronDayOfWeek, func(v int) string { return l.weekdayNames[v%7] }))
+		}
+	}
+
+	switch {
+	case dom != "" && dow != "":
+		// Standard cron runs when either day field matches
+		return dom + " " + l.or + " " + dow
+	case dom != "":
+		return dom
+	}
+	return dow
+}
+
+func (s *cronSchedule) describeMonths(l *cronLocale) string {
+	if s.Month.isStar() {
+		return ""
+	}
+	if n, ok := s.Month.starStep(); ok {
+		return l.every(cronMonth, n)
+	}
+	if months, singles := s.Month.singles(); singles && len(months) == 1 {
+		return fmt.Sprintf(l.inMonth, nameOr(l.monthNamesSingle, l.monthNames)[months[0]-1])
+	}
+	return fmt.Sprintf(l.inMonths, l.items(s.Month, cronMonth, func(v int) string { return l.monthNames[v-1] }))
+}
+
+// nameOr returns names, or fallback when the locale has no special forms
+func nameOr(names, fallback []string) []string {
+	if names != nil {
+		return names
+	}
+	return fallback
+}
+
+// cronErrorMessage formats a parse error with a caret under its position
+func cronErrorMessage(expr string, err error) string {
+	msg := fmt.Sprintf("Invalid cron expression: %v", err)
+	if syntaxErr, ok := err.(*cronSyntaxError); ok {
+		msg += "\n" + expr + "\n" + strings.Repeat(" ", utf8.RuneCountInString(expr[:syntaxErr.Pos])) + "^"
+	}
+	return msg
+}
+
+// supportedCronLanguages lists the describe_cron languages for messages
+func supportedCronLanguages() string {
+	var languages []string
+	for lang := range cronLocales {
+		languages = append(languages, lang)
+	}
+	sort.Strings(languages)
+	return strings.Join(languages, ", ")
+}
+
+func addCronTools(mcpServer *server.MCPServer, config *Config) {
+	mcpServer.AddTool(
+		mcp.NewTool("describe_cron",
+			mcp.WithDescription("Validate a cron expression and describe it in plain language, e.g. '0 9 * * MON-FRI' as 'At 09:00 on weekdays'. Accepts standard five-field expressions (minute hour day-of-month month day-of-week) with names, ranges, steps and lists, and the @yearly, @monthly, @weekly, @daily and @hourly macros. Errors point at the offending position."),
+			mcp.WithString("expression",
+				mcp.Description("The cron expression, e.g. '*/15 9-17 * * 1-5'."),
+				mcp.Required(),
+			),
+			mcp.WithString("language",
+				mcp.Description("Language of the description: "+supportedCronLanguages()+". Defaults to the session locale when supported, otherwise en."),
+			),
+			mcp.WithTitleAnnotation("Describe Cron Expression"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		handleDescribeCron(config),
+	)
+
+	mcpServer.AddTool(
+		mcp.NewTool("cron_last_run",
+			mcp.WithDescription("Find the most recent past run of a cron schedule and how long ago it was, plus the next run. With last_success, also tells whether the job is overdue: it missed its last scheduled run by more than the grace period."),
+			mcp.WithString("expression",
+				mcp.Description("The cron expression, e.g. '0 2 * * *'."),
+				mcp.Required(),
+			),
+			mcp.WithString("timezone",
+				mcp.Description("Timezone the schedule runs in. Defaults to the session or server default."),
+			),
+			mcp.WithString("at",
+				mcp.Description("Reference time in RFC 3339. Defaults to now."),
+			),
+			mcp.WithString("last_success",
+				mcp.Description("When the job last completed, in RFC 3339, to check whether it is overdue."),
+			),
+			mcp.WithString("grace",
+				mcp.Description("How long after a scheduled run the job may take before it counts as overdue, as a Go duration. Defaults to 0."),
+			),
+			mcp.WithOutputSchema[cronLastRun](),
+			mcp.WithTitleAnnotation("Cron Last Run"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		handleCronLastRun(config),
+	)
+}
+
+// handleDescribeCron returns a handler for the describe_cron tool
+func handleDescribeCron(config *Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		expr, err := request.RequireString("expression")
+		if err != nil {
+			return toolError(errorInvalidArgument, err.Error()), nil
+		}
+		// An omitted language follows the session locale when it is supported
+		language := strings.ToLower(request.GetString("language", ""))
+		if language == "" {
+			language = "en"
+			if lang := sessionLanguage(ctx); cronLocales[lang] != nil {
+				language = lang
+			}
+		}
+		locale, ok := cronLocales[language]
+		if !ok {
+			return toolError(errorInvalidArgument, fmt.Sprintf("Unsupported language: %s. Supported languages: %s", language, supportedCronLanguages())), nil
+		}
+
+		schedule, err := parseCron(expr)
+		if err != nil {
+			return toolError(errorParse, cronErrorMessage(expr, err)), nil
+		}
+		return mcp.NewToolResultText(schedule.describe(locale)), nil
+	}
+}
+
+// cronLastRun is the structured result of cron_last_run
+type cronLastRun struct {
+	Expression  string  `json:"expression"`
+	Timezone    string  `json:"timezone"`
+	At          string  `json:"at"`
+	LastRun     string  `json:"last_run,omitempty" jsonschema:"Most recent run at or before at, RFC 3339; omitted if none within five years"`
+	Ago         string  `json:"ago,omitempty" jsonschema:"Time since the last run, e.g. 2h 15m"`
+	AgoSeconds  float64 `json:"ago_seconds,omitempty"`
+	NextRun     string  `json:"next_run,omitempty"`
+	NextIn      string  `json:"next_in,omitempty"`
+	LastSuccess string  `json:"last_success,omitempty"`
+	// Overdue is only set when last_success is given
+	Overdue *bool  `json:"overdue,omitempty" jsonschema:"Whether the last success predates the last run and the grace period has passed"`
+	Status  string `json:"status,omitempty"`
+}
+
+// handleCronLastRun returns a handler for the cron_last_run tool
+func handleCronLastRun(config *Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		expr, err := request.RequireString("expression")
+		if err != nil {
+			return toolError(errorInvalidArgument, err.Error()), nil
+		}
+		schedule, err := parseCron(expr)
+		if err != nil {
+			return toolError(errorParse, cronErrorMessage(expr, err)), nil
+		}
+		timezoneStr := request.GetString("timezone", "")
+		loc, err := loadTimezone(ctx, timezoneStr, config)
+		if err != nil {
+			return invalidTimezoneError("Invalid timezone", timezoneStr), nil
+		}
+		at := config.now()
+		if atStr := request.GetString("at", ""); atStr != "" {
+			if at, err = time.Parse(time.RFC3339, atStr); err != nil {
+				return toolError(errorParse, fmt.Sprintf("Invalid at: %s. Please use RFC 3339 format.", atStr)), nil
+			}
+		}
+		at = at.In(loc)
+		graceStr := request.GetString("grace", "0s")
+		grace, err := time.ParseDuration(graceStr)
+		if err != nil || grace < 0 {
+			return toolError(errorParse, fmt.Sprintf("Invalid grace: %s. Please provide a non-negative duration, e.g. 15m.", graceStr)), nil
+		}
+
+		result := cronLastRun{Expression: expr, Timezone: loc.String(), At: at.Format(time.RFC3339)}
+		last := schedule.prev(at)
+		if !last.IsZero() {
+			result.LastRun = last.Format(time.RFC3339)
+			result.Ago = formatCountdown(at.Sub(last))
+			result.AgoSeconds = at.Sub(last).Seconds()
+		}
+		if next := schedule.next(at); !next.IsZero() {
+			result.NextRun = next.Format(time.RFC3339)
+			result.NextIn = formatCountdown(next.Sub(at))
+		}
+
+		if successStr := request.GetString("last_success", ""); successStr != "" {
+			success, err := time.Parse(time.RFC3339, successStr)
+			if err != nil {
+				return toolError(errorParse, fmt.Sprintf("Invalid last_success: %s. Please use RFC 3339 format.", successStr)), nil
+			}
+			result.LastSuccess = success.In(loc).Format(time.RFC3339)
+			overdue := !last.IsZero() && success.Before(last) && at.Sub(last) > grace
+			result.Overdue = &overdue
+			switch {
+			case overdue:
+				result.Status = fmt.Sprintf("overdue: the run due %s ago has not succeeded", formatCountdown(at.Sub(last)))
+			case !last.IsZero() && success.Before(last):
+				result.Status = "pending: the last run is still within its grace period"
+			default:
+				result.Status = "ok"
+			}
+		}
+
+		data, err := json.Marshal(result)
+		if err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to encode result: %v", err)), nil
+		}
+		return mcp.NewToolResultStructured(result, string(data)), nil
+	}
+}
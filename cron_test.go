@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestDescribeCron(t *testing.T) {
+	tests := []struct {
+		expr, lang, expected string
+	}{
+		{"0 9 * * MON-FRI", "en", "At 09:00 on weekdays"},
+		{"*/15 9-17 * * 1-5", "en", "Every 15 minutes, between 09:00 and 17:59 on weekdays"},
+		{"30 * * * *", "en", "At minute 30 past every hour"},
+		{"* * * * *", "en", "Every minute"},
+		{"0 0 1,15 * *", "en", "At 00:00 on days 1 and 15 of the month"},
+		{"0 9,17 * * SAT,SUN", "en", "At 09:00 and 17:00 on weekends"},
+		{"0 12 * JAN,JUL 7", "en", "At 12:00 on Sunday in January and July"},
+		{"0 0 1 * MON", "en", "At 00:00 on day 1 of the month or on Monday"},
+		{"0,30 8-10/2 * * 1,3,5", "en", "At minutes 0 and 30, every 2 hours between 08:00 and 10:59 on Monday, Wednesday and Friday"},
+		{"0 6 */2 3-5 *", "en", "At 06:00 every 2 days in March through May"},
+		{"@monthly", "en", "At 00:00 on day 1 of the month"},
+		{"0 9 * * MON-FRI", "pl", "O 09:00 w dni robocze"},
+		{"*/15 9-17 * * *", "pl", "Co 15 minut, między 09:00 a 17:59"},
+		{"*/2 * * * *", "pl", "Co 2 minuty"},
+		{"0 12 * 1 3", "pl", "O 12:00 w środę w styczniu"},
+		{"0 8 * * 1,3,5", "pl", "O 08:00 w dni: poniedziałek, środa i piątek"},
+		{"0 8 * 6-8 *", "pl", "O 08:00 w miesiącach: czerwiec–sierpień"},
+	}
+	for _, tt := range tests {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = map[string]any{"expression": tt.expr, "language": tt.lang}
+		result, err := handleDescribeCron(&Config{})(context.Background(), req)
+		if err != nil || result.IsError {
+			t.Fatalf("Failed to describe %q: %v %s", tt.expr, err, toolResultText(result))
+		}
+		if got := toolResultText(result); got != tt.expected {
+			t.Errorf("%s (%s): Expected %q but got %q", tt.expr, tt.lang, tt.expected, got)
+		}
+	}
+}
+
+func TestParseCron_Errors(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected string
+		caret    int
+	}{
+		{"0 25 * * *", "hour field at position 3: value 25 is out of range 0-23", 2},
+		{"0 9 * * MON-FOO", `day-of-week field at position 13: invalid value "FOO"`, 12},
+		{"*/0 * * * *", `minute field at position 3: invalid step "0"`, 2},
+		{"0 9 * *", "expected 5 fields", 7},
+		{"0 9 * * * 2025", "expected 5 fields", 10},
+		{"0 9 5-1 * *", "range 5-1 ends before it starts", 4},
+		{"0 9,,10 * * *", "empty list element", 4},
+		{"@reboot", "unknown macro @reboot", 0},
+	}
+	for _, tt := range tests {
+		_, err := parseCron(tt.expr)
+		if err == nil || !strings.Contains(err.Error(), tt.expected) {
+			t.Errorf("%s: Expected an error containing %q but got %v", tt.expr, tt.expected, err)
+			continue
+		}
+		lines := strings.Split(cronErrorMessage(tt.expr, err), "\n")
+		if len(lines) != 3 || strings.Index(lines[2], "^") != tt.caret {
+			t.Errorf("%s: Expected a caret at column %d but got %q", tt.expr, tt.caret, lines)
+		}
+	}
+}
+
+func TestParseCron_Matches(t *testing.T) {
+	if _, err := parseCron("5/20 */6 L-W * 0"); err == nil {
+		t.Errorf("Expected an error for unsupported L-W syntax")
+	}
+	schedule, err := parseCron("5/20 */6 * * 7")
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	for _, m := range []int{5, 25, 45} {
+		if !schedule.Minute.matches(m) {
+			t.Errorf("Expected minute %d to match", m)
+		}
+	}
+	if schedule.Minute.matches(0) || !schedule.Hour.matches(18) || schedule.Hour.matches(3) {
+		t.Errorf("Expected steps to select the right minutes and hours")
+	}
+	if !schedule.DayOfWeek.matches(0) || schedule.DayOfWeek.matches(7) {
+		t.Errorf("Expected day-of-week 7 to be normalized to Sunday (0)")
+	}
+}
+
+func TestCronSchedule_Next(t *testing.T) {
+	warsaw, err := time.LoadLocation("Europe/Warsaw")
+	if err != nil {
+		t.Fatalf("Failed to load timezone: %v", err)
+	}
+	tests := []struct {
+		expr     string
+		after    time.Time
+		expected string
+	}{
+		{"*/15 * * * *", time.Date(2025, 6, 2, 10, 7, 30, 0, warsaw), "2025-06-02T10:15:00+02:00"},
+		{"0 9 * * MON-FRI", time.Date(2025, 6, 6, 9, 0, 0, 0, warsaw), "2025-06-09T09:00:00+02:00"},
+		// Both day fields restricted: the 13th or any Friday
+		{"0 0 13 * FRI", time.Date(2025, 6, 1, 0, 0, 0, 0, warsaw), "2025-06-06T00:00:00+02:00"},
+		{"0 0 29 2 *", time.Date(2025, 1, 1, 0, 0, 0, 0, warsaw), "2028-02-29T00:00:00+01:00"},
+		// 02:30 does not exist on the spring-forward day
+		{"30 2 * * *", time.Date(2025, 3, 30, 0, 0, 0, 0, warsaw), "2025-03-31T02:30:00+02:00"},
+		{"0 0 31 2 *", time.Date(2025, 1, 1, 0, 0, 0, 0, warsaw), "0001-01-01T00:00:00Z"},
+	}
+	for _, tt := range tests {
+		schedule, err := parseCron(tt.expr)
+		if err != nil {
+			t.Fatalf("Failed to parse %q: %v", tt.expr, err)
+		}
+		if got := schedule.next(tt.after).Format(time.RFC3339); got != tt.expected {
+			t.Errorf("%s: Expected %s but got %s", tt.expr, tt.expected, got)
+		}
+	}
+}
+
+func TestCronSchedule_Prev(t *testing.T) {
+	warsaw, err := time.LoadLocation("Europe/Warsaw")
+	if err != nil {
+		t.Fatalf("Failed to load timezone: %v", err)
+	}
+	tests := []struct {
+		expr     string
+		before   time.Time
+		expected string
+	}{
+		{"*/15 * * * *", time.Date(2025, 6, 2, 10, 7, 30, 0, warsaw), "2025-06-02T10:00:00+02:00"},
+		// A run exactly at the reference time counts
+		{"0 9 * * MON-FRI", time.Date(2025, 6, 9, 9, 0, 0, 0, warsaw), "2025-06-09T09:00:00+02:00"},
+		{"0 9 * * MON-FRI", time.Date(2025, 6, 9, 8, 59, 0, 0, warsaw), "2025-06-06T09:00:00+02:00"},
+		{"0 0 29 2 *", time.Date(2025, 1, 1, 0, 0, 0, 0, warsaw), "2024-02-29T00:00:00+01:00"},
+		// 02:30 does not exist on the spring-forward day
+		{"30 2 * * *", time.Date(2025, 3, 30, 12, 0, 0, 0, warsaw), "2025-03-29T02:30:00+01:00"},
+		// 02:30 happens twice on the fall-back day and runs at the first
+		{"30 2 * * *", time.Date(2025, 10, 26, 12, 0, 0, 0, warsaw), "2025-10-26T02:30:00+02:00"},
+		{"0 0 31 2 *", time.Date(2025, 1, 1, 0, 0, 0, 0, warsaw), "0001-01-01T00:00:00Z"},
+	}
+	for _, tt := range tests {
+		schedule, err := parseCron(tt.expr)
+		if err != nil {
+			t.Fatalf("Failed to parse %q: %v", tt.expr, err)
+		}
+		if got := schedule.prev(tt.before).Format(time.RFC3339); got != tt.expected {
+			t.Errorf("%s: Expected %s but got %s", tt.expr, tt.expected, got)
+		}
+	}
+}
+
+func TestCronLastRun(t *testing.T) {
+	clock := newFakeClock(time.Date(2025, 6, 4, 3, 30, 0, 0, time.UTC))
+	handler := handleCronLastRun(&Config{DefaultTimezone: "UTC", Clock: clock})
+	call := func(args map[string]any) *mcp.CallToolResult {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := handler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("Failed to call handler: %v", err)
+		}
+		return result
+	}
+
+	result := call(map[string]any{"expression": "0 2 * * *", "timezone": "Europe/Warsaw"})
+	if result.IsError {
+		t.Fatalf("Failed to find the last run: %s", toolResultText(result))
+	}
+	got := result.StructuredContent.(cronLastRun)
+	if got.LastRun != "2025-06-04T02:00:00+02:00" || got.Ago != "3h 30m" || got.AgoSeconds != 12600 ||
+		got.NextRun != "2025-06-05T02:00:00+02:00" || got.Overdue != nil {
+		t.Errorf("Expected the 02:00 run 3h 30m ago but got %+v", got)
+	}
+
+	tests := []struct {
+		success, grace string
+		overdue        bool
+		status         string
+	}{
+		{"2025-06-04T00:05:00Z", "", false, "ok"},
+		{"2025-06-03T00:05:00Z", "1h", true, "overdue"},
+		{"2025-06-03T00:05:00Z", "4h", false, "pending"},
+	}
+	for _, tt := range tests {
+		args := map[string]any{"expression": "0 2 * * *", "timezone": "Europe/Warsaw", "last_success": tt.success}
+		if tt.grace != "" {
+			args["grace"] = tt.grace
+		}
+		got := call(args).StructuredContent.(cronLastRun)
+		if got.Overdue == nil || *got.Overdue != tt.overdue || !strings.HasPrefix(got.Status, tt.status) {
+			t.Errorf("%s with grace %q: expected overdue=%v (%s) but got %+v", tt.success, tt.grace, tt.overdue, tt.status, got)
+		}
+	}
+
+	for _, args := range []map[string]any{
+		{"expression": "0 25 * * *"},
+		{"expression": "0 2 * * *", "at": "yesterday"},
+		{"expression": "0 2 * * *", "grace": "-5m"},
+		{"expression": "0 2 * * *", "last_success": "2025-06-04"},
+	} {
+		if result := call(args); !result.IsError {
+			t.Errorf("Expected an error for %v but got %s", args, toolResultText(result))
+		}
+	}
+}
@@ -0,0 +1,292 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// datasetMaxSize bounds a download; GeoLite2-City is under 100 MB
+	datasetMaxSize = 512 << 20
+	// datasetFetchTimeout bounds each download
+	datasetFetchTimeout = 5 * time.Minute
+	// datasetChecksumSuffix is appended to a dataset URL's path to find its
+	// published checksum, in sha256sum format
+	datasetChecksumSuffix = ".sha256"
+)
+
+// datasetURLVariables names the variable holding each dataset's URL
+var datasetURLVariables = map[string]string{
+	datasetCalendars: "TIME_CALENDARS_URL",
+	datasetTerms:     "TIME_TERMS_URL",
+	datasetGeoIP:     "TIME_GEOIP_DB_URL",
+}
+
+// datasetValidators parse downloaded content, so a broken download never
+// replaces a good cached copy
+var datasetValidators = map[string]func([]byte) error{
+	datasetCalendars: func(data []byte) error { _, err := parseCalendars(data); return err },
+	datasetTerms:     func(data []byte) error { _, err := parseTermSchedules(data); return err },
+	datasetGeoIP:     func(data []byte) error { _, err := newGeoIPDatabase(data); return err },
+}
+
+// datasetFetcher downloads datasets from URLs into a cache directory, from
+// which they are loaded like local files. Conditional requests with the
+// cached ETag and Last-Modified keep refreshes cheap, and a cached copy
+// keeps the server starting while the URL is unreachable.
+type datasetFetcher struct {
+	urls            map[string]string
+	cacheDir        string
+	requireChecksum bool
+	httpClient      *http.Client
+}
+
+// datasetCacheMeta is stored next to each cached dataset
+type datasetCacheMeta struct {
+	URL          string `json:"url"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	SHA256       string `json:"sha256"`
+	FetchedAt    string `json:"fetched_at"`
+}
+
+// newDatasetFetcher returns nil when no dataset URL is configured
+func newDatasetFetcher(urls map[string]string, cacheDir string, requireChecksum bool) *datasetFetcher {
+	if len(urls) == 0 {
+		return nil
+	}
+	return &datasetFetcher{
+		urls:            urls,
+		cacheDir:        cacheDir,
+		requireChecksum: requireChecksum,
+		httpClient:      &http.Client{Timeout: datasetFetchTimeout},
+	}
+}
+
+// source returns where a dataset is loaded from: its cached download when it
+// has a URL, otherwise file
+func (f *datasetFetcher) source(name, file string) string {
+	if f == nil || f.urls[name] == "" {
+		return file
+	}
+	return f.path(name)
+}
+
+// pending reports whether a dataset with a URL has no cached copy yet
+func (f *datasetFetcher) pending(name string) bool {
+	if f == nil || f.urls[name] == "" {
+		return false
+	}
+	_, err := os.Stat(f.path(name))
+	return err != nil
+}
+
+func (f *datasetFetcher) path(name string) string {
+	return filepath.Join(f.cacheDir, name)
+}
+
+// fetchAll downloads every dataset that changed and returns their names. A
+// dataset that cannot be fetched falls back to its cached copy; it is an
+// error only when there is none.
+func (f *datasetFetcher) fetchAll(ctx context.Context) ([]string, error) {
+	if err := os.MkdirAll(f.cacheDir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create dataset cache: %w", err)
+	}
+	var changed []string
+	for _, name := range slices.Sorted(maps.Keys(f.urls)) {
+		updated, err := f.fetch(ctx, name)
+		if err != nil {
+			if _, statErr := os.Stat(f.path(name)); statErr != nil {
+				return nil, fmt.Errorf("failed to fetch %s dataset from %s: %w", name, f.urls[name], err)
+			}
+			slog.Warn("Dataset fetch failed, using cached copy", "dataset", name, "url", f.urls[name], "error", err)
+			continue
+		}
+		if updated {
+			changed = append(changed, name)
+		}
+	}
+	return changed, nil
+}
+
+// fetch downloads one dataset unless the cached copy is current, and
+// reports whether the cached content changed
+func (f *datasetFetcher) fetch(ctx context.Context, name string) (bool, error) {
+	source := f.urls[name]
+	meta := f.readMeta(name)
+	if meta.URL != source {
+		meta = datasetCacheMeta{}
+	} else if _, err := os.Stat(f.path(name)); err != nil {
+		meta = datasetCacheMeta{}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return false, err
+	}
+	if meta.ETag != "" {
+		req.Header.Set("If-None-Match", meta.ETag)
+	}
+	if meta.LastModified != "" {
+		req.Header.Set("If-Modified-Since", meta.LastModified)
+	}
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotModified && meta.URL != "":
+		return false, nil
+	case resp.StatusCode != http.StatusOK:
+		return false, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, datasetMaxSize+1))
+	if err != nil {
+		return false, err
+	}
+	if len(data) > datasetMaxSize {
+		return false, fmt.Errorf("dataset exceeds %d MB", datasetMaxSize>>20)
+	}
+
+	sum := sha256.Sum256(data)
+	digest := hex.EncodeToString(sum[:])
+	if err := f.verifyChecksum(ctx, source, digest); err != nil {
+		return false, err
+	}
+	if err := datasetValidators[name](data); err != nil {
+		return false, fmt.Errorf("invalid content: %w", err)
+	}
+	if err := writeFileAtomic(f.path(name), data); err != nil {
+		return false, err
+	}
+	f.writeMeta(name, datasetCacheMeta{
+		URL:          source,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		SHA256:       digest,
+		FetchedAt:    time.Now().UTC().Format(time.RFC3339),
+	})
+	return digest != meta.SHA256, nil
+}
+
+// verifyChecksum compares digest with the checksum published next to the
+// dataset. A missing checksum is accepted unless checksums are required.
+func (f *datasetFetcher) verifyChecksum(ctx context.Context, source, digest string) error {
+	u, err := url.Parse(source)
+	if err != nil {
+		return err
+	}
+	u.Path += datasetChecksumSuffix
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch checksum: %w", err)
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound && !f.requireChecksum:
+		return nil
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("failed to fetch checksum %s: unexpected status %s", u, resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return err
+	}
+	// sha256sum prints the digest followed by the file name
+	fields := strings.Fields(string(body))
+	if len(fields) == 0 || !strings.EqualFold(fields[0], digest) {
+		return fmt.Errorf("checksum mismatch: %s does not match the download", u)
+	}
+	return nil
+}
+
+func (f *datasetFetcher) readMeta(name string) datasetCacheMeta {
+	var meta datasetCacheMeta
+	data, err := os.ReadFile(f.path(name) + ".json")
+	if err == nil {
+		err = json.Unmarshal(data, &meta)
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		slog.Warn("Ignoring unreadable dataset cache metadata", "dataset", name, "error", err)
+	}
+	return meta
+}
+
+func (f *datasetFetcher) writeMeta(name string, meta datasetCacheMeta) {
+	data, err := json.Marshal(meta)
+	if err == nil {
+		err = writeFileAtomic(f.path(name)+".json", data)
+	}
+	if err != nil {
+		slog.Warn("Failed to write dataset cache metadata", "dataset", name, "error", err)
+	}
+}
+
+// writeFileAtomic replaces path with data, so readers never see a partial file
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// fetchDatasets downloads the datasets that have URLs at startup and loads
+// them into config, which parsed only the cached copies
+func fetchDatasets(ctx context.Context, config *Config) error {
+	if config.Datasets == nil || len(config.DatasetURLs) == 0 {
+		return nil
+	}
+	if _, err := config.Datasets.reload(ctx); err != nil {
+		return err
+	}
+	set := config.Datasets.load()
+	config.Calendars, config.Terms, config.GeoIP = set.Calendars, set.Terms, set.GeoIP
+	return nil
+}
+
+// startDatasetRefresh re-fetches the remote datasets every interval and
+// reloads them when any changed
+func startDatasetRefresh(config func() *Config, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		for range time.Tick(interval) {
+			registry := config().Datasets
+			if registry == nil {
+				continue
+			}
+			if err := registry.refresh(context.Background()); err != nil {
+				slog.Warn("Dataset refresh failed, keeping previous datasets", "error", err)
+			}
+		}
+	}()
+}
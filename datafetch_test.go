@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// datasetServer serves one dataset with an ETag and, when checksum is set,
+// a sha256sum file next to it
+type datasetServer struct {
+	mu       sync.Mutex
+	body     string
+	checksum string
+	requests int
+}
+
+func (s *datasetServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sum := sha256.Sum256([]byte(s.body))
+	digest := hex.EncodeToString(sum[:])
+	switch r.URL.Path {
+	case "/calendars.yaml":
+		s.requests++
+		etag := `"` + digest[:16] + `"`
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Write([]byte(s.body))
+	case "/calendars.yaml.sha256":
+		if s.checksum == "" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(s.checksum + "  calendars.yaml\n"))
+	}
+}
+
+func (s *datasetServer) set(body, checksum string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.body, s.checksum = body, checksum
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestDatasetFetcher(t *testing.T) {
+	ctx := context.Background()
+	upstream := &datasetServer{}
+	srv := httptest.NewServer(upstream)
+	defer srv.Close()
+	first := "calendars:\n  acme:\n    timezone: Europe/Warsaw\n"
+	upstream.set(first, sha256Hex(first))
+	fetcher := newDatasetFetcher(map[string]string{datasetCalendars: srv.URL + "/calendars.yaml"}, t.TempDir(), false)
+
+	changed, err := fetcher.fetchAll(ctx)
+	if err != nil || !slices.Equal(changed, []string{datasetCalendars}) {
+		t.Fatalf("Expected the first fetch to download the calendars but got %v %v", changed, err)
+	}
+	if data, _ := os.ReadFile(fetcher.source(datasetCalendars, "")); string(data) != first {
+		t.Errorf("Expected the download to be cached but got %q", data)
+	}
+	if changed, err := fetcher.fetchAll(ctx); err != nil || len(changed) != 0 || upstream.requests != 2 {
+		t.Errorf("Expected a conditional request with no change but got %v %v after %d requests", changed, err, upstream.requests)
+	}
+
+	second := "calendars:\n  acme: {}\n  gulf: {}\n"
+	upstream.set(second, sha256Hex(first))
+	if _, err := fetcher.fetch(ctx, datasetCalendars); err == nil {
+		t.Error("Expected a checksum mismatch to be rejected")
+	}
+	upstream.set("calendars: [", "")
+	if _, err := fetcher.fetch(ctx, datasetCalendars); err == nil {
+		t.Error("Expected invalid content to be rejected")
+	}
+	if data, _ := os.ReadFile(fetcher.source(datasetCalendars, "")); string(data) != first {
+		t.Errorf("Expected rejected downloads to keep the cached copy but got %q", data)
+	}
+
+	upstream.set(second, "")
+	if changed, err := fetcher.fetchAll(ctx); err != nil || !slices.Equal(changed, []string{datasetCalendars}) {
+		t.Errorf("Expected an unpublished checksum to be accepted but got %v %v", changed, err)
+	}
+	strict := newDatasetFetcher(fetcher.urls, t.TempDir(), true)
+	if _, err := strict.fetchAll(ctx); err == nil {
+		t.Error("Expected a missing checksum to fail when checksums are required")
+	}
+
+	// The cached copy outlives the server; without one, startup fails
+	srv.Close()
+	if _, err := fetcher.fetchAll(ctx); err != nil {
+		t.Errorf("Expected the cached copy to be used while the URL is down: %v", err)
+	}
+	if _, err := newDatasetFetcher(fetcher.urls, t.TempDir(), false).fetchAll(ctx); err == nil {
+		t.Error("Expected a fetch with no cached copy to fail while the URL is down")
+	}
+}
+
+func TestDatasetURLSettings(t *testing.T) {
+	upstream := &datasetServer{}
+	srv := httptest.NewServer(upstream)
+	defer srv.Close()
+	upstream.set("calendars:\n  acme: {}\n", "")
+	t.Setenv("TIME_CALENDARS_URL", srv.URL+"/calendars.yaml")
+	cacheDir := filepath.Join(t.TempDir(), "datasets")
+	t.Setenv("TIME_DATASET_CACHE_DIR", cacheDir)
+
+	// Parsing the configuration, as --validate-config does, downloads nothing
+	config, err := NewConfig()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if _, err := os.Stat(cacheDir); !os.IsNotExist(err) || upstream.requests != 0 {
+		t.Errorf("Expected no download or cache directory before startup but got %d requests, %v", upstream.requests, err)
+	}
+
+	if err := fetchDatasets(context.Background(), config); err != nil {
+		t.Fatalf("Failed to fetch datasets: %v", err)
+	}
+	if len(config.Calendars) != 1 {
+		t.Errorf("Expected the downloaded calendars in the config but got %d", len(config.Calendars))
+	}
+	if _, err := calendarByName(config, "acme"); err != nil {
+		t.Errorf("Expected the downloaded calendar to be loaded: %v", err)
+	}
+	if v := config.datasets().Versions[datasetCalendars]; v.URL != srv.URL+"/calendars.yaml" {
+		t.Errorf("Expected the version to record the URL but got %+v", v)
+	}
+
+	upstream.set("calendars:\n  acme: {}\n  gulf: {}\n", "")
+	if err := config.Datasets.refresh(context.Background()); err != nil {
+		t.Fatalf("Failed to refresh datasets: %v", err)
+	}
+	if _, err := calendarByName(config, "gulf"); err != nil {
+		t.Errorf("Expected the refreshed calendar to be loaded: %v", err)
+	}
+
+	t.Setenv("TIME_CALENDARS_FILE", "/etc/calendars.yaml")
+	if _, err := NewConfig(); err == nil {
+		t.Error("Expected a file and a URL for the same dataset to be rejected")
+	}
+	t.Setenv("TIME_CALENDARS_FILE", "")
+	t.Setenv("TIME_CALENDARS_URL", "ftp://example.com/calendars.yaml")
+	if _, err := NewConfig(); err == nil {
+		t.Error("Expected a non-HTTP URL to be rejected")
+	}
+}
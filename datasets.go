@@ -0,0 +1,324 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Names of the reloadable datasets, as reported in /version
+const (
+	datasetCalendars = "calendars"
+	datasetTerms     = "terms"
+	datasetGeoIP     = "geoip"
+)
+
+// datasetsReloadPath is the admin endpoint that re-reads the datasets
+const datasetsReloadPath = "/datasets/reload"
+
+// datasetVersion identifies the loaded content of a dataset
+type datasetVersion struct {
+	Source   string `json:"source"`
+	URL      string `json:"url,omitempty" jsonschema:"Where the dataset is downloaded from, when it is not a local file"`
+	SHA256   string `json:"sha256" jsonschema:"SHA-256 of the file, which changes with any edit"`
+	Entries  int    `json:"entries,omitempty" jsonschema:"Number of calendars or schedules defined"`
+	LoadedAt string `json:"loaded_at"`
+}
+
+// datasetSources are the files the datasets are read from
+type datasetSources struct {
+	Calendars string
+	Terms     string
+	GeoIP     string
+	// TenantCalendars are calendars referenced by tenants, which a reload
+	// must not drop
+	TenantCalendars []string
+	// Fetcher downloads the datasets that have URLs into the files above
+	Fetcher *datasetFetcher
+}
+
+// datasetSet is one consistent snapshot of the file-backed datasets
+type datasetSet struct {
+	Sources   datasetSources
+	Calendars map[string]*businessCalendar
+	Terms     map[string]*termSchedule
+	GeoIP     *geoIPDatabase
+	Versions  map[string]datasetVersion
+}
+
+// datasetRegistry holds the business calendars, academic terms and GeoIP
+// database that tools read on each call. The registry outlives config
+// reloads, so the data can be replaced by reload_datasets or SIGHUP without
+// re-registering tools.
+type datasetRegistry struct {
+	mu      sync.Mutex // serializes reloads
+	current atomic.Pointer[datasetSet]
+}
+
+// newDatasetRegistry wraps the datasets parsed into config
+func newDatasetRegistry(config *Config) *datasetRegistry {
+	set := &datasetSet{
+		Sources: datasetSources{
+			Calendars: config.CalendarsFile,
+			Terms:     config.TermsFile,
+			Fetcher:   newDatasetFetcher(config.DatasetURLs, config.DatasetCacheDir, config.DatasetRequireChecksum),
+		},
+		Calendars: config.Calendars,
+		Terms:     config.Terms,
+		GeoIP:     config.GeoIP,
+		Versions:  make(map[string]datasetVersion),
+	}
+	// The GeoIP file is only read when geoip detection is enabled
+	if slices.Contains(config.ClientTZDetect, clientTZGeoIP) {
+		set.Sources.GeoIP = config.GeoIPFile
+	}
+	for _, t := range config.Tenants {
+		if t.Calendar != "" {
+			set.Sources.TenantCalendars = append(set.Sources.TenantCalendars, t.Calendar)
+		}
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	for name, path := range set.Sources.files() {
+		if set.Sources.Fetcher.pending(name) {
+			set.Versions[name] = datasetVersion{Source: path, URL: config.DatasetURLs[name]}
+			continue
+		}
+		digest, err := fileDigest(path)
+		if err != nil {
+			slog.Warn("Failed to fingerprint dataset", "dataset", name, "file", path, "error", err)
+		}
+		set.Versions[name] = datasetVersion{Source: path, URL: config.DatasetURLs[name], SHA256: digest, Entries: set.entries(name), LoadedAt: now}
+	}
+	r := &datasetRegistry{}
+	r.current.Store(set)
+	return r
+}
+
+// files maps the configured datasets to their files
+func (s datasetSources) files() map[string]string {
+	files := make(map[string]string)
+	for name, path := range map[string]string{datasetCalendars: s.Calendars, datasetTerms: s.Terms, datasetGeoIP: s.GeoIP} {
+		if path != "" {
+			files[name] = path
+		}
+	}
+	return files
+}
+
+func (s *datasetSet) entries(name string) int {
+	switch name {
+	case datasetCalendars:
+		return len(s.Calendars)
+	case datasetTerms:
+		return len(s.Terms)
+	}
+	return 0
+}
+
+// load returns the current snapshot
+func (r *datasetRegistry) load() *datasetSet {
+	return r.current.Load()
+}
+
+// replace takes on the datasets of another registry, used when a config
+// reload has parsed them afresh
+func (r *datasetRegistry) replace(other *datasetRegistry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.current.Store(other.load())
+}
+
+// datasetReload is the structured result of reload_datasets
+type datasetReload struct {
+	Datasets map[string]datasetVersion `json:"datasets"`
+	Changed  []string                  `json:"changed" jsonschema:"Datasets whose content differs from the previous load"`
+}
+
+// reload downloads the datasets that have URLs, then re-reads every dataset
+// from its file
+func (r *datasetRegistry) reload(ctx context.Context) (datasetReload, error) {
+	if fetcher := r.load().Sources.Fetcher; fetcher != nil {
+		if _, err := fetcher.fetchAll(ctx); err != nil {
+			return datasetReload{}, err
+		}
+	}
+	return r.reloadFiles()
+}
+
+// refresh downloads the datasets that have URLs and reloads them if any
+// changed
+func (r *datasetRegistry) refresh(ctx context.Context) error {
+	fetcher := r.load().Sources.Fetcher
+	if fetcher == nil {
+		return nil
+	}
+	changed, err := fetcher.fetchAll(ctx)
+	if err != nil || len(changed) == 0 {
+		return err
+	}
+	result, err := r.reloadFiles()
+	if err != nil {
+		return err
+	}
+	slog.Info("Datasets refreshed", "changed", result.Changed)
+	return nil
+}
+
+// reloadFiles re-reads every dataset from its file. Either all of them are
+// replaced or, if any fails to load, none is.
+func (r *datasetRegistry) reloadFiles() (datasetReload, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	previous := r.load()
+	set := &datasetSet{
+		Sources:   previous.Sources,
+		Calendars: previous.Calendars,
+		Terms:     previous.Terms,
+		GeoIP:     previous.GeoIP,
+		Versions:  maps.Clone(previous.Versions),
+	}
+	result := datasetReload{Datasets: set.Versions, Changed: []string{}}
+	now := time.Now().UTC().Format(time.RFC3339)
+	files := set.Sources.files()
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		path := files[name]
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return datasetReload{}, fmt.Errorf("failed to read %s dataset: %w", name, err)
+		}
+		switch name {
+		case datasetCalendars:
+			set.Calendars, err = parseCalendars(data)
+		case datasetTerms:
+			set.Terms, err = parseTermSchedules(data)
+		case datasetGeoIP:
+			set.GeoIP, err = newGeoIPDatabase(data)
+		}
+		if err != nil {
+			return datasetReload{}, fmt.Errorf("invalid %s dataset %s: %w", name, path, err)
+		}
+		sum := sha256.Sum256(data)
+		version := datasetVersion{Source: path, URL: previous.Versions[name].URL, SHA256: hex.EncodeToString(sum[:]), Entries: set.entries(name), LoadedAt: now}
+		if version.SHA256 != previous.Versions[name].SHA256 {
+			result.Changed = append(result.Changed, name)
+		}
+		set.Versions[name] = version
+	}
+	for _, name := range set.Sources.TenantCalendars {
+		if _, ok := set.Calendars[name]; !ok {
+			return datasetReload{}, fmt.Errorf("calendar %q is used by a tenant but no longer defined", name)
+		}
+	}
+	r.current.Store(set)
+	return result, nil
+}
+
+// fileDigest returns the hex SHA-256 of a file
+func fileDigest(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// datasets returns the current datasets. Configs built without a registry,
+// as in tests, use their parsed fields.
+func (c *Config) datasets() *datasetSet {
+	if c.Datasets == nil {
+		return &datasetSet{Calendars: c.Calendars, Terms: c.Terms, GeoIP: c.GeoIP}
+	}
+	return c.Datasets.load()
+}
+
+// reloadDatasets reloads the datasets and logs the outcome
+func reloadDatasets(ctx context.Context, config *Config, username string) (datasetReload, error) {
+	if config.Datasets == nil {
+		return datasetReload{}, fmt.Errorf("no datasets are configured")
+	}
+	result, err := config.Datasets.reload(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Dataset reload failed, keeping previous datasets", "username", username, "error", err)
+		return datasetReload{}, err
+	}
+	slog.InfoContext(ctx, "Datasets reloaded", "username", username, "changed", result.Changed)
+	return result, nil
+}
+
+// addDatasetTools registers reload_datasets
+func addDatasetTools(mcpServer *server.MCPServer, config *Config) {
+	mcpServer.AddTool(
+		mcp.NewTool("reload_datasets",
+			mcp.WithDescription("Re-read the business calendars, academic terms and GeoIP database from their files, downloading those configured with a URL, without restarting the server. Either every dataset is replaced or, if one fails to load, none is. Requires the admin role."),
+			mcp.WithOutputSchema[datasetReload](),
+			mcp.WithTitleAnnotation("Reload Datasets"),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		handleReloadDatasets(config),
+	)
+}
+
+// handleReloadDatasets returns a handler for the reload_datasets tool
+func handleReloadDatasets(config *Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if denied := requireAdmin(ctx, config); denied != nil {
+			return denied, nil
+		}
+		_, username, _ := getUserInfo(ctx)
+		result, err := reloadDatasets(ctx, config, username)
+		if err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to reload datasets: %v", err)), nil
+		}
+		data, err := json.Marshal(result)
+		if err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to encode result: %v", err)), nil
+		}
+		return mcp.NewToolResultStructured(result, string(data)), nil
+	}
+}
+
+// addDatasetsEndpoint registers POST /datasets/reload for admins
+func addDatasetsEndpoint(mux *http.ServeMux, config *Config, rt *httpRuntime) {
+	if config.Datasets == nil || rt == nil {
+		return
+	}
+	mux.HandleFunc(datasetsReloadPath, func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := authenticateAdminEndpoint(w, r, config, rt)
+		if !ok || !allowRESTMethod(w, r, http.MethodPost) {
+			return
+		}
+		if claims == nil {
+			writeJSONResponse(w, r, http.StatusForbidden, restError{Error: "Forbidden: reloading datasets requires authentication"})
+			return
+		}
+		result, err := reloadDatasets(r.Context(), config, claims.Username)
+		if err != nil {
+			writeJSONResponse(w, r, http.StatusUnprocessableEntity, restError{Error: err.Error()})
+			return
+		}
+		writeJSONResponse(w, r, http.StatusOK, result)
+	})
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestReloadDatasets(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "calendars.yaml")
+	write := func(content string) {
+		if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("calendars:\n  acme:\n    timezone: Europe/Warsaw\n")
+	t.Setenv("TIME_CALENDARS_FILE", file)
+	config, err := NewConfig()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	before := config.datasets().Versions[datasetCalendars]
+	if before.Source != file || len(before.SHA256) != 64 || before.Entries != 1 {
+		t.Fatalf("Expected a version for the calendars file but got %+v", before)
+	}
+
+	write("calendars:\n  acme:\n    timezone: Europe/Warsaw\n  gulf:\n    weekend: [friday, saturday]\n")
+	result, err := handleReloadDatasets(config)(context.Background(), mcp.CallToolRequest{})
+	if err != nil || result.IsError {
+		t.Fatalf("Expected the reload to succeed but got %v %v", err, result.Content)
+	}
+	reload := result.StructuredContent.(datasetReload)
+	if !slices.Equal(reload.Changed, []string{datasetCalendars}) || reload.Datasets[datasetCalendars].Entries != 2 {
+		t.Errorf("Expected the calendars to change but got %+v", reload)
+	}
+	if _, err := calendarByName(config, "gulf"); err != nil {
+		t.Errorf("Expected the reloaded calendar to be available: %v", err)
+	}
+	if info := currentBuildInfo(config); info.Datasets[datasetCalendars].SHA256 == before.SHA256 {
+		t.Errorf("Expected /version to report the new calendars version")
+	}
+
+	// A broken file leaves the loaded calendars in place
+	write("calendars:\n  acme:\n    working_hours: late\n")
+	if _, err := config.Datasets.reload(context.Background()); err == nil {
+		t.Error("Expected an invalid calendars file to be rejected")
+	}
+	if _, err := calendarByName(config, "gulf"); err != nil {
+		t.Errorf("Expected the previous calendars to stay loaded: %v", err)
+	}
+
+	// Calendars referenced by tenants cannot be dropped
+	write("calendars:\n  gulf:\n    weekend: [friday, saturday]\n")
+	set := *config.datasets()
+	set.Sources.TenantCalendars = []string{"acme"}
+	config.Datasets.current.Store(&set)
+	if _, err := config.Datasets.reload(context.Background()); err == nil {
+		t.Error("Expected a reload dropping a tenant's calendar to be rejected")
+	}
+}
+
+func TestDatasetsEndpoint(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "calendars.yaml")
+	if err := os.WriteFile(file, []byte("calendars:\n  acme: {}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	config := &Config{
+		AuthEnabled:   true,
+		AuthSecretKey: "test-secret-key-that-is-long-enough-for-hs256",
+		AuthIssuer:    "test-issuer",
+		AuthAudience:  "test-audience",
+		AuthAdminRole: "admin",
+		CalendarsFile: file,
+	}
+	config.Datasets = newDatasetRegistry(config)
+	contextFunc := &reloadableContextFunc{}
+	fn, auth, err := createHTTPMiddleware(config)
+	if err != nil {
+		t.Fatalf("Failed to create HTTP middleware: %v", err)
+	}
+	contextFunc.store(fn, auth)
+	handler := createCustomHTTPHandler(http.NotFoundHandler(), config, &httpRuntime{contextFunc: contextFunc})
+
+	admin, _ := auth.GenerateToken("1", "ada", "admin", 1)
+	user, _ := auth.GenerateToken("2", "uma", "user", 1)
+	if rec := serveREST(handler, "POST", datasetsReloadPath, "", admin); rec.Code != http.StatusOK {
+		t.Errorf("Expected status 200 but got %d %s", rec.Code, rec.Body.String())
+	}
+	if rec := serveREST(handler, "POST", datasetsReloadPath, "", user); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 for a non-admin but got %d", rec.Code)
+	}
+	if rec := serveREST(handler, "GET", datasetsReloadPath, "", admin); rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405 but got %d", rec.Code)
+	}
+	os.Remove(file)
+	if rec := serveREST(handler, "POST", datasetsReloadPath, "", admin); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422 for a missing file but got %d", rec.Code)
+	}
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// zodiacSign is a Western (tropical) zodiac sign with the conventional dates
+// it starts on; the sun's actual ingress varies by a day from year to year
+type zodiacSign struct {
+	Name    string
+	Symbol  string
+	Element string
+	month   time.Month
+	day     int
+}
+
+// zodiacSigns are in calendar order of their start dates
+var zodiacSigns = []zodiacSign{
+	{"Aquarius", "♒", "air", time.January, 20},
+	{"Pisces", "♓", "water", time.February, 19},
+	{"Aries", "♈", "fire", time.March, 21},
+	{"Taurus", "♉", "earth", time.April, 20},
+	{"Gemini", "♊", "air", time.May, 21},
+	{"Cancer", "♋", "water", time.June, 21},
+	{"Leo", "♌", "fire", time.July, 23},
+	{"Virgo", "♍", "earth", time.August, 23},
+	{"Libra", "♎", "air", time.September, 23},
+	{"Scorpio", "♏", "water", time.October, 23},
+	{"Sagittarius", "♐", "fire", time.November, 22},
+	{"Capricorn", "♑", "earth", time.December, 22},
+}
+
+// birthstones are the modern birthstones of each month, the traditional
+// first, as listed by the American Gem Society
+var birthstones = [12][]string{
+	{"garnet"},
+	{"amethyst"},
+	{"aquamarine", "bloodstone"},
+	{"diamond"},
+	{"emerald"},
+	{"pearl", "alexandrite", "moonstone"},
+	{"ruby"},
+	{"peridot", "spinel", "sardonyx"},
+	{"sapphire"},
+	{"opal", "tourmaline"},
+	{"topaz", "citrine"},
+	{"turquoise", "zircon", "tanzanite"},
+}
+
+// observances are widely known observances in the holiday rule syntax of
+// business calendars; regional ones name their region
+var observances = func() []holiday {
+	definitions := []holidayDefinition{
+		{Rule: "01-01", Name: "New Year's Day"},
+		{Rule: "01-06", Name: "Epiphany"},
+		{Rule: "third monday of january", Name: "Martin Luther King Jr. Day (US)"},
+		{Rule: "02-02", Name: "Groundhog Day (US, Canada)"},
+		{Rule: "02-14", Name: "Valentine's Day"},
+		{Rule: "third monday of february", Name: "Presidents' Day (US)"},
+		{Rule: "03-08", Name: "International Women's Day"},
+		{Rule: "03-17", Name: "St. Patrick's Day"},
+		{Rule: "04-01", Name: "April Fools' Day"},
+		{Rule: "04-22", Name: "Earth Day"},
+		{Rule: "05-01", Name: "International Workers' Day"},
+		{Rule: "second sunday of may", Name: "Mother's Day (US and others)"},
+		{Rule: "last monday of may", Name: "Memorial Day (US)"},
+		{Rule: "third sunday of june", Name: "Father's Day (US, UK and others)"},
+		{Rule: "07-04", Name: "Independence Day (US)"},
+		{Rule: "first monday of september", Name: "Labor Day (US)"},
+		{Rule: "second monday of october", Name: "Thanksgiving (Canada)"},
+		{Rule: "10-31", Name: "Halloween"},
+		{Rule: "11-01", Name: "All Saints' Day"},
+		{Rule: "11-11", Name: "Armistice Day, Remembrance Day and Veterans Day"},
+		{Rule: "fourth thursday of november", Name: "Thanksgiving (US)"},
+		{Rule: "12-24", Name: "Christmas Eve"},
+		{Rule: "12-25", Name: "Christmas Day"},
+		{Rule: "12-26", Name: "Boxing Day"},
+		{Rule: "12-31", Name: "New Year's Eve"},
+		{Rule: "easter-47", Name: "Shrove Tuesday"},
+		{Rule: "easter-46", Name: "Ash Wednesday"},
+		{Rule: "easter-21", Name: "Mothering Sunday (UK)"},
+		{Rule: "easter-7", Name: "Palm Sunday"},
+		{Rule: "easter-2", Name: "Good Friday"},
+		{Rule: "easter", Name: "Easter Sunday"},
+		{Rule: "easter+1", Name: "Easter Monday"},
+		{Rule: "easter+39", Name: "Ascension Day"},
+		{Rule: "easter+49", Name: "Pentecost"},
+	}
+	built := make([]holiday, len(definitions))
+	for i, d := range definitions {
+		h, err := d.build()
+		if err != nil {
+			panic(err)
+		}
+		built[i] = h
+	}
+	return built
+}()
+
+// zodiacSignOn returns the index in zodiacSigns of the sign of a calendar date
+func zodiacSignOn(month time.Month, day int) int {
+	// Capricorn spans the new year
+	sign := len(zodiacSigns) - 1
+	for i, s := range zodiacSigns {
+		if month > s.month || month == s.month && day >= s.day {
+			sign = i
+		}
+	}
+	return sign
+}
+
+// dateFacts is the structured result of date_facts
+type dateFacts struct {
+	Date        string     `json:"date"`
+	Weekday     string     `json:"weekday"`
+	DayOfYear   int        `json:"day_of_year"`
+	Zodiac      zodiacFact `json:"zodiac"`
+	Birthstones []string   `json:"birthstones" jsonschema:"Birthstones of the month, the traditional one first"`
+	Observances []string   `json:"observances" jsonschema:"Widely known observances on the date; regional ones name their region"`
+}
+
+type zodiacFact struct {
+	Sign    string `json:"sign"`
+	Symbol  string `json:"symbol"`
+	Element string `json:"element"`
+	Dates   string `json:"dates" jsonschema:"Conventional date range of the sign, e.g. March 21 - April 19"`
+}
+
+func addDateFactsTool(mcpServer *server.MCPServer, config *Config) {
+	mcpServer.AddTool(
+		mcp.NewTool("date_facts",
+			mcp.WithDescription("Fun facts about a date for consumer-facing answers: the Western zodiac sign (conventional date ranges), the month's birthstones and widely known observances such as Valentine's Day, Easter or Thanksgiving (US)."),
+			mcp.WithString("date",
+				mcp.Description("Date in YYYY-MM-DD format. Defaults to today in timezone."),
+			),
+			mcp.WithString("timezone",
+				mcp.Description("Timezone that decides today. Defaults to the session or server default."),
+			),
+			mcp.WithOutputSchema[dateFacts](),
+			mcp.WithTitleAnnotation("Date Facts"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		handleDateFacts(config),
+	)
+}
+
+// handleDateFacts returns a handler for the date_facts tool
+func handleDateFacts(config *Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		timezoneStr := request.GetString("timezone", "")
+		loc, err := loadTimezone(ctx, timezoneStr, config)
+		if err != nil {
+			return invalidTimezoneError("Invalid timezone", timezoneStr), nil
+		}
+		date := config.now().In(loc)
+		if dateStr := request.GetString("date", ""); dateStr != "" {
+			if date, err = time.ParseInLocation("2006-01-02", dateStr, loc); err != nil {
+				return toolError(errorParse, fmt.Sprintf("Invalid date format: %s. Please provide date in YYYY-MM-DD format.", dateStr)), nil
+			}
+		}
+
+		year, month, day := date.Date()
+		i := zodiacSignOn(month, day)
+		sign, next := zodiacSigns[i], zodiacSigns[(i+1)%len(zodiacSigns)]
+		// A sign ends the day before the next one starts
+		end := time.Date(year, next.month, next.day-1, 0, 0, 0, 0, time.UTC)
+		facts := dateFacts{
+			Date:      date.Format("2006-01-02"),
+			Weekday:   date.Weekday().String(),
+			DayOfYear: date.YearDay(),
+			Zodiac: zodiacFact{
+				Sign:    sign.Name,
+				Symbol:  sign.Symbol,
+				Element: sign.Element,
+				Dates:   fmt.Sprintf("%s %d - %s %d", sign.month, sign.day, end.Month(), end.Day()),
+			},
+			Birthstones: birthstones[month-1],
+			Observances: []string{},
+		}
+		for _, h := range observances {
+			if h.matches(year, month, day) {
+				facts.Observances = append(facts.Observances, h.Name)
+			}
+		}
+
+		data, err := json.Marshal(facts)
+		if err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to encode result: %v", err)), nil
+		}
+		return mcp.NewToolResultStructured(facts, string(data)), nil
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestZodiacSignOn(t *testing.T) {
+	tests := []struct {
+		month time.Month
+		day   int
+		want  string
+	}{
+		{time.January, 1, "Capricorn"},
+		{time.January, 19, "Capricorn"},
+		{time.January, 20, "Aquarius"},
+		{time.March, 20, "Pisces"},
+		{time.March, 21, "Aries"},
+		{time.December, 21, "Sagittarius"},
+		{time.December, 22, "Capricorn"},
+	}
+	for _, tt := range tests {
+		if got := zodiacSigns[zodiacSignOn(tt.month, tt.day)].Name; got != tt.want {
+			t.Errorf("Expected %s %d to be %s but got %s", tt.month, tt.day, tt.want, got)
+		}
+	}
+}
+
+func TestDateFacts(t *testing.T) {
+	clock := newFakeClock(time.Date(2025, 2, 14, 12, 0, 0, 0, time.UTC))
+	handler := handleDateFacts(&Config{DefaultTimezone: "UTC", Clock: clock})
+	call := func(args map[string]any) *mcp.CallToolResult {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := handler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("Failed to call handler: %v", err)
+		}
+		return result
+	}
+
+	got := call(map[string]any{}).StructuredContent.(dateFacts)
+	if got.Date != "2025-02-14" || got.Zodiac.Sign != "Aquarius" || got.Zodiac.Dates != "January 20 - February 18" ||
+		!slices.Equal(got.Birthstones, []string{"amethyst"}) || !slices.Equal(got.Observances, []string{"Valentine's Day"}) {
+		t.Errorf("Expected Valentine's Day facts but got %+v", got)
+	}
+
+	// Movable observances follow Easter and weekday rules
+	got = call(map[string]any{"date": "2025-04-20"}).StructuredContent.(dateFacts)
+	if !slices.Equal(got.Observances, []string{"Easter Sunday"}) || got.Zodiac.Sign != "Taurus" {
+		t.Errorf("Expected Easter Sunday in Taurus but got %+v", got)
+	}
+	got = call(map[string]any{"date": "2025-11-27"}).StructuredContent.(dateFacts)
+	if !slices.Equal(got.Observances, []string{"Thanksgiving (US)"}) {
+		t.Errorf("Expected Thanksgiving but got %+v", got.Observances)
+	}
+	got = call(map[string]any{"date": "2025-12-25"}).StructuredContent.(dateFacts)
+	if got.Zodiac.Sign != "Capricorn" || got.Zodiac.Dates != "December 22 - January 19" || got.DayOfYear != 359 {
+		t.Errorf("Expected Capricorn on day 359 but got %+v", got)
+	}
+
+	if result := call(map[string]any{"date": "25-12-2025"}); toolErrorCodeOf(result) != errorParse {
+		t.Errorf("Expected %s for an invalid date but got %s", errorParse, toolResultText(result))
+	}
+}
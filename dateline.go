@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// dateLineCheck is the structured result of date_line_check
+type dateLineCheck struct {
+	SourceTimezone string `json:"source_timezone"`
+	SourceTime     string `json:"source_time" jsonschema:"Local time in the source timezone, RFC 3339"`
+	SourceDate     string `json:"source_date" jsonschema:"Local date and weekday in the source timezone"`
+	TargetTimezone string `json:"target_timezone"`
+	TargetTime     string `json:"target_time" jsonschema:"Local time in the target timezone, RFC 3339"`
+	TargetDate     string `json:"target_date" jsonschema:"Local date and weekday in the target timezone"`
+	DayDifference  int    `json:"day_difference" jsonschema:"Target date minus source date in days, from -2 to 2"`
+	Relationship   string `json:"relationship" jsonschema:"same day, next day, previous day, or two days ahead or behind"`
+	OffsetChange   string `json:"offset_change" jsonschema:"Target UTC offset minus source UTC offset, e.g. -23h"`
+	// DateLineCrossed is omitted when either zone has no known location
+	DateLineCrossed *bool  `json:"date_line_crossed,omitempty" jsonschema:"Whether the International Date Line lies between the zones on the shorter way around the globe"`
+	Explanation     string `json:"explanation"`
+}
+
+// dayRelationship names the difference between two local dates in days
+func dayRelationship(days int) string {
+	switch days {
+	case 0:
+		return "same day"
+	case 1:
+		return "next day"
+	case -1:
+		return "previous day"
+	case 2:
+		return "two days ahead"
+	case -2:
+		return "two days behind"
+	}
+	return fmt.Sprintf("%+d days", days)
+}
+
+// crossesDateLine reports whether the date line lies between two places
+// with the given UTC offsets in seconds. Along the shorter arc between
+// them the offset should change by about one hour per 15 degrees; a
+// change that is off by more than half a day means the calendar jumps a
+// day on the way, wherever the line bends around island groups.
+func crossesDateLine(from, to coordinates, fromOffset, toOffset int) bool {
+	east := math.Mod(to.Longitude-from.Longitude+540, 360) - 180
+	expected := east / 15 * 3600
+	return math.Abs(float64(toOffset-fromOffset)-expected) > 12*3600
+}
+
+// calendarDays returns the number of calendar days from a's local date to b's
+func calendarDays(a, b time.Time) int {
+	dayA := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	dayB := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(dayB.Sub(dayA).Hours() / 24)
+}
+
+func addDateLineTool(mcpServer *server.MCPServer, config *Config) {
+	mcpServer.AddTool(
+		mcp.NewTool("date_line_check",
+			mcp.WithDescription("Explain the calendar-day relationship of a conversion between two timezones (same day, next day or previous day), with the local dates on each side, and flag when the International Date Line lies between them, as in most Pacific conversions."),
+			mcp.WithString("source_timezone",
+				mcp.Description("Source IANA timezone name. Defaults to the session or server default."),
+			),
+			mcp.WithString("time",
+				mcp.Description("Time in the source timezone in 24-hour format (HH:MM). Defaults to now."),
+			),
+			mcp.WithString("date",
+				mcp.Description("Date of the time in the source timezone, YYYY-MM-DD. Defaults to today."),
+			),
+			mcp.WithString("target_timezone",
+				mcp.Description("Target IANA timezone name, e.g. Pacific/Auckland."),
+				mcp.Required(),
+			),
+			mcp.WithOutputSchema[dateLineCheck](),
+			mcp.WithTitleAnnotation("Date Line Check"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		handleDateLineCheck(config),
+	)
+}
+
+// handleDateLineCheck returns a handler for the date_line_check tool
+func handleDateLineCheck(config *Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		sourceTime, targetTime, _, _, errResult := resolveConversion(ctx, request, config)
+		if errResult != nil {
+			return errResult, nil
+		}
+		if dateStr := request.GetString("date", ""); dateStr != "" {
+			date, err := time.Parse("2006-01-02", dateStr)
+			if err != nil {
+				return toolError(errorParse, fmt.Sprintf("Invalid date: %s. Please use YYYY-MM-DD format.", dateStr)), nil
+			}
+			hour, minute, sec := sourceTime.Clock()
+			sourceTime = time.Date(date.Year(), date.Month(), date.Day(), hour, minute, sec, 0, sourceTime.Location())
+			targetTime = sourceTime.In(targetTime.Location())
+		}
+
+		_, sourceOffset := sourceTime.Zone()
+		_, targetOffset := targetTime.Zone()
+		days := calendarDays(sourceTime, targetTime)
+		check := dateLineCheck{
+			SourceTimezone: sourceTime.Location().String(),
+			SourceTime:     sourceTime.Format(time.RFC3339),
+			SourceDate:     sourceTime.Format("Monday, 2006-01-02"),
+			TargetTimezone: targetTime.Location().String(),
+			TargetTime:     targetTime.Format(time.RFC3339),
+			TargetDate:     targetTime.Format("Monday, 2006-01-02"),
+			DayDifference:  days,
+			Relationship:   dayRelationship(days),
+			OffsetChange:   formatOffsetDifference(targetOffset - sourceOffset),
+		}
+		check.Explanation = fmt.Sprintf("%s %s in %s is %s %s in %s (%s).",
+			check.SourceDate, sourceTime.Format("15:04"), check.SourceTimezone,
+			check.TargetDate, targetTime.Format("15:04"), check.TargetTimezone, check.Relationship)
+
+		coords := zoneCoordinates()
+		from, fromOK := coords[check.SourceTimezone]
+		to, toOK := coords[check.TargetTimezone]
+		if fromOK && toOK {
+			crossed := crossesDateLine(from, to, sourceOffset, targetOffset)
+			check.DateLineCrossed = &crossed
+			if crossed {
+				check.Explanation += " The International Date Line lies between them: crossing it eastward turns the calendar back a day, and westward forward a day."
+			}
+		}
+		return mcp.NewToolResultStructured(check, check.Explanation), nil
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestCrossesDateLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to coordinates
+		offsets  [2]int
+		want     bool
+	}{
+		{"Tokyo to Los Angeles", coordinates{35.65, 139.74}, coordinates{34.05, -118.24}, [2]int{9, -8}, true},
+		{"London to Tokyo", coordinates{51.51, -0.13}, coordinates{35.65, 139.74}, [2]int{0, 9}, false},
+		{"Honolulu to Kiritimati", coordinates{21.31, -157.86}, coordinates{1.87, -157.4}, [2]int{-10, 14}, true},
+		{"Apia to Pago Pago", coordinates{-13.83, -171.77}, coordinates{-14.27, -170.7}, [2]int{13, -11}, true},
+		{"New York to Los Angeles", coordinates{40.71, -74.01}, coordinates{34.05, -118.24}, [2]int{-5, -8}, false},
+		{"Auckland to Sydney", coordinates{-36.87, 174.77}, coordinates{-33.87, 151.21}, [2]int{13, 11}, false},
+	}
+	for _, tt := range tests {
+		if got := crossesDateLine(tt.from, tt.to, tt.offsets[0]*3600, tt.offsets[1]*3600); got != tt.want {
+			t.Errorf("Expected %s to cross the date line: %v but got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestDateLineCheck(t *testing.T) {
+	clock := newFakeClock(time.Date(2025, 12, 22, 0, 0, 0, 0, time.UTC))
+	handler := handleDateLineCheck(&Config{DefaultTimezone: "UTC", Clock: clock})
+	call := func(args map[string]any) dateLineCheck {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := handler(context.Background(), request)
+		if err != nil || result.IsError {
+			t.Fatalf("Failed to check %v: %v %s", args, err, toolResultText(result))
+		}
+		return result.StructuredContent.(dateLineCheck)
+	}
+
+	check := call(map[string]any{"source_timezone": "Pacific/Auckland", "time": "09:00", "date": "2025-12-22", "target_timezone": "Pacific/Honolulu"})
+	if check.DayDifference != -1 || check.Relationship != "previous day" || check.TargetDate != "Sunday, 2025-12-21" || check.OffsetChange != "-23h" {
+		t.Errorf("Expected Honolulu on the previous day but got %+v", check)
+	}
+	if !strings.Contains(check.Explanation, "Monday, 2025-12-22 09:00 in Pacific/Auckland is Sunday, 2025-12-21 10:00 in Pacific/Honolulu") {
+		t.Errorf("Expected the explanation to give both local dates but got %s", check.Explanation)
+	}
+
+	// Kiritimati is 26 hours ahead of Baker Island's UTC-12
+	check = call(map[string]any{"source_timezone": "Etc/GMT+12", "time": "23:00", "target_timezone": "Pacific/Kiritimati"})
+	if check.DayDifference != 2 || check.Relationship != "two days ahead" {
+		t.Errorf("Expected Kiritimati two days ahead but got %+v", check)
+	}
+
+	check = call(map[string]any{"source_timezone": "Europe/Warsaw", "time": "12:00", "target_timezone": "Europe/London"})
+	if check.DayDifference != 0 || check.Relationship != "same day" {
+		t.Errorf("Expected the same day but got %+v", check)
+	}
+
+	// The date line flag needs the zone tables for the zones' locations
+	if _, ok := zoneCoordinates()["Pacific/Auckland"]; ok {
+		check = call(map[string]any{"source_timezone": "Pacific/Auckland", "target_timezone": "Pacific/Honolulu"})
+		if check.DateLineCrossed == nil || !*check.DateLineCrossed {
+			t.Errorf("Expected the date line between Auckland and Honolulu but got %+v", check)
+		}
+		check = call(map[string]any{"source_timezone": "Europe/Warsaw", "target_timezone": "Asia/Tokyo"})
+		if check.DateLineCrossed == nil || *check.DateLineCrossed {
+			t.Errorf("Expected no date line between Warsaw and Tokyo but got %+v", check)
+		}
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]any{"target_timezone": "UTC", "date": "22/12/2025"}
+	result, _ := handler(context.Background(), request)
+	if code := toolErrorCodeOf(result); code != errorParse {
+		t.Errorf("Expected %s for an invalid date but got %q", errorParse, code)
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// maxDateAddYears bounds the total shift of date_add in either direction
+const maxDateAddYears = 10000
+
+// Month overflow policies of date_add for a day past the end of the target
+// month, as in January 31 plus one month
+const (
+	// monthOverflowClamp moves the day back to the last day of the month
+	monthOverflowClamp = "clamp"
+	// monthOverflowRoll carries the extra days into the next month, as
+	// time.AddDate does
+	monthOverflowRoll = "roll"
+)
+
+// dateAddResult is the structured result of date_add
+type dateAddResult struct {
+	Start         string `json:"start"`
+	Result        string `json:"result"`
+	ResultWeekday string `json:"result_weekday"`
+	Years         int    `json:"years"`
+	Months        int    `json:"months"`
+	Days          int    `json:"days" jsonschema:"Days added, including weeks as 7 days"`
+	MonthOverflow string `json:"month_overflow" jsonschema:"The policy applied: clamp or roll"`
+	Overflowed    bool   `json:"overflowed" jsonschema:"Whether the day did not exist in the target month, so the policy changed the result"`
+	Note          string `json:"note,omitempty"`
+}
+
+// daysInMonth returns the number of days of a month
+func daysInMonth(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// addMonths adds years and months and then days to the calendar date of t,
+// applying policy when the day does not exist in the target month. It also
+// returns that missing date, e.g. 2025-02-31, or "" if the day exists.
+func addMonths(t time.Time, years, months, days int, policy string) (time.Time, string) {
+	total := int(t.Month()) - 1 + months + 12*years
+	year := t.Year() + total/12
+	month := total % 12
+	if month < 0 {
+		month += 12
+		year--
+	}
+	target := time.Month(month + 1)
+
+	day := t.Day()
+	var missing string
+	if last := daysInMonth(year, target); day > last {
+		missing = fmt.Sprintf("%04d-%02d-%02d", year, target, day)
+		if policy == monthOverflowClamp {
+			day = last
+		}
+	}
+	return time.Date(year, target, day+days, 0, 0, 0, 0, time.UTC), missing
+}
+
+func addDateAddTool(mcpServer *server.MCPServer, config *Config) {
+	mcpServer.AddTool(
+		mcp.NewTool("date_add",
+			mcp.WithDescription("Add or subtract years, months, weeks and days to a date or local date-time, with explicit month-end semantics: month_overflow=clamp gives 2025-01-31 + 1 month = 2025-02-28, roll gives 2025-03-03. Years and months are applied first, then weeks and days. The policy applied is echoed in the result."),
+			mcp.WithString("date",
+				mcp.Description("Start as YYYY-MM-DD, a local date-time YYYY-MM-DDTHH:MM[:SS] in timezone, or RFC 3339. Defaults to now in timezone."),
+			),
+			mcp.WithNumber("years",
+				mcp.Description("Years to add; negative to subtract."),
+			),
+			mcp.WithNumber("months",
+				mcp.Description("Months to add; negative to subtract."),
+			),
+			mcp.WithNumber("weeks",
+				mcp.Description("Weeks to add; negative to subtract."),
+			),
+			mcp.WithNumber("days",
+				mcp.Description("Days to add; negative to subtract."),
+			),
+			mcp.WithString("month_overflow",
+				mcp.Description("When the day does not exist in the target month: clamp to the month's last day, or roll the extra days into the next month. Defaults to clamp."),
+				mcp.Enum(monthOverflowClamp, monthOverflowRoll),
+			),
+			mcp.WithString("timezone",
+				mcp.Description("Timezone of a local date-time and of now. Defaults to the session or server default."),
+			),
+			mcp.WithOutputSchema[dateAddResult](),
+			mcp.WithTitleAnnotation("Date Add"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		handleDateAdd(config),
+	)
+}
+
+// handleDateAdd returns a handler for the date_add tool
+func handleDateAdd(config *Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		timezoneStr := request.GetString("timezone", "")
+		loc, err := loadTimezone(ctx, timezoneStr, config)
+		if err != nil {
+			return invalidTimezoneError("Invalid timezone", timezoneStr), nil
+		}
+		policy := request.GetString("month_overflow", monthOverflowClamp)
+		if policy != monthOverflowClamp && policy != monthOverflowRoll {
+			return toolError(errorInvalidArgument, fmt.Sprintf("Invalid month_overflow: %s. Please use clamp or roll.", policy)), nil
+		}
+		years := request.GetInt("years", 0)
+		months := request.GetInt("months", 0)
+		weeks := request.GetInt("weeks", 0)
+		days := request.GetInt("days", 0)
+		for _, n := range []int{years, months / 12, weeks / 52, days / 366} {
+			if n > maxDateAddYears || n < -maxDateAddYears {
+				return toolError(errorInvalidArgument, fmt.Sprintf("Shift out of range; each part must be within %d years", maxDateAddYears)), nil
+			}
+		}
+		days += 7 * weeks
+
+		// A bare date stays a date; anything else keeps its wall clock
+		start := config.now().In(loc)
+		layout := time.RFC3339
+		dateOnly := false
+		if dateStr := request.GetString("date", ""); dateStr != "" {
+			parsed := false
+			for _, l := range []string{"2006-01-02", "2006-01-02T15:04", "2006-01-02T15:04:05", time.RFC3339} {
+				if t, err := time.ParseInLocation(l, dateStr, loc); err == nil {
+					start, parsed, dateOnly = t, true, l == "2006-01-02"
+					break
+				}
+			}
+			if !parsed {
+				return toolError(errorParse, fmt.Sprintf("Invalid date: %s. Please provide YYYY-MM-DD, YYYY-MM-DDTHH:MM[:SS] or RFC 3339.", dateStr)), nil
+			}
+		}
+		if dateOnly {
+			layout = "2006-01-02"
+		}
+
+		date, missing := addMonths(start, years, months, days, policy)
+		result := date
+		var notes []string
+		if !dateOnly {
+			clock := time.Duration(start.Hour())*time.Hour + time.Duration(start.Minute())*time.Minute +
+				time.Duration(start.Second())*time.Second + time.Duration(start.Nanosecond())
+			result = wallClockOn(date, clock, start.Location())
+		}
+		out := dateAddResult{
+			Start:         start.Format(layout),
+			Result:        result.Format(layout),
+			ResultWeekday: result.Weekday().String(),
+			Years:         years,
+			Months:        months,
+			Days:          days,
+			MonthOverflow: policy,
+			Overflowed:    missing != "",
+		}
+		if missing != "" {
+			action := "clamped to the end of the month"
+			if policy == monthOverflowRoll {
+				action = "rolled into the next month"
+			}
+			notes = append(notes, fmt.Sprintf("%s does not exist; %s", missing, action))
+		}
+		if !dateOnly {
+			if note := clockShiftNote(result, start.Format("15:04")); note != "" {
+				notes = append(notes, note)
+			}
+		}
+		out.Note = strings.Join(notes, "; ")
+
+		data, err := json.Marshal(out)
+		if err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to encode result: %v", err)), nil
+		}
+		return mcp.NewToolResultStructured(out, string(data)), nil
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestAddMonths(t *testing.T) {
+	tests := []struct {
+		start                string
+		years, months, days  int
+		policy, want, missed string
+	}{
+		{"2025-01-31", 0, 1, 0, monthOverflowClamp, "2025-02-28", "2025-02-31"},
+		{"2025-01-31", 0, 1, 0, monthOverflowRoll, "2025-03-03", "2025-02-31"},
+		{"2024-01-31", 0, 1, 0, monthOverflowClamp, "2024-02-29", "2024-02-31"},
+		{"2024-02-29", 1, 0, 0, monthOverflowClamp, "2025-02-28", "2025-02-29"},
+		{"2024-02-29", 1, 0, 0, monthOverflowRoll, "2025-03-01", "2025-02-29"},
+		{"2024-02-29", 4, 0, 0, monthOverflowClamp, "2028-02-29", ""},
+		{"2025-03-31", 0, -1, 0, monthOverflowClamp, "2025-02-28", "2025-02-31"},
+		{"2025-01-15", 0, -13, 0, monthOverflowClamp, "2023-12-15", ""},
+		// Days are added after clamping
+		{"2025-01-31", 0, 1, 1, monthOverflowClamp, "2025-03-01", "2025-02-31"},
+	}
+	for _, tt := range tests {
+		start, _ := time.Parse("2006-01-02", tt.start)
+		got, missed := addMonths(start, tt.years, tt.months, tt.days, tt.policy)
+		if got.Format("2006-01-02") != tt.want || missed != tt.missed {
+			t.Errorf("%s %+dy%+dm%+dd (%s): expected %s (missing %q) but got %s (missing %q)",
+				tt.start, tt.years, tt.months, tt.days, tt.policy, tt.want, tt.missed, got.Format("2006-01-02"), missed)
+		}
+	}
+}
+
+func TestDateAdd(t *testing.T) {
+	clock := newFakeClock(time.Date(2025, 1, 31, 10, 0, 0, 0, time.UTC))
+	handler := handleDateAdd(&Config{DefaultTimezone: "UTC", Clock: clock})
+	call := func(args map[string]any) *mcp.CallToolResult {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := handler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("Failed to call handler: %v", err)
+		}
+		return result
+	}
+
+	result := call(map[string]any{"date": "2025-01-31", "months": 1})
+	if result.IsError {
+		t.Fatalf("Failed to add: %s", toolResultText(result))
+	}
+	got := result.StructuredContent.(dateAddResult)
+	if got.Result != "2025-02-28" || got.MonthOverflow != "clamp" || !got.Overflowed || got.Note == "" {
+		t.Errorf("Expected 2025-02-28 clamped but got %+v", got)
+	}
+
+	got = call(map[string]any{"months": 1, "month_overflow": "roll"}).StructuredContent.(dateAddResult)
+	if got.Start != "2025-01-31T10:00:00Z" || got.Result != "2025-03-03T10:00:00Z" || got.MonthOverflow != "roll" {
+		t.Errorf("Expected now + 1 month to roll to 2025-03-03 but got %+v", got)
+	}
+
+	// Local date-times keep their wall clock across DST changes
+	got = call(map[string]any{"date": "2025-03-29T09:30", "weeks": 1, "timezone": "Europe/Warsaw"}).StructuredContent.(dateAddResult)
+	if got.Result != "2025-04-05T09:30:00+02:00" || got.Days != 7 || got.Overflowed {
+		t.Errorf("Expected 09:30 a week later in summer time but got %+v", got)
+	}
+	got = call(map[string]any{"date": "2025-03-29T02:30", "days": 1, "timezone": "Europe/Warsaw"}).StructuredContent.(dateAddResult)
+	if got.Result != "2025-03-30T03:30:00+02:00" || got.Note == "" {
+		t.Errorf("Expected a skipped 02:30 moved to 03:30 but got %+v", got)
+	}
+
+	for _, args := range []map[string]any{
+		{"date": "31.01.2025", "months": 1},
+		{"months": 1, "month_overflow": "saturate"},
+		{"years": 20000},
+		{"weeks": 1e15},
+	} {
+		if result := call(args); !result.IsError {
+			t.Errorf("Expected an error for %v but got %s", args, toolResultText(result))
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// dayPreviewClocks are the wall clock reference points of day_schedule_preview
+var dayPreviewClocks = []struct {
+	Label string
+	Clock time.Duration
+}{
+	{"midnight", 0},
+	{"start of working day", 9 * time.Hour},
+	{"noon", 12 * time.Hour},
+	{"end of working day", 17 * time.Hour},
+}
+
+// daySchedulePreview is the structured result of day_schedule_preview
+type daySchedulePreview struct {
+	Date      string          `json:"date"`
+	Weekday   string          `json:"weekday"`
+	Timezone  string          `json:"timezone"`
+	Latitude  *float64        `json:"latitude,omitempty"`
+	Longitude *float64        `json:"longitude,omitempty"`
+	DayLength string          `json:"day_length,omitempty" jsonschema:"Time from sunrise to sunset"`
+	Hours     float64         `json:"hours" jsonschema:"Length of the local day in hours: 23 or 25 on DST change days"`
+	Points    []dayPreviewRow `json:"points" jsonschema:"Reference points in chronological order"`
+	Note      string          `json:"note,omitempty"`
+}
+
+type dayPreviewRow struct {
+	Label     string `json:"label"`
+	Local     string `json:"local" jsonschema:"Local time in RFC 3339"`
+	Clock     string `json:"clock" jsonschema:"Local wall clock, HH:MM"`
+	UTCOffset string `json:"utc_offset"`
+	UTC       string `json:"utc" jsonschema:"The same instant in UTC, RFC 3339"`
+	Note      string `json:"note,omitempty"`
+}
+
+func newDayPreviewRow(label string, t time.Time, loc *time.Location) dayPreviewRow {
+	t = t.In(loc)
+	return dayPreviewRow{
+		Label:     label,
+		Local:     t.Format(time.RFC3339),
+		Clock:     t.Format("15:04"),
+		UTCOffset: t.Format("-07:00"),
+		UTC:       t.UTC().Format(time.RFC3339),
+	}
+}
+
+// wallClockOn returns the instant of a wall clock on a calendar date in loc.
+// time.Date leaves unspecified which instant a skipped or repeated clock
+// resolves to, so this picks the first occurrence of a repeated clock and
+// moves a skipped one forward by the gap, as 02:30 becomes 03:30 when clocks
+// jump from 02:00 to 03:00
+func wallClockOn(date time.Time, clock time.Duration, loc *time.Location) time.Time {
+	wall := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC).Add(clock)
+	// The offsets a day either side cover any single DST change
+	_, before := wall.Add(-24 * time.Hour).In(loc).Zone()
+	_, after := wall.Add(24 * time.Hour).In(loc).Zone()
+	first := wall.Add(-time.Duration(max(before, after)) * time.Second).In(loc)
+	if first.Format("15:04") == wall.Format("15:04") {
+		return first
+	}
+	return wall.Add(-time.Duration(before) * time.Second).In(loc)
+}
+
+// clockShiftNote explains when a DST change moves or repeats the wall clock
+// want that at was built from
+func clockShiftNote(at time.Time, want string) string {
+	if at.Format("15:04") != want {
+		return fmt.Sprintf("%s is skipped by a DST change; shown at %s", want, at.Format("15:04"))
+	}
+	if later := at.Add(time.Hour); later.Format("2006-01-02 15:04") == at.Format("2006-01-02 15:04") {
+		return fmt.Sprintf("%s happens twice due to a DST change; shown at the first", want)
+	}
+	return ""
+}
+
+func addDaySchedulePreviewTool(mcpServer *server.MCPServer, config *Config) {
+	mcpServer.AddTool(
+		mcp.NewTool("day_schedule_preview",
+			mcp.WithDescription("Preview a day in another city: lists local midnight, sunrise, 09:00, noon, 17:00, sunset and the following midnight for a date and timezone, each with its UTC equivalent. Flags DST changes that shift or skip a reference point."),
+			mcp.WithString("date",
+				mcp.Description("Date in YYYY-MM-DD format."),
+				mcp.Required(),
+			),
+			mcp.WithString("timezone",
+				mcp.Description("IANA timezone of the city. Defaults to the session or server default."),
+			),
+			mcp.WithNumber("latitude",
+				mcp.Description("Latitude in degrees, north positive, for sunrise and sunset. Defaults to the timezone's principal city."),
+			),
+			mcp.WithNumber("longitude",
+				mcp.Description("Longitude in degrees, east positive, for sunrise and sunset. Defaults to the timezone's principal city."),
+			),
+			mcp.WithOutputSchema[daySchedulePreview](),
+			mcp.WithTitleAnnotation("Day Schedule Preview"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		handleDaySchedulePreview(config),
+	)
+}
+
+// handleDaySchedulePreview returns a handler for the day_schedule_preview tool
+func handleDaySchedulePreview(config *Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		dateStr, err := request.RequireString("date")
+		if err != nil {
+			return toolError(errorInvalidArgument, err.Error()), nil
+		}
+		timezoneStr := request.GetString("timezone", "")
+		loc, err := loadTimezone(ctx, timezoneStr, config)
+		if err != nil {
+			return invalidTimezoneError("Invalid timezone", timezoneStr), nil
+		}
+		// Dates are calendar days, so they are handled in UTC
+		date, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			return toolError(errorParse, fmt.Sprintf("Invalid date format: %s. Please provide date in YYYY-MM-DD format.", dateStr)), nil
+		}
+
+		args := request.GetArguments()
+		_, hasLat := args["latitude"]
+		_, hasLon := args["longitude"]
+		var place coordinates
+		var known bool
+		switch {
+		case hasLat != hasLon:
+			return toolError(errorInvalidArgument, "Provide both 'latitude' and 'longitude', or neither"), nil
+		case hasLat:
+			place = coordinates{request.GetFloat("latitude", 0), request.GetFloat("longitude", 0)}
+			if math.Abs(place.Latitude) > 90 || math.Abs(place.Longitude) > 180 {
+				return toolError(errorInvalidArgument, "Latitude must be within ±90 and longitude within ±180 degrees"), nil
+			}
+			known = true
+		default:
+			place, known = zoneCoordinates()[loc.String()]
+		}
+
+		start := wallClockOn(date, 0, loc)
+		end := wallClockOn(date.AddDate(0, 0, 1), 0, loc)
+		preview := daySchedulePreview{
+			Date:     date.Format("2006-01-02"),
+			Weekday:  date.Weekday().String(),
+			Timezone: loc.String(),
+			Hours:    end.Sub(start).Hours(),
+		}
+
+		var points []dayPreviewRow
+		add := func(label string, at time.Time, note string) {
+			row := newDayPreviewRow(label, at, loc)
+			row.Note = note
+			points = append(points, row)
+		}
+		for _, ref := range dayPreviewClocks {
+			at := wallClockOn(date, ref.Clock, loc)
+			add(ref.Label, at, clockShiftNote(at, formatClock(ref.Clock)))
+		}
+		add("next midnight", end, clockShiftNote(end, "00:00"))
+
+		if !known {
+			preview.Note = fmt.Sprintf("No coordinates are known for %s; provide 'latitude' and 'longitude' for sunrise and sunset", loc)
+		} else {
+			preview.Latitude, preview.Longitude = &place.Latitude, &place.Longitude
+			sunrise, rises := solarEventTime(date, place.Latitude, place.Longitude, "sunrise")
+			sunset, sets := solarEventTime(date, place.Latitude, place.Longitude, "sunset")
+			if rises && sets {
+				add("sunrise", sunrise, "")
+				add("sunset", sunset, "")
+				preview.DayLength = shortDuration(sunset.Sub(sunrise).Round(time.Minute))
+			} else {
+				preview.Note = "The sun does not rise or set on this date (polar day or night)"
+			}
+		}
+		// UTC times in RFC 3339 sort chronologically as text
+		slices.SortStableFunc(points, func(a, b dayPreviewRow) int { return strings.Compare(a.UTC, b.UTC) })
+		preview.Points = points
+
+		data, err := json.Marshal(preview)
+		if err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to encode result: %v", err)), nil
+		}
+		return mcp.NewToolResultStructured(preview, string(data)), nil
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestDaySchedulePreview(t *testing.T) {
+	handler := handleDaySchedulePreview(&Config{DefaultTimezone: "UTC"})
+	call := func(args map[string]any) *mcp.CallToolResult {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := handler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("Failed to call handler: %v", err)
+		}
+		return result
+	}
+
+	// Warsaw on the day clocks go forward: a 23-hour day
+	result := call(map[string]any{"date": "2025-03-30", "timezone": "Europe/Warsaw", "latitude": 52.25, "longitude": 21.0})
+	if result.IsError {
+		t.Fatalf("Failed to preview day: %s", toolResultText(result))
+	}
+	got := result.StructuredContent.(daySchedulePreview)
+	if got.Hours != 23 || got.Weekday != "Sunday" || got.DayLength == "" {
+		t.Errorf("Expected a 23-hour Sunday with a day length but got %+v", got)
+	}
+	labels := []string{"midnight", "sunrise", "start of working day", "noon", "end of working day", "sunset", "next midnight"}
+	if len(got.Points) != len(labels) {
+		t.Fatalf("Expected %d points but got %+v", len(labels), got.Points)
+	}
+	for i, label := range labels {
+		if got.Points[i].Label != label {
+			t.Errorf("Expected point %d to be %s but got %s", i, label, got.Points[i].Label)
+		}
+	}
+	if p := got.Points[0]; p.UTC != "2025-03-29T23:00:00Z" || p.UTCOffset != "+01:00" {
+		t.Errorf("Expected midnight at 23:00 UTC but got %+v", p)
+	}
+	if p := got.Points[2]; p.Local != "2025-03-30T09:00:00+02:00" || p.UTC != "2025-03-30T07:00:00Z" || p.Note != "" {
+		t.Errorf("Expected 09:00 at 07:00 UTC but got %+v", p)
+	}
+
+	// Havana skips midnight when DST starts
+	got = call(map[string]any{"date": "2025-03-09", "timezone": "America/Havana", "latitude": 23.13, "longitude": -82.38}).StructuredContent.(daySchedulePreview)
+	if p := got.Points[0]; p.Clock != "01:00" || p.Note == "" {
+		t.Errorf("Expected a skipped midnight shown at 01:00 but got %+v", p)
+	}
+
+	// Polar night in Longyearbyen
+	got = call(map[string]any{"date": "2025-12-21", "timezone": "Arctic/Longyearbyen", "latitude": 78.22, "longitude": 15.65}).StructuredContent.(daySchedulePreview)
+	if len(got.Points) != 5 || got.DayLength != "" || got.Note == "" {
+		t.Errorf("Expected no sunrise or sunset in polar night but got %+v", got)
+	}
+
+	for _, args := range []map[string]any{
+		{},
+		{"date": "30.03.2025"},
+		{"date": "2025-03-30", "timezone": "Mars/Olympus"},
+		{"date": "2025-03-30", "latitude": 52.25},
+	} {
+		if result := call(args); !result.IsError {
+			t.Errorf("Expected an error for %v but got %s", args, toolResultText(result))
+		}
+	}
+}
+
+func TestWallClockOn(t *testing.T) {
+	date := time.Date(2025, 11, 2, 0, 0, 0, 0, time.UTC)
+	havana, err := time.LoadLocation("America/Havana")
+	if err != nil {
+		t.Fatalf("Failed to load timezone: %v", err)
+	}
+	// Clocks go back from 01:00 CST to 00:00 CDT, so 00:30 happens twice
+	at := wallClockOn(date, 30*time.Minute, havana)
+	if got := at.Format(time.RFC3339); got != "2025-11-02T00:30:00-04:00" || clockShiftNote(at, "00:30") == "" {
+		t.Errorf("Expected the first 00:30 with a note but got %s", got)
+	}
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Fatalf("Failed to load timezone: %v", err)
+	}
+	at = wallClockOn(time.Date(2025, 10, 26, 0, 0, 0, 0, time.UTC), 90*time.Minute, london)
+	if got := at.Format(time.RFC3339); got != "2025-10-26T01:30:00+01:00" {
+		t.Errorf("Expected the first 01:30 in London but got %s", got)
+	}
+	at = wallClockOn(time.Date(2025, 3, 30, 0, 0, 0, 0, time.UTC), 90*time.Minute, london)
+	if got := at.Format(time.RFC3339); got != "2025-03-30T02:30:00+01:00" || clockShiftNote(at, "01:30") == "" {
+		t.Errorf("Expected a skipped 01:30 moved to 02:30 but got %s", got)
+	}
+}
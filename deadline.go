@@ -0,0 +1,277 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// deadlineKeyPrefix namespaces tracked deadlines in the state store
+	deadlineKeyPrefix = "deadline:"
+	// deadlineURIPrefix is the scheme of deadline resources
+	deadlineURIPrefix = "deadline://"
+	// deadlineRetention is how long a deadline stays readable after it passes
+	deadlineRetention = 7 * 24 * time.Hour
+	// maxDeadlinesPerUser caps the deadlines one user tracks
+	maxDeadlinesPerUser = 100
+)
+
+// trackedDeadline is a deadline registered by track_deadline
+type trackedDeadline struct {
+	ID        string    `json:"id"`
+	Owner     string    `json:"owner,omitempty"`
+	Label     string    `json:"label"`
+	Deadline  time.Time `json:"deadline"`
+	Timezone  string    `json:"timezone"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// deadlineCountdown is the content of a deadline resource and the structured
+// result of track_deadline
+type deadlineCountdown struct {
+	ID               string  `json:"id"`
+	URI              string  `json:"uri" jsonschema:"Resource URI to read the countdown from"`
+	Label            string  `json:"label"`
+	Deadline         string  `json:"deadline" jsonschema:"Deadline in RFC 3339 in its timezone"`
+	Timezone         string  `json:"timezone"`
+	Now              string  `json:"now" jsonschema:"When the countdown was computed, in RFC 3339 in the deadline's timezone"`
+	Remaining        string  `json:"remaining" jsonschema:"Time left, e.g. 2d 3h 15m, or how long ago the deadline passed"`
+	RemainingSeconds float64 `json:"remaining_seconds" jsonschema:"Seconds left; negative once the deadline has passed"`
+	Passed           bool    `json:"passed"`
+}
+
+// formatCountdown formats a duration in days, hours and minutes, or seconds
+// under a minute, e.g. 2d 3h 15m
+func formatCountdown(d time.Duration) string {
+	d = d.Abs()
+	if d < time.Minute {
+		return fmt.Sprintf("%ds", int(d/time.Second))
+	}
+	minutes := int(d / time.Minute)
+	days, hours := minutes/(24*60), minutes/60%24
+	var parts []string
+	if days > 0 {
+		parts = append(parts, fmt.Sprintf("%dd", days))
+	}
+	if hours > 0 {
+		parts = append(parts, fmt.Sprintf("%dh", hours))
+	}
+	if minutes%60 > 0 {
+		parts = append(parts, fmt.Sprintf("%dm", minutes%60))
+	}
+	return strings.Join(parts, " ")
+}
+
+// countdown computes the time left until the deadline at now
+func (d *trackedDeadline) countdown(now time.Time) deadlineCountdown {
+	loc, err := loadLocationCached(d.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	remaining := d.Deadline.Sub(now)
+	return deadlineCountdown{
+		ID:               d.ID,
+		URI:              deadlineURIPrefix + d.ID,
+		Label:            d.Label,
+		Deadline:         d.Deadline.In(loc).Format(time.RFC3339),
+		Timezone:         loc.String(),
+		Now:              now.In(loc).Format(time.RFC3339),
+		Remaining:        formatCountdown(remaining),
+		RemainingSeconds: remaining.Truncate(time.Second).Seconds(),
+		Passed:           remaining <= 0,
+	}
+}
+
+// deadlineTracker keeps deadlines in the state store, keyed by owner and
+// ID so a user's deadlines can be counted with one key listing
+type deadlineTracker struct {
+	store stateStore
+	now   func() time.Time
+}
+
+func newDeadlineTracker(store stateStore, config *Config) *deadlineTracker {
+	return &deadlineTracker{store: store, now: config.now}
+}
+
+func deadlineKey(owner, id string) string {
+	return deadlineKeyPrefix + owner + ":" + id
+}
+
+// load returns the owner's deadline, or nil if there is none
+func (t *deadlineTracker) load(ctx context.Context, owner, id string) (*trackedDeadline, error) {
+	// IDs are hex; a colon could reach into another owner's keys
+	if id == "" || strings.Contains(id, ":") {
+		return nil, nil
+	}
+	data, ok, err := t.store.get(ctx, deadlineKey(owner, id))
+	if err != nil || !ok {
+		return nil, err
+	}
+	var d trackedDeadline
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, fmt.Errorf("corrupt deadline %q: %w", id, err)
+	}
+	return &d, nil
+}
+
+// save stores a deadline until deadlineRetention after it passes
+func (t *deadlineTracker) save(ctx context.Context, d *trackedDeadline) error {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	ttl := d.Deadline.Sub(t.now()) + deadlineRetention
+	return t.store.set(ctx, deadlineKey(d.Owner, d.ID), data, ttl)
+}
+
+// addDeadlineTools registers track_deadline, untrack_deadline and the
+// deadline:// resource template
+func addDeadlineTools(mcpServer *server.MCPServer, tracker *deadlineTracker, config *Config) {
+	mcpServer.AddTool(
+		mcp.NewTool("track_deadline",
+			mcp.WithDescription("Track a deadline as a countdown resource. Returns its deadline:// URI; reading the resource shows the time remaining, computed at each read. Deadlines are kept until a week after they pass."),
+			mcp.WithString("label",
+				mcp.Description("What the deadline is for, e.g. 'Q3 report'."),
+				mcp.Required(),
+			),
+			mcp.WithString("deadline",
+				mcp.Description("The deadline in RFC 3339 or 'YYYY-MM-DD HH:MM' in timezone."),
+				mcp.Required(),
+			),
+			mcp.WithString("timezone",
+				mcp.Description("Timezone of the deadline and of the countdown. Defaults to the session or server default."),
+			),
+			mcp.WithOutputSchema[deadlineCountdown](),
+			mcp.WithTitleAnnotation("Track Deadline"),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(false),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		handleTrackDeadline(tracker, config),
+	)
+	mcpServer.AddTool(
+		mcp.NewTool("untrack_deadline",
+			mcp.WithDescription("Stop tracking a deadline by ID."),
+			mcp.WithString("id",
+				mcp.Description("Deadline ID returned by track_deadline."),
+				mcp.Required(),
+			),
+			mcp.WithTitleAnnotation("Untrack Deadline"),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		handleUntrackDeadline(tracker),
+	)
+	mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate(deadlineURIPrefix+"{id}", "Deadline countdown",
+			mcp.WithTemplateDescription("Time remaining until a deadline tracked with track_deadline"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		handleReadDeadline(tracker),
+	)
+}
+
+// handleTrackDeadline returns a handler for the track_deadline tool
+func handleTrackDeadline(tracker *deadlineTracker, config *Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		label, err := request.RequireString("label")
+		if err != nil {
+			return toolError(errorInvalidArgument, err.Error()), nil
+		}
+		deadlineStr, err := request.RequireString("deadline")
+		if err != nil {
+			return toolError(errorInvalidArgument, err.Error()), nil
+		}
+		timezoneStr := request.GetString("timezone", "")
+		loc, err := loadTimezone(ctx, timezoneStr, config)
+		if err != nil {
+			return invalidTimezoneError("Invalid timezone", timezoneStr), nil
+		}
+		at, err := time.Parse(time.RFC3339, deadlineStr)
+		if err != nil {
+			if at, err = time.ParseInLocation("2006-01-02 15:04", deadlineStr, loc); err != nil {
+				return toolError(errorParse, fmt.Sprintf("Invalid deadline: %s. Please provide RFC 3339 or 'YYYY-MM-DD HH:MM'.", deadlineStr)), nil
+			}
+		}
+
+		owner, _, _ := getUserInfo(ctx)
+		existing, err := tracker.store.keys(ctx, deadlineKey(owner, ""))
+		if err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to read deadlines: %v", err)), nil
+		}
+		if len(existing) >= maxDeadlinesPerUser {
+			return toolError(errorLimitExceeded, fmt.Sprintf("You have reached the limit of %d tracked deadlines; untrack one first", maxDeadlinesPerUser)), nil
+		}
+
+		now := tracker.now()
+		d := &trackedDeadline{ID: newJobID(), Owner: owner, Label: label, Deadline: at, Timezone: loc.String(), CreatedAt: now}
+		if err := tracker.save(ctx, d); err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to save deadline: %v", err)), nil
+		}
+		slog.InfoContext(ctx, "Deadline tracked", "id", d.ID, "deadline", at.Format(time.RFC3339))
+
+		countdown := d.countdown(now)
+		data, err := json.Marshal(countdown)
+		if err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to encode deadline: %v", err)), nil
+		}
+		return mcp.NewToolResultStructured(countdown, string(data)), nil
+	}
+}
+
+// handleUntrackDeadline returns a handler for the untrack_deadline tool
+func handleUntrackDeadline(tracker *deadlineTracker) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		id, err := request.RequireString("id")
+		if err != nil {
+			return toolError(errorInvalidArgument, err.Error()), nil
+		}
+		owner, _, _ := getUserInfo(ctx)
+		d, err := tracker.load(ctx, owner, id)
+		if err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to read deadline: %v", err)), nil
+		}
+		if d == nil {
+			return toolError(errorNotFound, fmt.Sprintf("No tracked deadline with ID %s", id)), nil
+		}
+		if err := tracker.store.delete(ctx, deadlineKey(owner, id)); err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to untrack deadline: %v", err)), nil
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("Deadline %s untracked", id)), nil
+	}
+}
+
+// handleReadDeadline returns a handler for deadline:// resources. Deadlines
+// are looked up under the reader's user ID, so users only see their own.
+func handleReadDeadline(tracker *deadlineTracker) server.ResourceTemplateHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		id := strings.TrimPrefix(request.Params.URI, deadlineURIPrefix)
+		owner, _, _ := getUserInfo(ctx)
+		d, err := tracker.load(ctx, owner, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read deadline: %w", err)
+		}
+		if d == nil {
+			return nil, fmt.Errorf("no tracked deadline with ID %s", id)
+		}
+		data, err := json.Marshal(d.countdown(tracker.now()))
+		if err != nil {
+			return nil, err
+		}
+		return []mcp.ResourceContents{mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "application/json",
+			Text:     string(data),
+		}}, nil
+	}
+}
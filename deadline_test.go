@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestFormatCountdown(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{51*time.Hour + 15*time.Minute + 30*time.Second, "2d 3h 15m"},
+		{24 * time.Hour, "1d"},
+		{90 * time.Minute, "1h 30m"},
+		{45 * time.Second, "45s"},
+		{-2 * time.Hour, "2h"},
+	}
+	for _, tt := range tests {
+		if got := formatCountdown(tt.d); got != tt.want {
+			t.Errorf("Expected %v to format as %q but got %q", tt.d, tt.want, got)
+		}
+	}
+}
+
+func TestDeadlineTracking(t *testing.T) {
+	clock := newFakeClock(time.Date(2025, 6, 27, 12, 0, 0, 0, time.UTC))
+	config := &Config{DefaultTimezone: "UTC", Clock: clock}
+	tracker := newDeadlineTracker(newMemoryStore(), config)
+	track, untrack, read := handleTrackDeadline(tracker, config), handleUntrackDeadline(tracker), handleReadDeadline(tracker)
+	alice := context.WithValue(context.Background(), userIDKey, "alice")
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]any{"label": "Q2 report", "deadline": "2025-06-30 17:00", "timezone": "Europe/Warsaw"}
+	result, err := track(alice, request)
+	if err != nil || result.IsError {
+		t.Fatalf("Failed to track deadline: %v %s", err, toolResultText(result))
+	}
+	created := result.StructuredContent.(deadlineCountdown)
+	if created.URI != "deadline://"+created.ID || created.Remaining != "3d 3h" || created.Passed {
+		t.Errorf("Expected a countdown of 3d 3h at deadline://%s but got %+v", created.ID, created)
+	}
+
+	readCountdown := func(ctx context.Context) (deadlineCountdown, error) {
+		request := mcp.ReadResourceRequest{}
+		request.Params.URI = created.URI
+		contents, err := read(ctx, request)
+		if err != nil {
+			return deadlineCountdown{}, err
+		}
+		var countdown deadlineCountdown
+		if err := json.Unmarshal([]byte(contents[0].(mcp.TextResourceContents).Text), &countdown); err != nil {
+			t.Fatalf("Failed to decode resource: %v", err)
+		}
+		return countdown, nil
+	}
+
+	// Each read recomputes the time left
+	clock.Advance(27*time.Hour + 30*time.Minute)
+	countdown, err := readCountdown(alice)
+	if err != nil {
+		t.Fatalf("Failed to read deadline: %v", err)
+	}
+	if countdown.Remaining != "1d 23h 30m" || countdown.Now != "2025-06-28T17:30:00+02:00" {
+		t.Errorf("Expected 1d 23h 30m left at 2025-06-28T17:30:00+02:00 but got %+v", countdown)
+	}
+	clock.Advance(72 * time.Hour)
+	if countdown, _ := readCountdown(alice); !countdown.Passed || countdown.RemainingSeconds >= 0 {
+		t.Errorf("Expected the deadline to have passed but got %+v", countdown)
+	}
+
+	// Other users cannot read or untrack it
+	bob := context.WithValue(context.Background(), userIDKey, "bob")
+	if _, err := readCountdown(bob); err == nil {
+		t.Errorf("Expected another user's deadline to be unreadable")
+	}
+	request = mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]any{"id": created.ID}
+	if result, _ := untrack(bob, request); toolErrorCodeOf(result) != errorNotFound {
+		t.Errorf("Expected %s untracking another user's deadline but got %s", errorNotFound, toolResultText(result))
+	}
+	if result, _ := untrack(alice, request); result.IsError {
+		t.Fatalf("Failed to untrack deadline: %s", toolResultText(result))
+	}
+	if _, err := readCountdown(alice); err == nil {
+		t.Errorf("Expected an untracked deadline to be gone")
+	}
+
+	request = mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]any{"label": "x", "deadline": "next week"}
+	if result, _ := track(alice, request); toolErrorCodeOf(result) != errorParse {
+		t.Errorf("Expected %s for an invalid deadline but got %s", errorParse, toolResultText(result))
+	}
+}
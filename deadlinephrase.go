@@ -0,0 +1,495 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// deadlineDefinitions are the wall clocks and week end that business phrases
+// stand for
+type deadlineDefinitions struct {
+	EndOfDay        time.Duration
+	CloseOfBusiness time.Duration
+	StartOfDay      time.Duration
+	WeekEnd         time.Weekday
+}
+
+// deadlinePhraseAliases rewrites multi-word phrases to one token before
+// tokenizing, longest first
+var deadlinePhraseAliases = []struct{ from, to string }{
+	{"close of business", "cob"},
+	{"end of business", "cob"},
+	{"end of the day", "eod"},
+	{"end of day", "eod"},
+	{"start of business", "sod"},
+	{"start of the day", "sod"},
+	{"start of day", "sod"},
+	{"first thing", "sod"},
+	{"end of the week", "eow"},
+	{"end of week", "eow"},
+	{"end of the month", "eom"},
+	{"end of month", "eom"},
+	{"end of the quarter", "eoq"},
+	{"end of quarter", "eoq"},
+	{"end of the year", "eoy"},
+	{"end of year", "eoy"},
+}
+
+// deadlineFillers are words that carry no meaning in a deadline phrase
+var deadlineFillers = map[string]bool{
+	"by": true, "before": true, "until": true, "till": true, "due": true,
+	"on": true, "the": true, "at": true, "of": true, "latest": true,
+}
+
+// deadlineMonths maps month names and abbreviations to months
+var deadlineMonths = map[string]time.Month{
+	"january": time.January, "jan": time.January, "february": time.February, "feb": time.February,
+	"march": time.March, "mar": time.March, "april": time.April, "apr": time.April, "may": time.May,
+	"june": time.June, "jun": time.June, "july": time.July, "jul": time.July,
+	"august": time.August, "aug": time.August, "september": time.September, "sep": time.September, "sept": time.September,
+	"october": time.October, "oct": time.October, "november": time.November, "nov": time.November,
+	"december": time.December, "dec": time.December,
+}
+
+// deadlineQuarterPattern matches q1 to q4
+var deadlineQuarterPattern = regexp.MustCompile(`^q([1-4])$`)
+
+// deadlinePhrase accumulates what a phrase says about a deadline
+type deadlinePhrase struct {
+	defs        deadlineDefinitions
+	today       time.Time
+	date        time.Time
+	hasDate     bool
+	clock       time.Duration
+	clockName   string
+	start       bool
+	assumptions []string
+}
+
+func (p *deadlinePhrase) assume(format string, args ...any) {
+	p.assumptions = append(p.assumptions, fmt.Sprintf(format, args...))
+}
+
+func (p *deadlinePhrase) setDate(date time.Time) error {
+	if p.hasDate {
+		return fmt.Errorf("more than one day given")
+	}
+	p.date, p.hasDate = date, true
+	return nil
+}
+
+func (p *deadlinePhrase) setClock(clock time.Duration, name string) error {
+	if p.clockName != "" {
+		return fmt.Errorf("more than one time of day given")
+	}
+	p.clock, p.clockName = clock, name
+	return nil
+}
+
+// period returns the first and last day of the week, month, quarter or year
+// offset periods from today; a week ends on the configured week end
+func (p *deadlinePhrase) period(kind string, offset int) (time.Time, time.Time) {
+	t := p.today
+	switch kind {
+	case "week":
+		start := startOfWeek(t, time.Monday).AddDate(0, 0, 7*offset)
+		return start, start.AddDate(0, 0, (int(p.defs.WeekEnd)+6)%7)
+	case "month":
+		start := time.Date(t.Year(), t.Month()+time.Month(offset), 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 1, -1)
+	case "quarter":
+		start := time.Date(t.Year(), (t.Month()-1)/3*3+1+time.Month(3*offset), 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 3, -1)
+	default:
+		start := time.Date(t.Year()+offset, time.January, 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(1, 0, -1)
+	}
+}
+
+// periodPicker picks the deadline day of a period from its first and last day
+type periodPicker func(from, to time.Time) time.Time
+
+// namedPeriod parses a month name or quarter at tokens[i], with an optional
+// year after it, and returns the day pick chooses in it and the tokens used.
+// A period without a year is the next one whose chosen day is not past.
+func (p *deadlinePhrase) namedPeriod(tokens []string, i int, pick periodPicker) (time.Time, int, bool) {
+	var first time.Month
+	months := 1
+	name := tokens[i]
+	if month, ok := deadlineMonths[name]; ok {
+		first, name = month, month.String()
+	} else if m := deadlineQuarterPattern.FindStringSubmatch(name); m != nil {
+		q, _ := strconv.Atoi(m[1])
+		first, months, name = time.Month(3*q-2), 3, strings.ToUpper(name)
+	} else {
+		return time.Time{}, 0, false
+	}
+	day := func(year int) time.Time {
+		from := time.Date(year, first, 1, 0, 0, 0, 0, time.UTC)
+		return pick(from, from.AddDate(0, months, -1))
+	}
+	if i+1 < len(tokens) && len(tokens[i+1]) == 4 {
+		if year, err := strconv.Atoi(tokens[i+1]); err == nil {
+			return day(year), 2, true
+		}
+	}
+	year := p.today.Year()
+	if d := day(year); !d.Before(p.today) {
+		return d, 1, true
+	}
+	p.assume("%s without a year means the next one, in %d", name, year+1)
+	return day(year + 1), 1, true
+}
+
+// boundary parses what follows "end of", "start of" or "mid": this or next
+// week, month, quarter or year, or a month name or quarter. It returns the
+// day and the tokens used.
+func (p *deadlinePhrase) boundary(tokens []string, i int, edge string) (time.Time, int, error) {
+	pick := func(from, to time.Time) time.Time { return to }
+	switch edge {
+	case "start":
+		pick = func(from, to time.Time) time.Time { return from }
+	case "mid":
+		// Mid-month is the 15th; longer periods split in half
+		pick = func(from, to time.Time) time.Time {
+			if to.Sub(from) < 31*24*time.Hour {
+				return from.AddDate(0, 0, 14)
+			}
+			return from.AddDate(0, 0, int(to.Sub(from).Hours()/24)/2)
+		}
+	}
+
+	offset, used := 0, 0
+	if i < len(tokens) && (tokens[i] == "this" || tokens[i] == "next") {
+		if tokens[i] == "next" {
+			offset = 1
+		}
+		used++
+	}
+	if i+used >= len(tokens) {
+		return time.Time{}, 0, fmt.Errorf("%q needs a period such as month or November", edge)
+	}
+	tok := tokens[i+used]
+	var day time.Time
+	switch tok {
+	case "week", "month", "quarter", "year":
+		day = pick(p.period(tok, offset))
+		used++
+		if tok == "week" && edge == "end" {
+			p.assume("the week ends on %s", p.defs.WeekEnd)
+		}
+	default:
+		d, n, ok := p.namedPeriod(tokens, i+used, pick)
+		if !ok || used > 0 {
+			return time.Time{}, 0, fmt.Errorf("unknown period %q", tok)
+		}
+		day = d
+		used += n
+	}
+	if edge == "mid" {
+		p.assume("mid %s is %s", tok, day.Format("January 2"))
+	}
+	return day, used, nil
+}
+
+// parse reads the tokens of a phrase into a day and a time of day
+func (p *deadlinePhrase) parse(tokens []string) error {
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		var err error
+		switch tok {
+		case "eod":
+			err = p.setClock(p.defs.EndOfDay, "end of day")
+		case "cob", "eob":
+			err = p.setClock(p.defs.CloseOfBusiness, "close of business")
+		case "sod":
+			err = p.setClock(p.defs.StartOfDay, "start of day")
+		case "eow", "eom", "eoq", "eoy":
+			kind := map[string]string{"eow": "week", "eom": "month", "eoq": "quarter", "eoy": "year"}[tok]
+			_, to := p.period(kind, 0)
+			if kind == "week" {
+				p.assume("the week ends on %s", p.defs.WeekEnd)
+			}
+			err = p.setDate(to)
+		case "today":
+			err = p.setDate(p.today)
+		case "tomorrow":
+			err = p.setDate(p.today.AddDate(0, 0, 1))
+		case "end", "start", "beginning", "mid":
+			edge := map[string]string{"beginning": "start"}[tok]
+			if edge == "" {
+				edge = tok
+			}
+			var day time.Time
+			var used int
+			if day, used, err = p.boundary(tokens, i+1, edge); err == nil {
+				i += used
+				p.start = edge == "start"
+				err = p.setDate(day)
+			}
+		case "in":
+			// "in November" or "in Q3" is by the end of it
+			if i+1 < len(tokens) && (deadlineMonths[tokens[i+1]] != 0 || deadlineQuarterPattern.MatchString(tokens[i+1])) {
+				var day time.Time
+				var used int
+				if day, used, err = p.boundary(tokens, i+1, "end"); err == nil {
+					i += used
+					err = p.setDate(day)
+				}
+				break
+			}
+			if i+2 >= len(tokens) {
+				return fmt.Errorf(`"in" needs a count and days or weeks`)
+			}
+			n, convErr := strconv.Atoi(tokens[i+1])
+			unit := strings.TrimSuffix(tokens[i+2], "s")
+			if convErr != nil || n < 0 || n > 3660 || unit != "day" && unit != "week" {
+				return fmt.Errorf("invalid relative deadline %q", strings.Join(tokens[i:i+3], " "))
+			}
+			if unit == "week" {
+				n *= 7
+			}
+			err = p.setDate(p.today.AddDate(0, 0, n))
+			i += 2
+		case "this", "next":
+			if i+1 >= len(tokens) {
+				return fmt.Errorf("%q needs a weekday or period", tok)
+			}
+			offset := map[string]int{"this": 0, "next": 1}[tok]
+			if day, ok := scheduleWeekday(tokens[i+1]); ok {
+				i++
+				if offset == 0 {
+					err = p.setDate(p.comingWeekday(day))
+					break
+				}
+				// "next Friday" is the Friday of next week, not the coming one
+				start, _ := p.period("week", 1)
+				p.assume("next %s means %s of next week", day, day)
+				err = p.setDate(start.AddDate(0, 0, (int(day)+6)%7))
+				break
+			}
+			var day time.Time
+			var used int
+			if day, used, err = p.boundary(tokens, i, "start"); err == nil {
+				// "by next week" is before the week starts
+				i += used - 1
+				p.start = true
+				p.assume("%s alone means its start", strings.Join(tokens[i-used+1:i+1], " "))
+				err = p.setDate(day)
+			}
+		case "midnight":
+			// "by midnight Friday" means the end of Friday
+			p.assume("midnight means the end of the day")
+			err = p.setClock(24*time.Hour, "midnight")
+		default:
+			if clock, ok := parseScheduleClock(tok); ok {
+				err = p.setClock(clock, formatClock(clock))
+			} else if day, ok := scheduleWeekday(tok); ok {
+				err = p.setDate(p.comingWeekday(day))
+			} else if date, dateErr := time.Parse("2006-01-02", tok); dateErr == nil {
+				err = p.setDate(date)
+			} else if from, used, ok := p.namedPeriod(tokens, i, func(from, _ time.Time) time.Time { return from }); ok {
+				i += used - 1
+				p.start = true
+				p.assume("%s alone means its start", tok)
+				err = p.setDate(from)
+			} else {
+				return fmt.Errorf("unrecognized word %q", tok)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// comingWeekday returns the next day on weekday, today included
+func (p *deadlinePhrase) comingWeekday(day time.Weekday) time.Time {
+	p.assume("%s means the coming %s, today included", day, day)
+	return p.today.AddDate(0, 0, (int(day)-int(p.today.Weekday())+7)%7)
+}
+
+// resolveDeadlinePhrase turns a business phrase such as "EOD Friday" or "end
+// of Q3" into an instant in loc, with the assumptions made. A phrase without
+// a day means today; one without a time of day means the end of the day, or
+// the start of the day for the start of a period.
+func resolveDeadlinePhrase(phrase string, now time.Time, loc *time.Location, defs deadlineDefinitions) (time.Time, []string, error) {
+	text := strings.ToLower(strings.TrimSpace(phrase))
+	text = scheduleMeridiemPattern.ReplaceAllString(text, "$1${2}m")
+	text = strings.NewReplacer(",", " ", "mid-", "mid ", "end-of-", "end of ").Replace(text)
+	text = strings.Join(strings.Fields(text), " ")
+	for _, alias := range deadlinePhraseAliases {
+		text = strings.ReplaceAll(text, alias.from, alias.to)
+	}
+	var tokens []string
+	for _, tok := range strings.Fields(text) {
+		if tok = strings.Trim(tok, ".!?"); tok != "" && !deadlineFillers[tok] {
+			tokens = append(tokens, tok)
+		}
+	}
+	if len(tokens) == 0 {
+		return time.Time{}, nil, fmt.Errorf("empty phrase")
+	}
+
+	now = now.In(loc)
+	p := &deadlinePhrase{defs: defs, today: time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)}
+	if err := p.parse(tokens); err != nil {
+		return time.Time{}, nil, err
+	}
+	if !p.hasDate {
+		p.date = p.today
+		p.assume("no day given; today")
+	}
+	if p.clockName == "" {
+		if p.start {
+			p.clock, p.clockName = defs.StartOfDay, "start of day"
+		} else {
+			p.clock, p.clockName = defs.EndOfDay, "end of day"
+		}
+		p.assume("no time given; %s at %s", p.clockName, formatClock(p.clock))
+	} else if p.clockName != "midnight" && p.clockName != formatClock(p.clock) {
+		p.assume("%s is %s", p.clockName, formatClock(p.clock))
+	}
+	if wd := p.date.Weekday(); wd == time.Saturday || wd == time.Sunday {
+		p.assume("the day is a %s; not moved to a business day", wd)
+	}
+	at := wallClockOn(p.date, p.clock, loc)
+	if p.clock < 24*time.Hour {
+		if note := clockShiftNote(at, formatClock(p.clock)); note != "" {
+			p.assume("%s", note)
+		}
+	}
+	return at, p.assumptions, nil
+}
+
+// resolvedDeadline is the structured result of resolve_deadline_phrase
+type resolvedDeadline struct {
+	Phrase           string   `json:"phrase"`
+	Timezone         string   `json:"timezone"`
+	Reference        string   `json:"reference" jsonschema:"The time the phrase was resolved relative to, in RFC 3339"`
+	Deadline         string   `json:"deadline" jsonschema:"The deadline in RFC 3339 in timezone"`
+	DeadlineUTC      string   `json:"deadline_utc"`
+	Weekday          string   `json:"weekday"`
+	Remaining        string   `json:"remaining" jsonschema:"Time left, e.g. 2d 3h 15m, or how long ago the deadline passed"`
+	RemainingSeconds float64  `json:"remaining_seconds" jsonschema:"Seconds left; negative once the deadline has passed"`
+	Passed           bool     `json:"passed"`
+	Assumptions      []string `json:"assumptions" jsonschema:"How ambiguous parts of the phrase were read"`
+	EndOfDay         string   `json:"end_of_day" jsonschema:"The EOD clock used"`
+	CloseOfBusiness  string   `json:"close_of_business" jsonschema:"The COB clock used"`
+	StartOfDay       string   `json:"start_of_day"`
+	WeekEnd          string   `json:"week_end" jsonschema:"The last day of a week for end of week"`
+}
+
+func addResolveDeadlinePhraseTool(mcpServer *server.MCPServer, config *Config) {
+	mcpServer.AddTool(
+		mcp.NewTool("resolve_deadline_phrase",
+			mcp.WithDescription("Turn a business deadline phrase into a concrete instant, e.g. 'EOD Friday', 'COB tomorrow', 'end of Q3', 'mid-November', 'by next week', 'in 3 days 2pm'. EOD, COB and start of day are configurable; the result lists every assumption made, such as which Friday was meant."),
+			mcp.WithString("phrase",
+				mcp.Description("The deadline phrase in English."),
+				mcp.Required(),
+			),
+			mcp.WithString("timezone",
+				mcp.Description("Timezone of the deadline. Defaults to the session or server default."),
+			),
+			mcp.WithString("at",
+				mcp.Description("Reference time in RFC 3339 that relative words like tomorrow count from. Defaults to now."),
+			),
+			mcp.WithString("eod",
+				mcp.Description("Clock of end of day (EOD) as HH:MM; 24:00 for midnight. Defaults to 17:00."),
+			),
+			mcp.WithString("cob",
+				mcp.Description("Clock of close of business (COB, EOB) as HH:MM. Defaults to 17:00."),
+			),
+			mcp.WithString("start_of_day",
+				mcp.Description("Clock of start of day, used for 'first thing' and the start of a period, as HH:MM. Defaults to 09:00."),
+			),
+			mcp.WithString("week_end",
+				mcp.Description("Last day of the week for 'end of week'. Defaults to Friday."),
+			),
+			mcp.WithOutputSchema[resolvedDeadline](),
+			mcp.WithTitleAnnotation("Resolve Deadline Phrase"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		handleResolveDeadlinePhrase(config),
+	)
+}
+
+// handleResolveDeadlinePhrase returns a handler for the resolve_deadline_phrase tool
+func handleResolveDeadlinePhrase(config *Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		phrase, err := request.RequireString("phrase")
+		if err != nil {
+			return toolError(errorInvalidArgument, err.Error()), nil
+		}
+		timezoneStr := request.GetString("timezone", "")
+		loc, err := loadTimezone(ctx, timezoneStr, config)
+		if err != nil {
+			return invalidTimezoneError("Invalid timezone", timezoneStr), nil
+		}
+		now := config.now()
+		if atStr := request.GetString("at", ""); atStr != "" {
+			if now, err = time.Parse(time.RFC3339, atStr); err != nil {
+				return toolError(errorParse, fmt.Sprintf("Invalid at: %s. Please provide RFC 3339.", atStr)), nil
+			}
+		}
+
+		var defs deadlineDefinitions
+		for _, c := range []struct {
+			name, fallback string
+			clock          *time.Duration
+		}{
+			{"eod", "17:00", &defs.EndOfDay},
+			{"cob", "17:00", &defs.CloseOfBusiness},
+			{"start_of_day", "09:00", &defs.StartOfDay},
+		} {
+			if *c.clock, err = parseClock(request.GetString(c.name, c.fallback)); err != nil {
+				return toolError(errorParse, fmt.Sprintf("Invalid %s: %v. Please provide HH:MM.", c.name, err)), nil
+			}
+		}
+		weekEnd, ok := scheduleWeekday(strings.ToLower(request.GetString("week_end", "friday")))
+		if !ok {
+			return toolError(errorInvalidArgument, fmt.Sprintf("Invalid week_end: %s. Please provide a weekday.", request.GetString("week_end", ""))), nil
+		}
+		defs.WeekEnd = weekEnd
+
+		at, assumptions, err := resolveDeadlinePhrase(phrase, now, loc, defs)
+		if err != nil {
+			return toolError(errorParse, fmt.Sprintf("Cannot resolve %q: %v", phrase, err)), nil
+		}
+		remaining := at.Sub(now)
+		result := resolvedDeadline{
+			Phrase:           phrase,
+			Timezone:         loc.String(),
+			Reference:        now.In(loc).Format(time.RFC3339),
+			Deadline:         at.Format(time.RFC3339),
+			DeadlineUTC:      at.UTC().Format(time.RFC3339),
+			Weekday:          at.Weekday().String(),
+			Remaining:        formatCountdown(remaining),
+			RemainingSeconds: remaining.Truncate(time.Second).Seconds(),
+			Passed:           remaining <= 0,
+			Assumptions:      assumptions,
+			EndOfDay:         formatClock(defs.EndOfDay),
+			CloseOfBusiness:  formatClock(defs.CloseOfBusiness),
+			StartOfDay:       formatClock(defs.StartOfDay),
+			WeekEnd:          defs.WeekEnd.String(),
+		}
+
+		data, err := json.Marshal(result)
+		if err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to encode result: %v", err)), nil
+		}
+		return mcp.NewToolResultStructured(result, string(data)), nil
+	}
+}
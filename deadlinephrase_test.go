@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestResolveDeadlinePhrase(t *testing.T) {
+	warsaw, err := time.LoadLocation("Europe/Warsaw")
+	if err != nil {
+		t.Fatalf("Failed to load timezone: %v", err)
+	}
+	// A Wednesday morning
+	now := time.Date(2026, 10, 14, 10, 0, 0, 0, warsaw)
+	defs := deadlineDefinitions{EndOfDay: 17 * time.Hour, CloseOfBusiness: 18 * time.Hour, StartOfDay: 9 * time.Hour, WeekEnd: time.Friday}
+	for _, tt := range []struct {
+		phrase string
+		want   string
+	}{
+		{"EOD Friday", "2026-10-16T17:00:00+02:00"},
+		{"COB tomorrow", "2026-10-15T18:00:00+02:00"},
+		{"by end of day Friday", "2026-10-16T17:00:00+02:00"},
+		{"next Friday", "2026-10-23T17:00:00+02:00"},
+		{"end of week", "2026-10-16T17:00:00+02:00"},
+		{"end of next month", "2026-11-30T17:00:00+01:00"},
+		{"end of Q4 2026", "2026-12-31T17:00:00+01:00"},
+		{"mid-November", "2026-11-15T17:00:00+01:00"},
+		{"by next week", "2026-10-19T09:00:00+02:00"},
+		{"first thing Monday", "2026-10-19T09:00:00+02:00"},
+		{"in 3 days 2pm", "2026-10-17T14:00:00+02:00"},
+		{"in November", "2026-11-30T17:00:00+01:00"},
+		{"by midnight Friday", "2026-10-17T00:00:00+02:00"},
+		{"EOD", "2026-10-14T17:00:00+02:00"},
+	} {
+		at, assumptions, err := resolveDeadlinePhrase(tt.phrase, now, warsaw, defs)
+		if err != nil {
+			t.Errorf("Failed to resolve %q: %v", tt.phrase, err)
+			continue
+		}
+		if got := at.Format(time.RFC3339); got != tt.want {
+			t.Errorf("Expected %q to be %s but got %s (%v)", tt.phrase, tt.want, got, assumptions)
+		}
+	}
+
+	// Q3 has ended, so it means next year's
+	at, assumptions, err := resolveDeadlinePhrase("end of Q3", now, warsaw, defs)
+	if err != nil || at.Format("2006-01-02") != "2027-09-30" || len(assumptions) == 0 {
+		t.Errorf("Expected the end of Q3 2027 with assumptions but got %s %v %v", at, assumptions, err)
+	}
+
+	for _, phrase := range []string{"", "friday friday", "EOD COB", "end of", "in 3 fortnights", "someday"} {
+		if _, _, err := resolveDeadlinePhrase(phrase, now, warsaw, defs); err == nil {
+			t.Errorf("Expected an error for %q", phrase)
+		}
+	}
+}
+
+func TestResolveDeadlinePhraseTool(t *testing.T) {
+	handler := handleResolveDeadlinePhrase(&Config{DefaultTimezone: "UTC"})
+	call := func(args map[string]any) *mcp.CallToolResult {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := handler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("Failed to call handler: %v", err)
+		}
+		return result
+	}
+
+	result := call(map[string]any{"phrase": "EOD Friday", "timezone": "America/New_York", "at": "2026-10-14T12:00:00Z", "eod": "18:30"})
+	if result.IsError {
+		t.Fatalf("Failed to resolve phrase: %s", toolResultText(result))
+	}
+	got := result.StructuredContent.(resolvedDeadline)
+	if got.Deadline != "2026-10-16T18:30:00-04:00" || got.DeadlineUTC != "2026-10-16T22:30:00Z" || got.Passed || got.EndOfDay != "18:30" {
+		t.Errorf("Expected Friday 18:30 in New York but got %+v", got)
+	}
+	if got.Weekday != "Friday" || got.Remaining != "2d 10h 30m" || len(got.Assumptions) == 0 {
+		t.Errorf("Expected the countdown and assumptions but got %+v", got)
+	}
+
+	for _, args := range []map[string]any{
+		{},
+		{"phrase": "EOD", "timezone": "Mars/Olympus"},
+		{"phrase": "EOD", "at": "yesterday"},
+		{"phrase": "EOD", "eod": "5pm"},
+		{"phrase": "EOD", "week_end": "someday"},
+		{"phrase": "whenever"},
+	} {
+		if result := call(args); !result.IsError {
+			t.Errorf("Expected an error for %v but got %s", args, toolResultText(result))
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"expvar"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"time"
+)
+
+// debugEndpointsHandler serves net/http/pprof profiles and expvar variables.
+// It runs on its own loopback-only listener, never on the MCP address.
+func debugEndpointsHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+	return mux
+}
+
+// publishDebugVars exposes server statistics through /debug/vars
+func publishDebugVars(stats *serverStats) {
+	expvar.Publish("timemcp_stats", expvar.Func(func() any { return stats.snapshot(false) }))
+}
+
+// startDebugServer serves the debug endpoints in the background
+func startDebugServer(addr string, stats *serverStats) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on debug address %s: %w", addr, err)
+	}
+	publishDebugVars(stats)
+	srv := &http.Server{Handler: debugEndpointsHandler(), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+			slog.Error("Debug server failed", "error", err)
+		}
+	}()
+	slog.Warn("Debug endpoints enabled; do not expose them publicly", "address", ln.Addr().String())
+	return nil
+}
+
+// isLoopbackAddress reports whether addr is host:port with a loopback host
+func isLoopbackAddress(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
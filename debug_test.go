@@ -0,0 +1,60 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestIsLoopbackAddress(t *testing.T) {
+	tests := []struct {
+		addr     string
+		expected bool
+	}{
+		{"127.0.0.1:6060", true},
+		{"[::1]:6060", true},
+		{"localhost:6060", true},
+		{":6060", false},
+		{"0.0.0.0:6060", false},
+		{"10.0.0.5:6060", false},
+		{"127.0.0.1", false},
+	}
+	for _, tt := range tests {
+		if got := isLoopbackAddress(tt.addr); got != tt.expected {
+			t.Errorf("isLoopbackAddress(%q): expected %v, but got %v", tt.addr, tt.expected, got)
+		}
+	}
+}
+
+func TestParseDebugSettings_RequiresLoopback(t *testing.T) {
+	t.Setenv("TIME_DEBUG_ENDPOINTS", "true")
+	t.Setenv("TIME_DEBUG_ADDRESS", ":6060")
+	if _, _, err := parseDebugSettings(); err == nil {
+		t.Error("Expected a non-loopback debug address to be rejected")
+	}
+
+	// The address is not checked while debug endpoints are disabled
+	t.Setenv("TIME_DEBUG_ENDPOINTS", "false")
+	if _, _, err := parseDebugSettings(); err != nil {
+		t.Errorf("Expected no error with debug endpoints disabled, but got %v", err)
+	}
+}
+
+func TestDebugEndpointsHandler(t *testing.T) {
+	stats := newServerStats("")
+	stats.record("get_current_time", 0, false, nil)
+	publishDebugVars(stats)
+	handler := debugEndpointsHandler()
+
+	for _, path := range []string{"/debug/pprof/", "/debug/pprof/goroutine?debug=1", "/debug/vars"} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected %s to return 200, but got %d", path, rec.Code)
+		}
+		if path == "/debug/vars" && !strings.Contains(rec.Body.String(), `"timemcp_stats"`) {
+			t.Error("Expected /debug/vars to include timemcp_stats")
+		}
+	}
+}
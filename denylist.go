@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// denylistCheckInterval bounds how often the denylist file is checked for changes
+const denylistCheckInterval = 5 * time.Second
+
+// tokenDenylist tracks revoked token IDs (jti claims). Entries come from an
+// optional file, which is re-read when it changes, an optional URL polled
+// periodically, and revocations made at runtime.
+type tokenDenylist struct {
+	file       string
+	url        string
+	refresh    time.Duration
+	httpClient *http.Client
+
+	mu          sync.RWMutex
+	fileIDs     map[string]struct{}
+	urlIDs      map[string]struct{}
+	runtimeIDs  map[string]struct{}
+	fileModTime time.Time
+	lastCheck   time.Time
+	lastFetch   time.Time
+}
+
+func newTokenDenylist(file, url string, refresh time.Duration) (*tokenDenylist, error) {
+	d := &tokenDenylist{
+		file:       file,
+		url:        url,
+		refresh:    refresh,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		fileIDs:    make(map[string]struct{}),
+		urlIDs:     make(map[string]struct{}),
+		runtimeIDs: make(map[string]struct{}),
+	}
+	if file != "" {
+		if err := d.reloadFile(); err != nil {
+			return nil, err
+		}
+	}
+	if url != "" {
+		if err := d.fetchURL(); err != nil {
+			slog.Warn("Initial denylist fetch failed", "url", url, "error", err)
+		}
+	}
+	return d, nil
+}
+
+// isRevoked reports whether the token ID has been revoked
+func (d *tokenDenylist) isRevoked(jti string) bool {
+	if jti == "" {
+		return false
+	}
+	d.maybeRefresh()
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	_, inFile := d.fileIDs[jti]
+	_, inURL := d.urlIDs[jti]
+	_, inRuntime := d.runtimeIDs[jti]
+	return inFile || inURL || inRuntime
+}
+
+// revoke adds a token ID to the denylist, persisting it to the file if configured
+func (d *tokenDenylist) revoke(jti string) error {
+	if jti == "" {
+		return fmt.Errorf("token ID is empty")
+	}
+	d.mu.Lock()
+	d.runtimeIDs[jti] = struct{}{}
+	d.mu.Unlock()
+
+	if d.file != "" {
+		return appendToDenylistFile(d.file, jti)
+	}
+	return nil
+}
+
+func (d *tokenDenylist) maybeRefresh() {
+	d.mu.Lock()
+	now := time.Now()
+	checkFile := d.file != "" && now.Sub(d.lastCheck) >= denylistCheckInterval
+	fetch := d.url != "" && now.Sub(d.lastFetch) >= d.refresh
+	if checkFile {
+		d.lastCheck = now
+	}
+	if fetch {
+		d.lastFetch = now
+	}
+	d.mu.Unlock()
+
+	if checkFile {
+		if err := d.reloadFile(); err != nil {
+			slog.Warn("Denylist reload failed", "file", d.file, "error", err)
+		}
+	}
+	if fetch {
+		if err := d.fetchURL(); err != nil {
+			slog.Warn("Denylist fetch failed", "url", d.url, "error", err)
+		}
+	}
+}
+
+func (d *tokenDenylist) reloadFile() error {
+	info, err := os.Stat(d.file)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	d.mu.RLock()
+	unchanged := info.ModTime().Equal(d.fileModTime)
+	d.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	f, err := os.Open(d.file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	ids, err := parseDenylist(f)
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	d.fileIDs = ids
+	d.fileModTime = info.ModTime()
+	d.mu.Unlock()
+	return nil
+}
+
+func (d *tokenDenylist) fetchURL() error {
+	resp, err := d.httpClient.Get(d.url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	ids, err := parseDenylist(io.LimitReader(resp.Body, jwksMaxResponseSize))
+	if err != nil {
+		return err
+	}
+	d.mu.Lock()
+	d.urlIDs = ids
+	d.mu.Unlock()
+	return nil
+}
+
+// parseDenylist reads one token ID per line; blank lines and # comments are ignored
+func parseDenylist(r io.Reader) (map[string]struct{}, error) {
+	ids := make(map[string]struct{})
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		ids[strings.Fields(line)[0]] = struct{}{}
+	}
+	return ids, scanner.Err()
+}
+
+func appendToDenylistFile(path, jti string) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open denylist file: %w", err)
+	}
+	defer f.Close()
+	_, err = fmt.Fprintf(f, "%s # revoked %s\n", jti, time.Now().UTC().Format(time.RFC3339))
+	return err
+}
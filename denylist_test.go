@@ -0,0 +1,63 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseDenylist(t *testing.T) {
+	ids, err := parseDenylist(strings.NewReader("# revoked tokens\nabc # revoked 2025-01-01\n\n  def  \n"))
+	if err != nil {
+		t.Fatalf("Failed to parse denylist: %v", err)
+	}
+	for _, id := range []string{"abc", "def"} {
+		if _, ok := ids[id]; !ok {
+			t.Errorf("Expected %q to be in the denylist", id)
+		}
+	}
+	if len(ids) != 2 {
+		t.Errorf("Expected 2 entries, but got %d", len(ids))
+	}
+}
+
+func TestValidateJWT_RevokedToken(t *testing.T) {
+	auth, err := NewAuthMiddleware("test-secret", true, "test-issuer", "test-audience")
+	if err != nil {
+		t.Fatalf("Failed to create auth middleware: %v", err)
+	}
+	denylist, err := newTokenDenylist(filepath.Join(t.TempDir(), "denylist"), "", 0)
+	if err != nil {
+		t.Fatalf("Failed to create denylist: %v", err)
+	}
+	auth.denylist = denylist
+
+	token, err := auth.GenerateToken("1", "testuser", "user", 1)
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+	if _, err := auth.validateJWT(token); err != nil {
+		t.Fatalf("Expected token to be valid before revocation, but got %v", err)
+	}
+
+	jti := tokenID(token)
+	if jti == "" {
+		t.Fatal("Expected generated token to carry a jti claim")
+	}
+	if err := denylist.revoke(jti); err != nil {
+		t.Fatalf("Failed to revoke token: %v", err)
+	}
+	if _, err := auth.validateJWT(token); !errors.Is(err, errTokenRevoked) {
+		t.Errorf("Expected errTokenRevoked, but got %v", err)
+	}
+
+	// A fresh denylist reads the revocation back from the file
+	reloaded, err := newTokenDenylist(denylist.file, "", 0)
+	if err != nil {
+		t.Fatalf("Failed to reload denylist: %v", err)
+	}
+	if !reloaded.isRevoked(jti) {
+		t.Error("Expected revocation to be persisted to the denylist file")
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// drainNotifyDelay gives open event streams time to deliver the shutdown notice
+const drainNotifyDelay = 500 * time.Millisecond
+
+// connectionDrainer coordinates graceful shutdown of MCP sessions: once
+// draining starts, new sessions are refused and long-lived event streams are
+// closed so that http.Server.Shutdown only waits for in-flight requests.
+type connectionDrainer struct {
+	draining      atomic.Bool
+	streamsCtx    context.Context
+	cancelStreams context.CancelFunc
+}
+
+func newConnectionDrainer() *connectionDrainer {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &connectionDrainer{streamsCtx: ctx, cancelStreams: cancel}
+}
+
+// handler refuses new sessions while draining, whether initialized over
+// streamable HTTP or opened as a WebSocket, and ties standalone event streams
+// and WebSocket connections (GET requests) to the drainer so they end when
+// draining starts
+func (d *connectionDrainer) handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		newSession := r.Method == http.MethodPost && r.Header.Get(server.HeaderKeySessionID) == "" || isWebSocketUpgrade(r)
+		if d.draining.Load() && newSession {
+			w.Header().Set("Connection", "close")
+			w.Header().Set("Retry-After", "5")
+			http.Error(w, "Server is shutting down", http.StatusServiceUnavailable)
+			return
+		}
+
+		if r.Method == http.MethodGet {
+			ctx, cancel := context.WithCancel(r.Context())
+			defer cancel()
+			stop := context.AfterFunc(d.streamsCtx, cancel)
+			defer stop()
+			r = r.WithContext(ctx)
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// begin starts draining: connected clients are notified, new sessions are
+// refused and open event streams are closed
+func (d *connectionDrainer) begin(mcpServer *server.MCPServer, logger string) {
+	if d.draining.Swap(true) {
+		return
+	}
+	slog.Info("Draining MCP sessions")
+	mcpServer.SendNotificationToAllClients("notifications/message", map[string]any{
+		"level":  "warning",
+		"logger": logger,
+		"data":   "Server is shutting down; please reconnect",
+	})
+	time.Sleep(drainNotifyDelay)
+	d.cancelStreams()
+}
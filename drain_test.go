@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestConnectionDrainer_RefusesNewSessions(t *testing.T) {
+	drainer := newConnectionDrainer()
+	handler := drainer.handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	serve := func(r *http.Request) int {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, r)
+		return rec.Code
+	}
+	initialize := func() *http.Request { return httptest.NewRequest(http.MethodPost, "/mcp", nil) }
+	existing := func() *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		r.Header.Set(server.HeaderKeySessionID, "session-1")
+		return r
+	}
+	upgrade := func() *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/mcp", nil)
+		r.Header.Set("Connection", "Upgrade")
+		r.Header.Set("Upgrade", "websocket")
+		return r
+	}
+
+	if code := serve(initialize()); code != http.StatusOK {
+		t.Errorf("Expected new sessions before draining but got %d", code)
+	}
+	if code := serve(upgrade()); code != http.StatusOK {
+		t.Errorf("Expected WebSocket upgrades before draining but got %d", code)
+	}
+
+	drainer.begin(server.NewMCPServer("TimeMCP", "test"), "TimeMCP")
+	if code := serve(initialize()); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected new sessions to be refused while draining but got %d", code)
+	}
+	if code := serve(upgrade()); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected WebSocket upgrades to be refused while draining but got %d", code)
+	}
+	if code := serve(existing()); code != http.StatusOK {
+		t.Errorf("Expected requests of existing sessions to be served while draining but got %d", code)
+	}
+}
+
+func TestConnectionDrainer_ClosesStreams(t *testing.T) {
+	drainer := newConnectionDrainer()
+	done := make(chan error, 1)
+	handler := drainer.handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		done <- r.Context().Err()
+	}))
+	go handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/mcp", nil))
+
+	drainer.begin(server.NewMCPServer("TimeMCP", "test"), "TimeMCP")
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected the stream to be cancelled but got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("Expected the event stream to end when draining starts")
+	}
+}
+
+func TestShutdownHTTPServer(t *testing.T) {
+	start := func(t *testing.T, handler http.Handler) (*http.Server, string) {
+		t.Helper()
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		srv := &http.Server{Handler: handler}
+		go srv.Serve(listener)
+		return srv, "http://" + listener.Addr().String()
+	}
+
+	t.Run("in-flight requests complete", func(t *testing.T) {
+		started, release := make(chan struct{}), make(chan struct{})
+		drainer := newConnectionDrainer()
+		srv, url := start(t, drainer.handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+			w.WriteHeader(http.StatusOK)
+		})))
+
+		result := make(chan int, 1)
+		go func() {
+			resp, err := http.Post(url+"/mcp", "application/json", nil)
+			if err != nil {
+				result <- 0
+				return
+			}
+			resp.Body.Close()
+			result <- resp.StatusCode
+		}()
+		<-started
+
+		var wg sync.WaitGroup
+		shutdown := make(chan error, 1)
+		go func() {
+			shutdown <- shutdownHTTPServer(srv, &Config{HTTPShutdownTimeout: 5 * time.Second}, func() {
+				drainer.begin(server.NewMCPServer("TimeMCP", "test"), "TimeMCP")
+			}, &wg)
+		}()
+		time.Sleep(drainNotifyDelay + 100*time.Millisecond)
+		close(release)
+
+		if code := <-result; code != http.StatusOK {
+			t.Errorf("Expected the in-flight request to complete with 200 but got %d", code)
+		}
+		if err := <-shutdown; err != nil {
+			t.Errorf("Expected a clean shutdown but got %v", err)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		started, release := make(chan struct{}), make(chan struct{})
+		defer close(release)
+		srv, url := start(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+		}))
+		go func() {
+			if resp, err := http.Post(url+"/mcp", "application/json", nil); err == nil {
+				resp.Body.Close()
+			}
+		}()
+		<-started
+
+		var wg sync.WaitGroup
+		err := shutdownHTTPServer(srv, &Config{HTTPShutdownTimeout: 100 * time.Millisecond}, nil, &wg)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected the shutdown to time out but got %v", err)
+		}
+	})
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// maxDSTCalendarYears caps how far ahead dst_calendar looks
+	maxDSTCalendarYears = 50
+	// maxDSTCalendarZones caps the zones in one dst_calendar call
+	maxDSTCalendarZones = 20
+)
+
+// dstCalendar is the structured result of dst_calendar
+type dstCalendar struct {
+	From  string        `json:"from"`
+	To    string        `json:"to"`
+	Zones []zoneChanges `json:"zones"`
+}
+
+type zoneChanges struct {
+	Timezone    string          `json:"timezone"`
+	Transitions []dstTransition `json:"transitions"`
+}
+
+// dstTransition is one change of UTC offset as it affects wall clocks
+type dstTransition struct {
+	At           string `json:"at" jsonschema:"Instant of the change in RFC 3339, UTC"`
+	LocalBefore  string `json:"local_before" jsonschema:"Wall clock time the change happens at, in the old offset"`
+	LocalAfter   string `json:"local_after" jsonschema:"Wall clock time right after the change, in the new offset"`
+	OffsetFrom   string `json:"offset_from"`
+	OffsetTo     string `json:"offset_to"`
+	Delta        string `json:"delta" jsonschema:"Change of the offset, e.g. +1h"`
+	Abbreviation string `json:"abbreviation"`
+	IsDST        bool   `json:"is_dst"`
+	// Skipped and Repeated are the wall clock ranges that do not exist or
+	// happen twice, which is what shifts or doubles cron jobs
+	Skipped  string `json:"skipped,omitempty" jsonschema:"Wall clock times that do not exist, HH:MM-HH:MM"`
+	Repeated string `json:"repeated,omitempty" jsonschema:"Wall clock times that happen twice, HH:MM-HH:MM"`
+}
+
+// newDSTTransition describes tr as seen in loc
+func newDSTTransition(tr zoneTransition, loc *time.Location) dstTransition {
+	before := tr.localStart()
+	after := tr.At.In(loc)
+	delta := tr.OffsetTo - tr.OffsetFrom
+	d := dstTransition{
+		At:           tr.At.Format(time.RFC3339),
+		LocalBefore:  before.Format(time.RFC3339),
+		LocalAfter:   after.Format(time.RFC3339),
+		OffsetFrom:   before.Format("-07:00"),
+		OffsetTo:     after.Format("-07:00"),
+		Delta:        formatOffsetDifference(delta),
+		Abbreviation: tr.Name,
+		IsDST:        tr.IsDST,
+	}
+	switch {
+	case delta > 0:
+		d.Skipped = before.Format("15:04") + "-" + after.Format("15:04")
+	case delta < 0:
+		d.Repeated = after.Format("15:04") + "-" + before.Format("15:04")
+	}
+	return d
+}
+
+func addDSTCalendarTool(mcpServer *server.MCPServer, config *Config) {
+	mcpServer.AddTool(
+		mcp.NewTool("dst_calendar",
+			mcp.WithDescription("List the daylight saving and other UTC offset transitions of one or more timezones over the next years, with exact instants, offset deltas and the wall clock times that are skipped or repeated, to plan cron and maintenance schedules around them."),
+			mcp.WithArray("timezones",
+				mcp.Description("IANA timezone names, e.g. [\"Europe/Warsaw\", \"America/New_York\"]."),
+				mcp.WithStringItems(),
+				mcp.MinItems(1),
+				mcp.MaxItems(maxDSTCalendarZones),
+				mcp.Required(),
+			),
+			mcp.WithNumber("years",
+				mcp.Description(fmt.Sprintf("How many years ahead to list. Defaults to 1; at most %d.", maxDSTCalendarYears)),
+			),
+			mcp.WithString("from",
+				mcp.Description("Start of the period in RFC 3339 or YYYY-MM-DD (UTC). Defaults to now."),
+			),
+			mcp.WithOutputSchema[dstCalendar](),
+			mcp.WithTitleAnnotation("DST Calendar"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		handleDSTCalendar(config),
+	)
+}
+
+// handleDSTCalendar returns a handler for the dst_calendar tool
+func handleDSTCalendar(config *Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		names, err := request.RequireStringSlice("timezones")
+		if err != nil {
+			return toolError(errorInvalidArgument, err.Error()), nil
+		}
+		if len(names) == 0 || len(names) > maxDSTCalendarZones {
+			return toolError(errorInvalidArgument, fmt.Sprintf("Provide between 1 and %d timezones", maxDSTCalendarZones)), nil
+		}
+		years := request.GetInt("years", 1)
+		if years < 1 || years > maxDSTCalendarYears {
+			return toolError(errorInvalidArgument, fmt.Sprintf("Invalid years: %d. Please provide 1 to %d.", years, maxDSTCalendarYears)), nil
+		}
+
+		from := config.now().UTC()
+		if fromStr := request.GetString("from", ""); fromStr != "" {
+			if from, err = time.Parse(time.RFC3339, fromStr); err != nil {
+				if from, err = time.Parse("2006-01-02", fromStr); err != nil {
+					return toolError(errorParse, fmt.Sprintf("Invalid from: %s. Please provide RFC 3339 or YYYY-MM-DD.", fromStr)), nil
+				}
+			}
+		}
+		to := from.AddDate(years, 0, 0)
+
+		calendar := dstCalendar{From: from.UTC().Format(time.RFC3339), To: to.UTC().Format(time.RFC3339)}
+		for _, name := range names {
+			name = strings.TrimSpace(name)
+			loc, err := loadLocationCached(name)
+			if err != nil || name == "" {
+				return invalidTimezoneError("Invalid timezone", name), nil
+			}
+			zone := zoneChanges{Timezone: loc.String(), Transitions: []dstTransition{}}
+			for _, tr := range zoneTransitions(loc, from, to) {
+				// Abbreviation-only changes leave wall clocks alone
+				if tr.OffsetFrom == tr.OffsetTo {
+					continue
+				}
+				zone.Transitions = append(zone.Transitions, newDSTTransition(tr, loc))
+			}
+			calendar.Zones = append(calendar.Zones, zone)
+		}
+
+		data, err := json.Marshal(calendar)
+		if err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to encode result: %v", err)), nil
+		}
+		return mcp.NewToolResultStructured(calendar, string(data)), nil
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestDSTCalendar(t *testing.T) {
+	clock := newFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	handler := handleDSTCalendar(&Config{DefaultTimezone: "UTC", Clock: clock})
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]any{"timezones": []any{"Europe/Warsaw", "Asia/Tokyo"}, "years": 2}
+	result, err := handler(context.Background(), request)
+	if err != nil || result.IsError {
+		t.Fatalf("Failed to list transitions: %v %s", err, toolResultText(result))
+	}
+	calendar := result.StructuredContent.(dstCalendar)
+	if len(calendar.Zones) != 2 {
+		t.Fatalf("Expected 2 zones but got %d", len(calendar.Zones))
+	}
+	if tokyo := calendar.Zones[1]; len(tokyo.Transitions) != 0 {
+		t.Errorf("Expected no transitions in Asia/Tokyo but got %v", tokyo.Transitions)
+	}
+
+	warsaw := calendar.Zones[0].Transitions
+	if len(warsaw) != 4 {
+		t.Fatalf("Expected 4 transitions in Europe/Warsaw over 2 years but got %d", len(warsaw))
+	}
+	spring := dstTransition{
+		At:           "2025-03-30T01:00:00Z",
+		LocalBefore:  "2025-03-30T02:00:00+01:00",
+		LocalAfter:   "2025-03-30T03:00:00+02:00",
+		OffsetFrom:   "+01:00",
+		OffsetTo:     "+02:00",
+		Delta:        "+1h",
+		Abbreviation: "CEST",
+		IsDST:        true,
+		Skipped:      "02:00-03:00",
+	}
+	if warsaw[0] != spring {
+		t.Errorf("Expected %+v but got %+v", spring, warsaw[0])
+	}
+	if fall := warsaw[1]; fall.At != "2025-10-26T01:00:00Z" || fall.Delta != "-1h" || fall.Repeated != "02:00-03:00" || fall.Skipped != "" {
+		t.Errorf("Expected the fall back on 2025-10-26 repeating 02:00-03:00 but got %+v", fall)
+	}
+
+	// A half-hour shift
+	request.Params.Arguments = map[string]any{"timezones": []any{"Australia/Lord_Howe"}, "from": "2025-01-01"}
+	result, _ = handler(context.Background(), request)
+	if tr := result.StructuredContent.(dstCalendar).Zones[0].Transitions; len(tr) != 2 || tr[0].Delta != "-0h30m" {
+		t.Errorf("Expected two half-hour transitions in Australia/Lord_Howe but got %+v", tr)
+	}
+}
+
+func TestDSTCalendarErrors(t *testing.T) {
+	handler := handleDSTCalendar(&Config{DefaultTimezone: "UTC"})
+	tests := []struct {
+		args map[string]any
+		code toolErrorCode
+	}{
+		{map[string]any{}, errorInvalidArgument},
+		{map[string]any{"timezones": []any{"Europe/Warsw"}}, errorInvalidTimezone},
+		{map[string]any{"timezones": []any{""}}, errorInvalidTimezone},
+		{map[string]any{"timezones": []any{"UTC"}, "years": 0}, errorInvalidArgument},
+		{map[string]any{"timezones": []any{"UTC"}, "years": 51}, errorInvalidArgument},
+		{map[string]any{"timezones": []any{"UTC"}, "from": "next year"}, errorParse},
+	}
+	for _, tt := range tests {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = tt.args
+		result, err := handler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("Failed to call handler: %v", err)
+		}
+		if code := toolErrorCodeOf(result); code != tt.code {
+			t.Errorf("Expected %v to fail with %s but got %q: %s", tt.args, tt.code, code, toolResultText(result))
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseEnvFile(t *testing.T) {
+	vars, err := parseEnvFile(`
+# local development
+TIME_HTTP_ADDRESS=:9090
+export TIME_DEFAULT_TIMEZONE=UTC # trailing comment
+TIME_AUTH_SECRET_KEY='literal #value\n'
+TIME_HTTP_CSP="default-src 'self'\tx\"y"
+TIME_EMPTY=
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse env file: %v", err)
+	}
+	expected := map[string]string{
+		"TIME_HTTP_ADDRESS":     ":9090",
+		"TIME_DEFAULT_TIMEZONE": "UTC",
+		"TIME_AUTH_SECRET_KEY":  `literal #value\n`,
+		"TIME_HTTP_CSP":         "default-src 'self'\tx\"y",
+		"TIME_EMPTY":            "",
+	}
+	if len(vars) != len(expected) {
+		t.Errorf("Expected %d variables but got %d: %v", len(expected), len(vars), vars)
+	}
+	for key, want := range expected {
+		if got := vars[key]; got != want {
+			t.Errorf("Expected %s=%q but got %q", key, want, got)
+		}
+	}
+
+	for _, bad := range []string{"NOEQUALS", "BAD KEY=1", `QUOTE="open`, "=value"} {
+		if _, err := parseEnvFile(bad); err == nil {
+			t.Errorf("Expected error for %q", bad)
+		}
+	}
+}
+
+func TestLoadEnvFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte("TIME_TEST_ENV_FILE_A=file\nTIME_TEST_ENV_FILE_B=file\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write env file: %v", err)
+	}
+	t.Setenv("TIME_TEST_ENV_FILE_A", "shell")
+	t.Setenv("TIME_TEST_ENV_FILE_B", "")
+	os.Unsetenv("TIME_TEST_ENV_FILE_B")
+
+	if err := loadEnvFile(path); err != nil {
+		t.Fatalf("Failed to load env file: %v", err)
+	}
+	if got := os.Getenv("TIME_TEST_ENV_FILE_A"); got != "shell" {
+		t.Errorf("Expected shell value to take precedence but got %q", got)
+	}
+	if got := os.Getenv("TIME_TEST_ENV_FILE_B"); got != "file" {
+		t.Errorf("Expected value from env file but got %q", got)
+	}
+}
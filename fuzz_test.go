@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Fuzz targets for parsers that accept client-controlled strings. The seed
+// corpus runs with go test; explore further with e.g.
+// go test -run '^$' -fuzz FuzzParseICalDuration -fuzztime 30s .
+
+func FuzzParseCORSOrigins(f *testing.F) {
+	for _, seed := range []string{"", "*", "https://app.example.com, http://localhost:3000", "a,,a, b ", "://", "http://[::1", "https://user@host:8443/path?q"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, origins string) {
+		seen := make(map[string]bool)
+		for _, origin := range parseCORSOrigins(origins) {
+			if origin == "" || origin != strings.TrimSpace(origin) {
+				t.Errorf("Expected trimmed non-empty origins but got %q from %q", origin, origins)
+			}
+			if strings.Contains(origin, "://") {
+				t.Errorf("Expected URLs to be reduced to hosts but got %q from %q", origin, origins)
+			}
+			if seen[origin] {
+				t.Errorf("Expected no duplicate origins but got %q twice from %q", origin, origins)
+			}
+			seen[origin] = true
+		}
+	})
+}
+
+func FuzzLoadTimezone(f *testing.F) {
+	for _, seed := range []string{"UTC", "Asia/Tokyo", "America/Argentina/Buenos_Aires", "Etc/GMT+5", "Local", "../../etc/passwd", "/etc/localtime", "Mars/Olympus", "\x00"} {
+		f.Add(seed)
+	}
+	config := &Config{DefaultTimezone: "UTC"}
+	f.Fuzz(func(t *testing.T, name string) {
+		loc, err := loadTimezone(context.Background(), name, config)
+		if err != nil {
+			return
+		}
+		if loc == nil {
+			t.Fatalf("Expected a location or an error for %q", name)
+		}
+		if strings.Contains(name, "..") || strings.HasPrefix(name, "/") {
+			t.Errorf("Expected path-like name %q to be rejected", name)
+		}
+		_, offset := time.Date(2025, 6, 2, 12, 0, 0, 0, loc).Zone()
+		if offset < -24*3600 || offset > 24*3600 {
+			t.Errorf("Expected an offset within a day but got %d for %q", offset, name)
+		}
+	})
+}
+
+func FuzzAcceptLanguageTimezone(f *testing.F) {
+	for _, seed := range []string{"pl-PL,en;q=0.5", "en-US", "zh-Hant-TW;q=0.9, ja-JP;q=0.8", "de-DE;q=abc", "-,;;q=", "en-GB;q=-1"} {
+		f.Add(seed)
+	}
+	zones := make(map[string]bool)
+	for _, zone := range regionTimezones {
+		zones[zone] = true
+	}
+	f.Fuzz(func(t *testing.T, header string) {
+		if zone := acceptLanguageTimezone(header); zone != "" && !zones[zone] {
+			t.Errorf("Expected a known region zone but got %q from %q", zone, header)
+		}
+	})
+}
+
+// FuzzConvertTime feeds the time and timezone arguments of convert_time
+// through the datetime parser
+func FuzzConvertTime(f *testing.F) {
+	for _, seed := range [][3]string{
+		{"14:30", "UTC", "Asia/Tokyo"},
+		{"2:30 PM", "America/New_York", "Europe/London"},
+		{"25:99", "UTC", "UTC"},
+		{"12:00:00.000000001", "", "Etc/GMT-14"},
+		{"1/2/3 4:5", "Europe/Warsaw", "Pacific/Kiritimati"},
+		{"noon tomorrow", "UTC", "UTC"},
+		{"", "Invalid/Zone", "UTC"},
+	} {
+		f.Add(seed[0], seed[1], seed[2])
+	}
+	config := &Config{DefaultTimezone: "UTC"}
+	f.Fuzz(func(t *testing.T, timeStr, source, target string) {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]any{"time": timeStr, "source_timezone": source, "target_timezone": target}
+		sourceTime, targetTime, _, _, errResult := resolveConversion(context.Background(), request, config)
+		if errResult != nil {
+			if !errResult.IsError {
+				t.Errorf("Expected an error result for %q %q %q", timeStr, source, target)
+			}
+			return
+		}
+		if !sourceTime.Equal(targetTime) {
+			t.Errorf("Expected the converted time to be the same instant but got %v and %v", sourceTime, targetTime)
+		}
+	})
+}
+
+func FuzzParseICalDuration(f *testing.F) {
+	for _, seed := range []string{"PT1H30M", "P1D", "-P1W", "+P2DT3H", "P", "PT", "P1M", "PT1D", "P99999999999999999999W", "PT9223372036S"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		d, err := parseICalDuration(s)
+		if err != nil {
+			return
+		}
+		if negative := strings.HasPrefix(s, "-"); (d < 0) != negative && d != 0 {
+			t.Errorf("Expected the sign of %q to be kept but got %v", s, d)
+		}
+		if neg, err := parseICalDuration("-" + strings.TrimPrefix(s, "+")); !strings.HasPrefix(s, "-") && (err != nil || neg != -d) {
+			t.Errorf("Expected -%s to be %v but got %v, %v", s, -d, neg, err)
+		}
+	})
+}
+
+// FuzzClockInterval checks the interval validation of subscribe_clock
+func FuzzClockInterval(f *testing.F) {
+	for _, seed := range []string{"1s", "500ms", "1h", "-1s", "9223372036854775807ns", "1.5e3s", ""} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, interval string) {
+		if d, ok := parseClockInterval(interval); ok && (d < minClockInterval || d > maxClockInterval) {
+			t.Errorf("Expected %q to be rejected but got %v", interval, d)
+		}
+	})
+}
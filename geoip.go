@@ -0,0 +1,299 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"os"
+)
+
+// mmdbMetadataMarker precedes the metadata map at the end of a MaxMind DB
+var mmdbMetadataMarker = []byte("\xab\xcd\xefMaxMind.com")
+
+// geoIPDatabase reads MaxMind DB (.mmdb) files such as GeoLite2-City, which
+// map networks to records with a location.time_zone. Only lookups are
+// supported; the whole file is read into memory.
+type geoIPDatabase struct {
+	data       []byte // search tree followed by the data section
+	nodeCount  uint
+	recordSize uint
+	ipVersion  uint
+	dataStart  uint
+	ipv4Start  uint
+}
+
+func openGeoIPDatabase(path string) (*geoIPDatabase, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return newGeoIPDatabase(data)
+}
+
+func newGeoIPDatabase(data []byte) (*geoIPDatabase, error) {
+	marker := bytes.LastIndex(data, mmdbMetadataMarker)
+	if marker < 0 {
+		return nil, errors.New("not a MaxMind DB file: metadata marker not found")
+	}
+	metaStart := uint(marker + len(mmdbMetadataMarker))
+	meta, _, err := (&mmdbDecoder{data: data[metaStart:]}).decode(0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MaxMind DB metadata: %w", err)
+	}
+	fields, ok := meta.(map[string]any)
+	if !ok {
+		return nil, errors.New("invalid MaxMind DB metadata: not a map")
+	}
+	db := &geoIPDatabase{data: data[:marker]}
+	for name, dst := range map[string]*uint{"node_count": &db.nodeCount, "record_size": &db.recordSize, "ip_version": &db.ipVersion} {
+		value, ok := fields[name].(uint64)
+		if !ok {
+			return nil, fmt.Errorf("invalid MaxMind DB metadata: missing %s", name)
+		}
+		*dst = uint(value)
+	}
+	if db.recordSize != 24 && db.recordSize != 28 && db.recordSize != 32 {
+		return nil, fmt.Errorf("unsupported MaxMind DB record size %d", db.recordSize)
+	}
+	treeSize := db.nodeCount * db.recordSize / 4
+	// The data section follows the tree and 16 zero bytes
+	db.dataStart = treeSize + 16
+	if db.dataStart > uint(len(db.data)) {
+		return nil, errors.New("invalid MaxMind DB: search tree exceeds the file")
+	}
+
+	// In IPv6 databases IPv4 addresses live under ::/96
+	if db.ipVersion == 6 {
+		node := uint(0)
+		for i := 0; i < 96 && node < db.nodeCount; i++ {
+			if node, err = db.record(node, 0); err != nil {
+				return nil, err
+			}
+		}
+		db.ipv4Start = node
+	}
+	return db, nil
+}
+
+// record returns the left (bit 0) or right (bit 1) record of a node
+func (db *geoIPDatabase) record(node, bit uint) (uint, error) {
+	size := db.recordSize / 4
+	offset := node * size
+	if offset+size > db.dataStart {
+		return 0, errors.New("invalid MaxMind DB: node outside the search tree")
+	}
+	b := db.data[offset : offset+size]
+	switch db.recordSize {
+	case 24:
+		b = b[bit*3:]
+		return uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2]), nil
+	case 28:
+		if bit == 0 {
+			return uint(b[3]&0xf0)<<20 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2]), nil
+		}
+		return uint(b[3]&0x0f)<<24 | uint(b[4])<<16 | uint(b[5])<<8 | uint(b[6]), nil
+	default:
+		return uint(binary.BigEndian.Uint32(b[bit*4:])), nil
+	}
+}
+
+// lookup returns the record for ip, or nil when the database has none
+func (db *geoIPDatabase) lookup(ip net.IP) (any, error) {
+	node := uint(0)
+	bits := ip.To16()
+	if ip4 := ip.To4(); ip4 != nil {
+		bits = ip4
+		node = db.ipv4Start
+	} else if db.ipVersion == 4 {
+		return nil, nil
+	}
+	if bits == nil {
+		return nil, fmt.Errorf("invalid IP address %v", ip)
+	}
+
+	for i := 0; i < len(bits)*8 && node < db.nodeCount; i++ {
+		bit := uint(bits[i/8]>>(7-i%8)) & 1
+		var err error
+		if node, err = db.record(node, bit); err != nil {
+			return nil, err
+		}
+	}
+	if node <= db.nodeCount {
+		return nil, nil
+	}
+	offset := node - db.nodeCount - 16
+	value, _, err := (&mmdbDecoder{data: db.data[db.dataStart:]}).decode(offset)
+	return value, err
+}
+
+// timezone returns the location.time_zone of the record for ip
+func (db *geoIPDatabase) timezone(ip net.IP) string {
+	record, err := db.lookup(ip)
+	if err != nil {
+		return ""
+	}
+	fields, _ := record.(map[string]any)
+	location, _ := fields["location"].(map[string]any)
+	zone, _ := location["time_zone"].(string)
+	return zone
+}
+
+// mmdbDecoder decodes the MaxMind DB data section format. Integers of every
+// width decode to uint64 (int32 to int64), so callers need one type switch.
+type mmdbDecoder struct {
+	data  []byte
+	depth int
+}
+
+// maxMMDBDepth bounds nesting so a corrupt file cannot recurse forever
+const maxMMDBDepth = 64
+
+const (
+	mmdbExtended = iota
+	mmdbPointer
+	mmdbString
+	mmdbDouble
+	mmdbBytes
+	mmdbUint16
+	mmdbUint32
+	mmdbMap
+	mmdbInt32
+	mmdbUint64
+	mmdbUint128
+	mmdbArray
+	mmdbContainer
+	mmdbEndMarker
+	mmdbBool
+	mmdbFloat
+)
+
+var errMMDBTruncated = errors.New("truncated MaxMind DB data")
+
+// bytes returns n bytes at offset
+func (d *mmdbDecoder) bytes(offset, n uint) ([]byte, error) {
+	if offset+n > uint(len(d.data)) || offset+n < offset {
+		return nil, errMMDBTruncated
+	}
+	return d.data[offset : offset+n], nil
+}
+
+// uint decodes a big-endian unsigned integer of n bytes
+func (d *mmdbDecoder) uint(offset, n uint) (uint64, error) {
+	b, err := d.bytes(offset, n)
+	if err != nil {
+		return 0, err
+	}
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+	return v, nil
+}
+
+// decode returns the value at offset and the offset after it
+func (d *mmdbDecoder) decode(offset uint) (any, uint, error) {
+	if d.depth++; d.depth > maxMMDBDepth {
+		return nil, 0, errors.New("invalid MaxMind DB data: nesting too deep")
+	}
+	defer func() { d.depth-- }()
+	ctrl, err := d.bytes(offset, 1)
+	if err != nil {
+		return nil, 0, err
+	}
+	offset++
+	kind := uint(ctrl[0] >> 5)
+
+	if kind == mmdbPointer {
+		ss, vvv := uint(ctrl[0]>>3)&3, uint64(ctrl[0]&7)
+		v, err := d.uint(offset, ss+1)
+		if err != nil {
+			return nil, 0, err
+		}
+		var target uint64
+		switch ss {
+		case 0:
+			target = vvv<<8 | v
+		case 1:
+			target = (vvv<<16 | v) + 2048
+		case 2:
+			target = (vvv<<24 | v) + 526336
+		default:
+			target = v
+		}
+		value, _, err := d.decode(uint(target))
+		return value, offset + ss + 1, err
+	}
+
+	if kind == mmdbExtended {
+		ext, err := d.bytes(offset, 1)
+		if err != nil {
+			return nil, 0, err
+		}
+		kind = 7 + uint(ext[0])
+		offset++
+	}
+	size := uint(ctrl[0] & 0x1f)
+	if size >= 29 {
+		n := size - 28
+		v, err := d.uint(offset, n)
+		if err != nil {
+			return nil, 0, err
+		}
+		size = []uint{29, 285, 65821}[n-1] + uint(v)
+		offset += n
+	}
+
+	switch kind {
+	case mmdbString:
+		b, err := d.bytes(offset, size)
+		return string(b), offset + size, err
+	case mmdbBytes:
+		b, err := d.bytes(offset, size)
+		return b, offset + size, err
+	case mmdbDouble:
+		v, err := d.uint(offset, 8)
+		return math.Float64frombits(v), offset + 8, err
+	case mmdbFloat:
+		v, err := d.uint(offset, 4)
+		return float64(math.Float32frombits(uint32(v))), offset + 4, err
+	case mmdbUint16, mmdbUint32, mmdbUint64:
+		v, err := d.uint(offset, size)
+		return v, offset + size, err
+	case mmdbUint128:
+		b, err := d.bytes(offset, size)
+		return b, offset + size, err
+	case mmdbInt32:
+		v, err := d.uint(offset, size)
+		return int64(int32(uint32(v))), offset + size, err
+	case mmdbBool:
+		return size != 0, offset, nil
+	case mmdbMap:
+		m := make(map[string]any, size)
+		for range size {
+			key, next, err := d.decode(offset)
+			if err != nil {
+				return nil, 0, err
+			}
+			name, ok := key.(string)
+			if !ok {
+				return nil, 0, errors.New("invalid MaxMind DB data: map key is not a string")
+			}
+			if m[name], offset, err = d.decode(next); err != nil {
+				return nil, 0, err
+			}
+		}
+		return m, offset, nil
+	case mmdbArray:
+		a := make([]any, size)
+		for i := range a {
+			if a[i], offset, err = d.decode(offset); err != nil {
+				return nil, 0, err
+			}
+		}
+		return a, offset, nil
+	}
+	return nil, 0, fmt.Errorf("unsupported MaxMind DB data type %d", kind)
+}
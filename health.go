@@ -0,0 +1,280 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Component and overall statuses of /health, from best to worst
+const (
+	healthOK        = "ok"
+	healthDegraded  = "degraded"
+	healthUnhealthy = "unhealthy"
+)
+
+const (
+	// healthCheckTimeout bounds each upstream check of /health
+	healthCheckTimeout = 2 * time.Second
+	// ntpCheckInterval is how long NTP results are reused, so frequent
+	// health polls do not flood the NTP servers
+	ntpCheckInterval = 30 * time.Second
+	// ntpEpochOffset is the number of seconds from the NTP epoch (1900) to
+	// the Unix epoch
+	ntpEpochOffset = 2208988800
+)
+
+// componentHealth is the status of one dependency in /health
+type componentHealth struct {
+	Status  string         `json:"status"`
+	Message string         `json:"message,omitempty"`
+	Details map[string]any `json:"details,omitempty"`
+}
+
+// healthRank orders statuses so the worst component sets the overall status
+var healthRank = map[string]int{healthOK: 0, healthDegraded: 1, healthUnhealthy: 2}
+
+// rollupHealth returns the worst status of the components; an empty set is ok
+func rollupHealth(components map[string]componentHealth) string {
+	status := healthOK
+	for _, c := range components {
+		if healthRank[c.Status] > healthRank[status] {
+			status = c.Status
+		}
+	}
+	return status
+}
+
+// healthComponents checks each configured dependency. Losing the tz
+// database, the key set or draining makes the server unhealthy; a failing
+// store or NTP peer only degrades it, as most tools work without them.
+func (p *httpRuntime) healthComponents(ctx context.Context, config *Config, ntp *ntpChecker) map[string]componentHealth {
+	components := make(map[string]componentHealth)
+	if err := checkTimezoneData(config); err != nil {
+		components["tzdata"] = componentHealth{Status: healthUnhealthy, Message: err.Error()}
+	} else {
+		components["tzdata"] = componentHealth{Status: healthOK}
+	}
+	if p != nil && p.store != nil {
+		checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+		err := p.store.ping(checkCtx)
+		cancel()
+		details := map[string]any{"backend": config.Store}
+		if err != nil {
+			components["store"] = componentHealth{Status: healthDegraded, Message: err.Error(), Details: details}
+		} else {
+			components["store"] = componentHealth{Status: healthOK, Details: details}
+		}
+	}
+	if p != nil && p.contextFunc != nil {
+		if auth := p.contextFunc.auth(); auth != nil && auth.jwks != nil {
+			components["jwks"] = auth.jwks.health()
+		}
+	}
+	if ntp != nil {
+		components["ntp"] = ntp.check(ctx)
+	}
+	if p != nil && p.drainer != nil && p.drainer.draining.Load() {
+		components["shutdown"] = componentHealth{Status: healthUnhealthy, Message: "server is draining"}
+	}
+	return components
+}
+
+// health reports whether the key set is loaded and its URL reachable. The
+// set is refreshed when stale, at most once per minimum refresh interval.
+func (c *jwksCache) health() componentHealth {
+	c.mu.RLock()
+	stale := len(c.keys) == 0 || time.Since(c.fetchedAt) >= c.refreshInterval
+	due := time.Since(c.attemptedAt) >= c.minRefreshInterval
+	c.mu.RUnlock()
+	if stale && due {
+		if err := c.refresh(); err != nil {
+			slog.Warn("JWKS refresh failed", "url", c.url, "error", err)
+		}
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	details := map[string]any{"url": c.url, "keys": len(c.keys)}
+	if !c.fetchedAt.IsZero() {
+		details["fetched_at"] = c.fetchedAt.UTC().Format(time.RFC3339)
+	}
+	switch {
+	case len(c.keys) == 0:
+		return componentHealth{Status: healthUnhealthy, Message: fmt.Sprintf("no keys loaded: %v", c.lastErr), Details: details}
+	case c.lastErr != nil:
+		return componentHealth{Status: healthDegraded, Message: fmt.Sprintf("last refresh failed: %v", c.lastErr), Details: details}
+	}
+	return componentHealth{Status: healthOK, Details: details}
+}
+
+// ntpChecker compares the system clock with NTP servers and caches the
+// result for ntpCheckInterval
+type ntpChecker struct {
+	servers   []string
+	maxOffset time.Duration
+	query     func(ctx context.Context, server string) (offset, rtt time.Duration, err error)
+
+	mu        sync.Mutex
+	checkedAt time.Time
+	result    componentHealth
+}
+
+func newNTPChecker(servers []string, maxOffset time.Duration) *ntpChecker {
+	return &ntpChecker{servers: servers, maxOffset: maxOffset, query: queryNTP}
+}
+
+// check queries every server in parallel. The clock is judged by the
+// reachable server with the shortest round trip; no reachable server, or an
+// offset beyond the maximum, degrades the component.
+func (n *ntpChecker) check(ctx context.Context) componentHealth {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if !n.checkedAt.IsZero() && time.Since(n.checkedAt) < ntpCheckInterval {
+		return n.result
+	}
+
+	type peer struct {
+		server      string
+		offset, rtt time.Duration
+		err         error
+	}
+	peers := make([]peer, len(n.servers))
+	var wg sync.WaitGroup
+	for i, server := range n.servers {
+		wg.Go(func() {
+			offset, rtt, err := n.query(ctx, server)
+			peers[i] = peer{server, offset, rtt, err}
+		})
+	}
+	wg.Wait()
+
+	details := make(map[string]any)
+	var best *peer
+	for i, p := range peers {
+		if p.err != nil {
+			details[p.server] = map[string]any{"error": p.err.Error()}
+			continue
+		}
+		details[p.server] = map[string]any{"offset_ms": p.offset.Milliseconds(), "rtt_ms": p.rtt.Milliseconds()}
+		if best == nil || p.rtt < best.rtt {
+			best = &peers[i]
+		}
+	}
+	switch {
+	case best == nil:
+		n.result = componentHealth{Status: healthDegraded, Message: "no NTP server reachable", Details: details}
+	case best.offset.Abs() > n.maxOffset:
+		n.result = componentHealth{Status: healthDegraded, Message: fmt.Sprintf("clock is off by %s according to %s (limit %s)", best.offset.Round(time.Millisecond), best.server, n.maxOffset), Details: details}
+	default:
+		n.result = componentHealth{Status: healthOK, Details: details}
+	}
+	n.checkedAt = time.Now()
+	return n.result
+}
+
+// queryNTP sends one SNTP request (RFC 4330) and returns the offset of the
+// system clock from the server's and the round trip delay
+func queryNTP(ctx context.Context, server string) (time.Duration, time.Duration, error) {
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "123")
+	}
+	var dialer net.Dialer
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	conn, err := dialer.DialContext(ctx, "udp", server)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer conn.Close()
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return 0, 0, err
+	}
+
+	// Version 4, client mode; the transmit timestamp comes back as the
+	// origin timestamp, which ties the reply to this request
+	request := make([]byte, 48)
+	request[0] = 4<<3 | 3
+	sent := time.Now()
+	putNTPTime(request[40:], sent)
+	if _, err := conn.Write(request); err != nil {
+		return 0, 0, err
+	}
+	reply := make([]byte, 48)
+	if _, err := conn.Read(reply); err != nil {
+		return 0, 0, err
+	}
+	received := time.Now()
+
+	switch {
+	case reply[0]&7 != 4:
+		return 0, 0, fmt.Errorf("unexpected NTP mode %d", reply[0]&7)
+	case reply[1] == 0:
+		return 0, 0, fmt.Errorf("NTP server sent kiss code %q", reply[12:16])
+	case !slices.Equal(reply[24:32], request[40:48]):
+		return 0, 0, fmt.Errorf("NTP reply does not match the request")
+	}
+	serverReceived, serverSent := ntpTime(reply[32:40]), ntpTime(reply[40:48])
+	offset := (serverReceived.Sub(sent) + serverSent.Sub(received)) / 2
+	rtt := received.Sub(sent) - serverSent.Sub(serverReceived)
+	return offset, rtt, nil
+}
+
+// putNTPTime writes t as a 64-bit NTP timestamp
+func putNTPTime(b []byte, t time.Time) {
+	binary.BigEndian.PutUint32(b, uint32(t.Unix()+ntpEpochOffset))
+	binary.BigEndian.PutUint32(b[4:], uint32((uint64(t.Nanosecond())<<32)/1e9))
+}
+
+// ntpTime reads a 64-bit NTP timestamp
+func ntpTime(b []byte) time.Time {
+	seconds := int64(binary.BigEndian.Uint32(b)) - ntpEpochOffset
+	nanos := (int64(binary.BigEndian.Uint32(b[4:])) * 1e9) >> 32
+	return time.Unix(seconds, nanos)
+}
+
+// addHealthEndpoint registers /health, which reports the status of each
+// dependency and an overall status: 200 while healthy or degraded, so load
+// balancers keep a degraded server in rotation, and 503 when unhealthy
+func addHealthEndpoint(mux *http.ServeMux, config *Config, rt *httpRuntime) {
+	var ntp *ntpChecker
+	if len(config.HealthNTPServers) > 0 {
+		ntp = newNTPChecker(config.HealthNTPServers, config.HealthNTPMaxOffset)
+	}
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		components := rt.healthComponents(r.Context(), config, ntp)
+		for name, c := range components {
+			if c.Status != healthOK {
+				slog.WarnContext(r.Context(), "Health check failed", "component", name, "status", c.Status, "error", c.Message)
+			}
+		}
+		status := rollupHealth(components)
+		health := map[string]any{
+			"status":     map[string]string{healthOK: "healthy", healthDegraded: "degraded", healthUnhealthy: "unhealthy"}[status],
+			"service":    config.ServerName,
+			"version":    config.ServerVersion,
+			"timestamp":  config.now().UTC().Format(time.RFC3339),
+			"components": components,
+		}
+
+		if config.HTTPCORSEnabled {
+			origin := r.Header.Get("Origin")
+			if origin != "" && isOriginAllowed(origin, config.HTTPCORSOrigins) {
+				setCORSHeaders(w, r, origin, config.HTTPCORSPolicy, false)
+			}
+		}
+
+		code := http.StatusOK
+		if status == healthUnhealthy {
+			code = http.StatusServiceUnavailable
+		}
+		writeJSONResponse(w, r, code, health)
+	})
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// pingFailingStore is a state store whose ping fails
+type pingFailingStore struct{ stateStore }
+
+func (pingFailingStore) ping(context.Context) error { return errors.New("connection refused") }
+
+func TestHealthEndpointComponents(t *testing.T) {
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	var available atomic.Bool
+	available.Store(true)
+	jwksServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !available.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{rsaJWK("k1", &key.PublicKey)}})
+	}))
+	defer jwksServer.Close()
+
+	config := &Config{
+		ServerName:      "TimeMCP",
+		AuthEnabled:     true,
+		AuthIssuer:      "test-issuer",
+		AuthAudience:    "test-audience",
+		AuthJWKSURL:     jwksServer.URL,
+		AuthJWKSRefresh: time.Hour,
+		Store:           "memory",
+	}
+	contextFunc := &reloadableContextFunc{}
+	fn, auth, err := createHTTPMiddleware(config)
+	if err != nil {
+		t.Fatalf("Failed to create HTTP middleware: %v", err)
+	}
+	contextFunc.store(fn, auth)
+	rt := &httpRuntime{contextFunc: contextFunc, drainer: newConnectionDrainer(), store: newMemoryStore()}
+
+	mux := http.NewServeMux()
+	addHealthEndpoint(mux, config, rt)
+	health := func() (int, string, map[string]componentHealth) {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest("GET", "/health", nil))
+		var body struct {
+			Status     string                     `json:"status"`
+			Components map[string]componentHealth `json:"components"`
+		}
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode health response: %v", err)
+		}
+		return rec.Code, body.Status, body.Components
+	}
+
+	code, status, components := health()
+	if code != http.StatusOK || status != "healthy" {
+		t.Errorf("Expected a healthy 200 but got %d %s: %v", code, status, components)
+	}
+	for _, name := range []string{"tzdata", "store", "jwks"} {
+		if components[name].Status != healthOK {
+			t.Errorf("Expected %s ok but got %+v", name, components[name])
+		}
+	}
+
+	// A failing store degrades the server but keeps it in rotation
+	rt.store = pingFailingStore{rt.store}
+	code, status, components = health()
+	if code != http.StatusOK || status != "degraded" || components["store"].Status != healthDegraded {
+		t.Errorf("Expected a degraded 200 but got %d %s: %v", code, status, components)
+	}
+
+	// A key set that cannot be refreshed is degraded while keys are cached
+	available.Store(false)
+	auth.jwks.mu.Lock()
+	auth.jwks.fetchedAt, auth.jwks.attemptedAt = time.Time{}, time.Time{}
+	auth.jwks.mu.Unlock()
+	if _, _, components = health(); components["jwks"].Status != healthDegraded {
+		t.Errorf("Expected jwks degraded after a failed refresh but got %+v", components["jwks"])
+	}
+
+	rt.drainer.draining.Store(true)
+	if code, status, _ := health(); code != http.StatusServiceUnavailable || status != "unhealthy" {
+		t.Errorf("Expected an unhealthy 503 while draining but got %d %s", code, status)
+	}
+}
+
+// fakeNTPServer answers SNTP requests with its clock ahead by skew
+func fakeNTPServer(t *testing.T, skew time.Duration) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	go func() {
+		buf := make([]byte, 48)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if n < 48 {
+				continue
+			}
+			reply := make([]byte, 48)
+			reply[0] = 4<<3 | 4
+			reply[1] = 2
+			copy(reply[24:32], buf[40:48])
+			now := time.Now().Add(skew)
+			putNTPTime(reply[32:], now)
+			putNTPTime(reply[40:], now)
+			conn.WriteTo(reply, addr)
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+func TestQueryNTP(t *testing.T) {
+	server := fakeNTPServer(t, 5*time.Second)
+	offset, rtt, err := queryNTP(context.Background(), server)
+	if err != nil {
+		t.Fatalf("Failed to query NTP: %v", err)
+	}
+	if offset < 4900*time.Millisecond || offset > 5100*time.Millisecond || rtt < 0 || rtt > time.Second {
+		t.Errorf("Expected an offset of about 5s but got %s (rtt %s)", offset, rtt)
+	}
+
+	checker := newNTPChecker([]string{server}, time.Second)
+	if got := checker.check(context.Background()); got.Status != healthDegraded {
+		t.Errorf("Expected a 5s offset to degrade the clock but got %+v", got)
+	}
+	checker = newNTPChecker([]string{fakeNTPServer(t, 0)}, time.Second)
+	if got := checker.check(context.Background()); got.Status != healthOK {
+		t.Errorf("Expected an accurate clock to be ok but got %+v", got)
+	}
+
+	// Results are cached, so a failing query is not seen until they expire
+	calls := 0
+	checker.query = func(context.Context, string) (time.Duration, time.Duration, error) {
+		calls++
+		return 0, 0, errors.New("timeout")
+	}
+	checker.check(context.Background())
+	if calls != 0 {
+		t.Errorf("Expected the cached result to be reused but got %d queries", calls)
+	}
+	checker.checkedAt = time.Time{}
+	if got := checker.check(context.Background()); got.Status != healthDegraded || calls != 1 {
+		t.Errorf("Expected no reachable server to degrade the clock but got %+v", got)
+	}
+}
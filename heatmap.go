@@ -0,0 +1,230 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// maxHeatmapTimezones caps the columns of a call time heat map
+const maxHeatmapTimezones = 12
+
+// Availability of a participant in one hour, from best to worst
+const (
+	statusOffice = "office"
+	statusAwake  = "awake"
+	statusAsleep = "asleep"
+)
+
+// heatmapSymbols are the markdown cells for each status
+var heatmapSymbols = map[string]string{statusOffice: "🟩", statusAwake: "🟨", statusAsleep: "🟥"}
+
+// callTimeHeatmap is the structured result of call_time_heatmap
+type callTimeHeatmap struct {
+	Date      string        `json:"date"`
+	Reference string        `json:"reference_timezone" jsonschema:"Timezone whose day the hours cover"`
+	Timezones []string      `json:"timezones"`
+	Hours     []heatmapHour `json:"hours"`
+	Best      []string      `json:"best" jsonschema:"Starts of the highest scoring hours, in RFC 3339"`
+	MaxScore  int           `json:"max_score" jsonschema:"Score of an hour in which everyone is in office"`
+}
+
+type heatmapHour struct {
+	Start   string        `json:"start" jsonschema:"Start of the hour in RFC 3339, in the reference timezone"`
+	Local   []heatmapCell `json:"local"`
+	Score   int           `json:"score" jsonschema:"2 per participant in office, 1 per participant awake"`
+	Summary string        `json:"summary" jsonschema:"everyone in office, everyone awake or someone asleep"`
+}
+
+type heatmapCell struct {
+	Timezone string `json:"timezone"`
+	Time     string `json:"time" jsonschema:"Local time at the start of the hour, HH:MM"`
+	Status   string `json:"status" jsonschema:"office, awake or asleep"`
+}
+
+// availability is when participants are in office and awake, in their own
+// timezones; the office is open Monday to Friday
+type availability struct {
+	office, awake dailyWindow
+}
+
+// status evaluates t in its own location
+func (a availability) status(t time.Time) string {
+	a.office.loc, a.awake.loc = t.Location(), t.Location()
+	weekday := t.Weekday()
+	switch {
+	case weekday != time.Saturday && weekday != time.Sunday && a.office.contains(t):
+		return statusOffice
+	case a.awake.contains(t):
+		return statusAwake
+	}
+	return statusAsleep
+}
+
+// buildHeatmap scores every hour of date in ref for participants in locs
+func buildHeatmap(date time.Time, ref *time.Location, locs []*time.Location, avail availability) callTimeHeatmap {
+	heatmap := callTimeHeatmap{
+		Date:      date.Format("2006-01-02"),
+		Reference: ref.String(),
+		MaxScore:  2 * len(locs),
+	}
+	for _, loc := range locs {
+		heatmap.Timezones = append(heatmap.Timezones, loc.String())
+	}
+	// Step in absolute hours so days with a DST change have 23 or 25 rows
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, ref)
+	end := time.Date(date.Year(), date.Month(), date.Day()+1, 0, 0, 0, 0, ref)
+	best := -1
+	for t := start; t.Before(end); t = t.Add(time.Hour) {
+		hour := heatmapHour{Start: t.Format(time.RFC3339)}
+		office, awake := 0, 0
+		for _, loc := range locs {
+			local := t.In(loc)
+			status := avail.status(local)
+			switch status {
+			case statusOffice:
+				office++
+				hour.Score += 2
+			case statusAwake:
+				awake++
+				hour.Score++
+			}
+			hour.Local = append(hour.Local, heatmapCell{Timezone: loc.String(), Time: local.Format("15:04"), Status: status})
+		}
+		switch {
+		case office == len(locs):
+			hour.Summary = "everyone in office"
+		case office+awake == len(locs):
+			hour.Summary = "everyone awake"
+		default:
+			hour.Summary = "someone asleep"
+		}
+		switch {
+		case hour.Score > best:
+			best = hour.Score
+			heatmap.Best = []string{hour.Start}
+		case hour.Score == best:
+			heatmap.Best = append(heatmap.Best, hour.Start)
+		}
+		heatmap.Hours = append(heatmap.Hours, hour)
+	}
+	return heatmap
+}
+
+// markdown renders the heat map as a table with one row per hour
+func (h callTimeHeatmap) markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Call times on %s (%s)\n\n", h.Date, h.Reference)
+	b.WriteString("| " + h.Reference)
+	for _, tz := range h.Timezones {
+		b.WriteString(" | " + tz)
+	}
+	b.WriteString(" | Score |\n|---")
+	for range h.Timezones {
+		b.WriteString("|---")
+	}
+	b.WriteString("|---|\n")
+	for _, hour := range h.Hours {
+		start, _ := time.Parse(time.RFC3339, hour.Start)
+		b.WriteString("| " + start.Format("15:04"))
+		for _, cell := range hour.Local {
+			fmt.Fprintf(&b, " | %s %s", heatmapSymbols[cell.Status], cell.Time)
+		}
+		fmt.Fprintf(&b, " | %d/%d |\n", hour.Score, h.MaxScore)
+	}
+	b.WriteString("\n🟩 in office, 🟨 awake, 🟥 asleep\n")
+	return b.String()
+}
+
+// addHeatmapTool registers call_time_heatmap
+func addHeatmapTool(mcpServer *server.MCPServer, config *Config) {
+	mcpServer.AddTool(
+		mcp.NewTool("call_time_heatmap",
+			mcp.WithDescription("Score every hour of a day for a call between people in several timezones: each participant is in office, awake or asleep at the start of the hour. Returns structured scores and a markdown heat map."),
+			mcp.WithArray("timezones",
+				mcp.Description("IANA timezones of the participants, e.g. [\"Europe/Warsaw\", \"America/New_York\", \"Asia/Tokyo\"]."),
+				mcp.WithStringItems(),
+				mcp.MinItems(1),
+				mcp.MaxItems(maxHeatmapTimezones),
+				mcp.Required(),
+			),
+			mcp.WithString("date",
+				mcp.Description("Day to score in YYYY-MM-DD format. Defaults to today in the reference timezone."),
+			),
+			mcp.WithString("reference_timezone",
+				mcp.Description("Timezone whose day the rows cover. Defaults to the first participant's timezone."),
+			),
+			mcp.WithString("office_hours",
+				mcp.Description("Local office hours on weekdays, HH:MM-HH:MM. Defaults to 09:00-17:00."),
+			),
+			mcp.WithString("awake_hours",
+				mcp.Description("Local waking hours, HH:MM-HH:MM. Defaults to 07:00-23:00."),
+			),
+			mcp.WithOutputSchema[callTimeHeatmap](),
+			mcp.WithTitleAnnotation("Call Time Heat Map"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		handleCallTimeHeatmap(config),
+	)
+}
+
+// handleCallTimeHeatmap returns a handler for the call_time_heatmap tool
+func handleCallTimeHeatmap(config *Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		names, err := request.RequireStringSlice("timezones")
+		if err != nil {
+			return toolError(errorInvalidArgument, err.Error()), nil
+		}
+		if len(names) == 0 || len(names) > maxHeatmapTimezones {
+			return toolError(errorInvalidArgument, fmt.Sprintf("Provide between 1 and %d timezones", maxHeatmapTimezones)), nil
+		}
+		locs := make([]*time.Location, 0, len(names))
+		for _, name := range names {
+			loc, err := loadLocationCached(strings.TrimSpace(name))
+			if err != nil {
+				return invalidTimezoneError("Invalid timezone", name), nil
+			}
+			locs = append(locs, loc)
+		}
+
+		ref := locs[0]
+		if refStr := request.GetString("reference_timezone", ""); refStr != "" {
+			if ref, err = loadTimezone(ctx, refStr, config); err != nil {
+				return invalidTimezoneError("Invalid reference timezone", refStr), nil
+			}
+		}
+
+		var avail availability
+		for _, hours := range []struct {
+			arg, fallback string
+			window        *dailyWindow
+		}{
+			{"office_hours", "09:00-17:00", &avail.office},
+			{"awake_hours", "07:00-23:00", &avail.awake},
+		} {
+			spec := request.GetString(hours.arg, hours.fallback)
+			start, end, err := parseWorkingHours(spec)
+			if err != nil {
+				return toolError(errorParse, fmt.Sprintf("Invalid %s: %s. Please use HH:MM-HH:MM.", hours.arg, spec)), nil
+			}
+			*hours.window = dailyWindow{start: start, end: end % (24 * time.Hour)}
+		}
+
+		date := config.now().In(ref)
+		if dateStr := request.GetString("date", ""); dateStr != "" {
+			if date, err = time.ParseInLocation("2006-01-02", dateStr, ref); err != nil {
+				return toolError(errorParse, fmt.Sprintf("Invalid date: %s. Please use YYYY-MM-DD format.", dateStr)), nil
+			}
+		}
+
+		heatmap := buildHeatmap(date, ref, locs, avail)
+		return mcp.NewToolResultStructured(heatmap, heatmap.markdown()), nil
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestCallTimeHeatmap(t *testing.T) {
+	handler := handleCallTimeHeatmap(&Config{DefaultTimezone: "UTC"})
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]any{
+		"timezones": []any{"Europe/Warsaw", "America/New_York"},
+		"date":      "2025-12-23",
+	}
+	result, err := handler(context.Background(), request)
+	if err != nil || result.IsError {
+		t.Fatalf("Failed to build heat map: %v %s", err, toolResultText(result))
+	}
+	heatmap, ok := result.StructuredContent.(callTimeHeatmap)
+	if !ok {
+		t.Fatalf("Failed to get structured content, got %T", result.StructuredContent)
+	}
+	if len(heatmap.Hours) != 24 || heatmap.MaxScore != 4 {
+		t.Fatalf("Expected 24 hours scored out of 4 but got %d out of %d", len(heatmap.Hours), heatmap.MaxScore)
+	}
+	// Warsaw 15:00-16:59 is New York 09:00-10:59
+	want := []string{"2025-12-23T15:00:00+01:00", "2025-12-23T16:00:00+01:00"}
+	if strings.Join(heatmap.Best, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected best hours %v but got %v", want, heatmap.Best)
+	}
+	hour := heatmap.Hours[15]
+	if hour.Summary != "everyone in office" || hour.Local[1].Time != "09:00" {
+		t.Errorf("Expected everyone in office at 09:00 New York but got %+v", hour)
+	}
+	if got := heatmap.Hours[6]; got.Summary != "someone asleep" || got.Score != 0 {
+		t.Errorf("Expected everyone asleep at 06:00 but got %+v", got)
+	}
+	text := toolResultText(result)
+	if !strings.Contains(text, "| 15:00 | 🟩 15:00 | 🟩 09:00 | 4/4 |") {
+		t.Errorf("Expected a markdown row for 15:00 but got %s", text)
+	}
+
+	// The day of a DST change has 23 hours, and weekends have no office hours
+	request.Params.Arguments = map[string]any{"timezones": []any{"Europe/Warsaw"}, "date": "2025-03-30"}
+	result, _ = handler(context.Background(), request)
+	heatmap = result.StructuredContent.(callTimeHeatmap)
+	if len(heatmap.Hours) != 23 {
+		t.Errorf("Expected 23 hours on 2025-03-30 but got %d", len(heatmap.Hours))
+	}
+	for _, hour := range heatmap.Hours {
+		if hour.Local[0].Status == statusOffice {
+			t.Errorf("Expected no office hours on a Sunday but got %+v", hour)
+		}
+	}
+}
+
+func TestCallTimeHeatmapErrors(t *testing.T) {
+	handler := handleCallTimeHeatmap(&Config{DefaultTimezone: "UTC"})
+	tests := []struct {
+		args map[string]any
+		code toolErrorCode
+	}{
+		{map[string]any{}, errorInvalidArgument},
+		{map[string]any{"timezones": []any{}}, errorInvalidArgument},
+		{map[string]any{"timezones": []any{"Europe/Warsw"}}, errorInvalidTimezone},
+		{map[string]any{"timezones": []any{"UTC"}, "reference_timezone": "Mars/Olympus"}, errorInvalidTimezone},
+		{map[string]any{"timezones": []any{"UTC"}, "office_hours": "17:00-09:00"}, errorParse},
+		{map[string]any{"timezones": []any{"UTC"}, "date": "23/12/2025"}, errorParse},
+	}
+	for _, tt := range tests {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = tt.args
+		result, err := handler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("Failed to call handler: %v", err)
+		}
+		if code := toolErrorCodeOf(result); code != tt.code {
+			t.Errorf("Expected %v to fail with %s but got %q: %s", tt.args, tt.code, code, toolResultText(result))
+		}
+	}
+}
@@ -2,19 +2,24 @@ package main
 
 import (
 	"context"
-	"encoding/json"
-	"log"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
 
 	"github.com/mark3labs/mcp-go/server"
+	"golang.org/x/crypto/acme/autocert"
 )
 
-func createHttpServerOptions(config *Config) ([]server.StreamableHTTPOption, error) {
+func createHttpServerOptions(config *Config, contextFunc *reloadableContextFunc, sessions *sessionRegistry) ([]server.StreamableHTTPOption, error) {
 	var opts []server.StreamableHTTPOption
 
 	if config.HTTPHeartbeat > 0 {
@@ -23,6 +28,8 @@ func createHttpServerOptions(config *Config) ([]server.StreamableHTTPOption, err
 
 	if config.HTTPStateless {
 		opts = append(opts, server.WithStateLess(true))
+	} else if sessions != nil {
+		opts = append(opts, server.WithSessionIdManager(sessions))
 	}
 
 	opts = append(opts, server.WithEndpointPath(config.HTTPPath))
@@ -31,27 +38,129 @@ func createHttpServerOptions(config *Config) ([]server.StreamableHTTPOption, err
 		opts = append(opts, server.WithSessionIdleTTL(config.HTTPSessionIdleTTL))
 	}
 
-	if config.HTTPCORSEnabled || config.AuthEnabled {
-		httpContextFunc, err := createHTTPMiddleware(config)
-		if err != nil {
-			return nil, err
-		}
-		opts = append(opts, server.WithHTTPContextFunc(httpContextFunc))
+	// Installed unconditionally so a reload can enable auth or mTLS identities
+	httpContextFunc, auth, err := createHTTPMiddleware(config)
+	if err != nil {
+		return nil, err
 	}
+	contextFunc.store(httpContextFunc, auth)
+	opts = append(opts, server.WithHTTPContextFunc(contextFunc.contextFunc))
 
 	return opts, nil
 }
 
-func createCustomHttpServer(httpServer http.Handler, config *Config) *http.Server {
-	return &http.Server{
+func createCustomHttpServer(handler http.Handler, config *Config) (*http.Server, error) {
+	srv := &http.Server{
 		Addr:         config.HTTPAddress,
-		Handler:      createCustomHTTPHandler(httpServer, config),
+		Handler:      handler,
 		ReadTimeout:  config.HTTPTimeout,
 		WriteTimeout: config.HTTPTimeout,
 	}
+
+	if tlsEnabled(config) {
+		var manager *autocert.Manager
+		if acmeEnabled(config) {
+			manager = newACMEManager(config)
+			if config.HTTPACMEHTTPAddress != "" {
+				startACMEChallengeServer(srv, manager, config.HTTPACMEHTTPAddress)
+			}
+			slog.Info("ACME certificates enabled", "domains", strings.Join(config.HTTPACMEDomains, ", "))
+		}
+
+		tlsConfig, err := newTLSConfig(config, manager)
+		if err != nil {
+			return nil, err
+		}
+		srv.TLSConfig = tlsConfig
+	}
+
+	return srv, nil
+}
+
+// startACMEChallengeServer answers ACME HTTP-01 challenges and redirects other
+// plain HTTP traffic to HTTPS. It is closed when the main server shuts down.
+func startACMEChallengeServer(main *http.Server, manager *autocert.Manager, addr string) {
+	challenge := &http.Server{
+		Addr:              addr,
+		Handler:           manager.HTTPHandler(nil),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	main.RegisterOnShutdown(func() {
+		challenge.Close()
+	})
+
+	go func() {
+		slog.Info("Starting ACME HTTP-01 challenge server", "address", addr)
+		if err := challenge.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			slog.Error("ACME challenge server failed", "error", err)
+		}
+	}()
+}
+
+// unixSocketPrefix marks a TIME_HTTP_ADDRESS that refers to a unix domain socket
+const unixSocketPrefix = "unix:"
+
+// unixSocketPath returns the socket path if the address uses the unix: scheme
+func unixSocketPath(address string) (string, bool) {
+	if !strings.HasPrefix(address, unixSocketPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(address, unixSocketPrefix), true
+}
+
+// newListener opens a TCP listener or, for unix: addresses, a unix domain socket
+// with the configured permissions
+func newListener(config *Config) (net.Listener, error) {
+	path, ok := unixSocketPath(config.HTTPAddress)
+	if !ok {
+		return net.Listen("tcp", config.HTTPAddress)
+	}
+	if path == "" {
+		return nil, fmt.Errorf("empty unix socket path in TIME_HTTP_ADDRESS")
+	}
+
+	// Remove a stale socket left behind by an unclean shutdown, but never one
+	// another process is still listening on
+	if info, err := os.Stat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+		conn, err := net.DialTimeout("unix", path, time.Second)
+		if err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("unix socket %s is in use by another process", path)
+		}
+		if !errors.Is(err, syscall.ECONNREFUSED) {
+			return nil, fmt.Errorf("failed to check socket %s: %w", path, err)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("failed to remove stale socket %s: %w", path, err)
+		}
+	}
+
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, config.HTTPSocketMode); err != nil {
+		ln.Close()
+		return nil, fmt.Errorf("failed to set socket permissions: %w", err)
+	}
+	return ln, nil
+}
+
+// serve serves plain HTTP or HTTPS on the listener depending on the server's TLS configuration
+func serve(server *http.Server, ln net.Listener) error {
+	if server.TLSConfig != nil {
+		// Certificates are already loaded into TLSConfig
+		return server.ServeTLS(ln, "", "")
+	}
+	return server.Serve(ln)
 }
 
-func handleGracefulShutdown(server *http.Server, config *Config) error {
+func handleGracefulShutdown(server *http.Server, config *Config, beforeShutdown func()) error {
+	ln, err := newListener(config)
+	if err != nil {
+		return err
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
@@ -64,9 +173,13 @@ func handleGracefulShutdown(server *http.Server, config *Config) error {
 
 	go func() {
 		defer wg.Done()
-		log.Printf("Starting TimeMCP HTTP server on %s\n", server.Addr)
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Printf("HTTP server failed: %v\n", err)
+		scheme := "HTTP"
+		if server.TLSConfig != nil {
+			scheme = "HTTPS"
+		}
+		slog.Info("Starting TimeMCP server", "scheme", scheme, "address", server.Addr)
+		if err := serve(server, ln); err != nil && err != http.ErrServerClosed {
+			slog.Error("HTTP server failed", "error", err)
 			errChan <- err
 			cancel()
 		}
@@ -74,81 +187,150 @@ func handleGracefulShutdown(server *http.Server, config *Config) error {
 
 	select {
 	case sig := <-sigChan:
-		log.Printf("Received signal %v, shutting down HTTP server...\n", sig)
-		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), config.HTTPTimeout)
-		defer shutdownCancel()
-		if err := server.Shutdown(shutdownCtx); err != nil {
-			log.Printf("HTTP server shutdown error: %v\n", err)
-			return err
-		}
-		wg.Wait()
-		log.Println("HTTP server stopped")
-		return nil
+		slog.Info("Received signal, shutting down HTTP server", "signal", sig.String())
+		return shutdownHTTPServer(server, config, beforeShutdown, &wg)
 	case err := <-errChan:
 		wg.Wait()
 		return err
 	case <-ctx.Done():
-		log.Println("Context cancelled, shutting down HTTP server...")
-		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), config.HTTPTimeout)
-		defer shutdownCancel()
-		if err := server.Shutdown(shutdownCtx); err != nil {
-			log.Printf("HTTP server shutdown error: %v\n", err)
-			return err
-		}
-		wg.Wait()
-		log.Println("HTTP server stopped")
-		return nil
+		slog.Info("Context cancelled, shutting down HTTP server")
+		return shutdownHTTPServer(server, config, beforeShutdown, &wg)
 	}
 }
 
-// startHTTPServer starts the HTTP transport server
-func startHTTPServer(mcpServer *server.MCPServer, config *Config) error {
-	opts, err := createHttpServerOptions(config)
-	if err != nil {
-		return err
+// shutdownHTTPServer drains sessions and waits up to the shutdown timeout for
+// in-flight requests to complete
+func shutdownHTTPServer(server *http.Server, config *Config, beforeShutdown func(), wg *sync.WaitGroup) error {
+	if beforeShutdown != nil {
+		beforeShutdown()
 	}
 
-	httpServer := server.NewStreamableHTTPServer(mcpServer, opts...)
-	customServer := createCustomHttpServer(httpServer, config)
-
-	return handleGracefulShutdown(customServer, config)
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), config.HTTPShutdownTimeout)
+	defer shutdownCancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Error("HTTP server shutdown error", "error", err)
+		return err
+	}
+	wg.Wait()
+	slog.Info("HTTP server stopped")
+	return nil
 }
 
-func createCustomHTTPHandler(mcpHandler http.Handler, config *Config) http.Handler {
-	mux := http.NewServeMux()
+// startHTTPServer starts the HTTP transport server, serving MCP over
+// streamable HTTP or, for the ws transport, WebSocket. CORS, rate limits,
+// security headers, IP filters and auth keys follow configuration reloads.
+func startHTTPServer(mcpServer *server.MCPServer, reloader *configReloader, stats *serverStats, store stateStore, sessions *sessionRegistry, transport string) error {
+	config := reloader.Load()
+	contextFunc := &reloadableContextFunc{}
+	opts, err := createHttpServerOptions(config, contextFunc, sessions)
+	if err != nil {
+		return err
+	}
 
-	addHealthEndpoint(mux, config)
-	addCORSHandler(mux, mcpHandler, config)
+	drainer := newConnectionDrainer()
+	var mcpHandler http.Handler = payloadLogHandler(server.NewStreamableHTTPServer(mcpServer, opts...), reloader.Load)
+	if transport == "ws" {
+		mcpHandler = webSocketHandler(mcpServer, reloader.Load, contextFunc, sessions)
+	}
+	mcpHandler = drainer.handler(mcpHandler)
+	accessLog, err := openAccessLog(config.HTTPAccessLogFile)
+	if err != nil {
+		return err
+	}
+	rt := &httpRuntime{mcpServer: mcpServer, contextFunc: contextFunc, drainer: drainer, stats: stats, store: store, accessLog: accessLog, sessions: sessions, limiters: newRateLimiters()}
+	handler := newReloadableHandler(createCustomHTTPHandler(mcpHandler, config, rt))
+	var rootHandler http.Handler = handler
+	if config.OTelEnabled {
+		rootHandler = tracingHandler(handler)
+	}
+	customServer, err := createCustomHttpServer(rootHandler, config)
+	if err != nil {
+		return err
+	}
 
-	return mux
+	reloader.onReload("HTTP authentication", func(config *Config) error {
+		httpContextFunc, auth, err := createHTTPMiddleware(config)
+		if err != nil {
+			return err
+		}
+		contextFunc.store(httpContextFunc, auth)
+		return nil
+	})
+	reloader.onReload("HTTP handlers", func(config *Config) error {
+		handler.store(createCustomHTTPHandler(mcpHandler, config, rt))
+		return nil
+	})
+
+	return handleGracefulShutdown(customServer, config, func() { drainer.begin(mcpServer, config.ServerName) })
 }
 
-func addHealthEndpoint(mux *http.ServeMux, config *Config) {
-	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		health := map[string]any{
-			"status":    "healthy",
-			"service":   "TimeMCP",
-			"version":   "1.0.0",
-			"timestamp": time.Now().UTC().Format(time.RFC3339),
-		}
+// httpRuntime is the server state shared by HTTP endpoints across config
+// reloads. Any field may be nil, in which case the features using it are
+// skipped.
+type httpRuntime struct {
+	mcpServer   *server.MCPServer
+	contextFunc *reloadableContextFunc
+	drainer     *connectionDrainer
+	stats       *serverStats
+	store       stateStore
+	accessLog   io.Writer
+	sessions    *sessionRegistry
+	limiters    *rateLimiters
+}
 
-		w.Header().Set("Content-Type", "application/json")
-		w.Header().Set("Cache-Control", "no-cache")
+func createCustomHTTPHandler(mcpHandler http.Handler, config *Config, rt *httpRuntime) http.Handler {
+	mux := http.NewServeMux()
 
-		if config.HTTPCORSEnabled {
-			origin := r.Header.Get("Origin")
-			if origin != "" && isOriginAllowed(origin, config.HTTPCORSOrigins) {
-				w.Header().Set("Access-Control-Allow-Origin", origin)
-				w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
-				w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-			}
+	// REST calls share the MCP endpoint's rate limits and CORS handling
+	if config.HTTPREST && rt != nil && rt.mcpServer != nil && rt.contextFunc != nil {
+		apiMux := http.NewServeMux()
+		apiMux.Handle(restPathPrefix, restHandler(config, rt))
+		apiMux.Handle("/", mcpHandler)
+		mcpHandler = apiMux
+	}
+	if config.HTTPRateLimitGlobal.Enabled() || config.HTTPRateLimitIP.Enabled() || config.HTTPRateLimitUser.Enabled() ||
+		config.Tenants.rateLimited() {
+		// Callers are identified by the auth middleware of the MCP context
+		// function, which follows reloads
+		var claims func(r *http.Request) (*Claims, *http.Request)
+		if config.AuthEnabled && rt != nil && rt.contextFunc != nil {
+			claims = authenticatedClaims(rt.contextFunc.auth)
 		}
-
-		if err := json.NewEncoder(w).Encode(health); err != nil {
-			log.Printf("Failed to encode health response: %v\n", err)
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		var limiters *rateLimiters
+		if rt != nil {
+			limiters = rt.limiters
 		}
-	})
+		mcpHandler = rateLimitHandler(mcpHandler, config, limiters, claims)
+	}
+
+	addHealthEndpoint(mux, config, rt)
+	addVersionEndpoint(mux, config)
+	addProbeEndpoints(mux, config, rt)
+	addStatsEndpoint(mux, config, rt)
+	addCapabilitiesEndpoint(mux, config, rt)
+	addSessionsEndpoint(mux, config, rt)
+	addDatasetsEndpoint(mux, config, rt)
+	addOpenAPIEndpoint(mux, config, rt)
+	addCORSHandler(mux, mcpHandler, config)
+
+	var handler http.Handler = mux
+	if config.HTTPSecurityHeaders {
+		handler = securityHeadersHandler(handler, config)
+	}
+	if config.HTTPCompression {
+		handler = compressHandler(handler)
+	}
+	if len(config.HTTPAllowCIDRs) > 0 || len(config.HTTPDenyCIDRs) > 0 {
+		handler = ipFilterHandler(handler, config.HTTPAllowCIDRs, config.HTTPDenyCIDRs)
+	}
+	// Logged after the real client IP is resolved so entries show it
+	if config.HTTPAccessLog != accessLogOff && rt != nil && rt.accessLog != nil {
+		handler = accessLogHandler(handler, config.HTTPAccessLog, rt.accessLog)
+	}
+	if len(config.HTTPTrustedProxies) > 0 {
+		handler = realIPHandler(handler, config.HTTPTrustedProxies)
+	}
+	return requestIDHandler(handler)
 }
 
 func addCORSHandler(mux *http.ServeMux, mcpHandler http.Handler, config *Config) {
@@ -160,13 +342,10 @@ func addCORSHandler(mux *http.ServeMux, mcpHandler http.Handler, config *Config)
 	handler := func(w http.ResponseWriter, r *http.Request) {
 		origin := r.Header.Get("Origin")
 		if origin != "" && isOriginAllowed(origin, config.HTTPCORSOrigins) {
-			w.Header().Set("Access-Control-Allow-Origin", origin)
-			w.Header().Set("Vary", "Origin")
-			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+			preflight := r.Method == "OPTIONS"
+			setCORSHeaders(w, r, origin, config.HTTPCORSPolicy, preflight)
 
-			if r.Method == "OPTIONS" {
-				w.Header().Set("Access-Control-Max-Age", "86400")
+			if preflight {
 				w.WriteHeader(http.StatusOK)
 				return
 			}
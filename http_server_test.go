@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHealthEndpoint_ServerIdentity(t *testing.T) {
+	t.Setenv("TIME_SERVER_NAME", "TimeMCP-eu")
+	t.Setenv("TIME_SERVER_VERSION", "2.3.0-canary")
+	config, err := NewConfig()
+	if err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+	if config.ServerInstructions != serverInstructions {
+		t.Errorf("Expected default instructions %q but got %q", serverInstructions, config.ServerInstructions)
+	}
+
+	mux := http.NewServeMux()
+	addHealthEndpoint(mux, config, nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest("GET", "/health", nil))
+
+	var health map[string]any
+	if err := json.NewDecoder(rec.Body).Decode(&health); err != nil {
+		t.Fatalf("Failed to decode health response: %v", err)
+	}
+	if health["service"] != "TimeMCP-eu" || health["version"] != "2.3.0-canary" {
+		t.Errorf("Expected configured identity but got service=%v version=%v", health["service"], health["version"])
+	}
+}
+
+func TestNewListener_UnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "timemcp.sock")
+	config := &Config{HTTPAddress: unixSocketPrefix + path, HTTPSocketMode: 0o600}
+
+	// A socket another server is listening on is left alone
+	live, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	if ln, err := newListener(config); err == nil {
+		ln.Close()
+		t.Fatal("Expected a socket in use to be refused")
+	}
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("Expected the live socket to keep accepting connections: %v", err)
+	}
+	conn.Close()
+	live.Close()
+
+	// A socket left behind by an unclean shutdown is replaced
+	stale, err := net.ListenUnix("unix", &net.UnixAddr{Name: path, Net: "unix"})
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	stale.SetUnlinkOnClose(false)
+	stale.Close()
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("Expected the stale socket file to remain: %v", err)
+	}
+	ln, err := newListener(config)
+	if err != nil {
+		t.Fatalf("Expected the stale socket to be replaced but got %v", err)
+	}
+	defer ln.Close()
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("Expected a socket with mode 0600 but got %v %v", info, err)
+	}
+}
@@ -0,0 +1,809 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// maxICalSize caps the .ics input accepted by parse_ical
+	maxICalSize = 256 << 10
+	// maxICalOccurrences caps the occurrences returned for one call
+	maxICalOccurrences = 500
+	// maxICalWindowDays caps the expansion window
+	maxICalWindowDays = 366
+	// defaultICalWindowDays is the window used when window_end is omitted
+	defaultICalWindowDays = 30
+	// maxRecurrencePeriods bounds RRULE expansion however sparse the rule is
+	maxRecurrencePeriods = 100000
+)
+
+// windowsTimezones maps the Windows zone names Outlook and Exchange put in
+// TZID to IANA names, following the CLDR windowsZones "001" territory
+var windowsTimezones = map[string]string{
+	"GMT Standard Time":              "Europe/London",
+	"Greenwich Standard Time":        "Atlantic/Reykjavik",
+	"W. Europe Standard Time":        "Europe/Berlin",
+	"Romance Standard Time":          "Europe/Paris",
+	"Central Europe Standard Time":   "Europe/Budapest",
+	"Central European Standard Time": "Europe/Warsaw",
+	"E. Europe Standard Time":        "Europe/Chisinau",
+	"FLE Standard Time":              "Europe/Kiev",
+	"GTB Standard Time":              "Europe/Bucharest",
+	"Russian Standard Time":          "Europe/Moscow",
+	"Israel Standard Time":           "Asia/Jerusalem",
+	"South Africa Standard Time":     "Africa/Johannesburg",
+	"Arabian Standard Time":          "Asia/Dubai",
+	"India Standard Time":            "Asia/Calcutta",
+	"China Standard Time":            "Asia/Shanghai",
+	"Singapore Standard Time":        "Asia/Singapore",
+	"Tokyo Standard Time":            "Asia/Tokyo",
+	"Korea Standard Time":            "Asia/Seoul",
+	"AUS Eastern Standard Time":      "Australia/Sydney",
+	"New Zealand Standard Time":      "Pacific/Auckland",
+	"Eastern Standard Time":          "America/New_York",
+	"Central Standard Time":          "America/Chicago",
+	"Mountain Standard Time":         "America/Denver",
+	"US Mountain Standard Time":      "America/Phoenix",
+	"Pacific Standard Time":          "America/Los_Angeles",
+	"Alaskan Standard Time":          "America/Anchorage",
+	"Hawaiian Standard Time":         "Pacific/Honolulu",
+	"Atlantic Standard Time":         "America/Halifax",
+	"E. South America Standard Time": "America/Sao_Paulo",
+}
+
+// icalProperty is one unfolded content line: NAME;PARAM=VALUE:value
+type icalProperty struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// icalEvent is a VEVENT with the properties parse_ical uses
+type icalEvent struct {
+	UID      string
+	Summary  string
+	Location string
+	Start    time.Time
+	Duration time.Duration
+	AllDay   bool
+	// Timezone is the resolved IANA name, "UTC" or "floating"
+	Timezone     string
+	Rule         *recurrenceRule
+	ExDates      []time.Time
+	RDates       []time.Time
+	RecurrenceID time.Time
+}
+
+// parseICal extracts the VEVENTs of an iCalendar document. Floating times and
+// all-day dates are read in floating, since they have no zone of their own.
+func parseICal(data string, floating *time.Location) ([]icalEvent, error) {
+	var events []icalEvent
+	var stack []string
+	var event *icalEvent
+	var hasEnd bool
+	var end time.Time
+	var rrule string
+
+	for i, line := range unfoldICal(data) {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		prop, err := parseICalLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("content line %d: %w", i+1, err)
+		}
+
+		switch prop.name {
+		case "BEGIN":
+			component := strings.ToUpper(prop.value)
+			stack = append(stack, component)
+			if component == "VEVENT" {
+				event, hasEnd, end, rrule = &icalEvent{}, false, time.Time{}, ""
+			}
+			continue
+		case "END":
+			component := strings.ToUpper(prop.value)
+			if len(stack) == 0 || stack[len(stack)-1] != component {
+				return nil, fmt.Errorf("content line %d: unexpected END:%s", i+1, prop.value)
+			}
+			stack = stack[:len(stack)-1]
+			if component == "VEVENT" {
+				if err := event.finish(hasEnd, end, rrule); err != nil {
+					return nil, err
+				}
+				events = append(events, *event)
+				event = nil
+			}
+			continue
+		}
+		// Properties of nested components such as VALARM are not the event's
+		if event == nil || stack[len(stack)-1] != "VEVENT" {
+			continue
+		}
+
+		switch prop.name {
+		case "UID":
+			event.UID = prop.value
+		case "SUMMARY":
+			event.Summary = unescapeICalText(prop.value)
+		case "LOCATION":
+			event.Location = unescapeICalText(prop.value)
+		case "DTSTART":
+			times, allDay, tz, err := parseICalTimes(prop, floating)
+			if err != nil {
+				return nil, fmt.Errorf("event %s: DTSTART: %w", event.UID, err)
+			}
+			event.Start, event.AllDay, event.Timezone = times[0], allDay, tz
+		case "DTEND":
+			times, _, _, err := parseICalTimes(prop, floating)
+			if err != nil {
+				return nil, fmt.Errorf("event %s: DTEND: %w", event.UID, err)
+			}
+			hasEnd, end = true, times[0]
+		case "DURATION":
+			d, err := parseICalDuration(prop.value)
+			if err != nil {
+				return nil, fmt.Errorf("event %s: DURATION: %w", event.UID, err)
+			}
+			event.Duration = d
+		case "RRULE":
+			rrule = prop.value
+		case "EXDATE", "RDATE":
+			times, _, _, err := parseICalTimes(prop, floating)
+			if err != nil {
+				return nil, fmt.Errorf("event %s: %s: %w", event.UID, prop.name, err)
+			}
+			if prop.name == "EXDATE" {
+				event.ExDates = append(event.ExDates, times...)
+			} else {
+				event.RDates = append(event.RDates, times...)
+			}
+		case "RECURRENCE-ID":
+			times, _, _, err := parseICalTimes(prop, floating)
+			if err != nil {
+				return nil, fmt.Errorf("event %s: RECURRENCE-ID: %w", event.UID, err)
+			}
+			event.RecurrenceID = times[0]
+		}
+	}
+	if len(stack) > 0 {
+		return nil, fmt.Errorf("missing END:%s", stack[len(stack)-1])
+	}
+	return events, nil
+}
+
+// finish validates the event once END:VEVENT is reached and resolves the
+// duration and recurrence rule, which depend on DTSTART
+func (e *icalEvent) finish(hasEnd bool, end time.Time, rrule string) error {
+	if e.Start.IsZero() {
+		return fmt.Errorf("event %s has no DTSTART", e.UID)
+	}
+	if hasEnd {
+		if end.Before(e.Start) {
+			return fmt.Errorf("event %s ends before it starts", e.UID)
+		}
+		e.Duration = end.Sub(e.Start)
+	} else if e.Duration == 0 && e.AllDay {
+		e.Duration = 24 * time.Hour
+	}
+	if rrule != "" {
+		rule, err := parseRecurrenceRule(rrule, e.Start.Location())
+		if err != nil {
+			return fmt.Errorf("event %s: RRULE: %w", e.UID, err)
+		}
+		e.Rule = rule
+	}
+	return nil
+}
+
+// unfoldICal splits the document into lines, joining continuation lines that
+// start with a space or tab (RFC 5545 section 3.1)
+func unfoldICal(data string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// parseICalLine splits a content line into name, parameters and value;
+// quoted parameter values may contain ':' and ';'
+func parseICalLine(line string) (icalProperty, error) {
+	var parts []string
+	inQuote, start := false, 0
+	for i, r := range line {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+		case r == ';' && !inQuote:
+			parts = append(parts, line[start:i])
+			start = i + 1
+		case r == ':' && !inQuote:
+			parts = append(parts, line[start:i])
+			prop := icalProperty{name: strings.ToUpper(parts[0]), params: make(map[string]string), value: line[i+1:]}
+			for _, param := range parts[1:] {
+				key, value, _ := strings.Cut(param, "=")
+				prop.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+			}
+			return prop, nil
+		}
+	}
+	return icalProperty{}, fmt.Errorf("invalid content line %q", line)
+}
+
+// unescapeICalText decodes the backslash escapes of TEXT values
+func unescapeICalText(s string) string {
+	return strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(s)
+}
+
+// resolveICalTimezone maps a TZID to a location: IANA names (optionally with
+// the leading "/" some producers add) or common Windows zone names
+func resolveICalTimezone(tzid string) (*time.Location, error) {
+	if name, ok := windowsTimezones[tzid]; ok {
+		return loadLocationCached(name)
+	}
+	if name := strings.TrimPrefix(tzid, "/"); name != "" {
+		if loc, err := loadLocationCached(name); err == nil {
+			return loc, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown timezone %q in TZID", tzid)
+}
+
+// parseICalTimes parses a DATE or DATE-TIME value, or a comma-separated list
+// of them, and reports whether they are dates and the zone they are in
+func parseICalTimes(prop icalProperty, floating *time.Location) ([]time.Time, bool, string, error) {
+	loc, tz := floating, "floating"
+	if tzid := prop.params["TZID"]; tzid != "" {
+		var err error
+		if loc, err = resolveICalTimezone(tzid); err != nil {
+			return nil, false, "", err
+		}
+		tz = loc.String()
+	}
+
+	var times []time.Time
+	allDay := strings.EqualFold(prop.params["VALUE"], "DATE")
+	for _, value := range strings.Split(prop.value, ",") {
+		var t time.Time
+		var err error
+		switch {
+		case allDay || len(value) == 8:
+			allDay = true
+			t, err = time.ParseInLocation("20060102", value, floating)
+			tz = "floating"
+		case strings.HasSuffix(value, "Z"):
+			t, err = time.Parse("20060102T150405Z", value)
+			tz = "UTC"
+		default:
+			t, err = time.ParseInLocation("20060102T150405", value, loc)
+		}
+		if err != nil {
+			return nil, false, "", fmt.Errorf("invalid date-time %q", value)
+		}
+		times = append(times, t)
+	}
+	return times, allDay, tz, nil
+}
+
+// parseICalDuration parses an RFC 5545 duration such as PT1H30M, P1D or -P1W
+func parseICalDuration(s string) (time.Duration, error) {
+	str, sign := s, time.Duration(1)
+	if strings.HasPrefix(str, "-") {
+		str, sign = str[1:], -1
+	}
+	str = strings.TrimPrefix(str, "+")
+	if !strings.HasPrefix(str, "P") || len(str) < 3 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+
+	units := map[byte]time.Duration{'W': 7 * 24 * time.Hour, 'D': 24 * time.Hour, 'H': time.Hour, 'M': time.Minute, 'S': time.Second}
+	var total time.Duration
+	inTime, num := false, ""
+	for i := 1; i < len(str); i++ {
+		c := str[i]
+		switch {
+		case c == 'T':
+			inTime = true
+		case c >= '0' && c <= '9':
+			num += string(c)
+		default:
+			unit, ok := units[c]
+			// M is minutes and only valid after T; W and D only before it
+			if !ok || num == "" || (c == 'M' || c == 'H' || c == 'S') != inTime {
+				return 0, fmt.Errorf("invalid duration %q", s)
+			}
+			// Durations beyond time.Duration's ~292 years would wrap around
+			n, err := strconv.ParseInt(num, 10, 64)
+			if err != nil || time.Duration(n) > (math.MaxInt64-total)/unit {
+				return 0, fmt.Errorf("duration %q out of range", s)
+			}
+			total += time.Duration(n) * unit
+			num = ""
+		}
+	}
+	if num != "" {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return sign * total, nil
+}
+
+// weekdayNum is a BYDAY entry such as MO, 2TU or -1FR; n is 0 for every week
+type weekdayNum struct {
+	n   int
+	day time.Weekday
+}
+
+// recurrenceRule is the supported subset of an RRULE: FREQ DAILY, WEEKLY,
+// MONTHLY or YEARLY with INTERVAL, COUNT, UNTIL, BYDAY, BYMONTHDAY, BYMONTH,
+// BYSETPOS and WKST
+type recurrenceRule struct {
+	Freq       string
+	Interval   int
+	Count      int
+	Until      time.Time
+	ByDay      []weekdayNum
+	ByMonthDay []int
+	ByMonth    []time.Month
+	BySetPos   []int
+	WeekStart  time.Weekday
+}
+
+var icalWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// parseRecurrenceRule parses an RRULE value; UNTIL without a zone is read in loc
+func parseRecurrenceRule(value string, loc *time.Location) (*recurrenceRule, error) {
+	rule := &recurrenceRule{Interval: 1, WeekStart: time.Monday}
+	for _, part := range strings.Split(value, ";") {
+		key, val, _ := strings.Cut(part, "=")
+		key = strings.ToUpper(key)
+		var err error
+		switch key {
+		case "FREQ":
+			rule.Freq = strings.ToUpper(val)
+			if !slices.Contains([]string{"DAILY", "WEEKLY", "MONTHLY", "YEARLY"}, rule.Freq) {
+				return nil, fmt.Errorf("unsupported FREQ %s", val)
+			}
+		case "INTERVAL":
+			rule.Interval, err = strconv.Atoi(val)
+			if err == nil && rule.Interval < 1 {
+				err = fmt.Errorf("must be positive")
+			}
+		case "COUNT":
+			rule.Count, err = strconv.Atoi(val)
+			if err == nil && rule.Count < 1 {
+				err = fmt.Errorf("must be positive")
+			}
+		case "UNTIL":
+			switch {
+			case len(val) == 8:
+				// A date UNTIL includes that whole day
+				rule.Until, err = time.ParseInLocation("20060102", val, loc)
+				rule.Until = rule.Until.AddDate(0, 0, 1).Add(-time.Second)
+			case strings.HasSuffix(val, "Z"):
+				rule.Until, err = time.Parse("20060102T150405Z", val)
+			default:
+				rule.Until, err = time.ParseInLocation("20060102T150405", val, loc)
+			}
+		case "BYDAY":
+			for _, entry := range strings.Split(val, ",") {
+				entry = strings.ToUpper(entry)
+				if len(entry) < 2 {
+					return nil, fmt.Errorf("invalid BYDAY %q", entry)
+				}
+				day, ok := icalWeekdays[entry[len(entry)-2:]]
+				n := 0
+				if prefix := entry[:len(entry)-2]; prefix != "" {
+					n, err = strconv.Atoi(prefix)
+				}
+				if !ok || err != nil || n < -53 || n > 53 {
+					return nil, fmt.Errorf("invalid BYDAY %q", entry)
+				}
+				rule.ByDay = append(rule.ByDay, weekdayNum{n, day})
+			}
+		case "BYMONTHDAY", "BYMONTH", "BYSETPOS":
+			for _, entry := range strings.Split(val, ",") {
+				n, convErr := strconv.Atoi(entry)
+				if convErr != nil || n == 0 {
+					return nil, fmt.Errorf("invalid %s %q", key, entry)
+				}
+				switch {
+				case key == "BYMONTHDAY" && n >= -31 && n <= 31:
+					rule.ByMonthDay = append(rule.ByMonthDay, n)
+				case key == "BYMONTH" && n >= 1 && n <= 12:
+					rule.ByMonth = append(rule.ByMonth, time.Month(n))
+				case key == "BYSETPOS" && n >= -366 && n <= 366:
+					rule.BySetPos = append(rule.BySetPos, n)
+				default:
+					return nil, fmt.Errorf("invalid %s %q", key, entry)
+				}
+			}
+		case "WKST":
+			day, ok := icalWeekdays[strings.ToUpper(val)]
+			if !ok {
+				return nil, fmt.Errorf("invalid WKST %q", val)
+			}
+			rule.WeekStart = day
+		default:
+			return nil, fmt.Errorf("unsupported rule part %s", key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %v", key, val, err)
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, fmt.Errorf("FREQ is required")
+	}
+	if rule.Count > 0 && !rule.Until.IsZero() {
+		return nil, fmt.Errorf("COUNT and UNTIL are mutually exclusive")
+	}
+	for _, wd := range rule.ByDay {
+		if wd.n != 0 && rule.Freq != "MONTHLY" && rule.Freq != "YEARLY" {
+			return nil, fmt.Errorf("numbered BYDAY requires FREQ=MONTHLY or YEARLY")
+		}
+	}
+	if rule.Freq == "YEARLY" && len(rule.ByDay) > 0 && len(rule.ByMonth) == 0 {
+		return nil, fmt.Errorf("BYDAY with FREQ=YEARLY requires BYMONTH")
+	}
+	return rule, nil
+}
+
+// expand calls yield for each occurrence from start in order until yield
+// returns false or the rule ends
+func (r *recurrenceRule) expand(start time.Time, yield func(time.Time) bool) {
+	loc := start.Location()
+	hour, minute, sec := start.Clock()
+	year, month, day := start.Date()
+	count := 0
+
+	for period := 0; period < maxRecurrencePeriods; period++ {
+		var days []time.Time // dates at midnight UTC
+		step := period * r.Interval
+		switch r.Freq {
+		case "DAILY":
+			d := time.Date(year, month, day+step, 0, 0, 0, 0, time.UTC)
+			if r.matchesDay(d) {
+				days = append(days, d)
+			}
+		case "WEEKLY":
+			offset := (int(start.Weekday()) - int(r.WeekStart) + 7) % 7
+			weekStart := time.Date(year, month, day-offset+7*step, 0, 0, 0, 0, time.UTC)
+			for i := 0; i < 7; i++ {
+				d := weekStart.AddDate(0, 0, i)
+				if r.matchesWeekday(d, start.Weekday()) && (len(r.ByMonth) == 0 || slices.Contains(r.ByMonth, d.Month())) {
+					days = append(days, d)
+				}
+			}
+		case "MONTHLY":
+			first := time.Date(year, month+time.Month(step), 1, 0, 0, 0, 0, time.UTC)
+			if len(r.ByMonth) == 0 || slices.Contains(r.ByMonth, first.Month()) {
+				days = r.monthDays(first.Year(), first.Month(), day)
+			}
+		case "YEARLY":
+			months := r.ByMonth
+			if len(months) == 0 {
+				months = []time.Month{month}
+			}
+			for _, m := range months {
+				days = append(days, r.monthDays(year+step, m, day)...)
+			}
+		}
+		sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+		days = slices.CompactFunc(days, time.Time.Equal)
+		days = r.applySetPos(days)
+
+		for _, d := range days {
+			t := time.Date(d.Year(), d.Month(), d.Day(), hour, minute, sec, 0, loc)
+			if t.Before(start) {
+				continue
+			}
+			if !r.Until.IsZero() && t.After(r.Until) {
+				return
+			}
+			count++
+			if !yield(t) || (r.Count > 0 && count >= r.Count) {
+				return
+			}
+		}
+	}
+}
+
+// matchesDay applies the BYMONTH, BYMONTHDAY and BYDAY filters of a DAILY rule
+func (r *recurrenceRule) matchesDay(d time.Time) bool {
+	if len(r.ByMonth) > 0 && !slices.Contains(r.ByMonth, d.Month()) {
+		return false
+	}
+	if len(r.ByMonthDay) > 0 && !slices.Contains(r.monthDayNumbers(d.Year(), d.Month()), d.Day()) {
+		return false
+	}
+	return len(r.ByDay) == 0 || r.matchesWeekday(d, d.Weekday())
+}
+
+// matchesWeekday reports whether d falls on a BYDAY weekday, or on fallback
+// when the rule has no BYDAY
+func (r *recurrenceRule) matchesWeekday(d time.Time, fallback time.Weekday) bool {
+	if len(r.ByDay) == 0 {
+		return d.Weekday() == fallback
+	}
+	return slices.ContainsFunc(r.ByDay, func(wd weekdayNum) bool { return wd.day == d.Weekday() })
+}
+
+// monthDayNumbers resolves BYMONTHDAY, including negative days, in a month
+func (r *recurrenceRule) monthDayNumbers(year int, month time.Month) []int {
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	var days []int
+	for _, n := range r.ByMonthDay {
+		if n < 0 {
+			n = last + n + 1
+		}
+		if n >= 1 && n <= last {
+			days = append(days, n)
+		}
+	}
+	return days
+}
+
+// monthDays lists the dates a MONTHLY or YEARLY rule selects in one month;
+// without BYMONTHDAY or BYDAY that is the day of month of DTSTART
+func (r *recurrenceRule) monthDays(year int, month time.Month, startDay int) []time.Time {
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	date := func(d int) time.Time { return time.Date(year, month, d, 0, 0, 0, 0, time.UTC) }
+
+	var days []time.Time
+	switch {
+	case len(r.ByMonthDay) > 0:
+		for _, d := range r.monthDayNumbers(year, month) {
+			if len(r.ByDay) == 0 || r.matchesWeekday(date(d), 0) {
+				days = append(days, date(d))
+			}
+		}
+	case len(r.ByDay) > 0:
+		for _, wd := range r.ByDay {
+			if wd.n != 0 {
+				if d, ok := nthWeekday(year, month, wd.day, wd.n); ok {
+					days = append(days, date(d))
+				}
+				continue
+			}
+			for d := 1; d <= last; d++ {
+				if date(d).Weekday() == wd.day {
+					days = append(days, date(d))
+				}
+			}
+		}
+	case startDay <= last:
+		days = append(days, date(startDay))
+	}
+	return days
+}
+
+// applySetPos keeps the BYSETPOS positions of one period's sorted dates
+func (r *recurrenceRule) applySetPos(days []time.Time) []time.Time {
+	if len(r.BySetPos) == 0 {
+		return days
+	}
+	var selected []time.Time
+	for _, pos := range r.BySetPos {
+		i := pos - 1
+		if pos < 0 {
+			i = len(days) + pos
+		}
+		if i >= 0 && i < len(days) {
+			selected = append(selected, days[i])
+		}
+	}
+	sort.Slice(selected, func(i, j int) bool { return selected[i].Before(selected[j]) })
+	return slices.CompactFunc(selected, time.Time.Equal)
+}
+
+// icalOccurrence is one event occurrence in a parse_ical result
+type icalOccurrence struct {
+	UID              string `json:"uid,omitempty"`
+	Summary          string `json:"summary"`
+	Location         string `json:"location,omitempty"`
+	Start            string `json:"start" jsonschema:"Start in the target timezone (RFC 3339), or YYYY-MM-DD for all-day events"`
+	End              string `json:"end" jsonschema:"End in the target timezone (RFC 3339), or the exclusive end date for all-day events"`
+	AllDay           bool   `json:"all_day"`
+	OriginalStart    string `json:"original_start" jsonschema:"Start as written in the invite"`
+	OriginalTimezone string `json:"original_timezone" jsonschema:"IANA timezone of the invite, UTC, or floating for times without a zone"`
+	Recurring        bool   `json:"recurring"`
+}
+
+// icalParseResult is the structured result of parse_ical
+type icalParseResult struct {
+	TargetTimezone string           `json:"target_timezone"`
+	WindowStart    string           `json:"window_start" jsonschema:"First day of the window (YYYY-MM-DD)"`
+	WindowEnd      string           `json:"window_end" jsonschema:"Last day of the window (YYYY-MM-DD)"`
+	Occurrences    []icalOccurrence `json:"occurrences"`
+	Truncated      bool             `json:"truncated" jsonschema:"Whether occurrences were left out because the limit was reached"`
+}
+
+// icalOccurrences expands the events into occurrences overlapping the window
+// [from, to), sorted by start. Overridden instances (RECURRENCE-ID) replace
+// the ones their master event would generate.
+func icalOccurrences(events []icalEvent, from, to time.Time) []icalEvent {
+	overridden := make(map[string]bool)
+	for _, e := range events {
+		if !e.RecurrenceID.IsZero() {
+			overridden[e.UID+"/"+e.RecurrenceID.UTC().String()] = true
+		}
+	}
+
+	var occurrences []icalEvent
+	for _, e := range events {
+		add := func(start time.Time) {
+			if overridden[e.UID+"/"+start.UTC().String()] && e.RecurrenceID.IsZero() {
+				return
+			}
+			if slices.ContainsFunc(e.ExDates, start.Equal) {
+				return
+			}
+			if start.Before(to) && (start.Add(e.Duration).After(from) || !start.Before(from)) {
+				occ := e
+				occ.Start = start
+				occurrences = append(occurrences, occ)
+			}
+		}
+
+		if e.Rule == nil || !e.RecurrenceID.IsZero() {
+			add(e.Start)
+		} else {
+			// DTSTART is always an occurrence, even when the rule would skip it
+			add(e.Start)
+			e.Rule.expand(e.Start, func(t time.Time) bool {
+				if !t.Before(to) {
+					return false
+				}
+				if !t.Equal(e.Start) {
+					add(t)
+				}
+				return true
+			})
+		}
+		for _, rdate := range e.RDates {
+			add(rdate)
+		}
+	}
+	sort.SliceStable(occurrences, func(i, j int) bool { return occurrences[i].Start.Before(occurrences[j].Start) })
+	return occurrences
+}
+
+func addICalTools(mcpServer *server.MCPServer, config *Config) {
+	mcpServer.AddTool(
+		mcp.NewTool("parse_ical",
+			mcp.WithDescription("Parse an iCalendar (.ics) snippet such as a meeting invite, expand recurring events over a window, and convert the occurrences into a target timezone. Supports TZID (IANA and common Windows names), UTC and floating times, all-day events, DURATION, RRULE (DAILY/WEEKLY/MONTHLY/YEARLY with INTERVAL, COUNT, UNTIL, BYDAY, BYMONTHDAY, BYMONTH, BYSETPOS), EXDATE, RDATE and RECURRENCE-ID overrides."),
+			mcp.WithString("ics",
+				mcp.Description("The iCalendar data, from BEGIN:VCALENDAR or just one or more VEVENT blocks."),
+				mcp.Required(),
+			),
+			mcp.WithString("target_timezone",
+				mcp.Description("Timezone to convert occurrences to. Defaults to system timezone if not provided."),
+				mcp.DefaultString(""),
+			),
+			mcp.WithString("window_start",
+				mcp.Description("First day of the expansion window in YYYY-MM-DD format, in the target timezone. Defaults to today."),
+				mcp.DefaultString(""),
+			),
+			mcp.WithString("window_end",
+				mcp.Description(fmt.Sprintf("Last day of the expansion window in YYYY-MM-DD format, inclusive. Defaults to %d days after window_start; at most %d days.", defaultICalWindowDays, maxICalWindowDays)),
+				mcp.DefaultString(""),
+			),
+			mcp.WithOutputSchema[icalParseResult](),
+			mcp.WithTitleAnnotation("Parse iCalendar Events"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		handleParseICal(config),
+	)
+}
+
+// handleParseICal returns a handler for the parse_ical tool
+func handleParseICal(config *Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ics, err := request.RequireString("ics")
+		if err != nil {
+			return toolError(errorInvalidArgument, err.Error()), nil
+		}
+		if len(ics) > maxICalSize {
+			return toolError(errorInvalidArgument, fmt.Sprintf("iCalendar data exceeds %d bytes", maxICalSize)), nil
+		}
+
+		targetTimezoneStr := request.GetString("target_timezone", "")
+		targetLoc, err := loadTimezone(ctx, targetTimezoneStr, config)
+		if err != nil {
+			return invalidTimezoneError("Invalid target timezone", targetTimezoneStr), nil
+		}
+
+		now := config.now().In(targetLoc)
+		from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, targetLoc)
+		if str := request.GetString("window_start", ""); str != "" {
+			if from, err = time.ParseInLocation("2006-01-02", str, targetLoc); err != nil {
+				return toolError(errorParse, fmt.Sprintf("Invalid window_start: %s. Please provide date in YYYY-MM-DD format.", str)), nil
+			}
+		}
+		to := from.AddDate(0, 0, defaultICalWindowDays)
+		if str := request.GetString("window_end", ""); str != "" {
+			last, err := time.ParseInLocation("2006-01-02", str, targetLoc)
+			if err != nil {
+				return toolError(errorParse, fmt.Sprintf("Invalid window_end: %s. Please provide date in YYYY-MM-DD format.", str)), nil
+			}
+			to = last.AddDate(0, 0, 1)
+		}
+		if !to.After(from) {
+			return toolError(errorInvalidArgument, "window_end must not be before window_start"), nil
+		}
+		if to.After(from.AddDate(0, 0, maxICalWindowDays)) {
+			return toolError(errorInvalidArgument, fmt.Sprintf("The window may span at most %d days", maxICalWindowDays)), nil
+		}
+
+		events, err := parseICal(ics, targetLoc)
+		if err != nil {
+			return toolError(errorParse, fmt.Sprintf("Failed to parse iCalendar data: %v", err)), nil
+		}
+
+		result := icalParseResult{
+			TargetTimezone: targetLoc.String(),
+			WindowStart:    from.Format("2006-01-02"),
+			WindowEnd:      to.AddDate(0, 0, -1).Format("2006-01-02"),
+			Occurrences:    []icalOccurrence{},
+		}
+		occurrences := icalOccurrences(events, from, to)
+		if len(occurrences) > maxICalOccurrences {
+			occurrences, result.Truncated = occurrences[:maxICalOccurrences], true
+		}
+		for _, occ := range occurrences {
+			result.Occurrences = append(result.Occurrences, newICalOccurrence(occ, targetLoc))
+		}
+
+		data, err := json.Marshal(result)
+		if err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to encode events: %v", err)), nil
+		}
+		return mcp.NewToolResultStructured(result, string(data)), nil
+	}
+}
+
+func newICalOccurrence(e icalEvent, target *time.Location) icalOccurrence {
+	occ := icalOccurrence{
+		UID:              e.UID,
+		Summary:          e.Summary,
+		Location:         e.Location,
+		AllDay:           e.AllDay,
+		OriginalTimezone: e.Timezone,
+		Recurring:        e.Rule != nil || !e.RecurrenceID.IsZero() || len(e.RDates) > 0,
+	}
+	if e.AllDay {
+		// Dates are the same day everywhere, so they are not converted
+		days := int((e.Duration + 12*time.Hour) / (24 * time.Hour))
+		occ.Start = e.Start.Format("2006-01-02")
+		occ.End = e.Start.AddDate(0, 0, days).Format("2006-01-02")
+		occ.OriginalStart = occ.Start
+		return occ
+	}
+	occ.Start = e.Start.In(target).Format(time.RFC3339)
+	occ.End = e.Start.Add(e.Duration).In(target).Format(time.RFC3339)
+	occ.OriginalStart = e.Start.Format(time.RFC3339)
+	return occ
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const weeklyStandupICS = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VTIMEZONE\r\n" +
+	"TZID:W. Europe Standard Time\r\n" +
+	"BEGIN:STANDARD\r\n" +
+	"DTSTART:16010101T030000\r\n" +
+	"TZOFFSETFROM:+0200\r\n" +
+	"TZOFFSETTO:+0100\r\n" +
+	"END:STANDARD\r\n" +
+	"END:VTIMEZONE\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:standup-1\r\n" +
+	"SUMMARY:Team standup\\, Berlin\r\n" +
+	"DTSTART;TZID=W. Europe Standard Time:20251020T093000\r\n" +
+	"DTEND;TZID=W. Europe Standard Time:20251020T094500\r\n" +
+	"RRULE:FREQ=WEEKLY;BYDAY=MO,TH;COUNT=6\r\n" +
+	"EXDATE;TZID=W. Europe Standard Time:20251023T093000\r\n" +
+	"BEGIN:VALARM\r\n" +
+	"TRIGGER:-PT15M\r\n" +
+	"SUMMARY:Reminder\r\n" +
+	"END:VALARM\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:standup-1\r\n" +
+	"RECURRENCE-ID;TZID=W. Europe Standard Time:20251030T093000\r\n" +
+	"SUMMARY:Team standup (moved)\r\n" +
+	"DTSTART;TZID=W. Europe Standard Time:20251030T110000\r\n" +
+	"DURATION:PT15M\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestHandleParseICal(t *testing.T) {
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{
+		"ics":             weeklyStandupICS,
+		"target_timezone": "America/New_York",
+		"window_start":    "2025-10-01",
+		"window_end":      "2025-11-30",
+	}
+	result, err := handleParseICal(&Config{})(context.Background(), req)
+	if err != nil || result.IsError {
+		t.Fatalf("Failed to parse iCalendar data: %v %s", err, toolResultText(result))
+	}
+	parsed, ok := result.StructuredContent.(icalParseResult)
+	if !ok {
+		t.Fatalf("Expected structured content of type icalParseResult but got %T", result.StructuredContent)
+	}
+
+	// Six occurrences from Oct 20, less the EXDATE on Oct 23; the Oct 30 one
+	// is replaced by the override. Europe changes clocks on Oct 26 and the US
+	// on Nov 2, so the New York time shifts in between.
+	var starts []string
+	for _, occ := range parsed.Occurrences {
+		starts = append(starts, occ.Start)
+	}
+	expected := []string{
+		"2025-10-20T03:30:00-04:00",
+		"2025-10-27T04:30:00-04:00",
+		"2025-10-30T06:00:00-04:00",
+		"2025-11-03T03:30:00-05:00",
+		"2025-11-06T03:30:00-05:00",
+	}
+	if !slices.Equal(starts, expected) {
+		t.Errorf("Expected occurrences %v but got %v", expected, starts)
+	}
+
+	first := parsed.Occurrences[0]
+	if first.Summary != "Team standup, Berlin" || first.OriginalTimezone != "Europe/Berlin" || !first.Recurring {
+		t.Errorf("Expected the unescaped summary and Europe/Berlin but got %+v", first)
+	}
+	if first.End != "2025-10-20T03:45:00-04:00" || first.OriginalStart != "2025-10-20T09:30:00+02:00" {
+		t.Errorf("Expected end 03:45 and original start 09:30+02:00 but got %+v", first)
+	}
+	if moved := parsed.Occurrences[2]; moved.Summary != "Team standup (moved)" || moved.End != "2025-10-30T06:15:00-04:00" {
+		t.Errorf("Expected the moved occurrence but got %+v", moved)
+	}
+}
+
+func TestHandleParseICal_Errors(t *testing.T) {
+	tests := []struct {
+		args     map[string]any
+		expected string
+	}{
+		{map[string]any{"ics": "BEGIN:VEVENT\nDTSTART;TZID=Mars/Olympus:20250101T100000\nEND:VEVENT"}, "unknown timezone"},
+		{map[string]any{"ics": "BEGIN:VEVENT\nDTSTART:20250101T100000Z\nRRULE:FREQ=HOURLY\nEND:VEVENT"}, "unsupported FREQ"},
+		{map[string]any{"ics": "BEGIN:VEVENT\nSUMMARY:No start\nEND:VEVENT"}, "has no DTSTART"},
+		{map[string]any{"ics": "BEGIN:VEVENT\nDTSTART:20250101T100000Z"}, "missing END:VEVENT"},
+		{map[string]any{"ics": "BEGIN:VEVENT\nEND:VEVENT", "window_start": "2025-01-01", "window_end": "2026-06-01"}, "at most 366 days"},
+		{map[string]any{"ics": "BEGIN:VEVENT\nEND:VEVENT", "target_timezone": "Invalid/Zone"}, "Invalid target timezone"},
+	}
+	for _, tt := range tests {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = tt.args
+		result, err := handleParseICal(&Config{})(context.Background(), req)
+		if err != nil || !result.IsError || !strings.Contains(toolResultText(result), tt.expected) {
+			t.Errorf("Expected an error containing %q but got %s (%v)", tt.expected, toolResultText(result), err)
+		}
+	}
+}
+
+func TestICalOccurrences_Rules(t *testing.T) {
+	tests := []struct {
+		name     string
+		ics      string
+		expected []string
+	}{
+		{
+			name: "last weekday of the month",
+			ics: "BEGIN:VEVENT\nDTSTART:20250131T160000Z\n" +
+				"RRULE:FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1\nEND:VEVENT",
+			expected: []string{"2025-01-31", "2025-02-28", "2025-03-31", "2025-04-30", "2025-05-30", "2025-06-30"},
+		},
+		{
+			name: "second-to-last Friday",
+			ics: "BEGIN:VEVENT\nDTSTART:20250124T160000Z\n" +
+				"RRULE:FREQ=MONTHLY;BYDAY=-2FR;UNTIL=20250430\nEND:VEVENT",
+			expected: []string{"2025-01-24", "2025-02-21", "2025-03-21", "2025-04-18"},
+		},
+		{
+			name: "every other day with an RDATE",
+			ics: "BEGIN:VEVENT\nDTSTART:20250601T080000Z\n" +
+				"RRULE:FREQ=DAILY;INTERVAL=2;COUNT=3\nRDATE:20250620T080000Z\nEND:VEVENT",
+			expected: []string{"2025-06-01", "2025-06-03", "2025-06-05", "2025-06-20"},
+		},
+		{
+			name: "all-day monthly on the 31st skips short months",
+			ics: "BEGIN:VEVENT\nDTSTART;VALUE=DATE:20240131\n" +
+				"RRULE:FREQ=MONTHLY;BYMONTH=1,2,3\nEND:VEVENT",
+			expected: []string{"2025-01-31", "2025-03-31"},
+		},
+	}
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		events, err := parseICal(tt.ics, time.UTC)
+		if err != nil {
+			t.Fatalf("%s: Failed to parse: %v", tt.name, err)
+		}
+		var dates []string
+		for _, occ := range icalOccurrences(events, from, to) {
+			dates = append(dates, occ.Start.Format("2006-01-02"))
+		}
+		if !slices.Equal(dates, tt.expected) {
+			t.Errorf("%s: Expected %v but got %v", tt.name, tt.expected, dates)
+		}
+	}
+}
+
+func TestParseICalDuration(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+		valid    bool
+	}{
+		{"PT1H30M", 90 * time.Minute, true},
+		{"P1D", 24 * time.Hour, true},
+		{"-P1W", -7 * 24 * time.Hour, true},
+		{"P1DT2H", 26 * time.Hour, true},
+		{"P1M", 0, false},
+		{"PT", 0, false},
+		{"1H", 0, false},
+	}
+	for _, tt := range tests {
+		got, err := parseICalDuration(tt.input)
+		if (err == nil) != tt.valid || got != tt.expected {
+			t.Errorf("%s: Expected %v (valid %v) but got %v (%v)", tt.input, tt.expected, tt.valid, got, err)
+		}
+	}
+}
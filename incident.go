@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/araddon/dateparse"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// maxIncidents caps the incidents of one incident_metrics call
+	maxIncidents = 1000
+	// maxIncidentDays bounds how long an incident may last, which bounds the
+	// business-hours walk over its days
+	maxIncidentDays = 400
+)
+
+// incidentInput is one incident's milestones as given
+type incidentInput struct {
+	ID           string `json:"id"`
+	Detected     string `json:"detected"`
+	Acknowledged string `json:"acknowledged"`
+	Mitigated    string `json:"mitigated"`
+	Resolved     string `json:"resolved"`
+	Timezone     string `json:"timezone"`
+}
+
+// incidentMetrics is the structured result of incident_metrics
+type incidentMetrics struct {
+	Calendar  string            `json:"calendar,omitempty" jsonschema:"Business calendar of the business-hours durations"`
+	Incidents []incidentResult  `json:"incidents"`
+	MTTA      *incidentDuration `json:"mtta,omitempty" jsonschema:"Mean time from detection to acknowledgement"`
+	MTTM      *incidentDuration `json:"mttm,omitempty" jsonschema:"Mean time from detection to mitigation"`
+	MTTR      *incidentDuration `json:"mttr,omitempty" jsonschema:"Mean time from detection to resolution"`
+}
+
+type incidentResult struct {
+	Index             int               `json:"index" jsonschema:"Position of the incident in the input, from 0"`
+	ID                string            `json:"id,omitempty"`
+	Detected          string            `json:"detected" jsonschema:"Detection time in RFC 3339"`
+	TimeToAcknowledge *incidentDuration `json:"time_to_acknowledge,omitempty"`
+	TimeToMitigate    *incidentDuration `json:"time_to_mitigate,omitempty"`
+	TimeToResolve     *incidentDuration `json:"time_to_resolve,omitempty"`
+}
+
+type incidentDuration struct {
+	Duration        string   `json:"duration" jsonschema:"Elapsed time, e.g. 1h25m"`
+	Seconds         float64  `json:"seconds"`
+	Business        string   `json:"business,omitempty" jsonschema:"Time within working hours of the business calendar"`
+	BusinessSeconds *float64 `json:"business_seconds,omitempty"`
+}
+
+// newIncidentDuration describes elapsed and, with a calendar, working time
+func newIncidentDuration(elapsed time.Duration, cal *businessCalendar, working time.Duration) *incidentDuration {
+	d := &incidentDuration{Duration: shortDuration(elapsed), Seconds: elapsed.Seconds()}
+	if cal != nil {
+		seconds := working.Seconds()
+		d.Business, d.BusinessSeconds = shortDuration(working), &seconds
+	}
+	return d
+}
+
+// incidentMean accumulates one milestone's durations across incidents
+type incidentMean struct {
+	elapsed, working time.Duration
+	count            int
+}
+
+func (m *incidentMean) add(elapsed, working time.Duration) {
+	m.elapsed += elapsed
+	m.working += working
+	m.count++
+}
+
+func (m *incidentMean) result(cal *businessCalendar) *incidentDuration {
+	if m.count == 0 {
+		return nil
+	}
+	n := time.Duration(m.count)
+	return newIncidentDuration((m.elapsed / n).Round(time.Second), cal, (m.working / n).Round(time.Second))
+}
+
+func addIncidentMetricsTool(mcpServer *server.MCPServer, config *Config) {
+	mcpServer.AddTool(
+		mcp.NewTool("incident_metrics",
+			mcp.WithDescription("Compute incident response durations from milestone timestamps: time from detection to acknowledgement, mitigation and resolution per incident, and their means (MTTA, MTTM, MTTR) across incidents. With a business calendar, also the part of each duration within working hours."),
+			mcp.WithArray("incidents",
+				mcp.Description("Incidents: detected and optionally acknowledged, mitigated and resolved, each in RFC 3339 or another common date-time format; an optional id and timezone for times without an offset."),
+				mcp.Items(map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"id":           map[string]any{"type": "string"},
+						"detected":     map[string]any{"type": "string"},
+						"acknowledged": map[string]any{"type": "string"},
+						"mitigated":    map[string]any{"type": "string"},
+						"resolved":     map[string]any{"type": "string"},
+						"timezone":     map[string]any{"type": "string"},
+					},
+					"required": []string{"detected"},
+				}),
+				mcp.MinItems(1),
+				mcp.MaxItems(maxIncidents),
+				mcp.Required(),
+			),
+			mcp.WithString("timezone",
+				mcp.Description("Timezone of incidents without one. Defaults to the session or server default."),
+			),
+			mcp.WithString("calendar",
+				mcp.Description("Business calendar for business-hours durations. Defaults to the caller's tenant calendar, or the only configured calendar."),
+			),
+			mcp.WithOutputSchema[incidentMetrics](),
+			mcp.WithTitleAnnotation("Incident Metrics"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		handleIncidentMetrics(config),
+	)
+}
+
+// handleIncidentMetrics returns a handler for the incident_metrics tool
+func handleIncidentMetrics(config *Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Incidents []incidentInput `json:"incidents"`
+		}
+		if err := request.BindArguments(&args); err != nil {
+			return toolError(errorInvalidArgument, fmt.Sprintf("Invalid incidents: %v", err)), nil
+		}
+		if len(args.Incidents) == 0 || len(args.Incidents) > maxIncidents {
+			return toolError(errorInvalidArgument, fmt.Sprintf("Provide between 1 and %d incidents", maxIncidents)), nil
+		}
+		timezoneStr := request.GetString("timezone", "")
+		defaultLoc, err := loadTimezone(ctx, timezoneStr, config)
+		if err != nil {
+			return invalidTimezoneError("Invalid timezone", timezoneStr), nil
+		}
+		cal, err := windowCalendar(ctx, config, request.GetString("calendar", ""))
+		if err != nil {
+			return toolError(errorNotFound, err.Error()), nil
+		}
+
+		metrics := incidentMetrics{Incidents: []incidentResult{}}
+		if cal != nil {
+			metrics.Calendar = cal.Name
+		}
+		var means [3]incidentMean
+		for i, in := range args.Incidents {
+			loc := defaultLoc
+			if in.Timezone != "" {
+				if loc, err = loadLocationCached(in.Timezone); err != nil {
+					return invalidTimezoneError(fmt.Sprintf("Invalid timezone in incident %d", i), in.Timezone), nil
+				}
+			}
+			milestones := []struct{ name, value string }{
+				{"detected", in.Detected}, {"acknowledged", in.Acknowledged}, {"mitigated", in.Mitigated}, {"resolved", in.Resolved},
+			}
+			var times [4]time.Time
+			var last time.Time
+			for j, m := range milestones {
+				if m.value == "" {
+					if j == 0 {
+						return toolError(errorInvalidArgument, fmt.Sprintf("Incident %d has no detected time", i)), nil
+					}
+					continue
+				}
+				t, err := dateparse.ParseIn(m.value, loc)
+				if err != nil {
+					return toolError(errorParse, fmt.Sprintf("Invalid %s time in incident %d: %q. Please provide RFC 3339 or another common date-time format.", m.name, i, m.value)), nil
+				}
+				if t.Before(last) {
+					return toolError(errorInvalidArgument, fmt.Sprintf("Incident %d was %s before an earlier milestone", i, m.name)), nil
+				}
+				if j > 0 && t.Sub(times[0]) > maxIncidentDays*24*time.Hour {
+					return toolError(errorInvalidArgument, fmt.Sprintf("Incident %d lasts more than %d days", i, maxIncidentDays)), nil
+				}
+				times[j], last = t, t
+			}
+
+			result := incidentResult{Index: i, ID: in.ID, Detected: times[0].In(loc).Format(time.RFC3339)}
+			fields := []**incidentDuration{&result.TimeToAcknowledge, &result.TimeToMitigate, &result.TimeToResolve}
+			for j, field := range fields {
+				end := times[j+1]
+				if end.IsZero() {
+					continue
+				}
+				elapsed := end.Sub(times[0])
+				var working time.Duration
+				if cal != nil {
+					working = cal.workingTimeBetween(times[0], end)
+				}
+				*field = newIncidentDuration(elapsed, cal, working)
+				means[j].add(elapsed, working)
+			}
+			metrics.Incidents = append(metrics.Incidents, result)
+		}
+		metrics.MTTA, metrics.MTTM, metrics.MTTR = means[0].result(cal), means[1].result(cal), means[2].result(cal)
+
+		data, err := json.Marshal(metrics)
+		if err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to encode result: %v", err)), nil
+		}
+		return mcp.NewToolResultStructured(metrics, string(data)), nil
+	}
+}
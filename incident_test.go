@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestIncidentMetrics(t *testing.T) {
+	calendars, err := parseCalendars([]byte(testCalendars))
+	if err != nil {
+		t.Fatalf("Failed to parse calendars: %v", err)
+	}
+	handler := handleIncidentMetrics(&Config{DefaultTimezone: "UTC", Calendars: calendars})
+	call := func(args map[string]any) *mcp.CallToolResult {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := handler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("Failed to call handler: %v", err)
+		}
+		return result
+	}
+
+	result := call(map[string]any{
+		"timezone": "Europe/Warsaw",
+		"calendar": "acme",
+		"incidents": []any{
+			map[string]any{"id": "INC-1", "detected": "2025-12-22 15:30", "acknowledged": "2025-12-22 15:40", "mitigated": "2025-12-22 17:30", "resolved": "2025-12-23 09:30"},
+			// Over the Christmas holidays and a weekend
+			map[string]any{"id": "INC-2", "detected": "2025-12-23T15:00:00Z", "acknowledged": "2025-12-23T15:20:00Z", "resolved": "2025-12-29T08:00:00Z"},
+		},
+	})
+	if result.IsError {
+		t.Fatalf("Failed to compute incident metrics: %s", toolResultText(result))
+	}
+	got := result.StructuredContent.(incidentMetrics)
+	if got.Calendar != "acme" || len(got.Incidents) != 2 {
+		t.Fatalf("Expected two incidents in calendar acme but got %+v", got)
+	}
+	first, second := got.Incidents[0], got.Incidents[1]
+	if first.TimeToAcknowledge.Duration != "10m" || first.TimeToMitigate.Duration != "2h" || first.TimeToMitigate.Business != "1h" {
+		t.Errorf("Expected INC-1 to be acknowledged in 10m and mitigated in 2h (1h of working time) but got %+v %+v", first.TimeToAcknowledge, first.TimeToMitigate)
+	}
+	if first.TimeToResolve.Duration != "18h" || first.TimeToResolve.Business != "2h" {
+		t.Errorf("Expected INC-1 to be resolved in 18h (2h of working time) but got %+v", first.TimeToResolve)
+	}
+	if second.TimeToMitigate != nil || second.TimeToResolve.Duration != "137h" || second.TimeToResolve.Business != "9h" {
+		t.Errorf("Expected INC-2 to be resolved in 137h (9h of working time) without mitigation but got %+v", second)
+	}
+	if got.MTTA.Duration != "15m" || got.MTTM.Duration != "2h" || got.MTTR.Duration != "77h30m" || got.MTTR.Business != "5h30m" {
+		t.Errorf("Expected MTTA 15m, MTTM 2h and MTTR 77h30m (5h30m of working time) but got %+v %+v %+v", got.MTTA, got.MTTM, got.MTTR)
+	}
+
+	for _, args := range []map[string]any{
+		{"incidents": []any{map[string]any{"acknowledged": "2025-12-22 15:40"}}},
+		{"incidents": []any{map[string]any{"detected": "2025-12-22 15:30", "resolved": "2025-12-22 15:00"}}},
+		{"incidents": []any{map[string]any{"detected": "yesterday"}}},
+		{"incidents": []any{map[string]any{"detected": "2025-12-22 15:30"}}, "calendar": "nope"},
+	} {
+		if result := call(args); !result.IsError {
+			t.Errorf("Expected %v to be rejected", args)
+		}
+	}
+}
@@ -0,0 +1,413 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"TimeMCP/client"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const integrationSecret = "test-secret-key-that-is-long-enough-for-hs256"
+
+// newIntegrationServer builds the MCP server the way runServer does for the
+// built-in tools
+func newIntegrationServer(config *Config) *server.MCPServer {
+	mcpServer := server.NewMCPServer("TimeMCP", "test", server.WithToolCapabilities(true))
+	newToolMiddlewareChain(func() *Config { return config }, nil, nil).install(mcpServer)
+	addTools(mcpServer, config)
+	return mcpServer
+}
+
+// startIntegrationHTTPServer serves the full HTTP stack on a loopback port
+// and returns the MCP endpoint URL
+func startIntegrationHTTPServer(t *testing.T, config *Config) (string, *AuthMiddleware) {
+	t.Helper()
+	if config.HTTPPath == "" {
+		config.HTTPPath = "/mcp"
+	}
+	mcpServer := newIntegrationServer(config)
+	contextFunc := &reloadableContextFunc{}
+	opts, err := createHttpServerOptions(config, contextFunc, nil)
+	if err != nil {
+		t.Fatalf("Failed to create HTTP server options: %v", err)
+	}
+	mcpHandler := server.NewStreamableHTTPServer(mcpServer, opts...)
+	srv := httptest.NewServer(createCustomHTTPHandler(mcpHandler, config, &httpRuntime{mcpServer: mcpServer, contextFunc: contextFunc}))
+	t.Cleanup(srv.Close)
+	return srv.URL + config.HTTPPath, contextFunc.auth()
+}
+
+// startIntegrationStdioServer connects a client to the stdio server over pipes
+func startIntegrationStdioServer(t *testing.T, ctx context.Context, config *Config, stdioOpts []server.StdioOption, clientOpts ...client.Option) *client.Client {
+	t.Helper()
+	stdio := server.NewStdioServer(newIntegrationServer(config))
+	for _, opt := range stdioOpts {
+		opt(stdio)
+	}
+	clientIn, serverOut := io.Pipe()
+	serverIn, clientOut := io.Pipe()
+	go stdio.Listen(ctx, serverIn, serverOut)
+
+	c, err := client.New(ctx, transport.NewIO(clientIn, clientOut, io.NopCloser(strings.NewReader(""))), clientOpts...)
+	if err != nil {
+		t.Fatalf("Failed to connect over stdio: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+// postJSONRPC sends one JSON-RPC message to the MCP endpoint and decodes a
+// JSON response; transport errors are plain text
+func postJSONRPC(t *testing.T, url, sessionID, body string) (*http.Response, map[string]any) {
+	t.Helper()
+	req, _ := http.NewRequest("POST", url, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	if sessionID != "" {
+		req.Header.Set("Mcp-Session-Id", sessionID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to post %s: %v", body, err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	var message map[string]any
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") && len(bytes.TrimSpace(data)) > 0 {
+		if err := json.Unmarshal(data, &message); err != nil {
+			t.Fatalf("Failed to decode response %q: %v", data, err)
+		}
+	}
+	return resp, message
+}
+
+func TestIntegration_HTTPProtocol(t *testing.T) {
+	url, _ := startIntegrationHTTPServer(t, &Config{DefaultTimezone: "UTC"})
+
+	resp, message := postJSONRPC(t, url, "", `{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": {"protocolVersion": "2025-03-26", "capabilities": {}, "clientInfo": {"name": "integration", "version": "1.0"}}}`)
+	sessionID := resp.Header.Get("Mcp-Session-Id")
+	if resp.StatusCode != http.StatusOK || sessionID == "" {
+		t.Fatalf("Failed to initialize: %d %v", resp.StatusCode, message)
+	}
+	result, _ := message["result"].(map[string]any)
+	if info, _ := result["serverInfo"].(map[string]any); info["name"] != "TimeMCP" {
+		t.Errorf("Expected serverInfo for TimeMCP but got %v", result["serverInfo"])
+	}
+	if resp, _ := postJSONRPC(t, url, sessionID, `{"jsonrpc": "2.0", "method": "notifications/initialized"}`); resp.StatusCode != http.StatusAccepted {
+		t.Errorf("Expected status 202 for the initialized notification but got %d", resp.StatusCode)
+	}
+
+	_, message = postJSONRPC(t, url, sessionID, `{"jsonrpc": "2.0", "id": 2, "method": "tools/list"}`)
+	var list mcp.ListToolsResult
+	data, _ := json.Marshal(message["result"])
+	if err := json.Unmarshal(data, &list); err != nil {
+		t.Fatalf("Failed to decode tools/list: %v", err)
+	}
+	var names []string
+	for _, tool := range list.Tools {
+		names = append(names, tool.Name)
+	}
+	for _, want := range []string{"get_current_time", "convert_time", "convert_time_v2", "describe_cron", "get_server_info"} {
+		if !slices.Contains(names, want) {
+			t.Errorf("Expected tools/list to include %s but got %v", want, names)
+		}
+	}
+
+	_, message = postJSONRPC(t, url, sessionID, `{"jsonrpc": "2.0", "id": 3, "method": "tools/call", "params": {"name": "convert_time", "arguments": {"source_timezone": "UTC", "time": "12:00", "target_timezone": "Europe/Warsaw"}}}`)
+	data, _ = json.Marshal(message["result"])
+	if !strings.Contains(string(data), "Europe/Warsaw") || strings.Contains(string(data), `"isError":true`) {
+		t.Errorf("Expected a conversion to Europe/Warsaw but got %s", data)
+	}
+
+	_, message = postJSONRPC(t, url, sessionID, `{"jsonrpc": "2.0", "id": 4, "method": "tools/call", "params": {"name": "no_such_tool"}}`)
+	if message["error"] == nil {
+		t.Errorf("Expected a JSON-RPC error for an unknown tool but got %v", message)
+	}
+	if resp, _ := postJSONRPC(t, url, "bogus", `{"jsonrpc": "2.0", "id": 5, "method": "tools/list"}`); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected status 404 for an invalid session but got %d", resp.StatusCode)
+	}
+}
+
+func TestIntegration_HTTPClient(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	url, _ := startIntegrationHTTPServer(t, &Config{DefaultTimezone: "UTC"})
+
+	c, err := client.NewHTTP(ctx, url)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer c.Close()
+	if c.Server().Name != "TimeMCP" {
+		t.Errorf("Expected server name TimeMCP but got %s", c.Server().Name)
+	}
+
+	now, err := c.GetCurrentTime(ctx, "Asia/Tokyo")
+	if err != nil || !strings.Contains(now, "Asia/Tokyo") {
+		t.Errorf("Expected the current time in Asia/Tokyo but got %q, %v", now, err)
+	}
+	conversion, err := c.ConvertTime(ctx, client.ConvertRequest{Time: "14:00", SourceTimezone: "UTC", TargetTimezone: "Asia/Tokyo"})
+	if err != nil {
+		t.Fatalf("Failed to convert time: %v", err)
+	}
+	if !strings.Contains(conversion.Target.Datetime, "23:00") || conversion.Target.UTCOffset != "+09:00" {
+		t.Errorf("Expected 23:00 +09:00 in Tokyo but got %+v", conversion.Target)
+	}
+	info, err := c.GetServerInfo(ctx)
+	if err != nil || info.GoVersion == "" {
+		t.Errorf("Expected server info but got %+v, %v", info, err)
+	}
+	cron, err := c.DescribeCron(ctx, "0 9 * * 1-5", "")
+	if err != nil || cron == "" {
+		t.Errorf("Expected a cron description but got %q, %v", cron, err)
+	}
+
+	_, err = c.GetCurrentTime(ctx, "Mars/Olympus")
+	var toolErr *client.ToolError
+	if !errors.As(err, &toolErr) || toolErr.Tool != "get_current_time" || toolErr.Code != client.CodeInvalidTimezone {
+		t.Errorf("Expected an INVALID_TIMEZONE tool error but got %v", err)
+	}
+}
+
+func TestIntegration_HTTPAuth(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	config := &Config{
+		AuthEnabled:   true,
+		AuthSecretKey: integrationSecret,
+		AuthIssuer:    "test-issuer",
+		AuthAudience:  "test-audience",
+	}
+	url, auth := startIntegrationHTTPServer(t, config)
+	token, err := auth.GenerateToken("1", "ada", "user", 1)
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+	forger, err := NewAuthMiddlewareFromConfig(&Config{
+		AuthEnabled:   true,
+		AuthSecretKey: "another-secret-key-that-is-long-enough-for-hs256",
+		AuthIssuer:    config.AuthIssuer,
+		AuthAudience:  config.AuthAudience,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create auth middleware: %v", err)
+	}
+	forged, _ := forger.GenerateToken("1", "ada", "admin", 1)
+
+	testCases := []struct {
+		name    string
+		opts    []client.Option
+		allowed bool
+	}{
+		{"Valid Token", []client.Option{client.WithToken(token)}, true},
+		{"Missing Token", nil, false},
+		{"Forged Token", []client.Option{client.WithToken(forged)}, false},
+		{"Malformed Token", []client.Option{client.WithToken("not-a-jwt")}, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := client.NewHTTP(ctx, url, tc.opts...)
+			if err != nil {
+				t.Fatalf("Failed to connect: %v", err)
+			}
+			defer c.Close()
+			_, err = c.GetCurrentTime(ctx, "UTC")
+			if tc.allowed && err != nil {
+				t.Errorf("Expected the call to succeed but got %v", err)
+			}
+			if !tc.allowed && !client.IsForbidden(err) {
+				t.Errorf("Expected the call to be denied but got %v", err)
+			}
+		})
+	}
+}
+
+func TestIntegration_HTTPAPIKey(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	keys, err := parseAPIKeys("ci:reader:" + hashAPIKey("ci-secret"))
+	if err != nil {
+		t.Fatalf("Failed to parse API keys: %v", err)
+	}
+	url, _ := startIntegrationHTTPServer(t, &Config{AuthEnabled: true, AuthMode: authModeAPIKey, AuthAPIKeys: keys})
+
+	for key, allowed := range map[string]bool{"ci-secret": true, "wrong-secret": false} {
+		c, err := client.NewHTTP(ctx, url, client.WithAPIKey(key))
+		if err != nil {
+			t.Fatalf("Failed to connect: %v", err)
+		}
+		_, err = c.GetCurrentTime(ctx, "UTC")
+		c.Close()
+		if allowed != (err == nil) {
+			t.Errorf("Expected allowed=%v for key %s but got %v", allowed, key, err)
+		}
+	}
+}
+
+func TestIntegration_CORSPreflight(t *testing.T) {
+	t.Setenv("TIME_HTTP_CORS_ENABLED", "true")
+	t.Setenv("TIME_HTTP_CORS_ORIGINS", "https://app.example.com")
+	config, err := NewConfig()
+	if err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+	url, _ := startIntegrationHTTPServer(t, config)
+
+	preflight := func(origin string) *http.Response {
+		req, _ := http.NewRequest("OPTIONS", url, nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", "POST")
+		req.Header.Set("Access-Control-Request-Headers", "Content-Type, Authorization, Mcp-Session-Id")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Failed to send preflight: %v", err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	resp := preflight("https://app.example.com")
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Access-Control-Allow-Origin") != "https://app.example.com" {
+		t.Errorf("Expected an allowed preflight but got %d %v", resp.StatusCode, resp.Header)
+	}
+	if methods := resp.Header.Get("Access-Control-Allow-Methods"); !strings.Contains(methods, "POST") {
+		t.Errorf("Expected POST in Access-Control-Allow-Methods but got %q", methods)
+	}
+	if headers := resp.Header.Get("Access-Control-Allow-Headers"); !strings.Contains(headers, "Mcp-Session-Id") {
+		t.Errorf("Expected Mcp-Session-Id in Access-Control-Allow-Headers but got %q", headers)
+	}
+	if resp.Header.Get("Access-Control-Max-Age") == "" {
+		t.Errorf("Expected Access-Control-Max-Age on a preflight")
+	}
+
+	if resp := preflight("https://evil.example.com"); resp.Header.Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("Expected no CORS headers for a disallowed origin but got %v", resp.Header)
+	}
+
+	// Actual requests from an allowed origin expose the session header
+	req, _ := http.NewRequest("POST", url, strings.NewReader(`{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": {"protocolVersion": "2025-03-26", "capabilities": {}, "clientInfo": {"name": "browser", "version": "1.0"}}}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	req.Header.Set("Origin", "https://app.example.com")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	resp.Body.Close()
+	if expose := resp.Header.Get("Access-Control-Expose-Headers"); !strings.Contains(expose, "Mcp-Session-Id") {
+		t.Errorf("Expected Mcp-Session-Id to be exposed but got %q", expose)
+	}
+}
+
+func TestIntegration_Stdio(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	c := startIntegrationStdioServer(t, ctx, &Config{DefaultTimezone: "UTC"}, nil)
+
+	tools, err := c.ListTools(ctx)
+	if err != nil || len(tools) == 0 {
+		t.Fatalf("Failed to list tools: %v", err)
+	}
+	now, err := c.GetCurrentTime(ctx, "")
+	if err != nil || !strings.Contains(now, "UTC") {
+		t.Errorf("Expected the current time in the default timezone but got %q, %v", now, err)
+	}
+	conversion, err := c.ConvertTime(ctx, client.ConvertRequest{Time: "09:30", SourceTimezone: "America/New_York", TargetTimezone: "UTC"})
+	if err != nil || conversion.Source.Timezone != "America/New_York" {
+		t.Errorf("Expected a conversion from America/New_York but got %+v, %v", conversion, err)
+	}
+	if _, err := c.DescribeCron(ctx, "not a cron", ""); client.ErrorCode(err) != client.CodeParseError {
+		t.Errorf("Expected a PARSE_ERROR for an invalid cron expression but got %v", err)
+	}
+}
+
+func TestIntegration_ClientHints(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	url, _ := startIntegrationHTTPServer(t, &Config{DefaultTimezone: "UTC"})
+
+	c, err := client.NewHTTP(ctx, url, client.WithTimezone("Asia/Tokyo"), client.WithLocale("pl-PL"))
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer c.Close()
+	if now, err := c.GetCurrentTime(ctx, ""); err != nil || !strings.Contains(now, "Asia/Tokyo") {
+		t.Errorf("Expected the session timezone but got %q, %v", now, err)
+	}
+	if now, err := c.GetCurrentTime(ctx, "Europe/Warsaw"); err != nil || !strings.Contains(now, "Europe/Warsaw") {
+		t.Errorf("Expected an explicit timezone to win over the hint but got %q, %v", now, err)
+	}
+	polish, err := c.DescribeCron(ctx, "@daily", "")
+	if err != nil {
+		t.Fatalf("Failed to describe cron: %v", err)
+	}
+	english, _ := c.DescribeCron(ctx, "@daily", "en")
+	if polish == english {
+		t.Errorf("Expected the session locale to select Polish but got %q", polish)
+	}
+
+	plain, err := client.NewHTTP(ctx, url)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer plain.Close()
+	if now, err := plain.GetCurrentTime(ctx, ""); err != nil || !strings.Contains(now, "UTC") {
+		t.Errorf("Expected other sessions to keep the server default but got %q, %v", now, err)
+	}
+
+	stdio := startIntegrationStdioServer(t, ctx, &Config{DefaultTimezone: "UTC"}, nil, client.WithTimezone("America/New_York"))
+	if now, err := stdio.GetCurrentTime(ctx, ""); err != nil || !strings.Contains(now, "America/New_York") {
+		t.Errorf("Expected the session timezone over stdio but got %q, %v", now, err)
+	}
+}
+
+func TestIntegration_StdioAuth(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	config := &Config{
+		AuthEnabled:       true,
+		AuthSecretKey:     integrationSecret,
+		AuthIssuer:        "test-issuer",
+		AuthAudience:      "test-audience",
+		StdioAuthRequired: true,
+	}
+	var err error
+	if config.AuthRoles, err = parseRolePolicy("user:get_current_time"); err != nil {
+		t.Fatalf("Failed to parse role policy: %v", err)
+	}
+	auth, err := NewAuthMiddlewareFromConfig(config)
+	if err != nil {
+		t.Fatalf("Failed to create auth middleware: %v", err)
+	}
+	config.StdioAuthToken, _ = auth.GenerateToken("1", "uma", "user", 1)
+	opts, err := stdioAuthOptions(config)
+	if err != nil {
+		t.Fatalf("Failed to create stdio auth options: %v", err)
+	}
+	c := startIntegrationStdioServer(t, ctx, config, opts)
+
+	if _, err := c.GetCurrentTime(ctx, "UTC"); err != nil {
+		t.Errorf("Expected an authenticated stdio call to succeed but got %v", err)
+	}
+	if _, err := c.GetServerInfo(ctx); !client.IsForbidden(err) {
+		t.Errorf("Expected a role denial over stdio but got %v", err)
+	}
+
+	config.StdioAuthToken = "not-a-jwt"
+	if _, err := stdioAuthOptions(config); err == nil {
+		t.Errorf("Expected an invalid stdio token to be rejected at startup")
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Bounds of interval_convert intervals: a second up to a (Gregorian) year
+const (
+	minConvertInterval = time.Second
+	maxConvertInterval = 8766 * time.Hour
+)
+
+// ratePeriods are the periods a rate can be given per, with months and years
+// as their Gregorian averages (365.2425 days a year)
+var ratePeriods = map[string]time.Duration{
+	"hour":  time.Hour,
+	"day":   24 * time.Hour,
+	"week":  7 * 24 * time.Hour,
+	"month": 8766 * time.Hour / 12,
+	"year":  8766 * time.Hour,
+}
+
+// intervalConversion is the structured result of interval_convert
+type intervalConversion struct {
+	Interval        string  `json:"interval" jsonschema:"Average interval between occurrences as a Go duration, e.g. 1h30m0s"`
+	IntervalSeconds float64 `json:"interval_seconds"`
+	PerHour         float64 `json:"per_hour"`
+	PerDay          float64 `json:"per_day"`
+	PerWeek         float64 `json:"per_week"`
+	PerMonth        float64 `json:"per_month" jsonschema:"Occurrences per average Gregorian month of 30.44 days"`
+	PerYear         float64 `json:"per_year" jsonschema:"Occurrences per average Gregorian year of 365.2425 days"`
+	DividesDay      bool    `json:"divides_day" jsonschema:"Whether the interval divides 24 hours evenly, so the same clock times repeat every day"`
+	Description     string  `json:"description" jsonschema:"The conversion in words, e.g. every 1h30m = 16 times per day"`
+}
+
+// perPeriod returns how many intervals fit in period, to four decimals
+func perPeriod(interval, period time.Duration) float64 {
+	return math.Round(float64(period)/float64(interval)*10000) / 10000
+}
+
+// shortDuration formats d like time.Duration.String without zero trailing units
+func shortDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = s[:len(s)-2]
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = s[:len(s)-2]
+	}
+	return s
+}
+
+// convertInterval fills in every rate for an interval
+func convertInterval(interval time.Duration) intervalConversion {
+	return intervalConversion{
+		Interval:        interval.String(),
+		IntervalSeconds: interval.Seconds(),
+		PerHour:         perPeriod(interval, ratePeriods["hour"]),
+		PerDay:          perPeriod(interval, ratePeriods["day"]),
+		PerWeek:         perPeriod(interval, ratePeriods["week"]),
+		PerMonth:        perPeriod(interval, ratePeriods["month"]),
+		PerYear:         perPeriod(interval, ratePeriods["year"]),
+		DividesDay:      (24*time.Hour)%interval == 0,
+	}
+}
+
+func addIntervalConvertTool(mcpServer *server.MCPServer) {
+	mcpServer.AddTool(
+		mcp.NewTool("interval_convert",
+			mcp.WithDescription("Convert between an interval and a frequency: 'every 90 minutes' gives 16 times per day, '3 times per week' gives an average interval of 2 days 8 hours. Returns the rate per hour, day, week, month and year, and whether the interval divides the day evenly."),
+			mcp.WithString("interval",
+				mcp.Description("Interval between occurrences as a Go duration (e.g. 90m, 1h30m) or an ISO 8601 duration (e.g. PT90M, P2D). Give either interval or times with per."),
+			),
+			mcp.WithNumber("times",
+				mcp.Description("Number of occurrences per period, e.g. 3 for '3 times per week'."),
+			),
+			mcp.WithString("per",
+				mcp.Description("Period of times. Months and years are Gregorian averages. Defaults to day."),
+				mcp.Enum("hour", "day", "week", "month", "year"),
+			),
+			mcp.WithOutputSchema[intervalConversion](),
+			mcp.WithTitleAnnotation("Interval Convert"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		handleIntervalConvert(),
+	)
+}
+
+// handleIntervalConvert returns a handler for the interval_convert tool
+func handleIntervalConvert() server.ToolHandlerFunc {
+	return func(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		intervalStr := request.GetString("interval", "")
+		times := request.GetFloat("times", 0)
+		if (intervalStr == "") == (times == 0) {
+			return toolError(errorInvalidArgument, "Provide either interval or times, not both"), nil
+		}
+
+		var interval time.Duration
+		var description string
+		if intervalStr != "" {
+			var err error
+			if interval, err = time.ParseDuration(intervalStr); err != nil {
+				if interval, err = parseICalDuration(strings.ToUpper(intervalStr)); err != nil {
+					return toolError(errorParse, fmt.Sprintf("Invalid interval: %s. Please provide a duration such as 90m or PT90M.", intervalStr)), nil
+				}
+			}
+			if interval < minConvertInterval || interval > maxConvertInterval {
+				return toolError(errorInvalidArgument, fmt.Sprintf("Interval %s out of range; it must be from 1s to a year", intervalStr)), nil
+			}
+		} else {
+			per := request.GetString("per", "day")
+			period, ok := ratePeriods[per]
+			if !ok {
+				return toolError(errorInvalidArgument, fmt.Sprintf("Invalid per: %s. Please use hour, day, week, month or year.", per)), nil
+			}
+			if times < 0 || math.IsNaN(times) || math.IsInf(times, 0) {
+				return toolError(errorInvalidArgument, fmt.Sprintf("Invalid times: %v. Please provide a positive number.", times)), nil
+			}
+			exact := float64(period) / times
+			if exact < float64(minConvertInterval) || exact > float64(maxConvertInterval) {
+				return toolError(errorInvalidArgument, fmt.Sprintf("%v times per %s is out of range; the interval must be from 1s to a year", times, per)), nil
+			}
+			interval = time.Duration(exact).Round(time.Second)
+			description = fmt.Sprintf("%v times per %s = every %s", times, per, shortDuration(interval))
+		}
+
+		result := convertInterval(interval)
+		if description == "" {
+			description = fmt.Sprintf("every %s = %v times per day", shortDuration(interval), result.PerDay)
+		}
+		result.Description = description
+
+		data, err := json.Marshal(result)
+		if err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to encode result: %v", err)), nil
+		}
+		return mcp.NewToolResultStructured(result, string(data)), nil
+	}
+}
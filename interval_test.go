@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestIntervalConvert(t *testing.T) {
+	handler := handleIntervalConvert()
+	call := func(args map[string]any) *mcp.CallToolResult {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := handler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("Failed to call handler: %v", err)
+		}
+		return result
+	}
+
+	tests := []struct {
+		args        map[string]any
+		interval    string
+		perDay      float64
+		dividesDay  bool
+		description string
+	}{
+		{map[string]any{"interval": "90m"}, "1h30m0s", 16, true, "every 1h30m = 16 times per day"},
+		{map[string]any{"interval": "PT7H"}, "7h0m0s", 3.4286, false, "every 7h = 3.4286 times per day"},
+		{map[string]any{"times": 3.0, "per": "week"}, "56h0m0s", 0.4286, false, "3 times per week = every 56h"},
+		{map[string]any{"times": 4.0}, "6h0m0s", 4, true, "4 times per day = every 6h"},
+	}
+	for _, tt := range tests {
+		result := call(tt.args)
+		if result.IsError {
+			t.Fatalf("Failed to convert %v: %s", tt.args, toolResultText(result))
+		}
+		got := result.StructuredContent.(intervalConversion)
+		if got.Interval != tt.interval || got.PerDay != tt.perDay || got.DividesDay != tt.dividesDay || got.Description != tt.description {
+			t.Errorf("Expected %v to convert to %s, %v per day, divides day %v, %q but got %+v", tt.args, tt.interval, tt.perDay, tt.dividesDay, tt.description, got)
+		}
+	}
+
+	for _, args := range []map[string]any{
+		{},
+		{"interval": "90m", "times": 3.0},
+		{"interval": "soon"},
+		{"interval": "500ms"},
+		{"times": 3.0, "per": "fortnight"},
+		{"times": -1.0},
+	} {
+		if result := call(args); !result.IsError {
+			t.Errorf("Expected %v to be rejected", args)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"log/slog"
+	"net"
+	"net/http"
+)
+
+// ipFilterAllows applies the CIDR allow and deny lists. The denylist wins;
+// when an allowlist is set, only matching addresses are admitted.
+func ipFilterAllows(ip net.IP, allow, deny []*net.IPNet) bool {
+	if ipInNets(ip, deny) {
+		return false
+	}
+	if len(allow) > 0 {
+		return ipInNets(ip, allow)
+	}
+	return true
+}
+
+// ipFilterHandler rejects requests from client addresses outside the allowed
+// networks. It must run after realIPHandler so forwarded addresses are honored.
+func ipFilterHandler(next http.Handler, allow, deny []*net.IPNet) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip := remoteIP(r)
+		if !ipFilterAllows(ip, allow, deny) {
+			slog.WarnContext(r.Context(), "Rejected request: address not allowed", "remote_addr", r.RemoteAddr)
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
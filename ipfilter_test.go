@@ -0,0 +1,57 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIPFilterHandler(t *testing.T) {
+	allow, err := parseCIDRList("10.0.0.0/8, 2001:db8::/32")
+	if err != nil {
+		t.Fatalf("Failed to parse allowlist: %v", err)
+	}
+	deny, err := parseCIDRList("10.6.6.0/24")
+	if err != nil {
+		t.Fatalf("Failed to parse denylist: %v", err)
+	}
+	trusted, err := parseCIDRList("192.168.1.1")
+	if err != nil {
+		t.Fatalf("Failed to parse trusted proxies: %v", err)
+	}
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := realIPHandler(ipFilterHandler(next, allow, deny), trusted)
+
+	testCases := []struct {
+		name       string
+		remoteAddr string
+		xff        string
+		expected   int
+	}{
+		{"Allowed IPv4", "10.1.2.3:1234", "", http.StatusOK},
+		{"Allowed IPv6", "[2001:db8::1]:1234", "", http.StatusOK},
+		{"Denied Within Allowed Range", "10.6.6.6:1234", "", http.StatusForbidden},
+		{"Outside Allowlist", "203.0.113.5:1234", "", http.StatusForbidden},
+		{"Forwarded Through Trusted Proxy", "192.168.1.1:1234", "10.1.2.3", http.StatusOK},
+		{"Forwarded Denied Address", "192.168.1.1:1234", "10.6.6.1", http.StatusForbidden},
+		{"Spoofed Header From Untrusted Peer", "203.0.113.5:1234", "10.1.2.3", http.StatusForbidden},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+			req.RemoteAddr = tc.remoteAddr
+			if tc.xff != "" {
+				req.Header.Set("X-Forwarded-For", tc.xff)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tc.expected {
+				t.Errorf("Expected status %d, but got %d", tc.expected, rec.Code)
+			}
+		})
+	}
+}
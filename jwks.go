@@ -0,0 +1,223 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// jwksMinRefreshInterval limits how often an unknown kid can trigger a refetch
+	jwksMinRefreshInterval = 30 * time.Second
+	// jwksMaxResponseSize bounds the size of a fetched key set
+	jwksMaxResponseSize = 1 << 20
+)
+
+// jsonWebKey is the subset of RFC 7517 fields needed for signature verification
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// jwksCache fetches and caches a JSON Web Key Set. Keys are refreshed
+// periodically and whenever a token references an unknown kid.
+type jwksCache struct {
+	url                string
+	client             *http.Client
+	refreshInterval    time.Duration
+	minRefreshInterval time.Duration
+
+	mu          sync.RWMutex
+	keys        map[string]any
+	fetchedAt   time.Time
+	attemptedAt time.Time
+	lastErr     error
+}
+
+func newJWKSCache(url string, refreshInterval time.Duration) *jwksCache {
+	c := &jwksCache{
+		url:                url,
+		client:             &http.Client{Timeout: 10 * time.Second},
+		refreshInterval:    refreshInterval,
+		minRefreshInterval: jwksMinRefreshInterval,
+		keys:               make(map[string]any),
+	}
+	if err := c.refresh(); err != nil {
+		slog.Warn("Initial JWKS fetch failed", "url", url, "error", err)
+	}
+	return c
+}
+
+// key returns the verification key for kid, refreshing the set when the
+// cache is stale or the kid is unknown. Fetches, failed ones included, are
+// at least minRefreshInterval apart, so tokens with made-up kids cannot
+// flood the key set URL.
+func (c *jwksCache) key(kid string) (any, error) {
+	c.mu.RLock()
+	key, ok := c.keys[kid]
+	age := time.Since(c.fetchedAt)
+	c.mu.RUnlock()
+
+	if ok && age < c.refreshInterval {
+		return key, nil
+	}
+	if c.claimRefresh() {
+		if err := c.refresh(); err != nil {
+			slog.Warn("JWKS refresh failed", "url", c.url, "error", err)
+		}
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if key, ok := c.keys[kid]; ok {
+		return key, nil
+	}
+	if c.lastErr != nil {
+		return nil, fmt.Errorf("no key found for kid %q: key set unavailable: %w", kid, c.lastErr)
+	}
+	return nil, fmt.Errorf("no key found for kid %q", kid)
+}
+
+// claimRefresh reports whether the caller may fetch the key set now and, if
+// so, records the attempt so concurrent callers wait for the next window
+func (c *jwksCache) claimRefresh() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if time.Since(c.attemptedAt) < c.minRefreshInterval {
+		return false
+	}
+	c.attemptedAt = time.Now()
+	return true
+}
+
+// ready reports whether at least one key has been loaded
+func (c *jwksCache) ready() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.keys) > 0
+}
+
+// refresh fetches the key set, keeping the outcome for /health
+func (c *jwksCache) refresh() error {
+	keys, err := c.fetch()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.attemptedAt, c.lastErr = time.Now(), err
+	if err != nil {
+		return err
+	}
+	c.keys = keys
+	c.fetchedAt = c.attemptedAt
+	return nil
+}
+
+func (c *jwksCache) fetch() (map[string]any, error) {
+	resp, err := c.client.Get(c.url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, jwksMaxResponseSize))
+	if err != nil {
+		return nil, err
+	}
+	return parseJWKS(data)
+}
+
+// parseJWKS decodes a key set into public keys indexed by kid.
+// Keys not intended for signatures and unsupported key types are skipped.
+func parseJWKS(data []byte) (map[string]any, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("invalid JWKS document: %w", err)
+	}
+
+	keys := make(map[string]any, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			slog.Warn("Skipping JWKS key", "kid", jwk.Kid, "error", err)
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	return keys, nil
+}
+
+func (k jsonWebKey) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBase64URLInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBase64URLInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBase64URLInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBase64URLInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+func decodeBase64URLInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) == 0 {
+		return nil, fmt.Errorf("invalid base64url integer")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
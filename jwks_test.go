@@ -0,0 +1,128 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func rsaJWK(kid string, key *rsa.PublicKey) map[string]string {
+	return map[string]string{
+		"kty": "RSA",
+		"kid": kid,
+		"use": "sig",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func TestJWKS_RotationOnKidMiss(t *testing.T) {
+	oldKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	newKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	var rotated atomic.Bool
+	var fetches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		keys := []map[string]string{rsaJWK("old", &oldKey.PublicKey)}
+		if rotated.Load() {
+			keys = append(keys, rsaJWK("new", &newKey.PublicKey))
+		}
+		json.NewEncoder(w).Encode(map[string]any{"keys": keys})
+	}))
+	defer srv.Close()
+
+	auth, err := NewAuthMiddlewareFromConfig(&Config{
+		AuthEnabled:     true,
+		AuthIssuer:      "test-issuer",
+		AuthAudience:    "test-audience",
+		AuthJWKSURL:     srv.URL,
+		AuthJWKSRefresh: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create auth middleware: %v", err)
+	}
+	auth.jwks.minRefreshInterval = 0
+
+	sign := func(kid string, key *rsa.PrivateKey) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, Claims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    "test-issuer",
+				Audience:  jwt.ClaimStrings{"test-audience"},
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			},
+			UserID:   "1",
+			Username: "testuser",
+			Role:     "user",
+		})
+		token.Header["kid"] = kid
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatalf("Failed to sign token: %v", err)
+		}
+		return signed
+	}
+
+	if _, err := auth.validateJWT(sign("old", oldKey)); err != nil {
+		t.Fatalf("Expected token signed with cached key to validate, but got %v", err)
+	}
+	if fetches.Load() != 1 {
+		t.Errorf("Expected a cached key to be used without refetching, but got %d fetches", fetches.Load())
+	}
+
+	rotated.Store(true)
+	if _, err := auth.validateJWT(sign("new", newKey)); err != nil {
+		t.Fatalf("Expected token with rotated kid to validate after refresh, but got %v", err)
+	}
+
+	if _, err := auth.validateJWT(sign("unknown", newKey)); err == nil {
+		t.Error("Expected token with unknown kid to be rejected")
+	}
+}
+
+func TestJWKS_FailedFetchesAreThrottled(t *testing.T) {
+	var fetches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	cache := newJWKSCache(srv.URL, time.Hour)
+	for _, kid := range []string{"a", "b", "c"} {
+		_, err := cache.key(kid)
+		if err == nil || !strings.Contains(err.Error(), "503") {
+			t.Errorf("Expected the fetch error for kid %s, but got %v", kid, err)
+		}
+	}
+	if fetches.Load() != 1 {
+		t.Errorf("Expected unknown kids not to refetch within the throttle window, but got %d fetches", fetches.Load())
+	}
+
+	cache.minRefreshInterval = 0
+	cache.key("d")
+	if fetches.Load() != 2 {
+		t.Errorf("Expected a refetch once the window passed, but got %d fetches", fetches.Load())
+	}
+}
+
+func TestParseJWKS_SkipsUnsupportedKeys(t *testing.T) {
+	data := []byte(`{"keys":[{"kty":"oct","kid":"a","k":"c2VjcmV0"},{"kty":"RSA","kid":"enc","use":"enc","n":"AQAB","e":"AQAB"}]}`)
+	keys, err := parseJWKS(data)
+	if err != nil {
+		t.Fatalf("Failed to parse JWKS: %v", err)
+	}
+	if len(keys) != 0 {
+		t.Errorf("Expected unsupported and encryption keys to be skipped, but got %d keys", len(keys))
+	}
+}
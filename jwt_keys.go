@@ -0,0 +1,91 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Supported JWT signing algorithms
+const (
+	algHS256 = "HS256"
+	algRS256 = "RS256"
+	algES256 = "ES256"
+	algEdDSA = "EdDSA"
+)
+
+// isSupportedAlgorithm reports whether alg can be used for token verification
+func isSupportedAlgorithm(alg string) bool {
+	switch alg {
+	case algHS256, algRS256, algES256, algEdDSA:
+		return true
+	}
+	return false
+}
+
+// loadPublicKey reads a PEM public key, given either inline or as a file path,
+// and checks that it matches the signing algorithm
+func loadPublicKey(value, alg string) (any, error) {
+	data := []byte(value)
+	if !strings.HasPrefix(strings.TrimSpace(value), "-----BEGIN") {
+		var err error
+		data, err = os.ReadFile(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read public key file: %w", err)
+		}
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found in public key")
+	}
+
+	var key any
+	var err error
+	switch block.Type {
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		var cert *x509.Certificate
+		cert, err = x509.ParseCertificate(block.Bytes)
+		if err == nil {
+			key = cert.PublicKey
+		}
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %q", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+
+	if err := checkKeyAlgorithm(key, alg); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// checkKeyAlgorithm verifies that a public key can verify tokens signed with alg
+func checkKeyAlgorithm(key any, alg string) error {
+	ok := false
+	switch alg {
+	case algRS256:
+		_, ok = key.(*rsa.PublicKey)
+	case algES256:
+		var ec *ecdsa.PublicKey
+		ec, ok = key.(*ecdsa.PublicKey)
+		ok = ok && ec.Curve.Params().Name == "P-256"
+	case algEdDSA:
+		_, ok = key.(ed25519.PublicKey)
+	}
+	if !ok {
+		return fmt.Errorf("public key type %T does not match algorithm %s", key, alg)
+	}
+	return nil
+}
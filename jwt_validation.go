@@ -0,0 +1,126 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Default JWT validation settings
+const (
+	defaultAuthLeeway         = 60 * time.Second
+	defaultAuthRequiredClaims = "user_id,username,role"
+)
+
+// jwtValidation holds the configurable token checks
+type jwtValidation struct {
+	leeway         time.Duration
+	algorithms     []string
+	requiredClaims []string
+	skipIssuer     bool
+	skipAudience   bool
+}
+
+// errMissingRequiredClaims is returned when a token lacks a required claim
+var errMissingRequiredClaims = errors.New("token missing required claims")
+
+// knownClaims lists the claim names accepted in TIME_AUTH_REQUIRED_CLAIMS
+var knownClaims = []string{"user_id", "username", "role", "sub", "jti", "exp", "iat", "nbf", "iss", "aud"}
+
+// parseRequiredClaims validates a comma-separated claim list
+func parseRequiredClaims(str string) ([]string, error) {
+	claims := parseHeaderList(str, false)
+	for _, name := range claims {
+		if !slices.Contains(knownClaims, name) {
+			return nil, fmt.Errorf("unknown claim %q", name)
+		}
+	}
+	return claims, nil
+}
+
+// parseAlgorithmList validates a comma-separated list of signing algorithms
+func parseAlgorithmList(str string) ([]string, error) {
+	algorithms := parseHeaderList(str, false)
+	for _, alg := range algorithms {
+		if !isSupportedAlgorithm(alg) {
+			return nil, fmt.Errorf("unsupported algorithm %q", alg)
+		}
+	}
+	return algorithms, nil
+}
+
+// parserOptions builds the jwt parser options for the validation settings.
+// A token is accepted when it carries any of the audiences.
+func (v jwtValidation) parserOptions(issuer string, audiences []string, methods []string) []jwt.ParserOption {
+	opts := []jwt.ParserOption{
+		jwt.WithLeeway(v.leeway),
+		jwt.WithValidMethods(methods),
+	}
+	if !v.skipIssuer {
+		opts = append(opts, jwt.WithIssuer(issuer))
+	}
+	if !v.skipAudience {
+		opts = append(opts, jwt.WithAudience(audiences...))
+	}
+	if slices.Contains(v.requiredClaims, "exp") {
+		opts = append(opts, jwt.WithExpirationRequired())
+	}
+	if slices.Contains(v.requiredClaims, "iat") {
+		opts = append(opts, jwt.WithIssuedAt())
+	}
+	return opts
+}
+
+// allowedMethods narrows the algorithms a key source can verify to the
+// configured list
+func (v jwtValidation) allowedMethods(methods []string) ([]string, error) {
+	if len(v.algorithms) == 0 {
+		return methods, nil
+	}
+	var allowed []string
+	for _, alg := range v.algorithms {
+		if slices.Contains(methods, alg) {
+			allowed = append(allowed, alg)
+		}
+	}
+	if len(allowed) == 0 {
+		return nil, fmt.Errorf("none of the accepted algorithms %v can be verified with the configured keys (%v)", v.algorithms, methods)
+	}
+	return allowed, nil
+}
+
+// checkRequiredClaims reports the first required claim missing from claims
+func (v jwtValidation) checkRequiredClaims(claims *Claims) error {
+	for _, name := range v.requiredClaims {
+		var present bool
+		switch name {
+		case "user_id":
+			present = claims.UserID != ""
+		case "username":
+			present = claims.Username != ""
+		case "role":
+			present = claims.Role != ""
+		case "sub":
+			present = claims.Subject != ""
+		case "jti":
+			present = claims.ID != ""
+		case "exp":
+			present = claims.ExpiresAt != nil
+		case "iat":
+			present = claims.IssuedAt != nil
+		case "nbf":
+			present = claims.NotBefore != nil
+		case "iss":
+			present = claims.Issuer != ""
+		case "aud":
+			present = len(claims.Audience) > 0
+		}
+		if !present {
+			return errMissingRequiredClaims
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestJWTValidationSettings(t *testing.T) {
+	const secret = "validation-secret-that-is-at-least-32-chars"
+
+	// A third-party style token: subject only, foreign issuer and audience, issued 90s in the future
+	now := time.Now()
+	foreign := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "svc-42",
+			Issuer:    "https://idp.example.com",
+			Audience:  jwt.ClaimStrings{"other-api"},
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now.Add(90 * time.Second)),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		},
+	})
+	token, err := foreign.SignedString([]byte(secret))
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+
+	testCases := []struct {
+		name       string
+		validation jwtValidation
+		valid      bool
+	}{
+		{
+			name:       "Defaults Reject Foreign Token",
+			validation: jwtValidation{leeway: defaultAuthLeeway},
+			valid:      false,
+		},
+		{
+			name:       "Relaxed Checks Accept Foreign Token",
+			validation: jwtValidation{leeway: 2 * time.Minute, requiredClaims: []string{"sub", "exp"}, skipIssuer: true, skipAudience: true},
+			valid:      true,
+		},
+		{
+			name:       "Leeway Too Small For Not Before",
+			validation: jwtValidation{leeway: time.Second, requiredClaims: []string{"sub"}, skipIssuer: true, skipAudience: true},
+			valid:      false,
+		},
+		{
+			name:       "Missing Required Claim",
+			validation: jwtValidation{leeway: 2 * time.Minute, requiredClaims: []string{"sub", "jti"}, skipIssuer: true, skipAudience: true},
+			valid:      false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := &Config{
+				AuthEnabled:    true,
+				AuthSecretKey:  secret,
+				AuthIssuer:     "TimeMCP",
+				AuthAudience:   "TimeMCP-user",
+				AuthValidation: tc.validation,
+			}
+			auth, err := NewAuthMiddlewareFromConfig(config)
+			if err != nil {
+				t.Fatalf("Failed to create auth middleware: %v", err)
+			}
+			_, err = auth.validateJWT(token)
+			if tc.valid && err != nil {
+				t.Errorf("Expected token to be valid, but got %v", err)
+			}
+			if !tc.valid && err == nil {
+				t.Error("Expected token to be rejected")
+			}
+		})
+	}
+}
+
+func TestJWTValidation_AllowedMethods(t *testing.T) {
+	v := jwtValidation{algorithms: []string{algES256}}
+	if methods, err := v.allowedMethods([]string{algRS256, algES256, algEdDSA}); err != nil || len(methods) != 1 || methods[0] != algES256 {
+		t.Errorf("Expected [ES256], but got %v, %v", methods, err)
+	}
+	if _, err := v.allowedMethods([]string{algHS256}); err == nil {
+		t.Error("Expected an error when no accepted algorithm matches the key source")
+	}
+	if _, err := parseRequiredClaims("sub, email"); err == nil {
+		t.Error("Expected an error for an unknown claim name")
+	}
+}
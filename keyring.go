@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// hmacKey is an HS256 secret identified by the kid header of the tokens it signs
+type hmacKey struct {
+	ID     string
+	Secret []byte
+}
+
+// secretKeyring holds the accepted HS256 secrets. The first key signs new
+// tokens; the rest only verify tokens issued before a rotation.
+type secretKeyring []hmacKey
+
+// keyringPattern matches "kid:secret[,kid:secret...]"
+var keyringPattern = regexp.MustCompile(`^[A-Za-z0-9._-]+:[^,]+(,\s*[A-Za-z0-9._-]+:[^,]+)*$`)
+
+// parseSecretKeys returns the accepted secrets: the TIME_AUTH_SECRET_KEYS
+// keyring when set, else TIME_AUTH_SECRET_KEY as one secret without a kid.
+// The single secret is never split, so secrets containing colons or commas
+// keep working.
+func parseSecretKeys(secret, keyring string) (secretKeyring, error) {
+	keyring = strings.TrimSpace(keyring)
+	if keyring == "" {
+		if secret = strings.TrimSpace(secret); secret == "" {
+			return nil, nil
+		}
+		return secretKeyring{{Secret: []byte(secret)}}, nil
+	}
+	if !keyringPattern.MatchString(keyring) {
+		return nil, fmt.Errorf("expected kid:secret pairs separated by commas")
+	}
+
+	var keys secretKeyring
+	seen := make(map[string]struct{})
+	for _, entry := range strings.Split(keyring, ",") {
+		kid, secret, _ := strings.Cut(strings.TrimSpace(entry), ":")
+		secret = strings.TrimSpace(secret)
+		if secret == "" {
+			return nil, fmt.Errorf("key %q has an empty secret", kid)
+		}
+		if _, dup := seen[kid]; dup {
+			return nil, fmt.Errorf("duplicate key id %q", kid)
+		}
+		seen[kid] = struct{}{}
+		keys = append(keys, hmacKey{ID: kid, Secret: []byte(secret)})
+	}
+	return keys, nil
+}
+
+// current returns the signing key
+func (k secretKeyring) current() hmacKey {
+	if len(k) == 0 {
+		return hmacKey{}
+	}
+	return k[0]
+}
+
+// verificationKey selects the secret for a token's kid. Tokens without a kid,
+// such as those issued before keys were named, are checked against every key.
+func (k secretKeyring) verificationKey(kid string) (any, error) {
+	if kid == "" {
+		set := jwt.VerificationKeySet{}
+		for _, key := range k {
+			set.Keys = append(set.Keys, key.Secret)
+		}
+		return set, nil
+	}
+	for _, key := range k {
+		if key.ID == kid {
+			return key.Secret, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown key id %q", kid)
+}
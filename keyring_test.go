@@ -0,0 +1,122 @@
+package main
+
+import "testing"
+
+func TestParseSecretKeys(t *testing.T) {
+	testCases := []struct {
+		name     string
+		secret   string
+		keyring  string
+		expected []string
+		wantErr  bool
+	}{
+		{"empty", "", "", nil, false},
+		{"single secret", "plain-secret-without-kid", "", []string{""}, false},
+		{"secret with commas", "a,b,c", "", []string{""}, false},
+		// Secrets from before keyrings existed must not be split
+		{"secret with a colon", "app:s3cr3t-that-looks-like-a-kid", "", []string{""}, false},
+		{"keyring", "", "2025-06:new-secret, 2025-01:old-secret", []string{"2025-06", "2025-01"}, false},
+		{"keyring without kids", "", "just-a-secret", nil, true},
+		{"duplicate kid", "", "k1:a,k1:b", nil, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			keys, err := parseSecretKeys(tc.secret, tc.keyring)
+			if tc.wantErr {
+				if err == nil {
+					t.Error("Expected an error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to parse keys: %v", err)
+			}
+			if len(keys) != len(tc.expected) {
+				t.Fatalf("Expected %d keys, but got %d", len(tc.expected), len(keys))
+			}
+			for i, id := range tc.expected {
+				if keys[i].ID != id {
+					t.Errorf("Expected key %d to have ID %q, but got %q", i, id, keys[i].ID)
+				}
+			}
+		})
+	}
+}
+
+func TestSecretKeyRotation(t *testing.T) {
+	const oldSecret = "old-secret-key-that-is-at-least-32-chars"
+	const newSecret = "new-secret-key-that-is-at-least-32-chars"
+
+	// A token issued before keys were named carries no kid
+	legacy, err := NewAuthMiddleware(oldSecret, true, "test-issuer", "test-audience")
+	if err != nil {
+		t.Fatalf("Failed to create auth middleware: %v", err)
+	}
+	legacyToken, err := legacy.GenerateToken("1", "testuser", "user", 1)
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	before, err := newSecretAuthMiddleware("", "v1:"+oldSecret, true, "test-issuer", "test-audience")
+	if err != nil {
+		t.Fatalf("Failed to create auth middleware: %v", err)
+	}
+	oldToken, err := before.GenerateToken("1", "testuser", "user", 1)
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	rotated, err := newSecretAuthMiddleware("", "v2:"+newSecret+",v1:"+oldSecret, true, "test-issuer", "test-audience")
+	if err != nil {
+		t.Fatalf("Failed to create auth middleware: %v", err)
+	}
+	newToken, err := rotated.GenerateToken("1", "testuser", "user", 1)
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	for name, token := range map[string]string{"legacy": legacyToken, "old": oldToken, "new": newToken} {
+		if _, err := rotated.validateJWT(token); err != nil {
+			t.Errorf("Expected %s token to validate after rotation, but got %v", name, err)
+		}
+	}
+
+	// Once the old key is retired, its tokens are rejected
+	retired, err := newSecretAuthMiddleware("", "v2:"+newSecret, true, "test-issuer", "test-audience")
+	if err != nil {
+		t.Fatalf("Failed to create auth middleware: %v", err)
+	}
+	if _, err := retired.validateJWT(oldToken); err == nil {
+		t.Error("Expected token signed with a retired key to be rejected")
+	}
+	if _, err := retired.validateJWT(newToken); err != nil {
+		t.Errorf("Expected new token to validate, but got %v", err)
+	}
+}
+
+func TestLegacySecretWithColon(t *testing.T) {
+	const secret = "app:s3cr3t-key-that-is-at-least-32-chars"
+	// Tokens issued by a release that read the whole value as the secret
+	issued, err := NewAuthMiddleware(secret, true, "test-issuer", "test-audience")
+	if err != nil {
+		t.Fatalf("Failed to create auth middleware: %v", err)
+	}
+	token, err := issued.GenerateToken("1", "testuser", "user", 1)
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	upgraded, err := NewAuthMiddlewareFromConfig(&Config{
+		AuthEnabled:   true,
+		AuthSecretKey: secret,
+		AuthIssuer:    "test-issuer",
+		AuthAudience:  "test-audience",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create auth middleware: %v", err)
+	}
+	if _, err := upgraded.validateJWT(token); err != nil {
+		t.Errorf("Expected a token signed with a colon-containing secret to keep validating, but got %v", err)
+	}
+}
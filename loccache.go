@@ -0,0 +1,30 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// locationCache memoizes time.LoadLocation, which reads and parses zoneinfo
+// from disk on every call. Only successful lookups are cached, so the cache
+// is bounded by the size of the timezone database regardless of input.
+var locationCache sync.Map // name -> *time.Location
+
+// loadLocationCached returns the location for name, loading it at most once
+// per process for valid names. Names that are not in the timezone database
+// may be POSIX TZ strings, which are built on every call instead so that
+// arbitrary input cannot grow the cache.
+func loadLocationCached(name string) (*time.Location, error) {
+	if loc, ok := locationCache.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		if tz, posixErr := parsePosixTZ(name); posixErr == nil {
+			return tz.location(name)
+		}
+		return nil, err
+	}
+	actual, _ := locationCache.LoadOrStore(name, loc)
+	return actual.(*time.Location), nil
+}
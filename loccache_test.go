@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLoadLocationCached(t *testing.T) {
+	first, err := loadLocationCached("Europe/Warsaw")
+	if err != nil {
+		t.Fatalf("Failed to load location: %v", err)
+	}
+	second, err := loadLocationCached("Europe/Warsaw")
+	if err != nil {
+		t.Fatalf("Failed to load location: %v", err)
+	}
+	if first != second {
+		t.Errorf("Expected the cached location to be reused")
+	}
+
+	if _, err := loadLocationCached("Mars/Olympus_Mons"); err == nil {
+		t.Errorf("Expected an error for an unknown timezone")
+	}
+	if _, cached := locationCache.Load("Mars/Olympus_Mons"); cached {
+		t.Errorf("Expected failed lookups not to be cached")
+	}
+}
+
+// benchmarkZones mimics a realistic request mix dominated by a few zones
+var benchmarkZones = []string{"America/New_York", "Europe/London", "Asia/Tokyo", "UTC", "Europe/Warsaw", "Australia/Sydney"}
+
+// BenchmarkLoadTimezone_Uncached and BenchmarkLoadTimezone_Cached run lookups
+// from parallel goroutines, as concurrent HTTP tool calls would:
+//
+//	go test -bench LoadTimezone -cpu 1,8
+func BenchmarkLoadTimezone_Uncached(b *testing.B) {
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			if _, err := time.LoadLocation(benchmarkZones[i%len(benchmarkZones)]); err != nil {
+				b.Fatal(err)
+			}
+			i++
+		}
+	})
+}
+
+func BenchmarkLoadTimezone_Cached(b *testing.B) {
+	config := &Config{}
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			if _, err := loadTimezone(context.Background(), benchmarkZones[i%len(benchmarkZones)], config); err != nil {
+				b.Fatal(err)
+			}
+			i++
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// Log formats
+const (
+	logFormatText = "text"
+	logFormatJSON = "json"
+)
+
+// logLevel is shared by all handlers so reloads can change it in place
+var logLevel = new(slog.LevelVar)
+
+// parseLogLevel parses debug, info, warn (or warning) and error
+func parseLogLevel(str string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(str)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("unknown log level %q (expected debug, info, warn or error)", str)
+}
+
+// setupLogging installs the default slog logger writing to stderr, which
+// keeps stdout free for the stdio transport
+func setupLogging(level slog.Level, format string) {
+	logLevel.Set(level)
+	slog.SetDefault(slog.New(newLogHandler(os.Stderr, format)))
+}
+
+func newLogHandler(w io.Writer, format string) slog.Handler {
+	opts := &slog.HandlerOptions{Level: logLevel, ReplaceAttr: redactAttr}
+	if format == logFormatJSON {
+		return contextHandler{slog.NewJSONHandler(w, opts)}
+	}
+	return contextHandler{slog.NewTextHandler(w, opts)}
+}
+
+// contextHandler adds the request ID and caller identity from the context to
+// records logged with the *Context functions, so related logs share fields.
+// It also redacts credentials from messages, including lines written by
+// libraries through the standard log package.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if ctx != nil {
+		if requestID := getRequestID(ctx); requestID != "" {
+			r.AddAttrs(slog.String("request_id", requestID))
+		}
+		if userID, _, _ := getUserInfo(ctx); userID != "" {
+			r.AddAttrs(slog.String("user_id", userID))
+		}
+	}
+	return h.Handler.Handle(ctx, redactRecord(r))
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
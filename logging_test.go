@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func TestParseLogLevel(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected slog.Level
+		wantErr  bool
+	}{
+		{"", slog.LevelInfo, false},
+		{"debug", slog.LevelDebug, false},
+		{"INFO", slog.LevelInfo, false},
+		{"warning", slog.LevelWarn, false},
+		{"error", slog.LevelError, false},
+		{"verbose", 0, true},
+	}
+	for _, tt := range tests {
+		level, err := parseLogLevel(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseLogLevel(%q): expected error %v but got %v", tt.input, tt.wantErr, err)
+			continue
+		}
+		if !tt.wantErr && level != tt.expected {
+			t.Errorf("parseLogLevel(%q): expected %v but got %v", tt.input, tt.expected, level)
+		}
+	}
+}
+
+func TestLogHandler_JSONWithContextFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(newLogHandler(&buf, logFormatJSON))
+
+	ctx := context.WithValue(context.Background(), userIDKey, "user1")
+	logger.InfoContext(ctx, "Tool call completed", "tool", "get_current_time")
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("Failed to decode JSON log line %q: %v", buf.String(), err)
+	}
+	if record["msg"] != "Tool call completed" || record["tool"] != "get_current_time" || record["user_id"] != "user1" {
+		t.Errorf("Expected msg, tool and user_id fields but got %v", record)
+	}
+
+	buf.Reset()
+	logger.Debug("hidden")
+	if buf.Len() != 0 {
+		t.Errorf("Expected debug records to be dropped at info level, but got %q", buf.String())
+	}
+}
@@ -2,9 +2,10 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"time"
 
@@ -13,33 +14,78 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 )
 
-// loadTimezone loads a timezone location, using config default or system timezone if empty
-func loadTimezone(tzStr string, config *Config) (*time.Location, error) {
+// loadTimezone loads a timezone location. An empty name falls back to the
+// timezone the client declared for its session, then the zone detected for
+// the HTTP client, then the caller's tenant default, then the config default,
+// then the system timezone.
+func loadTimezone(ctx context.Context, tzStr string, config *Config) (*time.Location, error) {
 	if tzStr == "" {
+		if zone, ok := sessionTimezone(ctx); ok {
+			return loadLocationCached(zone)
+		}
+		if detected, ok := clientTimezone(ctx); ok {
+			return loadLocationCached(detected.Zone)
+		}
+		if t := tenantFromContext(ctx); t != nil && t.DefaultTimezone != "" {
+			return loadLocationCached(t.DefaultTimezone)
+		}
 		// Use default timezone from config if available
 		if config.DefaultTimezone != "" {
-			return time.LoadLocation(config.DefaultTimezone)
+			return loadLocationCached(config.DefaultTimezone)
 		}
 		// Fall back to system timezone
 		return time.Local, nil
 	}
-	return time.LoadLocation(tzStr)
+	return loadLocationCached(tzStr)
 }
 
 func main() {
 	if err := run(); err != nil {
-		log.Printf("Error: %v\n", err)
+		slog.Error("Fatal error", "error", err)
 		os.Exit(1)
 	}
 }
 
 func run() error {
-	transportFlag, authEnabledFlag, generateTokenFlag, tokenUserIDFlag, tokenUsernameFlag, tokenRoleFlag, tokenExpirationFlag := setupFlags()
+	flags := setupFlags()
 
-	if *generateTokenFlag {
-		secretKey := os.Getenv("TIME_AUTH_SECRET_KEY")
-		CreateTokenCommand(secretKey, *tokenUserIDFlag, *tokenUsernameFlag, *tokenRoleFlag, *tokenExpirationFlag)
-		return nil
+	if flags.envFile != "" {
+		if err := loadEnvFile(flags.envFile); err != nil {
+			return fmt.Errorf("configuration error: %w", err)
+		}
+		if flags.configFile == "" {
+			flags.configFile = os.Getenv("TIME_CONFIG_FILE")
+		}
+	}
+
+	if flags.configFile != "" {
+		if err := applyConfigFile(flags.configFile); err != nil {
+			return fmt.Errorf("configuration error: %w", err)
+		}
+	}
+
+	// Set up logging before NewConfig so configuration warnings are structured;
+	// invalid values are reported by NewConfig below
+	if level, format, _, err := parseLogSettings(); err == nil {
+		setupLogging(level, format)
+	}
+
+	if flags.generateToken {
+		return CreateTokenCommand(os.Getenv("TIME_AUTH_SECRET_KEY"), os.Getenv("TIME_AUTH_SECRET_KEYS"), tokenOptions{
+			UserID:     flags.tokenUserID,
+			Username:   flags.tokenUsername,
+			Role:       flags.tokenRole,
+			Expiration: time.Duration(flags.tokenExpiration) * time.Hour,
+			NotBefore:  flags.tokenNotBefore,
+			Issuer:     flags.tokenIssuer,
+			Audience:   flags.tokenAudience,
+			Scopes:     parseScopes(flags.tokenScopes),
+			Extra:      flags.tokenClaims,
+		}, flags.tokenJSON)
+	}
+
+	if flags.revokeToken != "" {
+		return RevokeTokenCommand(os.Getenv("TIME_AUTH_DENYLIST_FILE"), flags.revokeToken)
 	}
 
 	config, err := NewConfig()
@@ -47,34 +93,203 @@ func run() error {
 		return fmt.Errorf("configuration error: %w", err)
 	}
 
-	if *authEnabledFlag {
+	if flags.authEnabled {
 		config.AuthEnabled = true
-		log.Println("Authentication feature enabled via command line flag")
+		slog.Info("Authentication feature enabled via command line flag")
+	}
+
+	setLogSecrets(config)
+
+	if flags.validateConfig {
+		return ValidateConfigCommand(config)
+	}
+	if flags.printConfig {
+		return PrintConfigCommand(config)
+	}
+
+	if err := fetchDatasets(context.Background(), config); err != nil {
+		return fmt.Errorf("configuration error: %w", err)
+	}
+
+	if err := prepareTimezones(config); err != nil {
+		return fmt.Errorf("configuration error: %w", err)
+	}
+	if len(flags.command) > 0 {
+		return ToolCommand(config, flags.command, os.Stdout)
+	}
+
+	store, err := newStateStore(config)
+	if err != nil {
+		return fmt.Errorf("configuration error: %w", err)
+	}
+	defer func() {
+		if err := store.close(); err != nil {
+			slog.Warn("Failed to close state store", "error", err)
+		}
+	}()
+
+	if config.OTelEnabled {
+		shutdownTracing, err := setupTracing(context.Background(), config)
+		if err != nil {
+			return fmt.Errorf("configuration error: %w", err)
+		}
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := shutdownTracing(ctx); err != nil {
+				slog.Warn("Failed to flush traces", "error", err)
+			}
+		}()
+	}
+
+	reloader := newConfigReloader(config, flags.configFile, func(c *Config) {
+		if flags.authEnabled {
+			c.AuthEnabled = true
+		}
+	})
+	reloader.onReload("logging", func(c *Config) error {
+		logLevel.Set(c.LogLevel)
+		setLogSecrets(c)
+		return nil
+	})
+	reloader.watchReloadSignal()
+	if len(config.DatasetURLs) > 0 {
+		startDatasetRefresh(reloader.Load, config.DatasetRefresh)
 	}
 
+	hooks := &server.Hooks{}
+	hooks.AddAfterInitialize(brandInitializeResult)
+	hooks.AddAfterInitialize(acknowledgeClientHints)
+	// Stateful HTTP and WebSocket sessions are tracked for the admin session tools
+	var sessions *sessionRegistry
+	if (flags.transport == "http" && !config.HTTPStateless) || flags.transport == "ws" {
+		sessions = newSessionRegistry(config.HTTPSessionLifetime)
+		sessions.install(hooks)
+	}
 	mcpServer := server.NewMCPServer(
-		"TimeMCP",
-		"1.0.0",
+		config.ServerName,
+		config.ServerVersion,
 		server.WithToolCapabilities(true),
-		server.WithInstructions("Time conversion and timezone utilities."),
+		server.WithInstructions(config.ServerInstructions),
+		server.WithToolFilter(hideDeprecatedToolsFilter(reloader.Load)),
+		server.WithToolFilter(permittedToolsFilter(reloader.Load)),
+		server.WithHooks(hooks),
 	)
 
-	mcpServer.Use(loggingMiddleware(), authMiddleware(config))
+	stats := newServerStats(config.StatsFile)
+	stats.startFlushing(config.StatsFlushInterval)
+	defer func() {
+		if err := stats.flush(); err != nil {
+			slog.Warn("Failed to flush statistics", "file", config.StatsFile, "error", err)
+		}
+	}()
+	var quotas *quotaTracker
+	if config.QuotaPerMinute > 0 || config.QuotaPerDay > 0 {
+		quotas = newQuotaTracker(store, config.QuotaPerMinute, config.QuotaPerDay)
+		reloader.onReload("quotas", func(c *Config) error {
+			quotas.setLimits(c.QuotaPerMinute, c.QuotaPerDay)
+			return nil
+		})
+	}
+	chain := newToolMiddlewareChain(reloader.Load, stats, quotas)
+	chain.install(mcpServer)
+	if config.ToolPluginsDir != "" {
+		if err := loadToolPlugins(config.ToolPluginsDir); err != nil {
+			return fmt.Errorf("configuration error: %w", err)
+		}
+	}
 	addTools(mcpServer, config)
+	if quotas != nil {
+		addUsageTool(mcpServer, quotas)
+	}
+	addStatsTool(mcpServer, config, stats)
+	addNotificationScheduleTool(mcpServer, newPreferenceStore(store), config)
+	addDeadlineTools(mcpServer, newDeadlineTracker(store, config), config)
+	if config.SchedulerEnabled {
+		sched := newScheduler(store, mcpServer, config)
+		sched.start()
+		defer sched.close()
+		addSchedulerTools(mcpServer, sched, config)
+	}
+	clockSteps := newClockStepMonitor(reloader.Load, mcpServer, stats)
+	// Stateless HTTP sessions end with each request, so ticks could not reach them
+	if flags.transport != "http" || !config.HTTPStateless {
+		clock := newClockTicker(mcpServer)
+		defer clock.close()
+		addClockTools(mcpServer, clock, config)
+		clockSteps.onStep = append(clockSteps.onStep, clock.rearm)
+	}
+	clockSteps.start()
+	if sessions != nil {
+		addSessionTools(mcpServer, sessions, config)
+	}
+	addPingTool(mcpServer, sessions, config)
+	// Detection reads HTTP request metadata, which stdio does not have
+	if flags.transport != "stdio" && len(config.ClientTZDetect) > 0 {
+		addClientTimezoneTool(mcpServer, config)
+	}
+	addBatchTool(mcpServer, chain)
+	// Registered last so clashes with any built-in tool are detected
+	if err := addRegisteredTools(mcpServer, config); err != nil {
+		return fmt.Errorf("configuration error: %w", err)
+	}
 
-	return startServer(mcpServer, config, transportFlag)
+	if config.DebugEndpoints {
+		if err := startDebugServer(config.DebugAddress, stats); err != nil {
+			return err
+		}
+	}
+
+	return startServer(mcpServer, reloader, stats, store, sessions, flags.transport)
 }
 
-func setupFlags() (*string, *bool, *bool, *string, *string, *string, *int) {
-	transportFlag := flag.String("transport", "stdio", "Transport mode: 'stdio' (default) or 'http'")
-	authEnabledFlag := flag.Bool("auth-enabled", false, "Enable JWT authentication for HTTP transport")
-	generateTokenFlag := flag.Bool("generate-token", false, "Generate a JWT token and exit")
-	tokenUserIDFlag := flag.String("token-user-id", "user1", "User ID for token generation")
-	tokenUsernameFlag := flag.String("token-username", "admin", "Username for token generation")
-	tokenRoleFlag := flag.String("token-role", "admin", "Role for token generation")
-	tokenExpirationFlag := flag.Int("token-expiration", 744, "Token expiration in hours (default: 744 = 31 days)")
+// cliFlags holds the parsed command line flags
+type cliFlags struct {
+	transport       string
+	configFile      string
+	envFile         string
+	authEnabled     bool
+	generateToken   bool
+	tokenUserID     string
+	tokenUsername   string
+	tokenRole       string
+	tokenExpiration int
+	tokenNotBefore  time.Duration
+	tokenIssuer     string
+	tokenAudience   string
+	tokenScopes     string
+	tokenClaims     claimFlag
+	tokenJSON       bool
+	revokeToken     string
+	validateConfig  bool
+	printConfig     bool
+	// Subcommand and its arguments, e.g. ["now", "--tz", "UTC"]; see ToolCommand
+	command []string
+}
+
+func setupFlags() *cliFlags {
+	flags := &cliFlags{tokenClaims: claimFlag{}}
+	flag.StringVar(&flags.transport, "transport", "stdio", "Transport mode: 'stdio' (default), 'http' or 'ws' (WebSocket)")
+	flag.StringVar(&flags.configFile, "config", os.Getenv("TIME_CONFIG_FILE"), "Path to a YAML or TOML config file; environment variables take precedence")
+	flag.StringVar(&flags.envFile, "env-file", os.Getenv("TIME_ENV_FILE"), "Path to a .env file for local development; existing environment variables take precedence (default: none)")
+	flag.BoolVar(&flags.authEnabled, "auth-enabled", false, "Enable JWT authentication for HTTP transport")
+	flag.BoolVar(&flags.generateToken, "generate-token", false, "Generate a JWT token and exit")
+	flag.StringVar(&flags.tokenUserID, "token-user-id", "user1", "User ID for token generation")
+	flag.StringVar(&flags.tokenUsername, "token-username", "admin", "Username for token generation")
+	flag.StringVar(&flags.tokenRole, "token-role", "admin", "Role for token generation")
+	flag.IntVar(&flags.tokenExpiration, "token-expiration", 744, "Token expiration in hours (default: 744 = 31 days)")
+	flag.DurationVar(&flags.tokenNotBefore, "token-not-before", 0, "Delay before the token becomes valid (e.g. 10m)")
+	flag.StringVar(&flags.tokenIssuer, "token-issuer", getEnvWithDefault("TIME_AUTH_ISSUER", defaultAuthIssuer), "Issuer (iss) for token generation")
+	flag.StringVar(&flags.tokenAudience, "token-audience", getEnvWithDefault("TIME_AUTH_AUDIENCE", defaultAuthAudience), "Audience (aud) for token generation")
+	flag.StringVar(&flags.tokenScopes, "token-scopes", "", "Comma- or space-separated scopes for the scope claim")
+	flag.Var(flags.tokenClaims, "token-claim", "Extra claim as key=value; repeatable, JSON values keep their type")
+	flag.BoolVar(&flags.tokenJSON, "json", false, "Print the generated token and its claims as JSON")
+	flag.StringVar(&flags.revokeToken, "revoke-token", "", "Revoke a token (JWT or jti) by adding it to TIME_AUTH_DENYLIST_FILE and exit")
+	flag.BoolVar(&flags.validateConfig, "validate-config", false, "Validate the configuration and exit")
+	flag.BoolVar(&flags.printConfig, "print-config", false, "Print the effective configuration as JSON with secrets masked and exit")
 	flag.Parse()
-	return transportFlag, authEnabledFlag, generateTokenFlag, tokenUserIDFlag, tokenUsernameFlag, tokenRoleFlag, tokenExpirationFlag
+	flags.command = flag.Args()
+	return flags
 }
 
 func addTools(mcpServer *server.MCPServer, config *Config) {
@@ -94,7 +309,7 @@ func addTools(mcpServer *server.MCPServer, config *Config) {
 	)
 
 	mcpServer.AddTool(
-		mcp.NewTool("convert_time",
+		withDeprecation(mcp.NewTool("convert_time",
 			mcp.WithDescription("Convert time between timezones."),
 			mcp.WithString("source_timezone",
 				mcp.Description("Source timezone. Defaults to system timezone if not provided."),
@@ -113,24 +328,96 @@ func addTools(mcpServer *server.MCPServer, config *Config) {
 			mcp.WithDestructiveHintAnnotation(false),
 			mcp.WithIdempotentHintAnnotation(true),
 			mcp.WithOpenWorldHintAnnotation(false),
-		),
+		)),
 		handleConvertTime(config),
 	)
+
+	mcpServer.AddTool(
+		mcp.NewTool("convert_time_v2",
+			mcp.WithDescription("Convert time between timezones. Returns structured output with both times in RFC 3339, their UTC offsets, zone abbreviations and DST flags, and the offset difference."),
+			mcp.WithString("source_timezone",
+				mcp.Description("Source timezone. Defaults to system timezone if not provided."),
+				mcp.DefaultString(""),
+			),
+			mcp.WithString("time",
+				mcp.Description("Time in 24-hour format (HH:MM). Defaults to current time if not provided."),
+				mcp.DefaultString(""),
+			),
+			mcp.WithString("target_timezone",
+				mcp.Description("Target timezone to convert the time to."),
+				mcp.Required(),
+			),
+			mcp.WithOutputSchema[timeConversion](),
+			mcp.WithTitleAnnotation("Convert Time Between Timezones"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		handleConvertTimeV2(config),
+	)
+	addICalTools(mcpServer, config)
+	addVTimezoneTool(mcpServer, config)
+	addDSTCalendarTool(mcpServer, config)
+	addSolarScheduleTool(mcpServer, config)
+	addCronTools(mcpServer, config)
+	addWindowTool(mcpServer, config)
+	addHeatmapTool(mcpServer, config)
+	addDateLineTool(mcpServer, config)
+	addYearCalendarTool(mcpServer, config)
+	addTimesheetTool(mcpServer, config)
+	addIntervalConvertTool(mcpServer)
+	addTimelineTool(mcpServer, config)
+	addIncidentMetricsTool(mcpServer, config)
+	addParseScheduleTool(mcpServer, config)
+	addDateFactsTool(mcpServer, config)
+	addCompareCountdownsTool(mcpServer, config)
+	addWeekParityTool(mcpServer, config)
+	addDaySchedulePreviewTool(mcpServer, config)
+	addDateAddTool(mcpServer, config)
+	addBucketTimeTool(mcpServer, config)
+	addParsePosixTZTool(mcpServer, config)
+	addAbbreviationHistoryTool(mcpServer, config)
+	addResolveDeadlinePhraseTool(mcpServer, config)
+	addAnnotateTimesTool(mcpServer, config)
+
+	if len(config.Calendars) > 0 {
+		addCalendarTools(mcpServer, config)
+	}
+	if len(config.Terms) > 0 {
+		addAcademicTermTool(mcpServer, config)
+	}
+	if config.AuthDenylist != nil {
+		addAdminTools(mcpServer, config)
+	}
+	if len(config.datasets().Sources.files()) > 0 {
+		addDatasetTools(mcpServer, config)
+	}
+	addServerInfoTool(mcpServer, config)
 }
 
-func startServer(mcpServer *server.MCPServer, config *Config, transportFlag *string) error {
-	if *transportFlag != "stdio" && *transportFlag != "http" {
-		return fmt.Errorf("invalid transport mode: %s. Must be 'stdio' or 'http'", *transportFlag)
+func startServer(mcpServer *server.MCPServer, reloader *configReloader, stats *serverStats, store stateStore, sessions *sessionRegistry, transport string) error {
+	config := reloader.Load()
+	if transport != "stdio" && transport != "http" && transport != "ws" {
+		return fmt.Errorf("invalid transport mode: %s. Must be 'stdio', 'http' or 'ws'", transport)
 	}
 
-	if *transportFlag == "http" {
-		log.Printf("Starting TimeMCP server with HTTP transport on %s%s\n", config.HTTPAddress, config.HTTPPath)
-		if err := startHTTPServer(mcpServer, config); err != nil {
+	if transport != "stdio" {
+		name := "HTTP"
+		if transport == "ws" {
+			name = "WebSocket"
+		}
+		slog.Info("Starting TimeMCP server with "+name+" transport", "address", config.HTTPAddress, "path", config.HTTPPath)
+		if err := startHTTPServer(mcpServer, reloader, stats, store, sessions, transport); err != nil {
 			return fmt.Errorf("HTTP server error: %w", err)
 		}
 	} else {
-		log.Println("Starting TimeMCP server with stdio transport...")
-		if err := server.ServeStdio(mcpServer); err != nil {
+		slog.Info("Starting TimeMCP server with stdio transport")
+		opts, err := stdioAuthOptions(config)
+		if err != nil {
+			return err
+		}
+		if err := serveStdio(mcpServer, reloader.Load, opts...); err != nil {
 			return fmt.Errorf("error starting server: %w", err)
 		}
 	}
@@ -142,14 +429,19 @@ func loggingMiddleware() server.ToolHandlerMiddleware {
 	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
 		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			toolName := req.Params.Name
-			log.Printf("Calling tool '%s'...", toolName)
+			slog.DebugContext(ctx, "Calling tool", "tool", toolName)
+			start := time.Now()
 
 			result, err := next(ctx, req)
 
-			if err != nil {
-				log.Printf("Tool '%s' failed: %v", toolName, err)
-			} else {
-				log.Printf("Tool '%s' completed successfully", toolName)
+			durationMS := float64(time.Since(start).Microseconds()) / 1000
+			switch {
+			case err != nil:
+				slog.ErrorContext(ctx, "Tool call failed", "tool", toolName, "duration_ms", durationMS, "error", err)
+			case result != nil && result.IsError:
+				slog.InfoContext(ctx, "Tool call returned an error", "tool", toolName, "duration_ms", durationMS, "code", toolErrorCodeOf(result), "error", toolResultText(result))
+			default:
+				slog.InfoContext(ctx, "Tool call completed", "tool", toolName, "duration_ms", durationMS)
 			}
 
 			return result, err
@@ -157,11 +449,11 @@ func loggingMiddleware() server.ToolHandlerMiddleware {
 	}
 }
 
-// authMiddleware checks authentication for HTTP requests
-func authMiddleware(config *Config) server.ToolHandlerMiddleware {
+// authMiddleware checks authentication for HTTP requests against the current configuration
+func authMiddleware(config func() *Config) server.ToolHandlerMiddleware {
 	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
 		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			if authErr := checkAuth(ctx, req.Params.Name, config); authErr != nil {
+			if authErr := checkAuth(ctx, req.Params.Name, config()); authErr != nil {
 				return authErr, nil
 			}
 			return next(ctx, req)
@@ -170,27 +462,35 @@ func authMiddleware(config *Config) server.ToolHandlerMiddleware {
 }
 
 func checkAuth(ctx context.Context, toolName string, config *Config) *mcp.CallToolResult {
-	httpMethod, ok := ctx.Value(httpMethodKey).(string)
-	if !ok || httpMethod == "" {
-		return nil // Not an HTTP request, no auth check
-	}
-
-	if !config.AuthEnabled {
-		return nil // Auth is not enabled
-	}
-
-	if authError := getAuthError(ctx); authError != "" {
-		log.Printf("Authentication failed for tool '%s': %s", toolName, authError)
-		return mcp.NewToolResultError(fmt.Sprintf("Authentication required: %s", authError))
+	userID, username, role := getUserInfo(ctx)
+	switch contextToolAccess(ctx, toolName, config) {
+	case toolUnrestricted:
+		return nil // Unauthenticated stdio or auth not enabled
+	case toolAllowedAnonymously:
+		// Requests without credentials may use the anonymous tier; a bad token is still rejected
+		slog.InfoContext(ctx, "Tool called anonymously", "tool", toolName)
+		return nil
+	case toolUnauthenticated:
+		if authError := getAuthError(ctx); authError != "" {
+			slog.WarnContext(ctx, "Authentication failed", "tool", toolName, "error", authError)
+			return toolError(errorUnauthorized, fmt.Sprintf("Authentication required: %s", authError))
+		}
+		slog.WarnContext(ctx, "Authentication required but not provided", "tool", toolName)
+		return toolError(errorUnauthorized, "Authentication required")
+	case toolDeniedByRole:
+		slog.WarnContext(ctx, "Tool call denied by role policy", "tool", toolName, "username", username, "role", role)
+		return toolError(errorForbidden, fmt.Sprintf("Forbidden: role '%s' is not allowed to call tool '%s'", role, toolName))
+	case toolDeniedByTenant:
+		t := tenantFromContext(ctx)
+		slog.WarnContext(ctx, "Tool call denied by tenant policy", "tool", toolName, "username", username, "tenant", t.Name)
+		return toolError(errorForbidden, fmt.Sprintf("Forbidden: tenant '%s' is not allowed to call tool '%s'", t.Name, toolName))
 	}
 
-	if !isAuthenticated(ctx) {
-		log.Printf("Authentication required for tool '%s' but not provided", toolName)
-		return mcp.NewToolResultError("Authentication required")
+	if subject := getClientCertSubject(ctx); subject != "" && subject == userID {
+		slog.DebugContext(ctx, "Tool called by client certificate", "tool", toolName, "subject", subject, "role", role)
+		return nil
 	}
-
-	userID, username, role := getUserInfo(ctx)
-	log.Printf("Tool '%s' called by authenticated user %s (%s) with role %s", toolName, username, userID, role)
+	slog.DebugContext(ctx, "Tool called by authenticated user", "tool", toolName, "username", username, "role", role)
 	return nil
 }
 
@@ -199,12 +499,12 @@ func handleGetCurrentTime(config *Config) server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		timezoneStr := request.GetString("timezone", "")
 
-		loc, err := loadTimezone(timezoneStr, config)
+		loc, err := loadTimezone(ctx, timezoneStr, config)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Invalid timezone: %s", timezoneStr)), nil
+			return invalidTimezoneError("Invalid timezone", timezoneStr), nil
 		}
 
-		now := time.Now().In(loc)
+		now := config.now().In(loc)
 		response := fmt.Sprintf("Current time in %s (%s, UTC%s): %s",
 			loc.String(),
 			now.Format("MST"),
@@ -219,49 +519,11 @@ func handleGetCurrentTime(config *Config) server.ToolHandlerFunc {
 // handleConvertTime returns a handler for the convert_time tool
 func handleConvertTime(config *Config) server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		sourceTimezoneStr := request.GetString("source_timezone", "")
-		timeStr := request.GetString("time", "")
-
-		targetTimezoneStr, err := request.RequireString("target_timezone")
-		if err != nil {
-			return mcp.NewToolResultError(err.Error()), nil
-		}
-
-		// Set source timezone
-		sourceLoc, err := loadTimezone(sourceTimezoneStr, config)
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Invalid source timezone: %s", sourceTimezoneStr)), nil
-		}
-		if sourceTimezoneStr == "" {
-			sourceTimezoneStr = sourceLoc.String()
+		sourceTime, targetTime, sourceTimezoneStr, targetTimezoneStr, errResult := resolveConversion(ctx, request, config)
+		if errResult != nil {
+			return errResult, nil
 		}
 
-		// Set target timezone
-		targetLoc, err := loadTimezone(targetTimezoneStr, config)
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Invalid target timezone: %s", targetTimezoneStr)), nil
-		}
-
-		// Determine the time to convert
-		var sourceTime time.Time
-		if timeStr == "" {
-			// Use current time if not provided
-			sourceTime = time.Now().In(sourceLoc)
-		} else {
-			// Parse the provided time
-			// We'll construct a full datetime string with today's date
-			today := time.Now().In(sourceLoc).Format("2006-01-02")
-			fullTimeStr := fmt.Sprintf("%s %s", today, timeStr)
-
-			sourceTime, err = dateparse.ParseIn(fullTimeStr, sourceLoc)
-			if err != nil {
-				return mcp.NewToolResultError(fmt.Sprintf("Invalid time format: %s. Please provide time in HH:MM format.", timeStr)), nil
-			}
-		}
-
-		// Convert to target timezone
-		targetTime := sourceTime.In(targetLoc)
-
 		response := fmt.Sprintf(
 			"Time conversion: %s in %s → %s in %s",
 			sourceTime.Format("2006-01-02 15:04"),
@@ -273,3 +535,114 @@ func handleConvertTime(config *Config) server.ToolHandlerFunc {
 		return mcp.NewToolResultText(response), nil
 	}
 }
+
+// convertedTime is one side of a convert_time_v2 result
+type convertedTime struct {
+	Timezone     string `json:"timezone" jsonschema:"IANA timezone name"`
+	Datetime     string `json:"datetime" jsonschema:"RFC 3339 date and time"`
+	Abbreviation string `json:"abbreviation" jsonschema:"Zone abbreviation such as CET or EDT"`
+	UTCOffset    string `json:"utc_offset" jsonschema:"Offset from UTC such as +02:00"`
+	IsDST        bool   `json:"is_dst" jsonschema:"Whether daylight saving time is in effect"`
+}
+
+// timeConversion is the structured result of convert_time_v2
+type timeConversion struct {
+	Source         convertedTime `json:"source"`
+	Target         convertedTime `json:"target"`
+	TimeDifference string        `json:"time_difference" jsonschema:"Target offset relative to the source, such as +7h or -5h30m"`
+}
+
+func newConvertedTime(t time.Time, timezone string) convertedTime {
+	return convertedTime{
+		Timezone:     timezone,
+		Datetime:     t.Format(time.RFC3339),
+		Abbreviation: t.Format("MST"),
+		UTCOffset:    t.Format("-07:00"),
+		IsDST:        t.IsDST(),
+	}
+}
+
+// formatOffsetDifference formats the difference between two UTC offsets, in seconds
+func formatOffsetDifference(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign = "-"
+		seconds = -seconds
+	}
+	d := time.Duration(seconds) * time.Second
+	hours, minutes := int(d.Hours()), int(d.Minutes())%60
+	if minutes == 0 {
+		return fmt.Sprintf("%s%dh", sign, hours)
+	}
+	return fmt.Sprintf("%s%dh%dm", sign, hours, minutes)
+}
+
+// handleConvertTimeV2 returns a handler for the convert_time_v2 tool
+func handleConvertTimeV2(config *Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		sourceTime, targetTime, sourceTimezoneStr, targetTimezoneStr, errResult := resolveConversion(ctx, request, config)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		_, sourceOffset := sourceTime.Zone()
+		_, targetOffset := targetTime.Zone()
+		conversion := timeConversion{
+			Source:         newConvertedTime(sourceTime, sourceTimezoneStr),
+			Target:         newConvertedTime(targetTime, targetTimezoneStr),
+			TimeDifference: formatOffsetDifference(targetOffset - sourceOffset),
+		}
+		data, err := json.Marshal(conversion)
+		if err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to encode conversion: %v", err)), nil
+		}
+		return mcp.NewToolResultStructured(conversion, string(data)), nil
+	}
+}
+
+// resolveConversion parses convert_time arguments shared by all versions and
+// returns the source and target times with the timezone names to report
+func resolveConversion(ctx context.Context, request mcp.CallToolRequest, config *Config) (time.Time, time.Time, string, string, *mcp.CallToolResult) {
+	sourceTimezoneStr := request.GetString("source_timezone", "")
+	timeStr := request.GetString("time", "")
+
+	targetTimezoneStr, err := request.RequireString("target_timezone")
+	if err != nil {
+		return time.Time{}, time.Time{}, "", "", toolError(errorInvalidArgument, err.Error())
+	}
+
+	// Set source timezone
+	sourceLoc, err := loadTimezone(ctx, sourceTimezoneStr, config)
+	if err != nil {
+		return time.Time{}, time.Time{}, "", "", invalidTimezoneError("Invalid source timezone", sourceTimezoneStr)
+	}
+	if sourceTimezoneStr == "" {
+		sourceTimezoneStr = sourceLoc.String()
+	}
+
+	// Set target timezone
+	targetLoc, err := loadTimezone(ctx, targetTimezoneStr, config)
+	if err != nil {
+		return time.Time{}, time.Time{}, "", "", invalidTimezoneError("Invalid target timezone", targetTimezoneStr)
+	}
+
+	// Determine the time to convert
+	var sourceTime time.Time
+	if timeStr == "" {
+		// Use current time if not provided
+		sourceTime = config.now().In(sourceLoc)
+	} else {
+		// Parse the provided time
+		// We'll construct a full datetime string with today's date
+		today := config.now().In(sourceLoc).Format("2006-01-02")
+		fullTimeStr := fmt.Sprintf("%s %s", today, timeStr)
+
+		sourceTime, err = dateparse.ParseIn(fullTimeStr, sourceLoc)
+		if err != nil {
+			return time.Time{}, time.Time{}, "", "", toolError(errorParse, fmt.Sprintf("Invalid time format: %s. Please provide time in HH:MM format.", timeStr))
+		}
+	}
+
+	// Convert to target timezone
+	return sourceTime, sourceTime.In(targetLoc), sourceTimezoneStr, targetTimezoneStr, nil
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// toolMiddleware is one named stage of the tool middleware chain
+type toolMiddleware struct {
+	name string
+	fn   server.ToolHandlerMiddleware
+}
+
+// toolMiddlewareChain applies cross-cutting behavior uniformly to every
+// tool, built-in, registered or plugin. Stages run in the order they are
+// added; the first stage is outermost.
+type toolMiddlewareChain struct {
+	stages []toolMiddleware
+}
+
+// use appends a stage; a nil fn is skipped so optional features can be
+// added unconditionally
+func (c *toolMiddlewareChain) use(name string, fn server.ToolHandlerMiddleware) {
+	if fn != nil {
+		c.stages = append(c.stages, toolMiddleware{name, fn})
+	}
+}
+
+// names lists the stages from outermost to innermost
+func (c *toolMiddlewareChain) names() []string {
+	names := make([]string, len(c.stages))
+	for i, stage := range c.stages {
+		names[i] = stage.name
+	}
+	return names
+}
+
+// install adds the chain to the server for all tools
+func (c *toolMiddlewareChain) install(mcpServer *server.MCPServer) {
+	fns := make([]server.ToolHandlerMiddleware, len(c.stages))
+	for i, stage := range c.stages {
+		fns[i] = stage.fn
+	}
+	mcpServer.Use(fns...)
+	slog.Debug("Tool middleware installed", "chain", strings.Join(c.names(), " > "))
+}
+
+// wrap applies the chain to a single handler, as the server does
+func (c *toolMiddlewareChain) wrap(handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	for i := len(c.stages) - 1; i >= 0; i-- {
+		handler = c.stages[i].fn(handler)
+	}
+	return handler
+}
+
+// customToolMiddleware holds middleware added with RegisterToolMiddleware
+var customToolMiddleware struct {
+	mu     sync.Mutex
+	stages []toolMiddleware
+}
+
+// RegisterToolMiddleware adds a custom stage to every tool call, typically
+// from an init function next to RegisterTool calls. Custom stages run in
+// registration order after authentication and quotas, so the caller's
+// identity is available via getUserInfo, and inside the tool timeout.
+func RegisterToolMiddleware(name string, fn server.ToolHandlerMiddleware) {
+	if name == "" || fn == nil {
+		panic("RegisterToolMiddleware: name and middleware are required")
+	}
+	customToolMiddleware.mu.Lock()
+	defer customToolMiddleware.mu.Unlock()
+	customToolMiddleware.stages = append(customToolMiddleware.stages, toolMiddleware{name, fn})
+}
+
+// newToolMiddlewareChain builds the standard chain:
+//
+//	recovery > tracing > logging > stats > auth (incl. RBAC) > quota > deprecation > cache > custom... > timeout
+//
+// Tracing is skipped unless enabled and quota when quotas is nil.
+func newToolMiddlewareChain(config func() *Config, stats *serverStats, quotas *quotaTracker) *toolMiddlewareChain {
+	chain := &toolMiddlewareChain{}
+	chain.use("recovery", recoveryMiddleware())
+	if config().OTelEnabled {
+		chain.use("tracing", tracingMiddleware())
+	}
+	chain.use("logging", loggingMiddleware())
+	if stats != nil {
+		chain.use("stats", statsMiddleware(stats))
+	}
+	chain.use("auth", authMiddleware(config))
+	if quotas != nil {
+		chain.use("quota", quotaMiddleware(quotas))
+	}
+	chain.use("deprecation", deprecationMiddleware())
+	chain.use("cache", cacheMiddleware(config, newToolResultCache()))
+	customToolMiddleware.mu.Lock()
+	for _, stage := range customToolMiddleware.stages {
+		chain.use(stage.name, stage.fn)
+	}
+	customToolMiddleware.mu.Unlock()
+	chain.use("timeout", timeoutMiddleware(config))
+	return chain
+}
+
+// recoveryMiddleware turns a panic in any later stage or handler into a tool
+// error so one faulty tool cannot take the session or process down
+func recoveryMiddleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (result *mcp.CallToolResult, err error) {
+			defer func() {
+				if p := recover(); p != nil {
+					slog.ErrorContext(ctx, "Tool call panicked", "tool", req.Params.Name, "panic", fmt.Sprint(p), "stack", string(debug.Stack()))
+					result, err = toolError(errorInternal, fmt.Sprintf("Internal error in tool '%s'", req.Params.Name)), nil
+				}
+			}()
+			return next(ctx, req)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestToolMiddlewareChain_Order(t *testing.T) {
+	customToolMiddleware.mu.Lock()
+	saved := customToolMiddleware.stages
+	customToolMiddleware.stages = nil
+	customToolMiddleware.mu.Unlock()
+	t.Cleanup(func() {
+		customToolMiddleware.mu.Lock()
+		customToolMiddleware.stages = saved
+		customToolMiddleware.mu.Unlock()
+	})
+
+	var seenUser string
+	RegisterToolMiddleware("audit", func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			seenUser, _, _ = getUserInfo(ctx)
+			return next(ctx, req)
+		}
+	})
+
+	config := &Config{}
+	chain := newToolMiddlewareChain(func() *Config { return config }, newServerStats(""), newQuotaTracker(newMemoryStore(), 0, 0))
+	expected := []string{"recovery", "logging", "stats", "auth", "quota", "deprecation", "cache", "audit", "timeout"}
+	if names := chain.names(); !slices.Equal(names, expected) {
+		t.Errorf("Expected chain %v but got %v", expected, names)
+	}
+
+	handler := chain.wrap(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+	ctx := context.WithValue(context.Background(), userIDKey, "alice")
+	if _, err := handler(ctx, mcp.CallToolRequest{}); err != nil {
+		t.Fatalf("Failed to call tool: %v", err)
+	}
+	if seenUser != "alice" {
+		t.Errorf("Expected custom middleware to see the caller but got %q", seenUser)
+	}
+}
+
+func TestRecoveryMiddleware(t *testing.T) {
+	handler := recoveryMiddleware()(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var calendars map[string]*businessCalendar
+		return mcp.NewToolResultText(calendars["missing"].Name), nil
+	})
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "check_business_day"
+	result, err := handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Expected the panic to become a tool result but got %v", err)
+	}
+	if !result.IsError || toolResultText(result) != "Internal error in tool 'check_business_day'" {
+		t.Errorf("Expected an internal error result but got %q", toolResultText(result))
+	}
+}
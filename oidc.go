@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultOIDCRole is assigned when a token carries neither roles nor groups
+const defaultOIDCRole = "user"
+
+// oidcDiscovery is the subset of the OpenID provider metadata used by the server
+type oidcDiscovery struct {
+	Issuer  string `json:"issuer"`
+	JWKSURI string `json:"jwks_uri"`
+}
+
+// discoverOIDC fetches the provider's .well-known/openid-configuration document
+// and checks that it describes the expected issuer
+func discoverOIDC(issuer string) (*oidcDiscovery, error) {
+	wellKnown := strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration"
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(wellKnown)
+	if err != nil {
+		return nil, fmt.Errorf("OIDC discovery failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OIDC discovery failed: unexpected status %s", resp.Status)
+	}
+
+	var doc oidcDiscovery
+	if err := json.NewDecoder(io.LimitReader(resp.Body, jwksMaxResponseSize)).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid OIDC discovery document: %w", err)
+	}
+	if strings.TrimSuffix(doc.Issuer, "/") != strings.TrimSuffix(issuer, "/") {
+		return nil, fmt.Errorf("OIDC issuer mismatch: expected %s, provider reports %s", issuer, doc.Issuer)
+	}
+	if doc.JWKSURI == "" {
+		return nil, fmt.Errorf("OIDC discovery document has no jwks_uri")
+	}
+	return &doc, nil
+}
+
+// applyStandardClaims maps OIDC standard claims onto the user fields used
+// throughout the server: sub becomes the user ID, preferred_username the
+// username, and the first role (or group) the role.
+func (c *Claims) applyStandardClaims() {
+	if c.UserID == "" {
+		c.UserID = c.Subject
+	}
+	if c.Username == "" {
+		c.Username = c.PreferredUsername
+		if c.Username == "" {
+			c.Username = c.Subject
+		}
+	}
+	if c.Role == "" {
+		switch {
+		case len(c.Roles) > 0:
+			c.Role = c.Roles[0]
+		case len(c.Groups) > 0:
+			c.Role = c.Groups[0]
+		default:
+			c.Role = defaultOIDCRole
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestOIDC_DiscoveryAndClaimMapping(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   srv.URL,
+			"jwks_uri": srv.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kty": "EC",
+			"kid": "k1",
+			"crv": "P-256",
+			"x":   base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, 32))),
+			"y":   base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, 32))),
+		}}})
+	})
+
+	auth, err := NewAuthMiddlewareFromConfig(&Config{
+		AuthEnabled:     true,
+		AuthAudience:    "timemcp-client",
+		AuthOIDCIssuer:  srv.URL,
+		AuthJWKSRefresh: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create OIDC auth middleware: %v", err)
+	}
+
+	claims := Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    srv.URL,
+			Subject:   "f1d2-user",
+			Audience:  jwt.ClaimStrings{"timemcp-client"},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		PreferredUsername: "jane",
+		Groups:            jwt.ClaimStrings{"reader", "staff"},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	token.Header["kid"] = "k1"
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+
+	got, err := auth.validateJWT(signed)
+	if err != nil {
+		t.Fatalf("Expected OIDC token to validate, but got %v", err)
+	}
+	if got.UserID != "f1d2-user" || got.Username != "jane" || got.Role != "reader" {
+		t.Errorf("Unexpected claim mapping: user_id=%q username=%q role=%q", got.UserID, got.Username, got.Role)
+	}
+}
+
+func TestDiscoverOIDC_IssuerMismatch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   "https://evil.example.com",
+			"jwks_uri": "https://evil.example.com/keys",
+		})
+	}))
+	defer srv.Close()
+
+	if _, err := discoverOIDC(srv.URL); err == nil {
+		t.Fatal("Expected an error for mismatched issuer, but got nil")
+	}
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// openAPIJSON is a JSON object in the OpenAPI document
+type openAPIJSON = map[string]any
+
+// toolSchemas returns a tool's input and output JSON Schemas as they are
+// sent in tools/list; output is nil for tools without structured output
+func toolSchemas(tool mcp.Tool) (input, output any) {
+	data, err := json.Marshal(tool)
+	if err != nil {
+		return openAPIJSON{"type": "object"}, nil
+	}
+	var schemas struct {
+		InputSchema  any `json:"inputSchema"`
+		OutputSchema any `json:"outputSchema"`
+	}
+	json.Unmarshal(data, &schemas)
+	if schemas.InputSchema == nil {
+		schemas.InputSchema = openAPIJSON{"type": "object"}
+	}
+	return schemas.InputSchema, schemas.OutputSchema
+}
+
+// jsonContent wraps a schema as an application/json media type
+func jsonContent(schema any) openAPIJSON {
+	return openAPIJSON{"application/json": openAPIJSON{"schema": schema}}
+}
+
+// jsonResponse describes a JSON response
+func jsonResponse(description string, schema any) openAPIJSON {
+	return openAPIJSON{"description": description, "content": jsonContent(schema)}
+}
+
+// openAPIDocument describes the HTTP surface as OpenAPI 3.1. REST tool
+// operations are generated from the given tools, so request and response
+// schemas are the tools' own input and output schemas.
+func openAPIDocument(config *Config, rt *httpRuntime, tools []mcp.Tool) openAPIJSON {
+	errorResponse := jsonResponse("Error", openAPIJSON{"$ref": "#/components/schemas/Error"})
+	textResult := openAPIJSON{"$ref": "#/components/schemas/TextResult"}
+	var security []any
+	if config.AuthEnabled {
+		security = []any{openAPIJSON{"bearerAuth": []any{}}}
+	}
+	operation := func(id, summary string, secured bool, responses openAPIJSON) openAPIJSON {
+		op := openAPIJSON{"operationId": id, "summary": summary, "responses": responses}
+		if secured && security != nil {
+			op["security"] = security
+			responses["401"] = errorResponse
+		}
+		return op
+	}
+
+	paths := openAPIJSON{
+		"/health": openAPIJSON{"get": operation("getHealth", "Component health and identity", false, openAPIJSON{
+			"200": jsonResponse("Server is healthy or degraded", openAPIJSON{"type": "object", "properties": openAPIJSON{
+				"status": openAPIJSON{"type": "string", "enum": []string{"healthy", "degraded", "unhealthy"}}, "service": openAPIJSON{"type": "string"},
+				"version": openAPIJSON{"type": "string"}, "timestamp": openAPIJSON{"type": "string", "format": "date-time"},
+				"components": openAPIJSON{"type": "object", "additionalProperties": openAPIJSON{"type": "object", "properties": openAPIJSON{
+					"status":  openAPIJSON{"type": "string", "enum": []string{healthOK, healthDegraded, healthUnhealthy}},
+					"message": openAPIJSON{"type": "string"}, "details": openAPIJSON{"type": "object"},
+				}}},
+			}}),
+			"503": openAPIJSON{"description": "A critical component is unhealthy; the body lists component statuses"},
+		})},
+		"/livez": openAPIJSON{"get": operation("getLivez", "Liveness probe", false, openAPIJSON{
+			"200": openAPIJSON{"description": "Process is alive"},
+		})},
+		"/readyz": openAPIJSON{"get": operation("getReadyz", "Readiness probe", false, openAPIJSON{
+			"200": openAPIJSON{"description": "Ready to serve"},
+			"503": openAPIJSON{"description": "Not ready; the body lists the failing checks"},
+		})},
+		"/version": openAPIJSON{"get": operation("getVersion", "Build metadata", false, openAPIJSON{
+			"200": jsonResponse("Build metadata and loaded dataset versions", openAPIJSON{"type": "object", "properties": openAPIJSON{
+				"datasets": openAPIJSON{"type": "object", "additionalProperties": openAPIJSON{"type": "object"}},
+			}, "additionalProperties": openAPIJSON{"type": "string"}}),
+		})},
+		"/capabilities": openAPIJSON{"get": operation("getCapabilities", "Tools the caller may use, as in MCP tools/list", true, openAPIJSON{
+			"200": jsonResponse("Server identity and tools", openAPIJSON{"type": "object"}),
+		})},
+		config.HTTPPath: openAPIJSON{"post": operation("postMCP", "MCP Streamable HTTP endpoint (JSON-RPC 2.0)", true, openAPIJSON{
+			"200": openAPIJSON{"description": "JSON-RPC response, or an event stream"},
+		})},
+	}
+	if rt != nil && rt.stats != nil {
+		paths["/stats"] = openAPIJSON{"get": operation("getStats", "Tool usage statistics (admin role)", true, openAPIJSON{
+			"200": jsonResponse("Usage statistics", openAPIJSON{"type": "object"}),
+			"403": openAPIJSON{"description": "Caller is not an admin"},
+		})}
+	}
+
+	if rt != nil && rt.sessions != nil {
+		list := operation("listSessions", "Active stateful HTTP sessions (admin role)", true, openAPIJSON{
+			"200": jsonResponse("Sessions", openAPIJSON{"type": "object"}),
+			"403": openAPIJSON{"description": "Caller is not an admin"},
+		})
+		paths[sessionsPathPrefix] = openAPIJSON{"get": list}
+		terminate := operation("terminateSession", "Terminate a session (admin role; requires auth)", true, openAPIJSON{
+			"204": openAPIJSON{"description": "Session terminated"},
+			"403": errorResponse,
+			"404": errorResponse,
+		})
+		terminate["parameters"] = []any{openAPIJSON{"name": "id", "in": "path", "required": true, "schema": openAPIJSON{"type": "string"}}}
+		paths[sessionsPathPrefix+"/{id}"] = openAPIJSON{"delete": terminate}
+	}
+
+	if rt != nil && config.Datasets != nil {
+		paths[datasetsReloadPath] = openAPIJSON{"post": operation("reloadDatasets", "Re-read calendar, term and GeoIP files (admin role; requires auth)", true, openAPIJSON{
+			"200": jsonResponse("Dataset versions and the datasets that changed", openAPIJSON{"type": "object"}),
+			"403": errorResponse,
+			"422": errorResponse,
+		})}
+	}
+
+	if config.HTTPREST {
+		toolErrors := func(responses openAPIJSON) openAPIJSON {
+			for _, status := range []string{"400", "403", "429", "504"} {
+				responses[status] = errorResponse
+			}
+			return responses
+		}
+		for _, tool := range tools {
+			input, output := toolSchemas(tool)
+			if output == nil {
+				output = textResult
+			}
+			call := operation(tool.Name, tool.Description, true, toolErrors(openAPIJSON{"200": jsonResponse("Tool result", output)}))
+			call["requestBody"] = openAPIJSON{"content": jsonContent(input)}
+			paths[restPathPrefix+"tools/"+tool.Name] = openAPIJSON{"post": call}
+
+			switch tool.Name {
+			case "get_current_time":
+				now := operation("getNow", tool.Description, true, toolErrors(openAPIJSON{"200": jsonResponse("Current time", output)}))
+				now["parameters"] = []any{openAPIJSON{
+					"name": "tz", "in": "query", "description": "IANA timezone; defaults to the server's default timezone",
+					"schema": openAPIJSON{"type": "string"},
+				}}
+				paths[restPathPrefix+"now"] = openAPIJSON{"get": now}
+			case "convert_time_v2":
+				convert := operation("convertTime", tool.Description, true, toolErrors(openAPIJSON{"200": jsonResponse("Conversion", output)}))
+				convert["requestBody"] = openAPIJSON{"required": true, "content": jsonContent(input)}
+				paths[restPathPrefix+"convert"] = openAPIJSON{"post": convert}
+			}
+		}
+		paths[restPathPrefix+"tools"] = openAPIJSON{"get": operation("listTools", "Tools the caller may call", true, openAPIJSON{
+			"200": jsonResponse("Tools", openAPIJSON{"type": "array", "items": openAPIJSON{"type": "object"}}),
+		})}
+	}
+
+	doc := openAPIJSON{
+		"openapi": "3.1.0",
+		"info": openAPIJSON{
+			"title":       config.ServerName,
+			"version":     config.ServerVersion,
+			"description": config.ServerInstructions,
+		},
+		"paths": paths,
+		"components": openAPIJSON{
+			"schemas": openAPIJSON{
+				"Error": openAPIJSON{"type": "object", "required": []string{"error"},
+					"properties": openAPIJSON{"error": openAPIJSON{"type": "string"}, "code": openAPIJSON{"type": "string", "description": "Error code, e.g. INVALID_TIMEZONE"}}},
+				"TextResult": openAPIJSON{"type": "object", "required": []string{"result"},
+					"properties": openAPIJSON{"result": openAPIJSON{"type": "string"}}},
+			},
+		},
+	}
+	if config.AuthEnabled {
+		doc["components"].(openAPIJSON)["securitySchemes"] = openAPIJSON{
+			"bearerAuth": openAPIJSON{"type": "http", "scheme": "bearer", "description": "JWT or API key"},
+		}
+	}
+	return doc
+}
+
+// addOpenAPIEndpoint registers GET /openapi.json. Like /capabilities it
+// requires credentials when auth is enabled and only includes the tools the
+// caller's role may call.
+func addOpenAPIEndpoint(mux *http.ServeMux, config *Config, rt *httpRuntime) {
+	if rt == nil || rt.mcpServer == nil {
+		return
+	}
+	mux.HandleFunc("/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := authenticateEndpoint(w, r, config, rt)
+		if !ok {
+			return
+		}
+		if config.HTTPCORSEnabled {
+			if origin := r.Header.Get("Origin"); origin != "" && isOriginAllowed(origin, config.HTTPCORSOrigins) {
+				setCORSHeaders(w, r, origin, config.HTTPCORSPolicy, false)
+			}
+		}
+		slog.DebugContext(r.Context(), "OpenAPI document requested")
+		writeJSONResponse(w, r, http.StatusOK, openAPIDocument(config, rt, capabilities(rt, config, claims).Tools))
+	})
+}
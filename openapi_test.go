@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func getOpenAPI(t *testing.T, mux *http.ServeMux, token string) (int, map[string]any) {
+	t.Helper()
+	req := httptest.NewRequest("GET", "/openapi.json", nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	var doc map[string]any
+	if rec.Code == http.StatusOK {
+		if err := json.NewDecoder(rec.Body).Decode(&doc); err != nil {
+			t.Fatalf("Failed to decode OpenAPI document: %v", err)
+		}
+	}
+	return rec.Code, doc
+}
+
+func TestOpenAPIEndpoint(t *testing.T) {
+	config := &Config{ServerName: "TimeMCP", ServerVersion: "test", HTTPPath: "/mcp", HTTPREST: true}
+	mcpServer := server.NewMCPServer("TimeMCP", "test", server.WithToolCapabilities(true))
+	addTools(mcpServer, config)
+	mux := http.NewServeMux()
+	addOpenAPIEndpoint(mux, config, &httpRuntime{mcpServer: mcpServer})
+
+	status, doc := getOpenAPI(t, mux, "")
+	if status != http.StatusOK || doc["openapi"] != "3.1.0" {
+		t.Fatalf("Expected an OpenAPI 3.1 document but got %d %v", status, doc["openapi"])
+	}
+	paths := doc["paths"].(map[string]any)
+	for _, path := range []string{"/health", "/readyz", "/version", "/capabilities", "/mcp", "/api/v1/now", "/api/v1/convert", "/api/v1/tools"} {
+		if paths[path] == nil {
+			t.Errorf("Expected path %s in the document", path)
+		}
+	}
+	for name := range mcpServer.ListTools() {
+		if paths["/api/v1/tools/"+name] == nil {
+			t.Errorf("Expected an operation for tool %s", name)
+		}
+	}
+
+	// Request and response schemas are the tool's own
+	convert := paths["/api/v1/convert"].(map[string]any)["post"].(map[string]any)
+	body := convert["requestBody"].(map[string]any)["content"].(map[string]any)["application/json"].(map[string]any)["schema"].(map[string]any)
+	if body["required"].([]any)[0] != "target_timezone" {
+		t.Errorf("Expected the convert_time_v2 input schema but got %v", body)
+	}
+	ok := convert["responses"].(map[string]any)["200"].(map[string]any)["content"].(map[string]any)["application/json"].(map[string]any)["schema"].(map[string]any)
+	if ok["properties"].(map[string]any)["time_difference"] == nil {
+		t.Errorf("Expected the convert_time_v2 output schema but got %v", ok)
+	}
+
+	config.HTTPREST = false
+	if _, doc := getOpenAPI(t, mux, ""); doc["paths"].(map[string]any)["/api/v1/now"] != nil {
+		t.Errorf("Expected no REST paths when the REST API is off")
+	}
+}
+
+func TestOpenAPIEndpoint_FiltersByRole(t *testing.T) {
+	config := &Config{
+		HTTPREST:      true,
+		AuthEnabled:   true,
+		AuthSecretKey: "test-secret-key-that-is-long-enough-for-hs256",
+		AuthIssuer:    "test-issuer",
+		AuthAudience:  "test-audience",
+		AuthRoles:     rolePolicy{"viewer": {"get_current_time": {}}},
+	}
+	contextFunc := &reloadableContextFunc{}
+	fn, auth, err := createHTTPMiddleware(config)
+	if err != nil {
+		t.Fatalf("Failed to create HTTP middleware: %v", err)
+	}
+	contextFunc.store(fn, auth)
+	mcpServer := server.NewMCPServer("TimeMCP", "test", server.WithToolCapabilities(true))
+	addTools(mcpServer, config)
+	mux := http.NewServeMux()
+	addOpenAPIEndpoint(mux, config, &httpRuntime{mcpServer: mcpServer, contextFunc: contextFunc})
+
+	if status, _ := getOpenAPI(t, mux, ""); status != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 without credentials but got %d", status)
+	}
+	token, _ := auth.GenerateToken("1", "vera", "viewer", 1)
+	_, doc := getOpenAPI(t, mux, token)
+	paths := doc["paths"].(map[string]any)
+	if paths["/api/v1/tools/get_current_time"] == nil || paths["/api/v1/now"] == nil || paths["/api/v1/convert"] != nil {
+		t.Errorf("Expected only the viewer's tools in the document")
+	}
+	schemes := doc["components"].(map[string]any)["securitySchemes"]
+	if schemes == nil {
+		t.Errorf("Expected a bearer security scheme")
+	}
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"hash/fnv"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// maxLoggedPayload caps the part of one message written to the log; the
+// logged size is always the full one
+const maxLoggedPayload = 64 << 10
+
+// payloadSampler decides which JSON-RPC messages of one connection are
+// logged, for TIME_LOG_PAYLOADS. A response carries the id of its request,
+// so sampling by id logs both halves of an exchange; the per-connection salt
+// keeps clients that number their requests alike from always landing in, or
+// out of, the sample. Messages without an id are sampled at random.
+type payloadSampler struct {
+	transport string
+	config    func() *Config
+	salt      uint64
+}
+
+func newPayloadSampler(transport string, config func() *Config) *payloadSampler {
+	return &payloadSampler{transport: transport, config: config, salt: rand.Uint64()}
+}
+
+// enabled reports whether any messages are being sampled
+func (s *payloadSampler) enabled() bool {
+	return s.config().LogPayloads > 0
+}
+
+func (s *payloadSampler) sampled(data []byte) bool {
+	percent := s.config().LogPayloads
+	if percent <= 0 {
+		return false
+	}
+	if percent >= 100 {
+		return true
+	}
+	var message struct {
+		ID json.RawMessage `json:"id"`
+	}
+	if json.Unmarshal(data, &message) != nil || len(message.ID) == 0 || string(message.ID) == "null" {
+		return rand.Float64()*100 < percent
+	}
+	h := fnv.New64a()
+	binary.Write(h, binary.LittleEndian, s.salt)
+	h.Write(message.ID)
+	return float64(h.Sum64()%10000) < percent*100
+}
+
+// log logs one complete message when it is sampled
+func (s *payloadSampler) log(ctx context.Context, direction string, data []byte) {
+	if s.sampled(data) {
+		logPayload(ctx, s.transport, direction, data[:min(len(data), maxLoggedPayload)], len(data))
+	}
+}
+
+// logPayload logs a message, or its first part when size exceeds the data
+// given. Tokens and secrets in the payload are masked by the log handler.
+func logPayload(ctx context.Context, transport, direction string, data []byte, size int) {
+	attrs := []any{"transport", transport, "direction", direction, "bytes", size}
+	if size > len(data) {
+		attrs = append(attrs, "truncated", true)
+	} else {
+		kind, method := jsonrpcShape(data)
+		attrs = append(attrs, "kind", kind)
+		if method != "" {
+			attrs = append(attrs, "method", method)
+		}
+	}
+	attrs = append(attrs, "payload", string(data))
+	slog.InfoContext(ctx, "JSON-RPC payload", attrs...)
+}
+
+// jsonrpcShape classifies a message as a request, notification, response,
+// error, batch or, when it does not parse or fit any of those, invalid, and
+// returns its method if it has one
+func jsonrpcShape(data []byte) (kind, method string) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		if json.Valid(data) {
+			return "batch", ""
+		}
+		return "invalid", ""
+	}
+	var message struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+		Result json.RawMessage `json:"result"`
+		Error  json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal(data, &message); err != nil {
+		return "invalid", ""
+	}
+	switch {
+	case message.Method != "" && len(message.ID) > 0:
+		return "request", message.Method
+	case message.Method != "":
+		return "notification", message.Method
+	case message.Error != nil:
+		return "error", ""
+	case message.Result != nil:
+		return "response", ""
+	}
+	return "invalid", ""
+}
+
+// payloadLogHandler logs the bodies of a sampled share of MCP POST requests
+// and of their responses, including event streams answering them. GET event
+// streams are left alone, as they stay open for the whole session.
+func payloadLogHandler(next http.Handler, config func() *Config) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		percent := config().LogPayloads
+		if r.Method != http.MethodPost || percent <= 0 || rand.Float64()*100 >= percent {
+			next.ServeHTTP(w, r)
+			return
+		}
+		request := &payloadCapture{}
+		r.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.TeeReader(r.Body, request), r.Body}
+		recorder := &payloadRecorder{ResponseWriter: w}
+		next.ServeHTTP(recorder, r)
+		logPayload(r.Context(), "http", "received", request.data, request.size)
+		if recorder.response.size > 0 {
+			logPayload(r.Context(), "http", "sent", recorder.response.data, recorder.response.size)
+		}
+	})
+}
+
+// payloadCapture keeps the first maxLoggedPayload bytes written to it and
+// counts the rest
+type payloadCapture struct {
+	data []byte
+	size int
+}
+
+func (c *payloadCapture) Write(p []byte) (int, error) {
+	if room := maxLoggedPayload - len(c.data); room > 0 {
+		c.data = append(c.data, p[:min(len(p), room)]...)
+	}
+	c.size += len(p)
+	return len(p), nil
+}
+
+// payloadRecorder captures a response body as it is written
+type payloadRecorder struct {
+	http.ResponseWriter
+	response payloadCapture
+}
+
+func (pr *payloadRecorder) Write(b []byte) (int, error) {
+	n, err := pr.ResponseWriter.Write(b)
+	pr.response.Write(b[:n])
+	return n, err
+}
+
+// Flush keeps event streams working through the recorder
+func (pr *payloadRecorder) Flush() {
+	if f, ok := pr.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (pr *payloadRecorder) Unwrap() http.ResponseWriter {
+	return pr.ResponseWriter
+}
+
+// payloadLines splits a stdio stream into its newline-delimited messages and
+// logs the sampled ones
+type payloadLines struct {
+	ctx       context.Context
+	sampler   *payloadSampler
+	direction string
+
+	mu      sync.Mutex
+	pending []byte
+	// midLine is set while sampling is off and the stream stopped inside a
+	// message, so the rest of it is not logged as a message of its own
+	midLine bool
+}
+
+func (l *payloadLines) observe(p []byte) {
+	if len(p) == 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.sampler.enabled() {
+		l.pending = l.pending[:0]
+		l.midLine = p[len(p)-1] != '\n'
+		return
+	}
+	if l.midLine {
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			return
+		}
+		p, l.midLine = p[i+1:], false
+	}
+	l.pending = append(l.pending, p...)
+	for {
+		i := bytes.IndexByte(l.pending, '\n')
+		if i < 0 {
+			break
+		}
+		if line := bytes.TrimSpace(l.pending[:i]); len(line) > 0 {
+			l.sampler.log(l.ctx, l.direction, line)
+		}
+		l.pending = l.pending[i+1:]
+	}
+}
+
+// payloadReader passes reads through to the logged stream
+type payloadReader struct {
+	io.Reader
+	lines *payloadLines
+}
+
+func (r payloadReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.lines.observe(p[:n])
+	return n, err
+}
+
+// payloadWriter passes writes through to the logged stream
+type payloadWriter struct {
+	io.Writer
+	lines *payloadLines
+}
+
+func (w payloadWriter) Write(p []byte) (int, error) {
+	n, err := w.Writer.Write(p)
+	w.lines.observe(p[:n])
+	return n, err
+}
+
+// serveStdio serves MCP on stdin and stdout as server.ServeStdio does, with
+// both streams passed through the payload log
+func serveStdio(mcpServer *server.MCPServer, config func() *Config, opts ...server.StdioOption) error {
+	s := server.NewStdioServer(mcpServer)
+	for _, opt := range opts {
+		opt(s)
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+
+	sampler := newPayloadSampler("stdio", config)
+	in := &payloadLines{ctx: ctx, sampler: sampler, direction: "received"}
+	out := &payloadLines{ctx: ctx, sampler: sampler, direction: "sent"}
+	return s.Listen(ctx, payloadReader{os.Stdin, in}, payloadWriter{os.Stdout, out})
+}
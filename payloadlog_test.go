@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestJSONRPCShape(t *testing.T) {
+	tests := []struct {
+		message, kind, method string
+	}{
+		{`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`, "request", "tools/list"},
+		{`{"jsonrpc":"2.0","method":"notifications/initialized"}`, "notification", "notifications/initialized"},
+		{`{"jsonrpc":"2.0","id":1,"result":{}}`, "response", ""},
+		{`{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"no"}}`, "error", ""},
+		{`[{"jsonrpc":"2.0","id":1,"method":"ping"}]`, "batch", ""},
+		{`{"jsonrpc":"2.0","id":1,"method":`, "invalid", ""},
+		{`{"jsonrpc":"2.0","id":1}`, "invalid", ""},
+	}
+	for _, tt := range tests {
+		if kind, method := jsonrpcShape([]byte(tt.message)); kind != tt.kind || method != tt.method {
+			t.Errorf("%s: expected %s %q but got %s %q", tt.message, tt.kind, tt.method, kind, method)
+		}
+	}
+}
+
+func TestPayloadSampler(t *testing.T) {
+	config := &Config{LogPayloads: 50}
+	sampler := newPayloadSampler("ws", func() *Config { return config })
+	sampled := 0
+	for i := range 1000 {
+		request := fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"ping"}`, i)
+		response := fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"result":{}}`, i)
+		if sampler.sampled([]byte(request)) != sampler.sampled([]byte(response)) {
+			t.Fatalf("Expected request %d and its response to be sampled together", i)
+		}
+		if sampler.sampled([]byte(request)) {
+			sampled++
+		}
+	}
+	if sampled < 400 || sampled > 600 {
+		t.Errorf("Expected about half of the requests to be sampled but got %d of 1000", sampled)
+	}
+
+	config.LogPayloads = 0
+	if sampler.sampled([]byte(`{"jsonrpc":"2.0","id":1,"method":"ping"}`)) {
+		t.Error("Expected nothing to be sampled when payload logging is off")
+	}
+}
+
+func TestPayloadLogging(t *testing.T) {
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(newLogHandler(&buf, logFormatJSON)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	config := &Config{LogPayloads: 100}
+	handler := payloadLogHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.Write([]byte(`{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"Parse error"}}`))
+	}), func() *Config { return config })
+	body := `{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"arguments":{"password":"hunter2hunter2"}`
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/mcp", strings.NewReader(body)))
+
+	out := buf.String()
+	for _, want := range []string{
+		fmt.Sprintf(`"direction":"received","bytes":%d,"kind":"invalid"`, len(body)),
+		`"direction":"sent","bytes":75,"kind":"error"`,
+		`password\":\"[REDACTED]`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected the log to contain %s but got %s", want, out)
+		}
+	}
+	if strings.Contains(out, "hunter2hunter2") {
+		t.Errorf("Expected the password to be redacted but got %s", out)
+	}
+
+	// Messages split across reads are logged once complete
+	buf.Reset()
+	lines := &payloadLines{ctx: context.Background(), sampler: newPayloadSampler("stdio", func() *Config { return config }), direction: "received"}
+	lines.observe([]byte(`{"jsonrpc":"2.0","id":1,`))
+	lines.observe([]byte("\"method\":\"ping\"}\n{\"jsonrpc\""))
+	if got := strings.Count(buf.String(), `"kind":"request","method":"ping"`); got != 1 {
+		t.Errorf("Expected one complete request to be logged but got %s", buf.String())
+	}
+}
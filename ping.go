@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// pingResult is the structured result of the ping tool
+type pingResult struct {
+	Status         string `json:"status" jsonschema:"Always ok; any reply means the server and the session are alive"`
+	ServerTime     string `json:"server_time" jsonschema:"Server time in RFC 3339 UTC"`
+	SessionID      string `json:"session_id,omitempty"`
+	SessionStarted string `json:"session_started,omitempty" jsonschema:"When the session was created or, after a server restart, first seen"`
+	SessionExpires string `json:"session_expires,omitempty" jsonschema:"When the session reaches its maximum lifetime and the client must initialize again"`
+	IdleTimeout    string `json:"idle_timeout,omitempty" jsonschema:"How long the session may go without activity before it is closed"`
+	Heartbeat      string `json:"heartbeat,omitempty" jsonschema:"Interval of server keepalives on open streams and sockets"`
+}
+
+// addPingTool registers ping. sessions is nil over stdio and stateless
+// HTTP, where there is no session state to report.
+func addPingTool(mcpServer *server.MCPServer, sessions *sessionRegistry, config *Config) {
+	mcpServer.AddTool(
+		mcp.NewTool("ping",
+			mcp.WithDescription("Cheaply check that the server and your session are alive. Also reports when the session started, when it expires and how long it may stay idle, so clients can reconnect ahead of time. Calling it counts as session activity."),
+			mcp.WithOutputSchema[pingResult](),
+			mcp.WithTitleAnnotation("Ping"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		handlePing(sessions, config),
+	)
+}
+
+// handlePing returns a handler for the ping tool
+func handlePing(sessions *sessionRegistry, config *Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result := pingResult{Status: "ok", ServerTime: config.now().UTC().Format(time.RFC3339)}
+		if session := server.ClientSessionFromContext(ctx); session != nil {
+			result.SessionID = session.SessionID()
+		}
+		if info, ok := sessions.get(result.SessionID); ok {
+			result.SessionStarted = info.CreatedAt.UTC().Format(time.RFC3339)
+			if config.HTTPSessionLifetime > 0 {
+				result.SessionExpires = info.CreatedAt.Add(config.HTTPSessionLifetime).UTC().Format(time.RFC3339)
+			}
+			if config.HTTPSessionIdleTTL > 0 {
+				result.IdleTimeout = config.HTTPSessionIdleTTL.String()
+			}
+			if config.HTTPHeartbeat > 0 {
+				result.Heartbeat = config.HTTPHeartbeat.String()
+			}
+		}
+
+		data, err := json.Marshal(result)
+		if err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to encode result: %v", err)), nil
+		}
+		return mcp.NewToolResultStructured(result, string(data)), nil
+	}
+}
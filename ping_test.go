@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestPing(t *testing.T) {
+	config := &Config{HTTPHeartbeat: 30 * time.Second, HTTPSessionIdleTTL: 5 * time.Minute, HTTPSessionLifetime: time.Hour}
+	mcpServer := server.NewMCPServer("TimeMCP", "test")
+	sessions := newSessionRegistry(config.HTTPSessionLifetime)
+	created := time.Now()
+	sessions.now = func() time.Time { return created }
+	session := &webSocketSession{id: sessions.Generate()}
+	ctx := mcpServer.WithContext(context.Background(), session)
+
+	result, err := handlePing(sessions, config)(ctx, mcp.CallToolRequest{})
+	if err != nil || result.IsError {
+		t.Fatalf("Expected ping to succeed but got %v %v", err, result.Content)
+	}
+	ping := result.StructuredContent.(pingResult)
+	expires := created.Add(time.Hour).UTC().Format(time.RFC3339)
+	if ping.Status != "ok" || ping.SessionID != session.id || ping.SessionExpires != expires || ping.IdleTimeout != "5m0s" || ping.Heartbeat != "30s" {
+		t.Errorf("Expected the session's keepalive settings but got %+v", ping)
+	}
+
+	// Over stdio there is no tracked session to describe
+	result, _ = handlePing(nil, config)(context.Background(), mcp.CallToolRequest{})
+	if ping := result.StructuredContent.(pingResult); ping.Status != "ok" || ping.SessionStarted != "" || ping.IdleTimeout != "" {
+		t.Errorf("Expected only the status without a session but got %+v", ping)
+	}
+}
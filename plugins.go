@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// pluginDescribeTimeout bounds the describe call made at startup
+	pluginDescribeTimeout = 5 * time.Second
+	// maxPluginOutput caps what a plugin may write to stdout or stderr
+	maxPluginOutput = 1 << 20
+)
+
+// pluginEnvAllowlist lists the server environment passed to plugins; other
+// variables, including TIME_AUTH_SECRET_KEY, are withheld
+var pluginEnvAllowlist = []string{"PATH", "HOME", "TZ", "LANG", "ZONEINFO"}
+
+// pluginDescription is what "<plugin> describe" prints on stdout
+type pluginDescription struct {
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	InputSchema json.RawMessage     `json:"input_schema"`
+	Annotations *mcp.ToolAnnotation `json:"annotations"`
+}
+
+// loadToolPlugins registers a tool for every executable in dir. Each plugin
+// is a program that prints its description as JSON when run with "describe"
+// and, when run with "call", reads the arguments as a JSON object on stdin
+// and prints the result text on stdout; a non-zero exit status makes the
+// call a tool error with stderr as the message.
+func loadToolPlugins(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read plugin directory: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || !info.Mode().IsRegular() || info.Mode().Perm()&0o111 == 0 {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		def, err := describePlugin(path)
+		if err != nil {
+			return fmt.Errorf("plugin %s: %w", entry.Name(), err)
+		}
+		if err := registerTool(def); err != nil {
+			return fmt.Errorf("plugin %s: %w", entry.Name(), err)
+		}
+		slog.Info("Loaded tool plugin", "tool", def.Tool.Name, "path", path)
+	}
+	return nil
+}
+
+// describePlugin runs "<path> describe" and builds the tool definition
+func describePlugin(path string) (ToolDefinition, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), pluginDescribeTimeout)
+	defer cancel()
+	stdout, err := runPlugin(ctx, path, "describe", nil, nil)
+	if err != nil {
+		return ToolDefinition{}, err
+	}
+
+	var desc pluginDescription
+	if err := json.Unmarshal(stdout, &desc); err != nil {
+		return ToolDefinition{}, fmt.Errorf("invalid describe output: %w", err)
+	}
+	if desc.Name == "" {
+		return ToolDefinition{}, fmt.Errorf("describe output has no name")
+	}
+	if len(desc.InputSchema) == 0 {
+		desc.InputSchema = json.RawMessage(`{"type":"object","properties":{}}`)
+	}
+
+	tool := mcp.NewToolWithRawSchema(desc.Name, desc.Description, desc.InputSchema)
+	if desc.Annotations != nil {
+		tool.Annotations = *desc.Annotations
+	}
+	return ToolDefinition{
+		Tool: tool,
+		Handler: func(config *Config) server.ToolHandlerFunc {
+			return pluginHandler(path)
+		},
+	}, nil
+}
+
+// pluginHandler runs "<path> call" for each tool call. The call is killed
+// when ctx ends, so TIME_TOOL_TIMEOUT applies to plugins too.
+func pluginHandler(path string) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, err := json.Marshal(request.GetArguments())
+		if err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to encode arguments: %v", err)), nil
+		}
+		userID, _, role := getUserInfo(ctx)
+		env := []string{
+			"TIMEMCP_TOOL=" + request.Params.Name,
+			"TIMEMCP_USER_ID=" + userID,
+			"TIMEMCP_ROLE=" + role,
+			"TIMEMCP_REQUEST_ID=" + getRequestID(ctx),
+		}
+
+		stdout, err := runPlugin(ctx, path, "call", args, env)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return toolError(errorToolFailed, err.Error()), nil
+		}
+		return mcp.NewToolResultText(strings.TrimRight(string(stdout), "\n")), nil
+	}
+}
+
+// runPlugin executes the plugin with a filtered environment and returns its
+// stdout; a failed run is reported with the plugin's stderr
+func runPlugin(ctx context.Context, path, command string, stdin []byte, env []string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, path, command)
+	cmd.WaitDelay = time.Second
+	for _, key := range pluginEnvAllowlist {
+		if value, ok := os.LookupEnv(key); ok {
+			cmd.Env = append(cmd.Env, key+"="+value)
+		}
+	}
+	cmd.Env = append(cmd.Env, env...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	stdout := &limitedBuffer{limit: maxPluginOutput}
+	stderr := &limitedBuffer{limit: maxPluginOutput}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		var exitErr *exec.ExitError
+		if msg == "" || !errors.As(err, &exitErr) {
+			msg = err.Error()
+		}
+		return nil, errors.New(msg)
+	}
+	if stdout.truncated {
+		return nil, fmt.Errorf("output exceeds %d bytes", maxPluginOutput)
+	}
+	return stdout.Bytes(), nil
+}
+
+// limitedBuffer keeps at most limit bytes and discards the rest
+type limitedBuffer struct {
+	bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.Len(); len(p) > room {
+		b.truncated = true
+		b.Buffer.Write(p[:max(room, 0)])
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// writePlugin creates an executable shell script plugin in dir
+func writePlugin(t *testing.T, dir, name, script string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script), 0o755); err != nil {
+		t.Fatalf("Failed to write plugin: %v", err)
+	}
+}
+
+func TestLoadToolPlugins(t *testing.T) {
+	resetToolRegistry(t)
+	t.Setenv("TIME_AUTH_SECRET_KEY", "must-not-leak-to-plugins")
+	dir := t.TempDir()
+	writePlugin(t, dir, "fiscal", `case "$1" in
+describe) echo '{"name":"get_fiscal_quarter","description":"Fiscal quarter","input_schema":{"type":"object","properties":{"date":{"type":"string"}}},"annotations":{"readOnlyHint":true}}' ;;
+call) read args; echo "args=$args tool=$TIMEMCP_TOOL secret=$TIME_AUTH_SECRET_KEY" ;;
+esac
+`)
+	writePlugin(t, dir, "failing", `case "$1" in
+describe) echo '{"name":"always_fails"}' ;;
+call) echo "ledger unavailable" >&2; exit 3 ;;
+esac
+`)
+	// Non-executable files are ignored
+	os.WriteFile(filepath.Join(dir, "README"), []byte("notes"), 0o644)
+
+	if err := loadToolPlugins(dir); err != nil {
+		t.Fatalf("Failed to load plugins: %v", err)
+	}
+	defs := registeredTools()
+	if len(defs) != 2 {
+		t.Fatalf("Expected 2 plugin tools but got %d", len(defs))
+	}
+
+	handlers := make(map[string]ToolDefinition)
+	for _, def := range defs {
+		handlers[def.Tool.Name] = def
+	}
+	fiscal := handlers["get_fiscal_quarter"]
+	if fiscal.Tool.Annotations.ReadOnlyHint == nil || !*fiscal.Tool.Annotations.ReadOnlyHint {
+		t.Errorf("Expected annotations from the describe output")
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "get_fiscal_quarter"
+	req.Params.Arguments = map[string]any{"date": "2025-06-01"}
+	result, err := fiscal.Handler(&Config{})(context.Background(), req)
+	if err != nil || result.IsError {
+		t.Fatalf("Failed to call plugin: %v %s", err, toolResultText(result))
+	}
+	text := toolResultText(result)
+	if !strings.Contains(text, `args={"date":"2025-06-01"}`) || !strings.Contains(text, "tool=get_fiscal_quarter") {
+		t.Errorf("Expected arguments and tool name passed to the plugin but got %q", text)
+	}
+	if strings.Contains(text, "must-not-leak") {
+		t.Errorf("Expected server secrets to be withheld from plugins but got %q", text)
+	}
+
+	req.Params.Name = "always_fails"
+	result, err = handlers["always_fails"].Handler(&Config{})(context.Background(), req)
+	if err != nil || !result.IsError || toolResultText(result) != "ledger unavailable" {
+		t.Errorf("Expected a tool error with the plugin's stderr but got %v %q", err, toolResultText(result))
+	}
+}
+
+func TestPluginHandler_Timeout(t *testing.T) {
+	dir := t.TempDir()
+	writePlugin(t, dir, "slow", "sleep 5\n")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := pluginHandler(filepath.Join(dir, "slow"))(ctx, mcp.CallToolRequest{})
+	if err == nil {
+		t.Errorf("Expected the cancelled call to return the context error")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected the plugin to be killed at the deadline but it took %s", elapsed)
+	}
+}
+
+func TestLoadToolPlugins_InvalidDescribe(t *testing.T) {
+	resetToolRegistry(t)
+	dir := t.TempDir()
+	writePlugin(t, dir, "broken", "echo not-json\n")
+	if err := loadToolPlugins(dir); err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Errorf("Expected an error naming the broken plugin but got %v", err)
+	}
+}
@@ -0,0 +1,353 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// posixTZ is a parsed POSIX TZ string such as "EST5EDT,M3.2.0,M11.1.0".
+// Offsets are in seconds east of UTC, the opposite sign of the string.
+type posixTZ struct {
+	Std        string
+	StdOffset  int
+	Dst        string // empty without daylight saving time
+	DstOffset  int
+	Start, End posixTZRule
+}
+
+// posixTZRule is the date and local time of a daylight saving change
+type posixTZRule struct {
+	// Kind is 'J' for Jn (day 1-365, never counting February 29), 'N' for n
+	// (day 0-365, counting February 29) or 'M' for Mm.w.d
+	Kind        byte
+	Day         int
+	Month, Week int
+	Weekday     time.Weekday
+	Time        int // seconds after local midnight, from -167h to 167h
+}
+
+// posixDefaultRule is the rule tzcode and Go assume when a TZ string names a
+// daylight saving zone without one: the US rules since 2007
+const posixDefaultRule = ",M3.2.0,M11.1.0"
+
+// parsePosixTZ parses a POSIX TZ string: std offset [dst [offset] [,start[/time],end[/time]]]
+func parsePosixTZ(s string) (posixTZ, error) {
+	var tz posixTZ
+	rest := s
+	var err error
+	if tz.Std, rest, err = posixTZName(rest); err != nil {
+		return tz, err
+	}
+	var offset int
+	if offset, rest, err = posixTZOffset(rest, 24); err != nil {
+		return tz, fmt.Errorf("standard offset: %w", err)
+	}
+	tz.StdOffset = -offset
+	if rest == "" {
+		return tz, nil
+	}
+
+	if tz.Dst, rest, err = posixTZName(rest); err != nil {
+		return tz, err
+	}
+	tz.DstOffset = tz.StdOffset + 3600
+	if rest != "" && rest[0] != ',' {
+		if offset, rest, err = posixTZOffset(rest, 24); err != nil {
+			return tz, fmt.Errorf("daylight offset: %w", err)
+		}
+		tz.DstOffset = -offset
+	}
+	if rest == "" {
+		rest = posixDefaultRule
+	}
+	if rest[0] != ',' {
+		return tz, fmt.Errorf("unexpected %q after the daylight zone", rest)
+	}
+	if tz.Start, rest, err = posixTZRuleAt(rest[1:]); err != nil {
+		return tz, fmt.Errorf("start rule: %w", err)
+	}
+	if rest == "" || rest[0] != ',' {
+		return tz, errors.New("expected a comma and an end rule")
+	}
+	if tz.End, rest, err = posixTZRuleAt(rest[1:]); err != nil {
+		return tz, fmt.Errorf("end rule: %w", err)
+	}
+	if rest != "" {
+		return tz, fmt.Errorf("unexpected %q at the end", rest)
+	}
+	return tz, nil
+}
+
+// posixTZName parses a zone abbreviation: three or more letters, or a quoted
+// <...> form that may contain digits and signs, e.g. <+0330>
+func posixTZName(s string) (string, string, error) {
+	if strings.HasPrefix(s, "<") {
+		end := strings.IndexByte(s, '>')
+		if end < 0 {
+			return "", "", errors.New("unterminated <")
+		}
+		name := s[1:end]
+		if len(name) < 3 || strings.IndexFunc(name, func(r rune) bool {
+			return !(r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '+' || r == '-')
+		}) >= 0 {
+			return "", "", fmt.Errorf("invalid zone name <%s>", name)
+		}
+		return name, s[end+1:], nil
+	}
+	n := 0
+	for n < len(s) && (s[n] >= 'A' && s[n] <= 'Z' || s[n] >= 'a' && s[n] <= 'z') {
+		n++
+	}
+	if n < 3 {
+		return "", "", fmt.Errorf("zone name must have at least 3 letters at %q", s)
+	}
+	return s[:n], s[n:], nil
+}
+
+// posixTZOffset parses [+-]hh[:mm[:ss]] into seconds, with hours up to maxHours
+func posixTZOffset(s string, maxHours int) (int, string, error) {
+	sign := 1
+	if s != "" && (s[0] == '+' || s[0] == '-') {
+		if s[0] == '-' {
+			sign = -1
+		}
+		s = s[1:]
+	}
+	var parts [3]int
+	for i := range parts {
+		n := 0
+		for n < len(s) && n < 3 && s[n] >= '0' && s[n] <= '9' {
+			parts[i] = parts[i]*10 + int(s[n]-'0')
+			n++
+		}
+		if n == 0 || (i > 0 && (n != 2 || parts[i] > 59)) {
+			return 0, "", fmt.Errorf("invalid time at %q", s)
+		}
+		s = s[n:]
+		if i == 2 || s == "" || s[0] != ':' {
+			break
+		}
+		s = s[1:]
+	}
+	if parts[0] > maxHours {
+		return 0, "", fmt.Errorf("hours out of range 0-%d", maxHours)
+	}
+	return sign * (parts[0]*3600 + parts[1]*60 + parts[2]), s, nil
+}
+
+// posixTZRuleAt parses date[/time]: Jn, n or Mm.w.d
+func posixTZRuleAt(s string) (posixTZRule, string, error) {
+	rule := posixTZRule{Time: 2 * 3600}
+	number := func(lo, hi int) (int, error) {
+		n, v := 0, 0
+		for n < len(s) && n < 3 && s[n] >= '0' && s[n] <= '9' {
+			v = v*10 + int(s[n]-'0')
+			n++
+		}
+		if n == 0 || v < lo || v > hi {
+			return 0, fmt.Errorf("expected a number from %d to %d at %q", lo, hi, s)
+		}
+		s = s[n:]
+		return v, nil
+	}
+	var err error
+	switch {
+	case strings.HasPrefix(s, "J"):
+		s = s[1:]
+		rule.Kind = 'J'
+		rule.Day, err = number(1, 365)
+	case strings.HasPrefix(s, "M"):
+		s = s[1:]
+		rule.Kind = 'M'
+		if rule.Month, err = number(1, 12); err != nil {
+			return rule, "", err
+		}
+		if !strings.HasPrefix(s, ".") {
+			return rule, "", errors.New("expected Mm.w.d")
+		}
+		s = s[1:]
+		if rule.Week, err = number(1, 5); err != nil {
+			return rule, "", err
+		}
+		if !strings.HasPrefix(s, ".") {
+			return rule, "", errors.New("expected Mm.w.d")
+		}
+		s = s[1:]
+		var d int
+		d, err = number(0, 6)
+		rule.Weekday = time.Weekday(d)
+	default:
+		rule.Kind = 'N'
+		rule.Day, err = number(0, 365)
+	}
+	if err != nil {
+		return rule, "", err
+	}
+	if strings.HasPrefix(s, "/") {
+		if rule.Time, s, err = posixTZOffset(s[1:], 167); err != nil {
+			return rule, "", err
+		}
+	}
+	return rule, s, nil
+}
+
+// describe phrases the date of a rule, e.g. "the second Sunday of March"
+func (r posixTZRule) describe() string {
+	switch r.Kind {
+	case 'J':
+		// Counted in a common year, so J60 is always March 1
+		date := time.Date(2025, time.January, r.Day, 0, 0, 0, 0, time.UTC)
+		return fmt.Sprintf("%s %d", date.Month(), date.Day())
+	case 'N':
+		return fmt.Sprintf("day %d of the year counting from 0, February 29 included", r.Day)
+	}
+	ordinal := map[int]string{1: "first", 2: "second", 3: "third", 4: "fourth", 5: "last"}[r.Week]
+	return fmt.Sprintf("the %s %s of %s", ordinal, r.Weekday, time.Month(r.Month))
+}
+
+// formatPosixTime formats a rule time, which may be negative or past 24:00
+func formatPosixTime(seconds int) string {
+	sign := ""
+	if seconds < 0 {
+		sign, seconds = "-", -seconds
+	}
+	s := fmt.Sprintf("%s%02d:%02d", sign, seconds/3600, seconds/60%60)
+	if seconds%60 != 0 {
+		s += fmt.Sprintf(":%02d", seconds%60)
+	}
+	return s
+}
+
+// describe explains the zone in plain language
+func (tz posixTZ) describe() string {
+	std := fmt.Sprintf("Standard time %s at UTC%s", tz.Std, formatOffsetClock(tz.StdOffset))
+	if tz.Dst == "" {
+		return std + " all year, without daylight saving time."
+	}
+	return fmt.Sprintf("%s; daylight saving time %s at UTC%s from %s on %s, local standard time, until %s on %s, local daylight time.",
+		std, tz.Dst, formatOffsetClock(tz.DstOffset),
+		formatPosixTime(tz.Start.Time), tz.Start.describe(), formatPosixTime(tz.End.Time), tz.End.describe())
+}
+
+// formatOffsetClock formats an offset east of UTC as +HH:MM
+func formatOffsetClock(seconds int) string {
+	return time.Unix(0, 0).In(time.FixedZone("", seconds)).Format("-07:00")
+}
+
+// location builds a time.Location that follows the TZ string, as a TZif file
+// whose footer is the string itself: with no transitions listed, Go applies
+// the footer rule to every instant
+func (tz posixTZ) location(name string) (*time.Location, error) {
+	var buf bytes.Buffer
+	for range 2 {
+		// Version 2 headers and data; the first copy is the 32-bit section
+		buf.WriteString("TZif2")
+		buf.Write(make([]byte, 15))
+		for _, count := range []uint32{0, 0, 0, 0, 1, uint32(len(tz.Std) + 1)} {
+			_ = binary.Write(&buf, binary.BigEndian, count)
+		}
+		_ = binary.Write(&buf, binary.BigEndian, int32(tz.StdOffset))
+		buf.Write([]byte{0, 0})
+		buf.WriteString(tz.Std + "\x00")
+	}
+	buf.WriteString("\n" + name + "\n")
+	return time.LoadLocationFromTZData(name, buf.Bytes())
+}
+
+// posixTZInfo is the structured result of parse_posix_tz
+type posixTZInfo struct {
+	TZ          string          `json:"tz"`
+	Standard    posixTZZone     `json:"standard"`
+	Daylight    *posixTZZone    `json:"daylight,omitempty"`
+	DefaultRule bool            `json:"default_rule,omitempty" jsonschema:"Whether the string names a daylight zone without rules, so the US rules M3.2.0,M11.1.0 apply"`
+	Description string          `json:"description"`
+	Year        int             `json:"year"`
+	Transitions []dstTransition `json:"transitions" jsonschema:"The changes in year, as dst_calendar lists them"`
+}
+
+type posixTZZone struct {
+	Abbreviation string `json:"abbreviation"`
+	UTCOffset    string `json:"utc_offset"`
+	Starts       string `json:"starts,omitempty" jsonschema:"When daylight saving time starts, in local standard time"`
+	Ends         string `json:"ends,omitempty" jsonschema:"When daylight saving time ends, in local daylight time"`
+}
+
+func addParsePosixTZTool(mcpServer *server.MCPServer, config *Config) {
+	mcpServer.AddTool(
+		mcp.NewTool("parse_posix_tz",
+			mcp.WithDescription("Explain a POSIX TZ string such as EST5EDT,M3.2.0,M11.1.0 in plain language: the standard and daylight zones with their UTC offsets, when daylight saving time starts and ends, and the resulting transitions in a year. POSIX TZ strings are also accepted anywhere a timezone is."),
+			mcp.WithString("tz",
+				mcp.Description("The POSIX TZ string, e.g. CET-1CEST,M3.5.0,M10.5.0/3 or <+0530>-5:30. Note the offset sign is inverted: EST5 is UTC-05:00."),
+				mcp.Required(),
+			),
+			mcp.WithNumber("year",
+				mcp.Description("Year to list transitions for. Defaults to the current year."),
+			),
+			mcp.WithOutputSchema[posixTZInfo](),
+			mcp.WithTitleAnnotation("Parse POSIX TZ"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		handleParsePosixTZ(config),
+	)
+}
+
+// handleParsePosixTZ returns a handler for the parse_posix_tz tool
+func handleParsePosixTZ(config *Config) server.ToolHandlerFunc {
+	return func(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		tzStr, err := request.RequireString("tz")
+		if err != nil {
+			return toolError(errorInvalidArgument, err.Error()), nil
+		}
+		tz, err := parsePosixTZ(tzStr)
+		if err != nil {
+			return toolError(errorParse, fmt.Sprintf("Invalid POSIX TZ string %q: %v", tzStr, err)), nil
+		}
+		year := request.GetInt("year", config.now().Year())
+		if year < 1 || year > 9999 {
+			return toolError(errorInvalidArgument, fmt.Sprintf("Invalid year: %d. Please provide 1 to 9999.", year)), nil
+		}
+		loc, err := tz.location(tzStr)
+		if err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to build a location for %q: %v", tzStr, err)), nil
+		}
+
+		info := posixTZInfo{
+			TZ:          tzStr,
+			Standard:    posixTZZone{Abbreviation: tz.Std, UTCOffset: formatOffsetClock(tz.StdOffset)},
+			Description: tz.describe(),
+			Year:        year,
+			Transitions: []dstTransition{},
+		}
+		if tz.Dst != "" {
+			info.Daylight = &posixTZZone{
+				Abbreviation: tz.Dst,
+				UTCOffset:    formatOffsetClock(tz.DstOffset),
+				Starts:       fmt.Sprintf("%s on %s", formatPosixTime(tz.Start.Time), tz.Start.describe()),
+				Ends:         fmt.Sprintf("%s on %s", formatPosixTime(tz.End.Time), tz.End.describe()),
+			}
+			info.DefaultRule = !strings.Contains(tzStr, ",")
+		}
+		from := time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+		for _, tr := range zoneTransitions(loc, from, from.AddDate(1, 0, 0)) {
+			info.Transitions = append(info.Transitions, newDSTTransition(tr, loc))
+		}
+
+		data, err := json.Marshal(info)
+		if err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to encode result: %v", err)), nil
+		}
+		return mcp.NewToolResultStructured(info, string(data)), nil
+	}
+}
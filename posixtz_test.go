@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestParsePosixTZ(t *testing.T) {
+	tz, err := parsePosixTZ("CET-1CEST,M3.5.0,M10.5.0/3")
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	if tz.Std != "CET" || tz.StdOffset != 3600 || tz.Dst != "CEST" || tz.DstOffset != 7200 ||
+		tz.Start != (posixTZRule{Kind: 'M', Month: 3, Week: 5, Weekday: time.Sunday, Time: 7200}) || tz.End.Time != 3*3600 {
+		t.Errorf("Expected central European rules but got %+v", tz)
+	}
+
+	tz, err = parsePosixTZ("<+0330>-3:30<+0430>,J79/24,J263/24")
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	if tz.Std != "+0330" || tz.StdOffset != 3*3600+30*60 || tz.Start.Kind != 'J' || tz.Start.Day != 79 || tz.Start.Time != 24*3600 {
+		t.Errorf("Expected quoted names and Julian days but got %+v", tz)
+	}
+	if got := tz.Start.describe(); got != "March 20" {
+		t.Errorf("Expected J79 to be March 20 but got %q", got)
+	}
+
+	// A daylight zone without rules follows the US rules
+	if tz, err = parsePosixTZ("EST5EDT"); err != nil || tz.Start.Month != 3 || tz.End.Month != 11 {
+		t.Errorf("Expected the default rules but got %+v (%v)", tz, err)
+	}
+
+	for _, s := range []string{"", "EST", "ES5", "EST25", "EST5EDT,M3.2.0", "EST5EDT,M13.2.0,M11.1.0", "EST5EDT,M3.6.0,M11.1.0",
+		"EST5EDT,M3.2.7,M11.1.0", "EST5EDT,J0,J100", "EST5EDT,M3.2.0/200,M11.1.0", "<AB>5", "<EST5", "EST5EDT;M3.2.0,M11.1.0", "Europe/Nowhere"} {
+		if _, err := parsePosixTZ(s); err == nil {
+			t.Errorf("Expected an error for %q", s)
+		}
+	}
+}
+
+func TestPosixTZLocation(t *testing.T) {
+	tests := []struct {
+		tz             string
+		winter, summer string
+	}{
+		{"EST5EDT,M3.2.0,M11.1.0", "-05:00 EST", "-04:00 EDT"},
+		{"AEST-10AEDT,M10.1.0,M4.1.0/3", "+11:00 AEDT", "+10:00 AEST"},
+		{"<+0530>-5:30", "+05:30 +0530", "+05:30 +0530"},
+		{"UTC0", "+00:00 UTC", "+00:00 UTC"},
+	}
+	for _, tt := range tests {
+		// Strings outside the timezone database are accepted wherever a zone is
+		loc, err := loadLocationCached(tt.tz)
+		if err != nil {
+			t.Fatalf("Failed to load %q: %v", tt.tz, err)
+		}
+		winter := time.Date(2025, 1, 15, 12, 0, 0, 0, loc).Format("-07:00 MST")
+		summer := time.Date(2025, 7, 15, 12, 0, 0, 0, loc).Format("-07:00 MST")
+		if winter != tt.winter || summer != tt.summer {
+			t.Errorf("%s: expected %s and %s but got %s and %s", tt.tz, tt.winter, tt.summer, winter, summer)
+		}
+	}
+	if _, err := loadLocationCached("Mars/Olympus"); err == nil {
+		t.Errorf("Expected an error for an unknown zone")
+	}
+}
+
+func TestParsePosixTZTool(t *testing.T) {
+	clock := newFakeClock(time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC))
+	handler := handleParsePosixTZ(&Config{Clock: clock})
+	call := func(args map[string]any) *mcp.CallToolResult {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := handler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("Failed to call handler: %v", err)
+		}
+		return result
+	}
+
+	result := call(map[string]any{"tz": "EST5EDT,M3.2.0,M11.1.0"})
+	if result.IsError {
+		t.Fatalf("Failed to parse: %s", toolResultText(result))
+	}
+	got := result.StructuredContent.(posixTZInfo)
+	if got.Standard.UTCOffset != "-05:00" || got.Daylight == nil || got.Daylight.UTCOffset != "-04:00" ||
+		got.Daylight.Starts != "02:00 on the second Sunday of March" || got.DefaultRule || got.Year != 2025 {
+		t.Errorf("Expected US Eastern rules but got %+v", got)
+	}
+	if len(got.Transitions) != 2 || got.Transitions[0].At != "2025-03-09T07:00:00Z" || got.Transitions[1].At != "2025-11-02T06:00:00Z" {
+		t.Errorf("Expected the 2025 transitions but got %+v", got.Transitions)
+	}
+	if !strings.Contains(got.Description, "until 02:00 on the first Sunday of November") {
+		t.Errorf("Unexpected description %q", got.Description)
+	}
+
+	got = call(map[string]any{"tz": "NZST-12NZDT", "year": 2024}).StructuredContent.(posixTZInfo)
+	if !got.DefaultRule || got.Year != 2024 || len(got.Transitions) != 2 {
+		t.Errorf("Expected the default rules in 2024 but got %+v", got)
+	}
+
+	for _, args := range []map[string]any{{}, {"tz": "EST5EDT,M3.2.0"}, {"tz": "UTC0", "year": 0}} {
+		if result := call(args); !result.IsError {
+			t.Errorf("Expected an error for %v but got %s", args, toolResultText(result))
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// preferencesKeyPrefix namespaces per-user preferences in the state store
+const preferencesKeyPrefix = "preferences:"
+
+// userPreferences are settings a user saves once for tools to reuse
+type userPreferences struct {
+	// QuietHours are daily clock ranges such as "22:00-07:00"
+	QuietHours []string  `json:"quiet_hours,omitempty"`
+	Timezone   string    `json:"timezone,omitempty"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// preferenceStore keeps userPreferences in the state store, keyed by user
+// ID; unauthenticated callers share one entry
+type preferenceStore struct {
+	store stateStore
+}
+
+func newPreferenceStore(store stateStore) *preferenceStore {
+	return &preferenceStore{store: store}
+}
+
+// load returns the user's preferences, or nil if none are saved
+func (p *preferenceStore) load(ctx context.Context, user string) (*userPreferences, error) {
+	data, ok, err := p.store.get(ctx, preferencesKeyPrefix+user)
+	if err != nil || !ok {
+		return nil, err
+	}
+	var prefs userPreferences
+	if err := json.Unmarshal(data, &prefs); err != nil {
+		return nil, fmt.Errorf("corrupt preferences for %q: %w", user, err)
+	}
+	return &prefs, nil
+}
+
+func (p *preferenceStore) save(ctx context.Context, user string, prefs *userPreferences) error {
+	data, err := json.Marshal(prefs)
+	if err != nil {
+		return err
+	}
+	return p.store.set(ctx, preferencesKeyPrefix+user, data, 0)
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// probeTimezone is loaded by the readiness probe to confirm the tz database is usable
+const probeTimezone = "America/New_York"
+
+// readinessCheck is one named dependency check
+type readinessCheck struct {
+	name  string
+	check func() error
+}
+
+// readinessChecks lists the dependencies that must be available before the
+// server should receive traffic
+func (p *httpRuntime) readinessChecks(config *Config) []readinessCheck {
+	checks := []readinessCheck{{"tzdata", func() error { return checkTimezoneData(config) }}}
+	if config.AuthEnabled {
+		checks = append(checks, readinessCheck{"auth", func() error {
+			if p == nil || p.contextFunc == nil {
+				return nil
+			}
+			auth := p.contextFunc.auth()
+			if auth == nil {
+				return fmt.Errorf("authentication is not initialized")
+			}
+			return auth.ready()
+		}})
+	}
+	if p != nil && p.store != nil {
+		checks = append(checks, readinessCheck{"store", func() error {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			return p.store.ping(ctx)
+		}})
+	}
+	if p != nil && p.drainer != nil {
+		checks = append(checks, readinessCheck{"shutdown", func() error {
+			if p.drainer.draining.Load() {
+				return fmt.Errorf("server is draining")
+			}
+			return nil
+		}})
+	}
+	return checks
+}
+
+// checkTimezoneData verifies zone lookups work, including the configured default
+func checkTimezoneData(config *Config) error {
+	if _, err := time.LoadLocation(probeTimezone); err != nil {
+		return fmt.Errorf("timezone database unavailable: %w", err)
+	}
+	if config.DefaultTimezone != "" {
+		if _, err := loadLocationCached(config.DefaultTimezone); err != nil {
+			return fmt.Errorf("default timezone %s unavailable: %w", config.DefaultTimezone, err)
+		}
+	}
+	return nil
+}
+
+// addProbeEndpoints registers /livez, which succeeds while the process is
+// serving, and /readyz, which returns 503 until every dependency check passes
+func addProbeEndpoints(mux *http.ServeMux, config *Config, rt *httpRuntime) {
+	mux.HandleFunc("/livez", func(w http.ResponseWriter, r *http.Request) {
+		writeJSONResponse(w, r, http.StatusOK, map[string]any{"status": "ok"})
+	})
+
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		status := http.StatusOK
+		results := make(map[string]string)
+		for _, c := range rt.readinessChecks(config) {
+			if err := c.check(); err != nil {
+				slog.WarnContext(r.Context(), "Readiness check failed", "check", c.name, "error", err)
+				results[c.name] = err.Error()
+				status = http.StatusServiceUnavailable
+				continue
+			}
+			results[c.name] = "ok"
+		}
+
+		body := map[string]any{"status": "ready", "checks": results}
+		if status != http.StatusOK {
+			body["status"] = "not ready"
+		}
+		writeJSONResponse(w, r, status, body)
+	})
+}
+
+// writeJSONResponse writes an uncached JSON response
+func writeJSONResponse(w http.ResponseWriter, r *http.Request, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		slog.ErrorContext(r.Context(), "Failed to encode JSON response", "error", err)
+	}
+}
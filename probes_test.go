@@ -0,0 +1,77 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestProbeEndpoints(t *testing.T) {
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	var available atomic.Bool
+	jwksServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !available.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{rsaJWK("k1", &key.PublicKey)}})
+	}))
+	defer jwksServer.Close()
+
+	config := &Config{
+		AuthEnabled:     true,
+		AuthIssuer:      "test-issuer",
+		AuthAudience:    "test-audience",
+		AuthJWKSURL:     jwksServer.URL,
+		AuthJWKSRefresh: time.Hour,
+	}
+	contextFunc := &reloadableContextFunc{}
+	fn, auth, err := createHTTPMiddleware(config)
+	if err != nil {
+		t.Fatalf("Failed to create HTTP middleware: %v", err)
+	}
+	contextFunc.store(fn, auth)
+	rt := &httpRuntime{contextFunc: contextFunc, drainer: newConnectionDrainer()}
+
+	mux := http.NewServeMux()
+	addProbeEndpoints(mux, config, rt)
+	probe := func(path string) (int, map[string]any) {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		var body map[string]any
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode %s response: %v", path, err)
+		}
+		return rec.Code, body
+	}
+
+	if code, _ := probe("/livez"); code != http.StatusOK {
+		t.Errorf("Expected /livez to return 200 but got %d", code)
+	}
+
+	code, body := probe("/readyz")
+	if code != http.StatusServiceUnavailable {
+		t.Errorf("Expected /readyz to return 503 while JWKS is unavailable but got %d", code)
+	}
+	if checks, _ := body["checks"].(map[string]any); checks["tzdata"] != "ok" || checks["auth"] == "ok" {
+		t.Errorf("Expected tzdata ok and auth failing but got %v", body["checks"])
+	}
+
+	available.Store(true)
+	if code, body := probe("/readyz"); code != http.StatusOK {
+		t.Errorf("Expected /readyz to return 200 once JWKS is reachable but got %d: %v", code, body)
+	}
+
+	rt.drainer.draining.Store(true)
+	if code, _ := probe("/readyz"); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected /readyz to return 503 while draining but got %d", code)
+	}
+	if code, _ := probe("/livez"); code != http.StatusOK {
+		t.Errorf("Expected /livez to stay 200 while draining but got %d", code)
+	}
+}
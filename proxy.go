@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+const forwardedProtoKey contextKey = "forwarded_proto"
+
+// parseCIDRList parses a comma-separated list of CIDRs or bare IP addresses
+func parseCIDRList(list string) ([]*net.IPNet, error) {
+	var nets []*net.IPNet
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid IP address %q", entry)
+			}
+			bits := 128
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 32
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q", entry)
+		}
+		nets = append(nets, ipNet)
+	}
+	return nets, nil
+}
+
+// ipInNets reports whether ip belongs to any of the networks
+func ipInNets(ip net.IP, nets []*net.IPNet) bool {
+	if ip == nil {
+		return false
+	}
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteIP extracts the IP address from a request's RemoteAddr
+func remoteIP(r *http.Request) net.IP {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return net.ParseIP(host)
+}
+
+// resolveClientIP determines the real client IP for a request received from a
+// trusted proxy. The hops of every X-Forwarded-For line are walked from the
+// right, skipping trusted proxies, so a client cannot spoof its address by
+// prepending entries or sending a header line of its own; a malformed hop
+// stops the walk at the nearest trusted one. X-Real-IP is used when
+// X-Forwarded-For is absent. Headers from other peers are ignored.
+func resolveClientIP(r *http.Request, trusted []*net.IPNet) net.IP {
+	peer := remoteIP(r)
+	if !ipInNets(peer, trusted) {
+		return peer
+	}
+
+	if hops := forwardedHops(r); len(hops) > 0 {
+		client := peer
+		for i := len(hops) - 1; i >= 0; i-- {
+			ip := net.ParseIP(hops[i])
+			if ip == nil {
+				break
+			}
+			client = ip
+			if !ipInNets(ip, trusted) {
+				break
+			}
+		}
+		return client
+	}
+
+	if ip := net.ParseIP(strings.TrimSpace(r.Header.Get("X-Real-IP"))); ip != nil {
+		return ip
+	}
+	return peer
+}
+
+// forwardedHops returns the X-Forwarded-For entries of all header lines, in
+// order, the nearest proxy last
+func forwardedHops(r *http.Request) []string {
+	var hops []string
+	for _, line := range r.Header.Values("X-Forwarded-For") {
+		for _, hop := range strings.Split(line, ",") {
+			if hop = strings.TrimSpace(hop); hop != "" {
+				hops = append(hops, hop)
+			}
+		}
+	}
+	return hops
+}
+
+// realIPHandler rewrites RemoteAddr to the real client IP and records the
+// forwarded protocol when the request arrives through a trusted proxy
+func realIPHandler(next http.Handler, trusted []*net.IPNet) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !ipInNets(remoteIP(r), trusted) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if ip := resolveClientIP(r, trusted); ip != nil {
+			r.RemoteAddr = net.JoinHostPort(ip.String(), "0")
+		}
+		if proto := strings.ToLower(strings.TrimSpace(r.Header.Get("X-Forwarded-Proto"))); proto == "http" || proto == "https" {
+			r = r.WithContext(context.WithValue(r.Context(), forwardedProtoKey, proto))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requestScheme returns the scheme the client used, honoring X-Forwarded-Proto
+// from trusted proxies
+func requestScheme(r *http.Request) string {
+	if proto, ok := r.Context().Value(forwardedProtoKey).(string); ok {
+		return proto
+	}
+	if r.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResolveClientIP(t *testing.T) {
+	trusted, err := parseCIDRList("10.0.0.0/8, 192.168.1.1")
+	if err != nil {
+		t.Fatalf("Failed to parse trusted proxies: %v", err)
+	}
+
+	testCases := []struct {
+		name       string
+		remoteAddr string
+		xff        []string
+		xRealIP    string
+		expected   string
+	}{
+		{
+			name:       "Untrusted Peer Ignores Headers",
+			remoteAddr: "203.0.113.5:1234",
+			xff:        []string{"198.51.100.1"},
+			expected:   "203.0.113.5",
+		},
+		{
+			name:       "Trusted Peer Uses Forwarded For",
+			remoteAddr: "10.1.2.3:1234",
+			xff:        []string{"198.51.100.1"},
+			expected:   "198.51.100.1",
+		},
+		{
+			name:       "Spoofed Leftmost Entry Is Skipped",
+			remoteAddr: "10.1.2.3:1234",
+			xff:        []string{"1.2.3.4, 198.51.100.1, 10.9.9.9"},
+			expected:   "198.51.100.1",
+		},
+		{
+			name:       "Spoofed Header Line Is Skipped",
+			remoteAddr: "10.1.2.3:1234",
+			xff:        []string{"1.2.3.4", "198.51.100.1, 10.9.9.9"},
+			expected:   "198.51.100.1",
+		},
+		{
+			name:       "Last Header Line Is Nearest",
+			remoteAddr: "10.1.2.3:1234",
+			xff:        []string{"1.2.3.4", "198.51.100.1", "10.9.9.9"},
+			expected:   "198.51.100.1",
+		},
+		{
+			name:       "Malformed Hop Stops At Trusted Proxy",
+			remoteAddr: "10.1.2.3:1234",
+			xff:        []string{"198.51.100.1, garbage, 10.9.9.9"},
+			xRealIP:    "1.2.3.4",
+			expected:   "10.9.9.9",
+		},
+		{
+			name:       "All Hops Trusted",
+			remoteAddr: "10.1.2.3:1234",
+			xff:        []string{"10.8.8.8, 10.9.9.9"},
+			expected:   "10.8.8.8",
+		},
+		{
+			name:       "Untrusted Peer Ignores Real IP",
+			remoteAddr: "203.0.113.5:1234",
+			xRealIP:    "198.51.100.7",
+			expected:   "203.0.113.5",
+		},
+		{
+			name:       "Trusted Peer Uses Real IP",
+			remoteAddr: "192.168.1.1:1234",
+			xRealIP:    "198.51.100.7",
+			expected:   "198.51.100.7",
+		},
+		{
+			name:       "Trusted Peer Without Headers",
+			remoteAddr: "10.1.2.3:1234",
+			expected:   "10.1.2.3",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.RemoteAddr = tc.remoteAddr
+			for _, line := range tc.xff {
+				req.Header.Add("X-Forwarded-For", line)
+			}
+			if tc.xRealIP != "" {
+				req.Header.Set("X-Real-IP", tc.xRealIP)
+			}
+
+			ip := resolveClientIP(req, trusted)
+			if ip.String() != tc.expected {
+				t.Errorf("Expected client IP to be %s, but got %s", tc.expected, ip)
+			}
+		})
+	}
+}
+
+func TestRealIPHandler_ForwardedProto(t *testing.T) {
+	trusted, _ := parseCIDRList("10.0.0.0/8")
+
+	var gotAddr, gotScheme string
+	handler := realIPHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAddr = remoteIP(r).String()
+		gotScheme = requestScheme(r)
+	}), trusted)
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "10.0.0.1:5555"
+	req.Header.Set("X-Forwarded-For", "198.51.100.1")
+	req.Header.Set("X-Forwarded-Proto", "https")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if gotAddr != "198.51.100.1" {
+		t.Errorf("Expected RemoteAddr to be rewritten to 198.51.100.1, but got %s", gotAddr)
+	}
+	if gotScheme != "https" {
+		t.Errorf("Expected scheme to be https, but got %s", gotScheme)
+	}
+}
+
+func TestParseCIDRList_Invalid(t *testing.T) {
+	if _, err := parseCIDRList("10.0.0.0/8,not-an-ip"); err == nil {
+		t.Fatal("Expected an error for invalid entry, but got nil")
+	}
+}
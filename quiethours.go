@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// maxQuietHours caps the ranges in one quiet-hours configuration
+const maxQuietHours = 10
+
+// deliverySchedule is the structured result of notification_schedule
+type deliverySchedule struct {
+	QuietHours []string `json:"quiet_hours"`
+	Timezone   string   `json:"timezone"`
+	ComposedAt string   `json:"composed_at" jsonschema:"When the message was composed, in RFC 3339"`
+	DeliverAt  string   `json:"deliver_at" jsonschema:"The first instant outside quiet hours, in RFC 3339"`
+	Deferred   bool     `json:"deferred" jsonschema:"Whether delivery waits for quiet hours to end"`
+	Saved      bool     `json:"saved,omitempty" jsonschema:"Whether the quiet hours were saved as the caller's preference"`
+}
+
+// parseQuietHours parses comma-separated daily ranges such as
+// "12:00-13:00, 22:00-07:00" in loc
+func parseQuietHours(spec string, loc *time.Location) ([]string, []timeWindow, error) {
+	var ranges []string
+	var windows []timeWindow
+	for part := range strings.SplitSeq(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		w, err := parseWindow(part, loc, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		if _, ok := w.(dailyWindow); !ok {
+			return nil, nil, &windowSpecError{fmt.Sprintf("Invalid quiet hours: %s. Use daily ranges such as 22:00-07:00.", part)}
+		}
+		ranges = append(ranges, part)
+		windows = append(windows, w)
+	}
+	if len(ranges) == 0 {
+		return nil, nil, &windowSpecError{"Quiet hours are empty. Use daily ranges such as 22:00-07:00."}
+	}
+	if len(ranges) > maxQuietHours {
+		return nil, nil, &windowSpecError{fmt.Sprintf("Too many quiet hour ranges: %d (maximum %d)", len(ranges), maxQuietHours)}
+	}
+	return ranges, windows, nil
+}
+
+// nextAllowedDelivery returns the first instant at or after t outside every
+// window, or false when overlapping windows cover the whole day
+func nextAllowedDelivery(windows []timeWindow, t time.Time) (time.Time, bool) {
+	// Each pass leaves at least one window; chains longer than this wrap
+	// around the clock
+	for range 2*len(windows) + 2 {
+		moved := false
+		for _, w := range windows {
+			if !w.contains(t) {
+				continue
+			}
+			next := nextWindowBoundary(w, t)
+			if next.IsZero() {
+				return time.Time{}, false
+			}
+			t, moved = next, true
+		}
+		if !moved {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// addNotificationScheduleTool registers notification_schedule
+func addNotificationScheduleTool(mcpServer *server.MCPServer, prefs *preferenceStore, config *Config) {
+	mcpServer.AddTool(
+		mcp.NewTool("notification_schedule",
+			mcp.WithDescription("Return when a message composed now, or at a given time, may be delivered to a user with quiet hours (do not disturb). Quiet hours and timezone can be saved as the caller's preference and are then used when omitted."),
+			mcp.WithString("quiet_hours",
+				mcp.Description("Comma-separated daily ranges, e.g. '22:00-07:00' or '12:00-13:00, 22:00-07:00'. Defaults to the caller's saved quiet hours."),
+			),
+			mcp.WithString("timezone",
+				mcp.Description("The user's timezone. Defaults to the saved timezone, then the session or server default."),
+			),
+			mcp.WithString("time",
+				mcp.Description("When the message is composed, in RFC 3339 or 'YYYY-MM-DD HH:MM' in the timezone. Defaults to now."),
+			),
+			mcp.WithBoolean("save",
+				mcp.Description("Save the quiet hours and timezone as the caller's preference."),
+			),
+			mcp.WithOutputSchema[deliverySchedule](),
+			mcp.WithTitleAnnotation("Notification Schedule"),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		handleNotificationSchedule(prefs, config),
+	)
+}
+
+// handleNotificationSchedule returns a handler for the notification_schedule tool
+func handleNotificationSchedule(prefs *preferenceStore, config *Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		user, _, _ := getUserInfo(ctx)
+		saved, err := prefs.load(ctx, user)
+		if err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to read preferences: %v", err)), nil
+		}
+
+		spec := request.GetString("quiet_hours", "")
+		timezoneStr := request.GetString("timezone", "")
+		if saved != nil {
+			if spec == "" {
+				spec = strings.Join(saved.QuietHours, ", ")
+			}
+			if timezoneStr == "" {
+				timezoneStr = saved.Timezone
+			}
+		}
+		if spec == "" {
+			return toolError(errorInvalidArgument, "Provide 'quiet_hours', or save them first with 'save'"), nil
+		}
+		loc, err := loadTimezone(ctx, timezoneStr, config)
+		if err != nil {
+			return invalidTimezoneError("Invalid timezone", timezoneStr), nil
+		}
+		ranges, windows, err := parseQuietHours(spec, loc)
+		if err != nil {
+			var specErr *windowSpecError
+			if errors.As(err, &specErr) {
+				return toolError(errorParse, specErr.Error()), nil
+			}
+			return toolError(errorInvalidTimezone, fmt.Sprintf("Invalid quiet hours timezone: %v", err)), nil
+		}
+
+		composed := config.now()
+		if timeStr := request.GetString("time", ""); timeStr != "" {
+			if composed, err = time.Parse(time.RFC3339, timeStr); err != nil {
+				if composed, err = time.ParseInLocation("2006-01-02 15:04", timeStr, loc); err != nil {
+					return toolError(errorParse, fmt.Sprintf("Invalid time: %s. Please provide RFC 3339 or 'YYYY-MM-DD HH:MM'.", timeStr)), nil
+				}
+			}
+		}
+		deliver, ok := nextAllowedDelivery(windows, composed)
+		if !ok {
+			return toolError(errorInvalidArgument, fmt.Sprintf("Quiet hours %s cover the whole day, so nothing can be delivered", strings.Join(ranges, ", "))), nil
+		}
+
+		schedule := deliverySchedule{
+			QuietHours: ranges,
+			Timezone:   loc.String(),
+			ComposedAt: composed.In(loc).Format(time.RFC3339),
+			DeliverAt:  deliver.In(loc).Format(time.RFC3339),
+			Deferred:   !deliver.Equal(composed),
+		}
+		if request.GetBool("save", false) {
+			err := prefs.save(ctx, user, &userPreferences{QuietHours: ranges, Timezone: loc.String(), UpdatedAt: config.now().UTC()})
+			if err != nil {
+				return toolError(errorInternal, fmt.Sprintf("Failed to save preferences: %v", err)), nil
+			}
+			slog.InfoContext(ctx, "Quiet hours saved", "quiet_hours", ranges, "timezone", loc.String())
+			schedule.Saved = true
+		}
+
+		data, err := json.Marshal(schedule)
+		if err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to encode result: %v", err)), nil
+		}
+		return mcp.NewToolResultStructured(schedule, string(data)), nil
+	}
+}
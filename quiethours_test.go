@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestNotificationSchedule(t *testing.T) {
+	// 21:30 UTC is 22:30 in Warsaw
+	clock := newFakeClock(time.Date(2025, 12, 23, 21, 30, 0, 0, time.UTC))
+	config := &Config{DefaultTimezone: "UTC", Clock: clock}
+	handler := handleNotificationSchedule(newPreferenceStore(newMemoryStore()), config)
+	call := func(ctx context.Context, args map[string]any) deliverySchedule {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := handler(ctx, request)
+		if err != nil || result.IsError {
+			t.Fatalf("Failed to schedule with %v: %v %s", args, err, toolResultText(result))
+		}
+		var schedule deliverySchedule
+		if err := json.Unmarshal([]byte(toolResultText(result)), &schedule); err != nil {
+			t.Fatalf("Failed to decode result: %v", err)
+		}
+		return schedule
+	}
+
+	tests := []struct {
+		args     map[string]any
+		deliver  string
+		deferred bool
+	}{
+		{map[string]any{"quiet_hours": "22:00-07:00", "timezone": "Europe/Warsaw"}, "2025-12-24T07:00:00+01:00", true},
+		{map[string]any{"quiet_hours": "22:00-07:00", "timezone": "Europe/Warsaw", "time": "2025-12-24 12:00"}, "2025-12-24T12:00:00+01:00", false},
+		// Adjacent and overlapping ranges are skipped together
+		{map[string]any{"quiet_hours": "12:00-13:00, 12:30-14:00, 14:00-15:00", "time": "2025-12-24T12:15:00Z"}, "2025-12-24T15:00:00Z", true},
+		{map[string]any{"quiet_hours": "22:00-00:00, 00:00-06:00", "time": "2025-12-24T23:00:00Z"}, "2025-12-25T06:00:00Z", true},
+	}
+	for _, tt := range tests {
+		schedule := call(context.Background(), tt.args)
+		if schedule.DeliverAt != tt.deliver || schedule.Deferred != tt.deferred {
+			t.Errorf("Expected %v to deliver at %s (deferred %v) but got %s (deferred %v)", tt.args, tt.deliver, tt.deferred, schedule.DeliverAt, schedule.Deferred)
+		}
+	}
+
+	// Saved quiet hours apply to the same user only
+	alice := context.WithValue(context.Background(), userIDKey, "alice")
+	if schedule := call(alice, map[string]any{"quiet_hours": "22:00-07:00", "timezone": "Europe/Warsaw", "save": true}); !schedule.Saved {
+		t.Errorf("Expected the quiet hours to be saved")
+	}
+	schedule := call(alice, map[string]any{})
+	if schedule.Timezone != "Europe/Warsaw" || schedule.DeliverAt != "2025-12-24T07:00:00+01:00" {
+		t.Errorf("Expected the saved quiet hours to apply but got %+v", schedule)
+	}
+	request := mcp.CallToolRequest{}
+	result, err := handler(context.WithValue(context.Background(), userIDKey, "bob"), request)
+	if err != nil {
+		t.Fatalf("Failed to call handler: %v", err)
+	}
+	if code := toolErrorCodeOf(result); code != errorInvalidArgument {
+		t.Errorf("Expected %s for a user without saved quiet hours but got %q", errorInvalidArgument, code)
+	}
+}
+
+func TestNotificationScheduleErrors(t *testing.T) {
+	config := &Config{DefaultTimezone: "UTC"}
+	handler := handleNotificationSchedule(newPreferenceStore(newMemoryStore()), config)
+
+	tests := []struct {
+		args map[string]any
+		code toolErrorCode
+	}{
+		{map[string]any{"quiet_hours": "late"}, errorParse},
+		{map[string]any{"quiet_hours": " , "}, errorParse},
+		{map[string]any{"quiet_hours": "cron 0 22 * * * for 9h"}, errorParse},
+		{map[string]any{"quiet_hours": "00:00-12:00, 12:00-00:00"}, errorInvalidArgument},
+		{map[string]any{"quiet_hours": "22:00-07:00", "timezone": "Warsw"}, errorInvalidTimezone},
+		{map[string]any{"quiet_hours": "22:00-07:00", "time": "soon"}, errorParse},
+	}
+	for _, tt := range tests {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = tt.args
+		result, err := handler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("Failed to call handler: %v", err)
+		}
+		if code := toolErrorCodeOf(result); code != tt.code {
+			t.Errorf("Expected %v to fail with %s but got %q: %s", tt.args, tt.code, code, toolResultText(result))
+		}
+	}
+}
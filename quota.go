@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// usageToolName is exempt from quotas so callers can always inspect their usage
+const usageToolName = "get_usage"
+
+// Quota counters live in the state store so they survive restarts with the
+// file backend and are shared by replicas with the Redis backend
+const (
+	quotaMinuteTTL = 2 * time.Minute
+	quotaDayTTL    = 48 * time.Hour
+)
+
+// quotaTracker enforces per-user call quotas keyed by the authenticated user ID.
+// A limit of zero disables that window.
+type quotaTracker struct {
+	mu        sync.Mutex
+	perMinute int
+	perDay    int
+	store     stateStore
+	now       func() time.Time
+}
+
+func newQuotaTracker(store stateStore, perMinute, perDay int) *quotaTracker {
+	return &quotaTracker{
+		perMinute: perMinute,
+		perDay:    perDay,
+		store:     store,
+		now:       time.Now,
+	}
+}
+
+// setLimits updates the quotas; counts in the current windows are kept
+func (q *quotaTracker) setLimits(perMinute, perDay int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.perMinute = perMinute
+	q.perDay = perDay
+}
+
+func (q *quotaTracker) limits() (int, int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.perMinute, q.perDay
+}
+
+// quotaKeys returns the user's counter keys for the windows containing now
+func quotaKeys(userID string, now time.Time) (minuteKey, dayKey, totalKey string) {
+	minute := now.Truncate(time.Minute)
+	day := now.UTC().Format("2006-01-02")
+	return fmt.Sprintf("quota:%s:minute:%d", userID, minute.Unix()),
+		fmt.Sprintf("quota:%s:day:%s", userID, day),
+		fmt.Sprintf("quota:%s:total", userID)
+}
+
+// take records a call for userID. When a quota is exhausted it returns an
+// error describing the window and when it resets; rejected calls are not
+// counted.
+func (q *quotaTracker) take(ctx context.Context, userID string) error {
+	perMinute, perDay := q.limits()
+	now := q.now()
+	minuteKey, dayKey, totalKey := quotaKeys(userID, now)
+
+	// Counters are incremented first and rolled back when over the limit so
+	// concurrent replicas never admit more calls than allowed
+	dayCount, err := q.store.incrBy(ctx, dayKey, 1, quotaDayTTL)
+	if err != nil {
+		return err
+	}
+	if perDay > 0 && dayCount > int64(perDay) {
+		q.store.incrBy(ctx, dayKey, -1, quotaDayTTL)
+		resets := now.UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+		return &quotaError{fmt.Sprintf("daily quota of %d calls exceeded; resets at %s", perDay, resets.Format(time.RFC3339))}
+	}
+	minuteCount, err := q.store.incrBy(ctx, minuteKey, 1, quotaMinuteTTL)
+	if err != nil {
+		q.store.incrBy(ctx, dayKey, -1, quotaDayTTL)
+		return err
+	}
+	if perMinute > 0 && minuteCount > int64(perMinute) {
+		q.store.incrBy(ctx, minuteKey, -1, quotaMinuteTTL)
+		q.store.incrBy(ctx, dayKey, -1, quotaDayTTL)
+		retry := int(now.Truncate(time.Minute).Add(time.Minute).Sub(now).Seconds()) + 1
+		return &quotaError{fmt.Sprintf("per-minute quota of %d calls exceeded; retry in %ds", perMinute, retry)}
+	}
+	_, err = q.store.incrBy(ctx, totalKey, 1, 0)
+	return err
+}
+
+// quotaError reports an exhausted quota, as opposed to a store failure
+type quotaError struct {
+	msg string
+}
+
+func (e *quotaError) Error() string { return e.msg }
+
+// counter reads a counter from the store; missing keys count as zero
+func (q *quotaTracker) counter(ctx context.Context, key string) (int, error) {
+	value, ok, err := q.store.get(ctx, key)
+	if err != nil || !ok {
+		return 0, err
+	}
+	return strconv.Atoi(string(value))
+}
+
+// report describes the user's current usage
+func (q *quotaTracker) report(ctx context.Context, userID string) (string, error) {
+	perMinute, perDay := q.limits()
+	minuteKey, dayKey, totalKey := quotaKeys(userID, q.now())
+	minuteCount, err := q.counter(ctx, minuteKey)
+	if err != nil {
+		return "", err
+	}
+	dayCount, err := q.counter(ctx, dayKey)
+	if err != nil {
+		return "", err
+	}
+	total, err := q.counter(ctx, totalKey)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Usage for %s: %s this minute, %s today (UTC), %d calls in total",
+		userID, formatQuota(minuteCount, perMinute), formatQuota(dayCount, perDay), total), nil
+}
+
+func formatQuota(used, limit int) string {
+	if limit <= 0 {
+		return fmt.Sprintf("%d calls (unlimited)", used)
+	}
+	return fmt.Sprintf("%d of %d calls", used, limit)
+}
+
+// quotaMiddleware enforces per-user quotas on authenticated tool calls
+func quotaMiddleware(quotas *quotaTracker) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			userID, _, _ := getUserInfo(ctx)
+			if userID == "" || req.Params.Name == usageToolName {
+				return next(ctx, req)
+			}
+			if err := quotas.take(ctx, userID); err != nil {
+				var exceeded *quotaError
+				if !errors.As(err, &exceeded) {
+					// Quotas fail open so a store outage does not take tools down
+					slog.ErrorContext(ctx, "Quota check failed", "tool", req.Params.Name, "error", err)
+					return next(ctx, req)
+				}
+				slog.WarnContext(ctx, "Quota exceeded", "tool", req.Params.Name, "error", err)
+				return toolError(errorRateLimited, fmt.Sprintf("Too Many Requests (429): %v", err)), nil
+			}
+			return next(ctx, req)
+		}
+	}
+}
+
+// addUsageTool registers the get_usage tool
+func addUsageTool(mcpServer *server.MCPServer, quotas *quotaTracker) {
+	mcpServer.AddTool(
+		mcp.NewTool(usageToolName,
+			mcp.WithDescription("Show the calling user's tool call usage and quotas."),
+			mcp.WithTitleAnnotation("Get Usage"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			userID, _, _ := getUserInfo(ctx)
+			if userID == "" {
+				return toolError(errorUnauthorized, "Usage is tracked for authenticated users only"), nil
+			}
+			report, err := quotas.report(ctx, userID)
+			if err != nil {
+				return toolError(errorInternal, fmt.Sprintf("Failed to read usage: %v", err)), nil
+			}
+			return mcp.NewToolResultText(report), nil
+		},
+	)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQuotaTracker(t *testing.T) {
+	now := time.Date(2025, 6, 1, 23, 58, 0, 0, time.UTC)
+	quotas := newQuotaTracker(newMemoryStore(), 2, 3)
+	quotas.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if err := quotas.take(ctx, "alice"); err != nil {
+			t.Fatalf("Expected call %d to be allowed, but got %v", i+1, err)
+		}
+	}
+	if err := quotas.take(ctx, "alice"); err == nil || !strings.Contains(err.Error(), "per-minute") {
+		t.Errorf("Expected per-minute quota error, but got %v", err)
+	}
+	if err := quotas.take(ctx, "bob"); err != nil {
+		t.Errorf("Expected other users to be unaffected, but got %v", err)
+	}
+
+	// The next minute allows one more call before the daily quota is reached
+	now = now.Add(time.Minute)
+	if err := quotas.take(ctx, "alice"); err != nil {
+		t.Fatalf("Expected call in new minute to be allowed, but got %v", err)
+	}
+	if err := quotas.take(ctx, "alice"); err == nil || !strings.Contains(err.Error(), "daily") {
+		t.Errorf("Expected daily quota error, but got %v", err)
+	}
+
+	// A new UTC day resets the daily count
+	now = now.Add(24 * time.Hour)
+	if err := quotas.take(ctx, "alice"); err != nil {
+		t.Errorf("Expected call on a new day to be allowed, but got %v", err)
+	}
+
+	report, err := quotas.report(ctx, "alice")
+	if err != nil {
+		t.Fatalf("Failed to report usage: %v", err)
+	}
+	if !strings.Contains(report, "1 of 3 calls today") || !strings.Contains(report, "4 calls in total") {
+		t.Errorf("Unexpected usage report: %s", report)
+	}
+}
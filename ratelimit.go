@@ -0,0 +1,208 @@
+package main
+
+import (
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// rateLimitSweepInterval is how often idle buckets are evicted
+const rateLimitSweepInterval = time.Minute
+
+// RateLimit describes a token bucket: Rate tokens are added per second up to Burst
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// Enabled reports whether the limit is active
+func (l RateLimit) Enabled() bool {
+	return l.Rate > 0
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// rateLimiter keeps one token bucket per key
+type rateLimiter struct {
+	mu        sync.Mutex
+	rate      float64
+	burst     float64
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func newRateLimiter(limit RateLimit) *rateLimiter {
+	l := &rateLimiter{buckets: make(map[string]*tokenBucket), now: time.Now}
+	l.setLimit(limit)
+	return l
+}
+
+// setLimit changes the rate and burst; buckets keep their tokens, capped at
+// the new burst when next used
+func (l *rateLimiter) setLimit(limit RateLimit) {
+	burst := limit.Burst
+	if burst <= 0 {
+		burst = max(1, int(math.Ceil(limit.Rate)))
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rate = limit.Rate
+	l.burst = float64(burst)
+}
+
+// rateLimiters keeps limiters by scope across config reloads, so a reload
+// changes rates without refilling every client's bucket
+type rateLimiters struct {
+	mu      sync.Mutex
+	byScope map[string]*rateLimiter
+}
+
+func newRateLimiters() *rateLimiters {
+	return &rateLimiters{byScope: make(map[string]*rateLimiter)}
+}
+
+// get returns the limiter of scope set to limit. A nil set returns a new
+// limiter each time.
+func (s *rateLimiters) get(scope string, limit RateLimit) *rateLimiter {
+	if s == nil {
+		return newRateLimiter(limit)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if l, ok := s.byScope[scope]; ok {
+		l.setLimit(limit)
+		return l
+	}
+	l := newRateLimiter(limit)
+	s.byScope[scope] = l
+	return l
+}
+
+// allow takes a token for key. When no token is available it returns false
+// and how long until one will be.
+func (l *rateLimiter) allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	wait := time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+	return false, wait
+}
+
+// sweep evicts buckets that have been idle long enough to be full again
+func (l *rateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < rateLimitSweepInterval {
+		return
+	}
+	l.lastSweep = now
+	refill := time.Duration(l.burst / l.rate * float64(time.Second))
+	for key, b := range l.buckets {
+		if now.Sub(b.last) > refill {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// rateLimitHandler enforces global, per-IP, per-user and per-tenant limits
+// before the wrapped handler. Limiters come from limiters, so buckets survive
+// the handler being rebuilt on reload. claims resolves the authenticated
+// caller of a request and may be nil when authentication is disabled.
+func rateLimitHandler(next http.Handler, config *Config, limiters *rateLimiters, claims func(r *http.Request) (*Claims, *http.Request)) http.Handler {
+	var global, perIP, perUser *rateLimiter
+	if config.HTTPRateLimitGlobal.Enabled() {
+		global = limiters.get("global", config.HTTPRateLimitGlobal)
+	}
+	if config.HTTPRateLimitIP.Enabled() {
+		perIP = limiters.get("ip", config.HTTPRateLimitIP)
+	}
+	if config.HTTPRateLimitUser.Enabled() && claims != nil {
+		perUser = limiters.get("user", config.HTTPRateLimitUser)
+	}
+	// Each tenant shares one bucket at its own rate
+	perTenant := make(map[string]*rateLimiter)
+	for name, t := range config.Tenants {
+		if t.RateLimit.Enabled() && claims != nil {
+			perTenant[name] = limiters.get("tenant:"+name, t.RateLimit)
+		}
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if global != nil {
+			if ok, wait := global.allow(""); !ok {
+				rejectRateLimited(w, r, "global", wait)
+				return
+			}
+		}
+		if perIP != nil {
+			if ok, wait := perIP.allow(remoteIP(r).String()); !ok {
+				rejectRateLimited(w, r, "ip", wait)
+				return
+			}
+		}
+		if perUser == nil && len(perTenant) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		caller, r := claims(r)
+		if perUser != nil && caller != nil && caller.UserID != "" {
+			if ok, wait := perUser.allow(caller.UserID); !ok {
+				rejectRateLimited(w, r, "user", wait)
+				return
+			}
+		}
+		if t := config.Tenants.resolve(caller); t != nil && perTenant[t.Name] != nil {
+			if ok, wait := perTenant[t.Name].allow(""); !ok {
+				rejectRateLimited(w, r, "tenant", wait)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func rejectRateLimited(w http.ResponseWriter, r *http.Request, scope string, wait time.Duration) {
+	slog.WarnContext(r.Context(), "Rate limit exceeded", "scope", scope, "remote_addr", r.RemoteAddr)
+	seconds := int(math.Ceil(wait.Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(max(1, seconds)))
+	http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+}
+
+// authenticatedClaims returns a function resolving the caller from valid
+// request credentials with the current auth middleware. The request it
+// returns carries the outcome, so the MCP handler does not verify the
+// credentials again.
+func authenticatedClaims(auth func() *AuthMiddleware) func(r *http.Request) (*Claims, *http.Request) {
+	return func(r *http.Request) (*Claims, *http.Request) {
+		a := auth()
+		if a == nil {
+			return nil, r
+		}
+		claims, r, err := a.authenticateRequest(r)
+		if err != nil {
+			return nil, r
+		}
+		return claims, r
+	}
+}
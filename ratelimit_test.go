@@ -0,0 +1,133 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_TokenBucket(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	limiter := newRateLimiter(RateLimit{Rate: 1, Burst: 2})
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := limiter.allow("a"); !ok {
+			t.Fatalf("Expected request %d within burst to be allowed", i+1)
+		}
+	}
+
+	ok, wait := limiter.allow("a")
+	if ok {
+		t.Fatal("Expected request beyond burst to be rejected")
+	}
+	if wait != time.Second {
+		t.Errorf("Expected retry after 1s, but got %v", wait)
+	}
+
+	if ok, _ := limiter.allow("b"); !ok {
+		t.Error("Expected a different key to have its own bucket")
+	}
+
+	now = now.Add(time.Second)
+	if ok, _ := limiter.allow("a"); !ok {
+		t.Error("Expected a token to be refilled after one second")
+	}
+}
+
+func TestRateLimitHandler(t *testing.T) {
+	config := &Config{HTTPRateLimitIP: RateLimit{Rate: 0.001, Burst: 1}}
+	handler := rateLimitHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}), config, nil, nil)
+
+	send := func(addr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/mcp", nil)
+		req.RemoteAddr = addr
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := send("198.51.100.1:1000"); rec.Code != http.StatusOK {
+		t.Fatalf("Expected first request to succeed, but got %d", rec.Code)
+	}
+
+	rec := send("198.51.100.1:1001")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected 429, but got %d", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("Expected Retry-After header to be set")
+	}
+
+	if rec := send("198.51.100.2:1000"); rec.Code != http.StatusOK {
+		t.Errorf("Expected request from another IP to succeed, but got %d", rec.Code)
+	}
+}
+
+func TestRateLimitHandler_PerUser(t *testing.T) {
+	config := &Config{
+		AuthEnabled:       true,
+		AuthSecretKey:     "test-secret-key-that-is-long-enough-for-hs256",
+		AuthIssuer:        "test-issuer",
+		AuthAudience:      "test-audience",
+		HTTPRateLimitUser: RateLimit{Rate: 0.001, Burst: 1},
+	}
+	fn, auth, err := createHTTPMiddleware(config)
+	if err != nil {
+		t.Fatalf("Failed to create HTTP middleware: %v", err)
+	}
+	contextFunc := &reloadableContextFunc{}
+	contextFunc.store(fn, auth)
+	handler := rateLimitHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The MCP handler reuses the limiter's verification
+		if _, ok := r.Context().Value(authOutcomeKey).(*authOutcome); !ok {
+			t.Error("Expected the auth outcome to be passed on")
+		}
+		w.WriteHeader(http.StatusOK)
+	}), config, nil, authenticatedClaims(contextFunc.auth))
+
+	send := func(userID string) int {
+		token, _ := auth.GenerateToken(userID, "user"+userID, "user", 1)
+		req := httptest.NewRequest("POST", "/mcp", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	if code := send("1"); code != http.StatusOK {
+		t.Fatalf("Expected the first request to succeed, but got %d", code)
+	}
+	if code := send("1"); code != http.StatusTooManyRequests {
+		t.Errorf("Expected the user's second request to be limited, but got %d", code)
+	}
+	if code := send("2"); code != http.StatusOK {
+		t.Errorf("Expected another user to have its own bucket, but got %d", code)
+	}
+}
+
+func TestRateLimitHandler_KeepsBucketsOnReload(t *testing.T) {
+	limiters := newRateLimiters()
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	send := func(handler http.Handler) int {
+		req := httptest.NewRequest("POST", "/mcp", nil)
+		req.RemoteAddr = "198.51.100.1:1000"
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	handler := rateLimitHandler(ok, &Config{HTTPRateLimitIP: RateLimit{Rate: 0.001, Burst: 1}}, limiters, nil)
+	if code := send(handler); code != http.StatusOK {
+		t.Fatalf("Expected the first request to succeed, but got %d", code)
+	}
+	// A reload rebuilds the handler with the new rate but the same buckets
+	handler = rateLimitHandler(ok, &Config{HTTPRateLimitIP: RateLimit{Rate: 0.002, Burst: 1}}, limiters, nil)
+	if code := send(handler); code != http.StatusTooManyRequests {
+		t.Errorf("Expected the spent bucket to survive the reload, but got %d", code)
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// rolePolicy maps roles to the tools they may call. The tool name "*" grants
+// access to every tool. A nil policy allows everything.
+type rolePolicy map[string]map[string]struct{}
+
+// parseRolePolicy parses "admin:*;reader:get_current_time,convert_time".
+// Entries are separated by semicolons or newlines; lines starting with # are ignored.
+func parseRolePolicy(str string) (rolePolicy, error) {
+	policy := make(rolePolicy)
+	for _, entry := range strings.FieldsFunc(str, func(r rune) bool { return r == ';' || r == '\n' }) {
+		entry = strings.TrimSpace(entry)
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+
+		role, tools, ok := strings.Cut(entry, ":")
+		role = strings.TrimSpace(role)
+		if !ok || role == "" {
+			return nil, fmt.Errorf("invalid role entry %q (expected role:tool,tool)", entry)
+		}
+		allowed := policy[role]
+		if allowed == nil {
+			allowed = make(map[string]struct{})
+			policy[role] = allowed
+		}
+		for _, tool := range strings.Split(tools, ",") {
+			if tool = strings.TrimSpace(tool); tool != "" {
+				allowed[tool] = struct{}{}
+			}
+		}
+	}
+	if len(policy) == 0 {
+		return nil, nil
+	}
+	return policy, nil
+}
+
+// loadRolePolicy combines the inline policy and the optional policy file
+func loadRolePolicy(inline, file string) (rolePolicy, error) {
+	data := inline
+	if file != "" {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read roles file: %w", err)
+		}
+		data += "\n" + string(content)
+	}
+	return parseRolePolicy(data)
+}
+
+// allows reports whether role may call tool
+func (p rolePolicy) allows(role, tool string) bool {
+	if p == nil {
+		return true
+	}
+	allowed, ok := p[role]
+	if !ok {
+		return false
+	}
+	if _, ok := allowed["*"]; ok {
+		return true
+	}
+	_, ok = allowed[tool]
+	return ok
+}
+
+// toolAccess is the outcome of checking whether a caller may call a tool
+type toolAccess int
+
+const (
+	// toolUnrestricted: authentication is off or not enforced for the caller
+	toolUnrestricted toolAccess = iota
+	toolAllowed
+	toolAllowedAnonymously
+	toolUnauthenticated
+	toolDeniedByRole
+	toolDeniedByTenant
+)
+
+// permitted reports whether the call may proceed
+func (a toolAccess) permitted() bool {
+	return a == toolUnrestricted || a == toolAllowed || a == toolAllowedAnonymously
+}
+
+// checkToolAccess decides whether a caller may call tool. authError is empty
+// for authenticated callers and authErrorMissingToken for callers without
+// credentials, who get the anonymous tier; any other error is rejected.
+// Authenticated callers need both their role and tenant to allow the tool.
+// Tool calls, tools/list and the capabilities endpoints all decide with it.
+func checkToolAccess(config *Config, tool, authError, role string, t *tenant) toolAccess {
+	if !config.AuthEnabled {
+		return toolUnrestricted
+	}
+	if authError != "" {
+		if authError == authErrorMissingToken && anonymousAllowed(config.AuthAnonymousTools, tool) {
+			return toolAllowedAnonymously
+		}
+		return toolUnauthenticated
+	}
+	if !config.AuthRoles.allows(role, tool) {
+		return toolDeniedByRole
+	}
+	if t != nil && !t.allows(tool) {
+		return toolDeniedByTenant
+	}
+	return toolAllowed
+}
+
+// contextToolAccess applies checkToolAccess to the caller of an MCP request
+func contextToolAccess(ctx context.Context, tool string, config *Config) toolAccess {
+	if !authEnforced(ctx) {
+		return toolUnrestricted
+	}
+	authError := getAuthError(ctx)
+	if authError == "" && !isAuthenticated(ctx) && config.AuthEnabled {
+		return toolUnauthenticated
+	}
+	_, _, role := getUserInfo(ctx)
+	return checkToolAccess(config, tool, authError, role, tenantFromContext(ctx))
+}
+
+// toolPermitted reports whether the caller may call tool, applying the same
+// rules as checkAuth without logging, so listings can be filtered quietly
+func toolPermitted(ctx context.Context, tool string, config *Config) bool {
+	return contextToolAccess(ctx, tool, config).permitted()
+}
+
+// permittedToolsFilter limits tools/list to the tools the caller may call,
+// so clients are not offered tools that would fail with Forbidden
+func permittedToolsFilter(config func() *Config) server.ToolFilterFunc {
+	return func(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+		cfg := config()
+		permitted := make([]mcp.Tool, 0, len(tools))
+		for _, tool := range tools {
+			if toolPermitted(ctx, tool.Name, cfg) {
+				permitted = append(permitted, tool)
+			}
+		}
+		return permitted
+	}
+}
+
+// anonymousAllowed reports whether tool is in the anonymous allowlist
+func anonymousAllowed(tools []string, tool string) bool {
+	for _, allowed := range tools {
+		if allowed == "*" || allowed == tool {
+			return true
+		}
+	}
+	return false
+}
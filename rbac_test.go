@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestRolePolicy(t *testing.T) {
+	policy, err := parseRolePolicy("admin:*; reader:get_current_time, convert_time\n# ignored\nauditor:")
+	if err != nil {
+		t.Fatalf("Failed to parse role policy: %v", err)
+	}
+
+	testCases := []struct {
+		role     string
+		tool     string
+		expected bool
+	}{
+		{"admin", "anything", true},
+		{"reader", "get_current_time", true},
+		{"reader", "convert_time", true},
+		{"reader", "revoke_token", false},
+		{"auditor", "get_current_time", false},
+		{"unknown", "get_current_time", false},
+	}
+
+	for _, tc := range testCases {
+		if got := policy.allows(tc.role, tc.tool); got != tc.expected {
+			t.Errorf("allows(%q, %q): expected %v, but got %v", tc.role, tc.tool, tc.expected, got)
+		}
+	}
+}
+
+func TestRolePolicy_EmptyAllowsAll(t *testing.T) {
+	policy, err := parseRolePolicy("")
+	if err != nil {
+		t.Fatalf("Failed to parse empty policy: %v", err)
+	}
+	if !policy.allows("anyone", "any_tool") {
+		t.Error("Expected an empty policy to allow every call")
+	}
+}
+
+func TestParseRolePolicy_Invalid(t *testing.T) {
+	if _, err := parseRolePolicy("no-colon-here"); err == nil {
+		t.Fatal("Expected an error for entry without colon, but got nil")
+	}
+}
+
+func TestCheckAuth_AnonymousTier(t *testing.T) {
+	config := &Config{AuthEnabled: true, AuthAnonymousTools: []string{"get_current_time"}}
+
+	testCases := []struct {
+		name      string
+		authError string
+		tool      string
+		allowed   bool
+	}{
+		{"Anonymous Safe Tool", authErrorMissingToken, "get_current_time", true},
+		{"Anonymous Other Tool", authErrorMissingToken, "convert_time", false},
+		{"Invalid Token Safe Tool", authErrorInvalidToken, "get_current_time", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), httpMethodKey, "POST")
+			ctx = context.WithValue(ctx, authErrorKey, tc.authError)
+			ctx = context.WithValue(ctx, authenticatedKey, false)
+
+			result := checkAuth(ctx, tc.tool, config)
+			if tc.allowed && result != nil {
+				t.Errorf("Expected call to be allowed, but got an error result")
+			}
+			if !tc.allowed && result == nil {
+				t.Errorf("Expected call to be rejected, but it was allowed")
+			}
+		})
+	}
+}
+
+func TestPermittedToolsFilter(t *testing.T) {
+	config := &Config{
+		AuthEnabled:        true,
+		AuthAnonymousTools: []string{"get_current_time"},
+		AuthRoles:          rolePolicy{"viewer": {"get_current_time": {}, "convert_time": {}}},
+	}
+	tools := []mcp.Tool{{Name: "get_current_time"}, {Name: "convert_time"}, {Name: "schedule_webhook"}}
+	http := context.WithValue(context.Background(), httpMethodKey, "POST")
+	viewer := context.WithValue(context.WithValue(http, authenticatedKey, true), userRoleKey, "viewer")
+	restricted := withTenant(viewer, &tenant{Name: "acme", Tools: []string{"convert_time"}})
+
+	testCases := []struct {
+		name string
+		ctx  context.Context
+		want []string
+	}{
+		{"Stdio", context.Background(), []string{"get_current_time", "convert_time", "schedule_webhook"}},
+		{"Anonymous", context.WithValue(http, authErrorKey, authErrorMissingToken), []string{"get_current_time"}},
+		{"Invalid Token", context.WithValue(http, authErrorKey, authErrorInvalidToken), nil},
+		{"Viewer", viewer, []string{"get_current_time", "convert_time"}},
+		{"Viewer In Tenant", restricted, []string{"convert_time"}},
+	}
+	filter := permittedToolsFilter(func() *Config { return config })
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []string
+			for _, tool := range filter(tc.ctx, tools) {
+				got = append(got, tool.Name)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("Expected %v but got %v", tc.want, got)
+			}
+			// The listing must agree with what a call would be allowed to do
+			for _, tool := range tools {
+				if allowed := checkAuth(tc.ctx, tool.Name, config) == nil; allowed != slices.Contains(tc.want, tool.Name) {
+					t.Errorf("Expected listing of %s to match checkAuth (%v)", tool.Name, allowed)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"log/slog"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// redactedValue replaces sensitive values in logs
+const redactedValue = "[REDACTED]"
+
+// minRedactedSecretLength avoids replacing short, common strings that happen
+// to be configured as secrets
+const minRedactedSecretLength = 8
+
+var (
+	// jwtPattern matches compact JWS/JWE tokens; their header always starts with eyJ ('{"')
+	jwtPattern = regexp.MustCompile(`eyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+	// authSchemePattern matches credentials following an HTTP auth scheme
+	authSchemePattern = regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9._~+/=-]+`)
+	// secretAssignmentPattern matches key=value or key: value pairs naming a
+	// secret; short values are left alone so prose like "token: expired" survives
+	secretAssignmentPattern = regexp.MustCompile(`(?i)\b(authorization|x-api-key|api[_-]?key|secret(?:[_-]?key)?|password|token)(["']?\s*[:=]\s*["']?)[^\s"',;&]{8,}`)
+)
+
+// sensitiveLogKeys are attribute names whose values are never logged
+var sensitiveLogKeys = map[string]bool{
+	"authorization": true,
+	"token":         true,
+	"secret":        true,
+	"secret_key":    true,
+	"password":      true,
+	"api_key":       true,
+	"credential":    true,
+	"cookie":        true,
+}
+
+// logSecrets holds configured secret values that must never be logged verbatim
+var logSecrets struct {
+	mu     sync.RWMutex
+	values []string
+}
+
+// setLogSecrets replaces the configured secrets redacted from log output
+func setLogSecrets(config *Config) {
+	var values []string
+	add := func(secret string) {
+		if len(secret) >= minRedactedSecretLength {
+			values = append(values, secret)
+		}
+	}
+	if keys, err := parseSecretKeys(config.AuthSecretKey, config.AuthSecretKeys); err == nil {
+		for _, key := range keys {
+			add(string(key.Secret))
+		}
+	}
+	add(config.AuthSecretKey)
+	add(config.StdioAuthToken)
+	add(config.WebhookSecret)
+	if u, err := url.Parse(config.StoreRedisURL); err == nil && u.User != nil {
+		password, _ := u.User.Password()
+		add(password)
+	}
+
+	logSecrets.mu.Lock()
+	logSecrets.values = values
+	logSecrets.mu.Unlock()
+}
+
+// redactString masks JWTs, auth header credentials, secret assignments and
+// configured secrets in free-form text
+func redactString(s string) string {
+	logSecrets.mu.RLock()
+	for _, secret := range logSecrets.values {
+		s = strings.ReplaceAll(s, secret, redactedValue)
+	}
+	logSecrets.mu.RUnlock()
+
+	s = jwtPattern.ReplaceAllString(s, redactedValue)
+	s = authSchemePattern.ReplaceAllString(s, "$1 "+redactedValue)
+	return secretAssignmentPattern.ReplaceAllString(s, "${1}${2}"+redactedValue)
+}
+
+// redactAttr is a slog ReplaceAttr function applying redaction to every
+// attribute, including errors whose messages may embed tokens
+func redactAttr(_ []string, a slog.Attr) slog.Attr {
+	if sensitiveLogKeys[strings.ToLower(a.Key)] {
+		return slog.String(a.Key, redactedValue)
+	}
+	switch a.Value.Kind() {
+	case slog.KindString:
+		if s := a.Value.String(); s != "" {
+			return slog.String(a.Key, redactString(s))
+		}
+	case slog.KindAny:
+		if err, ok := a.Value.Any().(error); ok {
+			return slog.String(a.Key, redactString(err.Error()))
+		}
+	}
+	return a
+}
+
+// redactRecord returns the record with its message redacted; attributes are
+// handled by redactAttr
+func redactRecord(r slog.Record) slog.Record {
+	msg := redactString(r.Message)
+	if msg == r.Message {
+		return r
+	}
+	redacted := slog.NewRecord(r.Time, r.Level, msg, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		redacted.AddAttrs(a)
+		return true
+	})
+	return redacted
+}
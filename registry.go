@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ToolDefinition describes a tool added through RegisterTool
+type ToolDefinition struct {
+	// Tool is the MCP definition advertised to clients
+	Tool mcp.Tool
+	// Handler builds the tool's handler from the server configuration
+	Handler func(config *Config) server.ToolHandlerFunc
+	// Enabled reports whether the tool is available with this configuration;
+	// nil means always
+	Enabled func(config *Config) bool
+}
+
+// toolRegistry holds tools registered in addition to the built-in ones
+var toolRegistry struct {
+	mu   sync.Mutex
+	defs []ToolDefinition
+}
+
+// RegisterTool adds an organization-specific tool without changing addTools.
+// Call it from an init function in a separate file, for example
+// tools_acme.go kept next to main.go:
+//
+//	func init() {
+//		RegisterTool(ToolDefinition{
+//			Tool:    mcp.NewTool("get_fiscal_quarter", mcp.WithDescription("...")),
+//			Handler: handleGetFiscalQuarter,
+//		})
+//	}
+//
+// Registered tools pass through the same middleware as built-in tools. It
+// panics if the definition is incomplete or the name is already registered.
+func RegisterTool(def ToolDefinition) {
+	if err := registerTool(def); err != nil {
+		panic(err)
+	}
+}
+
+func registerTool(def ToolDefinition) error {
+	if def.Tool.Name == "" {
+		return fmt.Errorf("tool definition has no name")
+	}
+	if def.Handler == nil {
+		return fmt.Errorf("tool %s has no handler", def.Tool.Name)
+	}
+	toolRegistry.mu.Lock()
+	defer toolRegistry.mu.Unlock()
+	for _, existing := range toolRegistry.defs {
+		if existing.Tool.Name == def.Tool.Name {
+			return fmt.Errorf("tool %s is already registered", def.Tool.Name)
+		}
+	}
+	toolRegistry.defs = append(toolRegistry.defs, def)
+	return nil
+}
+
+// registeredTools returns a copy of the registered definitions
+func registeredTools() []ToolDefinition {
+	toolRegistry.mu.Lock()
+	defer toolRegistry.mu.Unlock()
+	return append([]ToolDefinition(nil), toolRegistry.defs...)
+}
+
+// addRegisteredTools adds the enabled registered tools to the server. A name
+// that clashes with a built-in tool is an error rather than a silent override.
+func addRegisteredTools(mcpServer *server.MCPServer, config *Config) error {
+	for _, def := range registeredTools() {
+		if def.Enabled != nil && !def.Enabled(config) {
+			continue
+		}
+		if mcpServer.GetTool(def.Tool.Name) != nil {
+			return fmt.Errorf("registered tool %s conflicts with a built-in tool", def.Tool.Name)
+		}
+		mcpServer.AddTool(def.Tool, def.Handler(config))
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// resetToolRegistry restores the registry after a test registers tools
+func resetToolRegistry(t *testing.T) {
+	toolRegistry.mu.Lock()
+	saved := toolRegistry.defs
+	toolRegistry.defs = nil
+	toolRegistry.mu.Unlock()
+	t.Cleanup(func() {
+		toolRegistry.mu.Lock()
+		toolRegistry.defs = saved
+		toolRegistry.mu.Unlock()
+	})
+}
+
+func fiscalQuarterTool(name string) ToolDefinition {
+	return ToolDefinition{
+		Tool: mcp.NewTool(name, mcp.WithDescription("Fiscal quarter for today.")),
+		Handler: func(config *Config) server.ToolHandlerFunc {
+			return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return mcp.NewToolResultText("FY26 Q3"), nil
+			}
+		},
+	}
+}
+
+func TestRegisterTool(t *testing.T) {
+	resetToolRegistry(t)
+
+	RegisterTool(fiscalQuarterTool("get_fiscal_quarter"))
+	disabled := fiscalQuarterTool("get_fiscal_year")
+	disabled.Enabled = func(config *Config) bool { return config.DefaultTimezone != "" }
+	RegisterTool(disabled)
+
+	if err := registerTool(fiscalQuarterTool("get_fiscal_quarter")); err == nil {
+		t.Errorf("Expected an error registering a duplicate name")
+	}
+	if err := registerTool(ToolDefinition{Tool: mcp.NewTool("no_handler")}); err == nil {
+		t.Errorf("Expected an error registering a tool without a handler")
+	}
+
+	mcpServer := server.NewMCPServer("test", "1.0.0", server.WithToolCapabilities(true))
+	addTools(mcpServer, &Config{})
+	if err := addRegisteredTools(mcpServer, &Config{}); err != nil {
+		t.Fatalf("Failed to add registered tools: %v", err)
+	}
+	if mcpServer.GetTool("get_fiscal_quarter") == nil {
+		t.Errorf("Expected the registered tool to be added")
+	}
+	if mcpServer.GetTool("get_fiscal_year") != nil {
+		t.Errorf("Expected a disabled tool to be skipped")
+	}
+}
+
+func TestAddRegisteredTools_BuiltinConflict(t *testing.T) {
+	resetToolRegistry(t)
+	RegisterTool(fiscalQuarterTool("convert_time"))
+
+	mcpServer := server.NewMCPServer("test", "1.0.0", server.WithToolCapabilities(true))
+	addTools(mcpServer, &Config{})
+	if err := addRegisteredTools(mcpServer, &Config{}); err == nil {
+		t.Errorf("Expected an error when a registered tool shadows a built-in tool")
+	}
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// configReloader holds the live configuration. On reload it re-reads the
+// config file and environment, then lets each component rebuild its derived
+// state. Components read the current config through Load, so live MCP
+// sessions keep running across reloads.
+type configReloader struct {
+	mu        sync.Mutex
+	current   atomic.Pointer[Config]
+	file      string
+	adjust    func(*Config)
+	listeners []reloadListener
+}
+
+type reloadListener struct {
+	name  string
+	apply func(*Config) error
+}
+
+// newConfigReloader wraps the initial config. adjust re-applies command line
+// overrides to every reloaded config and may be nil.
+func newConfigReloader(config *Config, file string, adjust func(*Config)) *configReloader {
+	r := &configReloader{file: file, adjust: adjust}
+	r.current.Store(config)
+	return r
+}
+
+// Load returns the current configuration
+func (r *configReloader) Load() *Config {
+	return r.current.Load()
+}
+
+// onReload registers a component to be updated after each successful reload
+func (r *configReloader) onReload(name string, apply func(*Config) error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.listeners = append(r.listeners, reloadListener{name: name, apply: apply})
+}
+
+// reload rebuilds the configuration. An invalid config is rejected and the
+// previous one stays active; a component that fails to apply the new config
+// keeps its previous state.
+func (r *configReloader) reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file != "" {
+		if err := applyConfigFile(r.file); err != nil {
+			return err
+		}
+	}
+	config, err := NewConfig()
+	if err != nil {
+		return err
+	}
+	if r.adjust != nil {
+		r.adjust(config)
+	}
+
+	previous := r.current.Load()
+	// Keep runtime revocations when the denylist sources are unchanged
+	if old, updated := previous.AuthDenylist, config.AuthDenylist; old != nil && updated != nil &&
+		old.file == updated.file && old.url == updated.url {
+		config.AuthDenylist = old
+	}
+	// Tools hold the dataset registry they were registered with, so it takes
+	// on the datasets parsed from the reloaded files
+	if old, updated := previous.Datasets, config.Datasets; old != nil && updated != nil {
+		old.replace(updated)
+		config.Datasets = old
+	}
+
+	warnRestartRequired(previous, config)
+	for _, l := range r.listeners {
+		if err := l.apply(config); err != nil {
+			slog.Error("Reload: failed to apply settings, keeping previous ones", "component", l.name, "error", err)
+		}
+	}
+	r.current.Store(config)
+	return nil
+}
+
+// watchReloadSignal reloads the configuration whenever SIGHUP is received
+func (r *configReloader) watchReloadSignal() {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGHUP)
+	go func() {
+		for range sigChan {
+			slog.Info("Received SIGHUP, reloading configuration")
+			if err := r.reload(); err != nil {
+				slog.Error("Configuration reload failed, keeping previous configuration", "error", err)
+				continue
+			}
+			slog.Info("Configuration reloaded")
+		}
+	}()
+}
+
+// warnRestartRequired logs settings that only take effect after a restart
+func warnRestartRequired(old, new *Config) {
+	changed := func(name string, differs bool) {
+		if differs {
+			slog.Warn("Reload: setting changed but requires a restart to take effect", "setting", name)
+		}
+	}
+	changed("server identity", old.ServerName != new.ServerName || old.ServerVersion != new.ServerVersion ||
+		old.ServerInstructions != new.ServerInstructions)
+	changed("TIME_HTTP_ADDRESS", old.HTTPAddress != new.HTTPAddress)
+	changed("TIME_HTTP_PATH", old.HTTPPath != new.HTTPPath)
+	changed("TIME_HTTP_STATELESS", old.HTTPStateless != new.HTTPStateless)
+	changed("TIME_HTTP_TIMEOUT", old.HTTPTimeout != new.HTTPTimeout)
+	// Tool handlers keep the configuration they were registered with
+	changed("TIME_DEFAULT_TIMEZONE", old.DefaultTimezone != new.DefaultTimezone)
+	changed("TIME_TZ_VALIDATE", old.TZValidate != new.TZValidate)
+	changed("TIME_AUTH_ADMIN_ROLE for admin tools", old.AuthAdminRole != new.AuthAdminRole)
+	changed("authentication enablement for admin tools", old.AuthEnabled != new.AuthEnabled)
+	changed("session keepalive settings", old.HTTPHeartbeat != new.HTTPHeartbeat || old.HTTPSessionIdleTTL != new.HTTPSessionIdleTTL ||
+		old.HTTPSessionLifetime != new.HTTPSessionLifetime)
+	changed("TLS settings", old.HTTPTLSCert != new.HTTPTLSCert || old.HTTPTLSKey != new.HTTPTLSKey ||
+		old.HTTPTLSSelfSigned != new.HTTPTLSSelfSigned || old.HTTPTLSClientCA != new.HTTPTLSClientCA ||
+		!slices.Equal(old.HTTPACMEDomains, new.HTTPACMEDomains))
+	changed("statistics settings", old.StatsFile != new.StatsFile || old.StatsFlushInterval != new.StatsFlushInterval)
+	changed("debug endpoints", old.DebugEndpoints != new.DebugEndpoints || old.DebugAddress != new.DebugAddress)
+	changed("health check settings", !slices.Equal(old.HealthNTPServers, new.HealthNTPServers) ||
+		old.HealthNTPMaxOffset != new.HealthNTPMaxOffset)
+	changed("TIME_HTTP_ACCESS_LOG_FILE", old.HTTPAccessLogFile != new.HTTPAccessLogFile)
+	changed("TIME_LOG_FORMAT", old.LogFormat != new.LogFormat)
+	changed("OpenTelemetry settings", old.OTelEnabled != new.OTelEnabled || old.OTelEndpoint != new.OTelEndpoint ||
+		old.OTelSampleRatio != new.OTelSampleRatio)
+	changed("TIME_TOOL_PLUGINS_DIR", old.ToolPluginsDir != new.ToolPluginsDir)
+	changed("TIME_TZ_PRELOAD", !slices.Equal(old.TZPreload, new.TZPreload))
+	changed("calendar tools enablement", (len(old.Calendars) > 0) != (len(new.Calendars) > 0))
+	changed("academic term tool enablement", (len(old.Terms) > 0) != (len(new.Terms) > 0))
+	changed("TIME_DATASET_REFRESH", old.DatasetRefresh != new.DatasetRefresh)
+	changed("client timezone detection enablement", (len(old.ClientTZDetect) > 0) != (len(new.ClientTZDetect) > 0))
+	changed("state store settings", old.Store != new.Store || old.StoreFile != new.StoreFile ||
+		old.StoreRedisURL != new.StoreRedisURL || old.StorePrefix != new.StorePrefix)
+	changed("scheduler settings", old.SchedulerEnabled != new.SchedulerEnabled || old.SchedulerMaxJobs != new.SchedulerMaxJobs ||
+		old.WebhookSecret != new.WebhookSecret || old.WebhookAllowPrivate != new.WebhookAllowPrivate)
+	changed("quota enablement", (old.QuotaPerMinute > 0 || old.QuotaPerDay > 0) != (new.QuotaPerMinute > 0 || new.QuotaPerDay > 0))
+}
+
+// reloadableHandler delegates to a handler that can be swapped atomically
+type reloadableHandler struct {
+	handler atomic.Pointer[http.Handler]
+}
+
+func newReloadableHandler(h http.Handler) *reloadableHandler {
+	r := &reloadableHandler{}
+	r.handler.Store(&h)
+	return r
+}
+
+func (r *reloadableHandler) store(h http.Handler) {
+	r.handler.Store(&h)
+}
+
+func (r *reloadableHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	(*r.handler.Load()).ServeHTTP(w, req)
+}
+
+// reloadableContextFunc delegates to an HTTP context function that can be
+// swapped atomically, along with the auth middleware it uses
+type reloadableContextFunc struct {
+	fn     atomic.Pointer[server.HTTPContextFunc]
+	authMW atomic.Pointer[AuthMiddleware]
+}
+
+func (r *reloadableContextFunc) store(fn server.HTTPContextFunc, auth *AuthMiddleware) {
+	r.fn.Store(&fn)
+	r.authMW.Store(auth)
+}
+
+// auth returns the current auth middleware, or nil when auth is disabled
+func (r *reloadableContextFunc) auth() *AuthMiddleware {
+	return r.authMW.Load()
+}
+
+func (r *reloadableContextFunc) contextFunc(ctx context.Context, req *http.Request) context.Context {
+	return (*r.fn.Load())(ctx, req)
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigReloader(t *testing.T) {
+	for _, key := range []string{"TIME_HTTP_CORS_ENABLED", "TIME_HTTP_CORS_ORIGINS", "TIME_HTTP_ADDRESS"} {
+		t.Setenv(key, "")
+		os.Unsetenv(key)
+	}
+	t.Cleanup(func() {
+		for key := range fileEnv {
+			os.Unsetenv(key)
+		}
+		fileEnv = map[string]string{}
+	})
+
+	path := filepath.Join(t.TempDir(), "timemcp.yaml")
+	writeConfig := func(origin string) {
+		content := "http:\n  cors:\n    enabled: true\n    origins: [" + origin + "]\n"
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("Failed to write config file: %v", err)
+		}
+	}
+
+	writeConfig("https://old.example.com")
+	if err := applyConfigFile(path); err != nil {
+		t.Fatalf("Failed to apply config file: %v", err)
+	}
+	config, err := NewConfig()
+	if err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+
+	adjusted := false
+	reloader := newConfigReloader(config, path, func(c *Config) { adjusted = true })
+	mcpHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := newReloadableHandler(createCustomHTTPHandler(mcpHandler, config, nil))
+	reloader.onReload("HTTP handlers", func(c *Config) error {
+		handler.store(createCustomHTTPHandler(mcpHandler, c, nil))
+		return nil
+	})
+
+	allowedOrigin := func(origin string) string {
+		req := httptest.NewRequest(http.MethodOptions, "/mcp", nil)
+		req.Header.Set("Origin", origin)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Header().Get("Access-Control-Allow-Origin")
+	}
+
+	if allowedOrigin("https://old.example.com") == "" {
+		t.Fatal("Expected the initial origin to be allowed")
+	}
+
+	writeConfig("https://new.example.com")
+	if err := reloader.reload(); err != nil {
+		t.Fatalf("Failed to reload: %v", err)
+	}
+	if !adjusted {
+		t.Error("Expected command line overrides to be re-applied")
+	}
+	if allowedOrigin("https://old.example.com") != "" {
+		t.Error("Expected the old origin to be rejected after reload")
+	}
+	if allowedOrigin("https://new.example.com") == "" {
+		t.Error("Expected the new origin to be allowed after reload")
+	}
+	if got := reloader.Load().HTTPCORSOrigins; len(got) != 1 || got[0] != "new.example.com" {
+		t.Errorf("Expected reloaded origins, but got %v", got)
+	}
+
+	// An invalid file leaves the previous configuration in place
+	if err := os.WriteFile(path, []byte("http:\n  unknown: 1\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	if err := reloader.reload(); err == nil {
+		t.Error("Expected reload of an invalid file to fail")
+	}
+	if allowedOrigin("https://new.example.com") == "" {
+		t.Error("Expected the previous configuration to stay active")
+	}
+}
+
+func TestWarnRestartRequired(t *testing.T) {
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	old := &Config{DefaultTimezone: "UTC", AuthAdminRole: "admin"}
+	warnRestartRequired(old, &Config{DefaultTimezone: "Europe/Warsaw", AuthAdminRole: "ops"})
+	for _, setting := range []string{"TIME_DEFAULT_TIMEZONE", "TIME_AUTH_ADMIN_ROLE"} {
+		if !strings.Contains(buf.String(), setting) {
+			t.Errorf("Expected a restart warning for %s, but got %s", setting, buf.String())
+		}
+	}
+
+	buf.Reset()
+	warnRestartRequired(old, &Config{DefaultTimezone: "UTC", AuthAdminRole: "admin"})
+	if buf.Len() != 0 {
+		t.Errorf("Expected no warnings for unchanged settings, but got %s", buf.String())
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequestIDHandler(t *testing.T) {
+	var seen string
+	handler := requestIDHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = getRequestID(r.Context())
+	}))
+
+	tests := []struct {
+		name     string
+		incoming string
+		reused   bool
+	}{
+		{"generated", "", false},
+		{"honored", "agent-run-42/step-7", true},
+		{"too long", strings.Repeat("a", maxRequestIDLength+1), false},
+		{"control characters", "id\x00forged", false},
+		{"spaces", "two words", false},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("POST", "/mcp", nil)
+		if tt.incoming != "" {
+			req.Header.Set(requestIDHeader, tt.incoming)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		echoed := rec.Header().Get(requestIDHeader)
+		if echoed == "" || echoed != seen {
+			t.Errorf("%s: Expected response header %q to match context ID %q", tt.name, echoed, seen)
+		}
+		if reused := echoed == tt.incoming; reused != tt.reused {
+			t.Errorf("%s: Expected incoming ID reused=%v but got ID %q", tt.name, tt.reused, echoed)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// restPathPrefix is where the REST API is served
+	restPathPrefix = "/api/v1/"
+	// maxRESTBodySize caps REST request bodies
+	maxRESTBodySize = 1 << 20
+)
+
+// restError is the body of a failed REST call
+type restError struct {
+	Error string        `json:"error"`
+	Code  toolErrorCode `json:"code,omitempty"`
+}
+
+// restHandler serves the REST API. Each endpoint is a tools/call sent
+// through the MCP server, so REST callers get the same handlers, tool
+// middleware (auth, roles, quotas, statistics, timeouts) and results as MCP
+// clients:
+//
+//	GET  /api/v1/now?tz=Europe/Warsaw       get_current_time
+//	POST /api/v1/convert                    convert_time_v2
+//	GET  /api/v1/tools                      the tools the caller may use
+//	POST /api/v1/tools/{name}               any tool, with its arguments as the body
+func restHandler(config *Config, rt *httpRuntime) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(restPathPrefix+"now", func(w http.ResponseWriter, r *http.Request) {
+		if !allowRESTMethod(w, r, http.MethodGet) {
+			return
+		}
+		callRESTTool(w, r, config, rt, "get_current_time", map[string]any{"timezone": r.URL.Query().Get("tz")})
+	})
+	mux.HandleFunc(restPathPrefix+"convert", func(w http.ResponseWriter, r *http.Request) {
+		if !allowRESTMethod(w, r, http.MethodPost) {
+			return
+		}
+		if args, ok := decodeRESTArguments(w, r); ok {
+			callRESTTool(w, r, config, rt, "convert_time_v2", args)
+		}
+	})
+	mux.HandleFunc(restPathPrefix+"tools", func(w http.ResponseWriter, r *http.Request) {
+		if !allowRESTMethod(w, r, http.MethodGet) {
+			return
+		}
+		claims, ok := authenticateEndpoint(w, r, config, rt)
+		if !ok {
+			return
+		}
+		writeJSONResponse(w, r, http.StatusOK, capabilities(rt, config, claims).Tools)
+	})
+	mux.HandleFunc(restPathPrefix+"tools/{name}", func(w http.ResponseWriter, r *http.Request) {
+		if !allowRESTMethod(w, r, http.MethodPost) {
+			return
+		}
+		if args, ok := decodeRESTArguments(w, r); ok {
+			callRESTTool(w, r, config, rt, r.PathValue("name"), args)
+		}
+	})
+	mux.HandleFunc(restPathPrefix, func(w http.ResponseWriter, r *http.Request) {
+		writeJSONResponse(w, r, http.StatusNotFound, restError{Error: "Not Found"})
+	})
+	return mux
+}
+
+// allowRESTMethod rejects requests with another method
+func allowRESTMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method == method {
+		return true
+	}
+	w.Header().Set("Allow", method)
+	writeJSONResponse(w, r, http.StatusMethodNotAllowed, restError{Error: "Method Not Allowed"})
+	return false
+}
+
+// decodeRESTArguments reads a JSON object of tool arguments; an empty body
+// means no arguments
+func decodeRESTArguments(w http.ResponseWriter, r *http.Request) (map[string]any, bool) {
+	args := map[string]any{}
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRESTBodySize)).Decode(&args)
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		writeJSONResponse(w, r, http.StatusRequestEntityTooLarge, restError{Error: fmt.Sprintf("Request body exceeds %d bytes", maxRESTBodySize)})
+		return nil, false
+	case err != nil && !errors.Is(err, io.EOF):
+		writeJSONResponse(w, r, http.StatusBadRequest, restError{Error: fmt.Sprintf("Invalid JSON body: %v", err)})
+		return nil, false
+	}
+	return args, true
+}
+
+// callRESTTool calls a tool through the MCP server with the request's
+// authentication context and writes its structured result, or its text as
+// {"result": ...}
+func callRESTTool(w http.ResponseWriter, r *http.Request, config *Config, rt *httpRuntime, name string, args map[string]any) {
+	if rt.mcpServer.GetTool(name) == nil {
+		writeJSONResponse(w, r, http.StatusNotFound, restError{Error: fmt.Sprintf("Unknown tool: %s", name)})
+		return
+	}
+	message, err := json.Marshal(mcp.JSONRPCRequest{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      mcp.NewRequestId(1),
+		Request: mcp.Request{Method: string(mcp.MethodToolsCall)},
+		Params:  mcp.CallToolParams{Name: name, Arguments: args},
+	})
+	if err != nil {
+		writeJSONResponse(w, r, http.StatusInternalServerError, restError{Error: "Internal Server Error"})
+		return
+	}
+
+	ctx := rt.contextFunc.contextFunc(r.Context(), r)
+	var result *mcp.CallToolResult
+	switch resp := rt.mcpServer.HandleMessage(ctx, message).(type) {
+	case mcp.JSONRPCResponse:
+		result, _ = resp.Result.(*mcp.CallToolResult)
+	case mcp.JSONRPCError:
+		writeJSONResponse(w, r, http.StatusBadRequest, restError{Error: resp.Error.Message})
+		return
+	}
+	if result == nil {
+		writeJSONResponse(w, r, http.StatusInternalServerError, restError{Error: "Internal Server Error"})
+		return
+	}
+
+	text := toolResultText(result)
+	if result.IsError {
+		code := toolErrorCodeOf(result)
+		status := code.httpStatus()
+		if status == http.StatusUnauthorized {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+		}
+		slog.DebugContext(r.Context(), "REST tool call failed", "tool", name, "status", status, "code", code)
+		writeJSONResponse(w, r, status, restError{Error: text, Code: code})
+		return
+	}
+	if result.StructuredContent != nil {
+		writeJSONResponse(w, r, http.StatusOK, result.StructuredContent)
+		return
+	}
+	writeJSONResponse(w, r, http.StatusOK, map[string]string{"result": text})
+}
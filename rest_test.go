@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func newRESTTestHandler(t *testing.T, config *Config) (http.Handler, *AuthMiddleware) {
+	t.Helper()
+	contextFunc := &reloadableContextFunc{}
+	fn, auth, err := createHTTPMiddleware(config)
+	if err != nil {
+		t.Fatalf("Failed to create HTTP middleware: %v", err)
+	}
+	contextFunc.store(fn, auth)
+	mcpServer := server.NewMCPServer("TimeMCP", "test", server.WithToolCapabilities(true))
+	newToolMiddlewareChain(func() *Config { return config }, nil, nil).install(mcpServer)
+	addTools(mcpServer, config)
+	mcpHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	return createCustomHTTPHandler(mcpHandler, config, &httpRuntime{mcpServer: mcpServer, contextFunc: contextFunc}), auth
+}
+
+func serveREST(handler http.Handler, method, path, body, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRESTHandler(t *testing.T) {
+	handler, _ := newRESTTestHandler(t, &Config{HTTPREST: true})
+
+	rec := serveREST(handler, "GET", "/api/v1/now?tz=Asia/Tokyo", "", "")
+	var text map[string]string
+	json.NewDecoder(rec.Body).Decode(&text)
+	if rec.Code != http.StatusOK || !strings.Contains(text["result"], "Asia/Tokyo (JST, UTC+09:00)") {
+		t.Errorf("Expected the Tokyo time but got %d %v", rec.Code, text)
+	}
+
+	rec = serveREST(handler, "POST", "/api/v1/convert", `{"source_timezone": "UTC", "time": "14:00", "target_timezone": "Asia/Kolkata"}`, "")
+	var conversion timeConversion
+	json.NewDecoder(rec.Body).Decode(&conversion)
+	if rec.Code != http.StatusOK || conversion.TimeDifference != "+5h30m" {
+		t.Errorf("Expected a structured conversion but got %d %+v", rec.Code, conversion)
+	}
+
+	rec = serveREST(handler, "POST", "/api/v1/tools/describe_cron", `{"expression": "@daily"}`, "")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "00:00") {
+		t.Errorf("Expected the cron description but got %d %s", rec.Code, rec.Body.String())
+	}
+
+	rec = serveREST(handler, "GET", "/api/v1/now?tz=Invalid/Zone", "", "")
+	var restErr restError
+	json.NewDecoder(rec.Body).Decode(&restErr)
+	if restErr.Code != errorInvalidTimezone || restErr.Error != "Invalid timezone: Invalid/Zone" {
+		t.Errorf("Expected an INVALID_TIMEZONE error but got %+v", restErr)
+	}
+
+	tests := []struct {
+		method, path, body string
+		expected           int
+	}{
+		{"GET", "/api/v1/now?tz=Invalid/Zone", "", http.StatusBadRequest},
+		{"POST", "/api/v1/now", "", http.StatusMethodNotAllowed},
+		{"POST", "/api/v1/convert", "{", http.StatusBadRequest},
+		{"POST", "/api/v1/tools/get_current_time", "", http.StatusOK},
+		{"POST", "/api/v1/tools/missing_tool", "{}", http.StatusNotFound},
+		{"GET", "/api/v1/other", "", http.StatusNotFound},
+		{"POST", "/mcp", "{}", http.StatusTeapot},
+	}
+	for _, tt := range tests {
+		if rec := serveREST(handler, tt.method, tt.path, tt.body, ""); rec.Code != tt.expected {
+			t.Errorf("%s %s: Expected status %d but got %d %s", tt.method, tt.path, tt.expected, rec.Code, rec.Body.String())
+		}
+	}
+
+	disabled, _ := newRESTTestHandler(t, &Config{})
+	if rec := serveREST(disabled, "GET", "/api/v1/now", "", ""); rec.Code != http.StatusTeapot {
+		t.Errorf("Expected REST to be off by default but got %d", rec.Code)
+	}
+}
+
+func TestRESTHandler_Auth(t *testing.T) {
+	config := &Config{
+		HTTPREST:      true,
+		AuthEnabled:   true,
+		AuthSecretKey: "test-secret-key-that-is-long-enough-for-hs256",
+		AuthIssuer:    "test-issuer",
+		AuthAudience:  "test-audience",
+		AuthRoles:     rolePolicy{"viewer": {"get_current_time": {}}},
+	}
+	handler, auth := newRESTTestHandler(t, config)
+
+	rec := serveREST(handler, "GET", "/api/v1/now?tz=UTC", "", "")
+	if rec.Code != http.StatusUnauthorized || rec.Header().Get("WWW-Authenticate") != "Bearer" {
+		t.Errorf("Expected status 401 without credentials but got %d", rec.Code)
+	}
+	token, _ := auth.GenerateToken("1", "vera", "viewer", 1)
+	if rec := serveREST(handler, "GET", "/api/v1/now?tz=UTC", "", token); rec.Code != http.StatusOK {
+		t.Errorf("Expected status 200 for the viewer but got %d %s", rec.Code, rec.Body.String())
+	}
+	if rec := serveREST(handler, "POST", "/api/v1/convert", `{"target_timezone": "UTC"}`, token); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 for a tool outside the role but got %d", rec.Code)
+	}
+	rec = serveREST(handler, "GET", "/api/v1/tools", "", token)
+	var tools []struct {
+		Name string `json:"name"`
+	}
+	json.NewDecoder(rec.Body).Decode(&tools)
+	if rec.Code != http.StatusOK || len(tools) != 1 || tools[0].Name != "get_current_time" {
+		t.Errorf("Expected only get_current_time for the viewer but got %d %v", rec.Code, tools)
+	}
+}
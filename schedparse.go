@@ -0,0 +1,432 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// defaultScheduleOccurrences is how many occurrences parse_schedule lists by default
+	defaultScheduleOccurrences = 5
+	// maxScheduleOccurrences caps the occurrences of one parse_schedule call
+	maxScheduleOccurrences = 100
+)
+
+// scheduleWeekdays maps weekday names, abbreviations and plurals to weekdays
+var scheduleWeekdays = map[string]time.Weekday{
+	"monday": time.Monday, "mon": time.Monday, "mo": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday, "fr": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+	"sunday": time.Sunday, "sun": time.Sunday,
+}
+
+// compactWeekday is one letter code of a compact weekday notation
+type compactWeekday struct {
+	code string
+	day  time.Weekday
+}
+
+// compactWeekdays are the letters of notations such as MWF or TTh, two-letter
+// codes first so they win over their first letter; R is Thursday and U Sunday
+var compactWeekdays = []compactWeekday{
+	{"th", time.Thursday}, {"tu", time.Tuesday}, {"sa", time.Saturday}, {"su", time.Sunday},
+	{"m", time.Monday}, {"t", time.Tuesday}, {"w", time.Wednesday}, {"r", time.Thursday},
+	{"f", time.Friday}, {"s", time.Saturday}, {"u", time.Sunday},
+}
+
+// scheduleOrdinals are ordinal words for "first monday" or "last day"
+var scheduleOrdinals = map[string]int{"first": 1, "second": 2, "third": 3, "fourth": 4, "fifth": 5, "last": -1}
+
+var (
+	// scheduleTimePattern matches 9am, 9:30pm, 09:00 and 21:15
+	scheduleTimePattern = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(am|pm)?$`)
+	// scheduleDayNumberPattern matches 1st, 2nd, 3rd and 15th
+	scheduleDayNumberPattern = regexp.MustCompile(`^(\d{1,2})(?:st|nd|rd|th)$`)
+	// scheduleMeridiemPattern joins "9 am" and "9 a.m." into 9am
+	scheduleMeridiemPattern = regexp.MustCompile(`(\d)\s*([ap])\.?m\b\.?`)
+)
+
+// scheduleFillers are words that carry no meaning in a schedule
+var scheduleFillers = map[string]bool{"on": true, "the": true, "and": true, "of": true, "in": true, "a": true, "&": true}
+
+// scheduleNotation is a parsed schedule: a recurrence rule and a time of day
+type scheduleNotation struct {
+	freq      string
+	interval  int
+	days      []weekdayNum
+	monthDays []int
+	clock     time.Duration
+	hasClock  bool
+}
+
+// rrule returns the notation as an RRULE value; fallback is the weekday of a
+// weekly rule that names none
+func (n *scheduleNotation) rrule(fallback time.Weekday) string {
+	parts := []string{"FREQ=" + n.freq}
+	if n.interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(n.interval))
+	}
+	days := n.days
+	if n.freq == "WEEKLY" && len(days) == 0 {
+		days = []weekdayNum{{0, fallback}}
+	}
+	if len(days) > 0 {
+		codes := make([]string, len(days))
+		for i, wd := range days {
+			codes[i] = strings.ToUpper(wd.day.String()[:2])
+			if wd.n != 0 {
+				codes[i] = strconv.Itoa(wd.n) + codes[i]
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+	if len(n.monthDays) > 0 {
+		nums := make([]string, len(n.monthDays))
+		for i, d := range n.monthDays {
+			nums[i] = strconv.Itoa(d)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(nums, ","))
+	}
+	return strings.Join(parts, ";")
+}
+
+// parseScheduleClock parses a time of day such as 9am, 9:30pm or 21:15
+func parseScheduleClock(token string) (time.Duration, bool) {
+	switch token {
+	case "noon":
+		return 12 * time.Hour, true
+	case "midnight":
+		return 0, true
+	}
+	m := scheduleTimePattern.FindStringSubmatch(token)
+	if m == nil || m[2] == "" && m[3] == "" {
+		return 0, false
+	}
+	hour, _ := strconv.Atoi(m[1])
+	minute, _ := strconv.Atoi(m[2])
+	switch {
+	case m[3] != "" && (hour < 1 || hour > 12):
+		return 0, false
+	case m[3] == "am" && hour == 12:
+		hour = 0
+	case m[3] == "pm" && hour < 12:
+		hour += 12
+	}
+	if hour > 23 || minute > 59 {
+		return 0, false
+	}
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute, true
+}
+
+// parseCompactWeekdays parses letter notations such as mwf, tth or mtwrf
+func parseCompactWeekdays(token string) ([]time.Weekday, bool) {
+	var days []time.Weekday
+	for token != "" {
+		i := slices.IndexFunc(compactWeekdays, func(c compactWeekday) bool { return strings.HasPrefix(token, c.code) })
+		if i < 0 {
+			return nil, false
+		}
+		days = append(days, compactWeekdays[i].day)
+		token = token[len(compactWeekdays[i].code):]
+	}
+	return days, true
+}
+
+// scheduleWeekday looks up a weekday name, abbreviation or plural
+func scheduleWeekday(token string) (time.Weekday, bool) {
+	if day, ok := scheduleWeekdays[token]; ok {
+		return day, true
+	}
+	day, ok := scheduleWeekdays[strings.TrimSuffix(token, "s")]
+	return day, ok && len(token) > 3
+}
+
+// parseScheduleNotation parses compact human schedules such as "MWF 09:00",
+// "every other Thursday at 5pm", "1st and 15th of the month" or "last
+// friday of each month"
+func parseScheduleNotation(s string) (*scheduleNotation, error) {
+	lower := scheduleMeridiemPattern.ReplaceAllString(strings.ToLower(s), "${1}${2}m")
+	tokens := strings.FieldsFunc(lower, func(r rune) bool { return r == ' ' || r == ',' || r == '/' || r == '+' })
+	n := &scheduleNotation{interval: 1}
+	setFreq := func(freq string) error {
+		if n.freq != "" && n.freq != freq {
+			return fmt.Errorf("conflicting frequencies %s and %s", strings.ToLower(n.freq), strings.ToLower(freq))
+		}
+		n.freq = freq
+		return nil
+	}
+	setClock := func(d time.Duration) error {
+		if n.hasClock {
+			return fmt.Errorf("only one time of day is supported; use one schedule per time")
+		}
+		n.clock, n.hasClock = d, true
+		return nil
+	}
+	addDay := func(wd weekdayNum) {
+		if !slices.Contains(n.days, wd) {
+			n.days = append(n.days, wd)
+		}
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		next := ""
+		if i+1 < len(tokens) {
+			next = tokens[i+1]
+		}
+		var err error
+		switch {
+		case scheduleFillers[token]:
+		case token == "every" || token == "each":
+			switch num, convErr := strconv.Atoi(next); {
+			case next == "other":
+				n.interval = 2
+				i++
+			case convErr == nil:
+				if num < 1 || num > 1000 {
+					return nil, fmt.Errorf("invalid interval %d", num)
+				}
+				n.interval = num
+				i++
+			}
+		case token == "day" || token == "days" || token == "daily":
+			err = setFreq("DAILY")
+		case token == "week" || token == "weeks" || token == "weekly":
+			err = setFreq("WEEKLY")
+		case token == "month" || token == "months" || token == "monthly":
+			err = setFreq("MONTHLY")
+		case token == "biweekly" || token == "fortnightly" || token == "fortnight":
+			err = setFreq("WEEKLY")
+			n.interval = 2
+		case token == "weekday" || token == "weekdays":
+			for day := time.Monday; day <= time.Friday; day++ {
+				addDay(weekdayNum{0, day})
+			}
+		case token == "weekend" || token == "weekends":
+			addDay(weekdayNum{0, time.Saturday})
+			addDay(weekdayNum{0, time.Sunday})
+		case token == "at":
+			// A bare hour after "at" is a time of day: "at 9"
+			if hour, convErr := strconv.Atoi(next); convErr == nil && hour >= 0 && hour <= 23 {
+				err = setClock(time.Duration(hour) * time.Hour)
+				i++
+			}
+		default:
+			if d, ok := parseScheduleClock(token); ok {
+				err = setClock(d)
+				break
+			}
+			ordinal, isOrdinal := scheduleOrdinals[token]
+			if m := scheduleDayNumberPattern.FindStringSubmatch(token); m != nil {
+				ordinal, _ = strconv.Atoi(m[1])
+				isOrdinal = ordinal >= 1 && ordinal <= 31
+			}
+			if isOrdinal {
+				if day, ok := scheduleWeekday(next); ok {
+					if ordinal > 5 {
+						return nil, fmt.Errorf("there is no %s %s in a month", token, next)
+					}
+					addDay(weekdayNum{ordinal, day})
+					i++
+				} else {
+					if next == "day" {
+						i++
+					}
+					if !slices.Contains(n.monthDays, ordinal) {
+						n.monthDays = append(n.monthDays, ordinal)
+					}
+				}
+				break
+			}
+			if day, ok := scheduleWeekday(token); ok {
+				addDay(weekdayNum{0, day})
+				break
+			}
+			if from, to, ok := strings.Cut(token, "-"); ok {
+				first, ok1 := scheduleWeekday(from)
+				last, ok2 := scheduleWeekday(to)
+				if ok1 && ok2 {
+					for day := first; ; day = (day + 1) % 7 {
+						addDay(weekdayNum{0, day})
+						if day == last {
+							break
+						}
+					}
+					break
+				}
+			}
+			if days, ok := parseCompactWeekdays(token); ok {
+				for _, day := range days {
+					addDay(weekdayNum{0, day})
+				}
+				break
+			}
+			return nil, fmt.Errorf("unrecognized %q", token)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	numbered := slices.ContainsFunc(n.days, func(wd weekdayNum) bool { return wd.n != 0 })
+	switch {
+	case len(n.monthDays) > 0 || numbered:
+		if err := setFreq("MONTHLY"); err != nil {
+			return nil, err
+		}
+		if numbered && slices.ContainsFunc(n.days, func(wd weekdayNum) bool { return wd.n == 0 }) {
+			return nil, fmt.Errorf("mix of numbered and plain weekdays")
+		}
+		if len(n.monthDays) > 0 && len(n.days) > 0 {
+			return nil, fmt.Errorf("mix of days of the month and weekdays")
+		}
+	case len(n.days) > 0:
+		// "daily on weekdays" and "every monday" are weekly rules
+		if n.freq == "DAILY" && n.interval == 1 || n.freq == "" {
+			n.freq = "WEEKLY"
+		}
+		if n.freq != "WEEKLY" {
+			return nil, fmt.Errorf("weekdays need a weekly schedule")
+		}
+	case n.freq == "":
+		return nil, fmt.Errorf("no days or frequency found")
+	}
+	slices.SortFunc(n.days, func(a, b weekdayNum) int {
+		// Monday first, as RRULE's default WKST
+		return (int(a.day)+6)%7 - (int(b.day)+6)%7
+	})
+	slices.SortFunc(n.monthDays, func(a, b int) int {
+		// Last days (negative) after numbered ones
+		if (a < 0) != (b < 0) {
+			return b - a
+		}
+		return a - b
+	})
+	return n, nil
+}
+
+// parsedSchedule is the structured result of parse_schedule
+type parsedSchedule struct {
+	Schedule    string   `json:"schedule"`
+	RRule       string   `json:"rrule" jsonschema:"RFC 5545 RRULE value, to be used with dtstart"`
+	DTStart     string   `json:"dtstart" jsonschema:"First occurrence in RFC 3339, the rule's DTSTART"`
+	ICal        string   `json:"ical" jsonschema:"DTSTART and RRULE lines for an iCalendar event"`
+	Timezone    string   `json:"timezone"`
+	Time        string   `json:"time" jsonschema:"Time of day, HH:MM; 00:00 when the schedule names none"`
+	Occurrences []string `json:"occurrences" jsonschema:"Next occurrences in RFC 3339"`
+}
+
+func addParseScheduleTool(mcpServer *server.MCPServer, config *Config) {
+	mcpServer.AddTool(
+		mcp.NewTool("parse_schedule",
+			mcp.WithDescription("Parse a compact human schedule such as 'MWF 09:00', 'TTh 2:30pm', 'every other Thursday at 5pm', 'weekdays 8am', '1st and 15th of the month' or 'last friday of each month' into an RRULE with its DTSTART and the next occurrences."),
+			mcp.WithString("schedule",
+				mcp.Description("The schedule. Weekdays as names, ranges (mon-fri) or letters (MWF, TTh, MTWRF); days of the month as 1st, 15th or 'last day'; 'every other', 'every 3 weeks', daily, weekly, monthly; one time of day such as 9am or 17:30."),
+				mcp.Required(),
+			),
+			mcp.WithString("timezone",
+				mcp.Description("Timezone of the schedule. Defaults to the session or server default."),
+			),
+			mcp.WithString("from",
+				mcp.Description("List occurrences from this time, in RFC 3339 or 'YYYY-MM-DD HH:MM' in timezone. Defaults to now."),
+			),
+			mcp.WithNumber("count",
+				mcp.Description(fmt.Sprintf("Number of occurrences to list, 1 to %d. Defaults to %d.", maxScheduleOccurrences, defaultScheduleOccurrences)),
+			),
+			mcp.WithOutputSchema[parsedSchedule](),
+			mcp.WithTitleAnnotation("Parse Schedule"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		handleParseSchedule(config),
+	)
+}
+
+// handleParseSchedule returns a handler for the parse_schedule tool
+func handleParseSchedule(config *Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		spec, err := request.RequireString("schedule")
+		if err != nil {
+			return toolError(errorInvalidArgument, err.Error()), nil
+		}
+		timezoneStr := request.GetString("timezone", "")
+		loc, err := loadTimezone(ctx, timezoneStr, config)
+		if err != nil {
+			return invalidTimezoneError("Invalid timezone", timezoneStr), nil
+		}
+		from := config.now().In(loc)
+		if fromStr := request.GetString("from", ""); fromStr != "" {
+			if from, err = time.Parse(time.RFC3339, fromStr); err != nil {
+				if from, err = time.ParseInLocation("2006-01-02 15:04", fromStr, loc); err != nil {
+					return toolError(errorParse, fmt.Sprintf("Invalid from: %s. Please provide RFC 3339 or 'YYYY-MM-DD HH:MM'.", fromStr)), nil
+				}
+			}
+			from = from.In(loc)
+		}
+		count := request.GetInt("count", defaultScheduleOccurrences)
+		if count < 1 || count > maxScheduleOccurrences {
+			return toolError(errorInvalidArgument, fmt.Sprintf("Invalid count: %d. Please provide 1 to %d.", count, maxScheduleOccurrences)), nil
+		}
+
+		notation, err := parseScheduleNotation(spec)
+		if err != nil {
+			return toolError(errorParse, fmt.Sprintf("Invalid schedule %q: %v", spec, err)), nil
+		}
+		start := clockOn(time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc), notation.clock)
+		if start.Before(from) {
+			start = clockOn(time.Date(from.Year(), from.Month(), from.Day()+1, 0, 0, 0, 0, loc), notation.clock)
+		}
+		ruleStr := notation.rrule(start.Weekday())
+		rule, err := parseRecurrenceRule(ruleStr, loc)
+		if err != nil {
+			return toolError(errorParse, fmt.Sprintf("Invalid schedule %q: %v", spec, err)), nil
+		}
+
+		// The first matching day at or after from anchors the rule, so
+		// "every other Thursday" starts with the next Thursday
+		anchor := *rule
+		anchor.Interval = 1
+		var first time.Time
+		anchor.expand(start, func(t time.Time) bool {
+			first = t
+			return false
+		})
+		if first.IsZero() {
+			return toolError(errorInvalidArgument, fmt.Sprintf("Schedule %q has no occurrences", spec)), nil
+		}
+
+		result := parsedSchedule{
+			Schedule:    spec,
+			RRule:       ruleStr,
+			DTStart:     first.Format(time.RFC3339),
+			ICal:        fmt.Sprintf("DTSTART;TZID=%s:%s\nRRULE:%s", loc.String(), first.Format("20060102T150405"), ruleStr),
+			Timezone:    loc.String(),
+			Time:        formatClock(notation.clock),
+			Occurrences: []string{},
+		}
+		rule.expand(first, func(t time.Time) bool {
+			result.Occurrences = append(result.Occurrences, t.Format(time.RFC3339))
+			return len(result.Occurrences) < count
+		})
+
+		data, err := json.Marshal(result)
+		if err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to encode result: %v", err)), nil
+		}
+		return mcp.NewToolResultStructured(result, string(data)), nil
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestParseScheduleNotation(t *testing.T) {
+	tests := []struct {
+		schedule string
+		rrule    string
+		clock    string
+	}{
+		{"MWF 09:00", "FREQ=WEEKLY;BYDAY=MO,WE,FR", "09:00"},
+		{"TTh 2:30 p.m.", "FREQ=WEEKLY;BYDAY=TU,TH", "14:30"},
+		{"mtwrf 8am", "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR", "08:00"},
+		{"every other Thursday at 5pm", "FREQ=WEEKLY;INTERVAL=2;BYDAY=TH", "17:00"},
+		{"every 3 weeks on mon-wed", "FREQ=WEEKLY;INTERVAL=3;BYDAY=MO,TU,WE", "00:00"},
+		{"weekdays at 7", "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR", "07:00"},
+		{"Saturdays and Sundays noon", "FREQ=WEEKLY;BYDAY=SA,SU", "12:00"},
+		{"every 2 days", "FREQ=DAILY;INTERVAL=2", "00:00"},
+		{"1st and 15th of the month", "FREQ=MONTHLY;BYMONTHDAY=1,15", "00:00"},
+		{"last day and the 15th of each month 18:00", "FREQ=MONTHLY;BYMONTHDAY=15,-1", "18:00"},
+		{"last Friday of the month", "FREQ=MONTHLY;BYDAY=-1FR", "00:00"},
+		{"first monday and third monday", "FREQ=MONTHLY;BYDAY=1MO,3MO", "00:00"},
+		{"biweekly on tue", "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU", "00:00"},
+	}
+	for _, tt := range tests {
+		n, err := parseScheduleNotation(tt.schedule)
+		if err != nil {
+			t.Errorf("Failed to parse %q: %v", tt.schedule, err)
+			continue
+		}
+		if got := n.rrule(time.Monday); got != tt.rrule || formatClock(n.clock) != tt.clock {
+			t.Errorf("Expected %q to parse as %s at %s but got %s at %s", tt.schedule, tt.rrule, tt.clock, got, formatClock(n.clock))
+		}
+	}
+
+	for _, schedule := range []string{"", "sometimes", "MWF 9am and 5pm", "daily on the 1st", "6th friday", "1st and monday", "every 9"} {
+		if _, err := parseScheduleNotation(schedule); err == nil {
+			t.Errorf("Expected %q to be rejected", schedule)
+		}
+	}
+}
+
+func TestParseSchedule(t *testing.T) {
+	// Friday 2025-07-04 10:00 in Warsaw
+	clock := newFakeClock(time.Date(2025, 7, 4, 8, 0, 0, 0, time.UTC))
+	handler := handleParseSchedule(&Config{DefaultTimezone: "UTC", Clock: clock})
+	call := func(args map[string]any) *mcp.CallToolResult {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := handler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("Failed to call handler: %v", err)
+		}
+		return result
+	}
+
+	result := call(map[string]any{"schedule": "every other Thursday at 5pm", "timezone": "Europe/Warsaw", "count": 3})
+	if result.IsError {
+		t.Fatalf("Failed to parse schedule: %s", toolResultText(result))
+	}
+	got := result.StructuredContent.(parsedSchedule)
+	// The next Thursday anchors the fortnightly rule
+	want := []string{"2025-07-10T17:00:00+02:00", "2025-07-24T17:00:00+02:00", "2025-08-07T17:00:00+02:00"}
+	if got.DTStart != want[0] || len(got.Occurrences) != 3 || got.Occurrences[1] != want[1] || got.Occurrences[2] != want[2] {
+		t.Errorf("Expected occurrences %v but got %+v", want, got)
+	}
+	if got.ICal != "DTSTART;TZID=Europe/Warsaw:20250710T170000\nRRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=TH" {
+		t.Errorf("Expected iCalendar lines for the rule but got %q", got.ICal)
+	}
+
+	// 09:00 today has passed, so MWF starts on Monday
+	result = call(map[string]any{"schedule": "MWF 09:00", "timezone": "Europe/Warsaw", "count": 2})
+	if got := result.StructuredContent.(parsedSchedule); got.Occurrences[0] != "2025-07-07T09:00:00+02:00" || got.Occurrences[1] != "2025-07-09T09:00:00+02:00" {
+		t.Errorf("Expected MWF to start on Monday 2025-07-07 but got %v", got.Occurrences)
+	}
+	result = call(map[string]any{"schedule": "1st and 15th of the month", "from": "2025-07-20 00:00", "count": 2})
+	if got := result.StructuredContent.(parsedSchedule); got.Occurrences[0] != "2025-08-01T00:00:00Z" || got.Occurrences[1] != "2025-08-15T00:00:00Z" {
+		t.Errorf("Expected the 1st and 15th of August but got %v", got.Occurrences)
+	}
+
+	for _, args := range []map[string]any{
+		{"schedule": "whenever"},
+		{"schedule": "MWF", "count": 0},
+		{"schedule": "MWF", "from": "soon"},
+	} {
+		if result := call(args); !result.IsError {
+			t.Errorf("Expected %v to be rejected", args)
+		}
+	}
+}
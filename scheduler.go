@@ -0,0 +1,606 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// schedulerPollInterval is how often due jobs are looked for
+	schedulerPollInterval = time.Second
+	// scheduleKeyPrefix namespaces jobs in the state store
+	scheduleKeyPrefix = "schedule:"
+	// scheduleClaimTTL keeps a firing claim long enough for every replica
+	// to see it
+	scheduleClaimTTL = time.Hour
+	// webhookTimeout bounds one webhook delivery
+	webhookTimeout = 10 * time.Second
+	// maxSchedulePayload caps the payload stored with a job
+	maxSchedulePayload = 16 << 10
+	// notificationGracePeriod is how long a notification job waits for its
+	// session to be seen by a replica before it is dropped
+	notificationGracePeriod = time.Minute
+	// defaultSchedulerMaxJobs is the default number of jobs per user
+	defaultSchedulerMaxJobs = 100
+)
+
+// scheduledJob is a webhook or notification registered by schedule_webhook
+type scheduledJob struct {
+	ID          string          `json:"id"`
+	Owner       string          `json:"owner,omitempty"`
+	Description string          `json:"description,omitempty"`
+	URL         string          `json:"url,omitempty" jsonschema:"Webhook URL, for webhook jobs"`
+	SessionID   string          `json:"session_id,omitempty" jsonschema:"MCP session notified, for notification jobs"`
+	At          time.Time       `json:"at,omitzero" jsonschema:"Instant of a one-time job"`
+	Cron        string          `json:"cron,omitempty" jsonschema:"Cron expression of a recurring job"`
+	Timezone    string          `json:"timezone"`
+	Payload     json.RawMessage `json:"payload,omitempty"`
+	NextRun     time.Time       `json:"next_run"`
+	CreatedAt   time.Time       `json:"created_at"`
+	LastRun     time.Time       `json:"last_run,omitzero"`
+	LastStatus  string          `json:"last_status,omitempty"`
+	Runs        int             `json:"runs"`
+}
+
+// scheduleList is the structured result of list_scheduled
+type scheduleList struct {
+	Jobs []scheduledJob `json:"jobs"`
+}
+
+// webhookEvent is the JSON body POSTed to a webhook and the data of an MCP
+// notification
+type webhookEvent struct {
+	ID           string          `json:"id"`
+	Description  string          `json:"description,omitempty"`
+	ScheduledFor string          `json:"scheduled_for"`
+	FiredAt      string          `json:"fired_at"`
+	Cron         string          `json:"cron,omitempty"`
+	Payload      json.RawMessage `json:"payload,omitempty"`
+}
+
+// scheduler fires scheduled jobs. Jobs live in the state store so they
+// survive restarts with the file or Redis backend; with Redis every replica
+// polls and a claim counter makes sure each run fires once.
+type scheduler struct {
+	store     stateStore
+	mcpServer *server.MCPServer
+	client    *http.Client
+	secret    string
+	userAgent string
+	now       func() time.Time
+
+	stop     chan struct{}
+	done     chan struct{}
+	inflight sync.WaitGroup
+}
+
+func newScheduler(store stateStore, mcpServer *server.MCPServer, config *Config) *scheduler {
+	return &scheduler{
+		store:     store,
+		mcpServer: mcpServer,
+		client:    newWebhookClient(config.WebhookAllowPrivate),
+		secret:    config.WebhookSecret,
+		userAgent: config.ServerName + "/" + config.ServerVersion,
+		now:       config.now,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+}
+
+// start polls for due jobs until close
+func (s *scheduler) start() {
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(schedulerPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.poll(context.Background())
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+// close stops polling and waits for deliveries in flight
+func (s *scheduler) close() {
+	close(s.stop)
+	<-s.done
+	s.inflight.Wait()
+}
+
+// newWebhookClient returns the client for webhook deliveries. Unless private
+// targets are allowed, connections to loopback, private and link-local
+// addresses are refused at dial time, after DNS resolution, so a job cannot
+// reach internal services.
+func newWebhookClient(allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || isPrivateAddress(ip) {
+				return fmt.Errorf("webhook target %s is a private address", host)
+			}
+			return nil
+		}
+	}
+	return &http.Client{
+		Timeout:   webhookTimeout,
+		Transport: &http.Transport{DialContext: dialer.DialContext, TLSHandshakeTimeout: 5 * time.Second},
+		// A redirect could point at an address the job was not checked for
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+}
+
+func isPrivateAddress(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsUnspecified() || ip.IsMulticast()
+}
+
+// validateWebhookURL checks a webhook URL when the job is created; literal
+// private addresses are refused early, resolved ones when dialing
+func validateWebhookURL(raw string, allowPrivate bool) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return fmt.Errorf("invalid webhook url %q (expected an http or https URL)", raw)
+	}
+	if allowPrivate {
+		return nil
+	}
+	if ip := net.ParseIP(u.Hostname()); u.Hostname() == "localhost" || ip != nil && isPrivateAddress(ip) {
+		return fmt.Errorf("webhook url %q points at a private address", raw)
+	}
+	return nil
+}
+
+// newJobID returns a random job identifier
+func newJobID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func (s *scheduler) save(ctx context.Context, job *scheduledJob) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return s.store.set(ctx, scheduleKeyPrefix+job.ID, data, 0)
+}
+
+func (s *scheduler) load(ctx context.Context, id string) (*scheduledJob, error) {
+	data, ok, err := s.store.get(ctx, scheduleKeyPrefix+id)
+	if err != nil || !ok {
+		return nil, err
+	}
+	var job scheduledJob
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, fmt.Errorf("invalid job %s: %w", id, err)
+	}
+	return &job, nil
+}
+
+// jobs returns the jobs owned by owner, or all jobs when all is set, by next run
+func (s *scheduler) jobs(ctx context.Context, owner string, all bool) ([]scheduledJob, error) {
+	keys, err := s.store.keys(ctx, scheduleKeyPrefix)
+	if err != nil {
+		return nil, err
+	}
+	jobs := []scheduledJob{}
+	for _, key := range keys {
+		job, err := s.load(ctx, key[len(scheduleKeyPrefix):])
+		if err != nil {
+			slog.WarnContext(ctx, "Skipping unreadable scheduled job", "key", key, "error", err)
+			continue
+		}
+		if job != nil && (all || job.Owner == owner) {
+			jobs = append(jobs, *job)
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].NextRun.Before(jobs[j].NextRun) })
+	return jobs, nil
+}
+
+// nextRun computes when a recurring job runs next after t, in its timezone
+func (job *scheduledJob) nextRun(t time.Time) (time.Time, error) {
+	loc, err := loadLocationCached(job.Timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+	schedule, err := parseCron(job.Cron)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return schedule.next(t.In(loc)), nil
+}
+
+// poll fires the jobs that are due
+func (s *scheduler) poll(ctx context.Context) {
+	keys, err := s.store.keys(ctx, scheduleKeyPrefix)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to list scheduled jobs", "error", err)
+		return
+	}
+	now := s.now()
+	for _, key := range keys {
+		job, err := s.load(ctx, key[len(scheduleKeyPrefix):])
+		if err != nil {
+			slog.WarnContext(ctx, "Skipping unreadable scheduled job", "key", key, "error", err)
+			continue
+		}
+		if job == nil || job.NextRun.After(now) {
+			continue
+		}
+		if job.SessionID != "" {
+			s.fireNotification(ctx, job, now)
+			continue
+		}
+
+		// Only the replica that claims this run fires it
+		claim := fmt.Sprintf("schedule-claim:%s:%d", job.ID, job.NextRun.Unix())
+		if n, err := s.store.incrBy(ctx, claim, 1, scheduleClaimTTL); err != nil || n != 1 {
+			continue
+		}
+		scheduledFor := job.NextRun
+		// The next run is recorded before delivery, so a crash cannot fire twice
+		if err := s.advance(ctx, job, now); err != nil {
+			slog.WarnContext(ctx, "Failed to update scheduled job", "id", job.ID, "error", err)
+			continue
+		}
+		s.inflight.Add(1)
+		go func() {
+			defer s.inflight.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+			defer cancel()
+			status := "ok"
+			if err := s.sendWebhook(ctx, job, scheduledFor); err != nil {
+				status = "error: " + err.Error()
+				slog.Warn("Webhook delivery failed", "id", job.ID, "url", redactString(job.URL), "error", err)
+			} else {
+				slog.Info("Webhook delivered", "id", job.ID, "scheduled_for", scheduledFor)
+			}
+			s.recordResult(context.Background(), job.ID, s.now(), status)
+		}()
+	}
+}
+
+// advance moves a recurring job to its next run after now, skipping runs
+// missed while no server was running, and deletes a one-time job
+func (s *scheduler) advance(ctx context.Context, job *scheduledJob, now time.Time) error {
+	if job.Cron == "" {
+		return s.store.delete(ctx, scheduleKeyPrefix+job.ID)
+	}
+	next, err := job.nextRun(now)
+	if err != nil || next.IsZero() {
+		return s.store.delete(ctx, scheduleKeyPrefix+job.ID)
+	}
+	job.NextRun = next
+	return s.save(ctx, job)
+}
+
+// recordResult stores the outcome of a delivery on a job that still exists
+func (s *scheduler) recordResult(ctx context.Context, id string, at time.Time, status string) {
+	job, err := s.load(ctx, id)
+	if err != nil || job == nil {
+		return
+	}
+	job.LastRun, job.LastStatus = at, status
+	job.Runs++
+	if err := s.save(ctx, job); err != nil {
+		slog.WarnContext(ctx, "Failed to record scheduled job result", "id", id, "error", err)
+	}
+}
+
+// event builds the delivery body for one run
+func (s *scheduler) event(job *scheduledJob, scheduledFor time.Time) webhookEvent {
+	loc, err := loadLocationCached(job.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	return webhookEvent{
+		ID:           job.ID,
+		Description:  job.Description,
+		ScheduledFor: scheduledFor.In(loc).Format(time.RFC3339),
+		FiredAt:      s.now().In(loc).Format(time.RFC3339),
+		Cron:         job.Cron,
+		Payload:      job.Payload,
+	}
+}
+
+// sendWebhook POSTs the event as JSON. With TIME_WEBHOOK_SECRET set the
+// request carries X-TimeMCP-Timestamp and an X-TimeMCP-Signature of
+// "sha256=" + hex(HMAC-SHA256(secret, timestamp + "." + body)).
+func (s *scheduler) sendWebhook(ctx context.Context, job *scheduledJob, scheduledFor time.Time) error {
+	body, err := json.Marshal(s.event(job, scheduledFor))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, job.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", s.userAgent)
+	req.Header.Set("X-TimeMCP-Job", job.ID)
+	if s.secret != "" {
+		timestamp := strconv.FormatInt(s.now().Unix(), 10)
+		mac := hmac.New(sha256.New, []byte(s.secret))
+		mac.Write([]byte(timestamp + "."))
+		mac.Write(body)
+		req.Header.Set("X-TimeMCP-Timestamp", timestamp)
+		req.Header.Set("X-TimeMCP-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// fireNotification sends a logging notification to the job's session. Only
+// the replica holding the session can deliver it; a job whose session has
+// not been seen for notificationGracePeriod is dropped, since sessions do
+// not come back.
+func (s *scheduler) fireNotification(ctx context.Context, job *scheduledJob, now time.Time) {
+	event := s.event(job, job.NextRun)
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	var params map[string]any
+	json.Unmarshal(data, &params)
+
+	err = s.mcpServer.SendNotificationToSpecificClient(job.SessionID, "notifications/message", map[string]any{
+		"level":  mcp.LoggingLevelInfo,
+		"logger": "scheduler",
+		"data":   params,
+	})
+	switch {
+	case errors.Is(err, server.ErrSessionNotFound) || errors.Is(err, server.ErrSessionNotInitialized):
+		if now.Sub(job.NextRun) >= notificationGracePeriod {
+			slog.InfoContext(ctx, "Dropping scheduled notification for a closed session", "id", job.ID)
+			s.store.delete(ctx, scheduleKeyPrefix+job.ID)
+		}
+		return
+	case err != nil:
+		job.LastStatus = "error: " + err.Error()
+	default:
+		job.LastStatus = "ok"
+	}
+	job.LastRun = now
+	job.Runs++
+	if err := s.advance(ctx, job, now); err != nil {
+		slog.WarnContext(ctx, "Failed to update scheduled job", "id", job.ID, "error", err)
+	}
+}
+
+// addSchedulerTools registers schedule_webhook, list_scheduled and
+// cancel_scheduled
+func addSchedulerTools(mcpServer *server.MCPServer, sched *scheduler, config *Config) {
+	mcpServer.AddTool(
+		mcp.NewTool("schedule_webhook",
+			mcp.WithDescription("Schedule an HTTP POST to a webhook URL, or an MCP notification to this session, at an instant or on a cron schedule. Returns the job with its ID and next run."),
+			mcp.WithString("url",
+				mcp.Description("Webhook URL to POST the event to. Omit and set notify to deliver to this session instead."),
+			),
+			mcp.WithBoolean("notify",
+				mcp.Description("Deliver as an MCP logging notification to the current session instead of a webhook; the job ends with the session."),
+			),
+			mcp.WithString("at",
+				mcp.Description("When to fire once: RFC 3339 (2025-06-01T09:00:00+02:00) or 'YYYY-MM-DD HH:MM' in timezone."),
+			),
+			mcp.WithString("cron",
+				mcp.Description("Cron expression for a recurring job, evaluated in timezone, e.g. '0 9 * * MON-FRI'."),
+			),
+			mcp.WithString("timezone",
+				mcp.Description("Timezone for cron and for 'at' without an offset. Defaults to system timezone if not provided."),
+			),
+			mcp.WithString("description",
+				mcp.Description("Free text included in every delivery."),
+			),
+			mcp.WithObject("payload",
+				mcp.Description("JSON object included in every delivery."),
+			),
+			mcp.WithOutputSchema[scheduledJob](),
+			mcp.WithTitleAnnotation("Schedule Webhook"),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(false),
+			mcp.WithOpenWorldHintAnnotation(true),
+		),
+		handleScheduleWebhook(sched, config),
+	)
+	mcpServer.AddTool(
+		mcp.NewTool("list_scheduled",
+			mcp.WithDescription("List your scheduled webhooks and notifications with their next run and last delivery status. Admins see every user's jobs."),
+			mcp.WithOutputSchema[scheduleList](),
+			mcp.WithTitleAnnotation("List Scheduled Jobs"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		handleListScheduled(sched, config),
+	)
+	mcpServer.AddTool(
+		mcp.NewTool("cancel_scheduled",
+			mcp.WithDescription("Cancel a scheduled webhook or notification by ID."),
+			mcp.WithString("id",
+				mcp.Description("Job ID returned by schedule_webhook."),
+				mcp.Required(),
+			),
+			mcp.WithTitleAnnotation("Cancel Scheduled Job"),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		handleCancelScheduled(sched, config),
+	)
+}
+
+// handleScheduleWebhook returns a handler for the schedule_webhook tool
+func handleScheduleWebhook(sched *scheduler, config *Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		owner, _, _ := getUserInfo(ctx)
+		now := sched.now()
+		job := &scheduledJob{
+			ID:          newJobID(),
+			Owner:       owner,
+			Description: request.GetString("description", ""),
+			URL:         request.GetString("url", ""),
+			Cron:        request.GetString("cron", ""),
+			CreatedAt:   now.UTC(),
+		}
+
+		switch notify := request.GetBool("notify", false); {
+		case notify && job.URL != "":
+			return toolError(errorInvalidArgument, "Provide either 'url' or 'notify', not both"), nil
+		case notify:
+			session := server.ClientSessionFromContext(ctx)
+			if session == nil || session.SessionID() == "" {
+				return toolError(errorSessionRequired, "Notifications need an MCP session; provide a webhook 'url' instead"), nil
+			}
+			job.SessionID = session.SessionID()
+		case job.URL == "":
+			return toolError(errorInvalidArgument, "Provide a webhook 'url' or set 'notify'"), nil
+		default:
+			if err := validateWebhookURL(job.URL, config.WebhookAllowPrivate); err != nil {
+				return toolError(errorInvalidArgument, err.Error()), nil
+			}
+		}
+
+		timezoneStr := request.GetString("timezone", "")
+		loc, err := loadTimezone(ctx, timezoneStr, config)
+		if err != nil {
+			return invalidTimezoneError("Invalid timezone", timezoneStr), nil
+		}
+		job.Timezone = loc.String()
+
+		atStr := request.GetString("at", "")
+		switch {
+		case atStr != "" && job.Cron != "":
+			return toolError(errorInvalidArgument, "Provide either 'at' or 'cron', not both"), nil
+		case atStr != "":
+			at, err := time.Parse(time.RFC3339, atStr)
+			if err != nil {
+				if at, err = time.ParseInLocation("2006-01-02 15:04", atStr, loc); err != nil {
+					return toolError(errorParse, fmt.Sprintf("Invalid at: %s. Please provide RFC 3339 or 'YYYY-MM-DD HH:MM'.", atStr)), nil
+				}
+			}
+			if !at.After(now) {
+				return toolError(errorInvalidArgument, fmt.Sprintf("The time %s is in the past", at.Format(time.RFC3339))), nil
+			}
+			job.At, job.NextRun = at, at
+		case job.Cron != "":
+			next, err := job.nextRun(now)
+			if err != nil {
+				return toolError(errorParse, cronErrorMessage(job.Cron, err)), nil
+			}
+			if next.IsZero() {
+				return toolError(errorInvalidArgument, fmt.Sprintf("Cron expression %q never runs", job.Cron)), nil
+			}
+			job.NextRun = next
+		default:
+			return toolError(errorInvalidArgument, "Provide 'at' for a one-time job or 'cron' for a recurring one"), nil
+		}
+
+		if payload, ok := request.GetArguments()["payload"]; ok && payload != nil {
+			data, err := json.Marshal(payload)
+			if err != nil || len(data) > maxSchedulePayload {
+				return toolError(errorInvalidArgument, fmt.Sprintf("Payload must be JSON of at most %d bytes", maxSchedulePayload)), nil
+			}
+			job.Payload = data
+		}
+
+		existing, err := sched.jobs(ctx, owner, false)
+		if err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to read scheduled jobs: %v", err)), nil
+		}
+		if len(existing) >= config.SchedulerMaxJobs {
+			return toolError(errorLimitExceeded, fmt.Sprintf("You have reached the limit of %d scheduled jobs; cancel one first", config.SchedulerMaxJobs)), nil
+		}
+		if err := sched.save(ctx, job); err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to save scheduled job: %v", err)), nil
+		}
+		slog.InfoContext(ctx, "Scheduled job created", "id", job.ID, "next_run", job.NextRun, "cron", job.Cron, "notify", job.SessionID != "")
+
+		data, err := json.Marshal(job)
+		if err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to encode job: %v", err)), nil
+		}
+		return mcp.NewToolResultStructured(*job, string(data)), nil
+	}
+}
+
+// handleListScheduled returns a handler for the list_scheduled tool
+func handleListScheduled(sched *scheduler, config *Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		owner, _, _ := getUserInfo(ctx)
+		jobs, err := sched.jobs(ctx, owner, requireAdmin(ctx, config) == nil)
+		if err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to read scheduled jobs: %v", err)), nil
+		}
+		list := scheduleList{Jobs: jobs}
+		data, err := json.Marshal(list)
+		if err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to encode jobs: %v", err)), nil
+		}
+		return mcp.NewToolResultStructured(list, string(data)), nil
+	}
+}
+
+// handleCancelScheduled returns a handler for the cancel_scheduled tool
+func handleCancelScheduled(sched *scheduler, config *Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		id, err := request.RequireString("id")
+		if err != nil {
+			return toolError(errorInvalidArgument, err.Error()), nil
+		}
+		job, err := sched.load(ctx, id)
+		if err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to read scheduled job: %v", err)), nil
+		}
+		owner, _, _ := getUserInfo(ctx)
+		// Other users' jobs are reported as missing rather than forbidden
+		if job == nil || job.Owner != owner && requireAdmin(ctx, config) != nil {
+			return toolError(errorNotFound, fmt.Sprintf("No scheduled job with ID %s", id)), nil
+		}
+		if err := sched.store.delete(ctx, scheduleKeyPrefix+id); err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to cancel scheduled job: %v", err)), nil
+		}
+		slog.InfoContext(ctx, "Scheduled job cancelled", "id", id)
+		return mcp.NewToolResultText(fmt.Sprintf("Scheduled job %s cancelled", id)), nil
+	}
+}
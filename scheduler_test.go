@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func newTestScheduler(config *Config) *scheduler {
+	mcpServer := server.NewMCPServer("TimeMCP", "test", server.WithToolCapabilities(true))
+	return newScheduler(newMemoryStore(), mcpServer, config)
+}
+
+// httpUserContext returns the context of an authenticated HTTP call by userID
+func httpUserContext(userID string) context.Context {
+	ctx := context.WithValue(context.Background(), httpMethodKey, http.MethodPost)
+	ctx = context.WithValue(ctx, authenticatedKey, true)
+	return context.WithValue(ctx, userIDKey, userID)
+}
+
+func TestScheduler_Webhook(t *testing.T) {
+	type delivery struct {
+		event     webhookEvent
+		signature string
+		timestamp string
+		body      []byte
+	}
+	deliveries := make(chan delivery, 2)
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var event webhookEvent
+		json.Unmarshal(body, &event)
+		deliveries <- delivery{event, r.Header.Get("X-TimeMCP-Signature"), r.Header.Get("X-TimeMCP-Timestamp"), body}
+	}))
+	defer target.Close()
+
+	config := &Config{SchedulerMaxJobs: 1, WebhookSecret: "webhook-secret", WebhookAllowPrivate: true}
+	sched := newTestScheduler(config)
+	now := time.Date(2025, 6, 2, 8, 59, 0, 0, time.UTC)
+	sched.now = func() time.Time { return now }
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{
+		"url":      target.URL,
+		"cron":     "0 9 * * MON-FRI",
+		"timezone": "Europe/Warsaw",
+		"payload":  map[string]any{"room": "A"},
+	}
+	ctx := httpUserContext("alice")
+	result, err := handleScheduleWebhook(sched, config)(ctx, req)
+	if err != nil || result.IsError {
+		t.Fatalf("Failed to schedule webhook: %v %s", err, toolResultText(result))
+	}
+	job := result.StructuredContent.(scheduledJob)
+	// 09:00 in Warsaw (UTC+2) has already passed at 08:59 UTC on Monday
+	if expected := time.Date(2025, 6, 3, 7, 0, 0, 0, time.UTC); !job.NextRun.Equal(expected) {
+		t.Errorf("Expected next run %v but got %v", expected, job.NextRun)
+	}
+	if result, _ := handleScheduleWebhook(sched, config)(ctx, req); !result.IsError || !strings.Contains(toolResultText(result), "limit of 1") {
+		t.Errorf("Expected the job limit to be enforced but got %s", toolResultText(result))
+	}
+
+	now = job.NextRun.Add(time.Second)
+	sched.poll(context.Background())
+	sched.inflight.Wait()
+	select {
+	case got := <-deliveries:
+		if got.event.ID != job.ID || got.event.ScheduledFor != "2025-06-03T09:00:00+02:00" || string(got.event.Payload) != `{"room":"A"}` {
+			t.Errorf("Expected the job's event but got %+v", got.event)
+		}
+		mac := hmac.New(sha256.New, []byte("webhook-secret"))
+		mac.Write([]byte(got.timestamp + "."))
+		mac.Write(got.body)
+		if expected := "sha256=" + hex.EncodeToString(mac.Sum(nil)); got.signature != expected {
+			t.Errorf("Expected signature %s but got %s", expected, got.signature)
+		}
+	default:
+		t.Fatalf("Failed to deliver the webhook")
+	}
+
+	// A second poll of the same instant must not fire again
+	sched.poll(context.Background())
+	sched.inflight.Wait()
+	if len(deliveries) != 0 {
+		t.Errorf("Expected a single delivery per run")
+	}
+	stored, _ := sched.load(context.Background(), job.ID)
+	if stored == nil || stored.Runs != 1 || stored.LastStatus != "ok" ||
+		!stored.NextRun.Equal(time.Date(2025, 6, 4, 7, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the job to advance to Wednesday after one run but got %+v", stored)
+	}
+}
+
+func TestScheduler_Ownership(t *testing.T) {
+	config := &Config{SchedulerMaxJobs: 10, AuthEnabled: true, AuthAdminRole: "admin"}
+	sched := newTestScheduler(config)
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"url": "https://example.com/hook", "at": "2099-01-01T00:00:00Z"}
+	result, err := handleScheduleWebhook(sched, config)(httpUserContext("alice"), req)
+	if err != nil || result.IsError {
+		t.Fatalf("Failed to schedule webhook: %v %s", err, toolResultText(result))
+	}
+	id := result.StructuredContent.(scheduledJob).ID
+
+	result, _ = handleListScheduled(sched, config)(httpUserContext("bob"), mcp.CallToolRequest{})
+	if jobs := result.StructuredContent.(scheduleList).Jobs; len(jobs) != 0 {
+		t.Errorf("Expected bob to see no jobs but got %d", len(jobs))
+	}
+	cancel := mcp.CallToolRequest{}
+	cancel.Params.Arguments = map[string]any{"id": id}
+	if result, _ := handleCancelScheduled(sched, config)(httpUserContext("bob"), cancel); !result.IsError {
+		t.Errorf("Expected bob not to cancel alice's job")
+	}
+	admin := context.WithValue(httpUserContext("root"), userRoleKey, "admin")
+	result, _ = handleListScheduled(sched, config)(admin, mcp.CallToolRequest{})
+	if jobs := result.StructuredContent.(scheduleList).Jobs; len(jobs) != 1 || jobs[0].Owner != "alice" {
+		t.Errorf("Expected the admin to see alice's job but got %+v", jobs)
+	}
+	if result, _ := handleCancelScheduled(sched, config)(httpUserContext("alice"), cancel); result.IsError {
+		t.Errorf("Expected alice to cancel her job but got %s", toolResultText(result))
+	}
+	if job, _ := sched.load(context.Background(), id); job != nil {
+		t.Errorf("Expected the job to be deleted")
+	}
+}
+
+func TestHandleScheduleWebhook_Errors(t *testing.T) {
+	tests := []struct {
+		args     map[string]any
+		expected string
+	}{
+		{map[string]any{"url": "http://127.0.0.1:8080/hook", "at": "2099-01-01T00:00:00Z"}, "private address"},
+		{map[string]any{"url": "http://localhost/hook", "at": "2099-01-01T00:00:00Z"}, "private address"},
+		{map[string]any{"url": "ftp://example.com/hook", "at": "2099-01-01T00:00:00Z"}, "invalid webhook url"},
+		{map[string]any{"url": "https://example.com/hook", "at": "2000-01-01T00:00:00Z"}, "in the past"},
+		{map[string]any{"url": "https://example.com/hook", "at": "2099-01-01T00:00:00Z", "cron": "* * * * *"}, "not both"},
+		{map[string]any{"url": "https://example.com/hook", "cron": "0 9 31 2 *"}, "never runs"},
+		{map[string]any{"url": "https://example.com/hook"}, "Provide 'at'"},
+		{map[string]any{"notify": true, "cron": "* * * * *"}, "need an MCP session"},
+	}
+	config := &Config{SchedulerMaxJobs: 10}
+	sched := newTestScheduler(config)
+	for _, tt := range tests {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = tt.args
+		result, err := handleScheduleWebhook(sched, config)(context.Background(), req)
+		if err != nil || !result.IsError || !strings.Contains(toolResultText(result), tt.expected) {
+			t.Errorf("Expected an error containing %q but got %s (%v)", tt.expected, toolResultText(result), err)
+		}
+	}
+}
+
+func TestWebhookClient_RefusesPrivateAddresses(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer target.Close()
+	if _, err := newWebhookClient(false).Get(target.URL); err == nil || !strings.Contains(err.Error(), "private address") {
+		t.Errorf("Expected the dial to a loopback address to be refused but got %v", err)
+	}
+	if resp, err := newWebhookClient(true).Get(target.URL); err != nil {
+		t.Errorf("Expected private addresses to be allowed but got %v", err)
+	} else {
+		resp.Body.Close()
+	}
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// securityHeadersHandler adds standard security headers to every response.
+// HSTS is only sent over HTTPS (directly or via a trusted proxy), since
+// browsers ignore it on plain HTTP.
+func securityHeadersHandler(next http.Handler, config *Config) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := w.Header()
+		h.Set("X-Content-Type-Options", "nosniff")
+		h.Set("Referrer-Policy", "no-referrer")
+		if config.HTTPFrameOptions != "" {
+			h.Set("X-Frame-Options", config.HTTPFrameOptions)
+		}
+		if config.HTTPContentSecurityPolicy != "" {
+			h.Set("Content-Security-Policy", config.HTTPContentSecurityPolicy)
+		}
+		if config.HTTPHSTSMaxAge > 0 && requestScheme(r) == "https" {
+			h.Set("Strict-Transport-Security", "max-age="+strconv.Itoa(config.HTTPHSTSMaxAge)+"; includeSubDomains")
+		}
+		next.ServeHTTP(w, r)
+	})
+}
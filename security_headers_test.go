@@ -0,0 +1,76 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSecurityHeadersHandler(t *testing.T) {
+	trusted, err := parseCIDRList("10.0.0.0/8")
+	if err != nil {
+		t.Fatalf("Failed to parse trusted proxies: %v", err)
+	}
+	config := &Config{
+		HTTPFrameOptions:          "DENY",
+		HTTPContentSecurityPolicy: "default-src 'none'",
+		HTTPHSTSMaxAge:            31536000,
+	}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := realIPHandler(securityHeadersHandler(next, config), trusted)
+
+	testCases := []struct {
+		name       string
+		url        string
+		remoteAddr string
+		proto      string
+		hsts       bool
+	}{
+		{name: "Plain HTTP", url: "http://example.com/mcp", remoteAddr: "203.0.113.5:1234"},
+		{name: "TLS", url: "https://example.com/mcp", remoteAddr: "203.0.113.5:1234", hsts: true},
+		{name: "HTTPS Via Trusted Proxy", url: "http://example.com/mcp", remoteAddr: "10.1.2.3:1234", proto: "https", hsts: true},
+		{name: "Forwarded Proto From Untrusted Peer", url: "http://example.com/mcp", remoteAddr: "203.0.113.5:1234", proto: "https"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tc.url, nil)
+			req.RemoteAddr = tc.remoteAddr
+			if tc.proto != "" {
+				req.Header.Set("X-Forwarded-Proto", tc.proto)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			expected := map[string]string{
+				"X-Content-Type-Options":  "nosniff",
+				"Referrer-Policy":         "no-referrer",
+				"X-Frame-Options":         "DENY",
+				"Content-Security-Policy": "default-src 'none'",
+			}
+			for header, value := range expected {
+				if got := rec.Header().Get(header); got != value {
+					t.Errorf("Expected %s %q but got %q", header, value, got)
+				}
+			}
+			hsts := rec.Header().Get("Strict-Transport-Security")
+			if tc.hsts && hsts != "max-age=31536000; includeSubDomains" {
+				t.Errorf("Expected HSTS over HTTPS but got %q", hsts)
+			}
+			if !tc.hsts && hsts != "" {
+				t.Errorf("Expected no HSTS over plain HTTP but got %q", hsts)
+			}
+		})
+	}
+
+	// Optional headers are omitted when not configured
+	rec := httptest.NewRecorder()
+	securityHeadersHandler(next, &Config{}).ServeHTTP(rec, httptest.NewRequest("GET", "https://example.com/mcp", nil))
+	for _, header := range []string{"X-Frame-Options", "Content-Security-Policy", "Strict-Transport-Security"} {
+		if got := rec.Header().Get(header); got != "" {
+			t.Errorf("Expected no %s without configuration but got %q", header, got)
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// sessionsPathPrefix is where the admin session endpoints live
+	sessionsPathPrefix = "/sessions"
+	// maxSessionIdle drops sessions from the listing that were never
+	// deleted by their client; mcp-go keeps no record of them either
+	maxSessionIdle = 24 * time.Hour
+	// terminatedSessionRetention is how long a terminated ID stays rejected
+	terminatedSessionRetention = 24 * time.Hour
+)
+
+// sessionInfo describes one stateful HTTP session
+type sessionInfo struct {
+	ID           string    `json:"id"`
+	UserID       string    `json:"user_id,omitempty"`
+	Username     string    `json:"username,omitempty"`
+	Client       string    `json:"client,omitempty" jsonschema:"Client name and version from initialize"`
+	CreatedAt    time.Time `json:"created_at" jsonschema:"When the session was created or, after a restart, first seen"`
+	LastActivity time.Time `json:"last_activity"`
+}
+
+// sessionList is the structured result of list_sessions
+type sessionList struct {
+	Sessions []sessionInfo `json:"sessions"`
+}
+
+// sessionRegistry tracks stateful HTTP sessions for the admin surface. It is
+// also the transport's SessionIdManager, so a terminated session's ID is
+// rejected with 404 and the client has to initialize again. Like mcp-go's
+// default manager it accepts well-formed IDs it has not seen, so sessions
+// survive restarts and move between replicas.
+type sessionRegistry struct {
+	server.StatelessGeneratingSessionIdManager
+
+	mu         sync.Mutex
+	sessions   map[string]*sessionInfo
+	terminated map[string]time.Time
+	now        func() time.Time
+	// lifetime ends sessions this long after they were created; 0 means
+	// sessions only end when idle or terminated
+	lifetime time.Duration
+}
+
+func newSessionRegistry(lifetime time.Duration) *sessionRegistry {
+	return &sessionRegistry{
+		sessions:   make(map[string]*sessionInfo),
+		terminated: make(map[string]time.Time),
+		now:        time.Now,
+		lifetime:   lifetime,
+	}
+}
+
+// install records session identity and activity with server hooks, which
+// run with the session and the HTTP context in ctx
+func (s *sessionRegistry) install(hooks *server.Hooks) {
+	hooks.AddBeforeAny(func(ctx context.Context, _ any, _ mcp.MCPMethod, _ any) {
+		if session := server.ClientSessionFromContext(ctx); session != nil {
+			s.touch(ctx, session.SessionID(), "")
+		}
+	})
+	hooks.AddAfterInitialize(func(ctx context.Context, _ any, request *mcp.InitializeRequest, _ *mcp.InitializeResult) {
+		if session := server.ClientSessionFromContext(ctx); session != nil && request != nil {
+			info := request.Params.ClientInfo
+			s.touch(ctx, session.SessionID(), strings.TrimSpace(info.Name+" "+info.Version))
+		}
+	})
+}
+
+// Generate creates a session ID for an initialize request
+func (s *sessionRegistry) Generate() string {
+	id := s.StatelessGeneratingSessionIdManager.Generate()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.prune(now)
+	s.sessions[id] = &sessionInfo{ID: id, CreatedAt: now, LastActivity: now}
+	return id
+}
+
+// Validate rejects terminated sessions, sessions past their lifetime and
+// malformed IDs
+func (s *sessionRegistry) Validate(sessionID string) (bool, error) {
+	s.mu.Lock()
+	_, terminated := s.terminated[sessionID]
+	if info := s.sessions[sessionID]; info != nil && !terminated {
+		if now := s.now(); s.lifetime > 0 && now.Sub(info.CreatedAt) > s.lifetime {
+			delete(s.sessions, sessionID)
+			s.terminated[sessionID] = now
+			terminated = true
+			slog.Info("Session reached its maximum lifetime", "session_id", sessionID, "lifetime", s.lifetime.String())
+		}
+	}
+	s.mu.Unlock()
+	if terminated {
+		return true, nil
+	}
+	return s.StatelessGeneratingSessionIdManager.Validate(sessionID)
+}
+
+// Terminate ends a session on client DELETE, idle expiry or admin request
+func (s *sessionRegistry) Terminate(sessionID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, sessionID)
+	s.terminated[sessionID] = s.now()
+	return false, nil
+}
+
+// touch records activity on a session and, once known, its user and client
+func (s *sessionRegistry) touch(ctx context.Context, sessionID, client string) {
+	if sessionID == "" {
+		return
+	}
+	userID, username, _ := getUserInfo(ctx)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, terminated := s.terminated[sessionID]; terminated {
+		return
+	}
+	now := s.now()
+	info := s.sessions[sessionID]
+	if info == nil {
+		info = &sessionInfo{ID: sessionID, CreatedAt: now}
+		s.sessions[sessionID] = info
+	}
+	info.LastActivity = now
+	if userID != "" {
+		info.UserID, info.Username = userID, username
+	}
+	if client != "" {
+		info.Client = client
+	}
+}
+
+// prune drops idle sessions and expired terminations; s.mu must be held
+func (s *sessionRegistry) prune(now time.Time) {
+	for id, info := range s.sessions {
+		if now.Sub(info.LastActivity) > maxSessionIdle {
+			delete(s.sessions, id)
+		}
+	}
+	for id, at := range s.terminated {
+		if now.Sub(at) > terminatedSessionRetention {
+			delete(s.terminated, id)
+		}
+	}
+}
+
+// get returns a tracked session; a nil registry tracks none
+func (s *sessionRegistry) get(sessionID string) (sessionInfo, bool) {
+	if s == nil {
+		return sessionInfo{}, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	info, ok := s.sessions[sessionID]
+	if !ok {
+		return sessionInfo{}, false
+	}
+	return *info, true
+}
+
+// list returns active sessions, most recently active first
+func (s *sessionRegistry) list() []sessionInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prune(s.now())
+	sessions := make([]sessionInfo, 0, len(s.sessions))
+	for _, info := range s.sessions {
+		sessions = append(sessions, *info)
+	}
+	slices.SortFunc(sessions, func(a, b sessionInfo) int { return b.LastActivity.Compare(a.LastActivity) })
+	return sessions
+}
+
+// terminate ends a session on behalf of an admin and stops notifications to it
+func (s *sessionRegistry) terminate(ctx context.Context, mcpServer *server.MCPServer, sessionID string) error {
+	s.mu.Lock()
+	_, ok := s.sessions[sessionID]
+	s.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("session %q not found", sessionID)
+	}
+	s.Terminate(sessionID)
+	mcpServer.UnregisterSession(ctx, sessionID)
+	_, username, _ := getUserInfo(ctx)
+	slog.InfoContext(ctx, "Session terminated by admin", "session_id", sessionID, "username", username)
+	return nil
+}
+
+// addSessionTools registers list_sessions and terminate_session
+func addSessionTools(mcpServer *server.MCPServer, sessions *sessionRegistry, config *Config) {
+	mcpServer.AddTool(
+		mcp.NewTool("list_sessions",
+			mcp.WithDescription("List active stateful HTTP sessions with their user, creation time and last activity. Requires the admin role."),
+			mcp.WithOutputSchema[sessionList](),
+			mcp.WithTitleAnnotation("List Sessions"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		handleListSessions(sessions, config),
+	)
+	mcpServer.AddTool(
+		mcp.NewTool("terminate_session",
+			mcp.WithDescription("Terminate a stateful HTTP session; its client must initialize a new one. Requires the admin role."),
+			mcp.WithString("id",
+				mcp.Description("Session ID from list_sessions."),
+				mcp.Required(),
+			),
+			mcp.WithTitleAnnotation("Terminate Session"),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		handleTerminateSession(mcpServer, sessions, config),
+	)
+}
+
+// handleListSessions returns a handler for the list_sessions tool
+func handleListSessions(sessions *sessionRegistry, config *Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if denied := requireAdmin(ctx, config); denied != nil {
+			return denied, nil
+		}
+		list := sessionList{Sessions: sessions.list()}
+		data, err := json.Marshal(list)
+		if err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to encode sessions: %v", err)), nil
+		}
+		return mcp.NewToolResultStructured(list, string(data)), nil
+	}
+}
+
+// handleTerminateSession returns a handler for the terminate_session tool
+func handleTerminateSession(mcpServer *server.MCPServer, sessions *sessionRegistry, config *Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if denied := requireAdmin(ctx, config); denied != nil {
+			return denied, nil
+		}
+		id, err := request.RequireString("id")
+		if err != nil {
+			return toolError(errorInvalidArgument, err.Error()), nil
+		}
+		if err := sessions.terminate(ctx, mcpServer, id); err != nil {
+			return toolError(errorNotFound, err.Error()), nil
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("Session %s terminated", id)), nil
+	}
+}
+
+// addSessionsEndpoint registers GET /sessions and DELETE /sessions/{id}. With
+// auth enabled the caller must present credentials for the admin role; like
+// the admin tools, terminating sessions is refused without auth.
+func addSessionsEndpoint(mux *http.ServeMux, config *Config, rt *httpRuntime) {
+	if rt == nil || rt.sessions == nil || rt.mcpServer == nil {
+		return
+	}
+	mux.HandleFunc(sessionsPathPrefix, func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := authenticateAdminEndpoint(w, r, config, rt); ok && allowRESTMethod(w, r, http.MethodGet) {
+			writeJSONResponse(w, r, http.StatusOK, sessionList{Sessions: rt.sessions.list()})
+		}
+	})
+	mux.HandleFunc(sessionsPathPrefix+"/{id}", func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := authenticateAdminEndpoint(w, r, config, rt)
+		if !ok || !allowRESTMethod(w, r, http.MethodDelete) {
+			return
+		}
+		if claims == nil {
+			writeJSONResponse(w, r, http.StatusForbidden, restError{Error: "Forbidden: terminating sessions requires authentication"})
+			return
+		}
+		ctx := context.WithValue(r.Context(), usernameKey, claims.Username)
+		if err := rt.sessions.terminate(ctx, rt.mcpServer, r.PathValue("id")); err != nil {
+			writeJSONResponse(w, r, http.StatusNotFound, restError{Error: err.Error()})
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestSessionRegistry(t *testing.T) {
+	sessions := newSessionRegistry(0)
+	hooks := &server.Hooks{}
+	sessions.install(hooks)
+	mcpServer := server.NewMCPServer("TimeMCP", "test", server.WithToolCapabilities(true), server.WithHooks(hooks))
+	handler := server.NewStreamableHTTPServer(mcpServer, server.WithSessionIdManager(sessions))
+
+	post := func(sessionID, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/mcp", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if sessionID != "" {
+			req.Header.Set("Mcp-Session-Id", sessionID)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+	rec := post("", `{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": {"protocolVersion": "2025-03-26", "capabilities": {}, "clientInfo": {"name": "probe", "version": "1.0"}}}`)
+	id := rec.Header().Get("Mcp-Session-Id")
+	if rec.Code != http.StatusOK || id == "" {
+		t.Fatalf("Failed to initialize a session: %d %s", rec.Code, rec.Body.String())
+	}
+
+	list := sessions.list()
+	if len(list) != 1 || list[0].ID != id || list[0].Client != "probe 1.0" || list[0].CreatedAt.IsZero() {
+		t.Fatalf("Expected the new session to be listed but got %+v", list)
+	}
+	if rec := post(id, `{"jsonrpc": "2.0", "id": 2, "method": "ping"}`); rec.Code != http.StatusOK {
+		t.Errorf("Expected the session to be usable but got %d", rec.Code)
+	}
+
+	if err := sessions.terminate(context.Background(), mcpServer, id); err != nil {
+		t.Fatalf("Failed to terminate session: %v", err)
+	}
+	if rec := post(id, `{"jsonrpc": "2.0", "id": 3, "method": "ping"}`); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for a terminated session but got %d", rec.Code)
+	}
+	if list := sessions.list(); len(list) != 0 {
+		t.Errorf("Expected no sessions after termination but got %+v", list)
+	}
+	if err := sessions.terminate(context.Background(), mcpServer, id); err == nil {
+		t.Errorf("Expected an error terminating an unknown session")
+	}
+}
+
+func TestSessionsEndpoint(t *testing.T) {
+	config := &Config{
+		AuthEnabled:   true,
+		AuthSecretKey: "test-secret-key-that-is-long-enough-for-hs256",
+		AuthIssuer:    "test-issuer",
+		AuthAudience:  "test-audience",
+		AuthAdminRole: "admin",
+	}
+	contextFunc := &reloadableContextFunc{}
+	fn, auth, err := createHTTPMiddleware(config)
+	if err != nil {
+		t.Fatalf("Failed to create HTTP middleware: %v", err)
+	}
+	contextFunc.store(fn, auth)
+	sessions := newSessionRegistry(0)
+	id := sessions.Generate()
+	rt := &httpRuntime{mcpServer: server.NewMCPServer("TimeMCP", "test"), contextFunc: contextFunc, sessions: sessions}
+	handler := createCustomHTTPHandler(http.NotFoundHandler(), config, rt)
+
+	admin, _ := auth.GenerateToken("1", "ada", "admin", 1)
+	user, _ := auth.GenerateToken("2", "uma", "user", 1)
+
+	rec := serveREST(handler, "GET", "/sessions", "", admin)
+	var list sessionList
+	json.NewDecoder(rec.Body).Decode(&list)
+	if rec.Code != http.StatusOK || len(list.Sessions) != 1 || list.Sessions[0].ID != id {
+		t.Errorf("Expected the session list but got %d %+v", rec.Code, list)
+	}
+	if rec := serveREST(handler, "GET", "/sessions", "", user); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 for a non-admin but got %d", rec.Code)
+	}
+	if rec := serveREST(handler, "POST", "/sessions", "", admin); rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405 but got %d", rec.Code)
+	}
+	if rec := serveREST(handler, "DELETE", "/sessions/"+id, "", admin); rec.Code != http.StatusNoContent {
+		t.Errorf("Expected status 204 but got %d %s", rec.Code, rec.Body.String())
+	}
+	if rec := serveREST(handler, "DELETE", "/sessions/"+id, "", admin); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for a terminated session but got %d", rec.Code)
+	}
+
+	open := createCustomHTTPHandler(http.NotFoundHandler(), &Config{}, rt)
+	if rec := serveREST(open, "DELETE", "/sessions/"+sessions.Generate(), "", ""); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected termination to require auth but got %d", rec.Code)
+	}
+}
+
+func TestSessionLifetime(t *testing.T) {
+	sessions := newSessionRegistry(time.Hour)
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	sessions.now = func() time.Time { return now }
+	id := sessions.Generate()
+
+	now = now.Add(59 * time.Minute)
+	if terminated, err := sessions.Validate(id); terminated || err != nil {
+		t.Errorf("Expected the session to be valid within its lifetime but got %v %v", terminated, err)
+	}
+	now = now.Add(2 * time.Minute)
+	if terminated, _ := sessions.Validate(id); !terminated {
+		t.Error("Expected the session to end after its lifetime")
+	}
+	if list := sessions.list(); len(list) != 0 {
+		t.Errorf("Expected the expired session to be dropped but got %+v", list)
+	}
+}
@@ -0,0 +1,343 @@
+package main
+
+import (
+	"archive/zip"
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// maxSolarOccurrences caps the occurrences of one solar_schedule call
+	maxSolarOccurrences = 366
+	// defaultSolarOccurrences is how many occurrences are listed by default
+	defaultSolarOccurrences = 10
+)
+
+// solarEvents are the sun events solar_schedule accepts, with the altitude
+// of the sun's center at each in degrees; sunrise and sunset allow for
+// refraction and the sun's radius, dawn and dusk are civil twilight
+var solarEvents = map[string]float64{
+	"sunrise":    -0.833,
+	"sunset":     -0.833,
+	"dawn":       -6,
+	"dusk":       -6,
+	"solar_noon": 0,
+}
+
+// julianDay converts an instant to a Julian day number
+func julianDay(t time.Time) float64 {
+	return float64(t.Unix())/86400 + 2440587.5
+}
+
+// fromJulianDay converts a Julian day number to an instant, rounded to the second
+func fromJulianDay(jd float64) time.Time {
+	return time.Unix(int64(math.Round((jd-2440587.5)*86400)), 0).UTC()
+}
+
+// solarEventTime returns when event happens on the calendar date of date at
+// latitude and longitude (east positive), using the sunrise equation, which
+// is accurate to about a minute. It reports false when the sun does not
+// reach the event's altitude that day, as in polar day or night.
+func solarEventTime(date time.Time, latitude, longitude float64, event string) (time.Time, bool) {
+	rad := math.Pi / 180
+	noonUTC := time.Date(date.Year(), date.Month(), date.Day(), 12, 0, 0, 0, time.UTC)
+	// Days since J2000 to the local mean solar noon of the date
+	n := julianDay(noonUTC) - 2451545.0 + 0.0008
+	meanNoon := n - longitude/360
+	anomaly := math.Mod(357.5291+0.98560028*meanNoon, 360)
+	m := anomaly * rad
+	center := 1.9148*math.Sin(m) + 0.0200*math.Sin(2*m) + 0.0003*math.Sin(3*m)
+	eclipticLongitude := math.Mod(anomaly+center+180+102.9372, 360) * rad
+	transit := 2451545.0 + meanNoon + 0.0053*math.Sin(m) - 0.0069*math.Sin(2*eclipticLongitude)
+	if event == "solar_noon" {
+		return fromJulianDay(transit), true
+	}
+
+	declination := math.Asin(math.Sin(eclipticLongitude) * math.Sin(23.4397*rad))
+	phi := latitude * rad
+	cosHourAngle := (math.Sin(solarEvents[event]*rad) - math.Sin(phi)*math.Sin(declination)) / (math.Cos(phi) * math.Cos(declination))
+	if cosHourAngle < -1 || cosHourAngle > 1 {
+		return time.Time{}, false
+	}
+	hourAngle := math.Acos(cosHourAngle) / rad
+	if event == "sunrise" || event == "dawn" {
+		return fromJulianDay(transit - hourAngle/360), true
+	}
+	return fromJulianDay(transit + hourAngle/360), true
+}
+
+// coordinates are a latitude and longitude in degrees, east positive
+type coordinates struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// zoneCoordinates maps zone names to the coordinates of their principal city
+// from zone1970.tab or zone.tab. Like timezoneNames it is empty when the tz
+// database is only embedded in the binary.
+var zoneCoordinates = sync.OnceValue(func() map[string]coordinates {
+	sources := zoneinfoDirs
+	if dir := os.Getenv("ZONEINFO"); dir != "" {
+		sources = append([]string{dir}, sources...)
+	}
+	for _, source := range sources {
+		for _, table := range []string{"zone1970.tab", "zone.tab"} {
+			if coords := readZoneTable(source, table); len(coords) > 0 {
+				return coords
+			}
+		}
+	}
+	return nil
+})
+
+// readZoneTable reads a zone table from a zoneinfo directory or zip
+func readZoneTable(source, table string) map[string]coordinates {
+	var r io.ReadCloser
+	if strings.HasSuffix(source, ".zip") {
+		z, err := zip.OpenReader(source)
+		if err != nil {
+			return nil
+		}
+		defer z.Close()
+		if r, err = z.Open(table); err != nil {
+			return nil
+		}
+	} else {
+		f, err := os.Open(filepath.Join(source, table))
+		if err != nil {
+			return nil
+		}
+		r = f
+	}
+	defer r.Close()
+
+	coords := make(map[string]coordinates)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) < 3 {
+			continue
+		}
+		if c, ok := parseISO6709(fields[1]); ok {
+			coords[fields[2]] = c
+		}
+	}
+	return coords
+}
+
+// parseISO6709 parses the ±DDMM±DDDMM or ±DDMMSS±DDDMMSS coordinates of
+// the zone tables
+func parseISO6709(s string) (coordinates, bool) {
+	split := strings.IndexAny(s[min(1, len(s)):], "+-") + 1
+	if split <= 0 {
+		return coordinates{}, false
+	}
+	lat, ok1 := parseISO6709Part(s[:split], 2)
+	lon, ok2 := parseISO6709Part(s[split:], 3)
+	return coordinates{lat, lon}, ok1 && ok2
+}
+
+func parseISO6709Part(s string, degreeDigits int) (float64, bool) {
+	if len(s) != 1+degreeDigits+2 && len(s) != 1+degreeDigits+4 {
+		return 0, false
+	}
+	value := 0.0
+	for i, scale := 1, 1.0; i < len(s); scale *= 60 {
+		width := 2
+		if i == 1 {
+			width = degreeDigits
+		}
+		n, err := strconv.Atoi(s[i : i+width])
+		if err != nil {
+			return 0, false
+		}
+		value += float64(n) / scale
+		i += width
+	}
+	if s[0] == '-' {
+		value = -value
+	}
+	return value, s[0] == '+' || s[0] == '-'
+}
+
+// solarSchedule is the structured result of solar_schedule
+type solarSchedule struct {
+	Event       string            `json:"event"`
+	Offset      string            `json:"offset"`
+	Timezone    string            `json:"timezone"`
+	Latitude    float64           `json:"latitude"`
+	Longitude   float64           `json:"longitude"`
+	Occurrences []solarOccurrence `json:"occurrences"`
+	NoEvent     []string          `json:"no_event,omitempty" jsonschema:"Dates without the sun event, e.g. in polar day or night"`
+}
+
+type solarOccurrence struct {
+	Date  string `json:"date"`
+	Time  string `json:"time" jsonschema:"The scheduled time in RFC 3339: the sun event plus the offset"`
+	Event string `json:"event_time" jsonschema:"The sun event itself in RFC 3339"`
+}
+
+func addSolarScheduleTool(mcpServer *server.MCPServer, config *Config) {
+	mcpServer.AddTool(
+		mcp.NewTool("solar_schedule",
+			mcp.WithDescription("Compute times defined relative to a sun event over a recurrence, e.g. 30 minutes before sunset every Friday in Lisbon: event=sunset, offset=-30m, rrule=FREQ=WEEKLY;BYDAY=FR, timezone=Europe/Lisbon. Coordinates default to the principal city of the timezone."),
+			mcp.WithString("event",
+				mcp.Description("Sun event: sunrise, sunset, dawn or dusk (civil twilight), or solar_noon."),
+				mcp.Enum("sunrise", "sunset", "dawn", "dusk", "solar_noon"),
+				mcp.Required(),
+			),
+			mcp.WithString("offset",
+				mcp.Description("Offset from the event as a Go duration, e.g. -30m or 1h15m. Defaults to 0."),
+			),
+			mcp.WithString("timezone",
+				mcp.Description("IANA timezone of the place. Defaults to the session or server default."),
+			),
+			mcp.WithNumber("latitude",
+				mcp.Description("Latitude in degrees, north positive. Defaults to the timezone's principal city."),
+			),
+			mcp.WithNumber("longitude",
+				mcp.Description("Longitude in degrees, east positive. Defaults to the timezone's principal city."),
+			),
+			mcp.WithString("rrule",
+				mcp.Description("RFC 5545 recurrence of the dates, e.g. FREQ=WEEKLY;BYDAY=FR. Defaults to FREQ=DAILY."),
+			),
+			mcp.WithString("from",
+				mcp.Description("First date in YYYY-MM-DD format. Defaults to today in the timezone."),
+			),
+			mcp.WithString("until",
+				mcp.Description("Last date in YYYY-MM-DD format, inclusive."),
+			),
+			mcp.WithNumber("limit",
+				mcp.Description(fmt.Sprintf("Maximum number of occurrences. Defaults to %d; at most %d.", defaultSolarOccurrences, maxSolarOccurrences)),
+			),
+			mcp.WithOutputSchema[solarSchedule](),
+			mcp.WithTitleAnnotation("Solar Schedule"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		handleSolarSchedule(config),
+	)
+}
+
+// handleSolarSchedule returns a handler for the solar_schedule tool
+func handleSolarSchedule(config *Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		event, err := request.RequireString("event")
+		if err != nil {
+			return toolError(errorInvalidArgument, err.Error()), nil
+		}
+		if _, ok := solarEvents[event]; !ok {
+			return toolError(errorInvalidArgument, fmt.Sprintf("Invalid event: %s. Please use sunrise, sunset, dawn, dusk or solar_noon.", event)), nil
+		}
+		offsetStr := request.GetString("offset", "0s")
+		offset, err := time.ParseDuration(offsetStr)
+		if err != nil || offset.Abs() > 24*time.Hour {
+			return toolError(errorParse, fmt.Sprintf("Invalid offset: %s. Please provide a duration of at most 24h, e.g. -30m.", offsetStr)), nil
+		}
+		timezoneStr := request.GetString("timezone", "")
+		loc, err := loadTimezone(ctx, timezoneStr, config)
+		if err != nil {
+			return invalidTimezoneError("Invalid timezone", timezoneStr), nil
+		}
+
+		args := request.GetArguments()
+		_, hasLat := args["latitude"]
+		_, hasLon := args["longitude"]
+		var place coordinates
+		switch {
+		case hasLat != hasLon:
+			return toolError(errorInvalidArgument, "Provide both 'latitude' and 'longitude', or neither"), nil
+		case hasLat:
+			place = coordinates{request.GetFloat("latitude", 0), request.GetFloat("longitude", 0)}
+			if math.Abs(place.Latitude) > 90 || math.Abs(place.Longitude) > 180 {
+				return toolError(errorInvalidArgument, "Latitude must be within ±90 and longitude within ±180 degrees"), nil
+			}
+		default:
+			var ok bool
+			if place, ok = zoneCoordinates()[loc.String()]; !ok {
+				return toolError(errorInvalidArgument, fmt.Sprintf("No coordinates are known for %s; provide 'latitude' and 'longitude'", loc)), nil
+			}
+		}
+
+		ruleStr := request.GetString("rrule", "FREQ=DAILY")
+		rule, err := parseRecurrenceRule(strings.TrimPrefix(ruleStr, "RRULE:"), loc)
+		if err != nil {
+			return toolError(errorParse, fmt.Sprintf("Invalid rrule: %v", err)), nil
+		}
+		from := config.now().In(loc)
+		if fromStr := request.GetString("from", ""); fromStr != "" {
+			if from, err = time.ParseInLocation("2006-01-02", fromStr, loc); err != nil {
+				return toolError(errorParse, fmt.Sprintf("Invalid from: %s. Please use YYYY-MM-DD format.", fromStr)), nil
+			}
+		}
+		from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc)
+		var until time.Time
+		if untilStr := request.GetString("until", ""); untilStr != "" {
+			if until, err = time.ParseInLocation("2006-01-02", untilStr, loc); err != nil {
+				return toolError(errorParse, fmt.Sprintf("Invalid until: %s. Please use YYYY-MM-DD format.", untilStr)), nil
+			}
+			if until.Before(from) {
+				return toolError(errorInvalidArgument, "'until' must not be before 'from'"), nil
+			}
+		}
+		limit := request.GetInt("limit", defaultSolarOccurrences)
+		if limit < 1 || limit > maxSolarOccurrences {
+			return toolError(errorInvalidArgument, fmt.Sprintf("Invalid limit: %d. Please provide 1 to %d.", limit, maxSolarOccurrences)), nil
+		}
+
+		schedule := solarSchedule{
+			Event:       event,
+			Offset:      offset.String(),
+			Timezone:    loc.String(),
+			Latitude:    place.Latitude,
+			Longitude:   place.Longitude,
+			Occurrences: []solarOccurrence{},
+		}
+		// Dates without the event count toward the limit so polar locations
+		// cannot make the expansion run on
+		seen := 0
+		rule.expand(from, func(date time.Time) bool {
+			if !until.IsZero() && date.After(until) {
+				return false
+			}
+			seen++
+			day := date.Format("2006-01-02")
+			at, ok := solarEventTime(date, place.Latitude, place.Longitude, event)
+			if !ok {
+				schedule.NoEvent = append(schedule.NoEvent, day)
+			} else {
+				schedule.Occurrences = append(schedule.Occurrences, solarOccurrence{
+					Date:  day,
+					Time:  at.Add(offset).In(loc).Format(time.RFC3339),
+					Event: at.In(loc).Format(time.RFC3339),
+				})
+			}
+			return seen < limit
+		})
+
+		data, err := json.Marshal(schedule)
+		if err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to encode result: %v", err)), nil
+		}
+		return mcp.NewToolResultStructured(schedule, string(data)), nil
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestSolarEventTime(t *testing.T) {
+	lisbon, _ := time.LoadLocation("Europe/Lisbon")
+	date := time.Date(2025, 6, 21, 0, 0, 0, 0, lisbon)
+	tests := []struct {
+		event string
+		want  string
+	}{
+		// Published times for Lisbon (38.72N, 9.14W) on the June solstice
+		{"sunrise", "06:12"},
+		{"solar_noon", "13:38"},
+		{"sunset", "21:05"},
+	}
+	for _, tt := range tests {
+		at, ok := solarEventTime(date, 38.72, -9.14, tt.event)
+		want, _ := time.ParseInLocation("2006-01-02 15:04", "2025-06-21 "+tt.want, lisbon)
+		if !ok || at.Sub(want).Abs() > 2*time.Minute {
+			t.Errorf("Expected %s at about %s but got %v (%v)", tt.event, tt.want, at.In(lisbon), ok)
+		}
+	}
+
+	// Tromsø has no sunrise at the winter solstice
+	if at, ok := solarEventTime(time.Date(2025, 12, 21, 0, 0, 0, 0, time.UTC), 69.65, 18.96, "sunrise"); ok {
+		t.Errorf("Expected no sunrise in Tromsø on 2025-12-21 but got %v", at)
+	}
+}
+
+func TestParseISO6709(t *testing.T) {
+	tests := []struct {
+		in       string
+		lat, lon float64
+		ok       bool
+	}{
+		{"+3843-00908", 38 + 43.0/60, -(9 + 8.0/60), true},
+		{"-334752+1511017", -(33 + 47.0/60 + 52.0/3600), 151 + 10.0/60 + 17.0/3600, true},
+		{"+3843", 0, 0, false},
+		{"3843-00908", 0, 0, false},
+		{"", 0, 0, false},
+	}
+	for _, tt := range tests {
+		c, ok := parseISO6709(tt.in)
+		if ok != tt.ok || ok && (c.Latitude != tt.lat || c.Longitude != tt.lon) {
+			t.Errorf("Expected %q to parse as (%v, %v, %v) but got (%v, %v, %v)", tt.in, tt.lat, tt.lon, tt.ok, c.Latitude, c.Longitude, ok)
+		}
+	}
+}
+
+func TestSolarSchedule(t *testing.T) {
+	handler := handleSolarSchedule(&Config{DefaultTimezone: "UTC"})
+	call := func(args map[string]any) *mcp.CallToolResult {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := handler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("Failed to call handler: %v", err)
+		}
+		return result
+	}
+
+	// 30 minutes before sunset every Friday in Lisbon
+	result := call(map[string]any{
+		"event": "sunset", "offset": "-30m", "rrule": "FREQ=WEEKLY;BYDAY=FR",
+		"timezone": "Europe/Lisbon", "latitude": 38.72, "longitude": -9.14,
+		"from": "2025-06-16", "until": "2025-07-06",
+	})
+	if result.IsError {
+		t.Fatalf("Failed to compute schedule: %s", toolResultText(result))
+	}
+	schedule := result.StructuredContent.(solarSchedule)
+	if len(schedule.Occurrences) != 3 {
+		t.Fatalf("Expected 3 Fridays but got %+v", schedule.Occurrences)
+	}
+	for _, o := range schedule.Occurrences {
+		at, _ := time.Parse(time.RFC3339, o.Time)
+		event, _ := time.Parse(time.RFC3339, o.Event)
+		if at.Weekday() != time.Friday || event.Sub(at) != 30*time.Minute || at.Hour() != 20 {
+			t.Errorf("Expected a Friday about 20:35, 30 minutes before sunset, but got %+v", o)
+		}
+	}
+
+	// Polar night dates are reported without occurrences
+	result = call(map[string]any{"event": "sunrise", "timezone": "Europe/Oslo", "latitude": 69.65, "longitude": 18.96, "from": "2025-12-20", "limit": 3})
+	if schedule := result.StructuredContent.(solarSchedule); len(schedule.Occurrences) != 0 || len(schedule.NoEvent) != 3 {
+		t.Errorf("Expected 3 dates without sunrise in Tromsø but got %+v", schedule)
+	}
+
+	// Coordinates default to the zone's principal city when the tz tables are installed
+	if _, ok := zoneCoordinates()["Europe/Lisbon"]; ok {
+		result = call(map[string]any{"event": "sunrise", "timezone": "Europe/Lisbon", "from": "2025-06-21", "limit": 1})
+		if schedule := result.StructuredContent.(solarSchedule); len(schedule.Occurrences) != 1 || schedule.Latitude < 38 || schedule.Latitude > 39 {
+			t.Errorf("Expected Lisbon's coordinates from the zone table but got %+v", schedule)
+		}
+	}
+
+	tests := []struct {
+		args map[string]any
+		code toolErrorCode
+	}{
+		{map[string]any{}, errorInvalidArgument},
+		{map[string]any{"event": "moonrise"}, errorInvalidArgument},
+		{map[string]any{"event": "sunset", "offset": "soon"}, errorParse},
+		{map[string]any{"event": "sunset", "latitude": 10.0}, errorInvalidArgument},
+		{map[string]any{"event": "sunset", "latitude": 91.0, "longitude": 0.0}, errorInvalidArgument},
+		{map[string]any{"event": "sunset", "latitude": 0.0, "longitude": 0.0, "rrule": "FREQ=HOURLY"}, errorParse},
+		{map[string]any{"event": "sunset", "latitude": 0.0, "longitude": 0.0, "limit": 1000}, errorInvalidArgument},
+		{map[string]any{"event": "sunset", "latitude": 0.0, "longitude": 0.0, "from": "2025-06-02", "until": "2025-06-01"}, errorInvalidArgument},
+		{map[string]any{"event": "sunset", "timezone": "Mars/Olympus"}, errorInvalidTimezone},
+	}
+	for _, tt := range tests {
+		if code := toolErrorCodeOf(call(tt.args)); code != tt.code {
+			t.Errorf("Expected %v to fail with %s but got %q", tt.args, tt.code, code)
+		}
+	}
+}
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// statsToolName is the tool reporting server statistics
+	statsToolName = "get_server_stats"
+	// maxTrackedTimezones bounds memory used by arbitrary timezone arguments;
+	// further distinct values are counted as "other"
+	maxTrackedTimezones = 500
+	// topTimezones is how many timezones statistics report
+	topTimezones = 10
+)
+
+// toolStats aggregates calls of one tool
+type toolStats struct {
+	Calls     int64   `json:"calls"`
+	Errors    int64   `json:"errors"`
+	TotalMS   float64 `json:"total_ms"`
+	ErrorRate float64 `json:"error_rate"`
+	AverageMS float64 `json:"average_ms"`
+}
+
+// serverStats aggregates tool usage in memory. When a file is configured the
+// counters are restored from it at startup and flushed to it periodically.
+type serverStats struct {
+	mu        sync.Mutex
+	started   time.Time
+	tools     map[string]*toolStats
+	timezones map[string]int64
+	file      string
+	now       func() time.Time
+
+	// Clock steps seen by this process; not persisted
+	clockSteps    int64
+	lastClockStep time.Time
+	lastStepSize  time.Duration
+}
+
+// statsSnapshot is the reported and persisted form of serverStats
+type statsSnapshot struct {
+	StartedAt     string                `json:"started_at"`
+	UptimeSeconds int64                 `json:"uptime_seconds"`
+	TotalCalls    int64                 `json:"total_calls"`
+	TotalErrors   int64                 `json:"total_errors"`
+	Tools         map[string]*toolStats `json:"tools"`
+	TopTimezones  []timezoneCount       `json:"top_timezones"`
+	Timezones     map[string]int64      `json:"timezones,omitempty"`
+
+	ClockSteps           int64   `json:"clock_steps"`
+	LastClockStep        string  `json:"last_clock_step,omitempty"`
+	LastClockStepSeconds float64 `json:"last_clock_step_seconds,omitempty"`
+}
+
+type timezoneCount struct {
+	Timezone string `json:"timezone"`
+	Count    int64  `json:"count"`
+}
+
+func newServerStats(file string) *serverStats {
+	s := &serverStats{
+		started:   time.Now(),
+		tools:     make(map[string]*toolStats),
+		timezones: make(map[string]int64),
+		file:      file,
+		now:       time.Now,
+	}
+	if file != "" {
+		if err := s.restore(); err != nil {
+			slog.Warn("Failed to restore statistics", "file", file, "error", err)
+		}
+	}
+	return s
+}
+
+// record counts one tool call and the timezones it referenced
+func (s *serverStats) record(tool string, duration time.Duration, failed bool, timezones []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tools[tool]
+	if !ok {
+		t = &toolStats{}
+		s.tools[tool] = t
+	}
+	t.Calls++
+	t.TotalMS += float64(duration.Microseconds()) / 1000
+	if failed {
+		t.Errors++
+	}
+
+	for _, tz := range timezones {
+		if _, tracked := s.timezones[tz]; !tracked && len(s.timezones) >= maxTrackedTimezones {
+			tz = "other"
+		}
+		s.timezones[tz]++
+	}
+}
+
+// recordClockStep counts a system clock step of size detected at time at
+func (s *serverStats) recordClockStep(at time.Time, size time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clockSteps++
+	s.lastClockStep = at
+	s.lastStepSize = size
+}
+
+// snapshot returns the current statistics; withAllTimezones includes the full
+// timezone histogram used for persistence
+func (s *serverStats) snapshot(withAllTimezones bool) statsSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snap := statsSnapshot{
+		StartedAt:     s.started.UTC().Format(time.RFC3339),
+		UptimeSeconds: int64(s.now().Sub(s.started).Seconds()),
+		Tools:         make(map[string]*toolStats, len(s.tools)),
+		ClockSteps:    s.clockSteps,
+	}
+	if s.clockSteps > 0 {
+		snap.LastClockStep = s.lastClockStep.UTC().Format(time.RFC3339)
+		snap.LastClockStepSeconds = s.lastStepSize.Seconds()
+	}
+	for name, t := range s.tools {
+		copied := *t
+		if copied.Calls > 0 {
+			copied.ErrorRate = float64(copied.Errors) / float64(copied.Calls)
+			copied.AverageMS = copied.TotalMS / float64(copied.Calls)
+		}
+		snap.Tools[name] = &copied
+		snap.TotalCalls += copied.Calls
+		snap.TotalErrors += copied.Errors
+	}
+
+	for tz, count := range s.timezones {
+		snap.TopTimezones = append(snap.TopTimezones, timezoneCount{Timezone: tz, Count: count})
+	}
+	slices.SortFunc(snap.TopTimezones, func(a, b timezoneCount) int {
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Timezone, b.Timezone)
+	})
+	if len(snap.TopTimezones) > topTimezones {
+		snap.TopTimezones = snap.TopTimezones[:topTimezones]
+	}
+	if withAllTimezones {
+		snap.Timezones = make(map[string]int64, len(s.timezones))
+		for tz, count := range s.timezones {
+			snap.Timezones[tz] = count
+		}
+	}
+	return snap
+}
+
+// restore loads counters flushed by a previous run; uptime starts over
+func (s *serverStats) restore() error {
+	data, err := os.ReadFile(s.file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var snap statsSnapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for name, t := range snap.Tools {
+		s.tools[name] = &toolStats{Calls: t.Calls, Errors: t.Errors, TotalMS: t.TotalMS}
+	}
+	for tz, count := range snap.Timezones {
+		s.timezones[tz] = count
+	}
+	return nil
+}
+
+// flush writes the statistics to the configured file atomically
+func (s *serverStats) flush() error {
+	if s.file == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(s.snapshot(true), "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.file), ".stats-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.file)
+}
+
+// startFlushing flushes the statistics every interval in the background
+func (s *serverStats) startFlushing(interval time.Duration) {
+	if s.file == "" || interval <= 0 {
+		return
+	}
+	go func() {
+		for range time.Tick(interval) {
+			if err := s.flush(); err != nil {
+				slog.Warn("Failed to flush statistics", "file", s.file, "error", err)
+			}
+		}
+	}()
+}
+
+// requestedTimezones returns the non-empty string arguments naming a timezone
+func requestedTimezones(req mcp.CallToolRequest) []string {
+	var timezones []string
+	for key, value := range req.GetArguments() {
+		if !strings.HasSuffix(key, "timezone") {
+			continue
+		}
+		if tz, ok := value.(string); ok && tz != "" {
+			timezones = append(timezones, tz)
+		}
+	}
+	return timezones
+}
+
+// statsMiddleware records every tool call, including calls rejected by later
+// middleware, which count as errors
+func statsMiddleware(stats *serverStats) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			start := time.Now()
+			result, err := next(ctx, req)
+			failed := err != nil || (result != nil && result.IsError)
+			stats.record(req.Params.Name, time.Since(start), failed, requestedTimezones(req))
+			return result, err
+		}
+	}
+}
+
+// addStatsTool registers the get_server_stats tool
+func addStatsTool(mcpServer *server.MCPServer, config *Config, stats *serverStats) {
+	mcpServer.AddTool(
+		mcp.NewTool(statsToolName,
+			mcp.WithDescription("Show server uptime, per-tool call counts and error rates, and the most requested timezones. Requires the admin role."),
+			mcp.WithTitleAnnotation("Get Server Stats"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(false),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if denied := requireAdmin(ctx, config); denied != nil {
+				return denied, nil
+			}
+			data, err := json.MarshalIndent(stats.snapshot(false), "", "  ")
+			if err != nil {
+				return toolError(errorInternal, fmt.Sprintf("Failed to encode statistics: %v", err)), nil
+			}
+			return mcp.NewToolResultText(string(data)), nil
+		},
+	)
+}
+
+// addStatsEndpoint registers GET /stats. With auth enabled the caller must
+// present credentials for the admin role.
+func addStatsEndpoint(mux *http.ServeMux, config *Config, rt *httpRuntime) {
+	if rt == nil || rt.stats == nil {
+		return
+	}
+	mux.HandleFunc("/stats", func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := authenticateAdminEndpoint(w, r, config, rt); !ok {
+			return
+		}
+		writeJSONResponse(w, r, http.StatusOK, rt.stats.snapshot(false))
+	})
+}
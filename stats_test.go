@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestServerStats(t *testing.T) {
+	stats := newServerStats("")
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	stats.started = start
+	stats.now = func() time.Time { return start.Add(90 * time.Second) }
+
+	stats.record("get_current_time", 2*time.Millisecond, false, []string{"Europe/Warsaw"})
+	stats.record("get_current_time", 4*time.Millisecond, true, []string{"Europe/Warsaw"})
+	stats.record("convert_time", time.Millisecond, false, []string{"UTC", "Europe/Warsaw"})
+
+	snap := stats.snapshot(false)
+	if snap.UptimeSeconds != 90 || snap.TotalCalls != 3 || snap.TotalErrors != 1 {
+		t.Errorf("Expected 90s uptime, 3 calls and 1 error but got %ds, %d, %d", snap.UptimeSeconds, snap.TotalCalls, snap.TotalErrors)
+	}
+	tool := snap.Tools["get_current_time"]
+	if tool.Calls != 2 || tool.ErrorRate != 0.5 || tool.AverageMS != 3 {
+		t.Errorf("Expected 2 calls, 0.5 error rate and 3ms average but got %+v", tool)
+	}
+	if len(snap.TopTimezones) != 2 || snap.TopTimezones[0] != (timezoneCount{"Europe/Warsaw", 3}) {
+		t.Errorf("Expected Europe/Warsaw to lead the top timezones but got %v", snap.TopTimezones)
+	}
+	if snap.Timezones != nil {
+		t.Error("Expected the full timezone histogram to be omitted from reports")
+	}
+}
+
+func TestServerStats_BoundsTimezones(t *testing.T) {
+	stats := newServerStats("")
+	for i := 0; i < maxTrackedTimezones+5; i++ {
+		stats.record("get_current_time", 0, true, []string{fmt.Sprintf("Invalid/Zone%d", i)})
+	}
+	if len(stats.timezones) != maxTrackedTimezones+1 || stats.timezones["other"] != 5 {
+		t.Errorf("Expected %d tracked timezones plus 5 counted as other, but got %d and %d",
+			maxTrackedTimezones, len(stats.timezones), stats.timezones["other"])
+	}
+}
+
+func TestServerStats_FlushAndRestore(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "stats.json")
+	stats := newServerStats(file)
+	stats.record("convert_time", time.Millisecond, false, []string{"Asia/Tokyo"})
+	if err := stats.flush(); err != nil {
+		t.Fatalf("Failed to flush statistics: %v", err)
+	}
+
+	restored := newServerStats(file)
+	restored.record("convert_time", time.Millisecond, true, []string{"Asia/Tokyo"})
+	snap := restored.snapshot(false)
+	if snap.Tools["convert_time"].Calls != 2 || snap.Tools["convert_time"].Errors != 1 {
+		t.Errorf("Expected restored counts to accumulate, but got %+v", snap.Tools["convert_time"])
+	}
+	if snap.TopTimezones[0] != (timezoneCount{"Asia/Tokyo", 2}) {
+		t.Errorf("Expected restored timezone counts to accumulate, but got %v", snap.TopTimezones)
+	}
+}
+
+func TestStatsMiddleware(t *testing.T) {
+	stats := newServerStats("")
+	handler := statsMiddleware(stats)(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultError("Invalid target timezone"), nil
+	})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "convert_time"
+	req.Params.Arguments = map[string]any{"source_timezone": "UTC", "target_timezone": "Mars/Base", "time": "10:00"}
+	handler(context.Background(), req)
+
+	snap := stats.snapshot(true)
+	if snap.Tools["convert_time"].Errors != 1 {
+		t.Errorf("Expected an error result to be counted, but got %+v", snap.Tools["convert_time"])
+	}
+	if len(snap.Timezones) != 2 || snap.Timezones["Mars/Base"] != 1 {
+		t.Errorf("Expected both timezone arguments to be counted, but got %v", snap.Timezones)
+	}
+}
+
+func TestStatsEndpoint_RequiresAdmin(t *testing.T) {
+	config := &Config{
+		AuthEnabled:   true,
+		AuthSecretKey: "test-secret-key-that-is-long-enough-for-hs256",
+		AuthIssuer:    "test-issuer",
+		AuthAudience:  "test-audience",
+		AuthAdminRole: "admin",
+	}
+	contextFunc := &reloadableContextFunc{}
+	fn, auth, err := createHTTPMiddleware(config)
+	if err != nil {
+		t.Fatalf("Failed to create HTTP middleware: %v", err)
+	}
+	contextFunc.store(fn, auth)
+	mux := http.NewServeMux()
+	addStatsEndpoint(mux, config, &httpRuntime{contextFunc: contextFunc, stats: newServerStats("")})
+
+	adminToken, _ := auth.GenerateToken("1", "root", "admin", 1)
+	userToken, _ := auth.GenerateToken("2", "alice", "user", 1)
+	tests := []struct {
+		name     string
+		token    string
+		expected int
+	}{
+		{"anonymous", "", http.StatusUnauthorized},
+		{"non-admin", userToken, http.StatusForbidden},
+		{"admin", adminToken, http.StatusOK},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/stats", nil)
+		if tt.token != "" {
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		if rec.Code != tt.expected {
+			t.Errorf("%s: Expected status %d but got %d", tt.name, tt.expected, rec.Code)
+		}
+		if tt.expected == http.StatusOK {
+			var snap statsSnapshot
+			if err := json.NewDecoder(rec.Body).Decode(&snap); err != nil {
+				t.Errorf("Failed to decode statistics: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// stdioAuthKey marks stdio sessions whose calls are subject to auth checks
+const stdioAuthKey contextKey = "stdio_auth"
+
+// stdioAuthEnabled reports whether stdio calls must carry an identity
+func stdioAuthEnabled(config *Config) bool {
+	return config.AuthEnabled && (config.StdioAuthToken != "" || config.StdioAuthRequired)
+}
+
+// authEnforced reports whether the call arrived over a transport that is
+// subject to authentication: HTTP, or stdio with stdio auth enabled
+func authEnforced(ctx context.Context) bool {
+	if method, ok := ctx.Value(httpMethodKey).(string); ok && method != "" {
+		return true
+	}
+	enforced, _ := ctx.Value(stdioAuthKey).(bool)
+	return enforced
+}
+
+// stdioAuthOptions validates TIME_STDIO_AUTH_TOKEN at startup and attaches
+// the resulting identity to every stdio call. An invalid or missing token
+// stops the server instead of running without an identity.
+func stdioAuthOptions(config *Config) ([]server.StdioOption, error) {
+	if !stdioAuthEnabled(config) {
+		return nil, nil
+	}
+	if config.StdioAuthToken == "" {
+		return nil, fmt.Errorf("TIME_STDIO_AUTH_TOKEN is required when TIME_STDIO_AUTH_REQUIRED=true")
+	}
+
+	auth, err := NewAuthMiddlewareFromConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := auth.authenticateCredential(config.StdioAuthToken)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TIME_STDIO_AUTH_TOKEN: %w", err)
+	}
+	slog.Info("Stdio transport authenticated", "username", claims.Username, "user_id", claims.UserID, "role", claims.Role)
+
+	return []server.StdioOption{server.WithStdioContextFunc(stdioContextFunc(auth, config.StdioAuthToken))}, nil
+}
+
+// stdioContextFunc validates token again on every stdio call, so a token
+// that expires or is revoked while the process runs stops authenticating
+// calls, which then fail the tool auth check
+func stdioContextFunc(auth *AuthMiddleware, token string) func(ctx context.Context) context.Context {
+	return func(ctx context.Context) context.Context {
+		ctx = context.WithValue(ctx, stdioAuthKey, true)
+		claims, err := auth.authenticateCredential(token)
+		if err != nil {
+			slog.WarnContext(ctx, "TIME_STDIO_AUTH_TOKEN is no longer valid", "error", err)
+			ctx = context.WithValue(ctx, authErrorKey, authErrorCode(err))
+			return context.WithValue(ctx, authenticatedKey, false)
+		}
+		ctx = context.WithValue(ctx, authenticatedKey, true)
+		ctx = context.WithValue(ctx, userIDKey, claims.UserID)
+		ctx = context.WithValue(ctx, usernameKey, claims.Username)
+		ctx = context.WithValue(ctx, userRoleKey, claims.Role)
+		return withTenant(ctx, auth.tenants.resolve(claims))
+	}
+}
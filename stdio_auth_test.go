@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestStdioAuthOptions(t *testing.T) {
+	const secret = "stdio-secret-key-that-is-at-least-32-chars"
+	auth, err := NewAuthMiddleware(secret, true, "TimeMCP", "TimeMCP-user")
+	if err != nil {
+		t.Fatalf("Failed to create auth middleware: %v", err)
+	}
+	token, err := auth.GenerateToken("42", "desktop", "reader", 1)
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	base := Config{AuthEnabled: true, AuthSecretKey: secret, AuthIssuer: "TimeMCP", AuthAudience: "TimeMCP-user"}
+
+	disabled := base
+	if opts, err := stdioAuthOptions(&disabled); err != nil || opts != nil {
+		t.Errorf("Expected no options without a stdio token, but got %v, %v", opts, err)
+	}
+
+	required := base
+	required.StdioAuthRequired = true
+	if _, err := stdioAuthOptions(&required); err == nil {
+		t.Error("Expected an error when a stdio token is required but missing")
+	}
+
+	invalid := base
+	invalid.StdioAuthToken = "not-a-token"
+	if _, err := stdioAuthOptions(&invalid); err == nil {
+		t.Error("Expected an error for an invalid stdio token")
+	}
+
+	valid := base
+	valid.StdioAuthToken = token
+	valid.AuthRoles, _ = parseRolePolicy("reader:get_current_time")
+	opts, err := stdioAuthOptions(&valid)
+	if err != nil || len(opts) != 1 {
+		t.Fatalf("Expected one stdio option, but got %d, %v", len(opts), err)
+	}
+}
+
+func TestCheckAuth_Stdio(t *testing.T) {
+	config := &Config{AuthEnabled: true}
+	config.AuthRoles, _ = parseRolePolicy("reader:get_current_time")
+
+	if result := checkAuth(context.Background(), "convert_time", config); result != nil {
+		t.Error("Expected unauthenticated stdio calls to skip auth checks")
+	}
+
+	ctx := context.WithValue(context.Background(), stdioAuthKey, true)
+	ctx = context.WithValue(ctx, authenticatedKey, true)
+	ctx = context.WithValue(ctx, userIDKey, "42")
+	ctx = context.WithValue(ctx, usernameKey, "desktop")
+	ctx = context.WithValue(ctx, userRoleKey, "reader")
+
+	if result := checkAuth(ctx, "get_current_time", config); result != nil {
+		t.Error("Expected allowed tool to pass for authenticated stdio caller")
+	}
+	if result := checkAuth(ctx, "convert_time", config); result == nil {
+		t.Error("Expected role policy to apply to authenticated stdio calls")
+	}
+}
+
+func TestStdioContextFunc_Revalidates(t *testing.T) {
+	denylist, err := newTokenDenylist("", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &Config{
+		AuthEnabled:   true,
+		AuthSecretKey: "stdio-secret-key-that-is-at-least-32-chars",
+		AuthIssuer:    "TimeMCP",
+		AuthAudience:  "TimeMCP-user",
+		AuthDenylist:  denylist,
+	}
+	auth, err := NewAuthMiddlewareFromConfig(config)
+	if err != nil {
+		t.Fatalf("Failed to create auth middleware: %v", err)
+	}
+	token, claims, err := auth.generateToken(tokenOptions{UserID: "42", Username: "desktop", Role: "reader", Expiration: time.Hour})
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+	contextFunc := stdioContextFunc(auth, token)
+
+	if result := checkAuth(contextFunc(context.Background()), "get_current_time", config); result != nil {
+		t.Errorf("Expected a valid stdio token to authenticate calls, but got %s", toolResultText(result))
+	}
+
+	// Revoking the token takes effect on the next call of the running session
+	denylist.revoke(claims["jti"].(string))
+	result := checkAuth(contextFunc(context.Background()), "get_current_time", config)
+	if result == nil || toolErrorCodeOf(result) != errorUnauthorized {
+		t.Errorf("Expected calls with a revoked stdio token to be unauthorized, but got %+v", result)
+	}
+
+	expired, _, _ := auth.generateToken(tokenOptions{UserID: "42", Username: "desktop", Role: "reader", Expiration: -time.Minute})
+	ctx := stdioContextFunc(auth, expired)(context.Background())
+	if getAuthError(ctx) != authErrorExpiredToken {
+		t.Errorf("Expected an expired token error, but got %q", getAuthError(ctx))
+	}
+}
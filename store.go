@@ -0,0 +1,280 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// State store backends
+const (
+	storeMemory = "memory"
+	storeFile   = "file"
+	storeRedis  = "redis"
+)
+
+const (
+	// defaultStorePrefix namespaces keys so deployments can share a Redis
+	defaultStorePrefix = "timemcp:"
+	// fileStoreFlushInterval bounds how much state a crash of the file
+	// backend can lose
+	fileStoreFlushInterval = 5 * time.Second
+	// memoryStoreSweepInterval bounds how often expired entries are purged
+	memoryStoreSweepInterval = time.Minute
+)
+
+// stateStore backs server state that should outlive a process or be shared
+// by replicas, such as quota counters. Values are opaque bytes; counters are
+// stored as decimal strings so every backend can read them with get. A ttl
+// of 0 means the key never expires.
+type stateStore interface {
+	get(ctx context.Context, key string) ([]byte, bool, error)
+	set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	delete(ctx context.Context, key string) error
+	// incrBy adds delta to a counter, creating it with the ttl if missing,
+	// and returns the new value
+	incrBy(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error)
+	// keys lists the live keys starting with prefix, in no particular order
+	keys(ctx context.Context, prefix string) ([]string, error)
+	ping(ctx context.Context) error
+	close() error
+}
+
+// newStateStore creates the backend selected by TIME_STORE
+func newStateStore(config *Config) (stateStore, error) {
+	switch config.Store {
+	case storeFile:
+		return newFileStore(config.StoreFile)
+	case storeRedis:
+		return newRedisStore(config.StoreRedisURL, config.StorePrefix)
+	default:
+		return newMemoryStore(), nil
+	}
+}
+
+// memoryEntry is a stored value with an optional expiry
+type memoryEntry struct {
+	Value     []byte    `json:"value"`
+	ExpiresAt time.Time `json:"expires_at,omitzero"`
+}
+
+func (e memoryEntry) expired(now time.Time) bool {
+	return !e.ExpiresAt.IsZero() && !now.Before(e.ExpiresAt)
+}
+
+// memoryStore keeps state in process memory; it is lost on restart and not
+// shared between replicas
+type memoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]memoryEntry
+	lastSweep time.Time
+	dirty     bool
+	now       func() time.Time
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{entries: make(map[string]memoryEntry), now: time.Now}
+}
+
+// lookup returns a live entry; callers hold the lock
+func (m *memoryStore) lookup(key string, now time.Time) (memoryEntry, bool) {
+	e, ok := m.entries[key]
+	if ok && e.expired(now) {
+		delete(m.entries, key)
+		return memoryEntry{}, false
+	}
+	return e, ok
+}
+
+// store writes an entry and occasionally purges expired ones; callers hold the lock
+func (m *memoryStore) store(key string, e memoryEntry, now time.Time) {
+	m.entries[key] = e
+	m.dirty = true
+	if now.Sub(m.lastSweep) < memoryStoreSweepInterval {
+		return
+	}
+	m.lastSweep = now
+	for k, entry := range m.entries {
+		if entry.expired(now) {
+			delete(m.entries, k)
+		}
+	}
+}
+
+func expiryFor(now time.Time, ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return now.Add(ttl)
+}
+
+func (m *memoryStore) get(_ context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.lookup(key, m.now())
+	return e.Value, ok, nil
+}
+
+func (m *memoryStore) set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	m.store(key, memoryEntry{Value: value, ExpiresAt: expiryFor(now, ttl)}, now)
+	return nil
+}
+
+func (m *memoryStore) delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, key)
+	m.dirty = true
+	return nil
+}
+
+func (m *memoryStore) incrBy(_ context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	e, ok := m.lookup(key, now)
+	var n int64
+	if ok {
+		var err error
+		if n, err = strconv.ParseInt(string(e.Value), 10, 64); err != nil {
+			return 0, fmt.Errorf("value of %s is not a counter", key)
+		}
+	} else {
+		e.ExpiresAt = expiryFor(now, ttl)
+	}
+	n += delta
+	e.Value = []byte(strconv.FormatInt(n, 10))
+	m.store(key, e, now)
+	return n, nil
+}
+
+func (m *memoryStore) keys(_ context.Context, prefix string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	var keys []string
+	for key, e := range m.entries {
+		if strings.HasPrefix(key, prefix) && !e.expired(now) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func (m *memoryStore) ping(context.Context) error { return nil }
+
+func (m *memoryStore) close() error { return nil }
+
+// fileStore is a memoryStore persisted to a JSON file. It is restored at
+// startup and written atomically every few seconds when changed and on
+// close. It survives restarts but must not be shared by replicas.
+type fileStore struct {
+	*memoryStore
+	file string
+	stop chan struct{}
+	done chan struct{}
+}
+
+func newFileStore(file string) (*fileStore, error) {
+	f := &fileStore{
+		memoryStore: newMemoryStore(),
+		file:        file,
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	if err := f.restore(); err != nil {
+		return nil, fmt.Errorf("failed to restore state from %s: %w", file, err)
+	}
+	go f.flushLoop()
+	return f, nil
+}
+
+func (f *fileStore) restore() error {
+	data, err := os.ReadFile(f.file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var entries map[string]memoryEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return err
+	}
+	now := f.now()
+	for key, e := range entries {
+		if !e.expired(now) {
+			f.entries[key] = e
+		}
+	}
+	return nil
+}
+
+func (f *fileStore) flushLoop() {
+	defer close(f.done)
+	ticker := time.NewTicker(fileStoreFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := f.flush(); err != nil {
+				slog.Warn("Failed to flush state store", "file", f.file, "error", err)
+			}
+		case <-f.stop:
+			return
+		}
+	}
+}
+
+// flush writes the live entries if anything changed since the last flush
+func (f *fileStore) flush() error {
+	f.mu.Lock()
+	if !f.dirty {
+		f.mu.Unlock()
+		return nil
+	}
+	now := f.now()
+	entries := make(map[string]memoryEntry, len(f.entries))
+	for key, e := range f.entries {
+		if !e.expired(now) {
+			entries[key] = e
+		}
+	}
+	f.dirty = false
+	f.mu.Unlock()
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(f.file), ".state-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.file)
+}
+
+func (f *fileStore) close() error {
+	close(f.stop)
+	<-f.done
+	return f.flush()
+}
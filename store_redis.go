@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisIncrScript increments a counter and sets its expiry only when the key
+// has none, atomically and without requiring Redis 7's EXPIRE NX
+var redisIncrScript = redis.NewScript(`
+local n = redis.call('INCRBY', KEYS[1], ARGV[1])
+if tonumber(ARGV[2]) > 0 and redis.call('PTTL', KEYS[1]) < 0 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return n
+`)
+
+// redisGlobEscaper escapes the characters SCAN MATCH treats as patterns
+var redisGlobEscaper = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`)
+
+// redisLogger routes go-redis client messages through slog so they are
+// structured and redacted like the rest of the logs
+type redisLogger struct{}
+
+func (redisLogger) Printf(ctx context.Context, format string, v ...any) {
+	slog.WarnContext(ctx, "Redis client message", "message", fmt.Sprintf(format, v...))
+}
+
+// redactURLPassword masks the password of a connection URL for display
+func redactURLPassword(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.User == nil {
+		return raw
+	}
+	if _, hasPassword := u.User.Password(); !hasPassword {
+		return raw
+	}
+	return u.Redacted()
+}
+
+// redisStore shares state between replicas through Redis
+type redisStore struct {
+	client *redis.Client
+	prefix string
+}
+
+// newRedisStore connects to a redis:// or rediss:// URL and verifies the
+// connection so misconfiguration fails at startup
+func newRedisStore(url, prefix string) (*redisStore, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TIME_STORE_REDIS_URL: %w", err)
+	}
+	redis.SetLogger(redisLogger{})
+	s := &redisStore{client: redis.NewClient(opts), prefix: prefix}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.ping(ctx); err != nil {
+		s.client.Close()
+		return nil, fmt.Errorf("failed to connect to Redis at %s: %w", opts.Addr, err)
+	}
+	return s, nil
+}
+
+func (s *redisStore) get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := s.client.Get(ctx, s.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (s *redisStore) set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return s.client.Set(ctx, s.prefix+key, value, ttl).Err()
+}
+
+func (s *redisStore) delete(ctx context.Context, key string) error {
+	return s.client.Del(ctx, s.prefix+key).Err()
+}
+
+func (s *redisStore) incrBy(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	return redisIncrScript.Run(ctx, s.client, []string{s.prefix + key}, delta, ttl.Milliseconds()).Int64()
+}
+
+func (s *redisStore) keys(ctx context.Context, prefix string) ([]string, error) {
+	pattern := redisGlobEscaper.Replace(s.prefix+prefix) + "*"
+	var keys []string
+	iter := s.client.Scan(ctx, 0, pattern, 100).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, strings.TrimPrefix(iter.Val(), s.prefix))
+	}
+	return keys, iter.Err()
+}
+
+func (s *redisStore) ping(ctx context.Context) error {
+	return s.client.Ping(ctx).Err()
+}
+
+func (s *redisStore) close() error {
+	return s.client.Close()
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// testStoreBehavior exercises the stateStore contract shared by all backends
+func testStoreBehavior(t *testing.T, store stateStore) {
+	ctx := context.Background()
+
+	if _, ok, err := store.get(ctx, "missing"); err != nil || ok {
+		t.Errorf("Expected missing key to be absent but got ok=%v err=%v", ok, err)
+	}
+	if err := store.set(ctx, "pref", []byte("Europe/Warsaw"), 0); err != nil {
+		t.Fatalf("Failed to set value: %v", err)
+	}
+	if value, ok, err := store.get(ctx, "pref"); err != nil || !ok || string(value) != "Europe/Warsaw" {
+		t.Errorf("Expected stored value but got %q ok=%v err=%v", value, ok, err)
+	}
+	if err := store.delete(ctx, "pref"); err != nil {
+		t.Fatalf("Failed to delete value: %v", err)
+	}
+	if _, ok, _ := store.get(ctx, "pref"); ok {
+		t.Errorf("Expected deleted key to be absent")
+	}
+
+	for _, tt := range []struct {
+		delta    int64
+		expected int64
+	}{{1, 1}, {1, 2}, {5, 7}, {-1, 6}} {
+		n, err := store.incrBy(ctx, "counter", tt.delta, time.Hour)
+		if err != nil {
+			t.Fatalf("Failed to increment counter: %v", err)
+		}
+		if n != tt.expected {
+			t.Errorf("Expected counter %d after adding %d but got %d", tt.expected, tt.delta, n)
+		}
+	}
+	if value, _, _ := store.get(ctx, "counter"); string(value) != "6" {
+		t.Errorf("Expected counter to read back as \"6\" but got %q", value)
+	}
+	for _, key := range []string{"job:a", "job:b", "jobs", "other"} {
+		store.set(ctx, key, []byte("x"), 0)
+	}
+	keys, err := store.keys(ctx, "job:")
+	slices.Sort(keys)
+	if err != nil || !slices.Equal(keys, []string{"job:a", "job:b"}) {
+		t.Errorf("Expected keys [job:a job:b] but got %v (%v)", keys, err)
+	}
+	if err := store.ping(ctx); err != nil {
+		t.Errorf("Expected ping to succeed but got %v", err)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStoreBehavior(t, newMemoryStore())
+}
+
+func TestMemoryStore_Expiry(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	store := newMemoryStore()
+	store.now = func() time.Time { return now }
+
+	store.incrBy(ctx, "window", 1, time.Minute)
+	store.incrBy(ctx, "window", 1, time.Minute)
+	now = now.Add(time.Minute)
+	if n, _ := store.incrBy(ctx, "window", 1, time.Minute); n != 1 {
+		t.Errorf("Expected an expired counter to restart at 1 but got %d", n)
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "state.json")
+	store, err := newFileStore(file)
+	if err != nil {
+		t.Fatalf("Failed to create file store: %v", err)
+	}
+	testStoreBehavior(t, store)
+	store.set(context.Background(), "expiring", []byte("x"), time.Nanosecond)
+	if err := store.close(); err != nil {
+		t.Fatalf("Failed to close file store: %v", err)
+	}
+
+	restored, err := newFileStore(file)
+	if err != nil {
+		t.Fatalf("Failed to restore file store: %v", err)
+	}
+	defer restored.close()
+	if n, err := restored.incrBy(context.Background(), "counter", 1, time.Hour); err != nil || n != 7 {
+		t.Errorf("Expected restored counter 7 but got %d (%v)", n, err)
+	}
+	if _, ok, _ := restored.get(context.Background(), "expiring"); ok {
+		t.Errorf("Expected expired entries not to be restored")
+	}
+}
+
+func TestFileStore_Corrupt(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "state.json")
+	os.WriteFile(file, []byte("{not json"), 0o600)
+	if _, err := newFileStore(file); err == nil {
+		t.Errorf("Expected an error for a corrupt state file")
+	}
+}
+
+// TestRedisStore runs against a real server when TIME_TEST_REDIS_URL is set
+func TestRedisStore(t *testing.T) {
+	url := os.Getenv("TIME_TEST_REDIS_URL")
+	if url == "" {
+		t.Skip("TIME_TEST_REDIS_URL not set")
+	}
+	store, err := newRedisStore(url, "timemcp-test:"+t.Name()+":")
+	if err != nil {
+		t.Fatalf("Failed to connect to Redis: %v", err)
+	}
+	defer store.close()
+	store.delete(context.Background(), "counter")
+	testStoreBehavior(t, store)
+	store.delete(context.Background(), "counter")
+}
+
+func TestParseStoreSettings(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		backend string
+		wantErr bool
+	}{
+		{"default", map[string]string{}, storeMemory, false},
+		{"file", map[string]string{"TIME_STORE": "file", "TIME_STORE_FILE": "/tmp/state.json"}, storeFile, false},
+		{"file without path", map[string]string{"TIME_STORE": "file"}, "", true},
+		{"redis without url", map[string]string{"TIME_STORE": "redis"}, "", true},
+		{"unknown", map[string]string{"TIME_STORE": "etcd"}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"TIME_STORE", "TIME_STORE_FILE", "TIME_STORE_REDIS_URL"} {
+				t.Setenv(key, tt.env[key])
+			}
+			backend, _, _, _, err := parseStoreSettings()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error=%v but got %v", tt.wantErr, err)
+			}
+			if backend != tt.backend {
+				t.Errorf("Expected backend %q but got %q", tt.backend, backend)
+			}
+		})
+	}
+}
+
+func TestRedactURLPassword(t *testing.T) {
+	if got := redactURLPassword("redis://:hunter2secret@cache:6379/0"); got != "redis://:xxxxx@cache:6379/0" {
+		t.Errorf("Expected password to be masked but got %q", got)
+	}
+	if got := redactURLPassword("redis://cache:6379"); got != "redis://cache:6379" {
+		t.Errorf("Expected URL without password unchanged but got %q", got)
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies spans created by TimeMCP itself
+const tracerName = "TimeMCP"
+
+// setupTracing installs a global OTLP/HTTP tracer provider and W3C trace
+// context propagation. The exporter also honors the standard
+// OTEL_EXPORTER_OTLP_* variables. The returned function flushes pending spans.
+func setupTracing(ctx context.Context, config *Config) (func(context.Context) error, error) {
+	var opts []otlptracehttp.Option
+	if config.OTelEndpoint != "" {
+		opts = append(opts, otlptracehttp.WithEndpointURL(config.OTelEndpoint))
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(config.ServerName),
+		semconv.ServiceVersion(config.ServerVersion),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.OTelSampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	slog.Info("OpenTelemetry tracing enabled", "sample_ratio", config.OTelSampleRatio)
+	return provider.Shutdown, nil
+}
+
+// tracingMiddleware records a span per tool call with the tool name, caller
+// and outcome. Over HTTP the span joins the trace of the incoming request.
+func tracingMiddleware() server.ToolHandlerMiddleware {
+	tracer := otel.Tracer(tracerName)
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ctx, span := tracer.Start(ctx, "tools/call "+req.Params.Name, trace.WithAttributes(
+				attribute.String("mcp.method.name", "tools/call"),
+				attribute.String("mcp.tool.name", req.Params.Name),
+			))
+			defer span.End()
+			if requestID := getRequestID(ctx); requestID != "" {
+				span.SetAttributes(attribute.String("http.request.header.x-request-id", requestID))
+			}
+			if userID, _, role := getUserInfo(ctx); userID != "" {
+				span.SetAttributes(attribute.String("enduser.id", userID), attribute.String("enduser.role", role))
+			}
+
+			result, err := next(ctx, req)
+			switch {
+			case err != nil:
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			case result != nil && result.IsError:
+				span.SetStatus(codes.Error, toolResultText(result))
+			}
+			return result, err
+		}
+	}
+}
+
+// toolResultText returns the first text content of a tool result
+func toolResultText(result *mcp.CallToolResult) string {
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			return text.Text
+		}
+	}
+	return ""
+}
+
+// tracingHandler records a server span per HTTP request and extracts the
+// caller's trace context so tool call spans join the agent's trace
+func tracingHandler(next http.Handler) http.Handler {
+	return otelhttp.NewHandler(next, "TimeMCP HTTP",
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return r.Method + " " + r.URL.Path
+		}),
+	)
+}
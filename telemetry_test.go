@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracingMiddleware(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	handler := tracingMiddleware()(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if req.Params.Name == "convert_time" {
+			return mcp.NewToolResultError("Invalid target timezone: Mars/Base"), nil
+		}
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	ctx := context.WithValue(context.Background(), userIDKey, "user1")
+	ctx = context.WithValue(ctx, userRoleKey, "reader")
+	for _, name := range []string{"get_current_time", "convert_time"} {
+		req := mcp.CallToolRequest{}
+		req.Params.Name = name
+		if _, err := handler(ctx, req); err != nil {
+			t.Fatalf("Failed to call %s: %v", name, err)
+		}
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 spans but got %d", len(spans))
+	}
+	if spans[0].Name() != "tools/call get_current_time" {
+		t.Errorf("Expected span name %q but got %q", "tools/call get_current_time", spans[0].Name())
+	}
+	attrs := map[attribute.Key]string{}
+	for _, kv := range spans[0].Attributes() {
+		attrs[kv.Key] = kv.Value.Emit()
+	}
+	if attrs["mcp.tool.name"] != "get_current_time" || attrs["enduser.id"] != "user1" || attrs["enduser.role"] != "reader" {
+		t.Errorf("Expected tool and user attributes but got %v", attrs)
+	}
+	if spans[0].Status().Code != codes.Unset {
+		t.Errorf("Expected successful call to leave status unset but got %v", spans[0].Status())
+	}
+	if status := spans[1].Status(); status.Code != codes.Error || status.Description != "Invalid target timezone: Mars/Base" {
+		t.Errorf("Expected error status for failed tool call but got %v", status)
+	}
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"gopkg.in/yaml.v3"
+)
+
+// tenantKey holds the caller's tenant profile in the request context
+const tenantKey contextKey = "tenant"
+
+// tenantFile is the schema of TIME_TENANTS_FILE (YAML or JSON):
+//
+//	tenants:
+//	  finance:
+//	    audiences: [finance-app]
+//	    tools: [get_current_time, convert_time, check_business_day]
+//	    default_timezone: Europe/Warsaw
+//	    calendar: acme-pl
+//	    rate_limit: {rps: 5, burst: 10}
+//	    name: Finance Time
+//	    instructions: Times for the finance team.
+type tenantFile struct {
+	Tenants map[string]tenantDefinition `yaml:"tenants"`
+}
+
+type tenantDefinition struct {
+	Audiences       []string `yaml:"audiences"`
+	Tools           []string `yaml:"tools"`
+	DefaultTimezone string   `yaml:"default_timezone"`
+	Calendar        string   `yaml:"calendar"`
+	RateLimit       struct {
+		RPS   float64 `yaml:"rps"`
+		Burst int     `yaml:"burst"`
+	} `yaml:"rate_limit"`
+	Name         string `yaml:"name"`
+	Instructions string `yaml:"instructions"`
+}
+
+// tenant is the policy applied to callers whose token selects it
+type tenant struct {
+	Name      string
+	Audiences []string
+	// Tools the tenant may call; nil allows every tool the role allows
+	Tools           []string
+	DefaultTimezone string
+	Calendar        string
+	RateLimit       RateLimit
+	// Branding replacing the server name and instructions
+	ServerName   string
+	Instructions string
+}
+
+// tenantPolicy maps tenant names to their profiles. A nil policy means a
+// single-tenant deployment.
+type tenantPolicy map[string]*tenant
+
+// loadTenants reads and validates a tenants file; calendars are the
+// configured business calendars tenants may reference
+func loadTenants(path string, calendars map[string]*businessCalendar) (tenantPolicy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tenants file: %w", err)
+	}
+	return parseTenants(data, calendars)
+}
+
+// parseTenants parses tenant definitions from YAML or JSON. A nil calendars
+// map skips the check that tenant calendars exist, for calendars that are
+// not downloaded yet.
+func parseTenants(data []byte, calendars map[string]*businessCalendar) (tenantPolicy, error) {
+	var file tenantFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+
+	policy := make(tenantPolicy, len(file.Tenants))
+	audiences := make(map[string]string)
+	for name, def := range file.Tenants {
+		t, err := def.build(name, calendars)
+		if err != nil {
+			return nil, fmt.Errorf("tenant %q: %w", name, err)
+		}
+		for _, aud := range t.Audiences {
+			if other, ok := audiences[aud]; ok {
+				return nil, fmt.Errorf("audience %q is used by tenants %q and %q", aud, other, name)
+			}
+			audiences[aud] = name
+		}
+		policy[name] = t
+	}
+	return policy, nil
+}
+
+func (d tenantDefinition) build(name string, calendars map[string]*businessCalendar) (*tenant, error) {
+	if d.DefaultTimezone != "" {
+		if _, err := loadLocationCached(d.DefaultTimezone); err != nil {
+			return nil, fmt.Errorf("invalid default_timezone %q", d.DefaultTimezone)
+		}
+	}
+	if _, ok := calendars[d.Calendar]; d.Calendar != "" && calendars != nil && !ok {
+		return nil, fmt.Errorf("unknown calendar %q (define it in TIME_CALENDARS_FILE)", d.Calendar)
+	}
+	if d.RateLimit.RPS < 0 || d.RateLimit.Burst < 0 {
+		return nil, fmt.Errorf("rate_limit must not be negative")
+	}
+	return &tenant{
+		Name:            name,
+		Audiences:       d.Audiences,
+		Tools:           d.Tools,
+		DefaultTimezone: d.DefaultTimezone,
+		Calendar:        d.Calendar,
+		RateLimit:       RateLimit{Rate: d.RateLimit.RPS, Burst: d.RateLimit.Burst},
+		ServerName:      d.Name,
+		Instructions:    d.Instructions,
+	}, nil
+}
+
+// audiences lists every tenant audience, which tokens may carry in addition
+// to TIME_AUTH_AUDIENCE
+func (p tenantPolicy) audiences() []string {
+	var audiences []string
+	for _, t := range p {
+		audiences = append(audiences, t.Audiences...)
+	}
+	slices.Sort(audiences)
+	return audiences
+}
+
+// resolve selects the tenant for verified claims: the tenant claim if set,
+// else the first audience belonging to a tenant
+func (p tenantPolicy) resolve(claims *Claims) *tenant {
+	if p == nil || claims == nil {
+		return nil
+	}
+	if claims.Tenant != "" {
+		return p[claims.Tenant]
+	}
+	for _, aud := range claims.Audience {
+		for _, t := range p {
+			if slices.Contains(t.Audiences, aud) {
+				return t
+			}
+		}
+	}
+	return nil
+}
+
+// rateLimited reports whether any tenant has a rate limit
+func (p tenantPolicy) rateLimited() bool {
+	for _, t := range p {
+		if t.RateLimit.Enabled() {
+			return true
+		}
+	}
+	return false
+}
+
+// check rejects tokens naming a tenant that is not configured, so a typo
+// cannot escape the tenant's restrictions
+func (p tenantPolicy) check(claims *Claims) error {
+	if p == nil || claims.Tenant == "" {
+		return nil
+	}
+	if _, ok := p[claims.Tenant]; !ok {
+		return fmt.Errorf("unknown tenant %q", claims.Tenant)
+	}
+	return nil
+}
+
+// allows reports whether the tenant may call tool
+func (t *tenant) allows(tool string) bool {
+	return t.Tools == nil || slices.Contains(t.Tools, "*") || slices.Contains(t.Tools, tool)
+}
+
+// withTenant records the caller's tenant
+func withTenant(ctx context.Context, t *tenant) context.Context {
+	if t == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, tenantKey, t)
+}
+
+// tenantFromContext returns the caller's tenant, or nil
+func tenantFromContext(ctx context.Context) *tenant {
+	t, _ := ctx.Value(tenantKey).(*tenant)
+	return t
+}
+
+// brandInitializeResult applies the caller's tenant branding to the
+// initialize response
+func brandInitializeResult(ctx context.Context, _ any, _ *mcp.InitializeRequest, result *mcp.InitializeResult) {
+	t := tenantFromContext(ctx)
+	if t == nil || result == nil {
+		return
+	}
+	if t.ServerName != "" {
+		result.ServerInfo.Name = t.ServerName
+	}
+	if t.Instructions != "" {
+		result.Instructions = t.Instructions
+	}
+}
+
+func parseTenantSettings(calendars map[string]*businessCalendar) (string, tenantPolicy, error) {
+	tenantsFile := os.Getenv("TIME_TENANTS_FILE")
+	if tenantsFile == "" {
+		return "", nil, nil
+	}
+	tenants, err := loadTenants(tenantsFile, calendars)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid TIME_TENANTS_FILE: %w", err)
+	}
+	return tenantsFile, tenants, nil
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const testTenants = `
+tenants:
+  finance:
+    audiences: [finance-app]
+    tools: [get_current_time, check_business_day]
+    default_timezone: Asia/Tokyo
+    calendar: acme-pl
+    name: Finance Time
+    instructions: Times for the finance team.
+  ops:
+    audiences: [ops-app]
+    rate_limit: {rps: 0.001, burst: 1}
+`
+
+func TestParseTenants(t *testing.T) {
+	calendars := map[string]*businessCalendar{"acme-pl": {Name: "acme-pl"}}
+	tenants, err := parseTenants([]byte(testTenants), calendars)
+	if err != nil {
+		t.Fatalf("Failed to parse tenants: %v", err)
+	}
+	if audiences := tenants.audiences(); strings.Join(audiences, ",") != "finance-app,ops-app" {
+		t.Errorf("Expected both tenant audiences but got %v", audiences)
+	}
+	if !tenants["ops"].allows("describe_cron") || tenants["finance"].allows("describe_cron") {
+		t.Errorf("Expected ops to allow every tool and finance only its list")
+	}
+
+	tests := []struct {
+		name   string
+		claims *Claims
+		want   string
+	}{
+		{"audience", &Claims{RegisteredClaims: jwt.RegisteredClaims{Audience: jwt.ClaimStrings{"finance-app"}}}, "finance"},
+		{"tenant claim", &Claims{RegisteredClaims: jwt.RegisteredClaims{Audience: jwt.ClaimStrings{"finance-app"}}, Tenant: "ops"}, "ops"},
+		{"no tenant", &Claims{RegisteredClaims: jwt.RegisteredClaims{Audience: jwt.ClaimStrings{"TimeMCP-user"}}}, ""},
+	}
+	for _, tt := range tests {
+		got := ""
+		if tenant := tenants.resolve(tt.claims); tenant != nil {
+			got = tenant.Name
+		}
+		if got != tt.want {
+			t.Errorf("%s: Expected tenant %q but got %q", tt.name, tt.want, got)
+		}
+	}
+	if err := tenants.check(&Claims{Tenant: "missing"}); err == nil {
+		t.Errorf("Expected an error for an unknown tenant claim")
+	}
+
+	invalid := []string{
+		"tenants: {a: {default_timezone: Mars/Olympus}}",
+		"tenants: {a: {calendar: missing}}",
+		"tenants: {a: {audiences: [x]}, b: {audiences: [x]}}",
+		"tenants: {a: {rate_limit: {rps: -1}}}",
+	}
+	for _, data := range invalid {
+		if _, err := parseTenants([]byte(data), calendars); err == nil {
+			t.Errorf("Expected an error for %q", data)
+		}
+	}
+}
+
+func TestTenantPolicy_HTTP(t *testing.T) {
+	calendars, err := parseCalendars([]byte("calendars: {acme-pl: {timezone: Europe/Warsaw}}"))
+	if err != nil {
+		t.Fatalf("Failed to parse calendars: %v", err)
+	}
+	tenants, err := parseTenants([]byte(testTenants), calendars)
+	if err != nil {
+		t.Fatalf("Failed to parse tenants: %v", err)
+	}
+	config := &Config{
+		HTTPREST:      true,
+		AuthEnabled:   true,
+		AuthSecretKey: "test-secret-key-that-is-long-enough-for-hs256",
+		AuthIssuer:    "test-issuer",
+		AuthAudience:  "test-audience",
+		Calendars:     calendars,
+		Tenants:       tenants,
+	}
+	handler, auth := newRESTTestHandler(t, config)
+	token := func(audience string, extra map[string]any) string {
+		token, _, err := auth.generateToken(tokenOptions{UserID: "1", Username: "fin", Role: "user", Expiration: time.Hour, Audience: audience, Extra: extra})
+		if err != nil {
+			t.Fatalf("Failed to generate token: %v", err)
+		}
+		return token
+	}
+	finance := token("finance-app", nil)
+
+	var text map[string]string
+	rec := serveREST(handler, "GET", "/api/v1/now", "", finance)
+	json.NewDecoder(rec.Body).Decode(&text)
+	if rec.Code != http.StatusOK || !strings.Contains(text["result"], "Asia/Tokyo") {
+		t.Errorf("Expected the tenant default timezone but got %d %v", rec.Code, text)
+	}
+	rec = serveREST(handler, "POST", "/api/v1/tools/check_business_day", `{"date": "2025-06-02"}`, finance)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "calendar acme-pl") {
+		t.Errorf("Expected the tenant calendar but got %d %s", rec.Code, rec.Body.String())
+	}
+	if rec := serveREST(handler, "POST", "/api/v1/tools/describe_cron", `{"expression": "@daily"}`, finance); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 for a tool outside the tenant but got %d", rec.Code)
+	}
+	if rec := serveREST(handler, "GET", "/api/v1/now", "", token("test-audience", map[string]any{"tenant": "nope"})); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 for an unknown tenant but got %d", rec.Code)
+	}
+
+	rec = serveREST(handler, "GET", "/capabilities", "", finance)
+	var caps capabilitiesResponse
+	json.NewDecoder(rec.Body).Decode(&caps)
+	if caps.Server["name"] != "Finance Time" || len(caps.Tools) != 2 {
+		t.Errorf("Expected branded capabilities with the tenant's tools but got %v and %d tools", caps.Server, len(caps.Tools))
+	}
+
+	ops := token("ops-app", nil)
+	if rec := serveREST(handler, "POST", "/api/v1/tools/describe_cron", `{"expression": "@daily"}`, ops); rec.Code != http.StatusOK {
+		t.Errorf("Expected ops to call any tool but got %d", rec.Code)
+	}
+	if rec := serveREST(handler, "POST", "/api/v1/tools/describe_cron", `{"expression": "@daily"}`, ops); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected the tenant rate limit but got %d", rec.Code)
+	}
+	if rec := serveREST(handler, "GET", "/api/v1/now", "", finance); rec.Code != http.StatusOK {
+		t.Errorf("Expected other tenants to keep their own limit but got %d", rec.Code)
+	}
+}
+
+func TestBrandInitializeResult(t *testing.T) {
+	result := &mcp.InitializeResult{ServerInfo: mcp.Implementation{Name: "TimeMCP"}, Instructions: "default"}
+	brandInitializeResult(context.Background(), 1, nil, result)
+	if result.ServerInfo.Name != "TimeMCP" {
+		t.Errorf("Expected no branding without a tenant but got %s", result.ServerInfo.Name)
+	}
+	ctx := withTenant(context.Background(), &tenant{Name: "finance", ServerName: "Finance Time", Instructions: "Finance"})
+	brandInitializeResult(ctx, 1, nil, result)
+	if result.ServerInfo.Name != "Finance Time" || result.Instructions != "Finance" {
+		t.Errorf("Expected tenant branding but got %s %q", result.ServerInfo.Name, result.Instructions)
+	}
+}
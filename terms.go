@@ -0,0 +1,330 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"gopkg.in/yaml.v3"
+)
+
+// termsFile is the schema of TIME_TERMS_FILE (YAML or JSON):
+//
+//	schedules:
+//	  uni:
+//	    timezone: Europe/Warsaw
+//	    week_start: monday
+//	    terms:
+//	      - name: Winter semester 2025/26
+//	        start: 2025-10-01
+//	        end: 2026-02-15
+//	        breaks:
+//	          - {name: Christmas break, start: 2025-12-22, end: 2026-01-06}
+type termsFile struct {
+	Schedules map[string]termScheduleDefinition `yaml:"schedules"`
+}
+
+type termScheduleDefinition struct {
+	Timezone  string           `yaml:"timezone"`
+	WeekStart string           `yaml:"week_start"`
+	Terms     []termDefinition `yaml:"terms"`
+}
+
+type termDefinition struct {
+	Name   string            `yaml:"name"`
+	Start  string            `yaml:"start"`
+	End    string            `yaml:"end"`
+	Breaks []breakDefinition `yaml:"breaks"`
+}
+
+type breakDefinition struct {
+	Name  string `yaml:"name"`
+	Start string `yaml:"start"`
+	End   string `yaml:"end"`
+}
+
+// termSchedule is a parsed term structure. Term and break dates are calendar
+// days in UTC, with inclusive ends
+type termSchedule struct {
+	Name      string
+	Location  *time.Location
+	WeekStart time.Weekday
+	Terms     []academicTerm
+}
+
+type academicTerm struct {
+	Name       string
+	Start, End time.Time
+	Breaks     []termBreak
+}
+
+type termBreak struct {
+	Name       string
+	Start, End time.Time
+}
+
+// loadTermSchedules reads and validates a terms file
+func loadTermSchedules(path string) (map[string]*termSchedule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read terms file: %w", err)
+	}
+	return parseTermSchedules(data)
+}
+
+// parseTermSchedules parses term schedules from YAML or JSON
+func parseTermSchedules(data []byte) (map[string]*termSchedule, error) {
+	var file termsFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+
+	schedules := make(map[string]*termSchedule, len(file.Schedules))
+	for name, def := range file.Schedules {
+		schedule, err := def.build(name)
+		if err != nil {
+			return nil, fmt.Errorf("schedule %q: %w", name, err)
+		}
+		schedules[name] = schedule
+	}
+	return schedules, nil
+}
+
+func (d termScheduleDefinition) build(name string) (*termSchedule, error) {
+	schedule := &termSchedule{Name: name, Location: time.UTC, WeekStart: time.Monday}
+	if d.Timezone != "" {
+		loc, err := loadLocationCached(d.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone %q", d.Timezone)
+		}
+		schedule.Location = loc
+	}
+	if d.WeekStart != "" {
+		wd, ok := weekdayNames[strings.ToLower(strings.TrimSpace(d.WeekStart))]
+		if !ok {
+			return nil, fmt.Errorf("invalid week_start %q", d.WeekStart)
+		}
+		schedule.WeekStart = wd
+	}
+	if len(d.Terms) == 0 {
+		return nil, fmt.Errorf("no terms")
+	}
+
+	for _, t := range d.Terms {
+		if t.Name == "" {
+			return nil, fmt.Errorf("term without a name")
+		}
+		start, end, err := parseDateRange(t.Start, t.End)
+		if err != nil {
+			return nil, fmt.Errorf("term %q: %w", t.Name, err)
+		}
+		term := academicTerm{Name: t.Name, Start: start, End: end}
+		for _, b := range t.Breaks {
+			bStart, bEnd, err := parseDateRange(b.Start, b.End)
+			if err != nil {
+				return nil, fmt.Errorf("term %q break %q: %w", t.Name, b.Name, err)
+			}
+			if bStart.Before(start) || bEnd.After(end) {
+				return nil, fmt.Errorf("term %q break %q is outside the term", t.Name, b.Name)
+			}
+			term.Breaks = append(term.Breaks, termBreak{Name: b.Name, Start: bStart, End: bEnd})
+		}
+		schedule.Terms = append(schedule.Terms, term)
+	}
+
+	slices.SortFunc(schedule.Terms, func(a, b academicTerm) int { return a.Start.Compare(b.Start) })
+	for i := 1; i < len(schedule.Terms); i++ {
+		if !schedule.Terms[i].Start.After(schedule.Terms[i-1].End) {
+			return nil, fmt.Errorf("terms %q and %q overlap", schedule.Terms[i-1].Name, schedule.Terms[i].Name)
+		}
+	}
+	return schedule, nil
+}
+
+// parseDateRange parses an inclusive YYYY-MM-DD range
+func parseDateRange(startStr, endStr string) (time.Time, time.Time, error) {
+	start, err := time.Parse("2006-01-02", startStr)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid start %q", startStr)
+	}
+	end, err := time.Parse("2006-01-02", endStr)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid end %q", endStr)
+	}
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("end %s is before start %s", endStr, startStr)
+	}
+	return start, end, nil
+}
+
+// termOn returns the index of the term containing date, or -1 and the index
+// of the next term (len(Terms) if none follows)
+func (s *termSchedule) termOn(date time.Time) (int, int) {
+	next := len(s.Terms)
+	for i, term := range s.Terms {
+		if !date.Before(term.Start) && !date.After(term.End) {
+			return i, i + 1
+		}
+		if date.Before(term.Start) {
+			next = i
+			break
+		}
+	}
+	return -1, next
+}
+
+// weekOfTerm returns the week of the term date falls in; week 1 is the week
+// containing the term's first day
+func (s *termSchedule) weekOfTerm(term academicTerm, date time.Time) int {
+	first := startOfWeek(term.Start, s.WeekStart)
+	return int(startOfWeek(date, s.WeekStart).Sub(first).Hours())/(7*24) + 1
+}
+
+// termScheduleByName looks up a term schedule, defaulting to the only one
+func termScheduleByName(config *Config, name string) (*termSchedule, error) {
+	terms := config.datasets().Terms
+	if name == "" && len(terms) == 1 {
+		for _, s := range terms {
+			return s, nil
+		}
+	}
+	if s, ok := terms[name]; ok {
+		return s, nil
+	}
+	names := make([]string, 0, len(terms))
+	for n := range terms {
+		names = append(names, n)
+	}
+	slices.Sort(names)
+	if name == "" {
+		return nil, fmt.Errorf("schedule is required (available: %s)", strings.Join(names, ", "))
+	}
+	return nil, fmt.Errorf("unknown schedule %q (available: %s)", name, strings.Join(names, ", "))
+}
+
+// academicTermResult is the structured result of academic_term
+type academicTermResult struct {
+	Schedule      string           `json:"schedule"`
+	Date          string           `json:"date"`
+	Weekday       string           `json:"weekday"`
+	InTerm        bool             `json:"in_term"`
+	Term          *termBoundaries  `json:"term,omitempty" jsonschema:"The term containing the date"`
+	WeekOfTerm    int              `json:"week_of_term,omitempty" jsonschema:"Week of the term, where week 1 contains the first day"`
+	WeeksInTerm   int              `json:"weeks_in_term,omitempty"`
+	DayOfTerm     int              `json:"day_of_term,omitempty"`
+	DaysRemaining *int             `json:"days_remaining,omitempty" jsonschema:"Days left in the term after the date"`
+	Break         *termBoundaries  `json:"break,omitempty" jsonschema:"The break within the term that the date falls in"`
+	PreviousTerm  *termBoundaries  `json:"previous_term,omitempty" jsonschema:"The last term that ended before the date, when between terms"`
+	NextTerm      *termBoundaries  `json:"next_term,omitempty" jsonschema:"The next term to start"`
+	DaysUntilNext int              `json:"days_until_next_term,omitempty"`
+	Terms         []termBoundaries `json:"terms,omitempty" jsonschema:"Every term of the schedule, when requested"`
+}
+
+type termBoundaries struct {
+	Name  string `json:"name"`
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+func boundariesOf(name string, start, end time.Time) *termBoundaries {
+	return &termBoundaries{Name: name, Start: start.Format("2006-01-02"), End: end.Format("2006-01-02")}
+}
+
+// describeTermDate maps a date to its term in a schedule
+func describeTermDate(s *termSchedule, date time.Time) academicTermResult {
+	days := func(from, to time.Time) int { return int(to.Sub(from).Hours() / 24) }
+	result := academicTermResult{
+		Schedule: s.Name,
+		Date:     date.Format("2006-01-02"),
+		Weekday:  date.Weekday().String(),
+	}
+
+	current, next := s.termOn(date)
+	if current >= 0 {
+		term := s.Terms[current]
+		remaining := days(date, term.End)
+		result.InTerm = true
+		result.Term = boundariesOf(term.Name, term.Start, term.End)
+		result.WeekOfTerm = s.weekOfTerm(term, date)
+		result.WeeksInTerm = s.weekOfTerm(term, term.End)
+		result.DayOfTerm = days(term.Start, date) + 1
+		result.DaysRemaining = &remaining
+		for _, b := range term.Breaks {
+			if !date.Before(b.Start) && !date.After(b.End) {
+				result.Break = boundariesOf(b.Name, b.Start, b.End)
+				break
+			}
+		}
+	} else if next > 0 {
+		prev := s.Terms[next-1]
+		result.PreviousTerm = boundariesOf(prev.Name, prev.Start, prev.End)
+	}
+	if next < len(s.Terms) {
+		term := s.Terms[next]
+		result.NextTerm = boundariesOf(term.Name, term.Start, term.End)
+		result.DaysUntilNext = days(date, term.Start)
+	}
+	return result
+}
+
+func addAcademicTermTool(mcpServer *server.MCPServer, config *Config) {
+	mcpServer.AddTool(
+		mcp.NewTool("academic_term",
+			mcp.WithDescription("Map a date to the academic term or semester of a configured term schedule: the term's boundaries, week of term, day of term, days remaining and any break it falls in. Between terms, returns the previous and next term."),
+			mcp.WithString("schedule",
+				mcp.Description("Name of the term schedule. Defaults to the only configured schedule."),
+			),
+			mcp.WithString("date",
+				mcp.Description("Date in YYYY-MM-DD format. Defaults to today in the schedule's timezone."),
+			),
+			mcp.WithBoolean("list_terms",
+				mcp.Description("Also list every term of the schedule with its boundaries. Defaults to false."),
+			),
+			mcp.WithOutputSchema[academicTermResult](),
+			mcp.WithTitleAnnotation("Academic Term"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		handleAcademicTerm(config),
+	)
+}
+
+// handleAcademicTerm returns a handler for the academic_term tool
+func handleAcademicTerm(config *Config) server.ToolHandlerFunc {
+	return func(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		schedule, err := termScheduleByName(config, request.GetString("schedule", ""))
+		if err != nil {
+			return toolError(errorNotFound, err.Error()), nil
+		}
+
+		// Dates are calendar days, so they are handled in UTC
+		today := config.now().In(schedule.Location)
+		date := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+		if dateStr := request.GetString("date", ""); dateStr != "" {
+			if date, err = time.Parse("2006-01-02", dateStr); err != nil {
+				return toolError(errorParse, fmt.Sprintf("Invalid date format: %s. Please provide date in YYYY-MM-DD format.", dateStr)), nil
+			}
+		}
+
+		result := describeTermDate(schedule, date)
+		if request.GetBool("list_terms", false) {
+			for _, term := range schedule.Terms {
+				result.Terms = append(result.Terms, *boundariesOf(term.Name, term.Start, term.End))
+			}
+		}
+
+		data, err := json.Marshal(result)
+		if err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to encode result: %v", err)), nil
+		}
+		return mcp.NewToolResultStructured(result, string(data)), nil
+	}
+}
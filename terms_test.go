@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const testTerms = `
+schedules:
+  uni:
+    timezone: Europe/Warsaw
+    terms:
+      - name: Summer semester 2026
+        start: 2026-02-23
+        end: 2026-06-30
+      - name: Winter semester 2025/26
+        start: 2025-10-01
+        end: 2026-02-15
+        breaks:
+          - {name: Christmas break, start: 2025-12-22, end: 2026-01-06}
+`
+
+func TestParseTermSchedulesInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"no terms", `schedules: {uni: {}}`, "no terms"},
+		{"bad date", `schedules: {uni: {terms: [{name: T1, start: 2025-13-01, end: 2026-01-01}]}}`, "invalid start"},
+		{"reversed", `schedules: {uni: {terms: [{name: T1, start: 2026-01-01, end: 2025-01-01}]}}`, "before start"},
+		{"overlap", `schedules: {uni: {terms: [{name: T1, start: 2025-01-01, end: 2025-06-30}, {name: T2, start: 2025-06-30, end: 2025-12-31}]}}`, "overlap"},
+		{"break outside", `schedules: {uni: {terms: [{name: T1, start: 2025-01-01, end: 2025-06-30, breaks: [{name: B, start: 2025-06-20, end: 2025-07-05}]}]}}`, "outside the term"},
+		{"week start", `schedules: {uni: {week_start: someday, terms: [{name: T1, start: 2025-01-01, end: 2025-06-30}]}}`, "week_start"},
+	}
+	for _, tt := range tests {
+		_, err := parseTermSchedules([]byte(tt.data))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected error containing %q but got %v", tt.name, tt.want, err)
+		}
+	}
+}
+
+func TestAcademicTerm(t *testing.T) {
+	terms, err := parseTermSchedules([]byte(testTerms))
+	if err != nil {
+		t.Fatalf("Failed to parse terms: %v", err)
+	}
+	clock := newFakeClock(time.Date(2025, 12, 31, 23, 30, 0, 0, time.UTC))
+	handler := handleAcademicTerm(&Config{DefaultTimezone: "UTC", Clock: clock, Terms: terms})
+	call := func(args map[string]any) academicTermResult {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := handler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("Failed to call handler: %v", err)
+		}
+		if result.IsError {
+			t.Fatalf("Failed to map date: %s", toolResultText(result))
+		}
+		return result.StructuredContent.(academicTermResult)
+	}
+
+	// Today is already 2026-01-01 in Warsaw, during the Christmas break.
+	// Week 1 is Monday 2025-09-29 to Sunday 2025-10-05
+	got := call(map[string]any{})
+	if got.Date != "2026-01-01" || !got.InTerm || got.Term.Name != "Winter semester 2025/26" ||
+		got.WeekOfTerm != 14 || got.WeeksInTerm != 20 || got.DayOfTerm != 93 || *got.DaysRemaining != 45 {
+		t.Errorf("Expected day 93 in week 14 of the winter semester but got %+v", got)
+	}
+	if got.Break == nil || got.Break.Name != "Christmas break" || got.Break.End != "2026-01-06" {
+		t.Errorf("Expected the Christmas break but got %+v", got.Break)
+	}
+	if got.NextTerm == nil || got.NextTerm.Name != "Summer semester 2026" {
+		t.Errorf("Expected the summer semester next but got %+v", got.NextTerm)
+	}
+
+	// Between semesters
+	got = call(map[string]any{"schedule": "uni", "date": "2026-02-20", "list_terms": true})
+	if got.InTerm || got.Term != nil || got.PreviousTerm == nil || got.PreviousTerm.End != "2026-02-15" ||
+		got.NextTerm.Start != "2026-02-23" || got.DaysUntilNext != 3 {
+		t.Errorf("Expected a date between semesters but got %+v", got)
+	}
+	if len(got.Terms) != 2 || got.Terms[0].Name != "Winter semester 2025/26" {
+		t.Errorf("Expected the terms in order but got %+v", got.Terms)
+	}
+
+	// After the last term
+	got = call(map[string]any{"date": "2026-07-01"})
+	if got.InTerm || got.NextTerm != nil || got.PreviousTerm.Name != "Summer semester 2026" {
+		t.Errorf("Expected a date after the last term but got %+v", got)
+	}
+}
+
+func TestAcademicTermErrors(t *testing.T) {
+	terms, err := parseTermSchedules([]byte(testTerms + "  school:\n    terms: [{name: T1, start: 2025-09-01, end: 2026-06-26}]\n"))
+	if err != nil {
+		t.Fatalf("Failed to parse terms: %v", err)
+	}
+	handler := handleAcademicTerm(&Config{DefaultTimezone: "UTC", Terms: terms})
+	for _, args := range []map[string]any{
+		{},
+		{"schedule": "college"},
+		{"schedule": "uni", "date": "01/02/2026"},
+	} {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := handler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("Failed to call handler: %v", err)
+		}
+		if !result.IsError {
+			t.Errorf("Expected an error for %v but got %s", args, toolResultText(result))
+		}
+	}
+}
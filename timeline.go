@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/araddon/dateparse"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// maxTimelineEvents caps the events of one build_timeline call
+const maxTimelineEvents = 1000
+
+// timelineInput is one labeled timestamp as given
+type timelineInput struct {
+	Label    string `json:"label"`
+	Time     string `json:"time"`
+	Timezone string `json:"timezone"`
+}
+
+// timeline is the structured result of build_timeline
+type timeline struct {
+	Timezone string          `json:"timezone"`
+	Start    string          `json:"start" jsonschema:"Earliest event in RFC 3339"`
+	End      string          `json:"end" jsonschema:"Latest event in RFC 3339"`
+	Span     string          `json:"span" jsonschema:"Time from the earliest to the latest event, e.g. 2h15m"`
+	Events   []timelineEvent `json:"events"`
+}
+
+type timelineEvent struct {
+	Index        int     `json:"index" jsonschema:"Position of the event in the input, from 0"`
+	Label        string  `json:"label"`
+	Time         string  `json:"time" jsonschema:"Event time in RFC 3339 in the timeline's timezone"`
+	Gap          string  `json:"gap" jsonschema:"Time since the previous event, 0s for the first"`
+	GapSeconds   float64 `json:"gap_seconds"`
+	Elapsed      string  `json:"elapsed" jsonschema:"Time since the first event"`
+	NewLocalDate bool    `json:"new_local_date,omitempty" jsonschema:"Whether the event falls on a later local date than the previous one"`
+}
+
+func addTimelineTool(mcpServer *server.MCPServer, config *Config) {
+	mcpServer.AddTool(
+		mcp.NewTool("build_timeline",
+			mcp.WithDescription("Build a chronological timeline from labeled timestamps in mixed formats and timezones, e.g. for an incident review. Events are normalized to one timezone and ordered, with the gap since the previous event and the time elapsed since the first."),
+			mcp.WithArray("events",
+				mcp.Description("Labeled timestamps: label, time in any common format (RFC 3339, '2025-03-10 14:05', 'Mar 10 2025 2:05pm', Unix seconds), and an optional timezone for times without an offset."),
+				mcp.Items(map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"label":    map[string]any{"type": "string"},
+						"time":     map[string]any{"type": "string"},
+						"timezone": map[string]any{"type": "string"},
+					},
+					"required": []string{"label", "time"},
+				}),
+				mcp.MinItems(1),
+				mcp.MaxItems(maxTimelineEvents),
+				mcp.Required(),
+			),
+			mcp.WithString("timezone",
+				mcp.Description("Timezone to present the timeline in, and of events without one. Defaults to the session or server default."),
+			),
+			mcp.WithOutputSchema[timeline](),
+			mcp.WithTitleAnnotation("Build Timeline"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		handleBuildTimeline(config),
+	)
+}
+
+// handleBuildTimeline returns a handler for the build_timeline tool
+func handleBuildTimeline(config *Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Events []timelineInput `json:"events"`
+		}
+		if err := request.BindArguments(&args); err != nil {
+			return toolError(errorInvalidArgument, fmt.Sprintf("Invalid events: %v", err)), nil
+		}
+		if len(args.Events) == 0 || len(args.Events) > maxTimelineEvents {
+			return toolError(errorInvalidArgument, fmt.Sprintf("Provide between 1 and %d events", maxTimelineEvents)), nil
+		}
+		timezoneStr := request.GetString("timezone", "")
+		outLoc, err := loadTimezone(ctx, timezoneStr, config)
+		if err != nil {
+			return invalidTimezoneError("Invalid timezone", timezoneStr), nil
+		}
+
+		type parsed struct {
+			index int
+			label string
+			t     time.Time
+		}
+		events := make([]parsed, len(args.Events))
+		for i, in := range args.Events {
+			loc := outLoc
+			if in.Timezone != "" {
+				if loc, err = loadLocationCached(in.Timezone); err != nil {
+					return invalidTimezoneError(fmt.Sprintf("Invalid timezone in event %d", i), in.Timezone), nil
+				}
+			}
+			t, err := dateparse.ParseIn(in.Time, loc)
+			if err != nil {
+				return toolError(errorParse, fmt.Sprintf("Invalid time in event %d: %q. Please provide RFC 3339 or another common date-time format.", i, in.Time)), nil
+			}
+			events[i] = parsed{index: i, label: in.Label, t: t.In(outLoc)}
+		}
+		// Stable, so simultaneous events keep their input order
+		slices.SortStableFunc(events, func(a, b parsed) int { return a.t.Compare(b.t) })
+
+		first, last := events[0].t, events[len(events)-1].t
+		result := timeline{
+			Timezone: outLoc.String(),
+			Start:    first.Format(time.RFC3339),
+			End:      last.Format(time.RFC3339),
+			Span:     shortDuration(last.Sub(first)),
+			Events:   make([]timelineEvent, len(events)),
+		}
+		prev := first
+		for i, e := range events {
+			gap := e.t.Sub(prev)
+			result.Events[i] = timelineEvent{
+				Index:        e.index,
+				Label:        e.label,
+				Time:         e.t.Format(time.RFC3339),
+				Gap:          shortDuration(gap),
+				GapSeconds:   gap.Seconds(),
+				Elapsed:      shortDuration(e.t.Sub(first)),
+				NewLocalDate: e.t.Format(time.DateOnly) > prev.Format(time.DateOnly),
+			}
+			prev = e.t
+		}
+
+		data, err := json.Marshal(result)
+		if err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to encode result: %v", err)), nil
+		}
+		return mcp.NewToolResultStructured(result, string(data)), nil
+	}
+}
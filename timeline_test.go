@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestBuildTimeline(t *testing.T) {
+	handler := handleBuildTimeline(&Config{DefaultTimezone: "UTC"})
+	call := func(args map[string]any) *mcp.CallToolResult {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := handler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("Failed to call handler: %v", err)
+		}
+		return result
+	}
+
+	result := call(map[string]any{
+		"timezone": "Europe/Warsaw",
+		"events": []any{
+			map[string]any{"label": "fix deployed", "time": "2025-03-10T15:20:00Z"},
+			map[string]any{"label": "alert fired", "time": "2025-03-10 09:05", "timezone": "America/New_York"},
+			map[string]any{"label": "on-call paged", "time": "2025-03-10 14:07"},
+			map[string]any{"label": "postmortem", "time": "Mar 11 2025 10:00am"},
+		},
+	})
+	if result.IsError {
+		t.Fatalf("Failed to build timeline: %s", toolResultText(result))
+	}
+	got := result.StructuredContent.(timeline)
+	want := []struct {
+		label, time, gap string
+		newDate          bool
+	}{
+		{"alert fired", "2025-03-10T14:05:00+01:00", "0s", false},
+		{"on-call paged", "2025-03-10T14:07:00+01:00", "2m", false},
+		{"fix deployed", "2025-03-10T16:20:00+01:00", "2h13m", false},
+		{"postmortem", "2025-03-11T10:00:00+01:00", "17h40m", true},
+	}
+	if len(got.Events) != len(want) {
+		t.Fatalf("Expected %d events but got %+v", len(want), got.Events)
+	}
+	for i, w := range want {
+		e := got.Events[i]
+		if e.Label != w.label || e.Time != w.time || e.Gap != w.gap || e.NewLocalDate != w.newDate {
+			t.Errorf("Expected event %d to be %+v but got %+v", i, w, e)
+		}
+	}
+	if got.Span != "19h55m" || got.Events[3].Elapsed != "19h55m" {
+		t.Errorf("Expected a span of 19h55m but got %+v", got)
+	}
+
+	for _, args := range []map[string]any{
+		{"events": []any{}},
+		{"events": []any{map[string]any{"label": "x", "time": "not a time"}}},
+		{"events": []any{map[string]any{"label": "x", "time": "2025-03-10 09:05", "timezone": "Mars/Olympus"}}},
+	} {
+		if result := call(args); !result.IsError {
+			t.Errorf("Expected %v to be rejected", args)
+		}
+	}
+}
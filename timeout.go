@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// toolCallResult carries a handler's return values across goroutines
+type toolCallResult struct {
+	result *mcp.CallToolResult
+	err    error
+}
+
+// timeoutMiddleware gives every tool call a deadline taken from the current
+// configuration. Handlers receive the deadline through ctx and should check
+// it in any loop or I/O; a handler that ignores it is abandoned, so the
+// session gets a timeout error instead of hanging. A timeout of 0 disables it.
+func timeoutMiddleware(config func() *Config) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			timeout := config().ToolTimeout
+			if timeout <= 0 {
+				return next(ctx, req)
+			}
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			done := make(chan toolCallResult, 1)
+			go func() {
+				// The recovery middleware cannot see panics in this goroutine
+				defer func() {
+					if p := recover(); p != nil {
+						slog.ErrorContext(ctx, "Tool call panicked", "tool", req.Params.Name, "panic", fmt.Sprint(p), "stack", string(debug.Stack()))
+						done <- toolCallResult{result: toolError(errorInternal, fmt.Sprintf("Internal error in tool '%s'", req.Params.Name))}
+					}
+				}()
+				result, err := next(ctx, req)
+				done <- toolCallResult{result, err}
+			}()
+
+			select {
+			case res := <-done:
+				if res.err != nil && errors.Is(res.err, context.DeadlineExceeded) {
+					return toolTimeoutResult(ctx, req.Params.Name, timeout), nil
+				}
+				return res.result, res.err
+			case <-ctx.Done():
+				if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+					return toolTimeoutResult(ctx, req.Params.Name, timeout), nil
+				}
+				// The client cancelled the request or the session closed
+				return nil, ctx.Err()
+			}
+		}
+	}
+}
+
+func toolTimeoutResult(ctx context.Context, toolName string, timeout time.Duration) *mcp.CallToolResult {
+	slog.WarnContext(ctx, "Tool call timed out", "tool", toolName, "timeout", timeout.String())
+	return toolError(errorTimeout, fmt.Sprintf("Tool '%s' timed out after %s", toolName, timeout))
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestTimeoutMiddleware(t *testing.T) {
+	config := &Config{ToolTimeout: 20 * time.Millisecond}
+	middleware := timeoutMiddleware(func() *Config { return config })
+
+	tests := []struct {
+		name      string
+		handler   func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error)
+		timeout   time.Duration
+		expectErr bool
+	}{
+		{
+			name: "completes in time",
+			handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return mcp.NewToolResultText("ok"), nil
+			},
+			timeout: 20 * time.Millisecond,
+		},
+		{
+			name: "honors cancellation",
+			handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			},
+			timeout:   20 * time.Millisecond,
+			expectErr: true,
+		},
+		{
+			name: "ignores cancellation",
+			handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				time.Sleep(200 * time.Millisecond)
+				return mcp.NewToolResultText("too late"), nil
+			},
+			timeout:   20 * time.Millisecond,
+			expectErr: true,
+		},
+		{
+			name: "disabled",
+			handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				if _, ok := ctx.Deadline(); ok {
+					t.Errorf("Expected no deadline when the timeout is disabled")
+				}
+				return mcp.NewToolResultText("ok"), nil
+			},
+			timeout: 0,
+		},
+	}
+	for _, tt := range tests {
+		config.ToolTimeout = tt.timeout
+		req := mcp.CallToolRequest{}
+		req.Params.Name = "slow_tool"
+
+		start := time.Now()
+		result, err := middleware(tt.handler)(context.Background(), req)
+		if err != nil {
+			t.Fatalf("%s: Failed to call tool: %v", tt.name, err)
+		}
+		if result.IsError != tt.expectErr {
+			t.Errorf("%s: Expected IsError=%v but got %v (%s)", tt.name, tt.expectErr, result.IsError, toolResultText(result))
+		}
+		if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+			t.Errorf("%s: Expected the call to return near the deadline but it took %s", tt.name, elapsed)
+		}
+	}
+}
+
+func TestTimeoutMiddleware_Panic(t *testing.T) {
+	middleware := timeoutMiddleware(func() *Config { return &Config{ToolTimeout: time.Second} })
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "exploding_tool"
+	result, err := middleware(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		panic("boom")
+	})(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Expected a panic to become a tool error result, but got %v", err)
+	}
+	if result == nil || !result.IsError || toolErrorCodeOf(result) != errorInternal {
+		t.Errorf("Expected an INTERNAL tool error but got %+v", result)
+	}
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// maxTimesheetEntries caps the entries of one timesheet_summary call
+const maxTimesheetEntries = 1000
+
+// Flags on timesheet entries
+const (
+	flagSpansMidnight = "spans_midnight"
+	flagDSTChange     = "dst_change"
+	flagOverlap       = "overlap"
+)
+
+// timesheetInput is one clock-in/clock-out pair as given
+type timesheetInput struct {
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	Timezone string `json:"timezone"`
+}
+
+// timesheetSummary is the structured result of timesheet_summary
+type timesheetSummary struct {
+	Rounding   string           `json:"rounding" jsonschema:"Rounding applied to clock times, e.g. nearest 15m, or none"`
+	Entries    []timesheetEntry `json:"entries"`
+	Days       []timesheetDay   `json:"days"`
+	TotalHours float64          `json:"total_hours"`
+	Total      string           `json:"total" jsonschema:"Total worked time as H:MM"`
+}
+
+type timesheetEntry struct {
+	Index    int      `json:"index" jsonschema:"Position of the entry in the input, from 0"`
+	Start    string   `json:"start" jsonschema:"Rounded clock-in in RFC 3339"`
+	End      string   `json:"end" jsonschema:"Rounded clock-out in RFC 3339"`
+	Timezone string   `json:"timezone"`
+	Hours    float64  `json:"hours"`
+	Flags    []string `json:"flags,omitempty" jsonschema:"spans_midnight, dst_change (the UTC offset differs at clock-in and clock-out) or overlap (with another entry)"`
+}
+
+type timesheetDay struct {
+	Date  string  `json:"date" jsonschema:"Local date, YYYY-MM-DD; entries spanning midnight are split between days"`
+	Hours float64 `json:"hours"`
+}
+
+// roundClock rounds the wall clock time of t to a multiple of step since
+// local midnight; mode is nearest, up or down
+func roundClock(t time.Time, step time.Duration, mode string) time.Time {
+	if step <= 0 {
+		return t
+	}
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	h, m, s := t.Clock()
+	wall := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(s)*time.Second + time.Duration(t.Nanosecond())
+	var rounded time.Duration
+	switch mode {
+	case "up":
+		rounded = (wall + step - 1) / step * step
+	case "down":
+		rounded = wall / step * step
+	default:
+		rounded = (wall + step/2) / step * step
+	}
+	minutes := int(rounded / time.Minute)
+	seconds := int(rounded % time.Minute / time.Second)
+	// time.Date normalizes 24:00 to the next midnight and wall times in a DST gap
+	return time.Date(midnight.Year(), midnight.Month(), midnight.Day(), minutes/60, minutes%60, seconds, 0, t.Location())
+}
+
+// hours converts a duration to hours with two decimals
+func hours(d time.Duration) float64 {
+	return math.Round(d.Hours()*100) / 100
+}
+
+// formatHoursMinutes formats a duration as H:MM
+func formatHoursMinutes(d time.Duration) string {
+	minutes := int(d.Round(time.Minute) / time.Minute)
+	return fmt.Sprintf("%d:%02d", minutes/60, minutes%60)
+}
+
+func addTimesheetTool(mcpServer *server.MCPServer, config *Config) {
+	mcpServer.AddTool(
+		mcp.NewTool("timesheet_summary",
+			mcp.WithDescription("Total worked hours from clock-in/clock-out pairs, per local day and overall, after rounding clock times (e.g. to the nearest 15 minutes). Durations are exact across DST changes; entries spanning midnight are split between days and flagged, as are entries spanning a DST change or overlapping another."),
+			mcp.WithArray("entries",
+				mcp.Description("Clock-in/clock-out pairs: start and end in RFC 3339 or 'YYYY-MM-DD HH:MM' in the entry's timezone, and an optional timezone."),
+				mcp.Items(map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"start":    map[string]any{"type": "string"},
+						"end":      map[string]any{"type": "string"},
+						"timezone": map[string]any{"type": "string"},
+					},
+					"required": []string{"start", "end"},
+				}),
+				mcp.MinItems(1),
+				mcp.MaxItems(maxTimesheetEntries),
+				mcp.Required(),
+			),
+			mcp.WithString("timezone",
+				mcp.Description("Timezone of entries without one. Defaults to the session or server default."),
+			),
+			mcp.WithString("rounding",
+				mcp.Description("Round clock times to this increment as a Go duration, e.g. 15m or 6m. Defaults to no rounding."),
+			),
+			mcp.WithString("rounding_mode",
+				mcp.Description("How to round: nearest, up or down. Defaults to nearest."),
+				mcp.Enum("nearest", "up", "down"),
+			),
+			mcp.WithOutputSchema[timesheetSummary](),
+			mcp.WithTitleAnnotation("Timesheet Summary"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		handleTimesheetSummary(config),
+	)
+}
+
+// handleTimesheetSummary returns a handler for the timesheet_summary tool
+func handleTimesheetSummary(config *Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Entries []timesheetInput `json:"entries"`
+		}
+		if err := request.BindArguments(&args); err != nil {
+			return toolError(errorInvalidArgument, fmt.Sprintf("Invalid entries: %v", err)), nil
+		}
+		if len(args.Entries) == 0 || len(args.Entries) > maxTimesheetEntries {
+			return toolError(errorInvalidArgument, fmt.Sprintf("Provide between 1 and %d entries", maxTimesheetEntries)), nil
+		}
+		timezoneStr := request.GetString("timezone", "")
+		defaultLoc, err := loadTimezone(ctx, timezoneStr, config)
+		if err != nil {
+			return invalidTimezoneError("Invalid timezone", timezoneStr), nil
+		}
+
+		roundingStr := request.GetString("rounding", "")
+		var step time.Duration
+		if roundingStr != "" {
+			if step, err = time.ParseDuration(roundingStr); err != nil || step < time.Minute || step > time.Hour {
+				return toolError(errorParse, fmt.Sprintf("Invalid rounding: %s. Please provide a duration from 1m to 1h, e.g. 15m.", roundingStr)), nil
+			}
+		}
+		mode := request.GetString("rounding_mode", "nearest")
+		if mode != "nearest" && mode != "up" && mode != "down" {
+			return toolError(errorInvalidArgument, fmt.Sprintf("Invalid rounding_mode: %s. Please use nearest, up or down.", mode)), nil
+		}
+
+		summary := timesheetSummary{Rounding: "none", Entries: []timesheetEntry{}, Days: []timesheetDay{}}
+		if step > 0 {
+			summary.Rounding = mode + " " + step.String()
+		}
+		type span struct{ start, end time.Time }
+		spans := make([]span, len(args.Entries))
+		perDay := make(map[string]time.Duration)
+		var total time.Duration
+		for i, in := range args.Entries {
+			loc := defaultLoc
+			if in.Timezone != "" {
+				if loc, err = loadLocationCached(in.Timezone); err != nil {
+					return invalidTimezoneError(fmt.Sprintf("Invalid timezone in entry %d", i), in.Timezone), nil
+				}
+			}
+			var times [2]time.Time
+			for j, s := range []string{in.Start, in.End} {
+				t, err := time.Parse(time.RFC3339, s)
+				if err != nil {
+					if t, err = time.ParseInLocation("2006-01-02 15:04", s, loc); err != nil {
+						return toolError(errorParse, fmt.Sprintf("Invalid time in entry %d: %q. Please provide RFC 3339 or 'YYYY-MM-DD HH:MM'.", i, s)), nil
+					}
+				}
+				times[j] = roundClock(t.In(loc), step, mode)
+			}
+			start, end := times[0], times[1]
+			if end.Before(start) {
+				return toolError(errorInvalidArgument, fmt.Sprintf("Entry %d ends before it starts", i)), nil
+			}
+			spans[i] = span{start, end}
+
+			entry := timesheetEntry{
+				Index:    i,
+				Start:    start.Format(time.RFC3339),
+				End:      end.Format(time.RFC3339),
+				Timezone: loc.String(),
+				Hours:    hours(end.Sub(start)),
+			}
+			if end.After(start) && end.Add(-time.Nanosecond).Format("2006-01-02") != start.Format("2006-01-02") {
+				entry.Flags = append(entry.Flags, flagSpansMidnight)
+			}
+			_, startOffset := start.Zone()
+			_, endOffset := end.Zone()
+			if startOffset != endOffset {
+				entry.Flags = append(entry.Flags, flagDSTChange)
+			}
+			summary.Entries = append(summary.Entries, entry)
+
+			// Split the entry at local midnights
+			for t := start; t.Before(end); {
+				next := time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+				if next.After(end) {
+					next = end
+				}
+				perDay[t.Format("2006-01-02")] += next.Sub(t)
+				t = next
+			}
+			total += end.Sub(start)
+		}
+
+		for i := range spans {
+			for j := range spans {
+				if i != j && spans[i].start.Before(spans[j].end) && spans[j].start.Before(spans[i].end) {
+					summary.Entries[i].Flags = append(summary.Entries[i].Flags, flagOverlap)
+					break
+				}
+			}
+		}
+		for _, date := range slices.Sorted(maps.Keys(perDay)) {
+			summary.Days = append(summary.Days, timesheetDay{Date: date, Hours: hours(perDay[date])})
+		}
+		summary.TotalHours = hours(total)
+		summary.Total = formatHoursMinutes(total)
+
+		data, err := json.Marshal(summary)
+		if err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to encode result: %v", err)), nil
+		}
+		return mcp.NewToolResultStructured(summary, string(data)), nil
+	}
+}
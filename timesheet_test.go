@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestRoundClock(t *testing.T) {
+	warsaw, _ := time.LoadLocation("Europe/Warsaw")
+	at := time.Date(2025, 3, 10, 8, 7, 0, 0, warsaw)
+	tests := []struct {
+		t    time.Time
+		step time.Duration
+		mode string
+		want string
+	}{
+		{at, 15 * time.Minute, "nearest", "08:00"},
+		{at.Add(30 * time.Second), 15 * time.Minute, "nearest", "08:15"}, // halves round up
+		{at, 15 * time.Minute, "up", "08:15"},
+		{at, 15 * time.Minute, "down", "08:00"},
+		{at, 6 * time.Minute, "nearest", "08:06"},
+		{at, 0, "nearest", "08:07"},
+		{time.Date(2025, 3, 10, 23, 55, 0, 0, warsaw), 15 * time.Minute, "nearest", "00:00"},
+	}
+	for _, tt := range tests {
+		if got := roundClock(tt.t, tt.step, tt.mode).Format("15:04"); got != tt.want {
+			t.Errorf("Expected %v rounded %s to %v to be %s but got %s", tt.t, tt.mode, tt.step, tt.want, got)
+		}
+	}
+}
+
+func TestTimesheetSummary(t *testing.T) {
+	handler := handleTimesheetSummary(&Config{DefaultTimezone: "UTC"})
+	call := func(args map[string]any) *mcp.CallToolResult {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := handler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("Failed to call handler: %v", err)
+		}
+		return result
+	}
+
+	result := call(map[string]any{
+		"timezone": "Europe/Warsaw",
+		"rounding": "15m",
+		"entries": []any{
+			map[string]any{"start": "2025-10-24 08:58", "end": "2025-10-24 17:04"},
+			// A night shift across the fall-back change lasts an hour longer
+			map[string]any{"start": "2025-10-25 22:00", "end": "2025-10-26 06:00"},
+			map[string]any{"start": "2025-10-26T05:00:00+01:00", "end": "2025-10-26T07:00:00+01:00"},
+			map[string]any{"start": "2025-10-27 09:00", "end": "2025-10-27 13:00", "timezone": "America/New_York"},
+		},
+	})
+	if result.IsError {
+		t.Fatalf("Failed to summarize timesheet: %s", toolResultText(result))
+	}
+	summary := result.StructuredContent.(timesheetSummary)
+	wantHours := []float64{8, 9, 2, 4}
+	wantFlags := [][]string{nil, {flagSpansMidnight, flagDSTChange, flagOverlap}, {flagOverlap}, nil}
+	for i, entry := range summary.Entries {
+		if entry.Hours != wantHours[i] || !slices.Equal(entry.Flags, wantFlags[i]) {
+			t.Errorf("Expected entry %d to have %v hours and flags %v but got %+v", i, wantHours[i], wantFlags[i], entry)
+		}
+	}
+	if summary.Entries[0].Start != "2025-10-24T09:00:00+02:00" || summary.Entries[0].End != "2025-10-24T17:00:00+02:00" {
+		t.Errorf("Expected the first entry rounded to 09:00-17:00 but got %+v", summary.Entries[0])
+	}
+	wantDays := []timesheetDay{{"2025-10-24", 8}, {"2025-10-25", 2}, {"2025-10-26", 9}, {"2025-10-27", 4}}
+	if !slices.Equal(summary.Days, wantDays) {
+		t.Errorf("Expected days %v but got %v", wantDays, summary.Days)
+	}
+	if summary.TotalHours != 23 || summary.Total != "23:00" || summary.Rounding != "nearest 15m0s" {
+		t.Errorf("Expected 23:00 in total with nearest 15m rounding but got %+v", summary)
+	}
+
+	tests := []struct {
+		args map[string]any
+		code toolErrorCode
+	}{
+		{map[string]any{}, errorInvalidArgument},
+		{map[string]any{"entries": "08:00-16:00"}, errorInvalidArgument},
+		{map[string]any{"entries": []any{map[string]any{"start": "2025-10-24 17:00", "end": "2025-10-24 09:00"}}}, errorInvalidArgument},
+		{map[string]any{"entries": []any{map[string]any{"start": "yesterday", "end": "2025-10-24 09:00"}}}, errorParse},
+		{map[string]any{"entries": []any{map[string]any{"start": "2025-10-24 08:00", "end": "2025-10-24 09:00", "timezone": "Warsw"}}}, errorInvalidTimezone},
+		{map[string]any{"entries": []any{map[string]any{"start": "2025-10-24 08:00", "end": "2025-10-24 09:00"}}, "rounding": "30s"}, errorParse},
+		{map[string]any{"entries": []any{map[string]any{"start": "2025-10-24 08:00", "end": "2025-10-24 09:00"}}, "rounding_mode": "banker"}, errorInvalidArgument},
+	}
+	for _, tt := range tests {
+		if code := toolErrorCodeOf(call(tt.args)); code != tt.code {
+			t.Errorf("Expected %v to fail with %s but got %q", tt.args, tt.code, code)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2025, 3, 30, 0, 30, 0, 0, time.UTC)
+	clock := newFakeClock(start)
+	if !clock.Now().Equal(start) {
+		t.Errorf("Expected %v but got %v", start, clock.Now())
+	}
+	clock.Advance(time.Hour)
+	if want := start.Add(time.Hour); !clock.Now().Equal(want) {
+		t.Errorf("Expected %v after Advance but got %v", want, clock.Now())
+	}
+	clock.Set(start)
+	if !clock.Now().Equal(start) {
+		t.Errorf("Expected %v after Set but got %v", start, clock.Now())
+	}
+	if (&Config{}).now().IsZero() {
+		t.Errorf("Expected a Config without a clock to use the system clock")
+	}
+}
+
+func TestHandlers_FakeClock(t *testing.T) {
+	// 00:30 UTC on the morning Europe/Warsaw moves from CET to CEST
+	clock := newFakeClock(time.Date(2025, 3, 30, 0, 30, 0, 0, time.UTC))
+	config := &Config{DefaultTimezone: "UTC", Clock: clock}
+	call := func(handler func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error), args map[string]any) string {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := handler(context.Background(), request)
+		if err != nil || result.IsError {
+			t.Fatalf("Failed to call handler with %v: %v %s", args, err, toolResultText(result))
+		}
+		return toolResultText(result)
+	}
+
+	text := call(handleGetCurrentTime(config), map[string]any{"timezone": "Europe/Warsaw"})
+	if !strings.Contains(text, "2025-03-30 01:30:00") || !strings.Contains(text, "CET") {
+		t.Errorf("Expected 01:30 CET from the fake clock but got %s", text)
+	}
+	clock.Advance(time.Hour)
+	text = call(handleGetCurrentTime(config), map[string]any{"timezone": "Europe/Warsaw"})
+	if !strings.Contains(text, "2025-03-30 03:30:00") || !strings.Contains(text, "CEST") {
+		t.Errorf("Expected 03:30 CEST after the transition but got %s", text)
+	}
+
+	// A time without a date is taken on the clock's current day
+	text = call(handleConvertTime(config), map[string]any{"source_timezone": "UTC", "time": "12:00", "target_timezone": "Asia/Tokyo"})
+	if !strings.Contains(text, "2025-03-30") {
+		t.Errorf("Expected the conversion on the fake clock's date but got %s", text)
+	}
+}
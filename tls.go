@@ -0,0 +1,162 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"time"
+
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// selfSignedValidity is how long a generated development certificate stays valid
+const selfSignedValidity = 24 * time.Hour
+
+// clientAuthModes maps TIME_HTTP_TLS_CLIENT_AUTH values to TLS client auth policies
+var clientAuthModes = map[string]tls.ClientAuthType{
+	"require":         tls.RequireAndVerifyClientCert,
+	"verify_if_given": tls.VerifyClientCertIfGiven,
+}
+
+// tlsEnabled reports whether the HTTP transport should be served over TLS
+func tlsEnabled(config *Config) bool {
+	return config.HTTPTLSSelfSigned || (config.HTTPTLSCert != "" && config.HTTPTLSKey != "") || acmeEnabled(config)
+}
+
+// acmeEnabled reports whether certificates are obtained automatically via ACME
+func acmeEnabled(config *Config) bool {
+	return len(config.HTTPACMEDomains) > 0
+}
+
+// newACMEManager creates an autocert manager restricted to the configured domains
+func newACMEManager(config *Config) *autocert.Manager {
+	return &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(config.HTTPACMEDomains...),
+		Cache:      autocert.DirCache(config.HTTPACMECacheDir),
+		Email:      config.HTTPACMEEmail,
+	}
+}
+
+// newTLSConfig builds a TLS configuration with modern defaults.
+// Only TLS 1.2+ with AEAD cipher suites is accepted; TLS 1.3 suites are not
+// configurable in Go and are always secure. When manager is non-nil,
+// certificates are obtained from it instead of from files.
+func newTLSConfig(config *Config, manager *autocert.Manager) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		CurvePreferences: []tls.CurveID{
+			tls.X25519,
+			tls.CurveP256,
+		},
+		CipherSuites: []uint16{
+			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+		},
+	}
+
+	if config.HTTPTLSClientCA != "" {
+		pool, err := loadCertPool(config.HTTPTLSClientCA)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = clientAuthModes[config.HTTPTLSClientAuth]
+	}
+
+	if manager != nil {
+		tlsConfig.GetCertificate = manager.GetCertificate
+		tlsConfig.NextProtos = []string{"h2", "http/1.1", acme.ALPNProto}
+		return tlsConfig, nil
+	}
+
+	if config.HTTPTLSCert != "" && config.HTTPTLSKey != "" {
+		cert, err := tls.LoadX509KeyPair(config.HTTPTLSCert, config.HTTPTLSKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+		return tlsConfig, nil
+	}
+
+	cert, err := generateSelfSignedCert([]string{"localhost", "127.0.0.1", "::1"}, selfSignedValidity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate self-signed certificate: %w", err)
+	}
+	tlsConfig.Certificates = []tls.Certificate{cert}
+	return tlsConfig, nil
+}
+
+// generateSelfSignedCert creates an in-memory ECDSA certificate for development use
+func generateSelfSignedCert(hosts []string, validFor time.Duration) (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	now := time.Now()
+	template := x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{Organization: []string{"TimeMCP development"}},
+		NotBefore:             now.Add(-time.Minute),
+		NotAfter:              now.Add(validFor),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+	}
+	for _, h := range hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, h)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	return tls.Certificate{
+		Certificate: [][]byte{der},
+		PrivateKey:  key,
+	}, nil
+}
+
+// loadCertPool reads a PEM bundle of CA certificates
+func loadCertPool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client CA bundle: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no valid certificates found in client CA bundle %s", path)
+	}
+	return pool, nil
+}
+
+// clientCertIdentity returns the subject of a verified client certificate, if any
+func clientCertIdentity(state *tls.ConnectionState) (*x509.Certificate, bool) {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return nil, false
+	}
+	return state.VerifiedChains[0][0], true
+}
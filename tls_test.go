@@ -0,0 +1,51 @@
+package main
+
+import (
+	"crypto/x509"
+	"testing"
+	"time"
+)
+
+func TestGenerateSelfSignedCert(t *testing.T) {
+	cert, err := generateSelfSignedCert([]string{"localhost", "127.0.0.1"}, time.Hour)
+	if err != nil {
+		t.Fatalf("Failed to generate certificate: %v", err)
+	}
+
+	parsed, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %v", err)
+	}
+
+	if len(parsed.DNSNames) != 1 || parsed.DNSNames[0] != "localhost" {
+		t.Errorf("Expected DNS names [localhost], but got %v", parsed.DNSNames)
+	}
+	if len(parsed.IPAddresses) != 1 || parsed.IPAddresses[0].String() != "127.0.0.1" {
+		t.Errorf("Expected IP addresses [127.0.0.1], but got %v", parsed.IPAddresses)
+	}
+	if parsed.NotAfter.Before(time.Now().Add(59 * time.Minute)) {
+		t.Errorf("Expected certificate to be valid for about an hour, but NotAfter is %v", parsed.NotAfter)
+	}
+}
+
+func TestNewTLSConfig_SelfSigned(t *testing.T) {
+	config := &Config{HTTPTLSSelfSigned: true}
+	if !tlsEnabled(config) {
+		t.Fatal("Expected TLS to be enabled for self-signed mode")
+	}
+
+	tlsConfig, err := newTLSConfig(config, nil)
+	if err != nil {
+		t.Fatalf("Failed to create TLS config: %v", err)
+	}
+	if len(tlsConfig.Certificates) != 1 {
+		t.Errorf("Expected one certificate, but got %d", len(tlsConfig.Certificates))
+	}
+}
+
+func TestNewTLSConfig_MissingFiles(t *testing.T) {
+	config := &Config{HTTPTLSCert: "/nonexistent/cert.pem", HTTPTLSKey: "/nonexistent/key.pem"}
+	if _, err := newTLSConfig(config, nil); err == nil {
+		t.Fatal("Expected an error for missing certificate files, but got nil")
+	}
+}
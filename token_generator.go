@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// reservedClaims are set by the generator and cannot be overridden with --token-claim
+var reservedClaims = []string{"jti", "iss", "aud", "exp", "iat", "nbf", "user_id", "username", "role", "scope"}
+
+// tokenOptions describes a token to generate
+type tokenOptions struct {
+	UserID     string
+	Username   string
+	Role       string
+	Expiration time.Duration
+	// NotBefore delays the token's validity relative to the issue time
+	NotBefore time.Duration
+	// Issuer and Audience override the middleware's values when set
+	Issuer   string
+	Audience string
+	Scopes   []string
+	Extra    map[string]any
+}
+
+// generateToken signs a token with the current HS256 key and returns it with its claims
+func (a *AuthMiddleware) generateToken(opts tokenOptions) (string, jwt.MapClaims, error) {
+	signingKey := a.secretKeys.current()
+	if len(signingKey.Secret) == 0 {
+		return "", nil, fmt.Errorf("token generation requires an HS256 secret key")
+	}
+	jti, err := newTokenID()
+	if err != nil {
+		return "", nil, err
+	}
+
+	issuer, audience := a.issuer, a.audience
+	if opts.Issuer != "" {
+		issuer = opts.Issuer
+	}
+	if opts.Audience != "" {
+		audience = opts.Audience
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{}
+	for key, value := range opts.Extra {
+		claims[key] = value
+	}
+	claims["jti"] = jti
+	claims["iss"] = issuer
+	claims["aud"] = []string{audience}
+	claims["exp"] = now.Add(opts.Expiration).Unix()
+	claims["iat"] = now.Unix()
+	claims["nbf"] = now.Add(opts.NotBefore).Unix()
+	claims["user_id"] = opts.UserID
+	claims["username"] = opts.Username
+	claims["role"] = opts.Role
+	if len(opts.Scopes) > 0 {
+		claims["scope"] = strings.Join(opts.Scopes, " ")
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	if signingKey.ID != "" {
+		token.Header["kid"] = signingKey.ID
+	}
+	signed, err := token.SignedString(signingKey.Secret)
+	if err != nil {
+		return "", nil, err
+	}
+	return signed, claims, nil
+}
+
+// claimFlag collects repeated --token-claim key=value flags. Values that parse
+// as JSON (numbers, booleans, arrays, objects) keep their type; anything else
+// is a string.
+type claimFlag map[string]any
+
+func (c claimFlag) String() string {
+	pairs := make([]string, 0, len(c))
+	for key, value := range c {
+		pairs = append(pairs, fmt.Sprintf("%s=%v", key, value))
+	}
+	slices.Sort(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (c claimFlag) Set(value string) error {
+	key, raw, ok := strings.Cut(value, "=")
+	key = strings.TrimSpace(key)
+	if !ok || key == "" {
+		return fmt.Errorf("expected key=value, got %q", value)
+	}
+	if slices.Contains(reservedClaims, key) {
+		return fmt.Errorf("claim %q is set by the generator; use the dedicated flag instead", key)
+	}
+	var parsed any
+	if err := json.Unmarshal([]byte(raw), &parsed); err != nil {
+		parsed = raw
+	}
+	c[key] = parsed
+	return nil
+}
+
+// parseScopes splits a comma- or space-separated scope list
+func parseScopes(str string) []string {
+	return strings.FieldsFunc(str, func(r rune) bool { return r == ',' || r == ' ' })
+}
+
+// CreateTokenCommand generates a token from the command line. With jsonOutput
+// it prints the token and its claims as JSON on stdout for scripting.
+func CreateTokenCommand(secretKey, keyring string, opts tokenOptions, jsonOutput bool) error {
+	if secretKey == "" && keyring == "" {
+		return fmt.Errorf("TIME_AUTH_SECRET_KEY or TIME_AUTH_SECRET_KEYS environment variable is required")
+	}
+
+	auth, err := newSecretAuthMiddleware(secretKey, keyring, true, defaultAuthIssuer, defaultAuthAudience)
+	if err != nil {
+		return fmt.Errorf("error creating auth middleware: %w", err)
+	}
+
+	token, claims, err := auth.generateToken(opts)
+	if err != nil {
+		return fmt.Errorf("error generating token: %w", err)
+	}
+
+	if jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(map[string]any{"token": token, "claims": claims})
+	}
+
+	fmt.Fprintf(os.Stderr, "Generated JWT token:\n%s\n\n", token)
+	fmt.Fprintf(os.Stderr, "Token details:\n")
+	fmt.Fprintf(os.Stderr, "  User ID: %s\n", opts.UserID)
+	fmt.Fprintf(os.Stderr, "  Username: %s\n", opts.Username)
+	fmt.Fprintf(os.Stderr, "  Role: %s\n", opts.Role)
+	fmt.Fprintf(os.Stderr, "  Token ID (jti): %s\n", claims["jti"])
+	fmt.Fprintf(os.Stderr, "  Issuer: %s, Audience: %v\n", claims["iss"], claims["aud"])
+	if len(opts.Scopes) > 0 {
+		fmt.Fprintf(os.Stderr, "  Scopes: %s\n", claims["scope"])
+	}
+	for key, value := range opts.Extra {
+		fmt.Fprintf(os.Stderr, "  %s: %v\n", key, value)
+	}
+	fmt.Fprintf(os.Stderr, "  Valid from: %s\n", time.Unix(claims["nbf"].(int64), 0).Format(time.RFC3339))
+	fmt.Fprintf(os.Stderr, "  Expires: %s\n", time.Unix(claims["exp"].(int64), 0).Format(time.RFC3339))
+	fmt.Fprintf(os.Stderr, "\nTo use this token, include it in HTTP requests:\n")
+	fmt.Fprintf(os.Stderr, "  Authorization: Bearer %s\n", token)
+	return nil
+}
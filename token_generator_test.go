@@ -0,0 +1,73 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestClaimFlag(t *testing.T) {
+	claims := claimFlag{}
+	for _, value := range []string{"team=ops", "tier=3", "beta=true", `tags=["a","b"]`} {
+		if err := claims.Set(value); err != nil {
+			t.Fatalf("Failed to set claim %q: %v", value, err)
+		}
+	}
+	if claims["team"] != "ops" || claims["tier"] != float64(3) || claims["beta"] != true {
+		t.Errorf("Unexpected claim values: %v", claims)
+	}
+	if tags, ok := claims["tags"].([]any); !ok || len(tags) != 2 {
+		t.Errorf("Expected tags to be a two-element array, but got %v", claims["tags"])
+	}
+
+	for _, value := range []string{"novalue", "=x", "exp=1", "role=admin"} {
+		if err := claims.Set(value); err == nil {
+			t.Errorf("Expected an error for %q", value)
+		}
+	}
+}
+
+func TestGenerateTokenWithOptions(t *testing.T) {
+	auth, err := NewAuthMiddleware("generator-secret-that-is-at-least-32-chars", true, "TimeMCP", "TimeMCP-user")
+	if err != nil {
+		t.Fatalf("Failed to create auth middleware: %v", err)
+	}
+
+	token, claims, err := auth.generateToken(tokenOptions{
+		UserID:     "1",
+		Username:   "testuser",
+		Role:       "user",
+		Expiration: time.Hour,
+		NotBefore:  10 * time.Minute,
+		Scopes:     parseScopes("time:read, time:convert"),
+		Extra:      map[string]any{"team": "ops"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+	if claims["scope"] != "time:read time:convert" || claims["team"] != "ops" {
+		t.Errorf("Unexpected claims: %v", claims)
+	}
+
+	// The token is not valid until the not-before offset has passed
+	if _, err := auth.validateJWT(token); err == nil {
+		t.Error("Expected token with a future not-before to be rejected")
+	}
+
+	token, _, err = auth.generateToken(tokenOptions{UserID: "1", Username: "testuser", Role: "user", Expiration: time.Hour, Audience: "other"})
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+	if _, err := auth.validateJWT(token); err == nil {
+		t.Error("Expected token for another audience to be rejected")
+	}
+
+	parsed := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(token, parsed); err != nil {
+		t.Fatalf("Failed to parse token: %v", err)
+	}
+	if parsed["iss"] != "TimeMCP" {
+		t.Errorf("Expected default issuer, but got %v", parsed["iss"])
+	}
+}
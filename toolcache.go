@@ -0,0 +1,161 @@
+package main
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// cacheableTools lists the tools whose results are cached and, for each, the
+// arguments that must be given for a call to depend only on its arguments.
+// Without them the tool falls back to today, the current year or a session
+// or tenant default, so the call is not cached.
+var cacheableTools = map[string][]string{
+	"abbreviation_history": {"timezone", "to_year"},
+	"academic_term":        {"date"},
+	"call_time_heatmap":    {"date", "reference_timezone"},
+	"check_business_day":   {"calendar", "date"},
+	"date_add":             {"date", "timezone"},
+	"date_facts":           {"date", "timezone"},
+	"date_line_check":      {"source_timezone", "target_timezone", "date", "time"},
+	"day_schedule_preview": {"date", "timezone"},
+	"dst_calendar":         {"from"},
+	"export_vtimezone":     {"timezone", "from_year"},
+	"interval_convert":     {},
+	"parse_posix_tz":       {"year"},
+	"solar_schedule":       {"timezone", "from"},
+	"week_parity":          {"date", "timezone"},
+	"year_calendar":        {"year", "timezone", "language"},
+}
+
+// toolResultCache is an LRU cache of tool results with a TTL. Limits are
+// passed on each call so they follow config reloads.
+type toolResultCache struct {
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // most recently used first
+}
+
+type cachedToolResult struct {
+	key     string
+	result  *mcp.CallToolResult
+	expires time.Time
+}
+
+func newToolResultCache() *toolResultCache {
+	return &toolResultCache{entries: make(map[string]*list.Element), order: list.New()}
+}
+
+// get returns a copy of the cached result for key, if it has not expired
+func (c *toolResultCache) get(key string) (*mcp.CallToolResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*cachedToolResult)
+	if time.Now().After(entry.expires) {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return cloneToolResult(entry.result), true
+}
+
+// put stores a copy of result, evicting the least recently used entries
+// beyond maxEntries
+func (c *toolResultCache) put(key string, result *mcp.CallToolResult, ttl time.Duration, maxEntries int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry := &cachedToolResult{key: key, result: cloneToolResult(result), expires: time.Now().Add(ttl)}
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = entry
+		c.order.MoveToFront(elem)
+	} else {
+		c.entries[key] = c.order.PushFront(entry)
+	}
+	for c.order.Len() > maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cachedToolResult).key)
+	}
+}
+
+// cloneToolResult copies the parts of a result that later middleware may
+// change, such as the metadata the deprecation stage adds
+func cloneToolResult(r *mcp.CallToolResult) *mcp.CallToolResult {
+	c := *r
+	c.Content = slices.Clone(r.Content)
+	if r.Meta != nil {
+		meta := *r.Meta
+		meta.AdditionalFields = maps.Clone(r.Meta.AdditionalFields)
+		c.Meta = &meta
+	}
+	return &c
+}
+
+// toolCacheKey returns the cache key of a call, or false when the call is
+// not cacheable. The key includes the dataset versions, so reloading a
+// calendar does not serve results computed from the old one.
+func toolCacheKey(config *Config, req mcp.CallToolRequest) (string, bool) {
+	pinned, ok := cacheableTools[req.Params.Name]
+	if !ok {
+		return "", false
+	}
+	args := req.GetArguments()
+	for _, name := range pinned {
+		if v, ok := args[name]; !ok || v == nil || v == "" {
+			return "", false
+		}
+	}
+	// Map keys are sorted when marshaled, so equal arguments give equal keys
+	encoded, err := json.Marshal(args)
+	if err != nil {
+		return "", false
+	}
+	var key strings.Builder
+	key.WriteString(req.Params.Name)
+	key.WriteByte(0)
+	key.Write(encoded)
+	versions := config.datasets().Versions
+	for _, name := range slices.Sorted(maps.Keys(versions)) {
+		key.WriteByte(0)
+		key.WriteString(name + "=" + versions[name].SHA256)
+	}
+	return key.String(), true
+}
+
+// cacheMiddleware serves repeated deterministic calls from cache. Only
+// successful results are cached; TIME_TOOL_CACHE_SIZE=0 disables it.
+func cacheMiddleware(config func() *Config, cache *toolResultCache) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			c := config()
+			if c.ToolCacheSize <= 0 || c.ToolCacheTTL <= 0 {
+				return next(ctx, req)
+			}
+			key, ok := toolCacheKey(c, req)
+			if !ok {
+				return next(ctx, req)
+			}
+			if result, ok := cache.get(key); ok {
+				return result, nil
+			}
+			result, err := next(ctx, req)
+			if err == nil && result != nil && !result.IsError {
+				cache.put(key, result, c.ToolCacheTTL, c.ToolCacheSize)
+			}
+			return result, err
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestCacheMiddleware(t *testing.T) {
+	config := &Config{ToolCacheSize: 2, ToolCacheTTL: time.Minute}
+	calls := 0
+	handler := cacheMiddleware(func() *Config { return config }, newToolResultCache())(
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			calls++
+			if req.GetString("date", "") == "bad" {
+				return toolError(errorParse, "Invalid date"), nil
+			}
+			return mcp.NewToolResultText("ok"), nil
+		})
+	call := func(name string, args map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		req := mcp.CallToolRequest{}
+		req.Params.Name = name
+		req.Params.Arguments = args
+		result, err := handler(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return result
+	}
+	facts := func(date string) map[string]any {
+		return map[string]any{"date": date, "timezone": "Europe/Warsaw"}
+	}
+
+	first := call("date_facts", facts("2026-03-10"))
+	first.Meta = &mcp.Meta{AdditionalFields: map[string]any{"changed": true}}
+	if second := call("date_facts", facts("2026-03-10")); calls != 1 || second.Meta != nil {
+		t.Errorf("Expected an unchanged cached result without a second call, got %d calls and %+v", calls, second.Meta)
+	}
+
+	testCases := []struct {
+		name string
+		tool string
+		args map[string]any
+	}{
+		{"Defaults To Today", "date_facts", map[string]any{"timezone": "Europe/Warsaw"}},
+		{"Session Timezone", "date_facts", map[string]any{"date": "2026-03-10"}},
+		{"Not Deterministic", "get_current_time", map[string]any{"timezone": "UTC"}},
+		{"Error Result", "date_facts", facts("bad")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			before := calls
+			call(tc.tool, tc.args)
+			call(tc.tool, tc.args)
+			if calls != before+2 {
+				t.Errorf("Expected both calls to reach the tool but got %d", calls-before)
+			}
+		})
+	}
+
+	// The least recently used entry is evicted beyond the size limit
+	calls = 0
+	call("date_facts", facts("2026-03-11"))
+	call("date_facts", facts("2026-03-12"))
+	call("date_facts", facts("2026-03-10"))
+	if calls != 3 {
+		t.Errorf("Expected the oldest entry to be evicted but got %d calls", calls)
+	}
+
+	// Reloading a dataset invalidates results computed from it
+	calls = 0
+	config.Datasets = &datasetRegistry{}
+	config.Datasets.current.Store(&datasetSet{Versions: map[string]datasetVersion{datasetCalendars: {SHA256: "abc"}}})
+	call("date_facts", facts("2026-03-12"))
+	if calls != 1 {
+		t.Errorf("Expected a dataset change to miss the cache but got %d calls", calls)
+	}
+
+	config.ToolCacheTTL = time.Nanosecond
+	call("date_facts", facts("2026-03-13"))
+	time.Sleep(time.Millisecond)
+	call("date_facts", facts("2026-03-13"))
+	if calls != 3 {
+		t.Errorf("Expected expired entries to be recomputed but got %d calls", calls)
+	}
+}
+
+func TestCacheableToolsMatchSchemas(t *testing.T) {
+	config := &Config{
+		Calendars: map[string]*businessCalendar{"acme": {Name: "acme", Location: time.UTC}},
+		Terms:     map[string]*termSchedule{"uni": {Name: "uni", Location: time.UTC}},
+	}
+	mcpServer := server.NewMCPServer("TimeMCP", "test", server.WithToolCapabilities(true))
+	addTools(mcpServer, config)
+	tools := mcpServer.ListTools()
+	for name, pinned := range cacheableTools {
+		tool, ok := tools[name]
+		if !ok {
+			t.Errorf("Cacheable tool %s is not registered", name)
+			continue
+		}
+		for _, arg := range pinned {
+			if _, ok := tool.Tool.InputSchema.Properties[arg]; !ok {
+				t.Errorf("Tool %s has no argument %s", name, arg)
+			}
+		}
+	}
+}
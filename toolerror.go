@@ -0,0 +1,90 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// toolErrorCode classifies a failed tool call so clients can branch on it
+// instead of matching the English message
+type toolErrorCode string
+
+const (
+	// errorInvalidArgument is a missing, conflicting or out-of-range argument
+	errorInvalidArgument toolErrorCode = "INVALID_ARGUMENT"
+	// errorInvalidTimezone is a timezone name the tz database does not know
+	errorInvalidTimezone toolErrorCode = "INVALID_TIMEZONE"
+	// errorAmbiguousTime is a wall time that a DST transition skips or repeats
+	errorAmbiguousTime toolErrorCode = "AMBIGUOUS_TIME"
+	// errorParse is a date, time, cron expression or document that does not parse
+	errorParse toolErrorCode = "PARSE_ERROR"
+	// errorNotFound is an unknown calendar, job, subscription or session
+	errorNotFound toolErrorCode = "NOT_FOUND"
+	// errorSessionRequired is a call that needs a stateful MCP session
+	errorSessionRequired toolErrorCode = "SESSION_REQUIRED"
+	// errorLimitExceeded is a per-user or per-session limit on stored objects
+	errorLimitExceeded toolErrorCode = "LIMIT_EXCEEDED"
+	// errorUnauthorized is a missing or invalid credential
+	errorUnauthorized toolErrorCode = "UNAUTHORIZED"
+	// errorForbidden is a caller whose role or tenant may not call the tool
+	errorForbidden toolErrorCode = "FORBIDDEN"
+	// errorRateLimited is a caller over its usage quota
+	errorRateLimited toolErrorCode = "RATE_LIMITED"
+	// errorTimeout is a call that ran past its deadline
+	errorTimeout toolErrorCode = "TIMEOUT"
+	// errorToolFailed is a failure reported by an external tool plugin
+	errorToolFailed toolErrorCode = "TOOL_FAILED"
+	// errorInternal is a server fault, such as a storage or encoding failure
+	errorInternal toolErrorCode = "INTERNAL"
+)
+
+// toolErrorDetail is the structured content of an error result:
+// {"error": {"code": "INVALID_TIMEZONE", "message": "Invalid timezone: Mars/Olympus"}}
+type toolErrorDetail struct {
+	Error toolErrorBody `json:"error"`
+}
+
+type toolErrorBody struct {
+	Code    toolErrorCode `json:"code"`
+	Message string        `json:"message"`
+	// Suggestions are valid values close to a rejected one, e.g. zone names
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+// toolError returns an error result carrying message as text, for clients
+// that only read content, and with its code as structured content
+func toolError(code toolErrorCode, message string) *mcp.CallToolResult {
+	result := mcp.NewToolResultError(message)
+	result.StructuredContent = toolErrorDetail{Error: toolErrorBody{Code: code, Message: message}}
+	return result
+}
+
+// toolErrorCodeOf returns the code of an error result, or "" for results
+// without one, such as errors from registered tools
+func toolErrorCodeOf(result *mcp.CallToolResult) toolErrorCode {
+	if detail, ok := result.StructuredContent.(toolErrorDetail); ok && result.IsError {
+		return detail.Error.Code
+	}
+	return ""
+}
+
+// httpStatus maps a code to the status the REST API answers with; codes for
+// bad input, and errors without a code, are 400
+func (c toolErrorCode) httpStatus() int {
+	switch c {
+	case errorUnauthorized:
+		return http.StatusUnauthorized
+	case errorForbidden:
+		return http.StatusForbidden
+	case errorNotFound:
+		return http.StatusNotFound
+	case errorRateLimited:
+		return http.StatusTooManyRequests
+	case errorTimeout:
+		return http.StatusGatewayTimeout
+	case errorInternal:
+		return http.StatusInternalServerError
+	}
+	return http.StatusBadRequest
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestToolError(t *testing.T) {
+	result := toolError(errorInvalidTimezone, "Invalid timezone: Mars/Olympus")
+	if !result.IsError || toolResultText(result) != "Invalid timezone: Mars/Olympus" {
+		t.Errorf("Expected an error result with the message as text but got %+v", result)
+	}
+	data, err := json.Marshal(result.StructuredContent)
+	if err != nil {
+		t.Fatalf("Failed to encode structured content: %v", err)
+	}
+	if string(data) != `{"error":{"code":"INVALID_TIMEZONE","message":"Invalid timezone: Mars/Olympus"}}` {
+		t.Errorf("Expected the code as structured content but got %s", data)
+	}
+	if code := toolErrorCodeOf(result); code != errorInvalidTimezone {
+		t.Errorf("Expected code INVALID_TIMEZONE but got %q", code)
+	}
+	if code := toolErrorCodeOf(mcp.NewToolResultError("plain")); code != "" {
+		t.Errorf("Expected no code for a plain error but got %q", code)
+	}
+
+	statuses := map[toolErrorCode]int{
+		errorUnauthorized:    http.StatusUnauthorized,
+		errorForbidden:       http.StatusForbidden,
+		errorRateLimited:     http.StatusTooManyRequests,
+		errorNotFound:        http.StatusNotFound,
+		errorTimeout:         http.StatusGatewayTimeout,
+		errorInternal:        http.StatusInternalServerError,
+		errorParse:           http.StatusBadRequest,
+		"":                   http.StatusBadRequest,
+		errorInvalidArgument: http.StatusBadRequest,
+	}
+	for code, want := range statuses {
+		if got := code.httpStatus(); got != want {
+			t.Errorf("Expected status %d for %q but got %d", want, code, got)
+		}
+	}
+}
+
+func TestToolErrorCodes_Handlers(t *testing.T) {
+	config := &Config{DefaultTimezone: "UTC"}
+	tests := []struct {
+		name    string
+		handler func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error)
+		args    map[string]any
+		want    toolErrorCode
+	}{
+		{"invalid timezone", handleGetCurrentTime(config), map[string]any{"timezone": "Mars/Olympus"}, errorInvalidTimezone},
+		{"missing target", handleConvertTime(config), map[string]any{}, errorInvalidArgument},
+		{"bad time", handleConvertTime(config), map[string]any{"time": "25:99", "target_timezone": "UTC"}, errorParse},
+		{"bad cron", handleDescribeCron(config), map[string]any{"expression": "not a cron"}, errorParse},
+		{"unknown calendar", handleCheckBusinessDay(config), map[string]any{"calendar": "nope"}, errorNotFound},
+	}
+	for _, tt := range tests {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = tt.args
+		result, err := tt.handler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("%s: Failed to call handler: %v", tt.name, err)
+		}
+		if code := toolErrorCodeOf(result); code != tt.want {
+			t.Errorf("%s: Expected code %s but got %q (%s)", tt.name, tt.want, code, toolResultText(result))
+		}
+	}
+}
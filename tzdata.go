@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// tzdataHint explains how to provide zoneinfo in minimal images
+const tzdataHint = "install the tzdata package, point ZONEINFO at a zoneinfo directory or zip, or build with -tags timetzdata to embed the database"
+
+// prepareTimezones fails fast when the tz database is missing and loads the
+// configured zones into the location cache so first calls don't pay for it
+func prepareTimezones(config *Config) error {
+	if config.TZValidate {
+		if err := checkTimezoneData(config); err != nil {
+			return fmt.Errorf("%w (%s)", err, tzdataHint)
+		}
+	}
+	for _, name := range config.TZPreload {
+		if _, err := loadLocationCached(name); err != nil {
+			return fmt.Errorf("invalid TIME_TZ_PRELOAD entry %q: %w", name, err)
+		}
+	}
+	if len(config.TZPreload) > 0 {
+		slog.Info("Preloaded timezones", "count", len(config.TZPreload))
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPrepareTimezones(t *testing.T) {
+	config := &Config{TZValidate: true, TZPreload: []string{"Asia/Kolkata", "UTC"}}
+	if err := prepareTimezones(config); err != nil {
+		t.Fatalf("Failed to prepare timezones: %v", err)
+	}
+	if _, cached := locationCache.Load("Asia/Kolkata"); !cached {
+		t.Errorf("Expected preloaded zone to be cached")
+	}
+
+	config.TZPreload = []string{"UTC", "Atlantis/Capital"}
+	err := prepareTimezones(config)
+	if err == nil || !strings.Contains(err.Error(), "Atlantis/Capital") {
+		t.Errorf("Expected an error naming the unknown zone but got %v", err)
+	}
+}
+
+func TestPrepareTimezones_ValidationHint(t *testing.T) {
+	config := &Config{TZValidate: true, DefaultTimezone: "Nowhere/Special"}
+	err := prepareTimezones(config)
+	if err == nil || !strings.Contains(err.Error(), "timetzdata") {
+		t.Errorf("Expected a failure with the tzdata hint but got %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"archive/zip"
+	"cmp"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// maxTimezoneSuggestions caps the "Did you mean" list
+	maxTimezoneSuggestions = 3
+	// maxSuggestionInput skips suggestions for inputs no zone name resembles,
+	// which also bounds the edit distance work per request
+	maxSuggestionInput = 64
+)
+
+// zoneinfoDirs are where Go looks for the system tz database on Unix
+var zoneinfoDirs = []string{"/usr/share/zoneinfo", "/usr/share/lib/zoneinfo", "/usr/lib/locale/TZ", "/etc/zoneinfo"}
+
+// timezoneNames lists the zone names of the tz database Go loads from, or
+// nil when it is only embedded with -tags timetzdata, which cannot be listed
+var timezoneNames = sync.OnceValue(func() []string {
+	sources := zoneinfoDirs
+	if dir := os.Getenv("ZONEINFO"); dir != "" {
+		sources = append([]string{dir}, sources...)
+	}
+	for _, source := range sources {
+		if names := listZoneinfo(source); len(names) > 0 {
+			slices.Sort(names)
+			return names
+		}
+	}
+	return nil
+})
+
+// listZoneinfo returns the zone names in a zoneinfo directory or zip
+func listZoneinfo(source string) []string {
+	var names []string
+	if strings.HasSuffix(source, ".zip") {
+		r, err := zip.OpenReader(source)
+		if err != nil {
+			return nil
+		}
+		defer r.Close()
+		for _, f := range r.File {
+			if isZoneName(f.Name) {
+				names = append(names, f.Name)
+			}
+		}
+		return names
+	}
+	filepath.WalkDir(source, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		name, _ := filepath.Rel(source, path)
+		name = filepath.ToSlash(name)
+		if d.IsDir() && (name == "posix" || name == "right") {
+			return filepath.SkipDir
+		}
+		if d.Type().IsRegular() || d.Type()&fs.ModeSymlink != 0 {
+			if isZoneName(name) {
+				names = append(names, name)
+			}
+		}
+		return nil
+	})
+	return names
+}
+
+// isZoneName tells zone files apart from the database's tables and metadata
+// (zone1970.tab, tzdata.zi, +VERSION, posixrules)
+func isZoneName(name string) bool {
+	return name != "" && name[0] >= 'A' && name[0] <= 'Z' && !strings.Contains(name, ".") && name != "Factory"
+}
+
+// suggestTimezones returns the zone names closest to an invalid input by
+// edit distance against the full name or its city, e.g. "Warsw" gives
+// Europe/Warsaw
+func suggestTimezones(input string) []string {
+	needle := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(input), " ", "_"))
+	if needle == "" || len(needle) > maxSuggestionInput {
+		return nil
+	}
+	// Allow about one typo per three characters, and at least two
+	limit := max(2, len(needle)/3)
+	type candidate struct {
+		name     string
+		distance int
+	}
+	var candidates []candidate
+	for _, name := range timezoneNames() {
+		lower := strings.ToLower(name)
+		distance := editDistance(needle, lower)
+		if _, city, ok := strings.Cut(lower, "/"); ok && !strings.Contains(needle, "/") {
+			distance = min(distance, editDistance(needle, city[strings.LastIndex(city, "/")+1:]))
+		}
+		if distance <= limit {
+			candidates = append(candidates, candidate{name, distance})
+		}
+	}
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		return cmp.Or(cmp.Compare(a.distance, b.distance), cmp.Compare(a.name, b.name))
+	})
+	var suggestions []string
+	for _, c := range candidates[:min(len(candidates), maxTimezoneSuggestions)] {
+		suggestions = append(suggestions, c.name)
+	}
+	return suggestions
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// didYouMean formats suggestions as " Did you mean A, B or C?"
+func didYouMean(suggestions []string) string {
+	switch len(suggestions) {
+	case 0:
+		return ""
+	case 1:
+		return fmt.Sprintf(" Did you mean %s?", suggestions[0])
+	}
+	last := len(suggestions) - 1
+	return fmt.Sprintf(" Did you mean %s or %s?", strings.Join(suggestions[:last], ", "), suggestions[last])
+}
+
+// invalidTimezoneError reports an unknown zone with the closest matches,
+// e.g. "Invalid source timezone: Warsw. Did you mean Europe/Warsaw?"; label
+// names the argument
+func invalidTimezoneError(label, name string) *mcp.CallToolResult {
+	suggestions := suggestTimezones(name)
+	message := fmt.Sprintf("%s: %s", label, name)
+	if len(suggestions) > 0 {
+		message += "." + didYouMean(suggestions)
+	}
+	result := toolError(errorInvalidTimezone, message)
+	detail := result.StructuredContent.(toolErrorDetail)
+	detail.Error.Suggestions = suggestions
+	result.StructuredContent = detail
+	return result
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"warsw", "warsaw", 1},
+		{"kitten", "sitting", 3},
+		{"tokyo", "", 5},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("Expected distance %d between %q and %q but got %d", tt.want, tt.a, tt.b, got)
+		}
+	}
+}
+
+func TestSuggestTimezones(t *testing.T) {
+	if len(timezoneNames()) == 0 {
+		t.Skip("No listable tz database on this system")
+	}
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"Warsw", "Europe/Warsaw"},
+		{"europe/warsaw", "Europe/Warsaw"},
+		{"Europe/Warszawa", "Europe/Warsaw"},
+		{"new york", "America/New_York"},
+		{"Asia/Tokio", "Asia/Tokyo"},
+	}
+	for _, tt := range tests {
+		suggestions := suggestTimezones(tt.input)
+		if len(suggestions) == 0 || suggestions[0] != tt.want {
+			t.Errorf("Expected %s first for %q but got %v", tt.want, tt.input, suggestions)
+		}
+		if len(suggestions) > maxTimezoneSuggestions {
+			t.Errorf("Expected at most %d suggestions but got %v", maxTimezoneSuggestions, suggestions)
+		}
+	}
+	if suggestions := suggestTimezones("Mars/Olympus Mons"); len(suggestions) != 0 {
+		t.Errorf("Expected no suggestions for an unrelated name but got %v", suggestions)
+	}
+	if slices.ContainsFunc(timezoneNames(), func(name string) bool { return strings.HasPrefix(name, "posix/") || strings.Contains(name, ".") }) {
+		t.Errorf("Expected only zone names in the list")
+	}
+
+	result := invalidTimezoneError("Invalid source timezone", "Warsw")
+	if text := toolResultText(result); !strings.HasPrefix(text, "Invalid source timezone: Warsw. Did you mean Europe/Warsaw") {
+		t.Errorf("Expected a suggestion in the message but got %q", text)
+	}
+	data, _ := json.Marshal(result.StructuredContent)
+	if !strings.Contains(string(data), `"suggestions":["Europe/Warsaw"`) || toolErrorCodeOf(result) != errorInvalidTimezone {
+		t.Errorf("Expected structured suggestions but got %s", data)
+	}
+}
+
+func TestDidYouMean(t *testing.T) {
+	if got := didYouMean(nil); got != "" {
+		t.Errorf("Expected nothing without suggestions but got %q", got)
+	}
+	if got := didYouMean([]string{"A", "B", "C"}); got != " Did you mean A, B or C?" {
+		t.Errorf("Expected a list of suggestions but got %q", got)
+	}
+}
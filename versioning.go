@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Tool versions are part of the tool name: an unsuffixed name is version 1
+// and later versions add "_v2", "_v3" and so on. When a version is
+// superseded the old name keeps its behavior, so existing agent prompts
+// continue to work, and is listed here so calls are flagged as deprecated.
+
+// toolDeprecation describes a tool name kept for compatibility
+type toolDeprecation struct {
+	// Replacement is the tool new callers should use
+	Replacement string
+	// Reason explains what changed
+	Reason string
+}
+
+// deprecatedTools maps deprecated tool names to their replacements
+var deprecatedTools = map[string]toolDeprecation{
+	"convert_time": {
+		Replacement: "convert_time_v2",
+		Reason:      "convert_time_v2 returns structured output with UTC offsets and DST flags",
+	},
+}
+
+// deprecationMessage is the notice attached to deprecated tools and their results
+func deprecationMessage(name string, d toolDeprecation) string {
+	return fmt.Sprintf("Tool '%s' is deprecated; use '%s' instead (%s).", name, d.Replacement, d.Reason)
+}
+
+// withDeprecation marks a tool definition as deprecated in its description
+func withDeprecation(tool mcp.Tool) mcp.Tool {
+	if d, ok := deprecatedTools[tool.Name]; ok {
+		tool.Description = "Deprecated: " + deprecationMessage(tool.Name, d) + " " + tool.Description
+	}
+	return tool
+}
+
+// deprecationMiddleware adds a "deprecation" entry to the result metadata of
+// calls to deprecated tools and logs them so operators can find old callers
+func deprecationMiddleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := next(ctx, req)
+			d, deprecated := deprecatedTools[req.Params.Name]
+			if !deprecated || result == nil {
+				return result, err
+			}
+
+			slog.InfoContext(ctx, "Deprecated tool called", "tool", req.Params.Name, "replacement", d.Replacement)
+			if result.Meta == nil {
+				result.Meta = &mcp.Meta{}
+			}
+			if result.Meta.AdditionalFields == nil {
+				result.Meta.AdditionalFields = make(map[string]any)
+			}
+			result.Meta.AdditionalFields["deprecation"] = map[string]any{
+				"deprecated":  true,
+				"replacement": d.Replacement,
+				"message":     deprecationMessage(req.Params.Name, d),
+			}
+			return result, err
+		}
+	}
+}
+
+// hideDeprecatedToolsFilter removes deprecated tools from tools/list when
+// TIME_HIDE_DEPRECATED_TOOLS is set; they remain callable by name
+func hideDeprecatedToolsFilter(config func() *Config) server.ToolFilterFunc {
+	return func(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+		if !config().HideDeprecatedTools {
+			return tools
+		}
+		visible := make([]mcp.Tool, 0, len(tools))
+		for _, tool := range tools {
+			if _, deprecated := deprecatedTools[tool.Name]; !deprecated {
+				visible = append(visible, tool)
+			}
+		}
+		return visible
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestDeprecationMiddleware(t *testing.T) {
+	handler := deprecationMiddleware()(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	tests := []struct {
+		tool       string
+		deprecated bool
+	}{
+		{"convert_time", true},
+		{"convert_time_v2", false},
+		{"get_current_time", false},
+	}
+	for _, tt := range tests {
+		req := mcp.CallToolRequest{}
+		req.Params.Name = tt.tool
+		result, err := handler(context.Background(), req)
+		if err != nil {
+			t.Fatalf("%s: Failed to call tool: %v", tt.tool, err)
+		}
+		var notice map[string]any
+		if result.Meta != nil {
+			notice, _ = result.Meta.AdditionalFields["deprecation"].(map[string]any)
+		}
+		if (notice != nil) != tt.deprecated {
+			t.Errorf("%s: Expected deprecation notice=%v but got %v", tt.tool, tt.deprecated, notice)
+		}
+		if tt.deprecated && notice["replacement"] != "convert_time_v2" {
+			t.Errorf("%s: Expected replacement convert_time_v2 but got %v", tt.tool, notice["replacement"])
+		}
+	}
+}
+
+func TestHideDeprecatedToolsFilter(t *testing.T) {
+	config := &Config{}
+	filter := hideDeprecatedToolsFilter(func() *Config { return config })
+	tools := []mcp.Tool{mcp.NewTool("convert_time"), mcp.NewTool("convert_time_v2")}
+
+	if got := filter(context.Background(), tools); len(got) != 2 {
+		t.Errorf("Expected deprecated tools to be listed by default but got %d tools", len(got))
+	}
+	config.HideDeprecatedTools = true
+	if got := filter(context.Background(), tools); len(got) != 1 || got[0].Name != "convert_time_v2" {
+		t.Errorf("Expected only convert_time_v2 but got %v", got)
+	}
+}
+
+func TestWithDeprecation(t *testing.T) {
+	tool := withDeprecation(mcp.NewTool("convert_time", mcp.WithDescription("Convert time between timezones.")))
+	if !strings.HasPrefix(tool.Description, "Deprecated: ") || !strings.Contains(tool.Description, "convert_time_v2") {
+		t.Errorf("Expected a deprecation note in the description but got %q", tool.Description)
+	}
+}
+
+func TestHandleConvertTimeV2(t *testing.T) {
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{
+		"source_timezone": "America/New_York",
+		"target_timezone": "Asia/Kolkata",
+		"time":            "09:15",
+	}
+	result, err := handleConvertTimeV2(&Config{})(context.Background(), req)
+	if err != nil || result.IsError {
+		t.Fatalf("Failed to convert time: %v %s", err, toolResultText(result))
+	}
+
+	conversion, ok := result.StructuredContent.(timeConversion)
+	if !ok {
+		t.Fatalf("Expected structured content of type timeConversion but got %T", result.StructuredContent)
+	}
+	if conversion.Target.UTCOffset != "+05:30" || conversion.Target.IsDST {
+		t.Errorf("Expected target offset +05:30 without DST but got %+v", conversion.Target)
+	}
+	if !strings.Contains(conversion.Source.Datetime, "T09:15:00") {
+		t.Errorf("Expected source time 09:15 but got %s", conversion.Source.Datetime)
+	}
+	expected := "+10h30m"
+	if conversion.Source.IsDST {
+		expected = "+9h30m"
+	}
+	if conversion.TimeDifference != expected {
+		t.Errorf("Expected time difference %s but got %s", expected, conversion.TimeDifference)
+	}
+
+	var fallback timeConversion
+	if err := json.Unmarshal([]byte(toolResultText(result)), &fallback); err != nil || fallback != conversion {
+		t.Errorf("Expected the text content to carry the same JSON but got %q (%v)", toolResultText(result), err)
+	}
+}
+
+func TestFormatOffsetDifference(t *testing.T) {
+	tests := []struct {
+		seconds  int
+		expected string
+	}{
+		{0, "+0h"},
+		{7 * 3600, "+7h"},
+		{-(5*3600 + 30*60), "-5h30m"},
+		{45 * 60, "+0h45m"},
+	}
+	for _, tt := range tests {
+		if got := formatOffsetDifference(tt.seconds); got != tt.expected {
+			t.Errorf("Expected %s for %ds but got %s", tt.expected, tt.seconds, got)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// maxVTimezoneYears caps the span of years export_vtimezone covers
+const maxVTimezoneYears = 50
+
+// zoneTransition is a change of UTC offset or abbreviation in a location
+type zoneTransition struct {
+	At         time.Time // instant of the change, UTC
+	OffsetFrom int
+	OffsetTo   int
+	Name       string
+	IsDST      bool
+}
+
+// localStart is the wall-clock time of the transition in the offset it
+// leaves, which is how VTIMEZONE DTSTART and RRULE are expressed
+func (t zoneTransition) localStart() time.Time {
+	return t.At.In(time.FixedZone("", t.OffsetFrom))
+}
+
+// zoneTransitions lists the transitions of loc in [from, to)
+func zoneTransitions(loc *time.Location, from, to time.Time) []zoneTransition {
+	var transitions []zoneTransition
+	t := from.In(loc)
+	for {
+		_, end := t.ZoneBounds()
+		if end.IsZero() || !end.Before(to) {
+			return transitions
+		}
+		// Zones past their last listed transition follow a rule, whose
+		// bounds also break at year ends without a change and may end where
+		// they start; step over those
+		if !end.After(t) {
+			t = t.Add(time.Second)
+			continue
+		}
+		beforeName, before := end.Add(-time.Second).In(loc).Zone()
+		after := end.In(loc)
+		name, offset := after.Zone()
+		if before == offset && beforeName == name && end.Add(-time.Second).In(loc).IsDST() == after.IsDST() {
+			t = after
+			continue
+		}
+		transitions = append(transitions, zoneTransition{
+			At:         end.UTC(),
+			OffsetFrom: before,
+			OffsetTo:   offset,
+			Name:       name,
+			IsDST:      after.IsDST(),
+		})
+		t = after
+	}
+}
+
+// yearlyRule returns the RRULE parts (without FREQ) matching every
+// transition, one per consecutive year on the same local time, or "" when
+// they follow no such rule
+func yearlyRule(transitions []zoneTransition) string {
+	if len(transitions) < 2 {
+		return ""
+	}
+	first := transitions[0].localStart()
+	nth, last, monthDay := true, true, true
+	for i, tr := range transitions {
+		local := tr.localStart()
+		if local.Year() != first.Year()+i || local.Month() != first.Month() ||
+			local.Hour() != first.Hour() || local.Minute() != first.Minute() || local.Second() != first.Second() {
+			return ""
+		}
+		daysInMonth := time.Date(local.Year(), local.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+		nth = nth && local.Weekday() == first.Weekday() && (local.Day()-1)/7 == (first.Day()-1)/7
+		last = last && local.Weekday() == first.Weekday() && local.Day()+7 > daysInMonth
+		monthDay = monthDay && local.Day() == first.Day()
+	}
+
+	weekday := strings.ToUpper(first.Weekday().String()[:2])
+	switch {
+	// "Last Sunday" rules are far more common than "fourth Sunday" ones, so
+	// prefer them when the transitions fit both
+	case last:
+		return fmt.Sprintf("BYMONTH=%d;BYDAY=-1%s", first.Month(), weekday)
+	case nth:
+		return fmt.Sprintf("BYMONTH=%d;BYDAY=%d%s", first.Month(), (first.Day()-1)/7+1, weekday)
+	case monthDay:
+		return fmt.Sprintf("BYMONTH=%d;BYMONTHDAY=%d", first.Month(), first.Day())
+	}
+	return ""
+}
+
+// formatUTCOffset formats an offset in seconds as +HHMM, or +HHMMSS when the
+// offset has seconds, as RFC 5545 UTC-OFFSET values require
+func formatUTCOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign, seconds = "-", -seconds
+	}
+	s := fmt.Sprintf("%s%02d%02d", sign, seconds/3600, seconds/60%60)
+	if seconds%60 != 0 {
+		s += fmt.Sprintf("%02d", seconds%60)
+	}
+	return s
+}
+
+// buildVTimezone generates the VTIMEZONE for loc covering fromYear through
+// toYear. Transitions sharing offsets and abbreviation become one STANDARD or
+// DAYLIGHT component: with an open-ended RRULE when the rule still holds
+// after toYear, an RRULE with UNTIL when it ended, or RDATEs otherwise.
+func buildVTimezone(loc *time.Location, fromYear, toYear int) string {
+	from := time.Date(fromYear, 1, 1, 0, 0, 0, 0, loc)
+	end := time.Date(toYear+1, 1, 1, 0, 0, 0, 0, loc)
+	// One more year shows whether rules continue past the range
+	transitions := zoneTransitions(loc, from, end.AddDate(1, 0, 0))
+
+	type component struct {
+		all     []zoneTransition
+		inRange []zoneTransition
+	}
+	var order []string
+	groups := make(map[string]*component)
+	for _, tr := range transitions {
+		key := fmt.Sprintf("%t/%d/%d/%s", tr.IsDST, tr.OffsetFrom, tr.OffsetTo, tr.Name)
+		if groups[key] == nil {
+			groups[key] = &component{}
+			order = append(order, key)
+		}
+		groups[key].all = append(groups[key].all, tr)
+		if tr.At.Before(end) {
+			groups[key].inRange = append(groups[key].inRange, tr)
+		}
+	}
+
+	var b strings.Builder
+	line := func(format string, args ...any) {
+		fmt.Fprintf(&b, format+"\r\n", args...)
+	}
+	line("BEGIN:VTIMEZONE")
+	line("TZID:%s", loc.String())
+	line("X-LIC-LOCATION:%s", loc.String())
+
+	written := 0
+	for _, key := range order {
+		group := groups[key]
+		if len(group.inRange) == 0 {
+			continue
+		}
+		first := group.inRange[0]
+		kind := "STANDARD"
+		if first.IsDST {
+			kind = "DAYLIGHT"
+		}
+		line("BEGIN:%s", kind)
+		line("DTSTART:%s", first.localStart().Format("20060102T150405"))
+		line("TZOFFSETFROM:%s", formatUTCOffset(first.OffsetFrom))
+		line("TZOFFSETTO:%s", formatUTCOffset(first.OffsetTo))
+		line("TZNAME:%s", first.Name)
+
+		continues := len(group.all) > len(group.inRange)
+		if rule := yearlyRule(group.all); continues && rule != "" {
+			line("RRULE:FREQ=YEARLY;%s", rule)
+		} else if rule := yearlyRule(group.inRange); rule != "" {
+			line("RRULE:FREQ=YEARLY;%s;UNTIL=%s", rule, group.inRange[len(group.inRange)-1].At.Format("20060102T150405Z"))
+		} else {
+			for _, tr := range group.inRange[1:] {
+				line("RDATE:%s", tr.localStart().Format("20060102T150405"))
+			}
+		}
+		line("END:%s", kind)
+		written++
+	}
+
+	if written == 0 {
+		// No transitions in range: a single fixed offset from the start
+		name, offset := from.Zone()
+		line("BEGIN:STANDARD")
+		line("DTSTART:19700101T000000")
+		line("TZOFFSETFROM:%s", formatUTCOffset(offset))
+		line("TZOFFSETTO:%s", formatUTCOffset(offset))
+		line("TZNAME:%s", name)
+		line("END:STANDARD")
+	}
+	line("END:VTIMEZONE")
+	return b.String()
+}
+
+func addVTimezoneTool(mcpServer *server.MCPServer, config *Config) {
+	mcpServer.AddTool(
+		mcp.NewTool("export_vtimezone",
+			mcp.WithDescription("Generate the RFC 5545 VTIMEZONE definition of an IANA timezone, with STANDARD and DAYLIGHT components and yearly RRULEs, for embedding in iCalendar data."),
+			mcp.WithString("timezone",
+				mcp.Description("IANA timezone name, e.g. Europe/Warsaw."),
+				mcp.Required(),
+			),
+			mcp.WithNumber("from_year",
+				mcp.Description("First year the definition must cover. Defaults to the current year."),
+			),
+			mcp.WithNumber("to_year",
+				mcp.Description(fmt.Sprintf("Last year the definition must cover. Defaults to from_year; at most %d years after it.", maxVTimezoneYears)),
+			),
+			mcp.WithTitleAnnotation("Export VTIMEZONE"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		handleExportVTimezone(config),
+	)
+}
+
+// handleExportVTimezone returns a handler for the export_vtimezone tool
+func handleExportVTimezone(config *Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		timezoneStr, err := request.RequireString("timezone")
+		if err != nil {
+			return toolError(errorInvalidArgument, err.Error()), nil
+		}
+		loc, err := loadTimezone(ctx, timezoneStr, config)
+		if err != nil {
+			return invalidTimezoneError("Invalid timezone", timezoneStr), nil
+		}
+
+		fromYear := request.GetInt("from_year", config.now().In(loc).Year())
+		toYear := request.GetInt("to_year", fromYear)
+		if fromYear < 1900 || fromYear > 2200 {
+			return toolError(errorInvalidArgument, fmt.Sprintf("Invalid from_year: %d. Please provide a year between 1900 and 2200.", fromYear)), nil
+		}
+		if toYear < fromYear || toYear-fromYear > maxVTimezoneYears {
+			return toolError(errorInvalidArgument, fmt.Sprintf("Invalid to_year: %d. It must be between from_year and %d years after it.", toYear, maxVTimezoneYears)), nil
+		}
+
+		return mcp.NewToolResultText(buildVTimezone(loc, fromYear, toYear)), nil
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestBuildVTimezone(t *testing.T) {
+	tests := []struct {
+		timezone string
+		from, to int
+		expected []string
+		absent   []string
+	}{
+		{
+			timezone: "Europe/Warsaw", from: 2025, to: 2025,
+			expected: []string{
+				"TZID:Europe/Warsaw",
+				"BEGIN:DAYLIGHT\r\nDTSTART:20250330T020000\r\nTZOFFSETFROM:+0100\r\nTZOFFSETTO:+0200\r\nTZNAME:CEST\r\nRRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=-1SU\r\nEND:DAYLIGHT",
+				"BEGIN:STANDARD\r\nDTSTART:20251026T030000\r\nTZOFFSETFROM:+0200\r\nTZOFFSETTO:+0100\r\nTZNAME:CET\r\nRRULE:FREQ=YEARLY;BYMONTH=10;BYDAY=-1SU\r\nEND:STANDARD",
+			},
+		},
+		{
+			timezone: "America/New_York", from: 2024, to: 2026,
+			expected: []string{"RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=2SU\r\n", "RRULE:FREQ=YEARLY;BYMONTH=11;BYDAY=1SU\r\n", "TZOFFSETTO:-0500"},
+		},
+		{
+			// Brazil abolished DST in 2019: the rule ends with UNTIL
+			timezone: "America/Sao_Paulo", from: 2018, to: 2019,
+			expected: []string{"RRULE:FREQ=YEARLY;BYMONTH=2;BYDAY=3SU;UNTIL=20190217T020000Z", "DTSTART:20181104T000000"},
+		},
+		{
+			timezone: "Asia/Tokyo", from: 2025, to: 2025,
+			expected: []string{"BEGIN:STANDARD\r\nDTSTART:19700101T000000\r\nTZOFFSETFROM:+0900\r\nTZOFFSETTO:+0900\r\nTZNAME:JST\r\n"},
+			absent:   []string{"DAYLIGHT", "RRULE"},
+		},
+	}
+	for _, tt := range tests {
+		loc, err := time.LoadLocation(tt.timezone)
+		if err != nil {
+			t.Fatalf("Failed to load %s: %v", tt.timezone, err)
+		}
+		vtz := buildVTimezone(loc, tt.from, tt.to)
+		if !strings.HasPrefix(vtz, "BEGIN:VTIMEZONE\r\n") || !strings.HasSuffix(vtz, "END:VTIMEZONE\r\n") {
+			t.Errorf("%s: Expected a VTIMEZONE block but got %q", tt.timezone, vtz)
+		}
+		for _, s := range tt.expected {
+			if !strings.Contains(vtz, s) {
+				t.Errorf("%s: Expected %q in\n%s", tt.timezone, s, vtz)
+			}
+		}
+		for _, s := range tt.absent {
+			if strings.Contains(vtz, s) {
+				t.Errorf("%s: Expected no %q in\n%s", tt.timezone, s, vtz)
+			}
+		}
+	}
+}
+
+func TestFormatUTCOffset(t *testing.T) {
+	tests := map[int]string{3600: "+0100", -18000: "-0500", 19800: "+0530", 0: "+0000", -2670: "-004430"}
+	for seconds, expected := range tests {
+		if got := formatUTCOffset(seconds); got != expected {
+			t.Errorf("Expected %s for %d but got %s", expected, seconds, got)
+		}
+	}
+}
+
+func TestHandleExportVTimezone_Errors(t *testing.T) {
+	tests := []map[string]any{
+		{"timezone": "Invalid/Zone"},
+		{"timezone": "Europe/Warsaw", "from_year": 1800},
+		{"timezone": "Europe/Warsaw", "from_year": 2025, "to_year": 2024},
+		{"timezone": "Europe/Warsaw", "from_year": 2000, "to_year": 2100},
+	}
+	for _, args := range tests {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+		result, err := handleExportVTimezone(&Config{})(context.Background(), req)
+		if err != nil || !result.IsError {
+			t.Errorf("Expected an error for %v but got %s", args, toolResultText(result))
+		}
+	}
+}
@@ -0,0 +1,365 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// webSocketSubprotocol is selected when the client offers it
+	webSocketSubprotocol = "mcp"
+	// maxWebSocketMessageSize caps one incoming JSON-RPC message
+	maxWebSocketMessageSize = 4 << 20
+	// maxWebSocketInFlight is how many requests of one connection are
+	// handled at once; further messages wait to be read
+	maxWebSocketInFlight = 16
+	// webSocketAuthCheckInterval is how often the credentials of a quiet
+	// connection are checked again; busy ones are checked on every message
+	webSocketAuthCheckInterval = 30 * time.Second
+)
+
+// webSocketSession is the MCP session of one WebSocket connection
+type webSocketSession struct {
+	id            string
+	notifications chan mcp.JSONRPCNotification
+	initialized   atomic.Bool
+	logLevel      atomic.Value // mcp.LoggingLevel
+
+	mu         sync.Mutex
+	clientInfo mcp.Implementation
+	clientCaps mcp.ClientCapabilities
+}
+
+func (s *webSocketSession) SessionID() string { return s.id }
+
+func (s *webSocketSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifications
+}
+
+func (s *webSocketSession) Initialize()       { s.initialized.Store(true) }
+func (s *webSocketSession) Initialized() bool { return s.initialized.Load() }
+
+func (s *webSocketSession) SetLogLevel(level mcp.LoggingLevel) { s.logLevel.Store(level) }
+
+func (s *webSocketSession) GetLogLevel() mcp.LoggingLevel {
+	if level, ok := s.logLevel.Load().(mcp.LoggingLevel); ok {
+		return level
+	}
+	return mcp.LoggingLevelError
+}
+
+func (s *webSocketSession) GetClientInfo() mcp.Implementation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.clientInfo
+}
+
+func (s *webSocketSession) SetClientInfo(info mcp.Implementation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clientInfo = info
+}
+
+func (s *webSocketSession) GetClientCapabilities() mcp.ClientCapabilities {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.clientCaps
+}
+
+func (s *webSocketSession) SetClientCapabilities(caps mcp.ClientCapabilities) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clientCaps = caps
+}
+
+var (
+	_ server.SessionWithLogging    = (*webSocketSession)(nil)
+	_ server.SessionWithClientInfo = (*webSocketSession)(nil)
+)
+
+// webSocketHandler serves MCP over WebSocket on the MCP path: each
+// connection is one session and each text message one JSON-RPC message. The
+// HTTP context function runs once on the upgrade request, so the connection
+// keeps that request's identity, tenant and detected timezone, and tool
+// calls are authorized by the tool middleware as over streamable HTTP. The
+// upgrade request's credentials are checked again on every message and
+// periodically, and the connection closes when they expire, are revoked or
+// stop validating after a reload.
+func webSocketHandler(mcpServer *server.MCPServer, config func() *Config, contextFunc *reloadableContextFunc, sessions *sessionRegistry) http.Handler {
+	path := config().HTTPPath
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != path {
+			writeJSONResponse(w, r, http.StatusNotFound, restError{Error: "Not Found"})
+			return
+		}
+		if !isWebSocketUpgrade(r) {
+			w.Header().Set("Upgrade", "websocket")
+			writeJSONResponse(w, r, http.StatusUpgradeRequired, restError{Error: "This server speaks MCP over WebSocket; open a WebSocket connection"})
+			return
+		}
+		ws := websocket.Server{
+			Handshake: func(wsConfig *websocket.Config, r *http.Request) error {
+				return webSocketHandshake(wsConfig, r, config())
+			},
+			Handler: func(conn *websocket.Conn) {
+				authenticated := r
+				var authorize func() error
+				if auth := contextFunc.auth(); auth != nil {
+					var claims *Claims
+					var err error
+					if claims, authenticated, err = auth.authenticateRequest(r); err == nil {
+						authorize = webSocketAuthorizer(contextFunc, r)
+					}
+					if claims != nil && claims.ExpiresAt != nil {
+						ctx, cancel := context.WithDeadline(authenticated.Context(), claims.ExpiresAt.Time)
+						defer cancel()
+						authenticated = authenticated.WithContext(ctx)
+					}
+				}
+				ctx := contextFunc.contextFunc(authenticated.Context(), authenticated)
+				serveWebSocket(ctx, conn, mcpServer, config(), sessions, authorize)
+			},
+		}
+		ws.ServeHTTP(hijackableWriter{w}, r)
+	})
+}
+
+// webSocketAuthorizer returns a check that the upgrade request r still
+// authenticates with the current auth middleware, so revoked tokens, removed
+// API keys and keys retired by a reload end the connection
+func webSocketAuthorizer(contextFunc *reloadableContextFunc, r *http.Request) func() error {
+	return func() error {
+		auth := contextFunc.auth()
+		if auth == nil {
+			return nil
+		}
+		_, err := auth.authenticate(r)
+		return err
+	}
+}
+
+// isWebSocketUpgrade reports whether r asks to upgrade to WebSocket
+func isWebSocketUpgrade(r *http.Request) bool {
+	return r.Method == http.MethodGet && strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+}
+
+// webSocketHandshake accepts clients without an Origin header, which are not
+// browsers, and browser origins allowed by the CORS settings, so pages on
+// other sites cannot open a socket with the user's cookies or network
+// position. It selects the mcp subprotocol when offered.
+func webSocketHandshake(wsConfig *websocket.Config, r *http.Request, config *Config) error {
+	if origin := r.Header.Get("Origin"); origin != "" {
+		if !config.HTTPCORSEnabled || !isOriginAllowed(origin, config.HTTPCORSOrigins) {
+			slog.WarnContext(r.Context(), "WebSocket origin rejected", "origin", origin)
+			return fmt.Errorf("origin %q is not allowed", origin)
+		}
+	}
+	offered := wsConfig.Protocol
+	wsConfig.Protocol = nil
+	for _, protocol := range offered {
+		if protocol == webSocketSubprotocol {
+			wsConfig.Protocol = []string{protocol}
+		}
+	}
+	return nil
+}
+
+// hijackableWriter finds the connection through writers wrapped by
+// compression or access logging, which websocket.Server cannot
+type hijackableWriter struct {
+	http.ResponseWriter
+}
+
+func (w hijackableWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+// webSocketConn serializes writes to one connection and tracks activity
+type webSocketConn struct {
+	conn         *websocket.Conn
+	payloads     *payloadSampler
+	mu           sync.Mutex
+	writeTimeout time.Duration
+	lastActivity atomic.Int64 // unix nanoseconds
+}
+
+func (c *webSocketConn) touch() {
+	c.lastActivity.Store(time.Now().UnixNano())
+}
+
+// idle returns the time since the last message either way
+func (c *webSocketConn) idle() time.Duration {
+	return time.Since(time.Unix(0, c.lastActivity.Load()))
+}
+
+func (c *webSocketConn) write(payloadType byte, data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.writeTimeout > 0 {
+		c.conn.SetWriteDeadline(time.Now().Add(c.writeTimeout))
+	}
+	c.conn.PayloadType = payloadType
+	_, err := c.conn.Write(data)
+	return err
+}
+
+func (c *webSocketConn) send(ctx context.Context, message any) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	c.touch()
+	c.payloads.log(ctx, "sent", data)
+	return c.write(websocket.TextFrame, data)
+}
+
+// serveWebSocket runs one session until the client disconnects, an admin
+// terminates the session, the session is idle or past its lifetime, its
+// credentials fail authorize, or the server drains connections. authorize is
+// nil for anonymous connections.
+func serveWebSocket(ctx context.Context, conn *websocket.Conn, mcpServer *server.MCPServer, config *Config, sessions *sessionRegistry, authorize func() error) {
+	// Hijacked connections keep the HTTP server's read and write deadlines
+	conn.SetDeadline(time.Time{})
+	conn.MaxPayloadBytes = maxWebSocketMessageSize
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if config.HTTPSessionLifetime > 0 {
+		var cancelLifetime context.CancelFunc
+		ctx, cancelLifetime = context.WithTimeout(ctx, config.HTTPSessionLifetime)
+		defer cancelLifetime()
+	}
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	session := &webSocketSession{notifications: make(chan mcp.JSONRPCNotification, 100)}
+	if sessions != nil {
+		session.id = sessions.Generate()
+		defer sessions.Terminate(session.id)
+	} else {
+		session.id = newRequestID()
+	}
+	if err := mcpServer.RegisterSession(ctx, session); err != nil {
+		slog.ErrorContext(ctx, "Failed to register WebSocket session", "error", err)
+		return
+	}
+	defer mcpServer.UnregisterSession(context.WithoutCancel(ctx), session.id)
+	ctx = mcpServer.WithContext(ctx, session)
+	slog.InfoContext(ctx, "WebSocket session opened", "session_id", session.id, "remote_addr", conn.Request().RemoteAddr)
+	defer slog.InfoContext(ctx, "WebSocket session closed", "session_id", session.id)
+
+	out := &webSocketConn{
+		conn:         conn,
+		payloads:     newPayloadSampler("ws", func() *Config { return config }),
+		writeTimeout: config.HTTPTimeout,
+	}
+	out.touch()
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	unauthorized := func() bool {
+		if authorize == nil {
+			return false
+		}
+		if err := authorize(); err != nil {
+			slog.WarnContext(ctx, "Closing WebSocket session whose credentials are no longer valid", "session_id", session.id, "error", err)
+			return true
+		}
+		return false
+	}
+	wg.Go(func() {
+		var heartbeat, idle, authCheck <-chan time.Time
+		if authorize != nil {
+			ticker := time.NewTicker(webSocketAuthCheckInterval)
+			defer ticker.Stop()
+			authCheck = ticker.C
+		}
+		if config.HTTPHeartbeat > 0 {
+			ticker := time.NewTicker(config.HTTPHeartbeat)
+			defer ticker.Stop()
+			heartbeat = ticker.C
+		}
+		// Messages either way count as activity, as on HTTP event streams
+		idleTTL := config.HTTPSessionIdleTTL
+		var idleTimer *time.Timer
+		if idleTTL > 0 {
+			idleTimer = time.NewTimer(idleTTL)
+			defer idleTimer.Stop()
+			idle = idleTimer.C
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case notification := <-session.notifications:
+				if err := out.send(ctx, notification); err != nil {
+					cancel()
+					return
+				}
+			case <-heartbeat:
+				if err := out.write(websocket.PingFrame, nil); err != nil {
+					cancel()
+					return
+				}
+			case <-authCheck:
+				if unauthorized() {
+					cancel()
+					return
+				}
+			case <-idle:
+				if since := out.idle(); since < idleTTL {
+					idleTimer.Reset(idleTTL - since)
+					continue
+				}
+				slog.InfoContext(ctx, "Closing idle WebSocket session", "session_id", session.id, "idle_ttl", idleTTL.String())
+				cancel()
+				return
+			}
+		}
+	})
+
+	inFlight := make(chan struct{}, maxWebSocketInFlight)
+	for {
+		var message []byte
+		if err := websocket.Message.Receive(conn, &message); err != nil {
+			if errors.Is(err, websocket.ErrFrameTooLarge) {
+				slog.WarnContext(ctx, "WebSocket message too large", "session_id", session.id, "limit", maxWebSocketMessageSize)
+			}
+			cancel()
+			return
+		}
+		out.touch()
+		out.payloads.log(ctx, "received", message)
+		if unauthorized() {
+			cancel()
+			return
+		}
+		if sessions != nil {
+			if terminated, _ := sessions.Validate(session.id); terminated {
+				cancel()
+				return
+			}
+		}
+		inFlight <- struct{}{}
+		wg.Go(func() {
+			defer func() { <-inFlight }()
+			if response := mcpServer.HandleMessage(ctx, message); response != nil {
+				if err := out.send(ctx, response); err != nil {
+					cancel()
+				}
+			}
+		})
+	}
+}
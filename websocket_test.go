@@ -0,0 +1,175 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+	"golang.org/x/net/websocket"
+)
+
+func TestWebSocketTransport(t *testing.T) {
+	config := &Config{
+		HTTPPath:        "/mcp",
+		HTTPTimeout:     5 * time.Second,
+		HTTPCORSEnabled: true,
+		HTTPCORSOrigins: []string{"https://app.example.com"},
+		AuthEnabled:     true,
+		AuthSecretKey:   "test-secret-key-that-is-long-enough-for-hs256",
+		AuthIssuer:      "test-issuer",
+		AuthAudience:    "test-audience",
+		HTTPCompression: true,
+	}
+	denylist, err := newTokenDenylist("", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.AuthDenylist = denylist
+	contextFunc := &reloadableContextFunc{}
+	fn, auth, err := createHTTPMiddleware(config)
+	if err != nil {
+		t.Fatalf("Failed to create HTTP middleware: %v", err)
+	}
+	contextFunc.store(fn, auth)
+	sessions := newSessionRegistry(0)
+	hooks := &server.Hooks{}
+	sessions.install(hooks)
+	mcpServer := server.NewMCPServer("TimeMCP", "test", server.WithToolCapabilities(true), server.WithHooks(hooks))
+	newToolMiddlewareChain(func() *Config { return config }, nil, nil).install(mcpServer)
+	addTools(mcpServer, config)
+	var current atomic.Pointer[Config]
+	current.Store(config)
+	mcpHandler := webSocketHandler(mcpServer, current.Load, contextFunc, sessions)
+	srv := httptest.NewServer(createCustomHTTPHandler(mcpHandler, config, &httpRuntime{mcpServer: mcpServer, contextFunc: contextFunc}))
+	defer srv.Close()
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/mcp"
+
+	dial := func(t *testing.T, token, origin string) (*websocket.Conn, error) {
+		t.Helper()
+		// The client always sends an Origin, as browsers do
+		wsConfig, err := websocket.NewConfig(url, origin)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wsConfig.Header = http.Header{"Accept-Encoding": {"gzip"}}
+		if token != "" {
+			wsConfig.Header.Set("Authorization", "Bearer "+token)
+		}
+		wsConfig.Protocol = []string{webSocketSubprotocol}
+		return websocket.DialConfig(wsConfig)
+	}
+	call := func(t *testing.T, conn *websocket.Conn, message string) map[string]any {
+		t.Helper()
+		if err := websocket.Message.Send(conn, message); err != nil {
+			t.Fatalf("Failed to send: %v", err)
+		}
+		var response map[string]any
+		if err := websocket.JSON.Receive(conn, &response); err != nil {
+			t.Fatalf("Failed to receive: %v", err)
+		}
+		return response
+	}
+
+	allowed := "https://app.example.com"
+	token, _ := auth.GenerateToken("1", "ada", "user", 1)
+	conn, err := dial(t, token, allowed)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer conn.Close()
+	initialize := call(t, conn, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"ws-test","version":"1.0"}}}`)
+	if _, ok := initialize["result"]; !ok {
+		t.Fatalf("Expected an initialize result but got %v", initialize)
+	}
+	websocket.Message.Send(conn, `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	response := call(t, conn, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"get_current_time","arguments":{"timezone":"UTC"}}}`)
+	if result, _ := response["result"].(map[string]any); result == nil || result["isError"] == true {
+		t.Errorf("Expected a tool result but got %v", response)
+	}
+	if listed := sessions.list(); len(listed) != 1 || listed[0].Username != "ada" || listed[0].Client != "ws-test 1.0" {
+		t.Errorf("Expected the session to be tracked with its user and client but got %+v", listed)
+	}
+
+	anonymous, err := dial(t, "", allowed)
+	if err != nil {
+		t.Fatalf("Failed to connect without a token: %v", err)
+	}
+	defer anonymous.Close()
+	response = call(t, anonymous, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"get_current_time","arguments":{}}}`)
+	data, _ := json.Marshal(response)
+	if !strings.Contains(string(data), "UNAUTHORIZED") {
+		t.Errorf("Expected an unauthenticated tool call to fail but got %s", data)
+	}
+
+	if _, err := dial(t, token, "https://evil.example.com"); err == nil {
+		t.Error("Expected a foreign origin to be rejected")
+	}
+	req := httptest.NewRequest("GET", "/mcp", nil)
+	if err := webSocketHandshake(&websocket.Config{}, req, config); err != nil {
+		t.Errorf("Expected a client without an Origin to be accepted: %v", err)
+	}
+
+	closed := func(t *testing.T, conn *websocket.Conn) bool {
+		t.Helper()
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		var message string
+		err := websocket.Message.Receive(conn, &message)
+		return err != nil && !errors.Is(err, os.ErrDeadlineExceeded)
+	}
+
+	// Revoking the token ends a connection opened with it
+	revoked, claims, _ := auth.generateToken(tokenOptions{UserID: "2", Username: "grace", Role: "user", Expiration: time.Hour})
+	conn, err = dial(t, revoked, allowed)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer conn.Close()
+	call(t, conn, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"ws-test","version":"1.0"}}}`)
+	denylist.revoke(claims["jti"].(string))
+	websocket.Message.Send(conn, `{"jsonrpc":"2.0","id":2,"method":"ping"}`)
+	if !closed(t, conn) {
+		t.Error("Expected the connection of a revoked token to be closed")
+	}
+
+	// Connections close when their token expires
+	expiring, _, _ := auth.generateToken(tokenOptions{UserID: "3", Username: "alan", Role: "user", Expiration: time.Second})
+	conn, err = dial(t, expiring, allowed)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer conn.Close()
+	if !closed(t, conn) {
+		t.Error("Expected the connection to be closed when its token expires")
+	}
+
+	// Connections without messages either way are closed after the idle TTL
+	idleConfig := *config
+	idleConfig.HTTPSessionIdleTTL = 50 * time.Millisecond
+	current.Store(&idleConfig)
+	idle, err := dial(t, token, allowed)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer idle.Close()
+	idle.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var message string
+	if err := websocket.Message.Receive(idle, &message); err == nil || errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Errorf("Expected the idle connection to be closed but got %q %v", message, err)
+	}
+
+	resp, err := http.Get(srv.URL + "/mcp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUpgradeRequired {
+		t.Errorf("Expected status 426 for a plain request but got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// maxWeekParityLabels caps the length of a rotation, e.g. A,B,C for three shifts
+	maxWeekParityLabels = 12
+	// maxWeekParityUpcoming caps the upcoming weeks listed
+	maxWeekParityUpcoming = 52
+	// defaultWeekParityUpcoming is how many upcoming weeks are listed by default
+	defaultWeekParityUpcoming = 4
+)
+
+// weekParity is the structured result of week_parity
+type weekParity struct {
+	Date              string           `json:"date"`
+	ISOYear           int              `json:"iso_year"`
+	ISOWeek           int              `json:"iso_week"`
+	ISOParity         string           `json:"iso_parity" jsonschema:"even or odd ISO week number"`
+	ISOYearHas53Weeks bool             `json:"iso_year_has_53_weeks" jsonschema:"Whether the ISO year has 53 weeks, so odd weeks 53 and 1 follow each other"`
+	Anchor            string           `json:"anchor,omitempty" jsonschema:"Start of the anchor week, which has the first label"`
+	WeekStart         string           `json:"week_start" jsonschema:"First day of the date's week"`
+	Label             string           `json:"label,omitempty" jsonschema:"The week's label in the rotation, e.g. A or B"`
+	WeeksSinceAnchor  int              `json:"weeks_since_anchor,omitempty"`
+	Upcoming          []weekParityWeek `json:"upcoming,omitempty" jsonschema:"The following weeks and their labels"`
+}
+
+type weekParityWeek struct {
+	WeekStart string `json:"week_start"`
+	ISOWeek   int    `json:"iso_week"`
+	Label     string `json:"label,omitempty"`
+}
+
+// isoWeeksInYear returns 52 or 53, the number of ISO weeks of an ISO year
+func isoWeeksInYear(year int) int {
+	// December 28 is always in the last ISO week of its year
+	_, week := time.Date(year, time.December, 28, 0, 0, 0, 0, time.UTC).ISOWeek()
+	return week
+}
+
+// startOfWeek returns the date of the first day of date's week
+func startOfWeek(date time.Time, first time.Weekday) time.Time {
+	offset := (int(date.Weekday()) - int(first) + 7) % 7
+	return time.Date(date.Year(), date.Month(), date.Day()-offset, 0, 0, 0, 0, time.UTC)
+}
+
+// rotationLabel returns the label of the week starting at week in a rotation
+// whose first label starts at anchor; both are week starts in UTC
+func rotationLabel(week, anchor time.Time, labels []string) (string, int) {
+	weeks := int(week.Sub(anchor).Hours()) / (7 * 24)
+	return labels[((weeks%len(labels))+len(labels))%len(labels)], weeks
+}
+
+func addWeekParityTool(mcpServer *server.MCPServer, config *Config) {
+	mcpServer.AddTool(
+		mcp.NewTool("week_parity",
+			mcp.WithDescription("Tell whether a date falls in an 'A week' or 'B week' of an alternating schedule, counted from an anchor week, and whether its ISO week number is even or odd. Rotations may have more than two weeks, e.g. A,B,C for shift plans. Also lists the labels of the following weeks."),
+			mcp.WithString("date",
+				mcp.Description("Date in YYYY-MM-DD format. Defaults to today in timezone."),
+			),
+			mcp.WithString("anchor",
+				mcp.Description("Any date in a week that has the first label, in YYYY-MM-DD format. Without it only ISO parity is reported."),
+			),
+			mcp.WithString("labels",
+				mcp.Description("Comma-separated week labels in rotation order. Defaults to A,B."),
+			),
+			mcp.WithString("week_start",
+				mcp.Description("First day of the week: monday or sunday. Defaults to monday, as ISO weeks."),
+				mcp.Enum("monday", "sunday"),
+			),
+			mcp.WithNumber("upcoming",
+				mcp.Description(fmt.Sprintf("Number of following weeks to list, 0 to %d. Defaults to %d.", maxWeekParityUpcoming, defaultWeekParityUpcoming)),
+			),
+			mcp.WithString("timezone",
+				mcp.Description("Timezone that decides today. Defaults to the session or server default."),
+			),
+			mcp.WithOutputSchema[weekParity](),
+			mcp.WithTitleAnnotation("Week Parity"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		handleWeekParity(config),
+	)
+}
+
+// handleWeekParity returns a handler for the week_parity tool
+func handleWeekParity(config *Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		timezoneStr := request.GetString("timezone", "")
+		loc, err := loadTimezone(ctx, timezoneStr, config)
+		if err != nil {
+			return invalidTimezoneError("Invalid timezone", timezoneStr), nil
+		}
+		// Dates are calendar days, so they are handled in UTC
+		today := config.now().In(loc)
+		date := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+		if dateStr := request.GetString("date", ""); dateStr != "" {
+			if date, err = time.Parse("2006-01-02", dateStr); err != nil {
+				return toolError(errorParse, fmt.Sprintf("Invalid date format: %s. Please provide date in YYYY-MM-DD format.", dateStr)), nil
+			}
+		}
+		first := time.Monday
+		if ws := request.GetString("week_start", "monday"); ws == "sunday" {
+			first = time.Sunday
+		} else if ws != "monday" {
+			return toolError(errorInvalidArgument, fmt.Sprintf("Invalid week_start: %s. Please use monday or sunday.", ws)), nil
+		}
+		var labels []string
+		for label := range strings.SplitSeq(request.GetString("labels", "A,B"), ",") {
+			if label = strings.TrimSpace(label); label != "" {
+				labels = append(labels, label)
+			}
+		}
+		if len(labels) < 2 || len(labels) > maxWeekParityLabels {
+			return toolError(errorInvalidArgument, fmt.Sprintf("Provide between 2 and %d labels", maxWeekParityLabels)), nil
+		}
+		upcoming := request.GetInt("upcoming", defaultWeekParityUpcoming)
+		if upcoming < 0 || upcoming > maxWeekParityUpcoming {
+			return toolError(errorInvalidArgument, fmt.Sprintf("Invalid upcoming: %d. Please provide 0 to %d.", upcoming, maxWeekParityUpcoming)), nil
+		}
+
+		isoYear, isoWeek := date.ISOWeek()
+		week := startOfWeek(date, first)
+		result := weekParity{
+			Date:              date.Format("2006-01-02"),
+			ISOYear:           isoYear,
+			ISOWeek:           isoWeek,
+			ISOParity:         "odd",
+			ISOYearHas53Weeks: isoWeeksInYear(isoYear) == 53,
+			WeekStart:         week.Format("2006-01-02"),
+		}
+		if isoWeek%2 == 0 {
+			result.ISOParity = "even"
+		}
+
+		var anchor time.Time
+		if anchorStr := request.GetString("anchor", ""); anchorStr != "" {
+			a, err := time.Parse("2006-01-02", anchorStr)
+			if err != nil {
+				return toolError(errorParse, fmt.Sprintf("Invalid anchor: %s. Please provide date in YYYY-MM-DD format.", anchorStr)), nil
+			}
+			anchor = startOfWeek(a, first)
+			result.Anchor = anchor.Format("2006-01-02")
+			result.Label, result.WeeksSinceAnchor = rotationLabel(week, anchor, labels)
+		}
+		for i := 1; i <= upcoming; i++ {
+			next := week.AddDate(0, 0, 7*i)
+			// The ISO week of a Sunday-start week is that of its Monday
+			_, nextISO := next.AddDate(0, 0, (int(time.Monday)-int(first)+7)%7).ISOWeek()
+			entry := weekParityWeek{WeekStart: next.Format("2006-01-02"), ISOWeek: nextISO}
+			if !anchor.IsZero() {
+				entry.Label, _ = rotationLabel(next, anchor, labels)
+			}
+			result.Upcoming = append(result.Upcoming, entry)
+		}
+
+		data, err := json.Marshal(result)
+		if err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to encode result: %v", err)), nil
+		}
+		return mcp.NewToolResultStructured(result, string(data)), nil
+	}
+}
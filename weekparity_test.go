@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestIsoWeeksInYear(t *testing.T) {
+	for year, want := range map[int]int{2020: 53, 2021: 52, 2025: 52, 2026: 53} {
+		if got := isoWeeksInYear(year); got != want {
+			t.Errorf("Expected %d to have %d ISO weeks but got %d", year, want, got)
+		}
+	}
+}
+
+func TestWeekParity(t *testing.T) {
+	clock := newFakeClock(time.Date(2025, 9, 10, 12, 0, 0, 0, time.UTC))
+	handler := handleWeekParity(&Config{DefaultTimezone: "UTC", Clock: clock})
+	call := func(args map[string]any) *mcp.CallToolResult {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := handler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("Failed to call handler: %v", err)
+		}
+		return result
+	}
+
+	// School year starting with an A week on Monday 2025-09-01
+	result := call(map[string]any{"anchor": "2025-09-03", "upcoming": 2})
+	if result.IsError {
+		t.Fatalf("Failed to compute week parity: %s", toolResultText(result))
+	}
+	got := result.StructuredContent.(weekParity)
+	if got.Date != "2025-09-10" || got.ISOWeek != 37 || got.ISOParity != "odd" || got.Anchor != "2025-09-01" ||
+		got.WeekStart != "2025-09-08" || got.Label != "B" || got.WeeksSinceAnchor != 1 {
+		t.Errorf("Expected a B week in odd ISO week 37 but got %+v", got)
+	}
+	if len(got.Upcoming) != 2 || got.Upcoming[0].Label != "A" || got.Upcoming[1].Label != "B" || got.Upcoming[1].WeekStart != "2025-09-22" {
+		t.Errorf("Expected upcoming A and B weeks but got %+v", got.Upcoming)
+	}
+
+	// Dates before the anchor continue the rotation backwards
+	got = call(map[string]any{"date": "2025-08-20", "anchor": "2025-09-01", "labels": "early, late, night", "upcoming": 0}).StructuredContent.(weekParity)
+	if got.Label != "late" || got.WeeksSinceAnchor != -2 || got.Upcoming != nil {
+		t.Errorf("Expected the late shift two weeks before the anchor but got %+v", got)
+	}
+
+	// ISO parity does not alternate across week 53
+	got = call(map[string]any{"date": "2026-12-31"}).StructuredContent.(weekParity)
+	if got.ISOWeek != 53 || got.ISOParity != "odd" || !got.ISOYearHas53Weeks || got.Label != "" {
+		t.Errorf("Expected odd ISO week 53 without a label but got %+v", got)
+	}
+
+	// Sunday-start weeks begin the day before
+	got = call(map[string]any{"date": "2025-09-07", "anchor": "2025-08-31", "week_start": "sunday"}).StructuredContent.(weekParity)
+	if got.WeekStart != "2025-09-07" || got.Label != "B" {
+		t.Errorf("Expected a B week starting on Sunday 2025-09-07 but got %+v", got)
+	}
+
+	for _, args := range []map[string]any{
+		{"date": "10/09/2025"},
+		{"anchor": "soon"},
+		{"labels": "A"},
+		{"upcoming": 100},
+		{"week_start": "friday"},
+	} {
+		if result := call(args); !result.IsError {
+			t.Errorf("Expected %v to be rejected", args)
+		}
+	}
+}
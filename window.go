@@ -0,0 +1,321 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// maxWindowSearchDays bounds the search for the next boundary; a window
+	// that does not change state within it reports none
+	maxWindowSearchDays = 400
+	// maxCronWindowRuns bounds how many overlapping cron runs are chained
+	// when looking for the end of a cron window
+	maxCronWindowRuns = 10000
+)
+
+// timeWindow is a recurring period of time
+type timeWindow interface {
+	// contains reports whether t falls inside the window
+	contains(t time.Time) bool
+	// boundaries returns instants after t, in order, at which the window
+	// may open or close; the search stops when the callback returns false
+	boundaries(t time.Time, yield func(time.Time) bool)
+	// location is the zone the window is defined in
+	location() *time.Location
+}
+
+// nextWindowBoundary returns the first instant after t at which the window
+// opens or closes, or the zero time if it does not change within the search
+func nextWindowBoundary(w timeWindow, t time.Time) time.Time {
+	inside := w.contains(t)
+	var next time.Time
+	w.boundaries(t, func(b time.Time) bool {
+		if b.After(t) && w.contains(b) != inside {
+			next = b
+			return false
+		}
+		return true
+	})
+	return next
+}
+
+// dailyWindow is a clock range repeated every day, e.g. 22:00-07:00; when the
+// end is not after the start the window wraps past midnight
+type dailyWindow struct {
+	start, end time.Duration
+	loc        *time.Location
+}
+
+func (w dailyWindow) location() *time.Location { return w.loc }
+
+func (w dailyWindow) contains(t time.Time) bool {
+	t = t.In(w.loc)
+	offset := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+	if w.start < w.end {
+		return offset >= w.start && offset < w.end
+	}
+	return offset >= w.start || offset < w.end
+}
+
+func (w dailyWindow) boundaries(t time.Time, yield func(time.Time) bool) {
+	t = t.In(w.loc)
+	for day := 0; day <= 2; day++ {
+		date := time.Date(t.Year(), t.Month(), t.Day()+day, 0, 0, 0, 0, w.loc)
+		first, second := clockOn(date, w.start), clockOn(date, w.end)
+		if second.Before(first) {
+			first, second = second, first
+		}
+		if !yield(first) || !yield(second) {
+			return
+		}
+	}
+}
+
+// businessHoursWindow is the working hours of a calendar's business days
+type businessHoursWindow struct {
+	cal *businessCalendar
+}
+
+func (w businessHoursWindow) location() *time.Location { return w.cal.Location }
+
+func (w businessHoursWindow) contains(t time.Time) bool {
+	return w.cal.isWorkingTime(t)
+}
+
+func (w businessHoursWindow) boundaries(t time.Time, yield func(time.Time) bool) {
+	t = t.In(w.cal.Location)
+	for day := 0; day <= maxWindowSearchDays; day++ {
+		date := time.Date(t.Year(), t.Month(), t.Day()+day, 0, 0, 0, 0, w.cal.Location)
+		if ok, _ := w.cal.isBusinessDay(date); !ok {
+			continue
+		}
+		if !yield(clockOn(date, w.cal.WorkStart)) || !yield(clockOn(date, w.cal.WorkEnd)) {
+			return
+		}
+	}
+}
+
+// cronWindow opens at each run of a cron schedule and stays open for a
+// duration, e.g. "0 9 * * 1-5 for 8h"
+type cronWindow struct {
+	schedule *cronSchedule
+	duration time.Duration
+	loc      *time.Location
+}
+
+func (w cronWindow) location() *time.Location { return w.loc }
+
+func (w cronWindow) contains(t time.Time) bool {
+	// The first run after t-duration is inside the window if it started by t
+	run := w.schedule.next(t.In(w.loc).Add(-w.duration))
+	return !run.IsZero() && !run.After(t)
+}
+
+func (w cronWindow) boundaries(t time.Time, yield func(time.Time) bool) {
+	run := w.schedule.next(t.In(w.loc).Add(-w.duration))
+	for i := 0; i < maxCronWindowRuns && !run.IsZero(); i++ {
+		if !yield(run) || !yield(run.Add(w.duration)) {
+			return
+		}
+		run = w.schedule.next(run)
+	}
+}
+
+// clockOn returns the wall clock offset on a date, normalizing times a DST
+// change skips the way time.Date does
+func clockOn(date time.Time, offset time.Duration) time.Time {
+	minutes := int(offset / time.Minute)
+	return time.Date(date.Year(), date.Month(), date.Day(), minutes/60, minutes%60, 0, 0, date.Location())
+}
+
+// dailyWindowPattern matches an optional label, a clock range and an
+// optional zone: "quiet hours 22:00–07:00 Europe/Warsaw"
+var dailyWindowPattern = regexp.MustCompile(`^(?:(.*?)\s+)?(\d{1,2}:\d{2})\s*[-–—]\s*(\d{1,2}:\d{2})(?:\s+(\S+))?$`)
+
+// cronWindowPattern matches "cron <expression> for <duration> [zone]"
+var cronWindowPattern = regexp.MustCompile(`^cron\s+(.+?)\s+for\s+(\S+)(?:\s+(\S+/\S+|UTC))?$`)
+
+// windowSpecError is a window argument that does not parse
+type windowSpecError struct {
+	message string
+}
+
+func (e *windowSpecError) Error() string { return e.message }
+
+// parseWindow parses the window argument of is_within_window. loc is used
+// for windows that do not name a zone; calendar selects the business hours.
+func parseWindow(spec string, loc *time.Location, calendar *businessCalendar) (timeWindow, error) {
+	spec = strings.TrimSpace(spec)
+	lower := strings.ToLower(spec)
+	switch {
+	case lower == "business hours" || lower == "business_hours" || lower == "business-hours":
+		if calendar == nil {
+			return nil, &windowSpecError{"business hours need a calendar; provide 'calendar' or configure TIME_CALENDARS_FILE"}
+		}
+		return businessHoursWindow{calendar}, nil
+	case strings.HasPrefix(lower, "cron "):
+		m := cronWindowPattern.FindStringSubmatch(spec)
+		if m == nil {
+			return nil, &windowSpecError{fmt.Sprintf("Invalid cron window: %s. Use 'cron <expression> for <duration> [timezone]', e.g. 'cron 0 9 * * 1-5 for 8h'.", spec)}
+		}
+		schedule, err := parseCron(m[1])
+		if err != nil {
+			return nil, &windowSpecError{cronErrorMessage(m[1], err)}
+		}
+		duration, err := time.ParseDuration(m[2])
+		if err != nil || duration < time.Minute {
+			return nil, &windowSpecError{fmt.Sprintf("Invalid window duration: %s. Please provide a duration of at least 1m, e.g. 8h.", m[2])}
+		}
+		if m[3] != "" {
+			if loc, err = loadLocationCached(m[3]); err != nil {
+				return nil, err
+			}
+		}
+		return cronWindow{schedule, duration, loc}, nil
+	}
+
+	m := dailyWindowPattern.FindStringSubmatch(spec)
+	if m == nil {
+		return nil, &windowSpecError{fmt.Sprintf("Invalid window: %s. Use 'business hours', 'HH:MM-HH:MM [timezone]' or 'cron <expression> for <duration> [timezone]'.", spec)}
+	}
+	start, err := parseClock(m[2])
+	if err != nil {
+		return nil, &windowSpecError{fmt.Sprintf("Invalid window start: %s", m[2])}
+	}
+	end, err := parseClock(m[3])
+	if err != nil {
+		return nil, &windowSpecError{fmt.Sprintf("Invalid window end: %s", m[3])}
+	}
+	if start == end {
+		return nil, &windowSpecError{fmt.Sprintf("Invalid window: %s. Start and end must differ.", spec)}
+	}
+	if m[4] != "" {
+		if loc, err = loadLocationCached(m[4]); err != nil {
+			return nil, err
+		}
+	}
+	return dailyWindow{start, end % (24 * time.Hour), loc}, nil
+}
+
+// windowCheck is the structured result of is_within_window
+type windowCheck struct {
+	Window       string `json:"window"`
+	Within       bool   `json:"within"`
+	Time         string `json:"time" jsonschema:"The checked time in RFC 3339, in the window's timezone"`
+	Timezone     string `json:"timezone"`
+	NextBoundary string `json:"next_boundary,omitempty" jsonschema:"When the window next opens or closes, in RFC 3339; omitted if not within about a year"`
+	NextChange   string `json:"next_change,omitempty" jsonschema:"opens or closes"`
+}
+
+// addWindowTool registers is_within_window
+func addWindowTool(mcpServer *server.MCPServer, config *Config) {
+	mcpServer.AddTool(
+		mcp.NewTool("is_within_window",
+			mcp.WithDescription("Check whether now, or a given time, falls inside a time window and when the window next opens or closes. Windows: 'business hours' of a business calendar; a daily clock range with an optional label and timezone, e.g. 'quiet hours 22:00-07:00 Europe/Warsaw' (ranges may cross midnight); or a cron window, e.g. 'cron 0 9 * * 1-5 for 8h'."),
+			mcp.WithString("window",
+				mcp.Description("The window: 'business hours', 'HH:MM-HH:MM [timezone]' or 'cron <expression> for <duration> [timezone]'."),
+				mcp.Required(),
+			),
+			mcp.WithString("time",
+				mcp.Description("Time to check in RFC 3339 or 'YYYY-MM-DD HH:MM' in the timezone. Defaults to now."),
+			),
+			mcp.WithString("timezone",
+				mcp.Description("Timezone for windows and times that do not name one. Defaults to the session or server default."),
+			),
+			mcp.WithString("calendar",
+				mcp.Description("Business calendar for 'business hours'. Defaults to the caller's tenant calendar, or the only configured calendar."),
+			),
+			mcp.WithOutputSchema[windowCheck](),
+			mcp.WithTitleAnnotation("Check Time Window"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		handleIsWithinWindow(config),
+	)
+}
+
+// windowCalendar picks the calendar for business hours: the argument, the
+// tenant's calendar, or the only configured one
+func windowCalendar(ctx context.Context, config *Config, name string) (*businessCalendar, error) {
+	if t := tenantFromContext(ctx); name == "" && t != nil {
+		name = t.Calendar
+	}
+	if name == "" {
+		calendars := config.datasets().Calendars
+		if len(calendars) != 1 {
+			return nil, nil
+		}
+		for _, cal := range calendars {
+			return cal, nil
+		}
+	}
+	return calendarByName(config, name)
+}
+
+// handleIsWithinWindow returns a handler for the is_within_window tool
+func handleIsWithinWindow(config *Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		spec, err := request.RequireString("window")
+		if err != nil {
+			return toolError(errorInvalidArgument, err.Error()), nil
+		}
+		timezoneStr := request.GetString("timezone", "")
+		loc, err := loadTimezone(ctx, timezoneStr, config)
+		if err != nil {
+			return invalidTimezoneError("Invalid timezone", timezoneStr), nil
+		}
+		cal, err := windowCalendar(ctx, config, request.GetString("calendar", ""))
+		if err != nil {
+			return toolError(errorNotFound, err.Error()), nil
+		}
+		window, err := parseWindow(spec, loc, cal)
+		if err != nil {
+			var specErr *windowSpecError
+			if errors.As(err, &specErr) {
+				return toolError(errorParse, specErr.Error()), nil
+			}
+			return toolError(errorInvalidTimezone, fmt.Sprintf("Invalid window timezone: %v", err)), nil
+		}
+
+		at := config.now()
+		if timeStr := request.GetString("time", ""); timeStr != "" {
+			if at, err = time.Parse(time.RFC3339, timeStr); err != nil {
+				if at, err = time.ParseInLocation("2006-01-02 15:04", timeStr, loc); err != nil {
+					return toolError(errorParse, fmt.Sprintf("Invalid time: %s. Please provide RFC 3339 or 'YYYY-MM-DD HH:MM'.", timeStr)), nil
+				}
+			}
+		}
+
+		wloc := window.location()
+		check := windowCheck{
+			Window:   spec,
+			Within:   window.contains(at),
+			Time:     at.In(wloc).Format(time.RFC3339),
+			Timezone: wloc.String(),
+		}
+		if next := nextWindowBoundary(window, at); !next.IsZero() {
+			check.NextBoundary = next.In(wloc).Format(time.RFC3339)
+			check.NextChange = "opens"
+			if check.Within {
+				check.NextChange = "closes"
+			}
+		}
+
+		data, err := json.Marshal(check)
+		if err != nil {
+			return toolError(errorInternal, fmt.Sprintf("Failed to encode result: %v", err)), nil
+		}
+		return mcp.NewToolResultStructured(check, string(data)), nil
+	}
+}
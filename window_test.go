@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestIsWithinWindow(t *testing.T) {
+	calendars, err := parseCalendars([]byte(testCalendars))
+	if err != nil {
+		t.Fatalf("Failed to parse calendars: %v", err)
+	}
+	// Tuesday 23 December 2025, 21:30 UTC is 22:30 in Warsaw
+	clock := newFakeClock(time.Date(2025, 12, 23, 21, 30, 0, 0, time.UTC))
+	config := &Config{DefaultTimezone: "UTC", Clock: clock, Calendars: calendars}
+	handler := handleIsWithinWindow(config)
+
+	tests := []struct {
+		args   map[string]any
+		within bool
+		next   string
+		change string
+	}{
+		{map[string]any{"window": "quiet hours 22:00–07:00 Europe/Warsaw"}, true, "2025-12-24T07:00:00+01:00", "closes"},
+		{map[string]any{"window": "22:00-07:00", "timezone": "Europe/Warsaw", "time": "2025-12-24 07:00"}, false, "2025-12-24T22:00:00+01:00", "opens"},
+		{map[string]any{"window": "09:00-17:00 UTC"}, false, "2025-12-24T09:00:00Z", "opens"},
+		// Christmas Eve and Christmas are holidays in acme
+		{map[string]any{"window": "business hours", "calendar": "acme"}, false, "2025-12-26T08:30:00+01:00", "opens"},
+		{map[string]any{"window": "business_hours", "calendar": "acme", "time": "2025-12-23T10:00:00+01:00"}, true, "2025-12-23T16:30:00+01:00", "closes"},
+		{map[string]any{"window": "cron 0 9 * * 1-5 for 8h", "time": "2025-12-27T12:00:00Z"}, false, "2025-12-29T09:00:00Z", "opens"},
+		{map[string]any{"window": "cron 0 9 * * 1-5 for 8h Europe/Warsaw", "time": "2025-12-23T12:00:00Z"}, true, "2025-12-23T17:00:00+01:00", "closes"},
+		// Hourly runs lasting 90m overlap, so the window never closes
+		{map[string]any{"window": "cron 0 * * * * for 90m", "time": "2025-12-23T12:00:00Z"}, true, "", ""},
+	}
+	for _, tt := range tests {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = tt.args
+		result, err := handler(context.Background(), request)
+		if err != nil || result.IsError {
+			t.Fatalf("Failed to check window %v: %v %s", tt.args, err, toolResultText(result))
+		}
+		var check windowCheck
+		if err := json.Unmarshal([]byte(toolResultText(result)), &check); err != nil {
+			t.Fatalf("Failed to decode result: %v", err)
+		}
+		if check.Within != tt.within || check.NextBoundary != tt.next || check.NextChange != tt.change {
+			t.Errorf("Expected %v to be (%v, %q, %q) but got (%v, %q, %q)", tt.args, tt.within, tt.next, tt.change, check.Within, check.NextBoundary, check.NextChange)
+		}
+	}
+}
+
+func TestIsWithinWindowErrors(t *testing.T) {
+	config := &Config{DefaultTimezone: "UTC"}
+	handler := handleIsWithinWindow(config)
+
+	tests := []struct {
+		args map[string]any
+		code toolErrorCode
+	}{
+		{map[string]any{}, errorInvalidArgument},
+		{map[string]any{"window": "lunch"}, errorParse},
+		{map[string]any{"window": "09:00-09:00"}, errorParse},
+		{map[string]any{"window": "25:00-07:00"}, errorParse},
+		{map[string]any{"window": "business hours"}, errorParse},
+		{map[string]any{"window": "business hours", "calendar": "acme"}, errorNotFound},
+		{map[string]any{"window": "cron 0 9 * * for 8h"}, errorParse},
+		{map[string]any{"window": "cron 0 9 * * * for 30s"}, errorParse},
+		{map[string]any{"window": "09:00-17:00 Mars/Olympus"}, errorInvalidTimezone},
+		{map[string]any{"window": "09:00-17:00", "timezone": "Warsw"}, errorInvalidTimezone},
+		{map[string]any{"window": "09:00-17:00", "time": "tomorrow"}, errorParse},
+	}
+	for _, tt := range tests {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = tt.args
+		result, err := handler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("Failed to call handler: %v", err)
+		}
+		if code := toolErrorCodeOf(result); code != tt.code {
+			t.Errorf("Expected %v to fail with %s but got %q: %s", tt.args, tt.code, code, toolResultText(result))
+		}
+	}
+}
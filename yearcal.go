@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// yearCalendarMonthsPerRow is how many months are printed side by side, as cal -y does
+const yearCalendarMonthsPerRow = 3
+
+// calendarWeekdays are the two-letter weekday headings, Sunday first, for
+// each describe_cron language; month names come from cronLocales
+var calendarWeekdays = map[string][7]string{
+	"en": {"Su", "Mo", "Tu", "We", "Th", "Fr", "Sa"},
+	"pl": {"Nd", "Pn", "Wt", "Śr", "Cz", "Pt", "So"},
+}
+
+// calendarFirstWeekdays are the customary first days of the week
+var calendarFirstWeekdays = map[string]time.Weekday{"en": time.Sunday, "pl": time.Monday}
+
+// padCenter centers s in width columns, counting runes so accented month
+// names line up
+func padCenter(s string, width int) string {
+	n := utf8.RuneCountInString(s)
+	if n >= width {
+		return s
+	}
+	left := (width - n) / 2
+	return strings.Repeat(" ", left) + s + strings.Repeat(" ", width-n-left)
+}
+
+// capitalize upper-cases the first letter of a month name
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
+// renderMonth returns the lines of one month: name, weekday headings and
+// six week rows, all of the same width so months can be placed side by side
+func renderMonth(year int, month time.Month, first time.Weekday, weekNumbers bool, lang string) []string {
+	width := 20
+	prefix := ""
+	if weekNumbers {
+		width += 3
+		prefix = "   "
+	}
+	lines := []string{padCenter(capitalize(cronLocales[lang].monthNames[month-1]), width)}
+
+	headings := make([]string, 7)
+	for i := range headings {
+		headings[i] = calendarWeekdays[lang][(int(first)+i)%7]
+	}
+	lines = append(lines, prefix+strings.Join(headings, " "))
+
+	start := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	// The first row starts on the first weekday on or before the 1st
+	day := start.AddDate(0, 0, -((int(start.Weekday()) - int(first) + 7) % 7))
+	for range 6 {
+		var row strings.Builder
+		if weekNumbers {
+			// ISO weeks run Monday to Sunday, so number the row by its Monday
+			monday := day.AddDate(0, 0, (int(time.Monday)-int(first)+7)%7)
+			if day.Month() == month || day.AddDate(0, 0, 6).Month() == month {
+				_, week := monday.ISOWeek()
+				fmt.Fprintf(&row, "%2d ", week)
+			} else {
+				row.WriteString("   ")
+			}
+		}
+		for i := range 7 {
+			if i > 0 {
+				row.WriteByte(' ')
+			}
+			if day.Month() == month {
+				fmt.Fprintf(&row, "%2d", day.Day())
+			} else {
+				row.WriteString("  ")
+			}
+			day = day.AddDate(0, 0, 1)
+		}
+		lines = append(lines, row.String())
+	}
+	return lines
+}
+
+// renderYearCalendar lays out the twelve months of year like cal -y
+func renderYearCalendar(year int, first time.Weekday, weekNumbers bool, lang string) string {
+	var b strings.Builder
+	var rows [][]string
+	for m := time.January; m <= time.December; m++ {
+		rows = append(rows, renderMonth(year, m, first, weekNumbers, lang))
+	}
+	width := utf8.RuneCountInString(rows[0][1])*yearCalendarMonthsPerRow + 2*(yearCalendarMonthsPerRow-1)
+	b.WriteString(strings.TrimRight(padCenter(fmt.Sprint(year), width), " ") + "\n\n")
+	for i := 0; i < len(rows); i += yearCalendarMonthsPerRow {
+		for line := range rows[i] {
+			var parts []string
+			for _, month := range rows[i : i+yearCalendarMonthsPerRow] {
+				parts = append(parts, month[line])
+			}
+			b.WriteString(strings.TrimRight(strings.Join(parts, "  "), " ") + "\n")
+		}
+		if i+yearCalendarMonthsPerRow < len(rows) {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+func addYearCalendarTool(mcpServer *server.MCPServer, config *Config) {
+	mcpServer.AddTool(
+		mcp.NewTool("year_calendar",
+			mcp.WithDescription("Render a whole-year calendar grid like Unix cal -y, as a markdown code block, with a configurable first weekday and optional ISO week numbers."),
+			mcp.WithNumber("year",
+				mcp.Description("Year to render. Defaults to the current year in the timezone."),
+			),
+			mcp.WithString("first_weekday",
+				mcp.Description("First day of the week: monday or sunday. Defaults to the language's custom (sunday for en, monday for pl)."),
+				mcp.Enum("monday", "sunday"),
+			),
+			mcp.WithBoolean("week_numbers",
+				mcp.Description("Show ISO week numbers in front of each week. Defaults to false."),
+			),
+			mcp.WithString("language",
+				mcp.Description("Language of month and weekday names: en or pl. Defaults to the session locale, else en."),
+			),
+			mcp.WithString("timezone",
+				mcp.Description("Timezone that decides the current year. Defaults to the session or server default."),
+			),
+			mcp.WithTitleAnnotation("Year Calendar"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		handleYearCalendar(config),
+	)
+}
+
+// handleYearCalendar returns a handler for the year_calendar tool
+func handleYearCalendar(config *Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// An omitted language follows the session locale when it is supported
+		language := strings.ToLower(request.GetString("language", ""))
+		if language == "" {
+			language = "en"
+			if lang := sessionLanguage(ctx); cronLocales[lang] != nil {
+				language = lang
+			}
+		}
+		if _, ok := calendarWeekdays[language]; !ok || cronLocales[language] == nil {
+			return toolError(errorInvalidArgument, fmt.Sprintf("Unsupported language: %s. Supported languages: %s", language, supportedCronLanguages())), nil
+		}
+
+		timezoneStr := request.GetString("timezone", "")
+		loc, err := loadTimezone(ctx, timezoneStr, config)
+		if err != nil {
+			return invalidTimezoneError("Invalid timezone", timezoneStr), nil
+		}
+		year := request.GetInt("year", config.now().In(loc).Year())
+		if year < 1 || year > 9999 {
+			return toolError(errorInvalidArgument, fmt.Sprintf("Invalid year: %d. Please provide a year between 1 and 9999.", year)), nil
+		}
+
+		first := calendarFirstWeekdays[language]
+		switch weekday := strings.ToLower(request.GetString("first_weekday", "")); weekday {
+		case "":
+		case "monday":
+			first = time.Monday
+		case "sunday":
+			first = time.Sunday
+		default:
+			return toolError(errorInvalidArgument, fmt.Sprintf("Invalid first_weekday: %s. Please use monday or sunday.", weekday)), nil
+		}
+
+		grid := renderYearCalendar(year, first, request.GetBool("week_numbers", false), language)
+		return mcp.NewToolResultText("```\n" + grid + "```"), nil
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestRenderMonth(t *testing.T) {
+	// February 2026 starts on a Sunday
+	lines := renderMonth(2026, time.February, time.Monday, true, "en")
+	want := []string{
+		"       February",
+		"   Mo Tu We Th Fr Sa Su",
+		" 5                    1",
+		" 6  2  3  4  5  6  7  8",
+		" 7  9 10 11 12 13 14 15",
+		" 8 16 17 18 19 20 21 22",
+		" 9 23 24 25 26 27 28",
+		"",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("Expected %d lines but got %d", len(want), len(lines))
+	}
+	for i := range want {
+		if strings.TrimRight(lines[i], " ") != want[i] {
+			t.Errorf("Expected line %d to be %q but got %q", i, want[i], lines[i])
+		}
+	}
+}
+
+func TestYearCalendar(t *testing.T) {
+	clock := newFakeClock(time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC))
+	handler := handleYearCalendar(&Config{DefaultTimezone: "UTC", Clock: clock})
+	call := func(args map[string]any) *mcp.CallToolResult {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := handler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("Failed to call handler: %v", err)
+		}
+		return result
+	}
+
+	text := toolResultText(call(map[string]any{}))
+	if !strings.HasPrefix(text, "```\n") || !strings.Contains(text, "2026") || !strings.Contains(text, "Su Mo Tu We Th Fr Sa") {
+		t.Errorf("Expected the current year starting on Sunday but got %s", text)
+	}
+	for _, month := range []string{"January", "June", "December"} {
+		if !strings.Contains(text, month) {
+			t.Errorf("Expected %s in the calendar", month)
+		}
+	}
+
+	// Polish starts weeks on Monday, and month names are capitalized
+	text = toolResultText(call(map[string]any{"year": 2025, "language": "pl"}))
+	if !strings.Contains(text, "Pn Wt Śr Cz Pt So Nd") || !strings.Contains(text, "Październik") {
+		t.Errorf("Expected a Polish calendar but got %s", text)
+	}
+	text = toolResultText(call(map[string]any{"year": 2025, "language": "pl", "first_weekday": "sunday"}))
+	if !strings.Contains(text, "Nd Pn Wt Śr Cz Pt So") {
+		t.Errorf("Expected weeks starting on Sunday but got %s", text)
+	}
+
+	for _, args := range []map[string]any{
+		{"language": "xx"},
+		{"year": 0},
+		{"first_weekday": "friday"},
+	} {
+		if code := toolErrorCodeOf(call(args)); code != errorInvalidArgument {
+			t.Errorf("Expected %v to fail with %s but got %q", args, errorInvalidArgument, code)
+		}
+	}
+}